@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jhwagner/kueue-bench/pkg/manifest"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the manifest and chart download cache",
+	Long: `Manage the cache of downloaded manifests and Helm charts (cache/ in the state directory).
+
+Kwok release manifests and extension manifest URLs are cached on download, keyed by URL
+and checksum. Manifests pinned by checksum are reused by later topology creation; unpinned
+ones are downloaded again and fall back to the cached copy only if the download fails.
+topology create --offline uses only cached manifests. Helm charts pinned to an exact version are cached the same way.`,
+}
+
+var cachePurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Remove all cached manifests and charts",
+	Args:  cobra.NoArgs,
+	RunE:  runCachePurge,
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cachePurgeCmd)
+}
+
+func runCachePurge(_ *cobra.Command, _ []string) error {
+	cache, err := manifest.OpenCache()
+	if err != nil {
+		return err
+	}
+	removed, err := cache.Purge()
+	if err != nil {
+		return fmt.Errorf("failed to purge cache: %w", err)
+	}
+	fmt.Printf("✓ Removed %d cached manifests from %s\n", removed, cache.Dir)
+	return nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jhwagner/kueue-bench/pkg/bench"
+	"github.com/jhwagner/kueue-bench/pkg/chaos"
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/topology"
+)
+
+var chaosCmd = &cobra.Command{
+	Use:   "chaos",
+	Short: "Inject disruptions into a topology",
+	Long:  `Inject disruptions into simulated clusters to measure how Kueue responds.`,
+}
+
+var chaosNodesCmd = &cobra.Command{
+	Use:   "nodes",
+	Short: "Churn simulated nodes",
+	Long: `Periodically delete a random fraction of KWOK nodes and recreate them,
+simulating capacity flapping. Runs until --duration elapses or interrupted.
+
+The same behavior can be enabled for a workload run via spec.chaos.nodeChurn
+in the WorkloadProfile.
+
+Examples:
+  kueue-bench chaos nodes --topology my-cluster --interval 2m --fraction 0.1
+  kueue-bench chaos nodes --topology my-cluster --selector pool=gpu --recreate-after 1m --duration 30m`,
+	RunE: runChaosNodes,
+}
+
+var chaosWorkerDownCmd = &cobra.Command{
+	Use:   "worker-down <topology> <worker>",
+	Short: "Take a MultiKueue worker offline for a while",
+	Long: `Pause the kind node containers of a MultiKueue worker so its API server stops
+responding, then resume them after --duration. Use it to measure how the
+management cluster detects the outage, fails workloads over to other workers
+and requeues them.
+
+The worker is restored early if the command is interrupted. External workers
+cannot be paused.
+
+Examples:
+  kueue-bench chaos worker-down my-multikueue worker-1 --duration 5m`,
+	Args: cobra.ExactArgs(2),
+	RunE: runChaosWorkerDown,
+}
+
+var (
+	chaosTopology      string
+	chaosCluster       string
+	chaosInterval      string
+	chaosFraction      float64
+	chaosRecreateAfter string
+	chaosSelector      map[string]string
+	chaosDuration      time.Duration
+	chaosSeed          int64
+
+	chaosWorkerDownDuration time.Duration
+)
+
+func init() {
+	rootCmd.AddCommand(chaosCmd)
+	chaosCmd.AddCommand(chaosNodesCmd)
+	chaosCmd.AddCommand(chaosWorkerDownCmd)
+
+	chaosNodesCmd.Flags().StringVar(&chaosTopology, "topology", "", "topology name (required)")
+	chaosNodesCmd.Flags().StringVar(&chaosCluster, "cluster", "", "cluster name within the topology (default: management cluster)")
+	chaosNodesCmd.Flags().StringVar(&chaosInterval, "interval", "1m", "time between churn rounds")
+	chaosNodesCmd.Flags().Float64Var(&chaosFraction, "fraction", 0.1, "fraction of selected nodes recycled each round")
+	chaosNodesCmd.Flags().StringVar(&chaosRecreateAfter, "recreate-after", "", "delay before deleted nodes are recreated")
+	chaosNodesCmd.Flags().StringToStringVar(&chaosSelector, "selector", nil, "node labels to restrict churn to (e.g. pool=gpu)")
+	chaosNodesCmd.Flags().DurationVar(&chaosDuration, "duration", 0, "stop after this long (default: run until interrupted)")
+	chaosNodesCmd.Flags().Int64Var(&chaosSeed, "seed", 0, "random seed for node selection (default: random)")
+
+	_ = chaosNodesCmd.MarkFlagRequired("topology")
+
+	chaosWorkerDownCmd.Flags().DurationVar(&chaosWorkerDownDuration, "duration", 5*time.Minute, "how long the worker stays down")
+}
+
+func runChaosNodes(cmd *cobra.Command, _ []string) error {
+	churn := &config.NodeChurn{
+		Interval:      chaosInterval,
+		Fraction:      chaosFraction,
+		RecreateAfter: chaosRecreateAfter,
+		NodeSelector:  chaosSelector,
+	}
+	if err := config.ValidateNodeChurn(churn); err != nil {
+		return fmt.Errorf("invalid churn settings: %w", err)
+	}
+
+	target, err := bench.ResolveCluster(chaosTopology, chaosCluster)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := chaos.NewClientset(target.KubeconfigPath, target.ClientOption())
+	if err != nil {
+		return err
+	}
+
+	seed := chaosSeed
+	if !cmd.Flags().Changed("seed") {
+		seed = time.Now().UnixNano()
+	}
+
+	churner, err := chaos.NewNodeChurner(clientset, chaosTopology, churn, seed)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if chaosDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, chaosDuration)
+		defer cancel()
+	}
+
+	fmt.Printf("Churning %.0f%% of nodes every %s in topology '%s' (seed: %d)\n",
+		chaosFraction*100, chaosInterval, chaosTopology, seed)
+	return churner.Run(ctx)
+}
+
+func runChaosWorkerDown(cmd *cobra.Command, args []string) error {
+	if chaosWorkerDownDuration <= 0 {
+		return fmt.Errorf("--duration must be positive")
+	}
+	topo, err := topology.Load(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load topology: %w", err)
+	}
+
+	// Ctrl-C cancels the context, which restores the worker instead of exiting with it paused
+	return topo.WorkerDown(cmd.Context(), args[1], chaosWorkerDownDuration)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/jhwagner/kueue-bench/pkg/run"
+)
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove old runs and their artifacts",
+	Long: `Remove saved runs, with their timelines and other artifacts, beyond the retention limits.
+Scenario batch reports are removed with the last of their runs; reports of batches
+without runs only by maxAge.
+
+Golden snapshots and the download cache are out of scope: they are reused by later
+topology creation rather than accumulated per run. Clear the cache with
+'kueue-bench cache purge', and delete unwanted snapshots/*.tar files from the state
+directory ('kueue-bench state show' lists them).
+
+Limits come from the flags or, when a flag is not set, from the retention section of the
+config file; the same section is applied automatically after every workload submit:
+
+  retention:
+    maxRuns: 50     # keep the 50 newest runs
+    maxAge: 168h    # remove runs started more than a week ago
+    maxDisk: 5Gi    # keep the newest runs that fit in 5Gi together
+
+Examples:
+  kueue-bench gc --max-runs 20
+  kueue-bench gc --max-age 72h --max-disk 2Gi --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runGC,
+}
+
+var gcDryRun bool
+
+func init() {
+	rootCmd.AddCommand(gcCmd)
+
+	gcCmd.Flags().Int("max-runs", 0, "keep at most this many of the newest runs")
+	gcCmd.Flags().Duration("max-age", 0, "remove runs started longer ago than this")
+	gcCmd.Flags().String("max-disk", "", "keep the newest runs that fit in this much disk space together (e.g. 5Gi)")
+	gcCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "list the runs that would be removed without removing them")
+
+	_ = viper.BindPFlag("retention.maxRuns", gcCmd.Flags().Lookup("max-runs"))
+	_ = viper.BindPFlag("retention.maxAge", gcCmd.Flags().Lookup("max-age"))
+	_ = viper.BindPFlag("retention.maxDisk", gcCmd.Flags().Lookup("max-disk"))
+}
+
+func runGC(_ *cobra.Command, _ []string) error {
+	policy, err := retentionPolicy()
+	if err != nil {
+		return err
+	}
+	if policy.IsZero() {
+		return fmt.Errorf("no retention limit set; pass --max-runs, --max-age or --max-disk, or set retention in the config file")
+	}
+
+	result, err := run.GC(policy, gcDryRun)
+	if err != nil {
+		return fmt.Errorf("failed to collect runs: %w", err)
+	}
+
+	verb := "Removed"
+	if gcDryRun {
+		verb = "Would remove"
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	for _, r := range result.Removed {
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\n", r.RunID, r.StartedAt.Format("2006-01-02 15:04:05"), formatBytes(r.Bytes))
+	}
+	_ = w.Flush()
+	fmt.Printf("✓ %s %d runs (%s), kept %d\n", verb, len(result.Removed), formatBytes(result.FreedBytes), result.Kept)
+	if len(result.RemovedBatches) > 0 {
+		fmt.Printf("✓ %s %d batch reports: %s\n", verb, len(result.RemovedBatches), strings.Join(result.RemovedBatches, ", "))
+	}
+	return nil
+}
+
+// retentionPolicy reads the retention limits from the gc flags and the config file
+func retentionPolicy() (run.RetentionPolicy, error) {
+	policy := run.RetentionPolicy{
+		MaxRuns: viper.GetInt("retention.maxRuns"),
+		MaxAge:  viper.GetDuration("retention.maxAge"),
+	}
+	if maxDisk := viper.GetString("retention.maxDisk"); maxDisk != "" {
+		q, err := resource.ParseQuantity(maxDisk)
+		if err != nil {
+			return policy, fmt.Errorf("invalid retention maxDisk %q: %w", maxDisk, err)
+		}
+		policy.MaxBytes = q.Value()
+	}
+	return policy, nil
+}
+
+// applyRetention removes the runs beyond the configured retention limits, if any are set
+func applyRetention() error {
+	policy, err := retentionPolicy()
+	if err != nil || policy.IsZero() {
+		return err
+	}
+	result, err := run.GC(policy, false)
+	if err != nil {
+		return fmt.Errorf("failed to collect runs: %w", err)
+	}
+	if len(result.Removed) > 0 {
+		fmt.Printf("✓ Removed %d old runs (%s) per the retention settings\n", len(result.Removed), formatBytes(result.FreedBytes))
+	}
+	return nil
+}
+
+// formatBytes formats a byte count with a binary unit, e.g. 1.5 GiB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
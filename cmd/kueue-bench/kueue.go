@@ -0,0 +1,261 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/jhwagner/kueue-bench/pkg/bench"
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/kueue"
+	"github.com/jhwagner/kueue-bench/pkg/topology"
+)
+
+var kueueCmd = &cobra.Command{
+	Use:   "kueue",
+	Short: "Manage Kueue in a topology",
+	Long:  `Inspect the Kueue objects provisioned in a topology's clusters and reinstall Kueue.`,
+}
+
+var kueueDiffCmd = &cobra.Command{
+	Use:   "diff <topology> [cluster]",
+	Short: "Diff live Kueue objects against the topology config",
+	Long: `Compare the Kueue objects in a cluster against those the topology provisioned.
+
+Reports objects that are missing, not in the config, or whose fields differ,
+e.g. after manual edits. Fields set in the cluster but not by the config,
+typically by API server or Kueue defaulting, are marked "not in config".
+Exits non-zero if any object differs.
+
+If the cluster is omitted, the management cluster or the only cluster is used.
+
+Examples:
+  kueue-bench kueue diff my-topology
+  kueue-bench kueue diff my-topology worker-1 --ignore-defaults`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runKueueDiff,
+}
+
+var kueueReinstallCmd = &cobra.Command{
+	Use:   "reinstall <topology> [cluster]",
+	Short: "Uninstall and reinstall Kueue in a cluster",
+	Long: `Uninstall Kueue from a cluster and install it again, optionally at another version,
+to test upgrade or downgrade behavior on an existing topology.
+
+The install reuses the topology's Kueue settings (Helm values, manager config)
+and the cluster's feature gates. Kueue's CRDs are kept across the reinstall, so
+existing Kueue objects are served by the new version. With --recreate-objects,
+the objects the topology provisioned are deleted first and created again
+after the install.
+
+If the cluster is omitted, the management cluster or the only cluster is used.
+
+Examples:
+  kueue-bench kueue reinstall my-topology --version 0.16.0
+  kueue-bench kueue reinstall my-topology worker-1 --version 0.17.0 --recreate-objects`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runKueueReinstall,
+}
+
+var kueueExportCmd = &cobra.Command{
+	Use:   "export <topology> [cluster]",
+	Short: "Export a cluster's Kueue objects as a kubectl List",
+	Long: `Print the Kueue objects in a cluster as a v1 List, as kubectl get -o yaml does.
+Every item carries its apiVersion and kind, and status and server-set metadata
+are dropped, so the output can be read by kubectl, the kubectl-kueue plugin and
+other Kueue tooling, or applied to another cluster. The namespaces of the
+LocalQueues are listed first.
+
+With --from-config, the objects the topology config provisions are exported
+instead, without contacting the cluster, including declared namespaces and their
+ResourceQuotas and LimitRanges. MultiKueue objects, which are set up
+from WorkerSets, are then not included.
+
+If the cluster is omitted, the management cluster or the only cluster is used.
+
+Examples:
+  kueue-bench kueue export my-topology > kueue-objects.yaml
+  kueue-bench kueue export my-topology worker-1 -o json
+  kueue-bench kueue export my-topology --from-config | kubectl apply --dry-run=client -f -`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runKueueExport,
+}
+
+var (
+	kueueDiffIgnoreDefaults bool
+
+	kueueExportOutput     string
+	kueueExportFromConfig bool
+
+	kueueReinstallVersion         string
+	kueueReinstallRecreateObjects bool
+)
+
+func init() {
+	rootCmd.AddCommand(kueueCmd)
+	kueueCmd.AddCommand(kueueDiffCmd)
+	kueueCmd.AddCommand(kueueReinstallCmd)
+	kueueCmd.AddCommand(kueueExportCmd)
+
+	kueueDiffCmd.Flags().BoolVar(&kueueDiffIgnoreDefaults, "ignore-defaults", false, "ignore fields set in the cluster but not by the config")
+
+	kueueExportCmd.Flags().StringVarP(&kueueExportOutput, "output", "o", kueue.FormatYAML, "output format: yaml or json")
+	kueueExportCmd.Flags().BoolVar(&kueueExportFromConfig, "from-config", false, "export the objects the topology config provisions instead of the live objects")
+
+	kueueReinstallCmd.Flags().StringVar(&kueueReinstallVersion, "version", "", "Kueue version to install (default: the topology's version)")
+	kueueReinstallCmd.Flags().BoolVar(&kueueReinstallRecreateObjects, "recreate-objects", false, "delete the topology's Kueue objects before uninstalling and create them again after installing")
+}
+
+func runKueueDiff(cmd *cobra.Command, args []string) error {
+	topologyName := args[0]
+	var clusterName string
+	if len(args) > 1 {
+		clusterName = args[1]
+	}
+
+	target, err := bench.ResolveCluster(topologyName, clusterName)
+	if err != nil {
+		return err
+	}
+	if target.KueueConfigPath == "" {
+		return fmt.Errorf("cluster %q has no recorded Kueue config; recreate the topology to record it", target.Name)
+	}
+	kueueConfig, err := config.LoadKueueConfig(target.KueueConfigPath)
+	if err != nil {
+		return err
+	}
+
+	client, err := kueue.NewClient(target.KubeconfigPath, target.ClientOption())
+	if err != nil {
+		return fmt.Errorf("failed to create Kueue client: %w", err)
+	}
+	diffs, err := kueue.DiffKueueObjects(cmd.Context(), client, kueueConfig)
+	if err != nil {
+		return fmt.Errorf("failed to diff Kueue objects: %w", err)
+	}
+	if kueueDiffIgnoreDefaults {
+		diffs = withoutDefaults(diffs)
+	}
+
+	if len(diffs) == 0 {
+		fmt.Printf("✓ Kueue objects in cluster '%s' match the topology config\n", target.Name)
+		return nil
+	}
+
+	counts := map[kueue.DiffState]int{}
+	for _, d := range diffs {
+		counts[d.State]++
+		fmt.Printf("%s %s: %s\n", d.Kind, d.Name, d.State)
+		for _, f := range d.Fields {
+			fmt.Printf("  %s: %s → %s\n", f.Path, fieldValue(f.Want, "(not in config)"), fieldValue(f.Got, "(unset)"))
+		}
+	}
+	return fmt.Errorf("%d Kueue object(s) differ from the topology config (%d changed, %d missing, %d unexpected)",
+		len(diffs), counts[kueue.DiffChanged], counts[kueue.DiffMissing], counts[kueue.DiffUnexpected])
+}
+
+func runKueueExport(cmd *cobra.Command, args []string) error {
+	topologyName := args[0]
+	var clusterName string
+	if len(args) > 1 {
+		clusterName = args[1]
+	}
+	if kueueExportOutput != kueue.FormatYAML && kueueExportOutput != kueue.FormatJSON {
+		return fmt.Errorf("invalid --output %q: must be %s or %s", kueueExportOutput, kueue.FormatYAML, kueue.FormatJSON)
+	}
+
+	target, err := bench.ResolveCluster(topologyName, clusterName)
+	if err != nil {
+		return err
+	}
+
+	var list *corev1.List
+	if kueueExportFromConfig {
+		if target.KueueConfigPath == "" {
+			return fmt.Errorf("cluster %q has no recorded Kueue config; recreate the topology to record it", target.Name)
+		}
+		kueueConfig, err := config.LoadKueueConfig(target.KueueConfigPath)
+		if err != nil {
+			return err
+		}
+		if list, err = kueue.ConfigObjects(kueueConfig); err != nil {
+			return fmt.Errorf("failed to build Kueue objects: %w", err)
+		}
+	} else {
+		client, err := kueue.NewClient(target.KubeconfigPath, target.ClientOption())
+		if err != nil {
+			return fmt.Errorf("failed to create Kueue client: %w", err)
+		}
+		if list, err = client.ExportObjects(cmd.Context()); err != nil {
+			return fmt.Errorf("failed to export Kueue objects: %w", err)
+		}
+	}
+
+	data, err := kueue.EncodeList(list, kueueExportOutput)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+func runKueueReinstall(cmd *cobra.Command, args []string) error {
+	topologyName := args[0]
+	var clusterName string
+	if len(args) > 1 {
+		clusterName = args[1]
+	}
+
+	target, err := bench.ResolveCluster(topologyName, clusterName)
+	if err != nil {
+		return err
+	}
+	topo, err := topology.Load(topologyName)
+	if err != nil {
+		return fmt.Errorf("failed to load topology: %w", err)
+	}
+
+	fmt.Printf("Reinstalling Kueue in cluster '%s'...\n", target.Name)
+	if err := topo.ReinstallKueue(cmd.Context(), target.Name, topology.ReinstallOptions{
+		Version:         strings.TrimPrefix(kueueReinstallVersion, "v"),
+		RecreateObjects: kueueReinstallRecreateObjects,
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Kueue reinstalled in cluster '%s'\n", target.Name)
+	return nil
+}
+
+// withoutDefaults drops fields the config does not set, and changed objects left without fields
+func withoutDefaults(diffs []kueue.ObjectDiff) []kueue.ObjectDiff {
+	var kept []kueue.ObjectDiff
+	for _, d := range diffs {
+		if d.State != kueue.DiffChanged {
+			kept = append(kept, d)
+			continue
+		}
+		var fields []kueue.FieldDiff
+		for _, f := range d.Fields {
+			if !f.NotInConfig() {
+				fields = append(fields, f)
+			}
+		}
+		if len(fields) > 0 {
+			d.Fields = fields
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
+
+// fieldValue returns v, or placeholder if the field is unset
+func fieldValue(v, placeholder string) string {
+	if v == "" {
+		return placeholder
+	}
+	return v
+}
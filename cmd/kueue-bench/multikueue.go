@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jhwagner/kueue-bench/pkg/topology"
+)
+
+var multikueueCmd = &cobra.Command{
+	Use:   "multikueue",
+	Short: "Manage MultiKueue in a topology",
+	Long:  `Manage the MultiKueue connections between a topology's management cluster and its workers.`,
+}
+
+var multikueueRotateCredentialsCmd = &cobra.Command{
+	Use:   "rotate-credentials <topology>",
+	Short: "Regenerate worker kubeconfigs and update their Secrets",
+	Long: `Regenerate the kubeconfig of every MultiKueue worker, update its Secret on the
+management cluster and wait for the MultiKueueClusters to become active again.
+
+Workers with serviceAccount credentials get a new token and the old one is
+revoked first, so Kueue briefly sees expired credentials before the new ones
+are in place. Admin kubeconfigs are extracted again and rewritten unchanged.
+
+Examples:
+  kueue-bench multikueue rotate-credentials my-topology`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMultikueueRotateCredentials,
+}
+
+var multikueueAuditCmd = &cobra.Command{
+	Use:   "audit <topology>",
+	Short: "Check that management and worker Workloads are consistent",
+	Long: `Compare the Workloads on each management cluster with the copies MultiKueue
+created on its workers, e.g. after a benchmark run. Reports:
+
+  missing      an admitted Workload has no copy on the worker it was dispatched to
+  duplicate    an admitted Workload has copies on more than one worker
+  job missing  a dispatched Workload's Job was not created on the worker
+  orphaned     a worker copy's management Workload was deleted or recreated
+
+Finished Workloads are not checked. Exits non-zero if any inconsistency is found.
+
+Examples:
+  kueue-bench multikueue audit my-topology`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMultikueueAudit,
+}
+
+func init() {
+	rootCmd.AddCommand(multikueueCmd)
+	multikueueCmd.AddCommand(multikueueRotateCredentialsCmd)
+	multikueueCmd.AddCommand(multikueueAuditCmd)
+}
+
+func runMultikueueRotateCredentials(cmd *cobra.Command, args []string) error {
+	topo, err := topology.Load(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load topology: %w", err)
+	}
+
+	if err := topo.RotateMultiKueueCredentials(cmd.Context()); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ MultiKueue credentials rotated in topology '%s'\n", args[0])
+	return nil
+}
+
+func runMultikueueAudit(cmd *cobra.Command, args []string) error {
+	topo, err := topology.Load(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load topology: %w", err)
+	}
+
+	found, err := topo.AuditMultiKueue(cmd.Context())
+	if err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		fmt.Printf("✓ MultiKueue Workloads in topology '%s' are consistent\n", args[0])
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "WORKLOAD\tINCONSISTENCY\tWORKERS\tREASON")
+	for _, f := range found {
+		workers := strings.Join(f.Workers, ",")
+		if workers == "" {
+			workers = "-"
+		}
+		fmt.Fprintf(w, "%s/%s\t%s\t%s\t%s\n", f.Namespace, f.Name, f.Kind, workers, f.Reason)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%d MultiKueue Workload inconsistencies found", len(found))
+}
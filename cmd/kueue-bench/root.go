@@ -3,15 +3,24 @@ package main
 import (
 	"fmt"
 	"os"
+	"runtime"
+	"runtime/debug"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+	"github.com/jhwagner/kueue-bench/pkg/state"
 )
 
 var (
 	cfgFile    string
 	verbose    bool
 	kubeconfig string
+	stateDir   string
+	maxProcs   int
+	memLimit   string
 )
 
 var rootCmd = &cobra.Command{
@@ -27,15 +36,22 @@ and rigorous performance benchmarking.`,
 
 func init() {
 	cobra.OnInitialize(initConfig)
+	restconfig.SetVersion(version)
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.kueue-bench.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "", "path to kubeconfig file (default is $HOME/.kube/config)")
+	rootCmd.PersistentFlags().StringVar(&stateDir, "state-dir", "", "state directory for topologies, runs and caches (default is $"+state.DirEnv+", ~/.kueue-bench if it exists, else $XDG_DATA_HOME/kueue-bench)")
+	rootCmd.PersistentFlags().IntVar(&maxProcs, "max-procs", 0, "CPUs kueue-bench itself may use at once, as GOMAXPROCS (default: all, or $GOMAXPROCS)")
+	rootCmd.PersistentFlags().StringVar(&memLimit, "memory-limit", "", "soft memory limit of kueue-bench itself, as GOMEMLIMIT (e.g. 512Mi; default: none, or $GOMEMLIMIT)")
 
 	// Bind flags to viper
 	_ = viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	_ = viper.BindPFlag("kubeconfig", rootCmd.PersistentFlags().Lookup("kubeconfig"))
+	_ = viper.BindPFlag("state-dir", rootCmd.PersistentFlags().Lookup("state-dir"))
+	_ = viper.BindPFlag("max-procs", rootCmd.PersistentFlags().Lookup("max-procs"))
+	_ = viper.BindPFlag("memory-limit", rootCmd.PersistentFlags().Lookup("memory-limit"))
 }
 
 func initConfig() {
@@ -58,4 +74,41 @@ func initConfig() {
 	if err := viper.ReadInConfig(); err == nil && verbose {
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
+
+	// The flag, or state-dir in the config file, overrides $KUEUE_BENCH_STATE_DIR, which
+	// every command opens its state from
+	if dir := viper.GetString("state-dir"); dir != "" {
+		if err := os.Setenv(state.DirEnv, dir); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	if err := applyResourceLimits(viper.GetInt("max-procs"), viper.GetString("memory-limit")); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// applyResourceLimits caps the CPUs and memory kueue-bench itself uses, so it competes
+// less with the clusters it measures on a shared host. Zero values keep the runtime's
+// defaults, which honour $GOMAXPROCS and $GOMEMLIMIT.
+func applyResourceLimits(procs int, memoryLimit string) error {
+	if procs < 0 {
+		return fmt.Errorf("invalid max-procs %d: must not be negative", procs)
+	}
+	if procs > 0 {
+		runtime.GOMAXPROCS(procs)
+	}
+	if memoryLimit != "" {
+		q, err := resource.ParseQuantity(memoryLimit)
+		if err != nil {
+			return fmt.Errorf("invalid memory-limit %q: %w", memoryLimit, err)
+		}
+		if q.Sign() <= 0 {
+			return fmt.Errorf("invalid memory-limit %q: must be positive", memoryLimit)
+		}
+		debug.SetMemoryLimit(q.Value())
+	}
+	return nil
 }
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/jhwagner/kueue-bench/pkg/bench"
+	"github.com/jhwagner/kueue-bench/pkg/config"
 	"github.com/jhwagner/kueue-bench/pkg/run"
+	"github.com/jhwagner/kueue-bench/pkg/whatif"
 )
 
 var runCmd = &cobra.Command{
@@ -23,9 +29,76 @@ var runListCmd = &cobra.Command{
 	RunE:  runRunList,
 }
 
+var runWhatIfCmd = &cobra.Command{
+	Use:   "what-if <run-id>",
+	Short: "Estimate how modified quotas would change a run's admissions",
+	Long: `Replay a recorded run's workloads against a modified Kueue configuration (the kueue
+section of a topology cluster: cohorts, clusterQueues, localQueues) without re-running them.
+
+Both the quotas the run used and the modified ones are replayed with a simplified admission
+model: workloads are admitted in priority then submission order as quota frees up, hold it
+for their recorded runtime, and borrow within their cohort up to borrowingLimit. Lending
+limits, hierarchical cohorts, preemption, fair sharing and admission checks are not
+modeled, so treat the result as a first pass before a full run.
+
+The baseline is the Kueue config recorded for the run's cluster, or --baseline when the
+topology no longer exists.
+
+Examples:
+  kueue-bench run what-if a1b2c3d4 --kueue-config more-gpu-quota.yaml
+  kueue-bench run what-if a1b2c3d4 --kueue-config shared-cohort.yaml --threshold 10s --top 50`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRunWhatIf,
+}
+
+var runCompareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compare the latency of repeated runs of two configurations",
+	Long: `Compare admission and end-to-end latency percentiles between repeated runs of a baseline
+and a candidate configuration. A single run of a noisy system routinely misleads, so
+each side should be several repetitions, e.g. queued with scenario queue add --repeat.
+
+Each percentile is taken once per run; the report shows its mean and standard deviation
+on each side and tests the difference with Welch's t-test. Runs may be given by run ID or
+by batch ID, which stands for every successful run of the batch.
+
+Examples:
+  kueue-bench run compare --baseline a1b2c3d4,e5f6a7b8,c9d0e1f2 --candidate 1a2b3c4d,5e6f7a8b,9c0d1e2f
+  kueue-bench run compare --baseline 0a1b2c3d --candidate 4e5f6a7b --alpha 0.01`,
+	Args: cobra.NoArgs,
+	RunE: runRunCompare,
+}
+
+var (
+	runCompareBaseline  []string
+	runCompareCandidate []string
+	runCompareAlpha     float64
+)
+
+var (
+	runWhatIfConfig    string
+	runWhatIfBaseline  string
+	runWhatIfThreshold time.Duration
+	runWhatIfTop       int
+)
+
 func init() {
 	rootCmd.AddCommand(runCmd)
 	runCmd.AddCommand(runListCmd)
+	runCmd.AddCommand(runWhatIfCmd)
+	runCmd.AddCommand(runCompareCmd)
+
+	runWhatIfCmd.Flags().StringVar(&runWhatIfConfig, "kueue-config", "", "modified Kueue config to replay the run against (required)")
+	runWhatIfCmd.Flags().StringVar(&runWhatIfBaseline, "baseline", "", "Kueue config the run used (default: the config recorded for its cluster)")
+	runWhatIfCmd.Flags().DurationVar(&runWhatIfThreshold, "threshold", time.Second, "smallest change in admission wait to report")
+	runWhatIfCmd.Flags().IntVar(&runWhatIfTop, "top", 20, "number of changed workloads to list (0 for all)")
+	_ = runWhatIfCmd.MarkFlagRequired("kueue-config")
+
+	runCompareCmd.Flags().StringSliceVar(&runCompareBaseline, "baseline", nil, "run or batch IDs of the baseline configuration (required)")
+	runCompareCmd.Flags().StringSliceVar(&runCompareCandidate, "candidate", nil, "run or batch IDs of the candidate configuration (required)")
+	runCompareCmd.Flags().Float64Var(&runCompareAlpha, "alpha", run.DefaultAlpha, "significance level of the test")
+	_ = runCompareCmd.MarkFlagRequired("baseline")
+	_ = runCompareCmd.MarkFlagRequired("candidate")
 }
 
 func runRunList(_ *cobra.Command, _ []string) error {
@@ -61,3 +134,180 @@ func runRunList(_ *cobra.Command, _ []string) error {
 
 	return nil
 }
+
+func runRunWhatIf(_ *cobra.Command, args []string) error {
+	runID := args[0]
+	meta, err := run.Load(runID)
+	if err != nil {
+		return fmt.Errorf("failed to load run %q: %w", runID, err)
+	}
+	timelines, err := run.LoadTimelines(runID)
+	if err != nil {
+		return fmt.Errorf("failed to load timelines for run %q: %w", runID, err)
+	}
+	trace, err := whatif.FromTimelines(timelines)
+	if err != nil {
+		return err
+	}
+
+	baselinePath := runWhatIfBaseline
+	if baselinePath == "" {
+		target, err := bench.ResolveCluster(meta.TopologyName, meta.ClusterName)
+		if err != nil {
+			return fmt.Errorf("%w; pass the Kueue config the run used with --baseline", err)
+		}
+		if target.KueueConfigPath == "" {
+			return fmt.Errorf("cluster %q has no recorded Kueue config; pass it with --baseline", target.Name)
+		}
+		baselinePath = target.KueueConfigPath
+	}
+	baseline, err := loadWhatIfModel(baselinePath)
+	if err != nil {
+		return err
+	}
+	modified, err := loadWhatIfModel(runWhatIfConfig)
+	if err != nil {
+		return err
+	}
+
+	report := whatif.Compare(trace, baseline, modified, runWhatIfThreshold)
+	fmt.Printf("Replayed %d workloads of run %s: %d admitted earlier, %d later, %d newly admitted, %d no longer admitted\n",
+		report.Workloads, runID, report.Earlier, report.Later, report.Admitted, report.NotAdmitted)
+
+	fmt.Println("\nAdmission wait (p50 / p95), baseline → what-if:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "CLUSTERQUEUE\tADMITTED\tBASELINE\tWHAT-IF")
+	_, _ = fmt.Fprintln(w, "------------\t--------\t--------\t-------")
+	for _, q := range report.Queues {
+		_, _ = fmt.Fprintf(w, "%s\t%d → %d\t%s\t%s\n", q.ClusterQueue, q.Baseline.Count, q.WhatIf.Count,
+			formatP50P95(q.Baseline), formatP50P95(q.WhatIf))
+	}
+	_ = w.Flush()
+
+	changes := report.Changes
+	if len(changes) == 0 {
+		return nil
+	}
+	if runWhatIfTop > 0 && len(changes) > runWhatIfTop {
+		changes = changes[:runWhatIfTop]
+	}
+	fmt.Printf("\nLargest changes (%d of %d):\n", len(changes), len(report.Changes))
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "WORKLOAD\tCLUSTERQUEUE\tBASELINE\tWHAT-IF\tCHANGE")
+	_, _ = fmt.Fprintln(w, "--------\t------------\t--------\t-------\t------")
+	for _, c := range changes {
+		change := "newly admitted"
+		if delta, ok := c.Delta(); ok {
+			change = delta.Round(time.Millisecond).String()
+			if delta > 0 {
+				change = "+" + change
+			}
+		} else if c.WhatIf == nil {
+			change = "no longer admitted"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Name, c.ClusterQueue, formatWait(c.Baseline), formatWait(c.WhatIf), change)
+	}
+	return w.Flush()
+}
+
+// loadWhatIfModel loads a Kueue config and builds its replay model
+func loadWhatIfModel(path string) (*whatif.Model, error) {
+	cfg, err := config.LoadKueueConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	model, err := whatif.NewModel(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Kueue config %s: %w", path, err)
+	}
+	return model, nil
+}
+
+// formatWait formats an admission wait, or "-" if the workload was not admitted
+func formatWait(wait *time.Duration) string {
+	if wait == nil {
+		return "-"
+	}
+	return wait.Round(time.Millisecond).String()
+}
+
+func runRunCompare(_ *cobra.Command, _ []string) error {
+	if runCompareAlpha <= 0 || runCompareAlpha >= 1 {
+		return fmt.Errorf("--alpha must be between 0 and 1")
+	}
+	baseline, err := loadRuns(runCompareBaseline)
+	if err != nil {
+		return err
+	}
+	candidate, err := loadRuns(runCompareCandidate)
+	if err != nil {
+		return err
+	}
+	comparison, err := run.CompareRuns(baseline, candidate, runCompareAlpha)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Baseline: %d runs, candidate: %d runs, significance level %g\n",
+		len(comparison.Baseline), len(comparison.Candidate), comparison.Alpha)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "METRIC\tBASELINE\tCANDIDATE\tCHANGE\tP-VALUE\tSIGNIFICANT")
+	_, _ = fmt.Fprintln(w, "------\t--------\t---------\t------\t-------\t-----------")
+	for _, m := range comparison.Metrics {
+		pValue, significant := "-", "-"
+		if !math.IsNaN(m.PValue) {
+			pValue = fmt.Sprintf("%.4f", m.PValue)
+			significant = "no"
+			if m.Significant {
+				significant = "yes"
+			}
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%+.1f%%\t%s\t%s\n", m.Metric,
+			formatMeanStddev(m.Baseline), formatMeanStddev(m.Candidate), m.Change*100, pValue, significant)
+	}
+	_ = w.Flush()
+
+	if len(comparison.Baseline) < 2 || len(comparison.Candidate) < 2 {
+		fmt.Println("\nNote: the significance test needs at least 2 runs on each side; 5 or more give a useful one")
+	}
+	return nil
+}
+
+// loadRuns loads runs by ID, expanding batch IDs to the successful runs of the batch
+func loadRuns(ids []string) ([]*run.RunMetadata, error) {
+	var runs []*run.RunMetadata
+	for _, id := range ids {
+		meta, err := run.Load(id)
+		if err == nil {
+			runs = append(runs, meta)
+			continue
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to load run %q: %w", id, err)
+		}
+
+		batch, batchErr := run.LoadBatch(id)
+		if batchErr != nil {
+			if errors.Is(batchErr, os.ErrNotExist) {
+				return nil, fmt.Errorf("no run or batch %q", id)
+			}
+			return nil, fmt.Errorf("failed to load batch %q: %w", id, batchErr)
+		}
+		for _, r := range batch.Results {
+			if r.RunID == "" || r.Error != "" {
+				continue
+			}
+			meta, err := run.Load(r.RunID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load run %q of batch %q: %w", r.RunID, id, err)
+			}
+			runs = append(runs, meta)
+		}
+	}
+	return runs, nil
+}
+
+// formatMeanStddev formats a percentile's mean and standard deviation over runs
+func formatMeanStddev(stats run.SampleStats) string {
+	return fmt.Sprintf("%s ± %s (n=%d)", stats.Mean.Round(time.Millisecond), stats.Stddev.Round(time.Millisecond), stats.N)
+}
@@ -0,0 +1,456 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jhwagner/kueue-bench/pkg/bench"
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/kueue"
+	"github.com/jhwagner/kueue-bench/pkg/presets"
+	"github.com/jhwagner/kueue-bench/pkg/run"
+	"github.com/jhwagner/kueue-bench/pkg/topology"
+)
+
+var scenarioCmd = &cobra.Command{
+	Use:   "scenario",
+	Short: "Queue and run batches of scenarios",
+	Long:  `Queue workload scenarios against a topology and run them as one batch, or run canned presets.`,
+}
+
+var scenarioQueueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Manage the scenario queue of a topology",
+	Long: `Queue workload profiles against a topology and run them sequentially, e.g. as an
+overnight benchmark batch. Between scenarios the workloads kueue-bench submitted are
+deleted and the ClusterQueues are given time to drain, so every scenario starts from an
+idle cluster. A consolidated report compares the scenarios once the batch finishes.
+
+Examples:
+  kueue-bench scenario queue add --topology my-cluster --profile baseline.yaml
+  kueue-bench scenario queue add --topology my-cluster --profile burst.yaml --timeline-wait 2m
+  kueue-bench scenario queue start --topology my-cluster
+  kueue-bench scenario queue report`,
+}
+
+var scenarioQueueAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a scenario to a topology's queue",
+	Args:  cobra.NoArgs,
+	RunE:  runScenarioQueueAdd,
+}
+
+var scenarioQueueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the scenarios queued against a topology",
+	Args:  cobra.NoArgs,
+	RunE:  runScenarioQueueList,
+}
+
+var scenarioQueueClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove every scenario queued against a topology",
+	Args:  cobra.NoArgs,
+	RunE:  runScenarioQueueClear,
+}
+
+var scenarioQueueStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Run the queued scenarios one after another",
+	Long: `Run the scenarios queued against a topology one after another, resetting the cluster
+between them. A failing scenario is recorded and the batch continues; a failing reset
+stops it. Scenarios leave the queue as they finish, so an interrupted batch resumes with
+the interrupted scenario on the next start.`,
+	Args: cobra.NoArgs,
+	RunE: runScenarioQueueStart,
+}
+
+var scenarioQueueReportCmd = &cobra.Command{
+	Use:   "report [batch-id]",
+	Short: "Show the report of a batch (default: the latest)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runScenarioQueueReport,
+}
+
+var scenarioPresetCmd = &cobra.Command{
+	Use:   "preset",
+	Short: "Run canned scenarios reproducing classic Kueue scheduling situations",
+	Long: `Presets pair a topology template with the workload profile that drives it:
+
+  preemption-storm         high-priority bursts preempting low-priority batch jobs
+  borrow-reclaim           a lender reclaiming quota borrowed within its cohort
+  fair-share-convergence   tenants' shares of a cohort pool converging on their weights
+
+Run a preset in one command, then export it to tweak the topology or profile and run
+the copies with topology create and workload submit.
+
+Examples:
+  kueue-bench scenario preset list
+  kueue-bench scenario preset run preemption-storm
+  kueue-bench scenario preset export borrow-reclaim --dir ./scenarios`,
+}
+
+var scenarioPresetListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the available presets",
+	Args:  cobra.NoArgs,
+	RunE:  runScenarioPresetList,
+}
+
+var scenarioPresetShowCmd = &cobra.Command{
+	Use:   "show <preset>",
+	Short: "Describe a preset",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runScenarioPresetShow,
+}
+
+var scenarioPresetExportCmd = &cobra.Command{
+	Use:   "export <preset>",
+	Short: "Write a preset's topology and profile to files to tweak",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runScenarioPresetExport,
+}
+
+var scenarioPresetRunCmd = &cobra.Command{
+	Use:   "run <preset>",
+	Short: "Create a preset's topology and submit its profile",
+	Long: `Create a preset's topology and submit its workload profile to it. A topology left by
+an earlier run of the preset is reused; one of the same name created otherwise is refused,
+so pick another name with --name. The topology is kept for inspection afterwards unless
+--delete is set.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScenarioPresetRun,
+}
+
+var (
+	scenarioTopology     string
+	scenarioProfileFile  string
+	scenarioCluster      string
+	scenarioTimelineWait time.Duration
+	scenarioResetTimeout time.Duration
+	scenarioRepeat       int
+
+	scenarioPresetDir       string
+	scenarioPresetOverwrite bool
+	scenarioPresetName      string
+	scenarioPresetDelete    bool
+	scenarioSubmitWorkers   int
+)
+
+func init() {
+	rootCmd.AddCommand(scenarioCmd)
+	scenarioCmd.AddCommand(scenarioQueueCmd)
+	scenarioQueueCmd.AddCommand(scenarioQueueAddCmd, scenarioQueueListCmd, scenarioQueueClearCmd,
+		scenarioQueueStartCmd, scenarioQueueReportCmd)
+
+	for _, cmd := range []*cobra.Command{scenarioQueueAddCmd, scenarioQueueListCmd, scenarioQueueClearCmd, scenarioQueueStartCmd} {
+		cmd.Flags().StringVar(&scenarioTopology, "topology", "", "topology name (required)")
+		_ = cmd.MarkFlagRequired("topology")
+	}
+
+	scenarioQueueAddCmd.Flags().StringVarP(&scenarioProfileFile, "profile", "p", "", "path to workload profile file (required)")
+	scenarioQueueAddCmd.Flags().StringVar(&scenarioCluster, "cluster", "", "cluster name within the topology (default: management cluster)")
+	scenarioQueueAddCmd.Flags().DurationVar(&scenarioTimelineWait, "timeline-wait", bench.DefaultTimelineWait, "keep recording workload timelines for this long after submission ends (0 stops when submission ends)")
+	scenarioQueueAddCmd.Flags().IntVar(&scenarioRepeat, "repeat", 1, "queue the scenario this many times, e.g. for run compare")
+	scenarioQueueAddCmd.Flags().IntVar(&scenarioSubmitWorkers, "submit-workers", 1, "how many workloads may be submitted concurrently")
+	_ = scenarioQueueAddCmd.MarkFlagRequired("profile")
+
+	scenarioQueueStartCmd.Flags().DurationVar(&scenarioResetTimeout, "reset-timeout", kueue.DefaultIdleTimeout, "how long to wait for ClusterQueues to drain between scenarios")
+
+	scenarioCmd.AddCommand(scenarioPresetCmd)
+	scenarioPresetCmd.AddCommand(scenarioPresetListCmd, scenarioPresetShowCmd, scenarioPresetExportCmd, scenarioPresetRunCmd)
+
+	scenarioPresetExportCmd.Flags().StringVar(&scenarioPresetDir, "dir", ".", "directory to write the topology and profile to")
+	scenarioPresetExportCmd.Flags().BoolVar(&scenarioPresetOverwrite, "overwrite", false, "replace existing files")
+
+	scenarioPresetRunCmd.Flags().StringVar(&scenarioPresetName, "name", "", "topology name (default: the preset name)")
+	scenarioPresetRunCmd.Flags().DurationVar(&scenarioTimelineWait, "timeline-wait", bench.DefaultTimelineWait, "keep recording workload timelines for this long after submission ends (0 stops when submission ends)")
+	scenarioPresetRunCmd.Flags().IntVar(&scenarioSubmitWorkers, "submit-workers", 1, "how many workloads may be submitted concurrently")
+	scenarioPresetRunCmd.Flags().BoolVar(&scenarioPresetDelete, "delete", false, "delete the topology once the run finishes")
+}
+
+func runScenarioQueueAdd(_ *cobra.Command, _ []string) error {
+	if scenarioRepeat < 1 {
+		return fmt.Errorf("--repeat must be at least 1")
+	}
+	var queue *bench.ScenarioQueue
+	for range scenarioRepeat {
+		var err error
+		queue, err = bench.EnqueueScenario(scenarioTopology, bench.QueuedScenario{
+			ProfilePath:   scenarioProfileFile,
+			Cluster:       scenarioCluster,
+			TimelineWait:  scenarioTimelineWait,
+			SubmitWorkers: scenarioSubmitWorkers,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	times := ""
+	if scenarioRepeat > 1 {
+		times = fmt.Sprintf(" %d times", scenarioRepeat)
+	}
+	fmt.Printf("✓ Queued %s%s against topology '%s' (%d scenarios queued)\n",
+		filepath.Base(scenarioProfileFile), times, scenarioTopology, len(queue.Scenarios))
+	return nil
+}
+
+func runScenarioQueueList(_ *cobra.Command, _ []string) error {
+	queue, err := bench.LoadQueue(scenarioTopology)
+	if err != nil {
+		return err
+	}
+	if len(queue.Scenarios) == 0 {
+		fmt.Printf("No scenarios queued for topology '%s'\n", scenarioTopology)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "#\tPROFILE\tCLUSTER\tTIMELINE WAIT\tADDED")
+	_, _ = fmt.Fprintln(w, "-\t-------\t-------\t-------------\t-----")
+	for i, s := range queue.Scenarios {
+		cluster := s.Cluster
+		if cluster == "" {
+			cluster = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", i+1, s.ProfilePath, cluster, s.TimelineWait,
+			s.AddedAt.Format("2006-01-02 15:04:05"))
+	}
+	return w.Flush()
+}
+
+func runScenarioQueueClear(_ *cobra.Command, _ []string) error {
+	if err := bench.ClearQueue(scenarioTopology); err != nil {
+		return fmt.Errorf("failed to clear scenario queue: %w", err)
+	}
+	fmt.Printf("✓ Cleared the scenario queue of topology '%s'\n", scenarioTopology)
+	return nil
+}
+
+func runScenarioQueueStart(cmd *cobra.Command, _ []string) error {
+	report, err := bench.RunQueue(cmd.Context(), scenarioTopology, bench.QueueOptions{
+		ResetTimeout: scenarioResetTimeout,
+		OnScenario: func(index, total int, s bench.QueuedScenario) {
+			fmt.Printf("\n=== Scenario %d/%d: %s ===\n", index+1, total, s.ProfilePath)
+		},
+	})
+	if report != nil && len(report.Results) > 0 {
+		fmt.Println()
+		printBatchReport(report)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Every scenario saved a run; keep them within the configured retention limits
+	if err := applyRetention(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if failed := report.Failed(); failed > 0 {
+		return fmt.Errorf("%d of %d scenarios failed", failed, len(report.Results))
+	}
+	return nil
+}
+
+func runScenarioQueueReport(_ *cobra.Command, args []string) error {
+	var report *run.BatchReport
+	if len(args) == 1 {
+		var err error
+		if report, err = run.LoadBatch(args[0]); err != nil {
+			return fmt.Errorf("failed to load batch %q: %w", args[0], err)
+		}
+	} else {
+		reports, err := run.ListBatches()
+		if err != nil {
+			return fmt.Errorf("failed to list batches: %w", err)
+		}
+		if len(reports) == 0 {
+			fmt.Println("No batches found")
+			return nil
+		}
+		report = reports[0]
+	}
+	printBatchReport(report)
+	return nil
+}
+
+// printBatchReport prints the consolidated results of a batch, one scenario per row
+func printBatchReport(report *run.BatchReport) {
+	fmt.Printf("Batch %s on topology '%s': %d scenarios, %d failed, %s\n", report.ID, report.Topology,
+		len(report.Results), report.Failed(), report.FinishedAt.Sub(report.StartedAt).Round(time.Second))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PROFILE\tRUN ID\tWORKLOADS\tADMISSION P50/P95\tE2E P50/P95\tSTATUS")
+	_, _ = fmt.Fprintln(w, "-------\t------\t---------\t-----------------\t-----------\t------")
+	for _, r := range report.Results {
+		name := r.ProfileName
+		if name == "" {
+			name = filepath.Base(r.ProfilePath)
+		}
+		runID, admission, e2e := "-", "-", "-"
+		if r.RunID != "" {
+			runID = r.RunID
+		}
+		if r.Latency != nil {
+			admission = formatP50P95(r.Latency.Admission)
+			e2e = formatP50P95(r.Latency.EndToEnd)
+		}
+		status := "ok"
+		if r.Error != "" {
+			status = "failed: " + r.Error
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", name, runID, r.WorkloadCount, admission, e2e, status)
+	}
+	_ = w.Flush()
+}
+
+// formatP50P95 formats the median and 95th percentile of a latency stage
+func formatP50P95(stats run.LatencyStats) string {
+	if stats.Count == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%s / %s", stats.P50.Round(time.Millisecond), stats.P95.Round(time.Millisecond))
+}
+
+func runScenarioPresetList(_ *cobra.Command, _ []string) error {
+	list, err := presets.List()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tDESCRIPTION")
+	_, _ = fmt.Fprintln(w, "----\t-----------")
+	for _, p := range list {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", p.Name, p.Summary())
+	}
+	return w.Flush()
+}
+
+func runScenarioPresetShow(_ *cobra.Command, args []string) error {
+	p, err := presets.Get(args[0])
+	if err != nil {
+		return err
+	}
+	profile, err := p.Profile()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Preset: %s\n\n%s\n\n", p.Name, strings.TrimSpace(p.Description))
+	fmt.Printf("Profile: %s, %s arrivals", profile.Spec.Duration, profile.Spec.ArrivalPattern.Type)
+	if rate := profile.Spec.ArrivalPattern.RatePerMinute; rate != nil {
+		fmt.Printf(" at %g/min", *rate)
+	}
+	fmt.Println()
+	for _, wl := range profile.Spec.Workloads {
+		priority := ""
+		if wl.PriorityClass != "" {
+			priority = ", priority " + wl.PriorityClass
+		}
+		fmt.Printf("  %s weight %d → %s/%s%s\n", wl.Type, wl.Weight, wl.Namespace, wl.LocalQueue, priority)
+	}
+	return nil
+}
+
+func runScenarioPresetExport(_ *cobra.Command, args []string) error {
+	p, err := presets.Get(args[0])
+	if err != nil {
+		return err
+	}
+	topologyPath, profilePath, err := p.Export(scenarioPresetDir, scenarioPresetOverwrite)
+	if err != nil {
+		return fmt.Errorf("failed to export preset: %w", err)
+	}
+	fmt.Printf("✓ Exported preset '%s':\n  %s\n  %s\n", p.Name, topologyPath, profilePath)
+	fmt.Printf("\nRun the exported copies with:\n  kueue-bench topology create -f %s\n  kueue-bench workload submit --topology %s --profile %s\n",
+		topologyPath, p.Name, profilePath)
+	return nil
+}
+
+func runScenarioPresetRun(cmd *cobra.Command, args []string) error {
+	p, err := presets.Get(args[0])
+	if err != nil {
+		return err
+	}
+	cfg, err := p.Topology()
+	if err != nil {
+		return err
+	}
+	profile, err := p.Profile()
+	if err != nil {
+		return err
+	}
+	topologyName := p.Name
+	if scenarioPresetName != "" {
+		topologyName = scenarioPresetName
+	}
+	cfg.Metadata.Name = topologyName
+
+	if err := config.ValidateTopology(cfg); err != nil {
+		return fmt.Errorf("topology validation failed: %w", err)
+	}
+
+	// Reuse a topology left by an earlier run of the preset, but not one created otherwise
+	if topo, err := topology.Load(topologyName); err == nil {
+		same, err := topo.CreatedFrom(cfg)
+		if err != nil {
+			return err
+		}
+		if !same {
+			return fmt.Errorf("topology '%s' exists but was not created from preset '%s'; delete it or pick another name with --name", topologyName, p.Name)
+		}
+		fmt.Printf("Using existing topology '%s'\n", topologyName)
+	} else if errors.Is(err, os.ErrNotExist) {
+		fmt.Printf("Creating topology '%s' from preset '%s'...\n", topologyName, p.Name)
+		if _, err := bench.CreateTopology(cmd.Context(), cfg); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Topology '%s' created successfully\n", topologyName)
+	} else {
+		return fmt.Errorf("failed to load topology: %w", err)
+	}
+
+	fmt.Printf("Submitting workloads of preset '%s'...\n", p.Name)
+	meta, err := bench.RunScenario(cmd.Context(), bench.ScenarioOptions{
+		Profile:       profile,
+		ProfilePath:   "preset:" + p.Name,
+		Topology:      topologyName,
+		TimelineWait:  scenarioTimelineWait,
+		SubmitWorkers: scenarioSubmitWorkers,
+		OnSubmit: func(name, workloadType, namespace string) {
+			fmt.Printf("  %s/%s (%s)\n", namespace, name, workloadType)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if meta.Latency != nil {
+		printLatency(meta.Latency)
+	}
+	if meta.Cost != nil {
+		printCost(meta.Cost)
+	}
+	if meta.ToolUsage != nil {
+		printToolUsage(meta.ToolUsage)
+	}
+	if err := applyRetention(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	if scenarioPresetDelete {
+		if err := bench.DeleteTopology(cmd.Context(), topologyName); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Topology '%s' deleted\n", topologyName)
+		return nil
+	}
+	fmt.Printf("\nTopology '%s' is kept for inspection; delete it with: kueue-bench topology delete %s\n", topologyName, topologyName)
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jhwagner/kueue-bench/pkg/bench"
+	"github.com/jhwagner/kueue-bench/pkg/server"
+)
+
+// serveShutdownTimeout bounds how long in-flight requests may take once the server stops
+const serveShutdownTimeout = 10 * time.Second
+
+var serveAddr string
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve topology and scenario operations over a local HTTP API",
+	Long: `Serve topology and scenario operations over a local HTTP API, so long-running
+benchmark orchestration (CI pipelines, notebooks) can create topologies, trigger runs and
+monitor them without re-invoking the CLI for every step.
+
+Creating or deleting a topology and running a scenario return an operation to poll:
+
+  curl -X POST localhost:8080/v1/topologies -d '{"path": "examples/topologies/basic-queue.yaml"}'
+  curl -X POST localhost:8080/v1/runs -d '{"topology": "basic", "profilePath": "profile.yaml"}'
+  curl localhost:8080/v1/operations/op-2
+  curl localhost:8080/v1/runs/<run-id>/results
+
+The server shares the CLI's state directory and has no authentication, so it listens on
+localhost by default. Ctrl-C cancels running operations and stops the server once they
+have cleaned up.`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "address to listen on")
+}
+
+func runServe(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+	h, err := bench.OpenDefault()
+	if err != nil {
+		return fmt.Errorf("failed to open state: %w", err)
+	}
+	srv := server.New(ctx, h)
+	httpServer := &http.Server{
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	listener, err := net.Listen("tcp", serveAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", serveAddr, err)
+	}
+	fmt.Printf("✓ Serving the kueue-bench API on http://%s\n", listener.Addr())
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- httpServer.Serve(listener) }()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	fmt.Println("Stopping server; waiting for running operations to clean up...")
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), serveShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("failed to stop server: %w", err)
+	}
+	srv.Wait()
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jhwagner/kueue-bench/pkg/run"
+	"github.com/jhwagner/kueue-bench/pkg/state"
+	"github.com/jhwagner/kueue-bench/pkg/topology"
+)
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Inspect local kueue-bench state",
+	Long: `Inspect the local state directory used to track topologies, runs, and snapshots.
+
+The directory is --state-dir if set, else $KUEUE_BENCH_STATE_DIR, else ~/.kueue-bench if it
+exists, else $XDG_DATA_HOME/kueue-bench (~/.local/share/kueue-bench).`,
+}
+
+var stateShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the contents of the state directory",
+	Long: `Show the state directory location, schema version, and every record it holds.
+
+Useful for debugging topologies whose clusters or kubeconfigs have gone missing.`,
+	RunE: runStateShow,
+}
+
+func init() {
+	rootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateShowCmd)
+}
+
+func runStateShow(_ *cobra.Command, _ []string) error {
+	store, err := state.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open state: %w", err)
+	}
+
+	info, err := store.Info()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("State directory: %s\n", store.Root())
+	fmt.Printf("Schema version:  %d\n", info.SchemaVersion)
+
+	topologies, err := topology.List()
+	if err != nil {
+		return fmt.Errorf("failed to list topologies: %w", err)
+	}
+	fmt.Printf("\nTopologies (%d):\n", len(topologies))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	for _, topo := range topologies {
+		metadata := topo.GetMetadata()
+		if topo.Unsupported() {
+			_, _ = fmt.Fprintf(w, "  %s\tunsupported (metadata schema version %d; upgrade kueue-bench)\n",
+				metadata.Name, metadata.SchemaVersion)
+			continue
+		}
+		clusterNames := make([]string, 0, len(metadata.Clusters))
+		for name := range metadata.Clusters {
+			clusterNames = append(clusterNames, name)
+		}
+		sort.Strings(clusterNames)
+		for _, name := range clusterNames {
+			c := metadata.Clusters[name]
+			kubeconfig := c.KubeconfigPath
+			if _, err := os.Stat(kubeconfig); err != nil {
+				kubeconfig += " (missing)"
+			}
+			_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", metadata.Name, c.Name, c.Role, kubeconfig)
+		}
+	}
+	_ = w.Flush()
+
+	runs, err := run.List()
+	if err != nil {
+		return fmt.Errorf("failed to list runs: %w", err)
+	}
+	fmt.Printf("\nRuns (%d):\n", len(runs))
+	for _, r := range runs {
+		fmt.Printf("  %s\t%s\n", r.RunID, r.StartedAt.Format("2006-01-02 15:04:05"))
+	}
+
+	snapshots, err := filepath.Glob(store.Path(state.Snapshots, "*.tar"))
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	fmt.Printf("\nSnapshots (%d):\n", len(snapshots))
+	for _, path := range snapshots {
+		size := int64(0)
+		if fi, err := os.Stat(path); err == nil {
+			size = fi.Size()
+		}
+		fmt.Printf("  %s\t%d MiB\n", filepath.Base(path), size>>20)
+	}
+
+	return nil
+}
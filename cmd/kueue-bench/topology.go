@@ -3,11 +3,18 @@ package main
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"text/tabwriter"
+	"time"
 
+	"github.com/jhwagner/kueue-bench/pkg/bench"
 	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/kueue"
+	"github.com/jhwagner/kueue-bench/pkg/kwok"
 	"github.com/jhwagner/kueue-bench/pkg/topology"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 var topologyCmd = &cobra.Command{
@@ -27,9 +34,14 @@ The topology name can be specified either:
 
 This will:
   1. Create kind cluster(s)
-  2. Install KWOK for node simulation
-  3. Install Kueue
-  4. Apply Kueue configuration objects`,
+  2. Load local images (spec.images and --load-image)
+  3. Install KWOK and its simulated nodes, and Kueue, concurrently
+  4. Install extensions
+  5. Apply Kueue configuration objects
+
+Running create again for an existing topology, e.g. after a partial failure,
+reuses its kind clusters and completes or updates their components and Kueue
+objects instead of failing. Delete the topology to change kind-level settings.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runTopologyCreate,
 }
@@ -42,6 +54,37 @@ var topologyDeleteCmd = &cobra.Command{
 	RunE:  runTopologyDelete,
 }
 
+var topologyDeleteNodesCmd = &cobra.Command{
+	Use:   "delete-nodes <name>",
+	Short: "Delete simulated nodes from a topology cluster",
+	Long: `Delete the KWOK nodes of one node pool, or every KWOK node when --pool is omitted.
+Nodes are deleted with a single DeleteCollection request (falling back to parallel
+deletes), so even pools of tens of thousands of nodes are removed quickly.
+
+Examples:
+  kueue-bench topology delete-nodes my-cluster --pool gpu
+  kueue-bench topology delete-nodes my-multikueue --cluster my-multikueue-worker-1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTopologyDeleteNodes,
+}
+
+var topologyReconcileNodesCmd = &cobra.Command{
+	Use:   "reconcile-nodes <name>",
+	Short: "Create missing and delete extra simulated nodes in a topology cluster",
+	Long: `Converge a cluster's KWOK nodes to the node pools in the topology's config.
+Existing nodes are listed and only the missing ones are created; existing nodes
+whose labels or capacity drifted from their pool are applied again, and nodes
+beyond a pool's count, or of pools no longer in the config, are deleted. Use it to
+resume node creation that was interrupted, or to restore nodes deleted with
+delete-nodes.
+
+Examples:
+  kueue-bench topology reconcile-nodes my-cluster
+  kueue-bench topology reconcile-nodes my-multikueue --cluster my-multikueue-worker-1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTopologyReconcileNodes,
+}
+
 var topologyListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all topologies",
@@ -49,19 +92,121 @@ var topologyListCmd = &cobra.Command{
 	RunE:  runTopologyList,
 }
 
+var topologyStatusCmd = &cobra.Command{
+	Use:   "status <name>",
+	Short: "Show a topology's clusters and MultiKueue connectivity",
+	Long: `Show a topology's clusters and, for MultiKueue topologies, whether the
+management cluster can reach each worker, read from the MultiKueueCluster
+Active conditions. Exits non-zero if any worker is unreachable.
+
+With --repair, the kubeconfigs of unreachable workers are extracted again and
+their Secrets and MultiKueueClusters on the management cluster are rewritten.
+
+Examples:
+  kueue-bench topology status my-topology
+  kueue-bench topology status my-topology --repair`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTopologyStatus,
+}
+
+var topologyWaitCmd = &cobra.Command{
+	Use:   "wait <name>",
+	Short: "Wait until every cluster of a topology is ready for benchmarks",
+	Long: `Wait until every cluster of a topology is ready to run benchmarks: the Kueue
+controller is available, CRDs are established and serve the API version
+kueue-bench uses, webhooks are serving, every ClusterQueue, LocalQueue,
+AdmissionCheck and MultiKueueCluster is active, and each management cluster
+has a MultiKueueCluster for every worker.
+
+Clusters are checked concurrently under a single timeout. Exits non-zero if any
+cluster is not ready in time, so it can gate scripts between topology create
+and a scenario run.
+
+Examples:
+  kueue-bench topology wait my-topology
+  kueue-bench topology wait my-topology --timeout 10m && kueue-bench scenario run ...`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTopologyWait,
+}
+
+var topologyDescribeCmd = &cobra.Command{
+	Use:   "describe <name>",
+	Short: "Show a topology's clusters and how long each took to create",
+	Long: `Show a topology's clusters and the duration of each creation stage per cluster:
+kind cluster, image loading, Kwok install, simulated nodes, Kueue install,
+extensions, API readiness, MultiKueue setup and Kueue objects.
+
+Kueue is installed concurrently with Kwok and its nodes, so those stages overlap
+and the stage durations of a cluster may add up to more than its total.
+
+Examples:
+  kueue-bench topology describe my-topology`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTopologyDescribe,
+}
+
+var topologyExplainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Print the clusters a topology file expands to",
+	Long: `Validate a topology file and print the clusters topology create would provision,
+without creating anything.
+
+WorkerSets are expanded into worker clusters with their derived ResourceFlavors,
+ClusterQueue quotas and LocalQueues, and each management cluster's Kueue config
+is shown as derived from its WorkerSets: summed quotas, auto-added MultiKueue
+admission checks and LocalQueue namespaces. Output is YAML, one document per
+cluster.
+
+Examples:
+  kueue-bench topology explain -f multikueue.yaml`,
+	Args: cobra.NoArgs,
+	RunE: runTopologyExplain,
+}
+
 var (
-	topologyFile string
+	topologyFile                 string
+	topologyLoadImages           []string
+	topologyGoldenSnapshots      bool
+	topologyOffline              bool
+	topologyProvisionConcurrency int
+	topologyProbeCapacity        bool
+	topologyStatusRepair         bool
+	topologyNodesCluster         string
+	topologyNodesPool            string
+	topologyWaitTimeout          time.Duration
 )
 
 func init() {
 	rootCmd.AddCommand(topologyCmd)
 	topologyCmd.AddCommand(topologyCreateCmd)
 	topologyCmd.AddCommand(topologyDeleteCmd)
+	topologyCmd.AddCommand(topologyDeleteNodesCmd)
+	topologyCmd.AddCommand(topologyReconcileNodesCmd)
 	topologyCmd.AddCommand(topologyListCmd)
+	topologyCmd.AddCommand(topologyStatusCmd)
+	topologyCmd.AddCommand(topologyWaitCmd)
+	topologyCmd.AddCommand(topologyDescribeCmd)
+	topologyCmd.AddCommand(topologyExplainCmd)
+
+	topologyDeleteNodesCmd.Flags().StringVar(&topologyNodesCluster, "cluster", "", "cluster name within the topology (default: management cluster)")
+	topologyDeleteNodesCmd.Flags().StringVar(&topologyNodesPool, "pool", "", "node pool whose nodes are deleted (default: all pools)")
+	topologyReconcileNodesCmd.Flags().StringVar(&topologyNodesCluster, "cluster", "", "cluster name within the topology (default: management cluster)")
+
+	topologyWaitCmd.Flags().DurationVar(&topologyWaitTimeout, "timeout", kueue.DefaultReadyTimeout, "how long to wait for all clusters together")
+
+	topologyStatusCmd.Flags().BoolVar(&topologyStatusRepair, "repair", false, "re-extract the kubeconfigs of unreachable MultiKueue workers and update their Secrets")
 
 	// Flags for create command
 	topologyCreateCmd.Flags().StringVarP(&topologyFile, "file", "f", "", "path to topology configuration file (required)")
+	topologyCreateCmd.Flags().StringSliceVar(&topologyLoadImages, "load-image", nil, "local Docker image to load into every cluster (repeatable; added to spec.images)")
+	topologyCreateCmd.Flags().BoolVar(&topologyGoldenSnapshots, "golden-snapshots", false, "reuse images cached from earlier identical clusters (sets spec.goldenSnapshots)")
+	topologyCreateCmd.Flags().IntVar(&topologyProvisionConcurrency, "provision-concurrency", 0, "number of Kueue objects of one kind to create in parallel (sets spec.kueue.provisionConcurrency)")
+	topologyCreateCmd.Flags().BoolVar(&topologyProbeCapacity, "probe-capacity", false, "check every ClusterQueue admits a job sized at its nominal quota (sets spec.kueue.probeCapacity)")
+	topologyCreateCmd.Flags().BoolVar(&topologyOffline, "offline", false, "install Kwok from manifests embedded in the binary and read extension manifests only from the download cache (sets spec.kwok.offline)")
 	_ = topologyCreateCmd.MarkFlagRequired("file")
+
+	topologyExplainCmd.Flags().StringVarP(&topologyFile, "file", "f", "", "path to topology configuration file (required)")
+	_ = topologyExplainCmd.MarkFlagRequired("file")
 }
 
 func runTopologyCreate(cmd *cobra.Command, args []string) error {
@@ -85,36 +230,119 @@ func runTopologyCreate(cmd *cobra.Command, args []string) error {
 	}
 	cfg.Metadata.Name = name
 
-	fmt.Printf("Creating topology '%s' from file '%s'...\n", name, topologyFile)
+	// Images from --load-image are appended to those declared in the config,
+	// skipping any already listed so validation doesn't reject them as duplicates
+	for _, image := range topologyLoadImages {
+		if !slices.Contains(cfg.Spec.Images, image) {
+			cfg.Spec.Images = append(cfg.Spec.Images, image)
+		}
+	}
+	if topologyGoldenSnapshots {
+		cfg.Spec.GoldenSnapshots = true
+	}
+	if topologyOffline {
+		if cfg.Spec.Kwok == nil {
+			cfg.Spec.Kwok = &config.KwokSettings{}
+		}
+		cfg.Spec.Kwok.Offline = true
+	}
 
-	if err := config.ValidateTopology(cfg); err != nil {
-		return fmt.Errorf("topology validation failed: %w", err)
+	if topologyProvisionConcurrency > 0 {
+		if cfg.Spec.Kueue == nil {
+			cfg.Spec.Kueue = &config.KueueSettings{}
+		}
+		cfg.Spec.Kueue.ProvisionConcurrency = topologyProvisionConcurrency
+	}
+	if topologyProbeCapacity {
+		if cfg.Spec.Kueue == nil {
+			cfg.Spec.Kueue = &config.KueueSettings{}
+		}
+		cfg.Spec.Kueue.ProbeCapacity = true
 	}
 
-	fmt.Println("✓ Topology loaded and validated")
+	fmt.Printf("Creating topology '%s' from file '%s'...\n", name, topologyFile)
 
-	// Create topology (creates clusters, installs components, saves metadata)
-	if _, err := topology.Create(cmd.Context(), name, cfg); err != nil {
-		return fmt.Errorf("failed to create topology: %w", err)
+	// Validate and create topology (creates clusters, installs components, saves metadata)
+	if _, err := bench.CreateTopology(cmd.Context(), cfg); err != nil {
+		return err
 	}
 
 	fmt.Printf("✓ Topology '%s' created successfully\n", name)
 	return nil
 }
 
-func runTopologyDelete(cmd *cobra.Command, args []string) error {
-	name := args[0]
-	fmt.Printf("Deleting topology '%s'...\n", name)
+func runTopologyDeleteNodes(cmd *cobra.Command, args []string) error {
+	target, err := bench.ResolveCluster(args[0], topologyNodesCluster)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := kwok.DeleteNodes(cmd.Context(), target.KubeconfigPath, args[0], topologyNodesPool, target.ClientOption())
+	if err != nil {
+		return fmt.Errorf("failed to delete nodes: %w", err)
+	}
+	fmt.Printf("✓ Deleted %d nodes from cluster '%s'\n", deleted, target.Name)
+	return nil
+}
+
+func runTopologyReconcileNodes(cmd *cobra.Command, args []string) error {
+	target, err := bench.ResolveCluster(args[0], topologyNodesCluster)
+	if err != nil {
+		return err
+	}
+	topo, err := topology.Load(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load topology: %w", err)
+	}
+
+	if _, err := topo.ReconcileNodes(cmd.Context(), target.Name); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Nodes of cluster '%s' match its node pools\n", target.Name)
+	return nil
+}
 
-	// Load topology metadata
-	topo, err := topology.Load(name)
+func runTopologyExplain(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadTopology(topologyFile)
 	if err != nil {
 		return fmt.Errorf("failed to load topology: %w", err)
 	}
+	if err := config.ValidateTopology(cfg); err != nil {
+		return fmt.Errorf("invalid topology: %w", err)
+	}
+	clusters, err := config.ExpandTopology(cfg)
+	if err != nil {
+		return err
+	}
+
+	for i, c := range clusters {
+		if i > 0 {
+			fmt.Println("---")
+		}
+		role := c.Role
+		if role == "" {
+			role = "standalone"
+		}
+		fmt.Printf("# %s (%s)\n", c.Name, role)
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(c); err != nil {
+			return fmt.Errorf("failed to marshal cluster %q: %w", c.Name, err)
+		}
+		if err := enc.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runTopologyDelete(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	fmt.Printf("Deleting topology '%s'...\n", name)
 
 	// Delete topology (deletes clusters and metadata)
-	if err := topo.Delete(cmd.Context()); err != nil {
-		return fmt.Errorf("failed to delete topology: %w", err)
+	if err := bench.DeleteTopology(cmd.Context(), name); err != nil {
+		return err
 	}
 
 	fmt.Printf("✓ Topology '%s' deleted successfully\n", name)
@@ -138,6 +366,11 @@ func runTopologyList(cmd *cobra.Command, args []string) error {
 	_, _ = fmt.Fprintln(w, "----\t--------\t-------")
 	for _, topo := range topologies {
 		metadata := topo.GetMetadata()
+		if topo.Unsupported() {
+			_, _ = fmt.Fprintf(w, "%s\t-\tunsupported (metadata schema version %d; upgrade kueue-bench)\n",
+				metadata.Name, metadata.SchemaVersion)
+			continue
+		}
 		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n",
 			metadata.Name,
 			len(metadata.Clusters),
@@ -147,3 +380,169 @@ func runTopologyList(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runTopologyStatus(cmd *cobra.Command, args []string) error {
+	topo, err := topology.Load(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load topology: %w", err)
+	}
+	metadata := topo.GetMetadata()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "CLUSTER\tROLE\tKUBECONFIG")
+	_, _ = fmt.Fprintln(w, "-------\t----\t----------")
+	for _, name := range sortedClusterNames(metadata.Clusters) {
+		c := metadata.Clusters[name]
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", c.Name, c.Role, c.KubeconfigPath)
+	}
+	_ = w.Flush()
+
+	if !hasManagementCluster(metadata) {
+		return nil
+	}
+
+	health, err := topo.CheckMultiKueue(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to check MultiKueue: %w", err)
+	}
+	var unreachable []string
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "WORKER\tMANAGEMENT\tWORKERSET\tMULTIKUEUECLUSTER\tSTATUS")
+	_, _ = fmt.Fprintln(w, "------\t----------\t---------\t-----------------\t------")
+	for _, h := range health {
+		status := "connected"
+		if !h.Connected {
+			status = "unreachable: " + h.Reason
+			unreachable = append(unreachable, h.Worker)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", h.Worker, h.Management, h.WorkerSet, h.Cluster, status)
+	}
+	_ = w.Flush()
+
+	if len(unreachable) == 0 {
+		return nil
+	}
+	if !topologyStatusRepair {
+		return fmt.Errorf("%d MultiKueue worker(s) unreachable; rerun with --repair to update their kubeconfigs", len(unreachable))
+	}
+
+	fmt.Printf("\nRepairing %d MultiKueue worker(s)...\n", len(unreachable))
+	if err := topo.RepairMultiKueue(cmd.Context(), unreachable); err != nil {
+		return err
+	}
+	fmt.Printf("✓ MultiKueue workers in topology '%s' reconnected\n", metadata.Name)
+	return nil
+}
+
+func runTopologyWait(cmd *cobra.Command, args []string) error {
+	topo, err := topology.Load(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load topology: %w", err)
+	}
+
+	fmt.Printf("Waiting up to %s for %d cluster(s) in topology '%s'...\n", topologyWaitTimeout, len(topo.GetMetadata().Clusters), args[0])
+	results, err := topo.WaitForReady(cmd.Context(), topologyWaitTimeout)
+	if err != nil {
+		return err
+	}
+
+	var notReady int
+	for _, r := range results {
+		if r.Err != nil {
+			notReady++
+			fmt.Printf("Cluster '%s' (%s) not ready: %v\n", r.Cluster, r.Role, r.Err)
+			continue
+		}
+		fmt.Printf("✓ Cluster '%s' (%s) ready after %s\n", r.Cluster, r.Role, formatStageDuration(r.Duration))
+	}
+	if notReady > 0 {
+		return fmt.Errorf("%d of %d cluster(s) not ready", notReady, len(results))
+	}
+	fmt.Printf("✓ Topology '%s' is ready\n", args[0])
+	return nil
+}
+
+func runTopologyDescribe(cmd *cobra.Command, args []string) error {
+	topo, err := topology.Load(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load topology: %w", err)
+	}
+	metadata := topo.GetMetadata()
+
+	fmt.Printf("Name:     %s\n", metadata.Name)
+	fmt.Printf("Created:  %s\n", metadata.CreatedAt.Format("2006-01-02 15:04:05"))
+	if metadata.ConfigPath != "" {
+		fmt.Printf("Config:   %s\n", metadata.ConfigPath)
+	}
+	if metadata.CreateDuration > 0 {
+		fmt.Printf("Duration: %s\n", formatStageDuration(metadata.CreateDuration))
+	}
+	fmt.Println()
+
+	// Only stages some cluster ran get a column
+	var stages []string
+	for _, stage := range topology.Stages {
+		for _, c := range metadata.Clusters {
+			if _, ok := c.StageDuration(stage); ok {
+				stages = append(stages, stage)
+				break
+			}
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	header := []string{"CLUSTER", "ROLE"}
+	for _, stage := range stages {
+		header = append(header, strings.ToUpper(stage))
+	}
+	header = append(header, "TOTAL")
+	underline := make([]string, len(header))
+	for i, h := range header {
+		underline[i] = strings.Repeat("-", len(h))
+	}
+	_, _ = fmt.Fprintln(w, strings.Join(header, "\t"))
+	_, _ = fmt.Fprintln(w, strings.Join(underline, "\t"))
+	for _, name := range sortedClusterNames(metadata.Clusters) {
+		c := metadata.Clusters[name]
+		role := c.Role
+		if c.External {
+			role += " (external)"
+		}
+		row := []string{c.Name, role}
+		for _, stage := range stages {
+			cell := "-"
+			if d, ok := c.StageDuration(stage); ok {
+				cell = formatStageDuration(d)
+			}
+			row = append(row, cell)
+		}
+		total := "-"
+		if c.SetupDuration > 0 {
+			total = formatStageDuration(c.SetupDuration)
+		}
+		row = append(row, total)
+		_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	_ = w.Flush()
+
+	if len(stages) == 0 {
+		fmt.Println("\nNo stage timings recorded; the topology was created by an older version of kueue-bench.")
+	}
+	return nil
+}
+
+// formatStageDuration rounds a stage duration to a tenth of a second
+func formatStageDuration(d time.Duration) string {
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// hasManagementCluster reports whether the topology has a MultiKueue management cluster
+func hasManagementCluster(metadata *topology.Metadata) bool {
+	for _, c := range metadata.Clusters {
+		if c.Role == config.RoleManagement {
+			return true
+		}
+	}
+	return false
+}
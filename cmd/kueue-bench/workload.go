@@ -2,16 +2,17 @@ package main
 
 import (
 	"fmt"
-	"math/rand"
 	"os"
 	"path/filepath"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
 
+	"github.com/jhwagner/kueue-bench/pkg/bench"
 	"github.com/jhwagner/kueue-bench/pkg/config"
 	"github.com/jhwagner/kueue-bench/pkg/run"
-	"github.com/jhwagner/kueue-bench/pkg/topology"
 	"github.com/jhwagner/kueue-bench/pkg/workload"
 )
 
@@ -35,152 +36,190 @@ Examples:
 	RunE: runWorkloadSubmit,
 }
 
+var workloadDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete generated workloads from a topology",
+	Long: `Delete the Jobs, JobSets and RayJobs kueue-bench submitted for a run, or for every
+run when --run is omitted. Workloads are deleted with one DeleteCollection request per
+namespace (falling back to parallel deletes), so large runs are cleared quickly. Their
+pods are garbage collected in the background.
+
+Examples:
+  kueue-bench workload delete --topology my-cluster --run a1b2c3d4
+  kueue-bench workload delete --topology my-cluster`,
+	Args: cobra.NoArgs,
+	RunE: runWorkloadDelete,
+}
+
 var (
-	workloadProfileFile string
-	workloadTopology    string
-	workloadCluster     string
-	workloadDryRun      bool
+	workloadProfileFile  string
+	workloadTopology     string
+	workloadCluster      string
+	workloadDryRun       bool
+	workloadTimelineWait time.Duration
+	workloadSubmitters   int
+	workloadDeleteRun    string
 )
 
 func init() {
 	rootCmd.AddCommand(workloadCmd)
 	workloadCmd.AddCommand(workloadSubmitCmd)
+	workloadCmd.AddCommand(workloadDeleteCmd)
 
 	workloadSubmitCmd.Flags().StringVarP(&workloadProfileFile, "profile", "p", "", "path to workload profile file (required)")
 	workloadSubmitCmd.Flags().StringVar(&workloadTopology, "topology", "", "topology name (required unless --dry-run)")
 	workloadSubmitCmd.Flags().StringVar(&workloadCluster, "cluster", "", "cluster name within the topology (default: management cluster)")
 	workloadSubmitCmd.Flags().BoolVar(&workloadDryRun, "dry-run", false, "build workloads and print them without submitting")
+	workloadSubmitCmd.Flags().DurationVar(&workloadTimelineWait, "timeline-wait", bench.DefaultTimelineWait, "keep recording workload timelines for this long after submission ends (0 stops when submission ends)")
+	workloadSubmitCmd.Flags().IntVar(&workloadSubmitters, "submit-workers", 1, "how many workloads may be submitted concurrently")
 
 	_ = workloadSubmitCmd.MarkFlagRequired("profile")
+
+	workloadDeleteCmd.Flags().StringVar(&workloadTopology, "topology", "", "topology name (required)")
+	workloadDeleteCmd.Flags().StringVar(&workloadCluster, "cluster", "", "cluster name within the topology (default: management cluster)")
+	workloadDeleteCmd.Flags().StringVar(&workloadDeleteRun, "run", "", "run ID whose workloads are deleted (default: all runs)")
+	_ = workloadDeleteCmd.MarkFlagRequired("topology")
 }
 
 func runWorkloadSubmit(cmd *cobra.Command, _ []string) error {
-	// Load and validate workload profile
+	// Load workload profile; RunScenario validates it
 	profile, err := config.LoadWorkloadProfile(workloadProfileFile)
 	if err != nil {
 		return fmt.Errorf("failed to load workload profile: %w", err)
 	}
-	if err := config.ValidateWorkloadProfile(profile); err != nil {
-		return fmt.Errorf("invalid workload profile: %w", err)
+	if !workloadDryRun && workloadTopology == "" {
+		return fmt.Errorf("--topology is required when not using --dry-run")
 	}
 
-	// Resolve kubeconfig path from topology metadata
-	kubeconfigPath := ""
-	if !workloadDryRun {
-		if workloadTopology == "" {
-			return fmt.Errorf("--topology is required when not using --dry-run")
-		}
-		kubeconfigPath, err = resolveKubeconfigPath(workloadTopology, workloadCluster)
-		if err != nil {
-			return err
-		}
+	profilePath, _ := filepath.Abs(workloadProfileFile)
+	meta, err := bench.RunScenario(cmd.Context(), bench.ScenarioOptions{
+		Profile:       profile,
+		ProfilePath:   profilePath,
+		Topology:      workloadTopology,
+		Cluster:       workloadCluster,
+		DryRun:        workloadDryRun,
+		TimelineWait:  workloadTimelineWait,
+		SubmitWorkers: workloadSubmitters,
+		OnSubmit: func(name, workloadType, namespace string) {
+			fmt.Printf("  %s/%s (%s)\n", namespace, name, workloadType)
+		},
+	})
+	if err != nil {
+		return err
 	}
 
-	runID := generateRunID()
-	startedAt := time.Now()
-
-	opts := []workload.EngineOption{
-		workload.WithOnSubmit(func(name, workloadType, namespace string) {
-			fmt.Printf("  %s/%s (%s)\n", namespace, name, workloadType)
-		}),
+	if meta.Latency != nil {
+		printLatency(meta.Latency)
 	}
-	if workloadDryRun {
-		opts = append(opts, workload.WithDryRun())
+	printWorkerLoss(meta.WorkerLoss)
+	if meta.Cost != nil {
+		printCost(meta.Cost)
 	}
-
-	engine, err := workload.NewEngine(profile, kubeconfigPath, runID, opts...)
-	if err != nil {
-		return fmt.Errorf("failed to create engine: %w", err)
+	if meta.ToolUsage != nil {
+		printToolUsage(meta.ToolUsage)
 	}
 
-	fmt.Printf("Submitting workloads from profile %q (run ID: %s, seed: %d)\n",
-		profile.Metadata.Name, runID, engine.EffectiveSeed())
-	if workloadDryRun {
-		fmt.Println("(dry-run mode: workloads will not be submitted)")
+	// Runs are saved on every submit; keep them within the configured retention limits
+	if err := applyRetention(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 	}
+	return nil
+}
 
-	result, err := engine.Run(cmd.Context())
+func runWorkloadDelete(cmd *cobra.Command, _ []string) error {
+	target, err := bench.ResolveCluster(workloadTopology, workloadCluster)
 	if err != nil {
-		return fmt.Errorf("workload generation failed: %w", err)
+		return err
 	}
-
-	elapsed := time.Since(startedAt)
-	fmt.Printf("Workload generation complete: %d workloads in %s (run ID: %s)\n",
-		result.WorkloadCount, elapsed.Round(time.Millisecond), runID)
-
-	// Persist run metadata (best-effort)
-	profilePath, _ := filepath.Abs(workloadProfileFile)
-	meta := &run.RunMetadata{
-		RunID:         runID,
-		ProfileName:   profile.Metadata.Name,
-		ProfilePath:   profilePath,
-		TopologyName:  workloadTopology,
-		ClusterName:   workloadCluster,
-		Seed:          result.EffectiveSeed,
-		DryRun:        workloadDryRun,
-		WorkloadCount: result.WorkloadCount,
-		StartedAt:     startedAt,
-		Duration:      elapsed.Round(time.Millisecond).String(),
-	}
-	if err := run.Save(meta); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to save run metadata: %v\n", err)
+	client, err := workload.NewWorkloadClient(target.KubeconfigPath, target.ClientOption())
+	if err != nil {
+		return err
 	}
 
+	deleted, err := client.DeleteRun(cmd.Context(), workloadTopology, workloadDeleteRun)
+	if err != nil {
+		return fmt.Errorf("failed to delete workloads: %w", err)
+	}
+	fmt.Printf("✓ Deleted %d workloads from cluster '%s'\n", deleted, target.Name)
 	return nil
 }
 
-// resolveKubeconfigPath returns the kubeconfig path for the target cluster within a topology.
-// If clusterName is empty, the target is inferred:
-//  1. A cluster named after the topology (MultiKueue management cluster) is preferred.
-//  2. If no such cluster exists but the topology has exactly one cluster, that cluster is used.
-//  3. Otherwise --cluster must be specified explicitly.
-func resolveKubeconfigPath(topologyName, clusterName string) (string, error) {
-	topo, err := topology.Load(topologyName)
-	if err != nil {
-		return "", fmt.Errorf("failed to load topology %q: %w", topologyName, err)
-	}
-
-	meta := topo.GetMetadata()
-
-	if clusterName == "" {
-		if _, ok := meta.Clusters[topologyName]; ok {
-			// MultiKueue topology: management cluster is named after the topology.
-			clusterName = topologyName
-		} else if len(meta.Clusters) == 1 {
-			// Single-cluster topology: only one choice.
-			for name := range meta.Clusters {
-				clusterName = name
-			}
-		} else {
-			return "", fmt.Errorf("topology %q has multiple clusters; use --cluster to specify one of: %v",
-				topologyName, clusterNames(meta.Clusters))
+// printWorkerLoss prints how the management cluster handled each lost worker
+func printWorkerLoss(results []run.WorkerLossResult) {
+	for _, r := range results {
+		fmt.Printf("Worker loss '%s': %d dispatched, %d requeued, %d rejected, %d unresolved\n",
+			r.Worker, r.Workloads, r.Requeued, r.Rejected, r.Unresolved)
+		if r.Recovery.Count > 0 {
+			fmt.Printf("  %-22s %s / %s / %s (%d workloads)\n", "deleted → recovered",
+				r.Recovery.P50.Round(time.Millisecond), r.Recovery.P95.Round(time.Millisecond),
+				r.Recovery.Max.Round(time.Millisecond), r.Recovery.Count)
 		}
 	}
+}
 
-	cluster, ok := meta.Clusters[clusterName]
-	if !ok {
-		return "", fmt.Errorf("cluster %q not found in topology %q (available: %v)",
-			clusterName, topologyName, clusterNames(meta.Clusters))
+// printCost prints the cost of each ClusterQueue's capacity over a run
+func printCost(summary *run.CostSummary) {
+	fmt.Printf("Cost over %s (nominal / used / idle, utilization):\n", summary.Duration.Round(time.Second))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	for _, q := range append(summary.Queues, summary.Total) {
+		name := q.ClusterQueue
+		if name == "" {
+			name = "total"
+		}
+		_, _ = fmt.Fprintf(w, "  %s\t%.2f / %.2f / %.2f\t%.0f%%\n", name, q.NominalCost, q.UsedCost, q.IdleCost, q.Utilization*100)
 	}
-	return cluster.KubeconfigPath, nil
+	_ = w.Flush()
 }
 
-// clusterNames returns the cluster name list for error messages.
-func clusterNames(clusters map[string]topology.Cluster) []string {
-	names := make([]string, 0, len(clusters))
-	for name := range clusters {
-		names = append(names, name)
+// printToolUsage prints the resources kueue-bench itself used during a run
+func printToolUsage(usage *run.ToolUsage) {
+	limit := "none"
+	if usage.MemoryLimit > 0 {
+		limit = resource.NewQuantity(usage.MemoryLimit, resource.BinarySI).String()
 	}
-	return names
+	fmt.Printf("kueue-bench usage: %s CPU (%.2f cores), peak RSS %s, %d GCs (GOMAXPROCS %d, memory limit %s, %d submit workers)\n",
+		usage.CPUTime.Round(time.Millisecond), usage.CPUCores, resource.NewQuantity(usage.PeakRSS, resource.BinarySI).String(),
+		usage.GCCycles, usage.GOMAXPROCS, limit, usage.SubmitWorkers)
 }
 
-// generateRunID returns a short random lowercase alphanumeric identifier.
-// Uses math/rand directly (not the profile seed) so run IDs are unique across reruns of the same profile.
-func generateRunID() string {
-	const chars = "abcdefghijklmnopqrstuvwxyz0123456789"
-	b := make([]byte, 8)
-	for i := range b {
-		b[i] = chars[rand.Intn(len(chars))] //nolint:gosec // run ID is non-security-sensitive
+// printLatency prints the latency summary of a run's workload timelines
+func printLatency(summary *run.TimelineSummary) {
+	fmt.Println("Latency (p50 / p95 / max):")
+	for _, stage := range []struct {
+		name  string
+		stats run.LatencyStats
+	}{
+		{"submit → admitted", summary.Admission},
+		{"admitted → scheduled", summary.Scheduling},
+		{"scheduled → running", summary.Startup},
+		{"submit → running", summary.EndToEnd},
+	} {
+		if stage.stats.Count == 0 {
+			fmt.Printf("  %-22s –\n", stage.name)
+			continue
+		}
+		fmt.Printf("  %-22s %s / %s / %s (%d workloads)\n", stage.name,
+			stage.stats.P50.Round(time.Millisecond), stage.stats.P95.Round(time.Millisecond),
+			stage.stats.Max.Round(time.Millisecond), stage.stats.Count)
+	}
+
+	checks := summary.AdmissionChecks
+	if checks == nil {
+		return
+	}
+	fmt.Printf("Admission checks: %d workloads, %d retried, %d retries\n", checks.Workloads, checks.Retried, checks.Retries)
+	for _, state := range []struct {
+		name  string
+		stats run.LatencyStats
+	}{
+		{"time in Pending", checks.Pending},
+		{"time in Retry", checks.Retry},
+	} {
+		if state.stats.Count == 0 {
+			continue
+		}
+		fmt.Printf("  %-22s %s / %s / %s (%d checks)\n", state.name,
+			state.stats.P50.Round(time.Millisecond), state.stats.P95.Round(time.Millisecond),
+			state.stats.Max.Round(time.Millisecond), state.stats.Count)
 	}
-	return string(b)
 }
@@ -0,0 +1,122 @@
+// Package bench is the Go API of kueue-bench. It lets test harnesses create topologies,
+// run workload scenarios against them and read back the results without going through
+// the CLI. Topology and run state is shared with the CLI through the state directory, so
+// objects created here can be inspected and cleaned up with kueue-bench commands and vice
+// versa; a Harness keeps them in a directory of its own.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/run"
+	"github.com/jhwagner/kueue-bench/pkg/topology"
+)
+
+// CreateTopology validates cfg and creates the topology named by cfg.Metadata.Name:
+// kind clusters, Kwok, Kueue and the configured Kueue objects
+func (h *Harness) CreateTopology(ctx context.Context, cfg *config.Topology) (*topology.Topology, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("topology configuration is required")
+	}
+	if cfg.Metadata.Name == "" {
+		return nil, fmt.Errorf("topology name must be set in metadata.name")
+	}
+	if err := config.ValidateTopology(cfg); err != nil {
+		return nil, fmt.Errorf("topology validation failed: %w", err)
+	}
+
+	topo, err := h.topologies.Create(ctx, cfg.Metadata.Name, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create topology: %w", err)
+	}
+	return topo, nil
+}
+
+// DeleteTopology deletes a topology's clusters and its saved metadata
+func (h *Harness) DeleteTopology(ctx context.Context, name string) error {
+	topo, err := h.topologies.Load(name)
+	if err != nil {
+		return fmt.Errorf("failed to load topology: %w", err)
+	}
+	if err := topo.Delete(ctx); err != nil {
+		return fmt.Errorf("failed to delete topology: %w", err)
+	}
+	return nil
+}
+
+// ResolveCluster returns the metadata of a cluster within a topology.
+// If clusterName is empty, the target is inferred:
+//  1. A cluster named after the topology (MultiKueue management cluster) is preferred.
+//  2. If no such cluster exists but the topology has exactly one cluster, that cluster is used.
+//  3. Otherwise the cluster must be specified explicitly.
+func (h *Harness) ResolveCluster(topologyName, clusterName string) (topology.Cluster, error) {
+	topo, err := h.topologies.Load(topologyName)
+	if err != nil {
+		return topology.Cluster{}, fmt.Errorf("failed to load topology %q: %w", topologyName, err)
+	}
+
+	meta := topo.GetMetadata()
+
+	if clusterName == "" {
+		if _, ok := meta.Clusters[topologyName]; ok {
+			// MultiKueue topology: management cluster is named after the topology.
+			clusterName = topologyName
+		} else if len(meta.Clusters) == 1 {
+			// Single-cluster topology: only one choice.
+			for name := range meta.Clusters {
+				clusterName = name
+			}
+		} else {
+			return topology.Cluster{}, fmt.Errorf("topology %q has multiple clusters; specify one of: %v",
+				topologyName, clusterNames(meta.Clusters))
+		}
+	}
+
+	cluster, ok := meta.Clusters[clusterName]
+	if !ok {
+		return topology.Cluster{}, fmt.Errorf("cluster %q not found in topology %q (available: %v)",
+			clusterName, topologyName, clusterNames(meta.Clusters))
+	}
+	return cluster, nil
+}
+
+// clusterNames returns the sorted cluster name list for error messages.
+func clusterNames(clusters map[string]topology.Cluster) []string {
+	names := make([]string, 0, len(clusters))
+	for name := range clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Results are the saved outcome of a scenario run
+type Results struct {
+	// Run is the run metadata, including the latency summary
+	Run *run.RunMetadata
+	// Timelines are the recorded per-workload timelines; nil if none were recorded
+	Timelines []run.WorkloadTimeline
+}
+
+// CollectResults loads the metadata and workload timelines saved for a run.
+// Runs without recorded timelines (e.g. dry runs) return nil Timelines.
+func (h *Harness) CollectResults(runID string) (*Results, error) {
+	meta, err := h.runs.Load(runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load run %q: %w", runID, err)
+	}
+
+	results := &Results{Run: meta}
+	if meta.DryRun || meta.Latency == nil {
+		return results, nil
+	}
+	timelines, err := h.runs.LoadTimelines(runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load timelines for run %q: %w", runID, err)
+	}
+	results.Timelines = timelines
+	return results, nil
+}
@@ -0,0 +1,144 @@
+package bench
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/run"
+	"github.com/jhwagner/kueue-bench/pkg/state"
+	"github.com/jhwagner/kueue-bench/pkg/topology"
+)
+
+// openHarness returns a Harness over a temporary state directory
+func openHarness(t *testing.T) *Harness {
+	t.Helper()
+	h, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	return h
+}
+
+// saveTopology writes topology metadata with the named clusters to the harness's state
+func saveTopology(t *testing.T, h *Harness, name string, clusters ...string) {
+	t.Helper()
+	meta := topology.Metadata{Name: name, Clusters: map[string]topology.Cluster{}}
+	for _, c := range clusters {
+		meta.Clusters[c] = topology.Cluster{Name: c, KubeconfigPath: "/kubeconfigs/" + c}
+	}
+	if err := h.state.Save(state.Topologies, name, meta); err != nil {
+		t.Fatalf("failed to save topology: %v", err)
+	}
+}
+
+func TestResolveCluster(t *testing.T) {
+	h := openHarness(t)
+	saveTopology(t, h, "single", "workers")
+	saveTopology(t, h, "mk", "mk", "worker-1", "worker-2")
+	saveTopology(t, h, "multi", "a", "b")
+
+	tests := []struct {
+		name        string
+		topology    string
+		cluster     string
+		want        string
+		wantErr     bool
+		errContains string
+	}{
+		{name: "only cluster inferred", topology: "single", want: "workers"},
+		{name: "management cluster inferred", topology: "mk", want: "mk"},
+		{name: "explicit cluster", topology: "mk", cluster: "worker-2", want: "worker-2"},
+		{name: "ambiguous", topology: "multi", wantErr: true, errContains: "specify one of: [a b]"},
+		{name: "unknown cluster", topology: "multi", cluster: "c", wantErr: true, errContains: `cluster "c" not found`},
+		{name: "unknown topology", topology: "missing", wantErr: true, errContains: `failed to load topology "missing"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := h.ResolveCluster(tt.topology, tt.cluster)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveCluster() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("ResolveCluster() error = %v, want containing %q", err, tt.errContains)
+				}
+				return
+			}
+			if got.Name != tt.want || got.KubeconfigPath != "/kubeconfigs/"+tt.want {
+				t.Errorf("ResolveCluster() = %+v, want cluster %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectResults(t *testing.T) {
+	h := openHarness(t)
+
+	admitted := time.Date(2026, 3, 28, 12, 0, 1, 0, time.UTC)
+	timelines := []run.WorkloadTimeline{{Name: "job-1", SubmittedAt: admitted.Add(-time.Second), AdmittedAt: &admitted}}
+	summary := run.Summarize(timelines)
+	for _, meta := range []*run.RunMetadata{
+		{RunID: "recorded", WorkloadCount: 1, Latency: &summary},
+		{RunID: "dryrun", DryRun: true, WorkloadCount: 3},
+	} {
+		if err := h.runs.Save(meta); err != nil {
+			t.Fatalf("run.Save() error: %v", err)
+		}
+	}
+	if err := h.runs.SaveTimelines("recorded", timelines); err != nil {
+		t.Fatalf("run.SaveTimelines() error: %v", err)
+	}
+
+	results, err := h.CollectResults("recorded")
+	if err != nil {
+		t.Fatalf("CollectResults() error: %v", err)
+	}
+	if results.Run.WorkloadCount != 1 || len(results.Timelines) != 1 || results.Timelines[0].Name != "job-1" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	results, err = h.CollectResults("dryrun")
+	if err != nil {
+		t.Fatalf("CollectResults() error: %v", err)
+	}
+	if !results.Run.DryRun || results.Timelines != nil {
+		t.Errorf("unexpected dry-run results: %+v", results)
+	}
+
+	if _, err := h.CollectResults("missing"); err == nil {
+		t.Error("CollectResults() expected error for unknown run")
+	}
+}
+
+func TestInvalidOptions(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := CreateTopology(ctx, &config.Topology{}); err == nil || !strings.Contains(err.Error(), "metadata.name") {
+		t.Errorf("CreateTopology() error = %v, want missing name error", err)
+	}
+	if _, err := RunScenario(ctx, ScenarioOptions{Topology: "t"}); err == nil || !strings.Contains(err.Error(), "profile is required") {
+		t.Errorf("RunScenario() error = %v, want missing profile error", err)
+	}
+}
+
+func TestHarnessStateIsolation(t *testing.T) {
+	a, b := openHarness(t), openHarness(t)
+	saveTopology(t, a, "bench", "bench")
+	if err := a.runs.Save(&run.RunMetadata{RunID: "run-a"}); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	if _, err := a.ResolveCluster("bench", ""); err != nil {
+		t.Errorf("ResolveCluster() in the topology's harness error: %v", err)
+	}
+	if _, err := b.ResolveCluster("bench", ""); err == nil {
+		t.Error("ResolveCluster() found a topology of another harness")
+	}
+	if runs, err := b.Runs().List(); err != nil || len(runs) != 0 {
+		t.Errorf("Runs().List() = %v, %v; want no runs of another harness", runs, err)
+	}
+}
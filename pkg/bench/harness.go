@@ -0,0 +1,136 @@
+package bench
+
+import (
+	"context"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/run"
+	"github.com/jhwagner/kueue-bench/pkg/state"
+	"github.com/jhwagner/kueue-bench/pkg/topology"
+)
+
+// Harness runs the package's operations against the topologies, runs and scenario queues
+// of one state directory. Harnesses with different directories can be used side by side,
+// e.g. one per test. The package-level functions use the default state directory.
+type Harness struct {
+	state      *state.Store
+	topologies *topology.Store
+	runs       *run.Store
+}
+
+// Open returns a Harness keeping its state in dir, e.g. a per-job temporary directory in CI
+func Open(dir string) (*Harness, error) {
+	s, err := state.OpenAt(dir)
+	if err != nil {
+		return nil, err
+	}
+	return newHarness(s), nil
+}
+
+// OpenDefault returns the Harness of the default state directory: $KUEUE_BENCH_STATE_DIR
+// or the CLI's default (see state.Root)
+func OpenDefault() (*Harness, error) {
+	s, err := state.Open()
+	if err != nil {
+		return nil, err
+	}
+	return newHarness(s), nil
+}
+
+func newHarness(s *state.Store) *Harness {
+	return &Harness{state: s, topologies: topology.NewStore(s), runs: run.NewStore(s)}
+}
+
+// Topologies returns the store of the harness's topologies
+func (h *Harness) Topologies() *topology.Store {
+	return h.topologies
+}
+
+// Runs returns the store of the harness's runs and batch reports
+func (h *Harness) Runs() *run.Store {
+	return h.runs
+}
+
+// CreateTopology creates a topology in the default state directory; see Harness.CreateTopology
+func CreateTopology(ctx context.Context, cfg *config.Topology) (*topology.Topology, error) {
+	h, err := OpenDefault()
+	if err != nil {
+		return nil, err
+	}
+	return h.CreateTopology(ctx, cfg)
+}
+
+// DeleteTopology deletes a topology in the default state directory; see Harness.DeleteTopology
+func DeleteTopology(ctx context.Context, name string) error {
+	h, err := OpenDefault()
+	if err != nil {
+		return err
+	}
+	return h.DeleteTopology(ctx, name)
+}
+
+// ResolveCluster resolves a cluster of a topology in the default state directory; see
+// Harness.ResolveCluster
+func ResolveCluster(topologyName, clusterName string) (topology.Cluster, error) {
+	h, err := OpenDefault()
+	if err != nil {
+		return topology.Cluster{}, err
+	}
+	return h.ResolveCluster(topologyName, clusterName)
+}
+
+// CollectResults loads a run from the default state directory; see Harness.CollectResults
+func CollectResults(runID string) (*Results, error) {
+	h, err := OpenDefault()
+	if err != nil {
+		return nil, err
+	}
+	return h.CollectResults(runID)
+}
+
+// RunScenario runs a scenario against a topology in the default state directory; see
+// Harness.RunScenario
+func RunScenario(ctx context.Context, opts ScenarioOptions) (*run.RunMetadata, error) {
+	h, err := OpenDefault()
+	if err != nil {
+		return nil, err
+	}
+	return h.RunScenario(ctx, opts)
+}
+
+// LoadQueue returns a topology's queue in the default state directory; see Harness.LoadQueue
+func LoadQueue(topologyName string) (*ScenarioQueue, error) {
+	h, err := OpenDefault()
+	if err != nil {
+		return nil, err
+	}
+	return h.LoadQueue(topologyName)
+}
+
+// EnqueueScenario queues a scenario in the default state directory; see
+// Harness.EnqueueScenario
+func EnqueueScenario(topologyName string, scenario QueuedScenario) (*ScenarioQueue, error) {
+	h, err := OpenDefault()
+	if err != nil {
+		return nil, err
+	}
+	return h.EnqueueScenario(topologyName, scenario)
+}
+
+// ClearQueue clears a topology's queue in the default state directory; see Harness.ClearQueue
+func ClearQueue(topologyName string) error {
+	h, err := OpenDefault()
+	if err != nil {
+		return err
+	}
+	return h.ClearQueue(topologyName)
+}
+
+// RunQueue runs a topology's queue in the default state directory; see Harness.RunQueue
+func RunQueue(ctx context.Context, topologyName string, opts QueueOptions) (*run.BatchReport, error) {
+	h, err := OpenDefault()
+	if err != nil {
+		return nil, err
+	}
+	return h.RunQueue(ctx, topologyName, opts)
+}
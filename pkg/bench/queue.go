@@ -0,0 +1,219 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/kueue"
+	"github.com/jhwagner/kueue-bench/pkg/run"
+	"github.com/jhwagner/kueue-bench/pkg/state"
+	"github.com/jhwagner/kueue-bench/pkg/workload"
+)
+
+// QueuedScenario is a scenario waiting in a topology's queue
+type QueuedScenario struct {
+	// ProfilePath is the absolute path of the workload profile, loaded when the scenario starts
+	ProfilePath string `json:"profilePath"`
+	// Cluster is the cluster within the topology; empty resolves it as ResolveCluster does
+	Cluster      string        `json:"cluster,omitempty"`
+	TimelineWait time.Duration `json:"timelineWait,omitempty"`
+	// SubmitWorkers is how many workloads may be submitted concurrently; 0 means 1
+	SubmitWorkers int       `json:"submitWorkers,omitempty"`
+	AddedAt       time.Time `json:"addedAt"`
+}
+
+// ScenarioQueue is the ordered list of scenarios queued against a topology
+type ScenarioQueue struct {
+	Topology  string           `json:"topology"`
+	Scenarios []QueuedScenario `json:"scenarios"`
+}
+
+// LoadQueue returns the scenarios queued against a topology; empty if none are queued
+func (h *Harness) LoadQueue(topologyName string) (*ScenarioQueue, error) {
+	queue := &ScenarioQueue{Topology: topologyName}
+	if err := h.state.Load(state.Queues, topologyName, queue); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return queue, nil
+		}
+		return nil, fmt.Errorf("failed to load scenario queue: %w", err)
+	}
+	return queue, nil
+}
+
+// saveQueue persists a queue, removing it once it is empty
+func (h *Harness) saveQueue(queue *ScenarioQueue) error {
+	if len(queue.Scenarios) == 0 {
+		return h.state.Remove(state.Queues, queue.Topology)
+	}
+	return h.state.Save(state.Queues, queue.Topology, queue)
+}
+
+// EnqueueScenario appends a scenario to a topology's queue. The topology, cluster and
+// profile are validated now so a batch does not fail hours in on a typo.
+func (h *Harness) EnqueueScenario(topologyName string, scenario QueuedScenario) (*ScenarioQueue, error) {
+	if _, err := h.ResolveCluster(topologyName, scenario.Cluster); err != nil {
+		return nil, err
+	}
+	profile, err := config.LoadWorkloadProfile(scenario.ProfilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load workload profile: %w", err)
+	}
+	if err := config.ValidateWorkloadProfile(profile); err != nil {
+		return nil, fmt.Errorf("invalid workload profile: %w", err)
+	}
+	if scenario.ProfilePath, err = filepath.Abs(scenario.ProfilePath); err != nil {
+		return nil, fmt.Errorf("failed to resolve profile path: %w", err)
+	}
+	if scenario.AddedAt.IsZero() {
+		scenario.AddedAt = time.Now()
+	}
+
+	queue, err := h.LoadQueue(topologyName)
+	if err != nil {
+		return nil, err
+	}
+	queue.Scenarios = append(queue.Scenarios, scenario)
+	if err := h.saveQueue(queue); err != nil {
+		return nil, fmt.Errorf("failed to save scenario queue: %w", err)
+	}
+	return queue, nil
+}
+
+// ClearQueue removes every scenario queued against a topology
+func (h *Harness) ClearQueue(topologyName string) error {
+	return h.saveQueue(&ScenarioQueue{Topology: topologyName})
+}
+
+// QueueOptions configure a RunQueue call
+type QueueOptions struct {
+	// ResetTimeout bounds how long the reset after each scenario waits for ClusterQueues
+	// to drain; kueue.DefaultIdleTimeout if zero
+	ResetTimeout time.Duration
+	// OnScenario is called before each scenario starts; optional
+	OnScenario func(index, total int, scenario QueuedScenario)
+	// OnSubmit is called for each workload as it is submitted; optional
+	OnSubmit func(name, workloadType, namespace string)
+}
+
+// RunQueue runs the scenarios queued against a topology one after another, deleting their
+// workloads and waiting for the ClusterQueues to drain between them so every scenario
+// starts from an idle cluster. A failing scenario is recorded and the batch moves on; a
+// failing reset stops the batch, as later results would be skewed. Finished scenarios are
+// removed from the queue as they complete, so an interrupted batch resumes where it
+// stopped. The consolidated report is saved and can be read back with run.Store.LoadBatch.
+func (h *Harness) RunQueue(ctx context.Context, topologyName string, opts QueueOptions) (*run.BatchReport, error) {
+	queue, err := h.LoadQueue(topologyName)
+	if err != nil {
+		return nil, err
+	}
+	if len(queue.Scenarios) == 0 {
+		return nil, fmt.Errorf("no scenarios queued for topology %q", topologyName)
+	}
+	resetTimeout := opts.ResetTimeout
+	if resetTimeout == 0 {
+		resetTimeout = kueue.DefaultIdleTimeout
+	}
+
+	report := &run.BatchReport{ID: NewRunID(), Topology: topologyName, StartedAt: time.Now()}
+	total := len(queue.Scenarios)
+	var batchErr error
+	for i := 0; len(queue.Scenarios) > 0; i++ {
+		scenario := queue.Scenarios[0]
+		if opts.OnScenario != nil {
+			opts.OnScenario(i, total, scenario)
+		}
+
+		result := h.runQueuedScenario(ctx, topologyName, scenario, opts.OnSubmit)
+		if ctx.Err() != nil {
+			// Leave the interrupted scenario queued so the next start reruns it
+			batchErr = ctx.Err()
+			break
+		}
+		if err := h.resetCluster(ctx, topologyName, scenario.Cluster, resetTimeout); err != nil {
+			if result.Error != "" {
+				result.Error += "; "
+			}
+			result.Error += err.Error()
+			batchErr = fmt.Errorf("stopping batch: %w", err)
+		}
+		report.Results = append(report.Results, result)
+
+		queue.Scenarios = queue.Scenarios[1:]
+		if err := h.saveQueue(queue); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save scenario queue: %v\n", err)
+		}
+		if batchErr != nil {
+			break
+		}
+	}
+
+	report.FinishedAt = time.Now()
+	if len(report.Results) > 0 {
+		if err := h.runs.SaveBatch(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save batch report: %v\n", err)
+		}
+	}
+	return report, batchErr
+}
+
+// runQueuedScenario runs one queued scenario and summarizes its outcome
+func (h *Harness) runQueuedScenario(ctx context.Context, topologyName string, scenario QueuedScenario, onSubmit func(name, workloadType, namespace string)) run.BatchResult {
+	result := run.BatchResult{ProfilePath: scenario.ProfilePath}
+	profile, err := config.LoadWorkloadProfile(scenario.ProfilePath)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to load workload profile: %v", err)
+		return result
+	}
+	result.ProfileName = profile.Metadata.Name
+
+	meta, err := h.RunScenario(ctx, ScenarioOptions{
+		Profile:       profile,
+		ProfilePath:   scenario.ProfilePath,
+		Topology:      topologyName,
+		Cluster:       scenario.Cluster,
+		TimelineWait:  scenario.TimelineWait,
+		SubmitWorkers: scenario.SubmitWorkers,
+		OnSubmit:      onSubmit,
+	})
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.RunID = meta.RunID
+	result.WorkloadCount = meta.WorkloadCount
+	result.Duration = meta.Duration
+	result.Latency = meta.Latency
+	return result
+}
+
+// resetCluster deletes every workload kueue-bench submitted to a cluster and waits for
+// its ClusterQueues to drain
+func (h *Harness) resetCluster(ctx context.Context, topologyName, clusterName string, timeout time.Duration) error {
+	target, err := h.ResolveCluster(topologyName, clusterName)
+	if err != nil {
+		return err
+	}
+	workloads, err := workload.NewWorkloadClient(target.KubeconfigPath, target.ClientOption())
+	if err != nil {
+		return err
+	}
+	deleted, err := workloads.DeleteRun(ctx, topologyName, "")
+	if err != nil {
+		return fmt.Errorf("failed to delete workloads: %w", err)
+	}
+	fmt.Printf("Reset: deleted %d workloads from cluster '%s'\n", deleted, target.Name)
+
+	client, err := kueue.NewClient(target.KubeconfigPath, target.ClientOption())
+	if err != nil {
+		return fmt.Errorf("failed to create Kueue client: %w", err)
+	}
+	if err := client.WaitForIdle(ctx, timeout); err != nil {
+		return fmt.Errorf("cluster '%s' did not become idle: %w", target.Name, err)
+	}
+	return nil
+}
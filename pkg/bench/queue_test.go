@@ -0,0 +1,68 @@
+package bench
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScenarioQueue(t *testing.T) {
+	h := openHarness(t)
+	saveTopology(t, h, "bench", "bench")
+	profile := filepath.Join("..", "..", "examples", "workloads", "basic-queue.yaml")
+
+	queue, err := h.LoadQueue("bench")
+	if err != nil {
+		t.Fatalf("LoadQueue() error: %v", err)
+	}
+	if len(queue.Scenarios) != 0 {
+		t.Fatalf("LoadQueue() of an empty queue = %+v", queue)
+	}
+
+	for _, wait := range []time.Duration{0, time.Minute} {
+		if _, err := h.EnqueueScenario("bench", QueuedScenario{ProfilePath: profile, TimelineWait: wait, SubmitWorkers: 4}); err != nil {
+			t.Fatalf("EnqueueScenario() error: %v", err)
+		}
+	}
+	queue, err = h.LoadQueue("bench")
+	if err != nil {
+		t.Fatalf("LoadQueue() error: %v", err)
+	}
+	if len(queue.Scenarios) != 2 || queue.Scenarios[1].TimelineWait != time.Minute || queue.Scenarios[1].SubmitWorkers != 4 {
+		t.Fatalf("queued scenarios = %+v, want two in order", queue.Scenarios)
+	}
+	if s := queue.Scenarios[0]; !filepath.IsAbs(s.ProfilePath) || s.AddedAt.IsZero() {
+		t.Errorf("queued scenario = %+v, want an absolute profile path and AddedAt", s)
+	}
+
+	tests := []struct {
+		name        string
+		topology    string
+		scenario    QueuedScenario
+		errContains string
+	}{
+		{name: "unknown topology", topology: "missing", scenario: QueuedScenario{ProfilePath: profile}, errContains: "failed to load topology"},
+		{name: "unknown cluster", topology: "bench", scenario: QueuedScenario{ProfilePath: profile, Cluster: "other"}, errContains: `cluster "other" not found`},
+		{name: "missing profile", topology: "bench", scenario: QueuedScenario{ProfilePath: "missing.yaml"}, errContains: "failed to load workload profile"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h.EnqueueScenario(tt.topology, tt.scenario)
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("EnqueueScenario() error = %v, want %q", err, tt.errContains)
+			}
+		})
+	}
+
+	if err := h.ClearQueue("bench"); err != nil {
+		t.Fatalf("ClearQueue() error: %v", err)
+	}
+	if queue, _ = h.LoadQueue("bench"); len(queue.Scenarios) != 0 {
+		t.Errorf("scenarios after ClearQueue() = %+v", queue.Scenarios)
+	}
+	if _, err := h.RunQueue(context.Background(), "bench", QueueOptions{}); err == nil || !strings.Contains(err.Error(), "no scenarios queued") {
+		t.Errorf("RunQueue() of an empty queue error = %v", err)
+	}
+}
@@ -0,0 +1,525 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/chaos"
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/kueue"
+	"github.com/jhwagner/kueue-bench/pkg/kwok"
+	"github.com/jhwagner/kueue-bench/pkg/run"
+	"github.com/jhwagner/kueue-bench/pkg/topology"
+	"github.com/jhwagner/kueue-bench/pkg/workload"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// DefaultTimelineWait is how long the CLI and server keep recording workload timelines
+// after submission ends by default. Without it, workloads submitted near the end of a run
+// are saved before they are admitted.
+const DefaultTimelineWait = 2 * time.Minute
+
+// ScenarioOptions configure a RunScenario call
+type ScenarioOptions struct {
+	// Profile is the workload profile to submit (required)
+	Profile *config.WorkloadProfile
+	// ProfilePath is recorded in the run metadata; optional
+	ProfilePath string
+	// Topology is the topology to submit to (required unless DryRun)
+	Topology string
+	// Cluster is the cluster within the topology; empty resolves it as ResolveCluster does
+	Cluster string
+	// RunID identifies the run; a random ID is generated if empty
+	RunID string
+	// DryRun builds workloads without submitting them
+	DryRun bool
+	// TimelineWait keeps recording workload timelines for this long after submission ends,
+	// so workloads still queued are admitted and run; see DefaultTimelineWait
+	TimelineWait time.Duration
+	// OnSubmit is called for each workload as it is submitted; optional
+	OnSubmit func(name, workloadType, namespace string)
+	// SubmitWorkers is how many workloads may be submitted concurrently; 0 means 1
+	SubmitWorkers int
+}
+
+// RunScenario submits the workloads of a profile to a topology cluster, running the
+// profile's chaos alongside, and saves the run metadata and workload timelines. The run
+// can be read back later with CollectResults. Failing to record or save timelines or
+// metadata is reported as a warning rather than failing the run.
+func (h *Harness) RunScenario(ctx context.Context, opts ScenarioOptions) (*run.RunMetadata, error) {
+	profile := opts.Profile
+	if profile == nil {
+		return nil, fmt.Errorf("workload profile is required")
+	}
+	if err := config.ValidateWorkloadProfile(profile); err != nil {
+		return nil, fmt.Errorf("invalid workload profile: %w", err)
+	}
+
+	// Resolve the target cluster from topology metadata
+	var target topology.Cluster
+	if !opts.DryRun {
+		if opts.Topology == "" {
+			return nil, fmt.Errorf("topology is required when not using dry run")
+		}
+		var err error
+		target, err = h.ResolveCluster(opts.Topology, opts.Cluster)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateSpreadQueues(profile, target); err != nil {
+			return nil, err
+		}
+	}
+	kubeconfigPath := target.KubeconfigPath
+
+	// Record the Kueue CRD versions the run executes against
+	var crds []kueue.CRDVersion
+	if !opts.DryRun {
+		var err error
+		crds, err = kueueCRDVersions(ctx, target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Kueue CRD versions will not be recorded: %v\n", err)
+		}
+	}
+
+	runID := opts.RunID
+	if runID == "" {
+		runID = NewRunID()
+	}
+	startedAt := time.Now()
+	usage := run.StartUsage()
+
+	// Record pod and admission timestamps so latency includes the kube-scheduler leg
+	var recorder *workload.TimelineRecorder
+	if !opts.DryRun {
+		var err error
+		recorder, err = startTimelineRecorder(ctx, target, runID, profile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: workload timelines will not be recorded: %v\n", err)
+		} else {
+			defer recorder.Close()
+			defer recorder.Stop()
+		}
+	}
+
+	// Price ClusterQueue capacity over the run when node pools carry a cost
+	var costs *costSampler
+	if !opts.DryRun {
+		var err error
+		costs, err = startCostSampler(ctx, target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: run cost will not be recorded: %v\n", err)
+		}
+	}
+
+	engineOpts := []workload.EngineOption{
+		workload.WithOnSubmit(func(name, workloadType, namespace string) {
+			if opts.OnSubmit != nil {
+				opts.OnSubmit(name, workloadType, namespace)
+			}
+			if recorder != nil {
+				recorder.Submitted(name, workloadType, namespace)
+			}
+		}),
+	}
+	engineOpts = append(engineOpts, workload.WithClientOptions(target.ClientOption()))
+	submitWorkers := max(opts.SubmitWorkers, 1)
+	engineOpts = append(engineOpts, workload.WithSubmitWorkers(submitWorkers))
+	if opts.DryRun {
+		engineOpts = append(engineOpts, workload.WithDryRun())
+	} else {
+		engineOpts = append(engineOpts, workload.WithTopology(opts.Topology))
+	}
+
+	// Bind pods directly to Kwok nodes when the cluster bypasses kube-scheduler
+	var binder *kwok.Binder
+	if target.Scheduler == config.SchedulerBypass {
+		var err error
+		binder, err = kwok.NewBinder(kubeconfigPath, target.ClientOption())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create pod binder: %w", err)
+		}
+		binderCtx, stopBinder := context.WithCancel(ctx)
+		defer stopBinder()
+		go func() {
+			if err := binder.Run(binderCtx); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: pod binder stopped: %v\n", err)
+			}
+		}()
+		engineOpts = append(engineOpts, workload.WithSchedulerName(kwok.BypassSchedulerName))
+		fmt.Println("Scheduler bypass enabled: pods are bound directly to Kwok nodes")
+	}
+
+	engine, err := workload.NewEngine(profile, kubeconfigPath, runID, engineOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create engine: %w", err)
+	}
+
+	fmt.Printf("Submitting workloads from profile %q (run ID: %s, seed: %d)\n",
+		profile.Metadata.Name, runID, engine.EffectiveSeed())
+	if opts.DryRun {
+		fmt.Println("(dry-run mode: workloads will not be submitted)")
+	}
+
+	// Run chaos (node churn and faults) alongside workload generation when the profile enables it
+	runCtx, stopChaos := context.WithCancel(ctx)
+	defer stopChaos()
+	var chaosDone <-chan error
+	if profile.Spec.Chaos != nil && !opts.DryRun {
+		chaosDone, err = startChaos(runCtx, opts.Topology, target, profile.Spec.Chaos, engine.EffectiveSeed())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Lose workers on the same clock, but keep measuring until timelines stop recording
+	lossCtx, stopLoss := context.WithCancel(ctx)
+	defer stopLoss()
+	var workerLoss *chaos.WorkerLossMonitor
+	var lossDone chan error
+	if profile.Spec.Chaos != nil && len(profile.Spec.Chaos.WorkerLoss) > 0 && !opts.DryRun {
+		workerLoss, err = h.newWorkerLossMonitor(opts.Topology, target, profile.Spec.Chaos.WorkerLoss)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Printf("Worker loss enabled: %d scheduled\n", len(profile.Spec.Chaos.WorkerLoss))
+		lossDone = make(chan error, 1)
+		go func() { lossDone <- workerLoss.Run(lossCtx) }()
+	}
+
+	result, err := engine.Run(runCtx)
+	stopChaos()
+	if chaosDone != nil {
+		if chaosErr := <-chaosDone; chaosErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: chaos injection failed: %v\n", chaosErr)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("workload generation failed: %w", err)
+	}
+
+	elapsed := time.Since(startedAt)
+	fmt.Printf("Workload generation complete: %d workloads in %s (run ID: %s)\n",
+		result.WorkloadCount, elapsed.Round(time.Millisecond), runID)
+
+	meta := &run.RunMetadata{
+		RunID:             runID,
+		ProfileName:       profile.Metadata.Name,
+		ProfilePath:       opts.ProfilePath,
+		TopologyName:      opts.Topology,
+		ClusterName:       opts.Cluster,
+		Seed:              result.EffectiveSeed,
+		DryRun:            opts.DryRun,
+		WorkloadCount:     result.WorkloadCount,
+		StartedAt:         startedAt,
+		Duration:          elapsed.Round(time.Millisecond).String(),
+		KueueFeatureGates: target.KueueFeatureGates,
+		KueueCRDs:         crds,
+	}
+	if binder != nil {
+		// Nothing binds bypass pods once the run returns, so bind those of Workloads
+		// admitted after submission while timelines are still recorded
+		if err := drainBinder(ctx, binder, target, binderDrainTimeout); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: pods admitted from now on will stay Pending: %v\n", err)
+		}
+	}
+	if recorder != nil {
+		meta.Latency = h.finishTimelines(ctx, recorder, runID, opts.TimelineWait)
+	}
+	if costs != nil {
+		meta.Cost = costs.Stop(ctx)
+	}
+	if lossDone != nil {
+		stopLoss()
+		if lossErr := <-lossDone; lossErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: worker loss failed: %v\n", lossErr)
+		}
+		meta.WorkerLoss = workerLoss.Results()
+	}
+	toolUsage := usage.Stop()
+	toolUsage.SubmitWorkers = submitWorkers
+	meta.ToolUsage = &toolUsage
+
+	// Persist run metadata (best-effort)
+	if err := h.runs.Save(meta); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save run metadata: %v\n", err)
+	}
+	return meta, nil
+}
+
+// validateSpreadQueues checks the profile's spread workloads against the Kueue config
+// recorded for the target cluster. Clusters without a recorded config are not checked.
+func validateSpreadQueues(profile *config.WorkloadProfile, target topology.Cluster) error {
+	spread := false
+	for _, w := range profile.Spec.Workloads {
+		spread = spread || w.Spread != nil
+	}
+	if !spread || target.KueueConfigPath == "" {
+		return nil
+	}
+	kueueConfig, err := config.LoadKueueConfig(target.KueueConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load Kueue config of cluster '%s': %w", target.Name, err)
+	}
+	if err := config.ValidateSpreadQueues(profile, kueueConfig); err != nil {
+		return fmt.Errorf("invalid workload profile for cluster '%s': %w", target.Name, err)
+	}
+	return nil
+}
+
+// kueueCRDVersions returns the target cluster's Kueue CRD versions, warning if they
+// changed since the topology recorded them
+func kueueCRDVersions(ctx context.Context, target topology.Cluster) ([]kueue.CRDVersion, error) {
+	client, err := kueue.NewClient(target.KubeconfigPath, target.ClientOption())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kueue client: %w", err)
+	}
+	crds, err := client.CRDVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if changes := kueue.CRDVersionChanges(target.KueueCRDs, crds); len(target.KueueCRDs) > 0 && len(changes) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: Kueue CRDs in cluster '%s' changed since they were recorded:\n", target.Name)
+		for _, change := range changes {
+			fmt.Fprintf(os.Stderr, "  %s\n", change)
+		}
+	}
+	return crds, nil
+}
+
+// maxTimelineNamespaces is the most namespaces the timeline recorder watches one by one;
+// runs submitting to more are watched across all namespaces
+const maxTimelineNamespaces = 10
+
+// startTimelineRecorder starts recording the run's pod and Workload lifecycle timestamps
+func startTimelineRecorder(ctx context.Context, target topology.Cluster, runID string, profile *config.WorkloadProfile) (*workload.TimelineRecorder, error) {
+	scope := workload.TimelineScope{
+		Namespaces:       timelineNamespaces(profile),
+		LabeledWorkloads: target.WorkloadRunLabels,
+	}
+	recorder, err := workload.NewTimelineRecorder(target.KubeconfigPath, runID, scope, target.ClientOption())
+	if err != nil {
+		return nil, err
+	}
+	if err := recorder.Start(ctx); err != nil {
+		recorder.Stop()
+		recorder.Close()
+		return nil, err
+	}
+	return recorder, nil
+}
+
+// timelineNamespaces returns the namespaces a profile submits to, or nil when its workloads
+// spread over generated namespaces or submit to more than maxTimelineNamespaces
+func timelineNamespaces(profile *config.WorkloadProfile) []string {
+	var namespaces []string
+	for _, spec := range profile.Spec.Workloads {
+		if spec.Spread != nil {
+			return nil
+		}
+		ns := spec.Namespace
+		if ns == "" {
+			ns = "default"
+		}
+		if !slices.Contains(namespaces, ns) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	if len(namespaces) > maxTimelineNamespaces {
+		return nil
+	}
+	return namespaces
+}
+
+// finishTimelines waits for in-flight workloads, then stops the recorder, saves the
+// timelines (best-effort) and returns their latency summary
+func (h *Harness) finishTimelines(ctx context.Context, recorder *workload.TimelineRecorder, runID string, wait time.Duration) *run.TimelineSummary {
+	if wait > 0 {
+		fmt.Printf("Recording workload timelines for %s...\n", wait)
+		select {
+		case <-ctx.Done():
+		case <-time.After(wait):
+		}
+	}
+	recorder.Stop()
+
+	timelines := recorder.Timelines()
+	if err := h.runs.SaveTimelines(runID, timelines); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save workload timelines: %v\n", err)
+	}
+
+	summary := run.Summarize(timelines)
+	return &summary
+}
+
+// binderDrainTimeout bounds how long a run keeps binding pods after submission ends
+const binderDrainTimeout = kueue.DefaultIdleTimeout
+
+// drainBinder waits until the cluster has no Workloads pending admission and the binder
+// no pods left to bind, or until timeout
+func drainBinder(ctx context.Context, binder *kwok.Binder, target topology.Cluster, timeout time.Duration) error {
+	client, err := kueue.NewClient(target.KubeconfigPath, target.ClientOption())
+	if err != nil {
+		return fmt.Errorf("failed to create Kueue client: %w", err)
+	}
+	fmt.Println("Binding pods of Workloads still pending admission...")
+	var pending int32
+	var unbound int
+	var lastErr error
+	err = wait.PollUntilContextTimeout(ctx, 2*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		if pending, lastErr = client.PendingWorkloads(ctx); lastErr != nil {
+			return false, nil
+		}
+		if unbound, lastErr = binder.Unbound(); lastErr != nil {
+			return false, nil
+		}
+		return pending == 0 && unbound == 0, nil
+	})
+	switch {
+	case err == nil:
+		return nil
+	case lastErr != nil:
+		return lastErr
+	default:
+		return fmt.Errorf("%d Workloads pending and %d pods unbound after %s", pending, unbound, timeout)
+	}
+}
+
+// costSampleInterval is how often ClusterQueue usage is sampled for the run's cost
+const costSampleInterval = 5 * time.Second
+
+// costSampler samples the cost rates of a cluster's ClusterQueues in the background
+type costSampler struct {
+	client  *kueue.Client
+	flavors map[string]kueue.FlavorCost
+	tracker *run.CostTracker
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// startCostSampler starts sampling ClusterQueue cost rates until Stop. It returns nil if
+// no node of the cluster carries a cost.
+func startCostSampler(ctx context.Context, target topology.Cluster) (*costSampler, error) {
+	client, err := kueue.NewClient(target.KubeconfigPath, target.ClientOption())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kueue client: %w", err)
+	}
+	flavors, err := client.FlavorCosts(ctx)
+	if err != nil || len(flavors) == 0 {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s := &costSampler{client: client, flavors: flavors, tracker: run.NewCostTracker(), cancel: cancel, done: make(chan struct{})}
+	s.sample(ctx)
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(costSampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.sample(ctx)
+			}
+		}
+	}()
+	return s, nil
+}
+
+// sample records the current cost rates; a failed sample keeps the previous rates
+func (s *costSampler) sample(ctx context.Context) {
+	cqs, err := s.client.ListClusterQueues(ctx)
+	if err != nil {
+		return
+	}
+	s.tracker.Sample(time.Now(), kueue.QueueCostRates(cqs, s.flavors))
+}
+
+// Stop takes a final sample and returns the cost summary of the sampled period
+func (s *costSampler) Stop(ctx context.Context) *run.CostSummary {
+	s.cancel()
+	<-s.done
+	s.sample(context.WithoutCancel(ctx))
+	return s.tracker.Summary()
+}
+
+// startChaos runs the profile's node churn and node faults on the topology's nodes in the background until
+// ctx is cancelled. The returned channel receives their combined result once both stop.
+func startChaos(ctx context.Context, topologyName string, target topology.Cluster, spec *config.ChaosSpec, seed int64) (<-chan error, error) {
+	clientset, err := chaos.NewClientset(target.KubeconfigPath, target.ClientOption())
+	if err != nil {
+		return nil, err
+	}
+
+	var runners []func(context.Context) error
+	if spec.NodeChurn != nil {
+		churner, err := chaos.NewNodeChurner(clientset, topologyName, spec.NodeChurn, seed)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Printf("Node churn enabled: %.0f%% of nodes every %s\n", spec.NodeChurn.Fraction*100, spec.NodeChurn.Interval)
+		runners = append(runners, churner.Run)
+	}
+	if len(spec.NodeFaults) > 0 {
+		injector, err := chaos.NewFaultInjector(clientset, topologyName, spec.NodeFaults, seed)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Printf("Node faults enabled: %d scheduled\n", len(spec.NodeFaults))
+		runners = append(runners, injector.Run)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		errs := make(chan error, len(runners))
+		for _, run := range runners {
+			go func() { errs <- run(ctx) }()
+		}
+		var combined error
+		for range runners {
+			combined = errors.Join(combined, <-errs)
+		}
+		done <- combined
+	}()
+	return done, nil
+}
+
+// newWorkerLossMonitor creates a WorkerLossMonitor for workers of the target management
+// cluster, deleting them through the topology
+func (h *Harness) newWorkerLossMonitor(topologyName string, target topology.Cluster, losses []config.WorkerLoss) (*chaos.WorkerLossMonitor, error) {
+	topo, err := h.topologies.Load(topologyName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load topology: %w", err)
+	}
+	clusters := make(map[string][]string, len(losses))
+	for _, l := range losses {
+		if clusters[l.Worker], err = topo.MultiKueueClusterNames(target.Name, l.Worker); err != nil {
+			return nil, err
+		}
+	}
+	client, err := kueue.NewClient(target.KubeconfigPath, target.ClientOption())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kueue client: %w", err)
+	}
+	return chaos.NewWorkerLossMonitor(client, losses, clusters, topo.DeleteWorker)
+}
+
+// NewRunID returns a short random lowercase alphanumeric identifier, as RunScenario
+// generates when ScenarioOptions.RunID is empty. Callers that need the ID before the run
+// starts generate it with NewRunID and pass it in.
+// Uses math/rand directly (not the profile seed) so run IDs are unique across reruns of the same profile.
+func NewRunID() string {
+	const chars = "abcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, 8)
+	for i := range b {
+		b[i] = chars[rand.Intn(len(chars))] //nolint:gosec // run ID is non-security-sensitive
+	}
+	return string(b)
+}
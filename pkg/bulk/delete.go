@@ -0,0 +1,144 @@
+// Package bulk deletes large numbers of Kubernetes objects, such as tens of thousands of
+// simulated nodes or the workloads of a run, without one round trip per object where the
+// API server allows it.
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// deleteWorkers is the number of concurrent deletes when DeleteCollection is unavailable
+	deleteWorkers = 16
+
+	// listPageSize bounds each list request, so very large collections are listed in pages
+	listPageSize = 500
+)
+
+// Delete deletes every object of gvr matching the label selector and returns how many
+// were deleted. Namespaced resources are deleted across all namespaces. Each namespace
+// (or the cluster, for cluster-scoped resources) is cleared with a single DeleteCollection
+// call; where the API server does not support it, objects are deleted individually in
+// parallel. Dependents, such as a Job's pods, are garbage collected in the background.
+func Delete(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, selector string) (int, error) {
+	byNamespace, err := listNames(ctx, client.Resource(gvr), selector)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list %s: %w", gvr.Resource, err)
+	}
+
+	namespaces := make([]string, 0, len(byNamespace))
+	for ns := range byNamespace {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	background := metav1.DeletePropagationBackground
+	opts := metav1.DeleteOptions{PropagationPolicy: &background}
+	deleted := 0
+	for _, ns := range namespaces {
+		resource := namespaced(client.Resource(gvr), ns)
+		err := resource.DeleteCollection(ctx, opts, metav1.ListOptions{LabelSelector: selector})
+		if apierrors.IsMethodNotSupported(err) {
+			err = deleteEach(ctx, resource, byNamespace[ns], opts)
+		}
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete %s: %w", gvr.Resource, err)
+		}
+		deleted += len(byNamespace[ns])
+	}
+	return deleted, nil
+}
+
+// DeleteNamed deletes the named objects of resource in parallel and returns how many were
+// deleted. Objects already gone are not an error.
+func DeleteNamed(ctx context.Context, resource dynamic.ResourceInterface, names []string) (int, error) {
+	background := metav1.DeletePropagationBackground
+	if err := deleteEach(ctx, resource, names, metav1.DeleteOptions{PropagationPolicy: &background}); err != nil {
+		return 0, err
+	}
+	return len(names), nil
+}
+
+// listNames lists the names of the objects matching selector, grouped by namespace ("" for
+// cluster-scoped objects)
+func listNames(ctx context.Context, resource dynamic.NamespaceableResourceInterface, selector string) (map[string][]string, error) {
+	byNamespace := make(map[string][]string)
+	opts := metav1.ListOptions{LabelSelector: selector, Limit: listPageSize}
+	for {
+		list, err := resource.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			byNamespace[item.GetNamespace()] = append(byNamespace[item.GetNamespace()], item.GetName())
+		}
+		if opts.Continue = list.GetContinue(); opts.Continue == "" {
+			return byNamespace, nil
+		}
+	}
+}
+
+// namespaced returns the resource in namespace, or the cluster-scoped resource for ""
+func namespaced(resource dynamic.NamespaceableResourceInterface, namespace string) dynamic.ResourceInterface {
+	if namespace == "" {
+		return resource
+	}
+	return resource.Namespace(namespace)
+}
+
+// deleteEach deletes the named objects with a pool of workers. Objects already gone are
+// not an error.
+func deleteEach(ctx context.Context, resource dynamic.ResourceInterface, names []string, opts metav1.DeleteOptions) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	queue := make(chan string)
+	go func() {
+		defer close(queue)
+		for _, name := range names {
+			select {
+			case queue <- name:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		combined error
+	)
+	for range min(deleteWorkers, len(names)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range queue {
+				err := resource.Delete(ctx, name, opts)
+				// Deletes interrupted by an earlier failure are not reported again
+				if err == nil || apierrors.IsNotFound(err) || errors.Is(err, context.Canceled) {
+					continue
+				}
+				mu.Lock()
+				combined = errors.Join(combined, fmt.Errorf("%s: %w", name, err))
+				mu.Unlock()
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	if combined == nil {
+		// Set only if the caller's context ended
+		return ctx.Err()
+	}
+	return combined
+}
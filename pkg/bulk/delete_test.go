@@ -0,0 +1,100 @@
+package bulk
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var jobGVR = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+
+func job(namespace, name, runID string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("batch/v1")
+	obj.SetKind("Job")
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(map[string]string{"kueue-bench.io/run-id": runID})
+	return obj
+}
+
+func newClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{jobGVR: "JobList"}, objects...)
+}
+
+func TestDeleteCollectionPerNamespace(t *testing.T) {
+	client := newClient(job("team-a", "a1", "run1"), job("team-a", "a2", "run1"), job("team-b", "b1", "run1"), job("team-b", "b2", "run2"))
+
+	deleted, err := Delete(context.TODO(), client, jobGVR, "kueue-bench.io/run-id=run1")
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("Delete() = %d, want 3", deleted)
+	}
+
+	var namespaces []string
+	for _, action := range client.Actions() {
+		if action.GetVerb() != "delete-collection" {
+			continue
+		}
+		namespaces = append(namespaces, action.GetNamespace())
+		selector := action.(k8stesting.DeleteCollectionAction).GetListRestrictions().Labels.String()
+		if selector != "kueue-bench.io/run-id=run1" {
+			t.Errorf("DeleteCollection selector = %q, want %q", selector, "kueue-bench.io/run-id=run1")
+		}
+	}
+	if want := []string{"team-a", "team-b"}; !slices.Equal(namespaces, want) {
+		t.Errorf("DeleteCollection namespaces = %v, want %v", namespaces, want)
+	}
+}
+
+func TestDeleteFallsBackToParallelDeletes(t *testing.T) {
+	client := newClient(job("team-a", "a1", "run1"), job("team-a", "a2", "run1"), job("team-a", "a3", "run2"))
+	client.PrependReactor("delete-collection", "jobs", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewMethodNotSupported(jobGVR.GroupResource(), "deletecollection")
+	})
+
+	deleted, err := Delete(context.TODO(), client, jobGVR, "kueue-bench.io/run-id=run1")
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("Delete() = %d, want 2", deleted)
+	}
+
+	remaining, err := client.Resource(jobGVR).Namespace("team-a").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, item := range remaining.Items {
+		names = append(names, item.GetName())
+	}
+	if want := []string{"a3"}; !slices.Equal(names, want) {
+		t.Errorf("remaining jobs = %v, want %v", names, want)
+	}
+}
+
+func TestDeleteNothingMatches(t *testing.T) {
+	client := newClient(job("team-a", "a1", "run2"))
+
+	deleted, err := Delete(context.TODO(), client, jobGVR, "kueue-bench.io/run-id=run1")
+	if err != nil || deleted != 0 {
+		t.Errorf("Delete() = (%d, %v), want (0, nil)", deleted, err)
+	}
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "delete-collection" {
+			t.Errorf("Delete() issued DeleteCollection in %q with nothing to delete", action.GetNamespace())
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Package chaos injects disruptions into simulated clusters during benchmark runs.
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/ownership"
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// kwokNodeSelector matches the simulated nodes created from KWOK node pools
+var kwokNodeSelector = labels.Set{"type": "kwok"}
+
+// ownedNodes returns the nodes kueue-bench created for the topology, including
+// Kwok nodes created before kueue-bench labeled them as its own
+func ownedNodes(nodes []corev1.Node, topology string) []corev1.Node {
+	owned := make([]corev1.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if ownership.Owned(node.Labels, topology) || ownership.Legacy(node.Labels) {
+			owned = append(owned, node)
+		}
+	}
+	return owned
+}
+
+// recreateTimeout bounds node recreation after the churner is stopped,
+// so a cancelled run does not leave the cluster short of capacity
+const recreateTimeout = 30 * time.Second
+
+// NodeChurner periodically deletes a random fraction of KWOK nodes and recreates them
+type NodeChurner struct {
+	clientset     kubernetes.Interface
+	topology      string
+	interval      time.Duration
+	fraction      float64
+	recreateAfter time.Duration
+	selector      labels.Selector
+	rng           *rand.Rand
+}
+
+// NewClientset creates a Kubernetes clientset from a kubeconfig path
+func NewClientset(kubeconfigPath string, opts ...restconfig.Option) (kubernetes.Interface, error) {
+	restConfig, err := restconfig.ForKubeconfig(kubeconfigPath, 0, opts...)
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+	return clientset, nil
+}
+
+// NewNodeChurner creates a NodeChurner from validated churn settings. Only the nodes
+// of the named topology are churned; seed makes the choice of churned nodes reproducible.
+func NewNodeChurner(clientset kubernetes.Interface, topology string, churn *config.NodeChurn, seed int64) (*NodeChurner, error) {
+	interval, err := time.ParseDuration(churn.Interval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid interval %q: %w", churn.Interval, err)
+	}
+
+	var recreateAfter time.Duration
+	if churn.RecreateAfter != "" {
+		if recreateAfter, err = time.ParseDuration(churn.RecreateAfter); err != nil {
+			return nil, fmt.Errorf("invalid recreateAfter %q: %w", churn.RecreateAfter, err)
+		}
+	}
+
+	set := labels.Merge(kwokNodeSelector, churn.NodeSelector)
+
+	return &NodeChurner{
+		clientset:     clientset,
+		topology:      topology,
+		interval:      interval,
+		fraction:      churn.Fraction,
+		recreateAfter: recreateAfter,
+		selector:      labels.SelectorFromSet(set),
+		rng:           rand.New(rand.NewSource(seed)), //nolint:gosec // reproducible simulation, not security-sensitive
+	}, nil
+}
+
+// Run churns nodes every interval until the context is cancelled
+func (c *NodeChurner) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		n, err := c.ChurnOnce(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Node churn: recycled %d node(s)\n", n)
+	}
+}
+
+// ChurnOnce deletes a random fraction of the selected nodes, waits recreateAfter,
+// and recreates them. Returns the number of nodes churned.
+func (c *NodeChurner) ChurnOnce(ctx context.Context) (int, error) {
+	nodeList, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: c.selector.String()})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	victims := c.pick(ownedNodes(nodeList.Items, c.topology))
+	if len(victims) == 0 {
+		return 0, nil
+	}
+
+	for i := range victims {
+		err := c.clientset.CoreV1().Nodes().Delete(ctx, victims[i].Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return 0, fmt.Errorf("failed to delete node %s: %w", victims[i].Name, err)
+		}
+	}
+
+	timer := time.NewTimer(c.recreateAfter)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+	case <-timer.C:
+	}
+
+	// Recreate even if the context was cancelled while waiting
+	recreateCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recreateTimeout)
+	defer cancel()
+	for i := range victims {
+		if err := recreateNode(recreateCtx, c.clientset, &victims[i]); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(victims), nil
+}
+
+// pick returns a random subset of nodes of size ceil(fraction * len(nodes))
+func (c *NodeChurner) pick(nodes []corev1.Node) []corev1.Node {
+	return pickNodes(c.rng, nodes, int(math.Ceil(c.fraction*float64(len(nodes)))))
+}
+
+// pickNodes returns count nodes chosen at random (all of them if count >= len(nodes))
+func pickNodes(rng *rand.Rand, nodes []corev1.Node, count int) []corev1.Node {
+	count = min(count, len(nodes))
+	if count <= 0 {
+		return nil
+	}
+
+	picked := make([]corev1.Node, 0, count)
+	for _, i := range rng.Perm(len(nodes))[:count] {
+		picked = append(picked, nodes[i])
+	}
+	return picked
+}
+
+// recreateNode creates a fresh copy of a deleted node. Conditions are dropped so
+// KWOK registers it from scratch, like a node rejoining the cluster.
+func recreateNode(ctx context.Context, clientset kubernetes.Interface, node *corev1.Node) error {
+	fresh := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        node.Name,
+			Labels:      node.Labels,
+			Annotations: node.Annotations,
+		},
+		Spec: corev1.NodeSpec{
+			Taints: userTaints(node.Spec.Taints),
+		},
+		Status: corev1.NodeStatus{
+			Capacity:    node.Status.Capacity,
+			Allocatable: node.Status.Allocatable,
+		},
+	}
+
+	_, err := clientset.CoreV1().Nodes().Create(ctx, fresh, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to recreate node %s: %w", node.Name, err)
+	}
+	return nil
+}
+
+// userTaints drops taints managed by the node lifecycle controller (node.kubernetes.io/*)
+func userTaints(taints []corev1.Taint) []corev1.Taint {
+	var kept []corev1.Taint
+	for _, t := range taints {
+		if !strings.HasPrefix(t.Key, "node.kubernetes.io/") {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,123 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"testing"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/ownership"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func kwokNode(name, pool string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"type": "kwok", "pool": pool},
+		},
+		Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{
+				{Key: "kwok.x-k8s.io/node", Value: "fake", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "node.kubernetes.io/not-ready", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+func TestChurnOnce(t *testing.T) {
+	var objects []runtime.Object
+	for i := 0; i < 10; i++ {
+		objects = append(objects, kwokNode(fmt.Sprintf("cpu-%d", i), "cpu"))
+	}
+	objects = append(objects, kwokNode("gpu-0", "gpu"))
+	clientset := fake.NewClientset(objects...)
+
+	churner, err := NewNodeChurner(clientset, "", &config.NodeChurn{
+		Interval:     "1m",
+		Fraction:     0.25,
+		NodeSelector: map[string]string{"pool": "cpu"},
+	}, 42)
+	if err != nil {
+		t.Fatalf("NewNodeChurner() error: %v", err)
+	}
+
+	n, err := churner.ChurnOnce(context.Background())
+	if err != nil {
+		t.Fatalf("ChurnOnce() error: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("ChurnOnce() churned %d nodes, want 3 (ceil(0.25 * 10))", n)
+	}
+
+	nodes, err := clientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(nodes.Items) != 11 {
+		t.Fatalf("expected all 11 nodes after recreation, got %d", len(nodes.Items))
+	}
+
+	recreated := 0
+	for _, node := range nodes.Items {
+		if len(node.Status.Conditions) == 0 {
+			recreated++
+			if node.Labels["pool"] != "cpu" {
+				t.Errorf("node %s outside selector was churned", node.Name)
+			}
+			if len(node.Spec.Taints) != 1 || node.Spec.Taints[0].Key != "kwok.x-k8s.io/node" {
+				t.Errorf("node %s taints = %v, want only the kwok taint", node.Name, node.Spec.Taints)
+			}
+		}
+	}
+	if recreated != 3 {
+		t.Errorf("expected 3 recreated nodes without conditions, got %d", recreated)
+	}
+}
+
+func TestChurnOnceNoMatchingNodes(t *testing.T) {
+	clientset := fake.NewClientset(kwokNode("cpu-0", "cpu"))
+
+	churner, err := NewNodeChurner(clientset, "", &config.NodeChurn{
+		Interval:     "1m",
+		Fraction:     1,
+		NodeSelector: map[string]string{"pool": "gpu"},
+	}, 1)
+	if err != nil {
+		t.Fatalf("NewNodeChurner() error: %v", err)
+	}
+
+	n, err := churner.ChurnOnce(context.Background())
+	if err != nil {
+		t.Fatalf("ChurnOnce() error: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("ChurnOnce() churned %d nodes, want 0", n)
+	}
+}
+
+func TestChurnOnceOtherTopology(t *testing.T) {
+	other := kwokNode("cpu-0", "cpu")
+	maps.Copy(other.Labels, ownership.Labels("other", ""))
+	clientset := fake.NewClientset(other)
+
+	churner, err := NewNodeChurner(clientset, "bench", &config.NodeChurn{Interval: "1m", Fraction: 1}, 1)
+	if err != nil {
+		t.Fatalf("NewNodeChurner() error: %v", err)
+	}
+
+	n, err := churner.ChurnOnce(context.Background())
+	if err != nil {
+		t.Fatalf("ChurnOnce() error: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("ChurnOnce() churned %d nodes of another topology, want 0", n)
+	}
+}
@@ -0,0 +1,251 @@
+package chaos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/kwok"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// FaultInjector applies scheduled node faults during a run and reverts them
+// when their duration elapses or the run ends
+type FaultInjector struct {
+	clientset kubernetes.Interface
+	topology  string
+	faults    []scheduledFault
+	rng       *rand.Rand
+}
+
+// scheduledFault is a NodeFault with its timing parsed and node selector resolved
+type scheduledFault struct {
+	config.NodeFault
+	at       time.Duration
+	duration time.Duration
+	selector labels.Selector
+	nodes    []string // nodes the fault is currently applied to
+}
+
+// faultAction is a point in time at which a fault is applied or reverted
+type faultAction struct {
+	at     time.Duration
+	fault  *scheduledFault
+	revert bool
+}
+
+// NewFaultInjector creates a FaultInjector from validated fault settings. Faults only hit
+// the nodes of the named topology; seed makes the choice of affected nodes reproducible.
+func NewFaultInjector(clientset kubernetes.Interface, topology string, faults []config.NodeFault, seed int64) (*FaultInjector, error) {
+	scheduled := make([]scheduledFault, 0, len(faults))
+	for i, f := range faults {
+		at, err := time.ParseDuration(f.At)
+		if err != nil {
+			return nil, fmt.Errorf("fault[%d]: invalid at %q: %w", i, f.At, err)
+		}
+
+		var duration time.Duration
+		if f.Duration != "" {
+			if duration, err = time.ParseDuration(f.Duration); err != nil {
+				return nil, fmt.Errorf("fault[%d]: invalid duration %q: %w", i, f.Duration, err)
+			}
+		}
+
+		set := labels.Merge(kwokNodeSelector, f.NodeSelector)
+		if f.Pool != "" {
+			set[kwok.PoolLabel] = f.Pool
+		}
+
+		scheduled = append(scheduled, scheduledFault{
+			NodeFault: f,
+			at:        at,
+			duration:  duration,
+			selector:  labels.SelectorFromSet(set),
+		})
+	}
+
+	return &FaultInjector{
+		clientset: clientset,
+		topology:  topology,
+		faults:    scheduled,
+		rng:       rand.New(rand.NewSource(seed)), //nolint:gosec // reproducible simulation, not security-sensitive
+	}, nil
+}
+
+// Run applies and reverts faults on schedule, measured from the time Run is called.
+// When the context is cancelled, every fault still in effect is reverted.
+func (f *FaultInjector) Run(ctx context.Context) error {
+	actions := f.schedule()
+	start := time.Now()
+
+	var runErr error
+	for _, action := range actions {
+		timer := time.NewTimer(time.Until(start.Add(action.at)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return f.revertAll(ctx)
+		case <-timer.C:
+		}
+
+		if action.revert {
+			if err := f.revert(ctx, action.fault); err != nil {
+				runErr = errors.Join(runErr, err)
+			}
+			continue
+		}
+		if err := f.apply(ctx, action.fault); err != nil {
+			runErr = errors.Join(runErr, err)
+		}
+	}
+
+	<-ctx.Done()
+	return errors.Join(runErr, f.revertAll(ctx))
+}
+
+// schedule returns the apply and revert actions of all faults in time order
+func (f *FaultInjector) schedule() []faultAction {
+	var actions []faultAction
+	for i := range f.faults {
+		fault := &f.faults[i]
+		actions = append(actions, faultAction{at: fault.at, fault: fault})
+		if fault.duration > 0 {
+			actions = append(actions, faultAction{at: fault.at + fault.duration, fault: fault, revert: true})
+		}
+	}
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].at < actions[j].at
+	})
+	return actions
+}
+
+// apply injects a fault onto its target nodes
+func (f *FaultInjector) apply(ctx context.Context, fault *scheduledFault) error {
+	nodeList, err := f.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: fault.selector.String()})
+	if err != nil {
+		return fmt.Errorf("failed to list nodes for %s fault: %w", fault.Type, err)
+	}
+
+	nodes := ownedNodes(nodeList.Items, f.topology)
+	count := len(nodes)
+	switch {
+	case fault.Count > 0:
+		count = fault.Count
+	case fault.Fraction > 0:
+		count = int(math.Ceil(fault.Fraction * float64(len(nodes))))
+	}
+
+	for _, node := range pickNodes(f.rng, nodes, count) {
+		if err := f.setFault(ctx, fault, node.Name, true); err != nil {
+			return err
+		}
+		fault.nodes = append(fault.nodes, node.Name)
+	}
+
+	fmt.Printf("Injected %s fault on %d node(s)\n", fault.Type, len(fault.nodes))
+	return nil
+}
+
+// revert removes a fault from the nodes it was applied to
+func (f *FaultInjector) revert(ctx context.Context, fault *scheduledFault) error {
+	var errs error
+	for _, name := range fault.nodes {
+		if err := f.setFault(ctx, fault, name, false); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	if len(fault.nodes) > 0 {
+		fmt.Printf("Reverted %s fault on %d node(s)\n", fault.Type, len(fault.nodes))
+	}
+	fault.nodes = nil
+	return errs
+}
+
+// revertAll reverts every fault still in effect, even though ctx may be cancelled
+func (f *FaultInjector) revertAll(ctx context.Context) error {
+	revertCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recreateTimeout)
+	defer cancel()
+
+	var errs error
+	for i := range f.faults {
+		errs = errors.Join(errs, f.revert(revertCtx, &f.faults[i]))
+	}
+	return errs
+}
+
+// setFault applies (enabled) or removes a fault on a single node
+func (f *FaultInjector) setFault(ctx context.Context, fault *scheduledFault, name string, enabled bool) error {
+	var err error
+	switch fault.Type {
+	case config.NodeFaultNotReady:
+		// The node-not-ready KWOK stage marks labeled nodes NotReady; removing the label
+		// lets node-initialize mark them Ready again
+		var value interface{}
+		if enabled {
+			value = "true"
+		}
+		err = f.mergePatch(ctx, name, map[string]interface{}{
+			"metadata": map[string]interface{}{"labels": map[string]interface{}{kwok.NotReadyLabel: value}},
+		})
+	case config.NodeFaultCordon:
+		err = f.mergePatch(ctx, name, map[string]interface{}{
+			"spec": map[string]interface{}{"unschedulable": enabled},
+		})
+	case config.NodeFaultTaint:
+		err = f.setTaint(ctx, name, fault.Taint, enabled)
+	default:
+		err = fmt.Errorf("unsupported fault type %q", fault.Type)
+	}
+
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to set %s fault on node %s: %w", fault.Type, name, err)
+	}
+	return nil
+}
+
+// mergePatch applies a JSON merge patch to a node
+func (f *FaultInjector) mergePatch(ctx context.Context, name string, patch map[string]interface{}) error {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = f.clientset.CoreV1().Nodes().Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{})
+	return err
+}
+
+// setTaint adds or removes a taint on a node
+func (f *FaultInjector) setTaint(ctx context.Context, name string, taint *config.Taint, enabled bool) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := f.clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		effect := corev1.TaintEffect(taint.Effect)
+		taints := make([]corev1.Taint, 0, len(node.Spec.Taints)+1)
+		for _, t := range node.Spec.Taints {
+			if t.Key != taint.Key || t.Effect != effect {
+				taints = append(taints, t)
+			}
+		}
+		if enabled {
+			taints = append(taints, corev1.Taint{Key: taint.Key, Value: taint.Value, Effect: effect})
+		}
+		node.Spec.Taints = taints
+
+		_, err = f.clientset.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+		return err
+	})
+}
@@ -0,0 +1,140 @@
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/kwok"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func poolNodes(pool string, n int) []runtime.Object {
+	var objects []runtime.Object
+	for i := 0; i < n; i++ {
+		node := kwokNode(fmt.Sprintf("%s-%d", pool, i), pool)
+		node.Labels[kwok.PoolLabel] = pool
+		objects = append(objects, node)
+	}
+	return objects
+}
+
+func TestFaultInjectorApplyRevert(t *testing.T) {
+	tests := []struct {
+		name    string
+		fault   config.NodeFault
+		wantHit int
+		check   func(node *corev1.Node) bool // reports whether the fault is in effect
+	}{
+		{
+			name:    "notReady by count",
+			fault:   config.NodeFault{Type: config.NodeFaultNotReady, At: "0s", Pool: "gpu", Count: 2},
+			wantHit: 2,
+			check: func(node *corev1.Node) bool {
+				return node.Labels[kwok.NotReadyLabel] == "true"
+			},
+		},
+		{
+			name:    "cordon by fraction",
+			fault:   config.NodeFault{Type: config.NodeFaultCordon, At: "0s", Pool: "gpu", Fraction: 0.5},
+			wantHit: 2,
+			check: func(node *corev1.Node) bool {
+				return node.Spec.Unschedulable
+			},
+		},
+		{
+			name: "taint whole pool",
+			fault: config.NodeFault{Type: config.NodeFaultTaint, At: "0s", Pool: "gpu",
+				Taint: &config.Taint{Key: "maintenance", Value: "true", Effect: "NoExecute"}},
+			wantHit: 4,
+			check: func(node *corev1.Node) bool {
+				for _, t := range node.Spec.Taints {
+					if t.Key == "maintenance" && t.Effect == corev1.TaintEffectNoExecute {
+						return true
+					}
+				}
+				return false
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := append(poolNodes("gpu", 4), poolNodes("cpu", 4)...)
+			clientset := fake.NewClientset(objects...)
+			ctx := context.Background()
+
+			injector, err := NewFaultInjector(clientset, "", []config.NodeFault{tt.fault}, 7)
+			if err != nil {
+				t.Fatalf("NewFaultInjector() error: %v", err)
+			}
+
+			countHit := func() int {
+				nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+				if err != nil {
+					t.Fatalf("List() error: %v", err)
+				}
+				hit := 0
+				for i := range nodes.Items {
+					if tt.check(&nodes.Items[i]) {
+						if nodes.Items[i].Labels["pool"] != "gpu" {
+							t.Errorf("node %s outside the target pool was affected", nodes.Items[i].Name)
+						}
+						hit++
+					}
+				}
+				return hit
+			}
+
+			if err := injector.apply(ctx, &injector.faults[0]); err != nil {
+				t.Fatalf("apply() error: %v", err)
+			}
+			if got := countHit(); got != tt.wantHit {
+				t.Errorf("after apply: %d nodes affected, want %d", got, tt.wantHit)
+			}
+
+			if err := injector.revertAll(ctx); err != nil {
+				t.Fatalf("revertAll() error: %v", err)
+			}
+			if got := countHit(); got != 0 {
+				t.Errorf("after revert: %d nodes affected, want 0", got)
+			}
+		})
+	}
+}
+
+func TestFaultInjectorSchedule(t *testing.T) {
+	injector, err := NewFaultInjector(fake.NewClientset(), "", []config.NodeFault{
+		{Type: config.NodeFaultCordon, At: "5m", Duration: "1m"},
+		{Type: config.NodeFaultNotReady, At: "2m", Duration: "10m"},
+		{Type: config.NodeFaultCordon, At: "3m"},
+	}, 1)
+	if err != nil {
+		t.Fatalf("NewFaultInjector() error: %v", err)
+	}
+
+	actions := injector.schedule()
+	want := []struct {
+		at     string
+		revert bool
+	}{
+		{"2m0s", false},
+		{"3m0s", false},
+		{"5m0s", false},
+		{"6m0s", true},
+		{"12m0s", true},
+	}
+	if len(actions) != len(want) {
+		t.Fatalf("schedule() returned %d actions, want %d", len(actions), len(want))
+	}
+	for i, w := range want {
+		if actions[i].at.String() != w.at || actions[i].revert != w.revert {
+			t.Errorf("action[%d] = {%s, revert=%v}, want {%s, revert=%v}",
+				i, actions[i].at, actions[i].revert, w.at, w.revert)
+		}
+	}
+}
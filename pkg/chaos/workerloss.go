@@ -0,0 +1,155 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/kueue"
+	"github.com/jhwagner/kueue-bench/pkg/run"
+	kueuev1beta2 "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+)
+
+// workerLossPollInterval is how often the Workloads of a lost worker are checked
+const workerLossPollInterval = 2 * time.Second
+
+// WorkerLossMonitor deletes MultiKueue workers at fixed offsets into a run and measures how
+// long the management cluster takes to requeue or reject the Workloads dispatched to them
+type WorkerLossMonitor struct {
+	client       *kueue.Client
+	losses       []scheduledLoss
+	deleteWorker func(ctx context.Context, worker string) error
+
+	mu      sync.Mutex
+	results []run.WorkerLossResult
+}
+
+// scheduledLoss is a WorkerLoss with its timing parsed and MultiKueueClusters resolved
+type scheduledLoss struct {
+	config.WorkerLoss
+	at       time.Duration
+	clusters map[string]bool
+}
+
+// NewWorkerLossMonitor creates a WorkerLossMonitor from validated worker loss settings.
+// client is connected to the management cluster, clusters maps each lost worker to the
+// MultiKueueClusters the management cluster reaches it through, and deleteWorker deletes a
+// worker cluster.
+func NewWorkerLossMonitor(client *kueue.Client, losses []config.WorkerLoss, clusters map[string][]string, deleteWorker func(ctx context.Context, worker string) error) (*WorkerLossMonitor, error) {
+	scheduled := make([]scheduledLoss, 0, len(losses))
+	for i, l := range losses {
+		at, err := time.ParseDuration(l.At)
+		if err != nil {
+			return nil, fmt.Errorf("workerLoss[%d]: invalid at %q: %w", i, l.At, err)
+		}
+		if len(clusters[l.Worker]) == 0 {
+			return nil, fmt.Errorf("workerLoss[%d]: no MultiKueueCluster for worker %q", i, l.Worker)
+		}
+		names := make(map[string]bool, len(clusters[l.Worker]))
+		for _, name := range clusters[l.Worker] {
+			names[name] = true
+		}
+		scheduled = append(scheduled, scheduledLoss{WorkerLoss: l, at: at, clusters: names})
+	}
+
+	return &WorkerLossMonitor{client: client, losses: scheduled, deleteWorker: deleteWorker}, nil
+}
+
+// Run deletes each worker at its offset and follows the Workloads dispatched to it until
+// all are requeued or rejected, or ctx is cancelled. Workers not yet due when ctx is
+// cancelled are kept.
+func (m *WorkerLossMonitor) Run(ctx context.Context) error {
+	start := time.Now()
+	errs := make(chan error, len(m.losses))
+	for i := range m.losses {
+		go func() { errs <- m.lose(ctx, &m.losses[i], start) }()
+	}
+	var combined error
+	for range m.losses {
+		combined = errors.Join(combined, <-errs)
+	}
+	return combined
+}
+
+// Results returns the results of the workers lost so far, in deletion order
+func (m *WorkerLossMonitor) Results() []run.WorkerLossResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	results := append([]run.WorkerLossResult(nil), m.results...)
+	sort.Slice(results, func(i, j int) bool { return results[i].DeletedAt.Before(results[j].DeletedAt) })
+	return results
+}
+
+// lose deletes one worker when it is due and records how its Workloads were handled
+func (m *WorkerLossMonitor) lose(ctx context.Context, l *scheduledLoss, start time.Time) error {
+	timer := time.NewTimer(time.Until(start.Add(l.at)))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-timer.C:
+	}
+
+	workloads, err := m.client.ListWorkloads(ctx)
+	if err != nil {
+		return fmt.Errorf("worker %q: %w", l.Worker, err)
+	}
+	pending := make(map[string]bool)
+	for i := range workloads {
+		if kueue.DispatchedTo(&workloads[i], l.clusters) {
+			pending[workloads[i].Namespace+"/"+workloads[i].Name] = true
+		}
+	}
+	dispatched := len(pending)
+
+	if err := m.deleteWorker(ctx, l.Worker); err != nil {
+		return fmt.Errorf("failed to delete worker %q: %w", l.Worker, err)
+	}
+	deletedAt := time.Now()
+	fmt.Printf("✓ Worker '%s' deleted with %d dispatched workloads\n", l.Worker, dispatched)
+
+	var requeued, rejected []time.Duration
+	ticker := time.NewTicker(workerLossPollInterval)
+	defer ticker.Stop()
+	for len(pending) > 0 {
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		workloads, err := m.client.ListWorkloads(ctx)
+		if err != nil {
+			// Keep polling; a single failed list does not invalidate the measurement
+			continue
+		}
+		byKey := make(map[string]*kueuev1beta2.Workload, len(workloads))
+		for i := range workloads {
+			byKey[workloads[i].Namespace+"/"+workloads[i].Name] = &workloads[i]
+		}
+		now := time.Now()
+		for key := range pending {
+			outcome, ok := kueue.LostClusterOutcome(byKey[key], l.clusters)
+			if !ok {
+				continue
+			}
+			delete(pending, key)
+			if outcome == kueue.LostClusterRequeued {
+				requeued = append(requeued, now.Sub(deletedAt))
+			} else {
+				rejected = append(rejected, now.Sub(deletedAt))
+			}
+		}
+	}
+
+	m.mu.Lock()
+	m.results = append(m.results, run.SummarizeWorkerLoss(l.Worker, deletedAt, dispatched, requeued, rejected))
+	m.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,43 @@
+package cluster
+
+import (
+	"strings"
+)
+
+const (
+	// kindNodeImageRepo is the repository of kind's published node images
+	kindNodeImageRepo = "kindest/node"
+
+	// DefaultKubernetesVersion is the Kubernetes version of clusters that do not set one.
+	// It is pinned rather than following kind's default node image, so it stays within
+	// the Kubernetes versions the default Kwok release is tested against.
+	DefaultKubernetesVersion = "v1.33.1"
+)
+
+// NodeImage returns the kind node image for a cluster's kubernetesVersion setting.
+// A version such as "v1.31.0" or "1.31.0" maps to the published kindest/node image;
+// a value containing "/" or ":" is used as a full image reference. An empty version
+// selects the image of DefaultKubernetesVersion.
+func NodeImage(kubernetesVersion string) string {
+	switch {
+	case kubernetesVersion == "":
+		kubernetesVersion = DefaultKubernetesVersion
+	case strings.ContainsAny(kubernetesVersion, "/:"):
+		return kubernetesVersion
+	case !strings.HasPrefix(kubernetesVersion, "v"):
+		kubernetesVersion = "v" + kubernetesVersion
+	}
+	return kindNodeImageRepo + ":" + kubernetesVersion
+}
+
+// KubernetesVersion returns the Kubernetes version a cluster will run, taken from the
+// tag of its node image (e.g. "v1.35.0"), or "" if the image has no version tag.
+func KubernetesVersion(kubernetesVersion string) string {
+	image := NodeImage(kubernetesVersion)
+	image, _, _ = strings.Cut(image, "@")
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+	return image[i+1:]
+}
@@ -0,0 +1,56 @@
+package cluster
+
+import (
+	"testing"
+)
+
+func TestNodeImage(t *testing.T) {
+	tests := []struct {
+		name              string
+		kubernetesVersion string
+		wantImage         string
+		wantVersion       string
+	}{
+		{
+			name:              "default",
+			kubernetesVersion: "",
+			wantImage:         "kindest/node:" + DefaultKubernetesVersion,
+			wantVersion:       DefaultKubernetesVersion,
+		},
+		{
+			name:              "version with v prefix",
+			kubernetesVersion: "v1.31.0",
+			wantImage:         "kindest/node:v1.31.0",
+			wantVersion:       "v1.31.0",
+		},
+		{
+			name:              "version without v prefix",
+			kubernetesVersion: "1.32.2",
+			wantImage:         "kindest/node:v1.32.2",
+			wantVersion:       "v1.32.2",
+		},
+		{
+			name:              "full image reference",
+			kubernetesVersion: "localhost:5001/node:v1.33.1",
+			wantImage:         "localhost:5001/node:v1.33.1",
+			wantVersion:       "v1.33.1",
+		},
+		{
+			name:              "untagged image",
+			kubernetesVersion: "localhost:5001/node",
+			wantImage:         "localhost:5001/node",
+			wantVersion:       "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NodeImage(tt.kubernetesVersion); got != tt.wantImage {
+				t.Errorf("NodeImage() = %q, want %q", got, tt.wantImage)
+			}
+			if got := KubernetesVersion(tt.kubernetesVersion); got != tt.wantVersion {
+				t.Errorf("KubernetesVersion() = %q, want %q", got, tt.wantVersion)
+			}
+		})
+	}
+}
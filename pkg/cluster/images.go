@@ -0,0 +1,69 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+)
+
+// LoadImages loads local Docker images into every node of a kind cluster.
+// This is the library equivalent of `kind load docker-image`: the images are
+// saved to a temporary archive with `docker save` and imported into each node's
+// containerd image store.
+func LoadImages(ctx context.Context, name string, images []string) error {
+	if len(images) == 0 {
+		return nil
+	}
+
+	provider := getProvider()
+
+	kindNodes, err := provider.ListInternalNodes(name)
+	if err != nil {
+		return fmt.Errorf("failed to list nodes for cluster '%s': %w", name, err)
+	}
+	if len(kindNodes) == 0 {
+		return fmt.Errorf("no nodes found for cluster '%s'", name)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "kueue-bench-images-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	// Save all images into a single archive
+	archivePath := filepath.Join(tmpDir, "images.tar")
+	args := append([]string{"save", "-o", archivePath}, images...)
+	if out, err := exec.CommandContext(ctx, "docker", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to save images %v: %w: %s", images, err, out)
+	}
+
+	fmt.Printf("Loading %d image(s) into cluster '%s'...\n", len(images), name)
+	for _, node := range kindNodes {
+		if err := loadImageArchive(ctx, node, archivePath); err != nil {
+			return fmt.Errorf("failed to load images into node %s: %w", node.String(), err)
+		}
+	}
+
+	fmt.Printf("✓ Images loaded into cluster '%s'\n", name)
+	return nil
+}
+
+// loadImageArchive imports an image archive into a single kind node, returning early if
+// ctx is cancelled.
+func loadImageArchive(ctx context.Context, node nodes.Node, archivePath string) error {
+	return untilCancelled(ctx, func() error {
+		f, err := os.Open(archivePath) //nolint:gosec // archive is written to a temp directory we own
+		if err != nil {
+			return fmt.Errorf("failed to open image archive: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+
+		return nodeutils.LoadImageArchive(node, f)
+	}, nil)
+}
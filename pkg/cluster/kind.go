@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/kind/pkg/apis/config/v1alpha4"
 	"sigs.k8s.io/kind/pkg/cluster"
 )
@@ -27,39 +31,60 @@ func getProvider() *cluster.Provider {
 	return provider
 }
 
+// CreateOptions contains optional settings for kind cluster creation
+type CreateOptions struct {
+	// Registry configures containerd registry mirrors and the local registry
+	Registry *config.RegistrySettings
+	// NodeMonitorGracePeriod overrides kube-controller-manager's node-monitor-grace-period
+	// so that simulated nodes with infrequent lease renewals stay Ready (optional)
+	NodeMonitorGracePeriod time.Duration
+	// NodeLabels are set on every kind node, e.g. to record the topology owning the cluster
+	NodeLabels map[string]string
+}
+
 // CreateCluster creates a new kind cluster
-func CreateCluster(ctx context.Context, name string, cfg *config.ClusterConfig, kubeconfigPath string) error {
+func CreateCluster(ctx context.Context, name string, cfg *config.ClusterConfig, kubeconfigPath string, opts CreateOptions) error {
 	provider := getProvider()
 
 	// Check if cluster already exists
-	clusters, err := provider.List()
+	exists, err := Exists(name)
 	if err != nil {
-		return fmt.Errorf("failed to list clusters: %w", err)
+		return err
 	}
-	for _, c := range clusters {
-		if c == name {
-			return fmt.Errorf("cluster '%s' already exists", name)
-		}
+	if exists {
+		return fmt.Errorf("cluster '%s' already exists", name)
 	}
 
 	// Generate kind config
-	kindConfig := generateKindConfig(cfg)
+	kindConfig := generateKindConfig(cfg, opts)
 
 	// Create cluster
 	fmt.Printf("Creating kind cluster '%s'...\n", name)
-	if err := provider.Create(
-		name,
-		cluster.CreateWithV1Alpha4Config(kindConfig),
-		cluster.CreateWithWaitForReady(2*time.Minute),
-	); err != nil {
+	// Remove any partially created cluster before retrying, or when cancelled mid-create
+	cleanupOp := func() { _ = provider.Delete(name, "") }
+	createOp := func() error {
+		return untilCancelled(ctx, func() error {
+			return provider.Create(
+				name,
+				cluster.CreateWithV1Alpha4Config(kindConfig),
+				cluster.CreateWithWaitForReady(2*time.Minute),
+			)
+		}, cleanupOp)
+	}
+	if err := withRetry(ctx, fmt.Sprintf("create kind cluster '%s'", name), createOp, cleanupOp); err != nil {
 		return fmt.Errorf("failed to create kind cluster: %w", err)
 	}
 
 	// Export kubeconfig to specified path
-	if err := ExportKubeconfig(name, kubeconfigPath); err != nil {
+	if err := ExportKubeconfig(ctx, name, kubeconfigPath); err != nil {
 		return fmt.Errorf("failed to export kubeconfig: %w", err)
 	}
 
+	// Write registry mirror configuration onto the nodes
+	if err := ConfigureRegistry(ctx, name, opts.Registry); err != nil {
+		return fmt.Errorf("failed to configure registry: %w", err)
+	}
+
 	fmt.Printf("✓ Cluster '%s' created successfully\n", name)
 	return nil
 }
@@ -69,16 +94,9 @@ func DeleteCluster(ctx context.Context, name string) error {
 	provider := getProvider()
 
 	// Check if cluster exists
-	clusters, err := provider.List()
+	exists, err := Exists(name)
 	if err != nil {
-		return fmt.Errorf("failed to list clusters: %w", err)
-	}
-	exists := false
-	for _, c := range clusters {
-		if c == name {
-			exists = true
-			break
-		}
+		return err
 	}
 	if !exists {
 		return fmt.Errorf("cluster '%s' does not exist", name)
@@ -86,7 +104,10 @@ func DeleteCluster(ctx context.Context, name string) error {
 
 	// Delete cluster
 	fmt.Printf("Deleting kind cluster '%s'...\n", name)
-	if err := provider.Delete(name, ""); err != nil {
+	deleteOp := func() error {
+		return untilCancelled(ctx, func() error { return provider.Delete(name, "") }, nil)
+	}
+	if err := withRetry(ctx, fmt.Sprintf("delete kind cluster '%s'", name), deleteOp, nil); err != nil {
 		return fmt.Errorf("failed to delete kind cluster: %w", err)
 	}
 
@@ -94,21 +115,62 @@ func DeleteCluster(ctx context.Context, name string) error {
 	return nil
 }
 
+// Exists reports whether a kind cluster with the given name exists
+func Exists(name string) (bool, error) {
+	clusters, err := getProvider().List()
+	if err != nil {
+		return false, fmt.Errorf("failed to list clusters: %w", err)
+	}
+	return slices.Contains(clusters, name), nil
+}
+
+// HasNodes reports whether any node of the cluster at kubeconfigPath matches selector
+func HasNodes(ctx context.Context, kubeconfigPath, selector string, opts ...restconfig.Option) (bool, error) {
+	restConfig, err := restconfig.ForKubeconfig(kubeconfigPath, 0, opts...)
+	if err != nil {
+		return false, err
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return false, fmt.Errorf("failed to create clientset: %w", err)
+	}
+	nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: selector, Limit: 1})
+	if err != nil {
+		return false, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	return len(nodes.Items) > 0, nil
+}
+
 // Helper functions
 
-func generateKindConfig(_ *config.ClusterConfig) *v1alpha4.Cluster {
+func generateKindConfig(cfg *config.ClusterConfig, opts CreateOptions) *v1alpha4.Cluster {
 	kindCfg := &v1alpha4.Cluster{
 		Nodes: []v1alpha4.Node{
-			{Role: v1alpha4.ControlPlaneRole},
+			{Role: v1alpha4.ControlPlaneRole, Image: NodeImage(cfg.KubernetesVersion), Labels: opts.NodeLabels},
 		},
 	}
 
+	// Enable per-registry hosts.toml lookup so mirrors can be configured on the nodes
+	if opts.Registry != nil {
+		kindCfg.ContainerdConfigPatches = append(kindCfg.ContainerdConfigPatches, containerdRegistryConfigPatch)
+	}
+
+	if opts.NodeMonitorGracePeriod > 0 {
+		kindCfg.KubeadmConfigPatches = append(kindCfg.KubeadmConfigPatches, fmt.Sprintf(nodeMonitorGracePeriodPatch, opts.NodeMonitorGracePeriod))
+	}
+
 	return kindCfg
 }
 
+// nodeMonitorGracePeriodPatch sets kube-controller-manager's node-monitor-grace-period
+const nodeMonitorGracePeriodPatch = `kind: ClusterConfiguration
+controllerManager:
+  extraArgs:
+    node-monitor-grace-period: "%s"`
+
 // ExportKubeconfig exports a kubeconfig for the given kind cluster to a file.
-func ExportKubeconfig(name string, kubeconfigPath string) error {
-	data, err := GetKubeconfig(name, false)
+func ExportKubeconfig(ctx context.Context, name string, kubeconfigPath string) error {
+	data, err := GetKubeconfig(ctx, name, false)
 	if err != nil {
 		return err
 	}
@@ -128,10 +190,17 @@ func ExportKubeconfig(name string, kubeconfigPath string) error {
 // GetKubeconfig returns the raw kubeconfig bytes for a kind cluster.
 // When internal is true, uses the cluster's Docker network address instead of 127.0.0.1,
 // which is needed for inter-cluster connectivity (e.g. MultiKueue management to worker).
-func GetKubeconfig(name string, internal bool) ([]byte, error) {
+func GetKubeconfig(ctx context.Context, name string, internal bool) ([]byte, error) {
 	provider := getProvider()
-	kubeconfig, err := provider.KubeConfig(name, internal)
-	if err != nil {
+	var kubeconfig string
+	getOp := func() error {
+		return untilCancelled(ctx, func() error {
+			var err error
+			kubeconfig, err = provider.KubeConfig(name, internal)
+			return err
+		}, nil)
+	}
+	if err := withRetry(ctx, fmt.Sprintf("get kubeconfig for '%s'", name), getOp, nil); err != nil {
 		return nil, fmt.Errorf("failed to get kubeconfig: %w", err)
 	}
 	return []byte(kubeconfig), nil
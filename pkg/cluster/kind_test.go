@@ -0,0 +1,18 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+)
+
+func TestGenerateKindConfigNodeLabels(t *testing.T) {
+	labels := map[string]string{"kueue-bench.io/topology": "bench"}
+	kindCfg := generateKindConfig(&config.ClusterConfig{}, CreateOptions{NodeLabels: labels})
+	for _, node := range kindCfg.Nodes {
+		if !reflect.DeepEqual(node.Labels, labels) {
+			t.Errorf("node %s labels = %v, want %v", node.Role, node.Labels, labels)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+)
+
+// ShapeTraffic adds netem latency, loss and rate limiting to traffic from the nodes of kind
+// cluster name to the nodes of kind cluster dst. Other traffic is left alone: a prio qdisc
+// with an extra band holds the netem qdisc, and a u32 filter per destination IP steers
+// packets into it.
+func ShapeTraffic(ctx context.Context, name, dst string, network *config.WorkerSetNetwork) error {
+	dstNodes, err := getProvider().ListNodes(dst)
+	if err != nil {
+		return fmt.Errorf("failed to list nodes for cluster '%s': %w", dst, err)
+	}
+	var dstIPs []string
+	for _, node := range dstNodes {
+		ipv4, _, err := node.IP()
+		if err != nil {
+			return fmt.Errorf("failed to get IP of node %s: %w", node.String(), err)
+		}
+		if ipv4 != "" {
+			dstIPs = append(dstIPs, ipv4)
+		}
+	}
+	if len(dstIPs) == 0 {
+		return fmt.Errorf("cluster '%s' has no node IPv4 addresses", dst)
+	}
+
+	kindNodes, err := getProvider().ListNodes(name)
+	if err != nil {
+		return fmt.Errorf("failed to list nodes for cluster '%s': %w", name, err)
+	}
+	for _, node := range kindNodes {
+		for _, args := range tcCommands(dstIPs, network) {
+			if err := node.CommandContext(ctx, "tc", args...).Run(); err != nil {
+				return fmt.Errorf("failed to run tc %s on node %s: %w", strings.Join(args, " "), node.String(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// tcCommands returns the tc invocations that shape eth0 egress to dstIPs. Packets are only
+// classified into band 4, which the default priomap never uses, by the destination filters.
+func tcCommands(dstIPs []string, network *config.WorkerSetNetwork) [][]string {
+	cmds := [][]string{
+		{"qdisc", "replace", "dev", "eth0", "root", "handle", "1:", "prio", "bands", "4"},
+		append([]string{"qdisc", "replace", "dev", "eth0", "parent", "1:4", "handle", "40:"}, netemArgs(network)...),
+	}
+	for _, ip := range dstIPs {
+		cmds = append(cmds, []string{"filter", "add", "dev", "eth0", "parent", "1:", "protocol", "ip",
+			"prio", "1", "u32", "match", "ip", "dst", ip + "/32", "flowid", "1:4"})
+	}
+	return cmds
+}
+
+// netemArgs renders a netem qdisc for the network settings
+func netemArgs(network *config.WorkerSetNetwork) []string {
+	args := []string{"netem"}
+	if network.Latency != "" {
+		args = append(args, "delay", network.Latency)
+		if network.Jitter != "" {
+			args = append(args, network.Jitter)
+		}
+	}
+	if network.Loss > 0 {
+		args = append(args, "loss", strconv.FormatFloat(network.Loss, 'g', -1, 64)+"%")
+	}
+	if network.Rate != "" {
+		args = append(args, "rate", network.Rate)
+	}
+	return args
+}
@@ -0,0 +1,56 @@
+package cluster
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+)
+
+func TestNetemArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		network config.WorkerSetNetwork
+		want    []string
+	}{
+		{
+			name:    "latency only",
+			network: config.WorkerSetNetwork{Latency: "50ms"},
+			want:    []string{"netem", "delay", "50ms"},
+		},
+		{
+			name:    "latency with jitter",
+			network: config.WorkerSetNetwork{Latency: "50ms", Jitter: "10ms"},
+			want:    []string{"netem", "delay", "50ms", "10ms"},
+		},
+		{
+			name:    "loss and rate",
+			network: config.WorkerSetNetwork{Loss: 0.5, Rate: "10mbit"},
+			want:    []string{"netem", "loss", "0.5%", "rate", "10mbit"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := netemArgs(&tt.network)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("netemArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTCCommands(t *testing.T) {
+	cmds := tcCommands([]string{"172.18.0.2", "172.18.0.3"}, &config.WorkerSetNetwork{Latency: "20ms"})
+	if len(cmds) != 4 {
+		t.Fatalf("got %d commands, want 4: %v", len(cmds), cmds)
+	}
+	if !slices.Contains(cmds[1], "1:4") || !slices.Contains(cmds[1], "netem") {
+		t.Errorf("netem qdisc not attached to band 1:4: %v", cmds[1])
+	}
+	for i, ip := range []string{"172.18.0.2", "172.18.0.3"} {
+		if !slices.Contains(cmds[2+i], ip+"/32") {
+			t.Errorf("filter %d does not match %s: %v", i, ip, cmds[2+i])
+		}
+	}
+}
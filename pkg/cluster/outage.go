@@ -0,0 +1,95 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// PauseCluster freezes every node container of a kind cluster with the container runtime's
+// pause command, making its API server unreachable without losing any state. If a node
+// fails to pause, the nodes already paused are resumed before returning the error.
+func PauseCluster(ctx context.Context, name string) error {
+	nodes, err := clusterNodes(name)
+	if err != nil {
+		return err
+	}
+	if err := pauseNodes(ctx, containerRuntime(), nodes); err != nil {
+		return fmt.Errorf("failed to pause cluster '%s': %w", name, err)
+	}
+	return nil
+}
+
+// pauseNodes pauses nodes in order, resuming the ones already paused if one fails
+func pauseNodes(ctx context.Context, runtime string, nodes []string) error {
+	for i, node := range nodes {
+		if err := runNodeCommand(ctx, runtime, "pause", node); err != nil {
+			// Roll back even if ctx was cancelled, so no node is left paused
+			if rbErr := runNodesCommand(context.WithoutCancel(ctx), runtime, "unpause", nodes[:i]); rbErr != nil {
+				return errors.Join(err, fmt.Errorf("failed to roll back: %w", rbErr))
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// UnpauseCluster resumes the node containers of a kind cluster paused by PauseCluster.
+// Every node is attempted even if some fail, so a partial failure leaves as few nodes
+// paused as possible.
+func UnpauseCluster(ctx context.Context, name string) error {
+	nodes, err := clusterNodes(name)
+	if err != nil {
+		return err
+	}
+	return runNodesCommand(ctx, containerRuntime(), "unpause", nodes)
+}
+
+// containerRuntime returns the CLI of the container runtime kind runs its nodes with,
+// honouring KIND_EXPERIMENTAL_PROVIDER like kind itself does
+func containerRuntime() string {
+	switch p := os.Getenv("KIND_EXPERIMENTAL_PROVIDER"); p {
+	case "podman", "nerdctl":
+		return p
+	default:
+		return "docker"
+	}
+}
+
+// clusterNodes returns the names of the node containers of a kind cluster
+func clusterNodes(name string) ([]string, error) {
+	kindNodes, err := getProvider().ListNodes(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes for cluster '%s': %w", name, err)
+	}
+	if len(kindNodes) == 0 {
+		return nil, fmt.Errorf("cluster '%s' has no nodes", name)
+	}
+
+	nodes := make([]string, 0, len(kindNodes))
+	for _, node := range kindNodes {
+		nodes = append(nodes, node.String())
+	}
+	return nodes, nil
+}
+
+// runNodesCommand runs a container command against every node, returning all failures
+func runNodesCommand(ctx context.Context, runtime, command string, nodes []string) error {
+	var errs []error
+	for _, node := range nodes {
+		if err := runNodeCommand(ctx, runtime, command, node); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runNodeCommand runs a container command such as pause or unpause against a node container
+func runNodeCommand(ctx context.Context, runtime, command, node string) error {
+	if out, err := exec.CommandContext(ctx, runtime, command, node).CombinedOutput(); err != nil { //nolint:gosec // node names come from kind
+		return fmt.Errorf("failed to %s node %s: %w: %s", command, node, err, out)
+	}
+	return nil
+}
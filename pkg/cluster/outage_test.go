@@ -0,0 +1,85 @@
+package cluster
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContainerRuntime(t *testing.T) {
+	tests := []struct {
+		provider string
+		want     string
+	}{
+		{provider: "", want: "docker"},
+		{provider: "docker", want: "docker"},
+		{provider: "podman", want: "podman"},
+		{provider: "nerdctl", want: "nerdctl"},
+		{provider: "unknown", want: "docker"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			t.Setenv("KIND_EXPERIMENTAL_PROVIDER", tt.provider)
+			if got := containerRuntime(); got != tt.want {
+				t.Errorf("containerRuntime() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeRuntime writes a container runtime CLI that logs its arguments and fails to pause
+// the node named failNode
+func fakeRuntime(t *testing.T, failNode string) (runtime, log string) {
+	t.Helper()
+	dir := t.TempDir()
+	log = filepath.Join(dir, "calls")
+	runtime = filepath.Join(dir, "runtime")
+	script := "#!/bin/sh\necho \"$1 $2\" >> " + log + "\n" +
+		"if [ \"$1\" = pause ] && [ \"$2\" = \"" + failNode + "\" ]; then exit 1; fi\n"
+	if err := os.WriteFile(runtime, []byte(script), 0o755); err != nil { //nolint:gosec // test script must be executable
+		t.Fatal(err)
+	}
+	return runtime, log
+}
+
+func readCalls(t *testing.T, log string) []string {
+	t.Helper()
+	data, err := os.ReadFile(log) //nolint:gosec // path is from t.TempDir
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestPauseNodes(t *testing.T) {
+	nodes := []string{"w1-control-plane", "w1-worker", "w1-worker2"}
+
+	t.Run("pauses every node", func(t *testing.T) {
+		runtime, log := fakeRuntime(t, "")
+		if err := pauseNodes(context.Background(), runtime, nodes); err != nil {
+			t.Fatalf("pauseNodes() error = %v", err)
+		}
+		want := []string{"pause w1-control-plane", "pause w1-worker", "pause w1-worker2"}
+		if got := readCalls(t, log); !reflect.DeepEqual(got, want) {
+			t.Errorf("calls = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("rolls back on failure", func(t *testing.T) {
+		runtime, log := fakeRuntime(t, "w1-worker2")
+		err := pauseNodes(context.Background(), runtime, nodes)
+		if err == nil || !strings.Contains(err.Error(), "w1-worker2") {
+			t.Fatalf("pauseNodes() error = %v, want failure on w1-worker2", err)
+		}
+		want := []string{
+			"pause w1-control-plane", "pause w1-worker", "pause w1-worker2",
+			"unpause w1-control-plane", "unpause w1-worker",
+		}
+		if got := readCalls(t, log); !reflect.DeepEqual(got, want) {
+			t.Errorf("calls = %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+)
+
+const (
+	// Defaults for the optional local registry container
+	defaultLocalRegistryName  = "kueue-bench-registry"
+	defaultLocalRegistryPort  = 5001
+	defaultLocalRegistryImage = "registry:2"
+
+	// kindNetwork is the Docker network kind attaches cluster nodes to
+	kindNetwork = "kind"
+
+	// registryConfigDir is where containerd looks up per-registry hosts.toml files
+	registryConfigDir = "/etc/containerd/certs.d"
+)
+
+// containerdRegistryConfigPatch points containerd at registryConfigDir so that
+// per-registry mirror configuration can be written after the nodes are up.
+const containerdRegistryConfigPatch = `[plugins."io.containerd.grpc.v1.cri".registry]
+  config_path = "` + registryConfigDir + `"`
+
+// ConfigureRegistry applies registry settings to a running kind cluster.
+// It ensures the local registry container (if configured) is running and attached
+// to the kind network, then writes a containerd hosts.toml for every mirrored
+// registry on each node.
+func ConfigureRegistry(ctx context.Context, name string, settings *config.RegistrySettings) error {
+	if settings == nil {
+		return nil
+	}
+
+	hosts := registryHosts(settings)
+
+	if settings.LocalRegistry != nil {
+		if err := ensureLocalRegistry(ctx, settings.LocalRegistry); err != nil {
+			return err
+		}
+	}
+
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	kindNodes, err := getProvider().ListInternalNodes(name)
+	if err != nil {
+		return fmt.Errorf("failed to list nodes for cluster '%s': %w", name, err)
+	}
+
+	for _, node := range kindNodes {
+		for registry, content := range hosts {
+			path := fmt.Sprintf("%s/%s/hosts.toml", registryConfigDir, registry)
+			if err := nodeutils.WriteFile(node, path, content); err != nil {
+				return fmt.Errorf("failed to write registry config for %s on node %s: %w", registry, node.String(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// registryHosts renders a containerd hosts.toml for each mirrored registry, keyed by registry host.
+// A configured local registry is also made reachable from the nodes as localhost:<port>,
+// matching the address images are pushed to from the host.
+func registryHosts(settings *config.RegistrySettings) map[string]string {
+	hosts := make(map[string]string, len(settings.Mirrors)+1)
+
+	for _, m := range settings.Mirrors {
+		var b strings.Builder
+		for _, ep := range m.Endpoints {
+			fmt.Fprintf(&b, "[host.%q]\n  capabilities = [\"pull\", \"resolve\"]\n", ep)
+		}
+		hosts[m.Registry] = b.String()
+	}
+
+	if lr := settings.LocalRegistry; lr != nil {
+		name, port, _ := localRegistryDefaults(lr)
+		local := fmt.Sprintf("localhost:%d", port)
+		if _, ok := hosts[local]; !ok {
+			hosts[local] = fmt.Sprintf("[host.%q]\n", fmt.Sprintf("http://%s:5000", name))
+		}
+	}
+
+	return hosts
+}
+
+// localRegistryDefaults returns the local registry name, host port and image with defaults applied.
+func localRegistryDefaults(lr *config.LocalRegistry) (name string, port int, image string) {
+	name, port, image = lr.Name, lr.Port, lr.Image
+	if name == "" {
+		name = defaultLocalRegistryName
+	}
+	if port == 0 {
+		port = defaultLocalRegistryPort
+	}
+	if image == "" {
+		image = defaultLocalRegistryImage
+	}
+	return name, port, image
+}
+
+// ensureLocalRegistry starts the local registry container if it is not already running
+// and connects it to the kind network. The container is shared across clusters and
+// topologies, and is intentionally left running when a topology is deleted so its
+// cache survives.
+func ensureLocalRegistry(ctx context.Context, lr *config.LocalRegistry) error {
+	name, port, image := localRegistryDefaults(lr)
+
+	out, err := exec.CommandContext(ctx, "docker", "inspect", "-f", "{{.State.Running}}", name).Output()
+	running := err == nil && strings.TrimSpace(string(out)) == "true"
+
+	if !running {
+		if err == nil {
+			// Container exists but is stopped
+			if out, err := exec.CommandContext(ctx, "docker", "start", name).CombinedOutput(); err != nil {
+				return fmt.Errorf("failed to start local registry '%s': %w: %s", name, err, out)
+			}
+		} else {
+			fmt.Printf("Starting local registry '%s' on localhost:%d...\n", name, port)
+			args := []string{"run", "-d", "--restart=always",
+				"-p", fmt.Sprintf("127.0.0.1:%d:5000", port),
+				"--name", name,
+			}
+			if lr.RemoteURL != "" {
+				args = append(args, "-e", "REGISTRY_PROXY_REMOTEURL="+lr.RemoteURL)
+			}
+			args = append(args, image)
+			if out, err := exec.CommandContext(ctx, "docker", args...).CombinedOutput(); err != nil {
+				return fmt.Errorf("failed to run local registry '%s': %w: %s", name, err, out)
+			}
+		}
+	}
+
+	// Connect to the kind network so nodes can resolve the registry by container name
+	out, err = exec.CommandContext(ctx, "docker", "network", "connect", kindNetwork, name).CombinedOutput()
+	if err != nil && !strings.Contains(string(out), "already exists") {
+		return fmt.Errorf("failed to connect local registry '%s' to network %s: %w: %s", name, kindNetwork, err, out)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// retryBackoff is the backoff applied to flaky kind operations: 4 attempts
+// spaced roughly 5s, 10s and 20s apart.
+var retryBackoff = wait.Backoff{
+	Duration: 5 * time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    4,
+}
+
+// transientErrorPatterns are substrings of kind/Docker errors known to succeed on retry.
+// They are kept specific: bare "EOF" or "connection refused" also match permanent
+// failures, such as a bad kubeconfig, which would then be retried for the whole backoff.
+var transientErrorPatterns = []string{
+	"port is already allocated",
+	"address already in use",
+	"failed to connect to containerd",
+	"containerd is not running",
+	"could not find a log line that matches",
+	"is already in use by container",
+	"connection reset by peer",
+	"TLS handshake timeout",
+	"i/o timeout",
+	"unexpected EOF",
+}
+
+// isTransient reports whether err matches a known transient kind/Docker failure.
+func isTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	for _, pattern := range transientErrorPatterns {
+		if strings.Contains(msg, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// withRetry runs fn, retrying with exponential backoff while it returns transient errors.
+// cleanup (optional) runs before each retry to undo partial work, e.g. a half-created cluster.
+// Non-transient errors and context cancellation are returned immediately.
+func withRetry(ctx context.Context, op string, fn func() error, cleanup func()) error {
+	backoff := retryBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if !isTransient(err) || backoff.Steps <= 1 {
+			return err
+		}
+
+		delay := backoff.Step()
+		fmt.Fprintf(os.Stderr, "Warning: %s failed (attempt %d), retrying in %s: %v\n",
+			op, attempt, delay.Round(time.Second), err)
+
+		if cleanup != nil {
+			cleanup()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%s: %w (last error: %v)", op, ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
+
+// untilCancelled runs fn, a kind operation that takes no context, and returns as soon as
+// ctx is cancelled, leaving fn to finish in the background. abort (optional) runs on
+// cancellation to undo fn's work, e.g. delete a cluster being created; since fn cannot be
+// interrupted, untilCancelled then waits for fn to return first, so fn cannot recreate
+// what abort removed.
+func untilCancelled(ctx context.Context, fn func() error, abort func()) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if abort != nil {
+			fmt.Fprintln(os.Stderr, "Cancelled; waiting for the operation in progress to stop before cleaning up...")
+			<-done
+			abort()
+		}
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,188 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil error",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "port conflict",
+			err:  errors.New("Bind for 127.0.0.1:6443 failed: port is already allocated"),
+			want: true,
+		},
+		{
+			name: "containerd not ready",
+			err:  errors.New("failed to connect to containerd: context deadline exceeded"),
+			want: true,
+		},
+		{
+			name: "node boot timeout",
+			err:  errors.New(`could not find a log line that matches "Reached target .*Multi-User System.*"`),
+			want: true,
+		},
+		{
+			name: "truncated image load",
+			err:  errors.New("failed to load image: command \"docker save\" failed: unexpected EOF"),
+			want: true,
+		},
+		{
+			name: "permanent failure",
+			err:  errors.New("node image kindest/node:v9.9.9 not found"),
+			want: false,
+		},
+		{
+			name: "bad kubeconfig",
+			err:  errors.New(`Get "https://127.0.0.1:6443/api": dial tcp 127.0.0.1:6443: connect: connection refused`),
+			want: false,
+		},
+		{
+			name: "docker daemon stopped",
+			err:  errors.New("Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?"),
+			want: false,
+		},
+		{
+			name: "bare EOF",
+			err:  errors.New("failed to read kubeconfig: EOF"),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransient(tt.err); got != tt.want {
+				t.Errorf("isTransient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	// Use a near-zero backoff so tests run quickly
+	orig := retryBackoff
+	retryBackoff = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 3}
+	t.Cleanup(func() { retryBackoff = orig })
+
+	t.Run("succeeds after transient failures", func(t *testing.T) {
+		calls, cleanups := 0, 0
+		err := withRetry(context.Background(), "op", func() error {
+			calls++
+			if calls < 3 {
+				return errors.New("port is already allocated")
+			}
+			return nil
+		}, func() { cleanups++ })
+		if err != nil {
+			t.Fatalf("withRetry() error = %v", err)
+		}
+		if calls != 3 {
+			t.Errorf("calls = %d, want 3", calls)
+		}
+		if cleanups != 2 {
+			t.Errorf("cleanups = %d, want 2", cleanups)
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		calls := 0
+		err := withRetry(context.Background(), "op", func() error {
+			calls++
+			return errors.New("connection reset by peer")
+		}, nil)
+		if err == nil {
+			t.Fatal("withRetry() expected error")
+		}
+		if calls != 3 {
+			t.Errorf("calls = %d, want 3", calls)
+		}
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		calls := 0
+		err := withRetry(context.Background(), "op", func() error {
+			calls++
+			return errors.New(`dial tcp 127.0.0.1:6443: connect: connection refused`)
+		}, nil)
+		if err == nil {
+			t.Fatal("withRetry() expected error")
+		}
+		if calls != 1 {
+			t.Errorf("calls = %d, want 1", calls)
+		}
+	})
+
+	t.Run("stops on context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := withRetry(ctx, "op", func() error {
+			return errors.New("connection reset by peer")
+		}, nil)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("withRetry() error = %v, want context.Canceled", err)
+		}
+	})
+}
+
+func TestUntilCancelled(t *testing.T) {
+	t.Run("returns the operation's result", func(t *testing.T) {
+		want := errors.New("invalid configuration")
+		err := untilCancelled(context.Background(), func() error { return want }, nil)
+		if !errors.Is(err, want) {
+			t.Errorf("untilCancelled() error = %v, want %v", err, want)
+		}
+	})
+
+	t.Run("returns on cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		release := make(chan struct{})
+		defer close(release)
+		time.AfterFunc(10*time.Millisecond, cancel)
+		err := untilCancelled(ctx, func() error {
+			<-release
+			return nil
+		}, nil)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("untilCancelled() error = %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("aborts after fn returns", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		var finished, abortedAfterFinish atomic.Bool
+		aborted := false
+		err := untilCancelled(ctx, func() error {
+			<-ctx.Done()
+			time.Sleep(20 * time.Millisecond)
+			finished.Store(true)
+			return nil
+		}, func() {
+			aborted = true
+			abortedAfterFinish.Store(finished.Load())
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("untilCancelled() error = %v, want context.Canceled", err)
+		}
+		if !aborted {
+			t.Error("abort was not called on cancellation")
+		}
+		if !abortedAfterFinish.Load() {
+			t.Error("abort ran while fn was still running")
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jhwagner/kueue-bench/pkg/state"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
+	kindexec "sigs.k8s.io/kind/pkg/exec"
+)
+
+// unsafeSnapshotKeyChars matches characters not allowed in snapshot file names
+var unsafeSnapshotKeyChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// SnapshotKey returns the golden snapshot key for a cluster built from the given
+// component versions. Clusters with the same key pull an identical set of images.
+func SnapshotKey(kubernetesVersion, kwokVersion, kueueVersion string) string {
+	if kubernetesVersion == "" {
+		kubernetesVersion = "default"
+	}
+	key := fmt.Sprintf("k8s-%s_kwok-%s_kueue-%s", kubernetesVersion, kwokVersion, kueueVersion)
+	return unsafeSnapshotKeyChars.ReplaceAllString(key, "-")
+}
+
+// HasSnapshot reports whether a golden snapshot exists for key in store.
+func HasSnapshot(store *state.Store, key string) (bool, error) {
+	path := snapshotPath(store, key)
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to stat snapshot: %w", err)
+	}
+	return true, nil
+}
+
+// ListImages returns the image references present in the cluster's containerd image store.
+// It is used to capture the baseline image set of a freshly created node before
+// components are installed, so that only the delta is snapshotted.
+func ListImages(ctx context.Context, name string) ([]string, error) {
+	node, err := controlPlaneNode(name)
+	if err != nil {
+		return nil, err
+	}
+	return listNodeImages(ctx, node)
+}
+
+// SaveSnapshot exports every image in the cluster that is not in baseline into a
+// golden snapshot archive for key.
+//
+// kind keeps /var (and therefore containerd's image store) on a volume, so committing
+// the node container would not capture pulled images. Instead the snapshot holds the
+// images pulled while installing KWOK, Kueue and extensions, which dominate setup time.
+func SaveSnapshot(ctx context.Context, store *state.Store, name, key string, baseline []string) error {
+	node, err := controlPlaneNode(name)
+	if err != nil {
+		return err
+	}
+
+	images, err := listNodeImages(ctx, node)
+	if err != nil {
+		return err
+	}
+	skip := make(map[string]bool, len(baseline))
+	for _, img := range baseline {
+		skip[img] = true
+	}
+	var refs []string
+	for _, img := range images {
+		if !skip[img] {
+			refs = append(refs, img)
+		}
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	path := snapshotPath(store, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	// Write to a temp file and rename so a partial export is never mistaken for a snapshot
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	fmt.Printf("Saving golden snapshot '%s' (%d images)...\n", key, len(refs))
+	args := append([]string{"--namespace=k8s.io", "images", "export", "-"}, refs...)
+	if err := node.CommandContext(ctx, "ctr", args...).SetStdout(tmp).Run(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to export images: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	fmt.Printf("✓ Golden snapshot '%s' saved\n", key)
+	return nil
+}
+
+// RestoreSnapshot imports the golden snapshot for key into every node of the cluster,
+// so subsequent component installs find their images locally instead of pulling them.
+func RestoreSnapshot(ctx context.Context, store *state.Store, name, key string) error {
+	path := snapshotPath(store, key)
+
+	kindNodes, err := getProvider().ListInternalNodes(name)
+	if err != nil {
+		return fmt.Errorf("failed to list nodes for cluster '%s': %w", name, err)
+	}
+
+	fmt.Printf("Restoring golden snapshot '%s' into cluster '%s'...\n", key, name)
+	for _, node := range kindNodes {
+		if err := loadImageArchive(ctx, node, path); err != nil {
+			return fmt.Errorf("failed to restore snapshot into node %s: %w", node.String(), err)
+		}
+	}
+
+	fmt.Printf("✓ Golden snapshot '%s' restored\n", key)
+	return nil
+}
+
+// controlPlaneNode returns the control-plane node of a kind cluster.
+func controlPlaneNode(name string) (nodes.Node, error) {
+	kindNodes, err := getProvider().ListInternalNodes(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes for cluster '%s': %w", name, err)
+	}
+	node, err := nodeutils.BootstrapControlPlaneNode(kindNodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find control-plane node for cluster '%s': %w", name, err)
+	}
+	return node, nil
+}
+
+// listNodeImages returns the sorted, named (non-digest) image references on a node.
+func listNodeImages(ctx context.Context, node nodes.Node) ([]string, error) {
+	lines, err := kindexec.OutputLines(node.CommandContext(ctx, "ctr", "--namespace=k8s.io", "images", "list", "-q"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images on node %s: %w", node.String(), err)
+	}
+
+	var images []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "sha256:") {
+			continue
+		}
+		images = append(images, line)
+	}
+	sort.Strings(images)
+	return images, nil
+}
+
+// snapshotPath returns the archive path for a snapshot key in store.
+func snapshotPath(store *state.Store, key string) string {
+	return store.Path(state.Snapshots, key+".tar")
+}
@@ -0,0 +1,42 @@
+package cluster
+
+import "testing"
+
+func TestSnapshotKey(t *testing.T) {
+	tests := []struct {
+		name              string
+		kubernetesVersion string
+		kwokVersion       string
+		kueueVersion      string
+		want              string
+	}{
+		{
+			name:              "all versions set",
+			kubernetesVersion: "v1.31.0",
+			kwokVersion:       "v0.7.0",
+			kueueVersion:      "v0.17.0",
+			want:              "k8s-v1.31.0_kwok-v0.7.0_kueue-v0.17.0",
+		},
+		{
+			name:         "default kubernetes version",
+			kwokVersion:  "v0.7.0",
+			kueueVersion: "v0.17.0",
+			want:         "k8s-default_kwok-v0.7.0_kueue-v0.17.0",
+		},
+		{
+			name:              "unsafe characters replaced",
+			kubernetesVersion: "kindest/node:v1.31.0",
+			kwokVersion:       "v0.7.0",
+			kueueVersion:      "v0.17.0",
+			want:              "k8s-kindest-node-v1.31.0_kwok-v0.7.0_kueue-v0.17.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SnapshotKey(tt.kubernetesVersion, tt.kwokVersion, tt.kueueVersion); got != tt.want {
+				t.Errorf("SnapshotKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/jhwagner/kueue-bench/pkg/helm"
+)
+
+// Extension presets
+const (
+	PresetJobSet          = "jobset"
+	PresetKubeRay         = "kuberay"
+	PresetKubeflowTrainer = "kubeflow-trainer"
+	PresetLWS             = "lws"
+	PresetCertManager     = "cert-manager"
+)
+
+// extensionPresets are pinned Helm installs of controllers commonly used with Kueue
+var extensionPresets = map[string]HelmExtension{
+	PresetJobSet: {
+		Chart:     "oci://registry.k8s.io/jobset/charts/jobset",
+		Version:   "0.12.0",
+		Namespace: "jobset-system",
+	},
+	PresetKubeRay: {
+		Chart:     "kuberay-operator",
+		Repo:      "https://ray-project.github.io/kuberay-helm/",
+		Version:   "1.4.2",
+		Namespace: "kuberay-system",
+	},
+	// Kubeflow Trainer v2 serves TrainJobs only, not the Training Operator's kubeflow.org/v1
+	// PyTorchJobs and TFJobs
+	PresetKubeflowTrainer: {
+		Chart:     "oci://ghcr.io/kubeflow/charts/kubeflow-trainer",
+		Version:   "2.0.0",
+		Namespace: "kubeflow-system",
+	},
+	PresetLWS: {
+		Chart:     "oci://registry.k8s.io/lws/charts/lws",
+		Version:   "0.7.0",
+		Namespace: "lws-system",
+	},
+	PresetCertManager: {
+		Chart:     "oci://quay.io/jetstack/charts/cert-manager",
+		Version:   "v1.18.2",
+		Namespace: "cert-manager",
+		Values:    map[string]interface{}{"crds": map[string]interface{}{"enabled": true}},
+	},
+}
+
+// ExtensionPresets returns the names of the built-in extension presets, sorted
+func ExtensionPresets() []string {
+	return slices.Sorted(maps.Keys(extensionPresets))
+}
+
+// ResolvePreset returns the extension with its preset expanded into a Helm install.
+// Fields of the extension's own helm block override the preset's; values are merged into
+// the preset's recursively, as Helm merges values files. Extensions without a preset are
+// returned unchanged.
+func (e Extension) ResolvePreset() (Extension, error) {
+	if e.Preset == "" {
+		return e, nil
+	}
+	preset, ok := extensionPresets[e.Preset]
+	if !ok {
+		return Extension{}, fmt.Errorf("unknown preset '%s' (must be one of %s)", e.Preset, strings.Join(ExtensionPresets(), ", "))
+	}
+
+	resolved := preset
+	resolved.Values = maps.Clone(preset.Values)
+	if h := e.Helm; h != nil {
+		for _, field := range []struct{ dst, src *string }{
+			{&resolved.Chart, &h.Chart},
+			{&resolved.Repo, &h.Repo},
+			{&resolved.Version, &h.Version},
+			{&resolved.ReleaseName, &h.ReleaseName},
+			{&resolved.Namespace, &h.Namespace},
+			{&resolved.Timeout, &h.Timeout},
+			{&resolved.ValuesFile, &h.ValuesFile},
+		} {
+			if *field.src != "" {
+				*field.dst = *field.src
+			}
+		}
+		if h.CreateNamespace != nil {
+			resolved.CreateNamespace = h.CreateNamespace
+		}
+		if h.Wait != nil {
+			resolved.Wait = h.Wait
+		}
+		if h.Auth != nil {
+			resolved.Auth = h.Auth
+		}
+		if len(h.Values) > 0 {
+			resolved.Values = helm.MergeValues(preset.Values, h.Values)
+		}
+		resolved.Set = maps.Clone(h.Set)
+	}
+
+	e.Helm = &resolved
+	return e, nil
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jhwagner/kueue-bench/pkg/manifest"
+)
+
+const kwokStageAPIVersion = "kwok.x-k8s.io/v1alpha1"
+
+// Documents returns the stage's YAML documents, reading File if set.
+// Multiple stages may be given in one source, separated by "---".
+func (s KwokStage) Documents() ([][]byte, error) {
+	data := []byte(s.Inline)
+	if s.File != "" {
+		var err error
+		data, err = os.ReadFile(s.File) //nolint:gosec // path comes from the user's topology file
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stage file: %w", err)
+		}
+	}
+
+	docs, err := manifest.SplitDocuments(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split stage documents: %w", err)
+	}
+	return docs, nil
+}
+
+// KwokStageName returns the name of a KWOK Stage manifest, or an error if the
+// document is not a named Stage
+func KwokStageName(doc []byte) (string, error) {
+	var header struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+		Metadata   struct {
+			Name string `yaml:"name"`
+		} `yaml:"metadata"`
+	}
+	if err := yaml.Unmarshal(doc, &header); err != nil {
+		return "", fmt.Errorf("failed to parse stage: %w", err)
+	}
+	if header.APIVersion != kwokStageAPIVersion || header.Kind != "Stage" {
+		return "", fmt.Errorf("expected %s Stage, got %s %s", kwokStageAPIVersion, header.APIVersion, header.Kind)
+	}
+	if header.Metadata.Name == "" {
+		return "", fmt.Errorf("stage metadata.name is required")
+	}
+	return header.Metadata.Name, nil
+}
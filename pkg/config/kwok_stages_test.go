@@ -0,0 +1,55 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKwokStageDocuments(t *testing.T) {
+	tests := []struct {
+		name   string
+		inline string
+		want   []string
+	}{
+		{
+			name:   "single stage",
+			inline: "kind: Stage\nmetadata:\n  name: a\n",
+			want:   []string{"a"},
+		},
+		{
+			name:   "leading separator",
+			inline: "---\nkind: Stage\nmetadata:\n  name: a\n---\nkind: Stage\nmetadata:\n  name: b\n",
+			want:   []string{"a", "b"},
+		},
+		{
+			name:   "CRLF line endings",
+			inline: "kind: Stage\r\nmetadata:\r\n  name: a\r\n---\r\nkind: Stage\r\nmetadata:\r\n  name: b\r\n",
+			want:   []string{"a", "b"},
+		},
+		{
+			name:   "separator with comment and empty documents",
+			inline: "kind: Stage\nmetadata:\n  name: a\n--- # second stage\nkind: Stage\nmetadata:\n  name: b\n---\n# nothing here\n---\n",
+			want:   []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := KwokStage{Inline: tt.inline}.Documents()
+			if err != nil {
+				t.Fatalf("Documents() error = %v", err)
+			}
+			var names []string
+			for _, doc := range docs {
+				for _, line := range strings.Split(string(doc), "\n") {
+					if name, ok := strings.CutPrefix(strings.TrimSpace(line), "name: "); ok {
+						names = append(names, name)
+					}
+				}
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Documents() names = %v, want %v (docs %q)", names, tt.want, docs)
+			}
+		})
+	}
+}
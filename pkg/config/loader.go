@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"gopkg.in/yaml.v3"
 )
@@ -13,21 +14,146 @@ func loadYAML[T any](path, typeName string) (*T, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read %s file: %w", typeName, err)
 	}
+	return parseYAML[T](data, typeName)
+}
 
+// parseYAML unmarshals YAML (or JSON) data into a value of type T.
+func parseYAML[T any](data []byte, typeName string) (*T, error) {
 	var result T
 	if err := yaml.Unmarshal(data, &result); err != nil {
 		return nil, fmt.Errorf("failed to parse %s YAML: %w", typeName, err)
 	}
-
 	return &result, nil
 }
 
-// LoadTopology loads and parses a topology configuration file
+// LoadTopology loads and parses a topology configuration file.
+// Relative file references (e.g. KWOK stage files) are resolved against the file's directory.
 func LoadTopology(path string) (*Topology, error) {
-	return loadYAML[Topology](path, "topology")
+	data, err := os.ReadFile(path) //nolint:gosec // filepath is user-provided CLI input, not untrusted
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topology file: %w", err)
+	}
+	return ParseTopology(data, filepath.Dir(path))
+}
+
+// ParseTopology parses a topology configuration, e.g. one received over the API.
+// Relative file references are resolved against dir.
+func ParseTopology(data []byte, dir string) (*Topology, error) {
+	t, err := parseYAML[Topology](data, "topology")
+	if err != nil {
+		return nil, err
+	}
+
+	if k := t.Spec.Kueue; k != nil {
+		k.Chart = resolvePath(k.Chart, dir)
+		k.Manifest = resolvePath(k.Manifest, dir)
+		if k.BuildFrom != nil {
+			k.BuildFrom.Path = resolvePath(k.BuildFrom.Path, dir)
+		}
+	}
+	for i := range t.Spec.Clusters {
+		resolveKwokStagePaths(t.Spec.Clusters[i].Kwok, dir)
+		resolveExtensionPaths(t.Spec.Clusters[i].Extensions, dir)
+	}
+	resolveExtensionPaths(t.Spec.Extensions, dir)
+	for i := range t.Spec.WorkerSets {
+		ws := &t.Spec.WorkerSets[i]
+		resolveKwokStagePaths(ws.Kwok, dir)
+		resolveExtensionPaths(ws.Extensions, dir)
+		for j := range ws.Workers {
+			ws.Workers[j].Kubeconfig = resolvePath(ws.Workers[j].Kubeconfig, dir)
+		}
+	}
+
+	return t, nil
+}
+
+// CheckKueueSource checks that the local chart, manifest or source checkout Kueue is
+// installed from exists. It is called when a topology is created rather than during
+// validation, so that validation doesn't depend on the filesystem.
+func CheckKueueSource(k *KueueSettings) error {
+	if k == nil {
+		return nil
+	}
+	for field, path := range map[string]string{"chart": k.Chart, "manifest": k.Manifest} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("kueue: %s: %w", field, err)
+		}
+	}
+	if b := k.BuildFrom; b != nil {
+		if _, err := os.Stat(filepath.Join(b.Path, KueueChartDir)); err != nil {
+			return fmt.Errorf("kueue.buildFrom: %s is not a Kueue checkout: %w", b.Path, err)
+		}
+	}
+	return nil
+}
+
+// resolveKwokStagePaths makes relative stage file paths relative to dir
+func resolveKwokStagePaths(k *ClusterKwokConfig, dir string) {
+	if k == nil {
+		return
+	}
+	for i := range k.Stages {
+		k.Stages[i].File = resolvePath(k.Stages[i].File, dir)
+	}
+}
+
+// resolveExtensionPaths makes relative Helm values and auth files, manifest and kustomization paths
+// relative to dir
+func resolveExtensionPaths(extensions []Extension, dir string) {
+	for i := range extensions {
+		if h := extensions[i].Helm; h != nil {
+			h.ValuesFile = resolvePath(h.ValuesFile, dir)
+			if h.Auth != nil {
+				h.Auth.ConfigFile = resolvePath(h.Auth.ConfigFile, dir)
+			}
+		}
+		if m := extensions[i].Manifest; m != nil {
+			m.Path = resolvePath(m.Path, dir)
+		}
+		if k := extensions[i].Kustomize; k != nil {
+			k.Path = resolvePath(k.Path, dir)
+		}
+		for j := range extensions[i].PostInstall {
+			if m := extensions[i].PostInstall[j].Manifest; m != nil {
+				m.Path = resolvePath(m.Path, dir)
+			}
+		}
+	}
+}
+
+// resolvePath makes a non-empty relative path relative to dir and absolute, so it stays
+// valid when the loaded topology is saved elsewhere
+func resolvePath(path, dir string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	resolved := filepath.Join(dir, path)
+	if abs, err := filepath.Abs(resolved); err == nil {
+		return abs
+	}
+	return resolved
 }
 
 // LoadWorkloadProfile loads and parses a workload profile configuration file
 func LoadWorkloadProfile(path string) (*WorkloadProfile, error) {
 	return loadYAML[WorkloadProfile](path, "workload profile")
 }
+
+// ParseWorkloadProfile parses a workload profile configuration
+func ParseWorkloadProfile(data []byte) (*WorkloadProfile, error) {
+	return parseYAML[WorkloadProfile](data, "workload profile")
+}
+
+// LoadKueueConfig loads a Kueue object configuration file, such as the one recorded for
+// each cluster of a topology. LocalQueue generators are expanded.
+func LoadKueueConfig(path string) (*KueueConfig, error) {
+	k, err := loadYAML[KueueConfig](path, "Kueue config")
+	if err != nil {
+		return nil, err
+	}
+	return k.WithGeneratedLocalQueues(), nil
+}
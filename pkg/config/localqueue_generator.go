@@ -0,0 +1,151 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// maxGeneratedLocalQueues caps the LocalQueues one generator may generate, so a typo in
+// its counts fails validation instead of exhausting memory generating them
+const maxGeneratedLocalQueues = 100000
+
+// QueueName expands a LocalQueueGenerator naming pattern, replacing {cq}, {ns} and {lq}
+// with the ClusterQueue name and the namespace and queue indices
+func QueueName(pattern, clusterQueue string, namespace, queue int) string {
+	return strings.NewReplacer(
+		"{cq}", clusterQueue,
+		"{ns}", strconv.Itoa(namespace),
+		"{lq}", strconv.Itoa(queue),
+	).Replace(pattern)
+}
+
+// patterns returns the generator's naming patterns, defaulted
+func (g LocalQueueGenerator) patterns() (namespace, queue string) {
+	namespace, queue = g.NamespacePattern, g.QueuePattern
+	if namespace == "" {
+		namespace = DefaultNamespacePattern
+	}
+	if queue == "" {
+		queue = DefaultQueuePattern
+	}
+	return namespace, queue
+}
+
+// Generate returns the namespaces and LocalQueues of the generator, namespace by namespace
+func (g LocalQueueGenerator) Generate() ([]Namespace, []LocalQueue) {
+	nsPattern, lqPattern := g.patterns()
+	namespaces := make([]Namespace, 0, g.Namespaces)
+	localQueues := make([]LocalQueue, 0, g.Namespaces*g.QueuesPerNamespace)
+	for i := range g.Namespaces {
+		ns := QueueName(nsPattern, g.ClusterQueue, i, 0)
+		namespaces = append(namespaces, Namespace{Name: ns, Labels: g.NamespaceLabels})
+		for j := range g.QueuesPerNamespace {
+			localQueues = append(localQueues, LocalQueue{
+				Name:         QueueName(lqPattern, g.ClusterQueue, i, j),
+				Namespace:    ns,
+				ClusterQueue: g.ClusterQueue,
+			})
+		}
+	}
+	return namespaces, localQueues
+}
+
+// WithGeneratedLocalQueues returns the config with the namespaces and LocalQueues of its
+// localQueueGenerators appended and the generators removed, so it can be provisioned,
+// recorded and compared like one listing them. Generated namespaces already declared in
+// namespaces keep their declared settings. k is not modified and is returned as is when it
+// has no generators.
+func (k *KueueConfig) WithGeneratedLocalQueues() *KueueConfig {
+	if k == nil || len(k.LocalQueueGenerators) == 0 {
+		return k
+	}
+	expanded := *k
+	expanded.LocalQueueGenerators = nil
+	expanded.Namespaces = append([]Namespace(nil), k.Namespaces...)
+	expanded.LocalQueues = append([]LocalQueue(nil), k.LocalQueues...)
+
+	declared := make(map[string]bool, len(k.Namespaces))
+	for _, ns := range k.Namespaces {
+		declared[ns.Name] = true
+	}
+	for _, g := range k.LocalQueueGenerators {
+		namespaces, localQueues := g.Generate()
+		for _, ns := range namespaces {
+			// Without labels, LocalQueue namespaces are created anyway
+			if !declared[ns.Name] && len(ns.Labels) > 0 {
+				expanded.Namespaces = append(expanded.Namespaces, ns)
+				declared[ns.Name] = true
+			}
+		}
+		expanded.LocalQueues = append(expanded.LocalQueues, localQueues...)
+	}
+	return &expanded
+}
+
+// validateLocalQueueGenerators checks the generators against the config's ClusterQueues,
+// and that the LocalQueues they generate have valid names unique across the config
+func validateLocalQueueGenerators(k *KueueConfig, clusterQueueNames map[string]bool) error {
+	queues := make(map[string]bool, len(k.LocalQueues))
+	for _, lq := range k.LocalQueues {
+		queues[lq.Namespace+"/"+lq.Name] = true
+	}
+	for i, g := range k.LocalQueueGenerators {
+		if err := validateLocalQueueGenerator(g, clusterQueueNames, queues); err != nil {
+			return fmt.Errorf("localQueueGenerator[%d] (%s): %w", i, g.ClusterQueue, err)
+		}
+	}
+	return nil
+}
+
+// validateLocalQueueGenerator checks a generator, adding the LocalQueues it generates to queues
+func validateLocalQueueGenerator(g LocalQueueGenerator, clusterQueueNames, queues map[string]bool) error {
+	switch {
+	case g.ClusterQueue == "":
+		return fmt.Errorf("clusterQueue is required")
+	case !clusterQueueNames[g.ClusterQueue]:
+		return fmt.Errorf("unknown clusterQueue '%s'", g.ClusterQueue)
+	case g.Namespaces < 1:
+		return fmt.Errorf("namespaces must be at least 1")
+	case g.QueuesPerNamespace < 1:
+		return fmt.Errorf("queuesPerNamespace must be at least 1")
+	case g.QueuesPerNamespace > maxGeneratedLocalQueues/g.Namespaces:
+		return fmt.Errorf("namespaces × queuesPerNamespace (%d × %d) exceeds the limit of %d generated LocalQueues",
+			g.Namespaces, g.QueuesPerNamespace, maxGeneratedLocalQueues)
+	}
+	nsPattern, lqPattern := g.patterns()
+	if g.Namespaces > 1 && !strings.Contains(nsPattern, "{ns}") {
+		return fmt.Errorf("namespacePattern '%s' must contain {ns} to name %d namespaces", nsPattern, g.Namespaces)
+	}
+	if g.QueuesPerNamespace > 1 && !strings.Contains(lqPattern, "{lq}") {
+		return fmt.Errorf("queuePattern '%s' must contain {lq} to name %d queues per namespace", lqPattern, g.QueuesPerNamespace)
+	}
+	for _, key := range sortedKeys(g.NamespaceLabels) {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("namespaceLabels: invalid label key '%s': %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(g.NamespaceLabels[key]); len(errs) > 0 {
+			return fmt.Errorf("namespaceLabels: invalid label value '%s': %s", g.NamespaceLabels[key], strings.Join(errs, "; "))
+		}
+	}
+
+	namespaces, localQueues := g.Generate()
+	for _, ns := range namespaces {
+		if errs := validation.IsDNS1123Label(ns.Name); len(errs) > 0 {
+			return fmt.Errorf("generated namespace '%s' is invalid: %s", ns.Name, strings.Join(errs, "; "))
+		}
+	}
+	for _, lq := range localQueues {
+		if errs := validation.IsDNS1123Subdomain(lq.Name); len(errs) > 0 {
+			return fmt.Errorf("generated LocalQueue '%s' is invalid: %s", lq.Name, strings.Join(errs, "; "))
+		}
+		key := lq.Namespace + "/" + lq.Name
+		if queues[key] {
+			return fmt.Errorf("generated LocalQueue %s is already defined", key)
+		}
+		queues[key] = true
+	}
+	return nil
+}
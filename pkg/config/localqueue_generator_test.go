@@ -0,0 +1,52 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithGeneratedLocalQueues(t *testing.T) {
+	k := &KueueConfig{
+		LocalQueues: []LocalQueue{{Name: "lq", Namespace: "default", ClusterQueue: "cq"}},
+		Namespaces:  []Namespace{{Name: "batch-team-1", Labels: map[string]string{"declared": "true"}}},
+		LocalQueueGenerators: []LocalQueueGenerator{{
+			ClusterQueue:       "batch",
+			Namespaces:         2,
+			QueuesPerNamespace: 2,
+			NamespacePattern:   "{cq}-team-{ns}",
+			NamespaceLabels:    map[string]string{"tier": "batch"},
+		}},
+	}
+
+	expanded := k.WithGeneratedLocalQueues()
+	if len(expanded.LocalQueueGenerators) != 0 {
+		t.Error("generators are kept in the expanded config")
+	}
+	var queues []string
+	for _, lq := range expanded.LocalQueues {
+		queues = append(queues, lq.Namespace+"/"+lq.Name+"→"+lq.ClusterQueue)
+	}
+	wantQueues := []string{
+		"default/lq→cq",
+		"batch-team-0/batch-lq-0→batch", "batch-team-0/batch-lq-1→batch",
+		"batch-team-1/batch-lq-0→batch", "batch-team-1/batch-lq-1→batch",
+	}
+	if !reflect.DeepEqual(queues, wantQueues) {
+		t.Errorf("LocalQueues = %v, want %v", queues, wantQueues)
+	}
+	// The declared namespace keeps its labels; the other generated one gets the generator's
+	wantNamespaces := []Namespace{
+		{Name: "batch-team-1", Labels: map[string]string{"declared": "true"}},
+		{Name: "batch-team-0", Labels: map[string]string{"tier": "batch"}},
+	}
+	if !reflect.DeepEqual(expanded.Namespaces, wantNamespaces) {
+		t.Errorf("Namespaces = %v, want %v", expanded.Namespaces, wantNamespaces)
+	}
+
+	if len(k.LocalQueues) != 1 || len(k.Namespaces) != 1 || len(k.LocalQueueGenerators) != 1 {
+		t.Error("WithGeneratedLocalQueues() modified its receiver")
+	}
+	if plain := (&KueueConfig{}); plain.WithGeneratedLocalQueues() != plain {
+		t.Error("a config without generators should be returned as is")
+	}
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migResourcePrefix is the extended resource prefix the NVIDIA device plugin uses for
+// MIG devices under the "mixed" strategy, e.g. nvidia.com/mig-1g.5gb
+const migResourcePrefix = "nvidia.com/mig-"
+
+// migSlices is the number of compute and memory slices a MIG profile occupies on a GPU
+type migSlices struct {
+	compute int
+	memory  int
+}
+
+// migModel describes a MIG-capable GPU: its slice counts and supported profiles
+type migModel struct {
+	computeSlices int
+	memorySlices  int
+	profiles      map[string]migSlices
+}
+
+// migModels lists MIG-capable GPUs by model name, from NVIDIA's MIG user guide
+var migModels = map[string]migModel{
+	"a30-24gb": {computeSlices: 4, memorySlices: 4, profiles: map[string]migSlices{
+		"1g.6gb": {1, 1}, "2g.12gb": {2, 2}, "4g.24gb": {4, 4},
+	}},
+	"a100-40gb": {computeSlices: 7, memorySlices: 8, profiles: map[string]migSlices{
+		"1g.5gb": {1, 1}, "1g.10gb": {1, 2}, "2g.10gb": {2, 2}, "3g.20gb": {3, 4}, "4g.20gb": {4, 4}, "7g.40gb": {7, 8},
+	}},
+	"a100-80gb": {computeSlices: 7, memorySlices: 8, profiles: map[string]migSlices{
+		"1g.10gb": {1, 1}, "1g.20gb": {1, 2}, "2g.20gb": {2, 2}, "3g.40gb": {3, 4}, "4g.40gb": {4, 4}, "7g.80gb": {7, 8},
+	}},
+	"h100-80gb": {computeSlices: 7, memorySlices: 8, profiles: map[string]migSlices{
+		"1g.10gb": {1, 1}, "1g.20gb": {1, 2}, "2g.20gb": {2, 2}, "3g.40gb": {3, 4}, "4g.40gb": {4, 4}, "7g.80gb": {7, 8},
+	}},
+	"h100-94gb": {computeSlices: 7, memorySlices: 8, profiles: map[string]migSlices{
+		"1g.12gb": {1, 1}, "1g.24gb": {1, 2}, "2g.24gb": {2, 2}, "3g.47gb": {3, 4}, "4g.47gb": {4, 4}, "7g.94gb": {7, 8},
+	}},
+	"h200-141gb": {computeSlices: 7, memorySlices: 8, profiles: map[string]migSlices{
+		"1g.18gb": {1, 1}, "1g.35gb": {1, 2}, "2g.35gb": {2, 2}, "3g.71gb": {3, 4}, "4g.71gb": {4, 4}, "7g.141gb": {7, 8},
+	}},
+}
+
+// MIGResources returns the MIG extended resources advertised by each node of the pool:
+// nvidia.com/mig-<profile> set to instances per GPU times GPUs. Returns nil without MIG.
+func (p *NodePool) MIGResources() map[string]string {
+	if p.MIG == nil {
+		return nil
+	}
+	resources := make(map[string]string, len(p.MIG.Profiles))
+	for profile, perGPU := range p.MIG.Profiles {
+		resources[migResourcePrefix+profile] = strconv.Itoa(perGPU * p.MIG.GPUs)
+	}
+	return resources
+}
+
+// DefaultPodsCapacity is the pods capacity of nodes whose pool doesn't set one, the
+// kubelet's default max-pods
+const DefaultPodsCapacity = "110"
+
+// Capacity returns the pool's per-node capacity: Resources plus any generated MIG
+// resources, and DefaultPodsCapacity pods unless Resources sets pods
+func (p *NodePool) Capacity() map[string]string {
+	capacity := make(map[string]string, len(p.Resources)+1)
+	for name, quantity := range p.Resources {
+		capacity[name] = quantity
+	}
+	for name, quantity := range p.MIGResources() {
+		capacity[name] = quantity
+	}
+	if _, ok := capacity["pods"]; !ok {
+		capacity["pods"] = DefaultPodsCapacity
+	}
+	return capacity
+}
+
+// validateMIG validates a pool's MIG partitioning: a known model, a positive GPU count,
+// supported profiles, and a per-GPU layout that fits the GPU's compute and memory slices
+func validateMIG(p *NodePool) error {
+	m := p.MIG
+	model, ok := migModels[m.Model]
+	if !ok {
+		return fmt.Errorf("mig: unknown model '%s' (supported: %s)", m.Model, strings.Join(sortedKeys(migModels), ", "))
+	}
+	if m.GPUs <= 0 {
+		return fmt.Errorf("mig: gpus must be > 0")
+	}
+	if len(m.Profiles) == 0 {
+		return fmt.Errorf("mig: at least one profile is required")
+	}
+
+	var compute, memory int
+	for _, profile := range sortedKeys(m.Profiles) {
+		slices, ok := model.profiles[profile]
+		if !ok {
+			return fmt.Errorf("mig: profile '%s' is not supported by %s (supported: %s)",
+				profile, m.Model, strings.Join(sortedKeys(model.profiles), ", "))
+		}
+		count := m.Profiles[profile]
+		if count <= 0 {
+			return fmt.Errorf("mig: profile '%s' count must be > 0", profile)
+		}
+		if _, ok := p.Resources[migResourcePrefix+profile]; ok {
+			return fmt.Errorf("mig: resource %s%s is also set in resources", migResourcePrefix, profile)
+		}
+		compute += count * slices.compute
+		memory += count * slices.memory
+	}
+
+	if compute > model.computeSlices || memory > model.memorySlices {
+		return fmt.Errorf("mig: profiles use %d/%d compute and %d/%d memory slices per GPU, exceeding %s",
+			compute, model.computeSlices, memory, model.memorySlices, m.Model)
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of a string-keyed map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,6 +1,11 @@
 package config
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
@@ -9,7 +14,8 @@ import (
 // - ResourceFlavors: minimal flavors (name only) matching WorkerSet flavor names for MultiKueue routing
 // - ClusterQueues: matching WorkerSet CQ names with auto-added admissionChecks and summed quotas
 // - LocalQueues: derived from WorkerSet LocalQueues (workloads are submitted to management cluster)
-// - Merges with user-defined objects from managementKueueConfig (cohorts, priorityClasses, etc.)
+// - Namespaces: from DeriveMultiKueueNamespaces
+// - Merges with user-defined objects from managementKueueConfig (cohorts, priorityClasses, admissionChecks, etc.)
 //
 // Parameters:
 // - workerSets: WorkerSet definitions from the topology spec
@@ -29,6 +35,10 @@ func DeriveManagementKueueConfig(workerSets []WorkerSet, expandedWorkers []Clust
 	workersByWS := make(map[string][]ClusterConfig, len(workerSets))
 	for _, ws := range workerSets {
 		for _, worker := range ws.Workers {
+			// Standby workers take over from the others rather than adding capacity
+			if worker.Standby {
+				continue
+			}
 			workersByWS[ws.Name] = append(workersByWS[ws.Name], workerByName[worker.Name])
 		}
 	}
@@ -38,8 +48,12 @@ func DeriveManagementKueueConfig(workerSets []WorkerSet, expandedWorkers []Clust
 	derivedCQs := deriveManagementClusterQueues(workerSets, workersByWS)
 	derivedLQs := deriveManagementLocalQueues(workerSets)
 
+	// Namespaces are pre-validated, so label conflicts cannot occur here
+	namespaces, _ := DeriveMultiKueueNamespaces(workerSets, managementKueueConfig)
+
 	// Start with derived objects
 	result := &KueueConfig{
+		Namespaces:      namespaces,
 		ResourceFlavors: derivedFlavors,
 		ClusterQueues:   derivedCQs,
 		LocalQueues:     derivedLQs,
@@ -49,6 +63,9 @@ func DeriveManagementKueueConfig(workerSets []WorkerSet, expandedWorkers []Clust
 	if managementKueueConfig != nil {
 		result.Cohorts = managementKueueConfig.Cohorts
 		result.PriorityClasses = managementKueueConfig.PriorityClasses
+		result.PodPriorityClasses = managementKueueConfig.PodPriorityClasses
+		result.AdmissionChecks = managementKueueConfig.AdmissionChecks
+		result.ProvisioningRequestConfigs = managementKueueConfig.ProvisioningRequestConfigs
 
 		// Append user-defined objects (derived ones take precedence)
 		result.ResourceFlavors = append(result.ResourceFlavors, managementKueueConfig.ResourceFlavors...)
@@ -59,6 +76,145 @@ func DeriveManagementKueueConfig(workerSets []WorkerSet, expandedWorkers []Clust
 	return result
 }
 
+// ExpandTopology returns the clusters topology create provisions: the explicit clusters, with
+// each management cluster's KueueConfig derived from its WorkerSets, followed by the expanded
+// workers, which get their management cluster's LocalQueue namespaces. LocalQueue
+// generators are expanded, and topology-level extensions are added to the clusters their
+// targets select. cfg is not modified.
+func ExpandTopology(cfg *Topology) ([]ClusterConfig, error) {
+	workers, err := ExpandWorkerSets(cfg.Spec.WorkerSets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand worker sets: %w", err)
+	}
+
+	clusters := make([]ClusterConfig, 0, len(cfg.Spec.Clusters)+len(workers))
+	for _, c := range cfg.Spec.Clusters {
+		c.Kueue = c.Kueue.WithGeneratedLocalQueues()
+		if c.Role == RoleManagement {
+			workerSets := WorkerSetsFor(cfg.Spec.WorkerSets, c.Name)
+			namespaces, err := DeriveMultiKueueNamespaces(workerSets, c.Kueue)
+			if err != nil {
+				return nil, err
+			}
+			// MultiKueue creates jobs in the same namespaces, with the same labels, on workers,
+			// and their pods need the same pod PriorityClasses there
+			var podPriorityClasses []PodPriorityClass
+			if c.Kueue != nil {
+				podPriorityClasses = c.Kueue.AllPodPriorityClasses()
+			}
+			for i := range workers {
+				if workers[i].Kueue != nil && slices.ContainsFunc(workerSets, func(ws WorkerSet) bool { return ws.hasWorker(workers[i].Name) }) {
+					workers[i].Kueue.Namespaces = namespaces
+					workers[i].Kueue.PodPriorityClasses = podPriorityClasses
+				}
+			}
+			c.Kueue = DeriveManagementKueueConfig(workerSets, workers, c.Kueue)
+		}
+		clusters = append(clusters, c)
+	}
+	clusters = append(clusters, workers...)
+
+	for i := range clusters {
+		clusters[i].Extensions = targetedExtensions(cfg.Spec.Extensions, clusters[i])
+	}
+	return clusters, nil
+}
+
+// targetedExtensions returns the topology-level extensions selecting c, followed by c's own
+// extensions. A cluster extension replaces a topology-level one of the same name. External
+// workers get no extensions.
+func targetedExtensions(topologyExtensions []Extension, c ClusterConfig) []Extension {
+	if c.ExternalKubeconfig != "" || len(topologyExtensions) == 0 {
+		return c.Extensions
+	}
+	var extensions []Extension
+	for _, ext := range topologyExtensions {
+		overridden := slices.ContainsFunc(c.Extensions, func(e Extension) bool { return e.Name == ext.Name })
+		if ext.Targets.Selects(c) && !overridden {
+			extensions = append(extensions, ext)
+		}
+	}
+	return append(extensions, c.Extensions...)
+}
+
+// hasWorker reports whether the WorkerSet lists the named worker
+func (ws WorkerSet) hasWorker(name string) bool {
+	return slices.ContainsFunc(ws.Workers, func(w Worker) bool { return w.Name == name })
+}
+
+// DeriveMultiKueueNamespaces returns the namespaces that must exist, with the same labels, on
+// the management cluster and on every worker: MultiKueue creates a workload's job in the same
+// namespace on the worker it dispatches to. They are the management cluster's declared
+// namespaces, with their ResourceQuotas and LimitRanges, and the namespace of every WorkerSet
+// and management LocalQueue. A LocalQueue's namespace is labeled with its ClusterQueue's
+// namespaceSelector matchLabels, so the queue is usable on both sides. Returns an error if a
+// namespace would need conflicting label values.
+func DeriveMultiKueueNamespaces(workerSets []WorkerSet, managementKueueConfig *KueueConfig) ([]Namespace, error) {
+	selectors := make(map[string]map[string]string)
+	var localQueues []LocalQueue
+	for _, ws := range workerSets {
+		for _, cq := range ws.ClusterQueues {
+			if cq.NamespaceSelector != nil {
+				selectors[cq.Name] = cq.NamespaceSelector.MatchLabels
+			}
+		}
+		localQueues = append(localQueues, ws.LocalQueues...)
+	}
+
+	var namespaces []Namespace
+	index := make(map[string]int)
+	addLabels := func(name string, labels map[string]string) error {
+		i, ok := index[name]
+		if !ok {
+			i = len(namespaces)
+			index[name] = i
+			namespaces = append(namespaces, Namespace{Name: name})
+		}
+		for k, v := range labels {
+			if existing, ok := namespaces[i].Labels[k]; ok && existing != v {
+				return fmt.Errorf("namespace '%s' needs label %s=%s and %s=%s", name, k, existing, k, v)
+			}
+			if namespaces[i].Labels == nil {
+				namespaces[i].Labels = make(map[string]string, len(labels))
+			}
+			namespaces[i].Labels[k] = v
+		}
+		return nil
+	}
+
+	if managementKueueConfig != nil {
+		for _, ns := range managementKueueConfig.Namespaces {
+			if err := addLabels(ns.Name, ns.Labels); err != nil {
+				return nil, err
+			}
+			// Pods run on the workers, so namespace quotas and limits apply there too
+			namespaces[index[ns.Name]].ResourceQuota = ns.ResourceQuota
+			namespaces[index[ns.Name]].LimitRange = ns.LimitRange
+		}
+		for _, cq := range managementKueueConfig.ClusterQueues {
+			if _, ok := selectors[cq.Name]; !ok && cq.NamespaceSelector != nil {
+				selectors[cq.Name] = cq.NamespaceSelector.MatchLabels
+			}
+		}
+		localQueues = append(localQueues, managementKueueConfig.LocalQueues...)
+	}
+
+	for _, lq := range localQueues {
+		name := lq.Namespace
+		if name == "" {
+			name = "default"
+		}
+		// The default namespace always exists; only list it if it needs labels
+		if _, ok := index[name]; !ok && name == "default" && len(selectors[lq.ClusterQueue]) == 0 {
+			continue
+		}
+		if err := addLabels(name, selectors[lq.ClusterQueue]); err != nil {
+			return nil, fmt.Errorf("localQueue %s/%s: %w", name, lq.Name, err)
+		}
+	}
+	return namespaces, nil
+}
+
 // deriveManagementResourceFlavors creates minimal ResourceFlavors for the management cluster.
 // These flavors only have names (no labels/tolerations) - just enough for MultiKueue routing.
 // Output order follows input order (stable across runs).
@@ -89,7 +245,7 @@ func deriveManagementClusterQueues(workerSets []WorkerSet, workersByWS map[strin
 	for _, ws := range workerSets {
 		for _, wsCQ := range ws.ClusterQueues {
 			// Aggregate quotas from all workers in this WorkerSet
-			aggregatedRGs := aggregateWorkerQuotas(wsCQ.Name, workersByWS[ws.Name])
+			aggregatedRGs := aggregateWorkerQuotas(wsCQ, workersByWS[ws.Name])
 
 			// Create management CQ with auto-added admissionChecks
 			admissionChecks := []string{ws.Name}
@@ -131,54 +287,51 @@ func deriveManagementLocalQueues(workerSets []WorkerSet) []LocalQueue {
 }
 
 // aggregateWorkerQuotas sums quotas across all workers in a WorkerSet for a specific ClusterQueue.
-// All inputs are pre-validated: quota strings come from Quantity.String() (always parseable),
-// and workers are pre-grouped by WorkerSet.
-func aggregateWorkerQuotas(cqName string, workers []ClusterConfig) []ResourceGroup {
-	// Find the matching CQ from each worker
-	var workerCQs []*ClusterQueue
+// The result follows the WorkerSet CQ's structure; each flavor sums the workers that provide it,
+// so workers with different flavors contribute only to their own. A flavor no worker provides
+// gets zero quota. All inputs are pre-validated: quota strings come from Quantity.String()
+// (always parseable), and workers are pre-grouped by WorkerSet.
+func aggregateWorkerQuotas(wsCQ WorkerSetClusterQueue, workers []ClusterConfig) []ResourceGroup {
+	// Index each worker CQ's quotas by flavor and resource
+	totals := make(map[string]map[string]resource.Quantity)
 	for i := range workers {
 		if workers[i].Kueue == nil {
 			continue
 		}
-		for j := range workers[i].Kueue.ClusterQueues {
-			if workers[i].Kueue.ClusterQueues[j].Name == cqName {
-				workerCQs = append(workerCQs, &workers[i].Kueue.ClusterQueues[j])
-				break
+		for _, cq := range workers[i].Kueue.ClusterQueues {
+			if cq.Name != wsCQ.Name {
+				continue
+			}
+			for _, rg := range cq.ResourceGroups {
+				for _, flavor := range rg.Flavors {
+					if totals[flavor.Name] == nil {
+						totals[flavor.Name] = make(map[string]resource.Quantity)
+					}
+					for _, res := range flavor.Resources {
+						total := totals[flavor.Name][res.Name]
+						total.Add(resource.MustParse(res.NominalQuota))
+						totals[flavor.Name][res.Name] = total
+					}
+				}
 			}
+			break
 		}
 	}
 
-	if len(workerCQs) == 0 {
-		return nil
-	}
-
-	// Use first worker's CQ as structural template
-	templateCQ := workerCQs[0]
-
-	// Aggregate quotas across all workers
-	aggregatedRGs := make([]ResourceGroup, len(templateCQ.ResourceGroups))
-	for rgIdx, rg := range templateCQ.ResourceGroups {
+	aggregatedRGs := make([]ResourceGroup, len(wsCQ.ResourceGroups))
+	for rgIdx, rg := range wsCQ.ResourceGroups {
 		aggregatedRGs[rgIdx] = ResourceGroup{
 			CoveredResources: rg.CoveredResources,
 			Flavors:          make([]FlavorQuotas, len(rg.Flavors)),
 		}
 
 		for flavorIdx, flavor := range rg.Flavors {
-			aggregated := make([]resource.Quantity, len(flavor.Resources))
-			for _, workerCQ := range workerCQs {
-				workerFlavor := workerCQ.ResourceGroups[rgIdx].Flavors[flavorIdx]
-				for i, res := range workerFlavor.Resources {
-					q := resource.MustParse(res.NominalQuota)
-					aggregated[i].Add(q)
-				}
-			}
-
-			// Convert aggregated quantities to Resource slice
-			resources := make([]Resource, len(flavor.Resources))
-			for i, res := range flavor.Resources {
+			resources := make([]Resource, len(rg.CoveredResources))
+			for i, name := range rg.CoveredResources {
+				total := totals[flavor.Name][name]
 				resources[i] = Resource{
-					Name:         res.Name,
-					NominalQuota: aggregated[i].String(),
+					Name:         name,
+					NominalQuota: total.String(),
 				}
 			}
 
@@ -191,3 +344,102 @@ func aggregateWorkerQuotas(cqName string, workers []ClusterConfig) []ResourceGro
 
 	return aggregatedRGs
 }
+
+// RankedWorkers returns the WorkerSet's worker names in dispatch preference order: workers
+// in dispatch.order first, then the rest in spec order
+func (ws WorkerSet) RankedWorkers() []string {
+	var order []string
+	if ws.Dispatch != nil {
+		order = ws.Dispatch.Order
+	}
+	ranked := append([]string{}, order...)
+	for _, w := range ws.Workers {
+		if !slices.Contains(order, w.Name) {
+			ranked = append(ranked, w.Name)
+		}
+	}
+	return ranked
+}
+
+// MultiKueueClusterName returns the MultiKueueCluster name for a worker. With dispatch.order
+// set, names are prefixed with the worker's rank, since the incremental dispatcher nominates
+// workers in name order.
+func (ws WorkerSet) MultiKueueClusterName(worker string) string {
+	if ws.Dispatch == nil || len(ws.Dispatch.Order) == 0 {
+		return worker
+	}
+	rank := slices.Index(ws.RankedWorkers(), worker)
+	return fmt.Sprintf("%02d-%s", rank+1, worker)
+}
+
+// managementCluster returns the WorkerSet's management cluster, or defaultManagement when
+// managementRef is unset
+func (ws WorkerSet) managementCluster(defaultManagement string) string {
+	if ws.ManagementRef == "" {
+		return defaultManagement
+	}
+	return ws.ManagementRef
+}
+
+// ManagedBy reports whether the named management cluster dispatches to the WorkerSet's workers
+func (ws WorkerSet) ManagedBy(management string) bool {
+	return ws.ManagementRef == "" || ws.ManagementRef == management
+}
+
+// WorkerSetsFor returns the WorkerSets managed by the named management cluster
+func WorkerSetsFor(workerSets []WorkerSet, management string) []WorkerSet {
+	var managed []WorkerSet
+	for _, ws := range workerSets {
+		if ws.ManagedBy(management) {
+			managed = append(managed, ws)
+		}
+	}
+	return managed
+}
+
+// MultiKueueDispatcher returns the dispatcher set by the WorkerSets, or "" for Kueue's default
+func MultiKueueDispatcher(workerSets []WorkerSet) string {
+	for _, ws := range workerSets {
+		if ws.Dispatch != nil && ws.Dispatch.Dispatcher != "" {
+			return ws.Dispatch.Dispatcher
+		}
+	}
+	return ""
+}
+
+// DefaultKueueNamespace is the namespace Kueue is installed into
+const DefaultKueueNamespace = "kueue-system"
+
+// defaultSecretNameTemplate names worker kubeconfig Secrets unless a WorkerSet overrides it
+const defaultSecretNameTemplate = "{{.Worker}}-kubeconfig"
+
+// SecretNameData is the data available to WorkerSet secretNameTemplates
+type SecretNameData struct {
+	WorkerSet string
+	Worker    string
+}
+
+// Namespace returns the namespace MultiKueue objects for the WorkerSet are created in
+func (ws WorkerSet) Namespace() string {
+	if ws.KueueNamespace != "" {
+		return ws.KueueNamespace
+	}
+	return DefaultKueueNamespace
+}
+
+// KubeconfigSecretName returns the name of the Secret holding a worker's kubeconfig
+func (ws WorkerSet) KubeconfigSecretName(worker string) (string, error) {
+	text := ws.SecretNameTemplate
+	if text == "" {
+		text = defaultSecretNameTemplate
+	}
+	t, err := template.New("secretNameTemplate").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid secretNameTemplate: %w", err)
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, SecretNameData{WorkerSet: ws.Name, Worker: worker}); err != nil {
+		return "", fmt.Errorf("failed to render secretNameTemplate: %w", err)
+	}
+	return b.String(), nil
+}
@@ -2,7 +2,10 @@ package config
 
 import (
 	"reflect"
+	"strings"
 	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func TestDeriveManagementKueueConfig(t *testing.T) {
@@ -276,6 +279,7 @@ func TestDeriveManagementKueueConfig(t *testing.T) {
 				LocalQueues: []LocalQueue{
 					{Name: "team-lq", Namespace: "team-a", ClusterQueue: "team-cq"},
 				},
+				Namespaces: []Namespace{{Name: "team-a"}},
 			},
 		},
 		{
@@ -365,6 +369,7 @@ func TestDeriveManagementKueueConfig(t *testing.T) {
 					{Name: "ws-lq", Namespace: "ws-ns", ClusterQueue: "team-cq"},
 					{Name: "mgmt-lq", Namespace: "mgmt-ns", ClusterQueue: "team-cq"},
 				},
+				Namespaces: []Namespace{{Name: "ws-ns"}, {Name: "mgmt-ns"}},
 			},
 		},
 		{
@@ -382,7 +387,7 @@ func TestDeriveManagementKueueConfig(t *testing.T) {
 							Preemption: &PreemptionConfig{
 								WithinClusterQueue: "LowerPriority",
 							},
-							FairSharing: &FairSharing{Weight: 2},
+							FairSharing: &FairSharing{Weight: "2"},
 							ResourceGroups: []WorkerSetResourceGroup{
 								{
 									CoveredResources: []string{"nvidia.com/gpu"},
@@ -425,7 +430,7 @@ func TestDeriveManagementKueueConfig(t *testing.T) {
 						Preemption: &PreemptionConfig{
 							WithinClusterQueue: "LowerPriority",
 						},
-						FairSharing: &FairSharing{Weight: 2},
+						FairSharing: &FairSharing{Weight: "2"},
 						ResourceGroups: []ResourceGroup{
 							{
 								CoveredResources: []string{"nvidia.com/gpu"},
@@ -450,3 +455,480 @@ func TestDeriveManagementKueueConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestExpandWorkerSetsExternalWorker(t *testing.T) {
+	ws := WorkerSet{
+		Name:            "ws",
+		ResourceFlavors: []WorkerSetFlavor{{Name: "default", NodePoolRef: "pool"}},
+		ClusterQueues: []WorkerSetClusterQueue{{
+			Name: "cq",
+			ResourceGroups: []WorkerSetResourceGroup{{
+				CoveredResources: []string{"cpu"},
+				Flavors:          []WorkerSetFlavorRef{{Name: "default"}},
+			}},
+		}},
+		Workers: []Worker{
+			{Name: "sim", NodePools: []NodePool{{Name: "pool", Count: 2, Resources: map[string]string{"cpu": "8"}}}},
+			{Name: "staging", Kubeconfig: "/kubeconfigs/staging"},
+		},
+	}
+
+	workers, err := ExpandWorkerSets([]WorkerSet{ws})
+	if err != nil {
+		t.Fatalf("ExpandWorkerSets() error = %v", err)
+	}
+	wantExternal := ClusterConfig{Name: "staging", Role: RoleWorker, ExternalKubeconfig: "/kubeconfigs/staging"}
+	if len(workers) != 2 || !reflect.DeepEqual(workers[1], wantExternal) {
+		t.Fatalf("ExpandWorkerSets() external worker = %+v, want %+v", workers[1], wantExternal)
+	}
+
+	// Management quota comes from the simulated worker only
+	mgmt := DeriveManagementKueueConfig([]WorkerSet{ws}, workers, nil)
+	simQuota := workers[0].Kueue.ClusterQueues[0].ResourceGroups[0].Flavors[0].Resources[0].NominalQuota
+	if got := mgmt.ClusterQueues[0].ResourceGroups[0].Flavors[0].Resources[0].NominalQuota; got != simQuota {
+		t.Errorf("management quota = %s, want %s", got, simQuota)
+	}
+}
+
+func TestExpandWorkerSetsSharedWorker(t *testing.T) {
+	workerSet := func(name, pool, cq string, workers ...Worker) WorkerSet {
+		return WorkerSet{
+			Name:            name,
+			ResourceFlavors: []WorkerSetFlavor{{Name: pool + "-flavor", NodePoolRef: pool}},
+			ClusterQueues: []WorkerSetClusterQueue{{
+				Name: cq,
+				ResourceGroups: []WorkerSetResourceGroup{{
+					CoveredResources: []string{"cpu"},
+					Flavors:          []WorkerSetFlavorRef{{Name: pool + "-flavor"}},
+				}},
+			}},
+			LocalQueues: []LocalQueue{{Name: "lq", Namespace: "team", ClusterQueue: cq}},
+			Workers:     workers,
+		}
+	}
+	cpuPool := NodePool{Name: "cpu", Count: 2, Resources: map[string]string{"cpu": "8"}}
+	gpuPool := NodePool{Name: "gpu", Count: 1, Resources: map[string]string{"cpu": "32"}}
+	workerSets := []WorkerSet{
+		workerSet("batch", "cpu", "batch-cq",
+			Worker{Name: "shared", NodePools: []NodePool{cpuPool}},
+			Worker{Name: "batch-only", NodePools: []NodePool{cpuPool}}),
+		workerSet("training", "gpu", "training-cq",
+			Worker{Name: "shared", NodePools: []NodePool{gpuPool}}),
+	}
+	workerSets[1].LocalQueues[0].Name = "training-lq"
+
+	workers, err := ExpandWorkerSets(workerSets)
+	if err != nil {
+		t.Fatalf("ExpandWorkerSets() error = %v", err)
+	}
+	if len(workers) != 2 || workers[0].Name != "shared" || workers[1].Name != "batch-only" {
+		t.Fatalf("ExpandWorkerSets() = %+v, want one ClusterConfig per worker", workers)
+	}
+
+	shared := workers[0]
+	if len(shared.NodePools) != 2 {
+		t.Errorf("shared worker nodePools = %+v, want cpu and gpu", shared.NodePools)
+	}
+	var cqs, lqs []string
+	for _, cq := range shared.Kueue.ClusterQueues {
+		cqs = append(cqs, cq.Name)
+	}
+	for _, lq := range shared.Kueue.LocalQueues {
+		lqs = append(lqs, lq.Name)
+	}
+	if !reflect.DeepEqual(cqs, []string{"batch-cq", "training-cq"}) || !reflect.DeepEqual(lqs, []string{"lq", "training-lq"}) {
+		t.Errorf("shared worker queues = %v, %v; want both workerSets' queues", cqs, lqs)
+	}
+	if len(workerSets[0].Workers[0].NodePools) != 1 || len(workerSets[0].LocalQueues) != 1 {
+		t.Errorf("ExpandWorkerSets() modified the workerSet spec")
+	}
+
+	// Each management ClusterQueue sums its own WorkerSet's workers
+	mgmt := DeriveManagementKueueConfig(workerSets, workers, nil)
+	want := map[string]string{"batch-cq": "32", "training-cq": "32"}
+	for _, cq := range mgmt.ClusterQueues {
+		if got := cq.ResourceGroups[0].Flavors[0].Resources[0].NominalQuota; got != want[cq.Name] {
+			t.Errorf("management %s quota = %s, want %s", cq.Name, got, want[cq.Name])
+		}
+	}
+}
+
+func TestDeriveMultiKueueNamespaces(t *testing.T) {
+	workerSets := []WorkerSet{{
+		Name: "ws",
+		ClusterQueues: []WorkerSetClusterQueue{
+			{Name: "team-cq", NamespaceSelector: &LabelSelector{MatchLabels: map[string]string{"team": "a"}}},
+			{Name: "open-cq"},
+		},
+		LocalQueues: []LocalQueue{
+			{Name: "lq", Namespace: "team-a", ClusterQueue: "team-cq"},
+			{Name: "open-lq", Namespace: "shared", ClusterQueue: "open-cq"},
+			{Name: "default-lq", ClusterQueue: "open-cq"},
+		},
+	}}
+
+	tests := []struct {
+		name        string
+		management  *KueueConfig
+		want        []Namespace
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "LocalQueue namespaces get their ClusterQueue's selector labels",
+			want: []Namespace{
+				{Name: "team-a", Labels: map[string]string{"team": "a"}},
+				{Name: "shared"},
+			},
+		},
+		{
+			name: "declared management namespaces come first and keep their labels",
+			management: &KueueConfig{
+				Namespaces:  []Namespace{{Name: "team-a", Labels: map[string]string{"env": "bench"}}},
+				LocalQueues: []LocalQueue{{Name: "mgmt-lq", Namespace: "mgmt", ClusterQueue: "team-cq"}},
+			},
+			want: []Namespace{
+				{Name: "team-a", Labels: map[string]string{"env": "bench", "team": "a"}},
+				{Name: "shared"},
+				{Name: "mgmt", Labels: map[string]string{"team": "a"}},
+			},
+		},
+		{
+			name: "declared management namespaces keep their quotas and limits",
+			management: &KueueConfig{
+				Namespaces: []Namespace{{
+					Name:          "shared",
+					ResourceQuota: map[string]string{"count/jobs.batch": "50"},
+					LimitRange:    &LimitRange{DefaultRequest: map[string]string{"cpu": "1"}},
+				}},
+			},
+			want: []Namespace{
+				{
+					Name:          "shared",
+					ResourceQuota: map[string]string{"count/jobs.batch": "50"},
+					LimitRange:    &LimitRange{DefaultRequest: map[string]string{"cpu": "1"}},
+				},
+				{Name: "team-a", Labels: map[string]string{"team": "a"}},
+			},
+		},
+		{
+			name: "conflicting labels",
+			management: &KueueConfig{
+				Namespaces: []Namespace{{Name: "team-a", Labels: map[string]string{"team": "b"}}},
+			},
+			wantErr:     true,
+			errContains: "localQueue team-a/lq: namespace 'team-a' needs label team=b and team=a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DeriveMultiKueueNamespaces(workerSets, tt.management)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeriveMultiKueueNamespaces() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("DeriveMultiKueueNamespaces() error = %v, expected to contain %q", err, tt.errContains)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DeriveMultiKueueNamespaces() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandTopology(t *testing.T) {
+	pool := []NodePool{{Name: "pool", Count: 2, Resources: map[string]string{"cpu": "8"}}}
+	cfg := &Topology{Spec: TopologySpec{
+		Clusters: []ClusterConfig{
+			{Name: "standalone", NodePools: pool, Kueue: &KueueConfig{
+				LocalQueueGenerators: []LocalQueueGenerator{{ClusterQueue: "cq", Namespaces: 3, QueuesPerNamespace: 2}},
+			}},
+			{Name: "management", Role: RoleManagement, Kueue: &KueueConfig{
+				Namespaces:      []Namespace{{Name: "team-a", Labels: map[string]string{"team": "a"}}},
+				PriorityClasses: []WorkloadPriorityClass{{Name: "high", Value: 100, PodPriorityClass: true}},
+			}},
+		},
+		WorkerSets: []WorkerSet{{
+			Name:            "ws",
+			ResourceFlavors: []WorkerSetFlavor{{Name: "default", NodePoolRef: "pool"}},
+			ClusterQueues: []WorkerSetClusterQueue{{
+				Name:              "cq",
+				NamespaceSelector: &LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+				ResourceGroups: []WorkerSetResourceGroup{{
+					CoveredResources: []string{"cpu"},
+					Flavors:          []WorkerSetFlavorRef{{Name: "default"}},
+				}},
+			}},
+			LocalQueues: []LocalQueue{{Name: "lq", Namespace: "team-a", ClusterQueue: "cq"}},
+			Workers:     []Worker{{Name: "worker-1", NodePools: pool}, {Name: "worker-2", NodePools: pool}},
+		}},
+	}}
+	original := cfg.Spec.Clusters[1].Kueue
+
+	clusters, err := ExpandTopology(cfg)
+	if err != nil {
+		t.Fatalf("ExpandTopology() error = %v", err)
+	}
+	var names []string
+	for _, c := range clusters {
+		names = append(names, c.Name)
+	}
+	if want := []string{"standalone", "management", "worker-1", "worker-2"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("cluster names = %v, want %v", names, want)
+	}
+
+	if got := clusters[0].Kueue; len(got.LocalQueues) != 6 || len(got.LocalQueueGenerators) != 0 {
+		t.Errorf("standalone Kueue config = %+v, want 6 generated LocalQueues", got)
+	}
+	if len(cfg.Spec.Clusters[0].Kueue.LocalQueues) != 0 {
+		t.Errorf("ExpandTopology() modified the topology's standalone Kueue config")
+	}
+
+	mgmt := clusters[1].Kueue
+	if got := mgmt.ClusterQueues[0].AdmissionChecks; !reflect.DeepEqual(got, []string{"ws"}) {
+		t.Errorf("management admissionChecks = %v, want [ws]", got)
+	}
+	if got := mgmt.ClusterQueues[0].ResourceGroups[0].Flavors[0].Resources; !reflect.DeepEqual(got, []Resource{{Name: "cpu", NominalQuota: "32"}}) {
+		t.Errorf("management quotas = %+v, want cpu 32", got)
+	}
+	for _, w := range clusters[2:] {
+		if !reflect.DeepEqual(w.Kueue.Namespaces, original.Namespaces) {
+			t.Errorf("worker %s namespaces = %+v, want %+v", w.Name, w.Kueue.Namespaces, original.Namespaces)
+		}
+		// Pods of dispatched jobs run on the workers, which need their pod PriorityClasses
+		if want := []PodPriorityClass{{Name: "high", Value: 100}}; !reflect.DeepEqual(w.Kueue.PodPriorityClasses, want) {
+			t.Errorf("worker %s podPriorityClasses = %+v, want %+v", w.Name, w.Kueue.PodPriorityClasses, want)
+		}
+	}
+	if cfg.Spec.Clusters[1].Kueue != original || len(original.ClusterQueues) != 0 {
+		t.Errorf("ExpandTopology() modified the topology's management Kueue config")
+	}
+}
+
+func TestTopologyExtensionTargets(t *testing.T) {
+	pool := []NodePool{{Name: "pool", Count: 1, Resources: map[string]string{"cpu": "8"}}}
+	jobset := Extension{Name: "jobset", Preset: PresetJobSet, Targets: &ExtensionTargets{Roles: []string{RoleWorker}}}
+	prometheus := Extension{Name: "prometheus", Helm: &HelmExtension{Chart: "prometheus"}, Targets: &ExtensionTargets{Clusters: []string{"management"}}}
+	certManager := Extension{Name: "cert-manager", Preset: PresetCertManager}
+	localPrometheus := Extension{Name: "prometheus", Helm: &HelmExtension{Chart: "oci://registry.example.com/prometheus"}}
+	cfg := &Topology{Spec: TopologySpec{
+		Extensions: []Extension{certManager, jobset, prometheus},
+		Clusters: []ClusterConfig{
+			// A cluster's own extension replaces the topology-level one of the same name
+			{Name: "management", Role: RoleManagement, Extensions: []Extension{localPrometheus}},
+		},
+		WorkerSets: []WorkerSet{{
+			Name: "ws",
+			Workers: []Worker{
+				{Name: "worker-1", NodePools: pool},
+				{Name: "worker-2", NodePools: pool},
+				{Name: "external", Kubeconfig: "/tmp/external.kubeconfig"},
+			},
+		}},
+	}}
+	clusters, err := ExpandTopology(cfg)
+	if err != nil {
+		t.Fatalf("ExpandTopology() error = %v", err)
+	}
+	names := func(exts []Extension) []string {
+		var n []string
+		for _, e := range exts {
+			n = append(n, e.Name)
+		}
+		return n
+	}
+	want := map[string][]string{
+		"management": {"cert-manager", "prometheus"},
+		"worker-1":   {"cert-manager", "jobset"},
+		"worker-2":   {"cert-manager", "jobset"},
+		"external":   nil,
+	}
+	for _, c := range clusters {
+		if got := names(c.Extensions); !reflect.DeepEqual(got, want[c.Name]) {
+			t.Errorf("cluster %s extensions = %v, want %v", c.Name, got, want[c.Name])
+		}
+	}
+	if got := clusters[0].Extensions[1]; !reflect.DeepEqual(got, localPrometheus) {
+		t.Errorf("management prometheus = %+v, want the cluster's own %+v", got, localPrometheus)
+	}
+}
+
+func TestWorkerQuotaSkew(t *testing.T) {
+	weight := 0.25
+	pool := []NodePool{{Name: "pool", Count: 4, Resources: map[string]string{"cpu": "10", "memory": "3Gi"}}}
+	ws := WorkerSet{
+		Name:            "ws",
+		ResourceFlavors: []WorkerSetFlavor{{Name: "default", NodePoolRef: "pool"}},
+		ClusterQueues: []WorkerSetClusterQueue{{
+			Name: "cq",
+			ResourceGroups: []WorkerSetResourceGroup{{
+				CoveredResources: []string{"cpu", "memory"},
+				Flavors:          []WorkerSetFlavorRef{{Name: "default"}},
+			}},
+		}},
+		Workers: []Worker{
+			{Name: "primary", NodePools: pool},
+			{Name: "partial", NodePools: pool, CapacityWeight: &weight},
+			{Name: "standby", NodePools: pool, Standby: true},
+		},
+	}
+
+	workers, err := ExpandWorkerSets([]WorkerSet{ws})
+	if err != nil {
+		t.Fatalf("ExpandWorkerSets() error = %v", err)
+	}
+	quotas := func(c ClusterConfig) []Resource {
+		return c.Kueue.ClusterQueues[0].ResourceGroups[0].Flavors[0].Resources
+	}
+	want := map[string][]Resource{
+		"primary": {{Name: "cpu", NominalQuota: "40"}, {Name: "memory", NominalQuota: "12Gi"}},
+		"partial": {{Name: "cpu", NominalQuota: "10"}, {Name: "memory", NominalQuota: "3Gi"}},
+		"standby": {{Name: "cpu", NominalQuota: "40"}, {Name: "memory", NominalQuota: "12Gi"}},
+	}
+	for _, w := range workers {
+		if got := quotas(w); !reflect.DeepEqual(got, want[w.Name]) {
+			t.Errorf("worker %s quotas = %+v, want %+v", w.Name, got, want[w.Name])
+		}
+	}
+
+	// The standby worker keeps its quota but adds none to the management ClusterQueue
+	mgmt := DeriveManagementKueueConfig([]WorkerSet{ws}, workers, nil)
+	wantMgmt := []Resource{{Name: "cpu", NominalQuota: "50"}, {Name: "memory", NominalQuota: "15Gi"}}
+	if got := mgmt.ClusterQueues[0].ResourceGroups[0].Flavors[0].Resources; !reflect.DeepEqual(got, wantMgmt) {
+		t.Errorf("management quotas = %+v, want %+v", got, wantMgmt)
+	}
+}
+
+func TestHeterogeneousWorkerFlavors(t *testing.T) {
+	ws := WorkerSet{
+		Name: "ws",
+		ResourceFlavors: []WorkerSetFlavor{
+			{Name: "a100", NodePoolRef: "a100-pool", Workers: []string{"worker-1"}},
+			{Name: "h100", NodePoolRef: "h100-pool", Workers: []string{"worker-2"}},
+			{Name: "cpu", NodePoolRef: "cpu-pool"},
+		},
+		ClusterQueues: []WorkerSetClusterQueue{{
+			Name: "cq",
+			ResourceGroups: []WorkerSetResourceGroup{
+				{CoveredResources: []string{"nvidia.com/gpu"}, Flavors: []WorkerSetFlavorRef{{Name: "a100"}, {Name: "h100"}}},
+				{CoveredResources: []string{"cpu"}, Flavors: []WorkerSetFlavorRef{{Name: "cpu"}}},
+			},
+		}},
+		Workers: []Worker{
+			{Name: "worker-1", NodePools: []NodePool{
+				{Name: "a100-pool", Count: 2, Resources: map[string]string{"nvidia.com/gpu": "8"}},
+				{Name: "cpu-pool", Count: 1, Resources: map[string]string{"cpu": "32"}},
+			}},
+			{Name: "worker-2", NodePools: []NodePool{
+				{Name: "h100-pool", Count: 3, Resources: map[string]string{"nvidia.com/gpu": "4"}},
+				{Name: "cpu-pool", Count: 1, Resources: map[string]string{"cpu": "32"}},
+			}},
+		},
+	}
+
+	workers, err := ExpandWorkerSets([]WorkerSet{ws})
+	if err != nil {
+		t.Fatalf("ExpandWorkerSets() error = %v", err)
+	}
+	wantFlavors := map[string][]string{"worker-1": {"a100", "cpu"}, "worker-2": {"h100", "cpu"}}
+	for _, w := range workers {
+		var flavors []string
+		for _, rf := range w.Kueue.ResourceFlavors {
+			flavors = append(flavors, rf.Name)
+		}
+		if !reflect.DeepEqual(flavors, wantFlavors[w.Name]) {
+			t.Errorf("worker %s resourceFlavors = %v, want %v", w.Name, flavors, wantFlavors[w.Name])
+		}
+		gpu := w.Kueue.ClusterQueues[0].ResourceGroups[0].Flavors
+		if len(gpu) != 1 || gpu[0].Name != wantFlavors[w.Name][0] {
+			t.Errorf("worker %s GPU flavors = %+v, want only %s", w.Name, gpu, wantFlavors[w.Name][0])
+		}
+	}
+
+	// Each flavor sums only the workers that provide it
+	mgmt := DeriveManagementKueueConfig([]WorkerSet{ws}, workers, nil)
+	want := []ResourceGroup{
+		{CoveredResources: []string{"nvidia.com/gpu"}, Flavors: []FlavorQuotas{
+			{Name: "a100", Resources: []Resource{{Name: "nvidia.com/gpu", NominalQuota: "16"}}},
+			{Name: "h100", Resources: []Resource{{Name: "nvidia.com/gpu", NominalQuota: "12"}}},
+		}},
+		{CoveredResources: []string{"cpu"}, Flavors: []FlavorQuotas{
+			{Name: "cpu", Resources: []Resource{{Name: "cpu", NominalQuota: "64"}}},
+		}},
+	}
+	if got := mgmt.ClusterQueues[0].ResourceGroups; !reflect.DeepEqual(got, want) {
+		t.Errorf("management resourceGroups = %+v, want %+v", got, want)
+	}
+
+	// A flavor provided only by a standby worker is kept with zero quota
+	ws.Workers[1].Standby = true
+	mgmt = DeriveManagementKueueConfig([]WorkerSet{ws}, workers, nil)
+	if got := mgmt.ClusterQueues[0].ResourceGroups[0].Flavors[1].Resources[0].NominalQuota; got != "0" {
+		t.Errorf("standby-only flavor quota = %s, want 0", got)
+	}
+}
+
+func TestScaleQuantity(t *testing.T) {
+	tests := []struct {
+		quantity string
+		weight   float64
+		want     string
+	}{
+		{quantity: "96", weight: 0.1, want: "9600m"},
+		{quantity: "96", weight: 0.3, want: "28800m"},
+		{quantity: "8", weight: 2, want: "16"},
+		{quantity: "3Ti", weight: 0.5, want: "1536Gi"},
+		{quantity: "1Ki", weight: 0.3, want: "307"},
+		{quantity: "500m", weight: 0.001, want: "1m"},
+		// Beyond the range of MilliValue
+		{quantity: "20E", weight: 0.5, want: "10E"},
+		{quantity: "6Ei", weight: 0.5, want: "3Ei"},
+	}
+	for _, tt := range tests {
+		got := scaleQuantity(resource.MustParse(tt.quantity), tt.weight)
+		if got.String() != tt.want {
+			t.Errorf("scaleQuantity(%s, %g) = %s, want %s", tt.quantity, tt.weight, got.String(), tt.want)
+		}
+	}
+}
+
+func TestWorkerSetDispatchOrder(t *testing.T) {
+	ws := WorkerSet{Workers: []Worker{{Name: "a"}, {Name: "b"}, {Name: "c"}}}
+	if got := ws.MultiKueueClusterName("b"); got != "b" {
+		t.Errorf("MultiKueueClusterName() without order = %q, want %q", got, "b")
+	}
+
+	ws.Dispatch = &WorkerSetDispatch{Dispatcher: DispatcherIncremental, Order: []string{"c", "a"}}
+	if got, want := ws.RankedWorkers(), []string{"c", "a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RankedWorkers() = %v, want %v", got, want)
+	}
+	var names []string
+	for _, w := range ws.RankedWorkers() {
+		names = append(names, ws.MultiKueueClusterName(w))
+	}
+	if want := []string{"01-c", "02-a", "03-b"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("MultiKueueClusterName() = %v, want %v", names, want)
+	}
+	if got := MultiKueueDispatcher([]WorkerSet{{}, ws}); got != DispatcherIncremental {
+		t.Errorf("MultiKueueDispatcher() = %q, want %q", got, DispatcherIncremental)
+	}
+}
+
+func TestKubeconfigSecretName(t *testing.T) {
+	ws := WorkerSet{Name: "gpu"}
+	if got, err := ws.KubeconfigSecretName("worker-1"); err != nil || got != "worker-1-kubeconfig" {
+		t.Errorf("KubeconfigSecretName() = %q, %v, want default name", got, err)
+	}
+	if ws.Namespace() != DefaultKueueNamespace {
+		t.Errorf("Namespace() = %q, want %q", ws.Namespace(), DefaultKueueNamespace)
+	}
+
+	ws.SecretNameTemplate = "{{.WorkerSet}}-{{.Worker}}-creds"
+	if got, err := ws.KubeconfigSecretName("worker-1"); err != nil || got != "gpu-worker-1-creds" {
+		t.Errorf("KubeconfigSecretName() = %q, %v, want templated name", got, err)
+	}
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// NodeTemplateData is the data available to node name and label templates
+type NodeTemplateData struct {
+	Pool   string
+	Index  int
+	Zone   string
+	Region string
+	Rack   string
+}
+
+// TemplateData returns the template data for the index-th node of the pool.
+// Nodes are spread round-robin across zones, and each zone's nodes are grouped into
+// consecutive racks of NodesPerRack nodes.
+func (p *NodePool) TemplateData(index int) NodeTemplateData {
+	data := NodeTemplateData{Pool: p.Name, Index: index, Region: p.Region}
+
+	zoneIndex := index
+	if len(p.Zones) > 0 {
+		data.Zone = p.Zones[index%len(p.Zones)]
+		zoneIndex = index / len(p.Zones)
+	}
+
+	if p.NodesPerRack > 0 {
+		if data.Zone != "" {
+			data.Rack = fmt.Sprintf("%s-%s-rack-%d", p.Name, data.Zone, zoneIndex/p.NodesPerRack)
+		} else {
+			data.Rack = fmt.Sprintf("%s-rack-%d", p.Name, zoneIndex/p.NodesPerRack)
+		}
+	}
+
+	return data
+}
+
+// NodeNamer renders the names and templated label values of a pool's nodes
+type NodeNamer struct {
+	pool   *NodePool
+	name   *template.Template
+	labels map[string]*template.Template
+}
+
+// NewNodeNamer parses the pool's name template and templated label values
+func NewNodeNamer(p *NodePool) (*NodeNamer, error) {
+	n := &NodeNamer{pool: p, labels: make(map[string]*template.Template)}
+
+	if p.NameTemplate != "" {
+		t, err := parseNodeTemplate("nameTemplate", p.NameTemplate)
+		if err != nil {
+			return nil, err
+		}
+		n.name = t
+	}
+
+	for key, value := range p.Labels {
+		if !IsNodeTemplate(value) {
+			continue
+		}
+		t, err := parseNodeTemplate("label "+key, value)
+		if err != nil {
+			return nil, err
+		}
+		n.labels[key] = t
+	}
+
+	return n, nil
+}
+
+// Name returns the name of the index-th node. Without a name template nodes are
+// named kwok-node-<pool>-<index>.
+func (n *NodeNamer) Name(index int) (string, error) {
+	if n.name == nil {
+		return fmt.Sprintf("kwok-node-%s-%03d", n.pool.Name, index), nil
+	}
+	return executeNodeTemplate(n.name, n.pool.TemplateData(index))
+}
+
+// Labels returns the rendered values of the pool's templated labels for the index-th node
+func (n *NodeNamer) Labels(index int) (map[string]string, error) {
+	if len(n.labels) == 0 {
+		return nil, nil
+	}
+	data := n.pool.TemplateData(index)
+	labels := make(map[string]string, len(n.labels))
+	for key, t := range n.labels {
+		value, err := executeNodeTemplate(t, data)
+		if err != nil {
+			return nil, err
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// IsNodeTemplate reports whether a label value is a template rendered per node
+func IsNodeTemplate(value string) bool {
+	return strings.Contains(value, "{{")
+}
+
+// parseNodeTemplate parses a node name or label template
+func parseNodeTemplate(name, text string) (*template.Template, error) {
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return t, nil
+}
+
+// executeNodeTemplate renders a node template
+func executeNodeTemplate(t *template.Template, data NodeTemplateData) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", t.Name(), err)
+	}
+	return b.String(), nil
+}
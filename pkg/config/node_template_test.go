@@ -0,0 +1,66 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNodeNamer(t *testing.T) {
+	tests := []struct {
+		name       string
+		pool       NodePool
+		index      int
+		wantName   string
+		wantLabels map[string]string
+	}{
+		{
+			name:     "default name",
+			pool:     NodePool{Name: "cpu", Labels: map[string]string{"tier": "batch"}},
+			index:    7,
+			wantName: "kwok-node-cpu-007",
+		},
+		{
+			name: "templated name and labels",
+			pool: NodePool{
+				Name:         "gpu",
+				Zones:        []string{"zone-a", "zone-b"},
+				NodesPerRack: 2,
+				NameTemplate: "{{.Pool}}-{{.Zone}}-{{printf \"%04d\" .Index}}",
+				Labels: map[string]string{
+					"tier":              "batch",
+					"example.com/rack":  "{{.Rack}}",
+					"example.com/index": "{{.Index}}",
+				},
+			},
+			index:    5,
+			wantName: "gpu-zone-b-0005",
+			wantLabels: map[string]string{
+				"example.com/rack":  "gpu-zone-b-rack-1",
+				"example.com/index": "5",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namer, err := NewNodeNamer(&tt.pool)
+			if err != nil {
+				t.Fatalf("NewNodeNamer() error = %v", err)
+			}
+			name, err := namer.Name(tt.index)
+			if err != nil {
+				t.Fatalf("Name() error = %v", err)
+			}
+			if name != tt.wantName {
+				t.Errorf("Name() = %q, want %q", name, tt.wantName)
+			}
+			labels, err := namer.Labels(tt.index)
+			if err != nil {
+				t.Fatalf("Labels() error = %v", err)
+			}
+			if !reflect.DeepEqual(labels, tt.wantLabels) {
+				t.Errorf("Labels() = %v, want %v", labels, tt.wantLabels)
+			}
+		})
+	}
+}
@@ -1,7 +1,11 @@
 package config
 
 import (
+	"fmt"
+	"slices"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -19,33 +23,272 @@ type Metadata struct {
 	Annotations map[string]string `yaml:"annotations,omitempty"`
 }
 
+// DescriptionAnnotation describes what a topology or profile demonstrates
+const DescriptionAnnotation = "kueue-bench.io/description"
+
 // TopologySpec defines the desired topology configuration
 type TopologySpec struct {
 	Kueue      *KueueSettings  `yaml:"kueue,omitempty"`
 	Kwok       *KwokSettings   `yaml:"kwok,omitempty"`
 	Clusters   []ClusterConfig `yaml:"clusters"`
 	WorkerSets []WorkerSet     `yaml:"workerSets,omitempty"`
+	// Images are local Docker images loaded into every cluster after creation
+	// (equivalent to `kind load docker-image`), e.g. locally built Kueue images.
+	Images   []string          `yaml:"images,omitempty"`
+	Registry *RegistrySettings `yaml:"registry,omitempty"`
+	// GoldenSnapshots caches the images pulled by the first cluster built with a given
+	// (Kubernetes, KWOK, Kueue) version combination and preloads them into later ones.
+	GoldenSnapshots bool `yaml:"goldenSnapshots,omitempty"`
+	// Extensions are installed into every cluster their targets select, before the
+	// cluster's own extensions
+	Extensions []Extension `yaml:"extensions,omitempty"`
+	// Client overrides the rate limits and timeout of kueue-bench's own API clients
+	Client *ClientSettings `yaml:"client,omitempty"`
+}
+
+// ClientSettings overrides the client-side limits of every API client kueue-bench opens
+// against the topology's clusters. Unset fields keep each client's built-in defaults.
+type ClientSettings struct {
+	// QPS replaces the client-side request rate limit; burst defaults to it
+	QPS   float32 `yaml:"qps,omitempty"`
+	Burst int     `yaml:"burst,omitempty"`
+	// Timeout bounds each API request (e.g. "30s")
+	Timeout string `yaml:"timeout,omitempty"`
 }
 
 // KueueSettings contains Kueue version and Helm values settings
 type KueueSettings struct {
 	Version    string                 `yaml:"version,omitempty"`
 	HelmValues map[string]interface{} `yaml:"helmValues,omitempty"`
+	// Chart installs Kueue from a local chart directory or packaged .tgz instead of the
+	// release registry (path relative to the topology file)
+	Chart string `yaml:"chart,omitempty"`
+	// Manifest installs Kueue by applying a rendered manifest file instead of a Helm chart
+	// (path relative to the topology file)
+	Manifest string `yaml:"manifest,omitempty"`
+	// BuildFrom builds the Kueue image from a source checkout, loads it into every cluster
+	// and installs the checkout's chart with it
+	BuildFrom *KueueBuild `yaml:"buildFrom,omitempty"`
+	// Config sets Kueue controller manager Configuration fields
+	Config *KueueManagerConfig `yaml:"config,omitempty"`
+	// FeatureGates enables or disables Kueue feature gates in every cluster
+	FeatureGates map[string]bool `yaml:"featureGates,omitempty"`
+	// ProvisionConcurrency is how many Kueue objects of one kind are created in parallel
+	// (default: 10)
+	ProvisionConcurrency int `yaml:"provisionConcurrency,omitempty"`
+	// ProbeCapacity submits a Job sized at each ClusterQueue's nominal quota after
+	// provisioning and fails topology creation if it is not admitted
+	ProbeCapacity bool `yaml:"probeCapacity,omitempty"`
+}
+
+// KueueManagerConfig holds commonly tuned Kueue Configuration fields. They are rendered
+// into the chart's manager Configuration on top of any given through helmValues.
+type KueueManagerConfig struct {
+	WaitForPodsReady        *WaitForPodsReadyConfig     `yaml:"waitForPodsReady,omitempty"`
+	FairSharing             *FairSharingConfig          `yaml:"fairSharing,omitempty"`
+	AdmissionFairSharing    *AdmissionFairSharingConfig `yaml:"admissionFairSharing,omitempty"`
+	ExcludeResourcePrefixes []string                    `yaml:"excludeResourcePrefixes,omitempty"`
+	ClientConnection        *ClientConnectionConfig     `yaml:"clientConnection,omitempty"`
+	Integrations            *IntegrationsConfig         `yaml:"integrations,omitempty"`
+	MultiKueue              *MultiKueueConfig           `yaml:"multiKueue,omitempty"`
+	// MultiKueueDispatcher is set on the management cluster from the WorkerSets' dispatch
+	MultiKueueDispatcher string `yaml:"-"`
+}
+
+// WaitForPodsReadyConfig configures Kueue's wait-for-pods-ready admission mode
+type WaitForPodsReadyConfig struct {
+	Enable          bool   `yaml:"enable"`
+	Timeout         string `yaml:"timeout,omitempty"`         // duration, default: 5m
+	BlockAdmission  *bool  `yaml:"blockAdmission,omitempty"`  // default: false
+	RecoveryTimeout string `yaml:"recoveryTimeout,omitempty"` // duration, default: timeout
+}
+
+// FairSharingConfig configures fair sharing between ClusterQueues in a cohort
+type FairSharingConfig struct {
+	Enable               bool     `yaml:"enable"`
+	PreemptionStrategies []string `yaml:"preemptionStrategies,omitempty"`
+}
+
+// AdmissionFairSharingConfig configures usage-based ordering of LocalQueues
+type AdmissionFairSharingConfig struct {
+	UsageHalfLifeTime     string             `yaml:"usageHalfLifeTime,omitempty"`     // duration
+	UsageSamplingInterval string             `yaml:"usageSamplingInterval,omitempty"` // duration, default: 5m
+	ResourceWeights       map[string]float64 `yaml:"resourceWeights,omitempty"`
+}
+
+// ClientConnectionConfig sets the Kueue manager's API server client rate limits
+type ClientConnectionConfig struct {
+	QPS   float32 `yaml:"qps,omitempty"`
+	Burst int32   `yaml:"burst,omitempty"`
+}
+
+// MultiKueueConfig configures the MultiKueue controller on the management cluster
+type MultiKueueConfig struct {
+	WorkerLostTimeout string `yaml:"workerLostTimeout,omitempty"` // duration, default: 15m
+}
+
+// IntegrationsConfig selects the job frameworks Kueue manages. In a MultiKueue topology the
+// same frameworks are enabled on management and worker clusters, so each needs a MultiKueue
+// adapter.
+type IntegrationsConfig struct {
+	// Frameworks replaces the Configuration's integrations.frameworks, e.g. batch/job
+	Frameworks []string `yaml:"frameworks,omitempty"`
+}
+
+// Kueue job framework names, as used in integrations.frameworks
+const (
+	FrameworkBatchJob        = "batch/job"
+	FrameworkJobSet          = "jobset.x-k8s.io/jobset"
+	FrameworkMPIJob          = "kubeflow.org/mpijob"
+	FrameworkPaddleJob       = "kubeflow.org/paddlejob"
+	FrameworkPyTorchJob      = "kubeflow.org/pytorchjob"
+	FrameworkTFJob           = "kubeflow.org/tfjob"
+	FrameworkXGBoostJob      = "kubeflow.org/xgboostjob"
+	FrameworkJAXJob          = "kubeflow.org/jaxjob"
+	FrameworkTrainJob        = "trainer.kubeflow.org/trainjob"
+	FrameworkRayJob          = "ray.io/rayjob"
+	FrameworkRayCluster      = "ray.io/raycluster"
+	FrameworkRayService      = "ray.io/rayservice"
+	FrameworkAppWrapper      = "workload.codeflare.dev/appwrapper"
+	FrameworkPod             = "pod"
+	FrameworkDeployment      = "deployment"
+	FrameworkStatefulSet     = "statefulset"
+	FrameworkLeaderWorkerSet = "leaderworkerset.x-k8s.io/leaderworkerset"
+	FrameworkSparkApp        = "sparkoperator.k8s.io/sparkapplication"
+)
+
+// MultiKueueFrameworks are the frameworks MultiKueue has job adapters for, as of Kueue v0.17
+var MultiKueueFrameworks = []string{
+	FrameworkBatchJob, FrameworkJobSet,
+	FrameworkMPIJob, FrameworkPaddleJob, FrameworkPyTorchJob, FrameworkTFJob, FrameworkXGBoostJob, FrameworkJAXJob,
+	FrameworkTrainJob, FrameworkRayJob, FrameworkRayCluster, FrameworkRayService, FrameworkAppWrapper,
+	FrameworkPod, FrameworkStatefulSet, FrameworkLeaderWorkerSet,
+}
+
+// knownFrameworks are all frameworks Kueue integrates with
+var knownFrameworks = append(slices.Clone(MultiKueueFrameworks), FrameworkDeployment, FrameworkSparkApp)
+
+// Fair sharing preemption strategies
+const (
+	PreemptionLessThanOrEqualToFinalShare = "LessThanOrEqualToFinalShare"
+	PreemptionLessThanInitialShare        = "LessThanInitialShare"
+)
+
+// DefaultPreemptionStrategies are used when fair sharing is enabled without
+// preemptionStrategies, matching Kueue's documented default
+var DefaultPreemptionStrategies = []string{PreemptionLessThanOrEqualToFinalShare, PreemptionLessThanInitialShare}
+
+// KueueChartDir is the location of the Kueue Helm chart within a Kueue checkout
+const KueueChartDir = "charts/kueue"
+
+// KueueBuild identifies a Kueue source checkout to build and install
+type KueueBuild struct {
+	// Path is the Kueue repository root (relative to the topology file)
+	Path string `yaml:"path"`
+	// ImageTag tags the built controller image (default: dev)
+	ImageTag string `yaml:"imageTag,omitempty"`
 }
 
-// KwokSettings contains Kwok version settings
+// KwokSettings contains Kwok version and simulation settings
 type KwokSettings struct {
 	Version string `yaml:"version,omitempty"`
+	// Offline installs Kwok from the manifests embedded in kueue-bench instead of downloading them
+	Offline bool `yaml:"offline,omitempty"`
+	// ManifestSHA256 verifies the downloaded Kwok release manifest against a hex SHA-256 digest
+	ManifestSHA256 string `yaml:"manifestSHA256,omitempty"`
+	// PodStartup delays bound pods becoming Running to simulate container startup
+	PodStartup *PodStartupLatency `yaml:"podStartup,omitempty"`
+	// Heartbeat controls how much node heartbeat traffic Kwok generates
+	Heartbeat *HeartbeatSettings `yaml:"heartbeat,omitempty"`
+}
+
+// Heartbeat modes
+const (
+	HeartbeatModeRealistic = "realistic" // kubelet defaults: 40s leases, 5m status reports
+	HeartbeatModeReduced   = "reduced"   // infrequent leases and status updates for large simulations
+)
+
+// HeartbeatSettings sets the simulated kubelet heartbeat intervals. Mode picks a preset;
+// LeaseDuration and StatusInterval (durations) override it.
+type HeartbeatSettings struct {
+	Mode string `yaml:"mode,omitempty"`
+	// LeaseDuration is the node lease duration; leases are renewed every quarter of it
+	LeaseDuration string `yaml:"leaseDuration,omitempty"`
+	// StatusInterval is how often each node's status heartbeat is written
+	StatusInterval string `yaml:"statusInterval,omitempty"`
+}
+
+// PodStartupLatency is a uniform startup delay between Min and Max (durations, e.g. "5s").
+// Pods matching an override's label use its delay instead; the first matching override wins.
+type PodStartupLatency struct {
+	Min       string               `yaml:"min,omitempty"`
+	Max       string               `yaml:"max,omitempty"`
+	Overrides []PodStartupOverride `yaml:"overrides,omitempty"`
+}
+
+// PodStartupOverride applies a different startup delay to pods carrying a label
+type PodStartupOverride struct {
+	Label string `yaml:"label"` // key=value, e.g. accelerator=gpu
+	Min   string `yaml:"min,omitempty"`
+	Max   string `yaml:"max,omitempty"`
+}
+
+// RegistrySettings configures containerd registry mirrors for all clusters
+type RegistrySettings struct {
+	Mirrors       []RegistryMirror `yaml:"mirrors,omitempty"`
+	LocalRegistry *LocalRegistry   `yaml:"localRegistry,omitempty"`
+}
+
+// RegistryMirror redirects image pulls for an upstream registry to mirror endpoints.
+// Endpoints are tried in order before falling back to the upstream registry.
+type RegistryMirror struct {
+	Registry  string   `yaml:"registry"`  // e.g. docker.io, registry.k8s.io
+	Endpoints []string `yaml:"endpoints"` // e.g. http://mirror.example.com:5000
+}
+
+// LocalRegistry defines a registry container shared by all kind clusters.
+// When RemoteURL is set, the registry runs as a pull-through cache for that upstream.
+type LocalRegistry struct {
+	Name      string `yaml:"name,omitempty"`      // default: kueue-bench-registry
+	Port      int    `yaml:"port,omitempty"`      // host port, default: 5001
+	Image     string `yaml:"image,omitempty"`     // default: registry:2
+	RemoteURL string `yaml:"remoteURL,omitempty"` // upstream to proxy, e.g. https://registry-1.docker.io
 }
 
 // ClusterConfig defines a single cluster configuration
 type ClusterConfig struct {
-	Name              string       `yaml:"name"`
-	Role              string       `yaml:"role"` // standalone, management, worker
-	KubernetesVersion string       `yaml:"kubernetesVersion,omitempty"`
-	NodePools         []NodePool   `yaml:"nodePools"`
-	Kueue             *KueueConfig `yaml:"kueue,omitempty"`
-	Extensions        []Extension  `yaml:"extensions,omitempty"`
+	Name              string             `yaml:"name"`
+	Role              string             `yaml:"role"` // standalone, management, worker
+	KubernetesVersion string             `yaml:"kubernetesVersion,omitempty"`
+	NodePools         []NodePool         `yaml:"nodePools"`
+	Kueue             *KueueConfig       `yaml:"kueue,omitempty"`
+	Extensions        []Extension        `yaml:"extensions,omitempty"`
+	Kwok              *ClusterKwokConfig `yaml:"kwok,omitempty"`
+	// KueueFeatureGates overrides spec.kueue.featureGates for this cluster
+	KueueFeatureGates map[string]bool `yaml:"kueueFeatureGates,omitempty"`
+	// ExternalKubeconfig is set on workers expanded from an external Worker; the cluster
+	// already exists and is only registered with MultiKueue
+	ExternalKubeconfig string `yaml:"-"`
+}
+
+// ClusterKwokConfig customizes KWOK for a single cluster
+type ClusterKwokConfig struct {
+	Stages []KwokStage `yaml:"stages,omitempty"`
+	// Scheduler selects how pods are bound to Kwok nodes (default: kube-scheduler)
+	Scheduler string `yaml:"scheduler,omitempty"`
+}
+
+// Pod scheduling modes
+const (
+	SchedulerKube   = "kube-scheduler" // pods are scheduled by the cluster's kube-scheduler
+	SchedulerBypass = "bypass"         // generated workload pods are bound directly to Kwok nodes
+)
+
+// KwokStage is a KWOK Stage manifest given inline or as a file path (relative to the
+// topology file). A stage named like an embedded stage replaces it; others are added.
+type KwokStage struct {
+	Inline string `yaml:"inline,omitempty"`
+	File   string `yaml:"file,omitempty"`
 }
 
 // Extension defines an additional component to install in a cluster
@@ -53,32 +296,183 @@ type Extension struct {
 	Name     string             `yaml:"name"`
 	Helm     *HelmExtension     `yaml:"helm,omitempty"`
 	Manifest *ManifestExtension `yaml:"manifest,omitempty"`
+	// Kustomize builds a kustomization and applies the result
+	Kustomize *KustomizeExtension `yaml:"kustomize,omitempty"`
+	// Preset installs a built-in, pinned Helm chart (see ExtensionPresets). Helm fields
+	// set alongside it override the preset's.
+	Preset string `yaml:"preset,omitempty"`
+	// PostInstall are run in order once the extension is installed, e.g. to wait for an
+	// operator's CRDs and then create its custom resources
+	PostInstall []PostInstallAction `yaml:"postInstall,omitempty"`
+	// Targets selects the clusters a topology-level extension is installed into
+	// (default: all clusters). Not allowed on cluster or workerSet extensions.
+	Targets *ExtensionTargets `yaml:"targets,omitempty"`
+}
+
+// ExtensionTargets selects clusters by role or name. A cluster is selected if it has one
+// of Roles or is named in Clusters; WorkerSet workers have role worker.
+type ExtensionTargets struct {
+	Roles    []string `yaml:"roles,omitempty"`
+	Clusters []string `yaml:"clusters,omitempty"`
+}
+
+// Selects reports whether the targets select a cluster. Nil targets select every cluster.
+func (t *ExtensionTargets) Selects(c ClusterConfig) bool {
+	if t == nil {
+		return true
+	}
+	return slices.Contains(t.Roles, c.Role) || slices.Contains(t.Clusters, c.Name)
+}
+
+// PostInstallAction is a step run after an extension is installed. Exactly one of
+// Namespace, WaitForCRD or Manifest is set.
+type PostInstallAction struct {
+	// Namespace creates a namespace if it does not exist
+	Namespace string `yaml:"namespace,omitempty"`
+	// WaitForCRD waits for a CRD, e.g. "jobsets.jobset.x-k8s.io", to be Established
+	WaitForCRD string `yaml:"waitForCRD,omitempty"`
+	// Manifest applies manifests from a URL or local files
+	Manifest *ManifestExtension `yaml:"manifest,omitempty"`
+	// Timeout bounds WaitForCRD (default: "2m")
+	Timeout string `yaml:"timeout,omitempty"`
 }
 
 // HelmExtension defines a Helm chart to install
 type HelmExtension struct {
 	Chart           string            `yaml:"chart"`
+	Repo            string            `yaml:"repo,omitempty"` // chart repository URL, for non-OCI charts
 	Version         string            `yaml:"version,omitempty"`
 	ReleaseName     string            `yaml:"releaseName,omitempty"`
 	Namespace       string            `yaml:"namespace,omitempty"`
 	CreateNamespace *bool             `yaml:"createNamespace,omitempty"` // default: true
 	Wait            *bool             `yaml:"wait,omitempty"`            // default: true
 	Timeout         string            `yaml:"timeout,omitempty"`         // default: "5m"
-	Set             map[string]string `yaml:"set,omitempty"`
+	Set             map[string]string `yaml:"set,omitempty"`             // applied on top of valuesFile and values
+
+	// ValuesFile is a Helm values file (path relative to the topology file)
+	ValuesFile string `yaml:"valuesFile,omitempty"`
+	// Values are structured Helm values, applied on top of ValuesFile
+	Values map[string]interface{} `yaml:"values,omitempty"`
+	// Auth holds credentials for a private chart registry or repository
+	Auth *HelmAuth `yaml:"auth,omitempty"`
 }
 
-// ManifestExtension defines a raw manifest to apply from a URL
+// HelmAuth holds credentials for pulling a chart. Secrets are read from the named
+// environment variables so they stay out of topology files. Without auth, Helm's registry
+// config and the Docker config (~/.docker/config.json) are used, as helm install does.
+type HelmAuth struct {
+	// ConfigFile is a Docker-style registry config file with OCI registry credentials
+	// (path relative to the topology file)
+	ConfigFile       string `yaml:"configFile,omitempty"`
+	UsernameEnv      string `yaml:"usernameEnv,omitempty"`
+	PasswordEnv      string `yaml:"passwordEnv,omitempty"`
+	IdentityTokenEnv string `yaml:"identityTokenEnv,omitempty"` // OCI registry identity (refresh) token
+}
+
+// KustomizeExtension defines a kustomization to build and apply
+type KustomizeExtension struct {
+	// URL is a remote kustomization, e.g. "https://github.com/org/repo//config/default?ref=v1.0.0"
+	URL string `yaml:"url,omitempty"`
+	// Path is a local kustomization directory (relative to the topology file)
+	Path string `yaml:"path,omitempty"`
+}
+
+// ManifestExtension defines raw manifests to apply from a URL or local files
 type ManifestExtension struct {
-	URL string `yaml:"url"`
+	URL string `yaml:"url,omitempty"`
+	// Path is a local file, a directory of manifests or a glob pattern (relative to the
+	// topology file). Files are applied in lexical order.
+	Path string `yaml:"path,omitempty"`
+	// SHA256 is the expected SHA-256 (hex) of the manifest at URL; downloads that do not
+	// match are rejected
+	SHA256 string `yaml:"sha256,omitempty"`
+	// Auth sends credentials with the download of URL, e.g. for a private artifact host
+	Auth *ManifestAuth `yaml:"auth,omitempty"`
+}
+
+// ManifestAuth names the environment variables holding credentials for a manifest
+// download, so secrets stay out of topology files. Use either a bearer token or basic auth.
+type ManifestAuth struct {
+	BearerTokenEnv string `yaml:"bearerTokenEnv,omitempty"`
+	UsernameEnv    string `yaml:"usernameEnv,omitempty"`
+	PasswordEnv    string `yaml:"passwordEnv,omitempty"`
 }
 
 // NodePool defines a pool of simulated nodes
 type NodePool struct {
-	Name      string            `yaml:"name"`
-	Count     int               `yaml:"count"`
-	Resources map[string]string `yaml:"resources"`
-	Labels    map[string]string `yaml:"labels,omitempty"`
-	Taints    []Taint           `yaml:"taints,omitempty"`
+	Name  string `yaml:"name"`
+	Count int    `yaml:"count"`
+	// NameTemplate is a Go template for node names, rendered with NodeTemplateData
+	// (e.g. "{{.Zone}}-gpu-{{.Index}}"). Default: kwok-node-<pool>-<index>
+	NameTemplate string            `yaml:"nameTemplate,omitempty"`
+	Resources    map[string]string `yaml:"resources"`
+	// Labels values may be templates rendered per node, like NameTemplate
+	Labels map[string]string `yaml:"labels,omitempty"`
+	Taints []Taint           `yaml:"taints,omitempty"`
+	// Zones spreads nodes round-robin across simulated zones (topology.kubernetes.io/zone)
+	Zones []string `yaml:"zones,omitempty"`
+	// Region sets topology.kubernetes.io/region on every node
+	Region string `yaml:"region,omitempty"`
+	// NodesPerRack groups each zone's nodes into racks of this size (topology.kueue-bench.io/rack)
+	NodesPerRack int `yaml:"nodesPerRack,omitempty"`
+	// SystemReserved is subtracted from capacity to give node allocatable, as kubelet does
+	SystemReserved map[string]string `yaml:"systemReserved,omitempty"`
+	// Devices are simulated DRA devices published in a ResourceSlice for each node
+	Devices []DeviceSet `yaml:"devices,omitempty"`
+	// MIG advertises MIG-partitioned GPUs as nvidia.com/mig-<profile> resources
+	MIG *MIGConfig `yaml:"mig,omitempty"`
+	// CostPerHour is the hourly cost of each node, in any currency, for run cost reports.
+	// Recorded on the nodes in the NodeCostAnnotation.
+	CostPerHour float64 `yaml:"costPerHour,omitempty"`
+}
+
+// NodeCostAnnotation holds the hourly cost of a simulated node, from its pool's costPerHour
+const NodeCostAnnotation = "kueue-bench.io/cost-per-hour"
+
+// MIGConfig describes identically partitioned MIG GPUs on every node of a pool
+type MIGConfig struct {
+	Model    string         `yaml:"model"`    // GPU model, e.g. a100-40gb
+	GPUs     int            `yaml:"gpus"`     // GPUs per node
+	Profiles map[string]int `yaml:"profiles"` // MIG profile -> instances per GPU, e.g. 1g.5gb: 7
+}
+
+// DeviceSet describes identical DRA devices a simulated driver publishes on every node of a pool
+type DeviceSet struct {
+	Driver string `yaml:"driver"` // e.g. gpu.example.com
+	Count  int    `yaml:"count"`  // devices per node
+	// DeviceClass, if set, creates a DeviceClass of this name selecting the driver's devices
+	DeviceClass string            `yaml:"deviceClass,omitempty"`
+	Attributes  map[string]string `yaml:"attributes,omitempty"` // string attributes, e.g. model: a100
+	Capacity    map[string]string `yaml:"capacity,omitempty"`   // quantities, e.g. memory: 80Gi
+}
+
+// Allocatable returns the pool's per-node allocatable resources: Capacity minus
+// SystemReserved, floored at zero.
+func (p *NodePool) Allocatable() (map[string]string, error) {
+	capacities := p.Capacity()
+	allocatable := make(map[string]string, len(capacities))
+	for name, capacity := range capacities {
+		reserved, ok := p.SystemReserved[name]
+		if !ok {
+			allocatable[name] = capacity
+			continue
+		}
+
+		q, err := resource.ParseQuantity(capacity)
+		if err != nil {
+			return nil, fmt.Errorf("invalid resource quantity for %s: %w", name, err)
+		}
+		r, err := resource.ParseQuantity(reserved)
+		if err != nil {
+			return nil, fmt.Errorf("invalid systemReserved quantity for %s: %w", name, err)
+		}
+		q.Sub(r)
+		if q.Sign() < 0 {
+			q = resource.Quantity{}
+		}
+		allocatable[name] = q.String()
+	}
+	return allocatable, nil
 }
 
 // Taint represents a Kubernetes node taint
@@ -91,12 +485,79 @@ type Taint struct {
 // KueueConfig defines Kueue objects for a cluster
 type KueueConfig struct {
 	Cohorts         []Cohort                `yaml:"cohorts,omitempty"`
+	Topologies      []KueueTopology         `yaml:"topologies,omitempty"`
 	ResourceFlavors []ResourceFlavor        `yaml:"resourceFlavors,omitempty"`
 	ClusterQueues   []ClusterQueue          `yaml:"clusterQueues,omitempty"`
 	LocalQueues     []LocalQueue            `yaml:"localQueues,omitempty"`
 	PriorityClasses []WorkloadPriorityClass `yaml:"priorityClasses,omitempty"`
+	// LocalQueueGenerators fan out namespaces of LocalQueues for ClusterQueues; topology
+	// create provisions them like localQueues and namespaces
+	LocalQueueGenerators []LocalQueueGenerator `yaml:"localQueueGenerators,omitempty"`
+	// AdmissionChecks are referenced by name from ClusterQueue admissionChecks
+	AdmissionChecks []AdmissionCheck `yaml:"admissionChecks,omitempty"`
+	// ProvisioningRequestConfigs are referenced from AdmissionCheck parameters
+	ProvisioningRequestConfigs []ProvisioningRequestConfig `yaml:"provisioningRequestConfigs,omitempty"`
+	// Namespaces are created with labels, e.g. for ClusterQueue namespaceSelectors.
+	// LocalQueue namespaces not listed here are created without labels.
+	Namespaces []Namespace `yaml:"namespaces,omitempty"`
+	// PodPriorityClasses are Kubernetes (scheduling.k8s.io) PriorityClasses, which set the
+	// priority kube-scheduler preempts pods by on nodes, unlike Kueue's PriorityClasses
+	PodPriorityClasses []PodPriorityClass `yaml:"podPriorityClasses,omitempty"`
+}
+
+// Namespace is a namespace created before LocalQueues
+type Namespace struct {
+	Name   string            `yaml:"name"`
+	Labels map[string]string `yaml:"labels,omitempty"`
+	// ResourceQuota is the hard limit of a ResourceQuota created in the namespace, e.g.
+	// requests.cpu: "64" or count/jobs.batch: "100", interacting with Kueue's quotas
+	ResourceQuota map[string]string `yaml:"resourceQuota,omitempty"`
+	// LimitRange sets container resource defaults and bounds in the namespace
+	LimitRange *LimitRange `yaml:"limitRange,omitempty"`
+}
+
+// FixtureName names the ResourceQuota and LimitRange created in declared namespaces
+const FixtureName = "kueue-bench"
+
+// LimitRange is the Container limits of a Kubernetes LimitRange, by resource name
+type LimitRange struct {
+	// Default is the limit of containers that set none
+	Default map[string]string `yaml:"default,omitempty"`
+	// DefaultRequest is the request of containers that set none
+	DefaultRequest map[string]string `yaml:"defaultRequest,omitempty"`
+	Min            map[string]string `yaml:"min,omitempty"`
+	Max            map[string]string `yaml:"max,omitempty"`
+}
+
+// PodPriorityClass represents a Kubernetes (scheduling.k8s.io) PriorityClass
+type PodPriorityClass struct {
+	Name        string `yaml:"name"`
+	Value       int32  `yaml:"value"`
+	Description string `yaml:"description,omitempty"`
+	// PreemptionPolicy is PreemptLowerPriority (default) or Never
+	PreemptionPolicy string `yaml:"preemptionPolicy,omitempty"`
+	// GlobalDefault gives pods without a priorityClassName this class
+	GlobalDefault bool `yaml:"globalDefault,omitempty"`
+}
+
+// AllPodPriorityClasses returns the declared podPriorityClasses followed by those generated
+// for priorityClasses with podPriorityClass set
+func (k *KueueConfig) AllPodPriorityClasses() []PodPriorityClass {
+	classes := append([]PodPriorityClass(nil), k.PodPriorityClasses...)
+	for _, wpc := range k.PriorityClasses {
+		if wpc.PodPriorityClass {
+			classes = append(classes, PodPriorityClass{Name: wpc.Name, Value: wpc.Value, Description: wpc.Description})
+		}
+	}
+	return classes
 }
 
+// Pod preemption policies
+const (
+	PreemptLowerPriority = "PreemptLowerPriority"
+	PreemptNever         = "Never"
+)
+
 // Cohort represents a Kueue Cohort for hierarchical cohorts
 type Cohort struct {
 	Name           string          `yaml:"name"`
@@ -105,16 +566,25 @@ type Cohort struct {
 	FairSharing    *FairSharing    `yaml:"fairSharing,omitempty"`
 }
 
-// FairSharing defines fair sharing configuration for cohorts and cluster queues
+// FairSharing defines fair sharing configuration for cohorts, cluster queues and local queues
 type FairSharing struct {
-	Weight int32 `yaml:"weight"`
+	// Weight is a non-negative quantity such as "2" or "0.5" (default: 1)
+	Weight string `yaml:"weight"`
+}
+
+// KueueTopology represents a Kueue Topology for Topology-Aware Scheduling (TAS).
+// Levels are node label keys ordered from the broadest domain to the narrowest.
+type KueueTopology struct {
+	Name   string   `yaml:"name"`
+	Levels []string `yaml:"levels"`
 }
 
 // ResourceFlavor represents a Kueue ResourceFlavor
 type ResourceFlavor struct {
-	Name        string              `yaml:"name"`
-	NodeLabels  map[string]string   `yaml:"nodeLabels,omitempty"`
-	Tolerations []corev1.Toleration `yaml:"tolerations,omitempty"`
+	Name         string              `yaml:"name"`
+	NodeLabels   map[string]string   `yaml:"nodeLabels,omitempty"`
+	Tolerations  []corev1.Toleration `yaml:"tolerations,omitempty"`
+	TopologyName string              `yaml:"topologyName,omitempty"`
 }
 
 // ClusterQueue represents a Kueue ClusterQueue
@@ -171,32 +641,168 @@ type LocalQueue struct {
 	Name         string `yaml:"name"`
 	Namespace    string `yaml:"namespace"`
 	ClusterQueue string `yaml:"clusterQueue"`
+	// StopPolicy stops admission to the queue: None (default), Hold or HoldAndDrain
+	StopPolicy string `yaml:"stopPolicy,omitempty"`
+	// FairSharing weights the queue's usage when AdmissionFairSharing orders LocalQueues
+	FairSharing *FairSharing `yaml:"fairSharing,omitempty"`
 }
 
+// LocalQueueGenerator generates Namespaces namespaces, each with QueuesPerNamespace
+// LocalQueues pointing to ClusterQueue, e.g. to test how Kueue scales with the number of
+// LocalQueues or to spread load over many namespaces
+type LocalQueueGenerator struct {
+	ClusterQueue       string `yaml:"clusterQueue"`
+	Namespaces         int    `yaml:"namespaces"`
+	QueuesPerNamespace int    `yaml:"queuesPerNamespace"`
+	// NamespacePattern names the namespaces: {cq} is replaced by the ClusterQueue name and
+	// {ns} by the namespace index, from 0. Defaults to DefaultNamespacePattern.
+	NamespacePattern string `yaml:"namespacePattern,omitempty"`
+	// QueuePattern names the LocalQueues of each namespace: {cq} and {ns} are replaced as in
+	// NamespacePattern and {lq} by the queue index within its namespace, from 0. Defaults
+	// to DefaultQueuePattern.
+	QueuePattern string `yaml:"queuePattern,omitempty"`
+	// NamespaceLabels are set on generated namespaces that are not declared in namespaces
+	NamespaceLabels map[string]string `yaml:"namespaceLabels,omitempty"`
+}
+
+// Default LocalQueueGenerator naming patterns
+const (
+	DefaultNamespacePattern = "{cq}-ns-{ns}"
+	DefaultQueuePattern     = "{cq}-lq-{lq}"
+)
+
+// LocalQueue stop policies
+const (
+	StopPolicyNone         = "None"
+	StopPolicyHold         = "Hold"
+	StopPolicyHoldAndDrain = "HoldAndDrain"
+)
+
 // WorkloadPriorityClass represents a Kueue WorkloadPriorityClass
 type WorkloadPriorityClass struct {
 	Name        string `yaml:"name"`
 	Value       int32  `yaml:"value"`
 	Description string `yaml:"description,omitempty"`
+	// PodPriorityClass also creates a Kubernetes PriorityClass with the same name and
+	// value, so pods of the class's workloads preempt by the same priority on nodes
+	PodPriorityClass bool `yaml:"podPriorityClass,omitempty"`
+}
+
+// AdmissionCheck represents a Kueue AdmissionCheck handled by the named controller,
+// e.g. kueue.x-k8s.io/provisioning-request
+type AdmissionCheck struct {
+	Name           string                    `yaml:"name"`
+	ControllerName string                    `yaml:"controllerName"`
+	Parameters     *AdmissionCheckParameters `yaml:"parameters,omitempty"`
+}
+
+// kueueAPIGroup is the default API group of AdmissionCheck parameters
+const kueueAPIGroup = "kueue.x-k8s.io"
+
+// AdmissionCheckParameters references the object configuring an AdmissionCheck.
+// APIGroup defaults to kueue.x-k8s.io.
+type AdmissionCheckParameters struct {
+	APIGroup string `yaml:"apiGroup,omitempty"`
+	Kind     string `yaml:"kind"`
+	Name     string `yaml:"name"`
+}
+
+// ProvisioningRequestConfig represents a Kueue ProvisioningRequestConfig, which configures
+// the ProvisioningRequests created for workloads by a provisioning-request AdmissionCheck
+type ProvisioningRequestConfig struct {
+	Name                  string                     `yaml:"name"`
+	ProvisioningClassName string                     `yaml:"provisioningClassName"`
+	Parameters            map[string]string          `yaml:"parameters,omitempty"`
+	ManagedResources      []string                   `yaml:"managedResources,omitempty"`
+	RetryStrategy         *ProvisioningRetryStrategy `yaml:"retryStrategy,omitempty"`
+}
+
+// ProvisioningRetryStrategy defines how failed ProvisioningRequests are retried
+type ProvisioningRetryStrategy struct {
+	BackoffLimitCount  *int32 `yaml:"backoffLimitCount,omitempty"`
+	BackoffBaseSeconds *int32 `yaml:"backoffBaseSeconds,omitempty"`
+	BackoffMaxSeconds  *int32 `yaml:"backoffMaxSeconds,omitempty"`
 }
 
 // WorkerSet defines a group of homogeneous workers for MultiKueue.
 // All workers share identical Kueue object structure (names, relationships);
 // values (labels, quotas) are derived from each worker's node pools.
 type WorkerSet struct {
-	Name            string                  `yaml:"name"`
-	Extensions      []Extension             `yaml:"extensions,omitempty"`
+	Name string `yaml:"name"`
+	// ManagementRef names the management cluster that dispatches to the workers; required when
+	// the topology has more than one management cluster
+	ManagementRef string             `yaml:"managementRef,omitempty"`
+	Extensions    []Extension        `yaml:"extensions,omitempty"`
+	Kwok          *ClusterKwokConfig `yaml:"kwok,omitempty"`
+	// KueueFeatureGates overrides spec.kueue.featureGates for every worker
+	KueueFeatureGates map[string]bool `yaml:"kueueFeatureGates,omitempty"`
+	// Credentials selects how the management cluster authenticates to workers
+	// (CredentialsAdmin or CredentialsServiceAccount; default admin)
+	Credentials string `yaml:"credentials,omitempty"`
+	// KueueNamespace is the namespace Kueue runs in; MultiKueue kubeconfig Secrets and scoped
+	// worker ServiceAccounts are created there (default: kueue-system)
+	KueueNamespace string `yaml:"kueueNamespace,omitempty"`
+	// SecretNameTemplate is a Go template for worker kubeconfig Secret names, rendered with
+	// SecretNameData (default: "{{.Worker}}-kubeconfig")
+	SecretNameTemplate string `yaml:"secretNameTemplate,omitempty"`
+	// Dispatch configures how MultiKueue dispatches workloads to the workers
+	Dispatch *WorkerSetDispatch `yaml:"dispatch,omitempty"`
+	// Network impairs traffic from the workers to the management cluster, e.g. to model
+	// workers in another region
+	Network         *WorkerSetNetwork       `yaml:"network,omitempty"`
 	ResourceFlavors []WorkerSetFlavor       `yaml:"resourceFlavors"`
 	ClusterQueues   []WorkerSetClusterQueue `yaml:"clusterQueues"`
 	LocalQueues     []LocalQueue            `yaml:"localQueues,omitempty"`
 	Workers         []Worker                `yaml:"workers"`
 }
 
+// MultiKueue worker credential modes
+const (
+	CredentialsAdmin          = "admin"          // kubeconfig Secrets hold the worker's admin kubeconfig
+	CredentialsServiceAccount = "serviceAccount" // kubeconfig Secrets hold a token for a ServiceAccount scoped to MultiKueue
+)
+
+// WorkerSetDispatch configures MultiKueue workload dispatch for a WorkerSet
+type WorkerSetDispatch struct {
+	// Dispatcher selects Kueue's MultiKueue dispatcher (DispatcherAllAtOnce or
+	// DispatcherIncremental). Kueue's dispatcher is cluster-wide, so WorkerSets must agree.
+	Dispatcher string `yaml:"dispatcher,omitempty"`
+	// Order ranks workers by preference; unlisted workers follow in spec order
+	Order []string `yaml:"order,omitempty"`
+}
+
+// MultiKueue dispatchers
+const (
+	DispatcherAllAtOnce   = "allAtOnce"   // every worker is nominated at once; the first to admit wins
+	DispatcherIncremental = "incremental" // workers are nominated up to 3 at a time, in MultiKueueCluster name order
+)
+
+// WorkerSetNetwork shapes traffic from a WorkerSet's workers to the management cluster with
+// tc netem. The API server responses MultiKueue waits on take this path, so latency adds to
+// every round trip.
+type WorkerSetNetwork struct {
+	// Latency is the delay added to each packet, e.g. "50ms"
+	Latency string `yaml:"latency,omitempty"`
+	// Jitter varies the delay by up to this much, e.g. "10ms"; requires latency
+	Jitter string `yaml:"jitter,omitempty"`
+	// Loss is the percentage of packets dropped (0-100)
+	Loss float64 `yaml:"loss,omitempty"`
+	// Rate limits bandwidth, in tc units, e.g. "10mbit"
+	Rate string `yaml:"rate,omitempty"`
+}
+
 // WorkerSetFlavor maps a flavor to a node pool. At expansion time, the flavor's
 // nodeLabels and tolerations are derived from the referenced pool in each worker.
 type WorkerSetFlavor struct {
 	Name        string `yaml:"name"`
 	NodePoolRef string `yaml:"nodePoolRef"`
+	// Workers limits the flavor to the named workers; empty means every worker
+	Workers []string `yaml:"workers,omitempty"`
+}
+
+// AppliesTo reports whether the flavor is provided by the named worker
+func (f WorkerSetFlavor) AppliesTo(worker string) bool {
+	return len(f.Workers) == 0 || slices.Contains(f.Workers, worker)
 }
 
 // WorkerSetClusterQueue defines ClusterQueue structure at the WorkerSet level.
@@ -227,7 +833,24 @@ type WorkerSetFlavorRef struct {
 // Each Worker becomes a ClusterConfig after expansion.
 type Worker struct {
 	Name      string     `yaml:"name"`
-	NodePools []NodePool `yaml:"nodePools"`
+	NodePools []NodePool `yaml:"nodePools,omitempty"`
+	// Kubeconfig references an existing cluster instead of simulating one from node pools
+	// (relative to the topology file)
+	Kubeconfig string `yaml:"kubeconfig,omitempty"`
+	// CapacityWeight scales the worker's derived quotas, e.g. 0.1 for a cluster lending a
+	// tenth of its pools to the WorkerSet (default 1)
+	CapacityWeight *float64 `yaml:"capacityWeight,omitempty"`
+	// Standby excludes the worker's quotas from the management ClusterQueues, so it only
+	// takes workloads the other workers cannot
+	Standby bool `yaml:"standby,omitempty"`
+}
+
+// Weight returns the worker's capacity weight, 1 unless set
+func (w Worker) Weight() float64 {
+	if w.CapacityWeight == nil {
+		return 1
+	}
+	return *w.CapacityWeight
 }
 
 // TopologyMetadata stores runtime information about a created topology
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNodePoolAllocatable(t *testing.T) {
+	tests := []struct {
+		name           string
+		resources      map[string]string
+		systemReserved map[string]string
+		mig            *MIGConfig
+		want           map[string]string
+	}{
+		{
+			name:      "no reservation",
+			resources: map[string]string{"cpu": "8", "memory": "32Gi"},
+			want:      map[string]string{"cpu": "8", "memory": "32Gi", "pods": "110"},
+		},
+		{
+			name:           "partial reservation",
+			resources:      map[string]string{"cpu": "8", "memory": "32Gi", "nvidia.com/gpu": "4"},
+			systemReserved: map[string]string{"cpu": "500m", "memory": "2Gi"},
+			want:           map[string]string{"cpu": "7500m", "memory": "30Gi", "nvidia.com/gpu": "4", "pods": "110"},
+		},
+		{
+			name:           "reservation equal to capacity",
+			resources:      map[string]string{"cpu": "1"},
+			systemReserved: map[string]string{"cpu": "1"},
+			want:           map[string]string{"cpu": "0", "pods": "110"},
+		},
+		{
+			name:      "mig resources",
+			resources: map[string]string{"cpu": "96"},
+			mig:       &MIGConfig{Model: "a100-40gb", GPUs: 8, Profiles: map[string]int{"1g.5gb": 3, "2g.10gb": 2}},
+			want:      map[string]string{"cpu": "96", "nvidia.com/mig-1g.5gb": "24", "nvidia.com/mig-2g.10gb": "16", "pods": "110"},
+		},
+		{
+			name:           "mig resources with reservation",
+			mig:            &MIGConfig{Model: "a100-40gb", GPUs: 2, Profiles: map[string]int{"7g.40gb": 1}},
+			systemReserved: map[string]string{"nvidia.com/mig-7g.40gb": "1"},
+			want:           map[string]string{"nvidia.com/mig-7g.40gb": "1", "pods": "110"},
+		},
+		{
+			name:           "pods reserved from the default capacity",
+			resources:      map[string]string{"cpu": "8"},
+			systemReserved: map[string]string{"pods": "10"},
+			want:           map[string]string{"cpu": "8", "pods": "100"},
+		},
+		{
+			name:           "pods reserved from an explicit capacity",
+			resources:      map[string]string{"cpu": "8", "pods": "250"},
+			systemReserved: map[string]string{"pods": "50"},
+			want:           map[string]string{"cpu": "8", "pods": "200"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := NodePool{Name: "p", Count: 1, Resources: tt.resources, SystemReserved: tt.systemReserved, MIG: tt.mig}
+			got, err := pool.Allocatable()
+			if err != nil {
+				t.Fatalf("Allocatable() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Allocatable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtensionResolvePreset(t *testing.T) {
+	plain := Extension{Name: "custom", Helm: &HelmExtension{Chart: "oci://example.com/chart"}}
+	if got, err := plain.ResolvePreset(); err != nil || !reflect.DeepEqual(got, plain) {
+		t.Errorf("ResolvePreset() without preset = %+v, %v; want unchanged", got, err)
+	}
+
+	wait := false
+	ext := Extension{
+		Name:   "cert-manager",
+		Preset: PresetCertManager,
+		Helm: &HelmExtension{
+			Version: "v1.17.0",
+			Wait:    &wait,
+			Values:  map[string]interface{}{"replicaCount": 2, "crds": map[string]interface{}{"keep": false}},
+		},
+	}
+	got, err := ext.ResolvePreset()
+	if err != nil {
+		t.Fatalf("ResolvePreset() error = %v", err)
+	}
+	want := &HelmExtension{
+		Chart:     "oci://quay.io/jetstack/charts/cert-manager",
+		Version:   "v1.17.0",
+		Namespace: "cert-manager",
+		Wait:      &wait,
+		Values:    map[string]interface{}{"crds": map[string]interface{}{"enabled": true, "keep": false}, "replicaCount": 2},
+	}
+	if !reflect.DeepEqual(got.Helm, want) {
+		t.Errorf("ResolvePreset() helm = %+v, want %+v", got.Helm, want)
+	}
+	// The preset's own values are not modified
+	if _, ok := extensionPresets[PresetCertManager].Values["crds"].(map[string]interface{})["keep"]; ok {
+		t.Errorf("ResolvePreset() modified the preset's values")
+	}
+
+	if _, err := (Extension{Name: "x", Preset: "volcano"}).ResolvePreset(); err == nil {
+		t.Errorf("ResolvePreset() with unknown preset: expected error")
+	}
+}
+
+func TestAllPodPriorityClasses(t *testing.T) {
+	k := &KueueConfig{
+		PriorityClasses: []WorkloadPriorityClass{
+			{Name: "batch", Value: 100},
+			{Name: "serving", Value: 1000, Description: "latency sensitive", PodPriorityClass: true},
+		},
+		PodPriorityClasses: []PodPriorityClass{{Name: "system-adjacent", Value: 5000, PreemptionPolicy: PreemptNever}},
+	}
+	want := []PodPriorityClass{
+		{Name: "system-adjacent", Value: 5000, PreemptionPolicy: PreemptNever},
+		{Name: "serving", Value: 1000, Description: "latency sensitive"},
+	}
+	if got := k.AllPodPriorityClasses(); !reflect.DeepEqual(got, want) {
+		t.Errorf("AllPodPriorityClasses() = %+v, want %+v", got, want)
+	}
+}
@@ -2,9 +2,17 @@ package config
 
 import (
 	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
@@ -35,6 +43,43 @@ func ValidateTopology(t *Topology) error {
 		return fmt.Errorf("at least one cluster or workerSet is required")
 	}
 
+	if err := validateImages(t.Spec.Images); err != nil {
+		return err
+	}
+
+	if t.Spec.Registry != nil {
+		if err := validateRegistry(t.Spec.Registry); err != nil {
+			return err
+		}
+	}
+
+	if t.Spec.Kwok != nil {
+		if err := validateKwokSettings(t.Spec.Kwok); err != nil {
+			return err
+		}
+	}
+
+	if t.Spec.Client != nil {
+		if err := validateClientSettings(t.Spec.Client); err != nil {
+			return err
+		}
+	}
+
+	if t.Spec.Kueue != nil {
+		if err := validateKueueSettings(t.Spec.Kueue); err != nil {
+			return err
+		}
+		if t.Spec.Kueue.Manifest != "" && hasClusterFeatureGates(t) {
+			return fmt.Errorf("kueueFeatureGates cannot be used with kueue.manifest")
+		}
+		if t.Spec.Kueue.Manifest != "" && MultiKueueDispatcher(t.Spec.WorkerSets) != "" {
+			return fmt.Errorf("workerSet dispatch.dispatcher cannot be used with kueue.manifest")
+		}
+		if err := validateMultiKueueIntegrations(t.Spec.Kueue, t.Spec.WorkerSets); err != nil {
+			return err
+		}
+	}
+
 	clusterNames := make(map[string]bool, len(t.Spec.Clusters))
 	for i, cluster := range t.Spec.Clusters {
 		if err := validateCluster(&cluster, i); err != nil {
@@ -43,15 +88,27 @@ func ValidateTopology(t *Topology) error {
 		clusterNames[cluster.Name] = true
 	}
 
-	if err := validateWorkerSets(t.Spec.WorkerSets, clusterNames); err != nil {
+	if err := validateWorkerSets(t.Spec.WorkerSets, clusterNames, soleManagementCluster(t.Spec.Clusters)); err != nil {
+		return err
+	}
+
+	if err := validateTopologyExtensions(t.Spec); err != nil {
 		return err
 	}
 
-	// If WorkerSets exist, require exactly one management cluster for MultiKueue
+	// If WorkerSets exist, each must be managed by a management cluster
 	if len(t.Spec.WorkerSets) > 0 {
-		if err := validateMultiKueueTopology(t.Spec.Clusters); err != nil {
+		if err := validateMultiKueueTopology(t.Spec.Clusters, t.Spec.WorkerSets); err != nil {
 			return err
 		}
+		for _, c := range t.Spec.Clusters {
+			if c.Role != RoleManagement {
+				continue
+			}
+			if _, err := DeriveMultiKueueNamespaces(WorkerSetsFor(t.Spec.WorkerSets, c.Name), c.Kueue.WithGeneratedLocalQueues()); err != nil {
+				return fmt.Errorf("workerSets of management cluster '%s': %w", c.Name, err)
+			}
+		}
 	}
 
 	return nil
@@ -77,6 +134,10 @@ func validateCluster(c *ClusterConfig, index int) error {
 		}
 	}
 
+	if err := validateNodeNames(c.NodePools); err != nil {
+		return fmt.Errorf("cluster[%d] (%s): %w", index, c.Name, err)
+	}
+
 	if c.Kueue != nil {
 		if err := validateKueueConfig(c.Kueue, index, c.Name); err != nil {
 			return err
@@ -84,11 +145,21 @@ func validateCluster(c *ClusterConfig, index int) error {
 	}
 
 	if len(c.Extensions) > 0 {
-		if err := validateExtensions(c.Extensions, index, c.Name); err != nil {
-			return err
+		if err := validateExtensions(c.Extensions); err != nil {
+			return fmt.Errorf("cluster[%d] (%s): %w", index, c.Name, err)
 		}
 	}
 
+	if c.Kwok != nil {
+		if err := validateClusterKwok(c.Kwok); err != nil {
+			return fmt.Errorf("cluster[%d] (%s): kwok: %w", index, c.Name, err)
+		}
+	}
+
+	if err := validateFeatureGates(c.KueueFeatureGates); err != nil {
+		return fmt.Errorf("cluster[%d] (%s): kueueFeatureGates: %w", index, c.Name, err)
+	}
+
 	return nil
 }
 
@@ -113,16 +184,59 @@ func validateNodePoolContents(p *NodePool) error {
 		return fmt.Errorf("count must be > 0")
 	}
 
-	if len(p.Resources) == 0 {
+	if len(p.Resources) == 0 && p.MIG == nil {
 		return fmt.Errorf("at least one resource is required")
 	}
 
+	if p.CostPerHour < 0 {
+		return fmt.Errorf("costPerHour must be >= 0")
+	}
+
 	for resName, quantity := range p.Resources {
+		if errs := validation.IsQualifiedName(resName); len(errs) > 0 {
+			return fmt.Errorf("invalid resource name '%s': %s", resName, strings.Join(errs, "; "))
+		}
 		if _, err := resource.ParseQuantity(quantity); err != nil {
 			return fmt.Errorf("invalid resource quantity for %s: %w", resName, err)
 		}
 	}
 
+	if p.MIG != nil {
+		if err := validateMIG(p); err != nil {
+			return err
+		}
+	}
+
+	capacities := p.Capacity()
+	for resName, quantity := range p.SystemReserved {
+		capacity, ok := capacities[resName]
+		if !ok {
+			return fmt.Errorf("systemReserved: resource %s is not in the pool's resources", resName)
+		}
+		reserved, err := resource.ParseQuantity(quantity)
+		if err != nil {
+			return fmt.Errorf("systemReserved: invalid quantity for %s: %w", resName, err)
+		}
+		if reserved.Sign() < 0 {
+			return fmt.Errorf("systemReserved: quantity for %s must be >= 0", resName)
+		}
+		if reserved.Cmp(resource.MustParse(capacity)) > 0 {
+			return fmt.Errorf("systemReserved: %s reservation %s exceeds capacity %s", resName, quantity, capacity)
+		}
+	}
+
+	if _, err := NewNodeNamer(p); err != nil {
+		return err
+	}
+	for key, value := range p.Labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid label key '%s': %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); !IsNodeTemplate(value) && len(errs) > 0 {
+			return fmt.Errorf("invalid value for label %s: %s", key, strings.Join(errs, "; "))
+		}
+	}
+
 	for k, taint := range p.Taints {
 		if taint.Effect != "NoSchedule" && taint.Effect != "PreferNoSchedule" && taint.Effect != "NoExecute" {
 			return fmt.Errorf("taint[%d]: invalid effect '%s' (must be NoSchedule, PreferNoSchedule, or NoExecute)",
@@ -130,6 +244,36 @@ func validateNodePoolContents(p *NodePool) error {
 		}
 	}
 
+	seenZones := make(map[string]bool, len(p.Zones))
+	for k, zone := range p.Zones {
+		if errs := validation.IsValidLabelValue(zone); zone == "" || len(errs) > 0 {
+			return fmt.Errorf("zone[%d]: invalid zone name '%s'", k, zone)
+		}
+		if seenZones[zone] {
+			return fmt.Errorf("zone[%d]: duplicate zone '%s'", k, zone)
+		}
+		seenZones[zone] = true
+	}
+
+	if errs := validation.IsValidLabelValue(p.Region); len(errs) > 0 {
+		return fmt.Errorf("invalid region '%s'", p.Region)
+	}
+
+	if p.NodesPerRack < 0 {
+		return fmt.Errorf("nodesPerRack must be >= 0")
+	}
+
+	drivers := make(map[string]bool, len(p.Devices))
+	for k, d := range p.Devices {
+		if err := validateDeviceSet(&d); err != nil {
+			return fmt.Errorf("devices[%d]: %w", k, err)
+		}
+		if drivers[d.Driver] {
+			return fmt.Errorf("devices[%d]: duplicate driver '%s'", k, d.Driver)
+		}
+		drivers[d.Driver] = true
+	}
+
 	return nil
 }
 
@@ -140,13 +284,42 @@ func validateKueueConfig(k *KueueConfig, clusterIndex int, clusterName string) e
 		return err
 	}
 
+	// Validate TAS Topologies
+	topologyNames, err := validateKueueTopologies(k.Topologies, clusterIndex, clusterName)
+	if err != nil {
+		return err
+	}
+
 	// Build a map of resource flavor names for validation
 	flavorNames := make(map[string]bool)
-	for _, rf := range k.ResourceFlavors {
+	for i, rf := range k.ResourceFlavors {
 		if rf.Name == "" {
 			return fmt.Errorf("cluster[%d] (%s): resourceFlavor: name is required", clusterIndex, clusterName)
 		}
 		flavorNames[rf.Name] = true
+
+		if rf.TopologyName != "" {
+			if !topologyNames[rf.TopologyName] {
+				return fmt.Errorf("cluster[%d] (%s): resourceFlavor[%d] (%s): unknown topology '%s'",
+					clusterIndex, clusterName, i, rf.Name, rf.TopologyName)
+			}
+			if len(rf.NodeLabels) == 0 {
+				return fmt.Errorf("cluster[%d] (%s): resourceFlavor[%d] (%s): nodeLabels are required when topologyName is set",
+					clusterIndex, clusterName, i, rf.Name)
+			}
+		}
+	}
+
+	// Validate cohort-level quotas (limits are lent to or borrowed from the parent cohort)
+	for i, cohort := range k.Cohorts {
+		if err := validateResourceGroups(cohort.ResourceGroups, flavorNames, cohort.ParentName != "", "parentName"); err != nil {
+			return fmt.Errorf("cluster[%d] (%s): cohort[%d] (%s): %w", clusterIndex, clusterName, i, cohort.Name, err)
+		}
+	}
+
+	// Validate AdmissionChecks and the ProvisioningRequestConfigs they reference
+	if err := validateAdmissionChecks(k, clusterIndex, clusterName); err != nil {
+		return err
 	}
 
 	// Validate ClusterQueues
@@ -168,23 +341,69 @@ func validateKueueConfig(k *KueueConfig, clusterIndex int, clusterName string) e
 				clusterIndex, clusterName, i, cq.Name)
 		}
 
-		// Validate that referenced flavors exist
-		for j, rg := range cq.ResourceGroups {
-			for k, fq := range rg.Flavors {
-				if !flavorNames[fq.Name] {
-					return fmt.Errorf("cluster[%d] (%s): clusterQueue[%d] (%s): resourceGroup[%d]: flavor[%d]: unknown resourceFlavor '%s'",
-						clusterIndex, clusterName, i, cq.Name, j, k, fq.Name)
-				}
+		if err := validateFairSharing(cq.FairSharing); err != nil {
+			return fmt.Errorf("cluster[%d] (%s): clusterQueue[%d] (%s): %w", clusterIndex, clusterName, i, cq.Name, err)
+		}
 
-				// Validate resource quotas
-				for l, res := range fq.Resources {
-					if _, err := resource.ParseQuantity(res.NominalQuota); err != nil {
-						return fmt.Errorf("cluster[%d] (%s): clusterQueue[%d] (%s): resourceGroup[%d]: flavor[%d]: resource[%d]: invalid nominalQuota: %w",
-							clusterIndex, clusterName, i, cq.Name, j, k, l, err)
-					}
-				}
+		if err := validateResourceGroups(cq.ResourceGroups, flavorNames, cq.Cohort != "", "cohort"); err != nil {
+			return fmt.Errorf("cluster[%d] (%s): clusterQueue[%d] (%s): %w", clusterIndex, clusterName, i, cq.Name, err)
+		}
+	}
+
+	// Validate Namespaces
+	namespaceNames := make(map[string]bool, len(k.Namespaces))
+	for i, ns := range k.Namespaces {
+		if errs := validation.IsDNS1123Label(ns.Name); len(errs) > 0 {
+			return fmt.Errorf("cluster[%d] (%s): namespace[%d]: invalid name '%s': %s",
+				clusterIndex, clusterName, i, ns.Name, strings.Join(errs, "; "))
+		}
+		if namespaceNames[ns.Name] {
+			return fmt.Errorf("cluster[%d] (%s): namespace[%d]: duplicate name '%s'", clusterIndex, clusterName, i, ns.Name)
+		}
+		namespaceNames[ns.Name] = true
+
+		for _, key := range sortedKeys(ns.Labels) {
+			if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+				return fmt.Errorf("cluster[%d] (%s): namespace[%d] (%s): invalid label key '%s': %s",
+					clusterIndex, clusterName, i, ns.Name, key, strings.Join(errs, "; "))
 			}
+			if errs := validation.IsValidLabelValue(ns.Labels[key]); len(errs) > 0 {
+				return fmt.Errorf("cluster[%d] (%s): namespace[%d] (%s): invalid label value '%s': %s",
+					clusterIndex, clusterName, i, ns.Name, ns.Labels[key], strings.Join(errs, "; "))
+			}
+		}
+		if err := validateNamespaceFixtures(ns); err != nil {
+			return fmt.Errorf("cluster[%d] (%s): namespace[%d] (%s): %w", clusterIndex, clusterName, i, ns.Name, err)
+		}
+	}
+
+	if err := validatePodPriorityClasses(k.PodPriorityClasses); err != nil {
+		return fmt.Errorf("cluster[%d] (%s): %w", clusterIndex, clusterName, err)
+	}
+	podPriorityClassNames := make(map[string]bool, len(k.PodPriorityClasses))
+	for _, pc := range k.PodPriorityClasses {
+		podPriorityClassNames[pc.Name] = true
+	}
+	for i, wpc := range k.PriorityClasses {
+		if !wpc.PodPriorityClass {
+			continue
+		}
+		switch {
+		case podPriorityClassNames[wpc.Name]:
+			return fmt.Errorf("cluster[%d] (%s): priorityClass[%d] (%s): podPriorityClass is set, but a PriorityClass '%s' is already defined",
+				clusterIndex, clusterName, i, wpc.Name, wpc.Name)
+		case strings.HasPrefix(wpc.Name, "system-"):
+			return fmt.Errorf("cluster[%d] (%s): priorityClass[%d] (%s): podPriorityClass is set, but the system- prefix is reserved for PriorityClasses",
+				clusterIndex, clusterName, i, wpc.Name)
+		case wpc.Value > maxPodPriority:
+			return fmt.Errorf("cluster[%d] (%s): priorityClass[%d] (%s): podPriorityClass is set, but value %d exceeds %d",
+				clusterIndex, clusterName, i, wpc.Name, wpc.Value, maxPodPriority)
 		}
+		podPriorityClassNames[wpc.Name] = true
+	}
+
+	if err := validateLocalQueueGenerators(k, clusterQueueNames); err != nil {
+		return fmt.Errorf("cluster[%d] (%s): %w", clusterIndex, clusterName, err)
 	}
 
 	// Validate LocalQueues
@@ -206,14 +425,172 @@ func validateKueueConfig(k *KueueConfig, clusterIndex int, clusterName string) e
 			return fmt.Errorf("cluster[%d] (%s): localQueue[%d] (%s): unknown clusterQueue '%s'",
 				clusterIndex, clusterName, i, lq.Name, lq.ClusterQueue)
 		}
+
+		switch lq.StopPolicy {
+		case "", StopPolicyNone, StopPolicyHold, StopPolicyHoldAndDrain:
+		default:
+			return fmt.Errorf("cluster[%d] (%s): localQueue[%d] (%s): invalid stopPolicy '%s' (must be %s, %s or %s)",
+				clusterIndex, clusterName, i, lq.Name, lq.StopPolicy, StopPolicyNone, StopPolicyHold, StopPolicyHoldAndDrain)
+		}
+		if err := validateFairSharing(lq.FairSharing); err != nil {
+			return fmt.Errorf("cluster[%d] (%s): localQueue[%d] (%s): %w", clusterIndex, clusterName, i, lq.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// validateNamespaceFixtures checks the resource names and quantities of a namespace's
+// ResourceQuota and LimitRange
+func validateNamespaceFixtures(ns Namespace) error {
+	if err := validateResourceList(ns.ResourceQuota); err != nil {
+		return fmt.Errorf("resourceQuota: %w", err)
+	}
+	if lr := ns.LimitRange; lr != nil {
+		for _, field := range []struct {
+			name string
+			list map[string]string
+		}{{"default", lr.Default}, {"defaultRequest", lr.DefaultRequest}, {"min", lr.Min}, {"max", lr.Max}} {
+			if err := validateResourceList(field.list); err != nil {
+				return fmt.Errorf("limitRange: %s: %w", field.name, err)
+			}
+		}
+		for _, name := range sortedKeys(lr.Min) {
+			upper, ok := lr.Max[name]
+			if !ok {
+				continue
+			}
+			if lower := resource.MustParse(lr.Min[name]); lower.Cmp(resource.MustParse(upper)) > 0 {
+				return fmt.Errorf("limitRange: min %s %s exceeds max %s", name, lr.Min[name], upper)
+			}
+		}
+	}
+	return nil
+}
+
+// validateResourceList checks that a resource list has qualified resource names and
+// non-negative quantities
+func validateResourceList(list map[string]string) error {
+	for _, name := range sortedKeys(list) {
+		if errs := validation.IsQualifiedName(name); len(errs) > 0 {
+			return fmt.Errorf("invalid resource name '%s': %s", name, strings.Join(errs, "; "))
+		}
+		if _, err := parseNonNegativeQuantity(list[name], name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxPodPriority is the highest value of a user-defined PriorityClass; higher values are
+// reserved for system classes
+const maxPodPriority = 1000000000
+
+// validatePodPriorityClasses validates Kubernetes PriorityClass definitions
+func validatePodPriorityClasses(classes []PodPriorityClass) error {
+	names := make(map[string]bool, len(classes))
+	globalDefault := ""
+	for i, pc := range classes {
+		if errs := validation.IsDNS1123Subdomain(pc.Name); len(errs) > 0 {
+			return fmt.Errorf("podPriorityClass[%d]: invalid name '%s': %s", i, pc.Name, strings.Join(errs, "; "))
+		}
+		if strings.HasPrefix(pc.Name, "system-") {
+			return fmt.Errorf("podPriorityClass[%d] (%s): the system- prefix is reserved", i, pc.Name)
+		}
+		if names[pc.Name] {
+			return fmt.Errorf("podPriorityClass[%d]: duplicate name '%s'", i, pc.Name)
+		}
+		names[pc.Name] = true
+
+		if pc.Value > maxPodPriority {
+			return fmt.Errorf("podPriorityClass[%d] (%s): value %d exceeds %d", i, pc.Name, pc.Value, maxPodPriority)
+		}
+		switch pc.PreemptionPolicy {
+		case "", PreemptLowerPriority, PreemptNever:
+		default:
+			return fmt.Errorf("podPriorityClass[%d] (%s): invalid preemptionPolicy '%s' (must be %s or %s)",
+				i, pc.Name, pc.PreemptionPolicy, PreemptLowerPriority, PreemptNever)
+		}
+		if pc.GlobalDefault {
+			if globalDefault != "" {
+				return fmt.Errorf("podPriorityClass[%d] (%s): only one class can be globalDefault, '%s' already is", i, pc.Name, globalDefault)
+			}
+			globalDefault = pc.Name
+		}
+	}
+	return nil
+}
+
+// validateAdmissionChecks validates AdmissionCheck and ProvisioningRequestConfig
+// definitions. ClusterQueues may also reference AdmissionChecks created elsewhere (e.g. for
+// MultiKueue WorkerSets), so references from ClusterQueues are not checked here.
+func validateAdmissionChecks(k *KueueConfig, clusterIndex int, clusterName string) error {
+	prcNames := make(map[string]bool)
+	for i, prc := range k.ProvisioningRequestConfigs {
+		if prc.Name == "" {
+			return fmt.Errorf("cluster[%d] (%s): provisioningRequestConfig[%d]: name is required", clusterIndex, clusterName, i)
+		}
+		if prcNames[prc.Name] {
+			return fmt.Errorf("cluster[%d] (%s): provisioningRequestConfig[%d]: duplicate name '%s'", clusterIndex, clusterName, i, prc.Name)
+		}
+		prcNames[prc.Name] = true
+
+		if prc.ProvisioningClassName == "" {
+			return fmt.Errorf("cluster[%d] (%s): provisioningRequestConfig[%d] (%s): provisioningClassName is required",
+				clusterIndex, clusterName, i, prc.Name)
+		}
+		if r := prc.RetryStrategy; r != nil {
+			for field, v := range map[string]*int32{
+				"backoffLimitCount":  r.BackoffLimitCount,
+				"backoffBaseSeconds": r.BackoffBaseSeconds,
+				"backoffMaxSeconds":  r.BackoffMaxSeconds,
+			} {
+				if v != nil && *v < 0 {
+					return fmt.Errorf("cluster[%d] (%s): provisioningRequestConfig[%d] (%s): retryStrategy.%s must be >= 0",
+						clusterIndex, clusterName, i, prc.Name, field)
+				}
+			}
+		}
 	}
 
+	acNames := make(map[string]bool)
+	for i, ac := range k.AdmissionChecks {
+		if ac.Name == "" {
+			return fmt.Errorf("cluster[%d] (%s): admissionCheck[%d]: name is required", clusterIndex, clusterName, i)
+		}
+		if acNames[ac.Name] {
+			return fmt.Errorf("cluster[%d] (%s): admissionCheck[%d]: duplicate name '%s'", clusterIndex, clusterName, i, ac.Name)
+		}
+		acNames[ac.Name] = true
+
+		if ac.ControllerName == "" {
+			return fmt.Errorf("cluster[%d] (%s): admissionCheck[%d] (%s): controllerName is required",
+				clusterIndex, clusterName, i, ac.Name)
+		}
+		p := ac.Parameters
+		if p == nil {
+			continue
+		}
+		if p.Kind == "" || p.Name == "" {
+			return fmt.Errorf("cluster[%d] (%s): admissionCheck[%d] (%s): parameters: kind and name are required",
+				clusterIndex, clusterName, i, ac.Name)
+		}
+		// ProvisioningRequestConfigs are the only Kueue parameter objects declared in config
+		if p.Kind == "ProvisioningRequestConfig" && (p.APIGroup == "" || p.APIGroup == kueueAPIGroup) && !prcNames[p.Name] {
+			return fmt.Errorf("cluster[%d] (%s): admissionCheck[%d] (%s): unknown provisioningRequestConfig '%s'",
+				clusterIndex, clusterName, i, ac.Name, p.Name)
+		}
+	}
 	return nil
 }
 
-func validateWorkerSets(workerSets []WorkerSet, clusterNames map[string]bool) error {
+func validateWorkerSets(workerSets []WorkerSet, clusterNames map[string]bool, defaultManagement string) error {
+	// dispatchers maps each management cluster to the dispatcher its WorkerSets set
+	dispatchers := make(map[string]string)
+	secretNames := make(map[string]string)
 	wsNames := make(map[string]bool)
-	workerNames := make(map[string]bool)
+	// workerSetOf maps each worker to the first WorkerSet it appears in
+	workerSetOf := make(map[string]int)
 
 	for i, ws := range workerSets {
 		if ws.Name == "" {
@@ -234,8 +611,22 @@ func validateWorkerSets(workerSets []WorkerSet, clusterNames map[string]bool) er
 			return fmt.Errorf("workerSet[%d] (%s): at least one worker is required", i, ws.Name)
 		}
 
-		// Build flavor name to nodePoolRef map
-		flavorPools := make(map[string]string, len(ws.ResourceFlavors))
+		if ws.Kwok != nil {
+			if err := validateClusterKwok(ws.Kwok); err != nil {
+				return fmt.Errorf("workerSet[%d] (%s): kwok: %w", i, ws.Name, err)
+			}
+		}
+
+		if err := validateFeatureGates(ws.KueueFeatureGates); err != nil {
+			return fmt.Errorf("workerSet[%d] (%s): kueueFeatureGates: %w", i, ws.Name, err)
+		}
+		if ws.Credentials != "" && ws.Credentials != CredentialsAdmin && ws.Credentials != CredentialsServiceAccount {
+			return fmt.Errorf("workerSet[%d] (%s): invalid credentials '%s' (must be %s or %s)",
+				i, ws.Name, ws.Credentials, CredentialsAdmin, CredentialsServiceAccount)
+		}
+
+		// Build flavor name to flavor map
+		flavors := make(map[string]WorkerSetFlavor, len(ws.ResourceFlavors))
 		for j, f := range ws.ResourceFlavors {
 			if f.Name == "" {
 				return fmt.Errorf("workerSet[%d] (%s): resourceFlavor[%d]: name is required", i, ws.Name, j)
@@ -243,7 +634,12 @@ func validateWorkerSets(workerSets []WorkerSet, clusterNames map[string]bool) er
 			if f.NodePoolRef == "" {
 				return fmt.Errorf("workerSet[%d] (%s): resourceFlavor[%d] (%s): nodePoolRef is required", i, ws.Name, j, f.Name)
 			}
-			flavorPools[f.Name] = f.NodePoolRef
+			for _, w := range f.Workers {
+				if !ws.hasWorker(w) {
+					return fmt.Errorf("workerSet[%d] (%s): resourceFlavor[%d] (%s): unknown worker '%s'", i, ws.Name, j, f.Name, w)
+				}
+			}
+			flavors[f.Name] = f
 		}
 
 		// Validate ClusterQueue structure and flavor references
@@ -258,13 +654,16 @@ func validateWorkerSets(workerSets []WorkerSet, clusterNames map[string]bool) er
 				return fmt.Errorf("workerSet[%d] (%s): clusterQueue[%d] (%s): at least one resourceGroup is required",
 					i, ws.Name, j, cq.Name)
 			}
+			if err := validateFairSharing(cq.FairSharing); err != nil {
+				return fmt.Errorf("workerSet[%d] (%s): clusterQueue[%d] (%s): %w", i, ws.Name, j, cq.Name, err)
+			}
 			for k, rg := range cq.ResourceGroups {
 				if len(rg.CoveredResources) == 0 {
 					return fmt.Errorf("workerSet[%d] (%s): clusterQueue[%d] (%s): resourceGroup[%d]: at least one coveredResource is required",
 						i, ws.Name, j, cq.Name, k)
 				}
 				for l, fr := range rg.Flavors {
-					if _, ok := flavorPools[fr.Name]; !ok {
+					if _, ok := flavors[fr.Name]; !ok {
 						return fmt.Errorf("workerSet[%d] (%s): clusterQueue[%d] (%s): resourceGroup[%d]: flavor[%d]: unknown resourceFlavor '%s'",
 							i, ws.Name, j, cq.Name, k, l, fr.Name)
 					}
@@ -291,23 +690,9 @@ func validateWorkerSets(workerSets []WorkerSet, clusterNames map[string]bool) er
 			}
 		}
 
-		// Build map of required resources per pool for cross-checking workers
-		poolRequiredResources := make(map[string]map[string]bool)
-		for _, cq := range ws.ClusterQueues {
-			for _, rg := range cq.ResourceGroups {
-				for _, fr := range rg.Flavors {
-					poolName := flavorPools[fr.Name]
-					if poolRequiredResources[poolName] == nil {
-						poolRequiredResources[poolName] = make(map[string]bool)
-					}
-					for _, cr := range rg.CoveredResources {
-						poolRequiredResources[poolName][cr] = true
-					}
-				}
-			}
-		}
-
 		// Validate each worker
+		simulated := 0
+		setWorkers := make(map[string]bool, len(ws.Workers))
 		for j, worker := range ws.Workers {
 			if worker.Name == "" {
 				return fmt.Errorf("workerSet[%d] (%s): worker[%d]: name is required", i, ws.Name, j)
@@ -316,11 +701,42 @@ func validateWorkerSets(workerSets []WorkerSet, clusterNames map[string]bool) er
 				return fmt.Errorf("workerSet[%d] (%s): worker[%d]: name '%s' conflicts with an existing cluster",
 					i, ws.Name, j, worker.Name)
 			}
-			if workerNames[worker.Name] {
+			if setWorkers[worker.Name] {
 				return fmt.Errorf("workerSet[%d] (%s): worker[%d]: duplicate worker name '%s'",
 					i, ws.Name, j, worker.Name)
 			}
-			workerNames[worker.Name] = true
+			setWorkers[worker.Name] = true
+
+			// Workers may be shared by WorkerSets that reach them the same way
+			if k, ok := workerSetOf[worker.Name]; ok {
+				if err := validateSharedWorker(workerSets[k], ws, defaultManagement); err != nil {
+					return fmt.Errorf("workerSet[%d] (%s): worker[%d] (%s): shared with workerSet '%s': %w",
+						i, ws.Name, j, worker.Name, workerSets[k].Name, err)
+				}
+			} else {
+				workerSetOf[worker.Name] = i
+			}
+
+			if w := worker.CapacityWeight; w != nil && (!(*w > 0) || math.IsInf(*w, 1)) {
+				return fmt.Errorf("workerSet[%d] (%s): worker[%d] (%s): capacityWeight must be > 0, got %g",
+					i, ws.Name, j, worker.Name, *w)
+			}
+
+			// External workers exist already; quotas are derived from simulated workers only
+			if worker.Kubeconfig != "" {
+				if len(worker.NodePools) > 0 {
+					return fmt.Errorf("workerSet[%d] (%s): worker[%d] (%s): nodePools cannot be set with kubeconfig",
+						i, ws.Name, j, worker.Name)
+				}
+				if worker.CapacityWeight != nil {
+					return fmt.Errorf("workerSet[%d] (%s): worker[%d] (%s): capacityWeight cannot be set with kubeconfig",
+						i, ws.Name, j, worker.Name)
+				}
+				continue
+			}
+			if !worker.Standby {
+				simulated++
+			}
 
 			if len(worker.NodePools) == 0 {
 				return fmt.Errorf("workerSet[%d] (%s): worker[%d] (%s): at least one nodePool is required",
@@ -340,27 +756,199 @@ func validateWorkerSets(workerSets []WorkerSet, clusterNames map[string]bool) er
 				pools[pool.Name] = pool
 			}
 
-			// Verify all nodePoolRefs exist in this worker
+			if err := validateNodeNames(worker.NodePools); err != nil {
+				return fmt.Errorf("workerSet[%d] (%s): worker[%d] (%s): %w", i, ws.Name, j, worker.Name, err)
+			}
+
+			// Verify the nodePoolRefs of the worker's flavors exist in this worker
 			for _, f := range ws.ResourceFlavors {
+				if !f.AppliesTo(worker.Name) {
+					continue
+				}
 				if _, ok := pools[f.NodePoolRef]; !ok {
 					return fmt.Errorf("workerSet[%d] (%s): worker[%d] (%s): nodePoolRef '%s' (from resourceFlavor '%s') not found",
 						i, ws.Name, j, worker.Name, f.NodePoolRef, f.Name)
 				}
 			}
 
-			// Verify all covered resources exist in the referenced pools
-			for poolName, requiredResources := range poolRequiredResources {
-				pool := pools[poolName]
-				for cr := range requiredResources {
-					if _, ok := pool.Resources[cr]; !ok {
-						return fmt.Errorf("workerSet[%d] (%s): worker[%d] (%s): nodePool '%s': covered resource '%s' not found in pool resources",
-							i, ws.Name, j, worker.Name, poolName, cr)
+			// Verify every resourceGroup has a flavor on this worker, and its covered
+			// resources exist in the referenced pools
+			for _, cq := range ws.ClusterQueues {
+				for k, rg := range cq.ResourceGroups {
+					provided := false
+					for _, fr := range rg.Flavors {
+						f := flavors[fr.Name]
+						if !f.AppliesTo(worker.Name) {
+							continue
+						}
+						provided = true
+						pool := pools[f.NodePoolRef]
+						for _, cr := range rg.CoveredResources {
+							if _, ok := pool.Capacity()[cr]; !ok {
+								return fmt.Errorf("workerSet[%d] (%s): worker[%d] (%s): nodePool '%s': covered resource '%s' not found in pool resources",
+									i, ws.Name, j, worker.Name, f.NodePoolRef, cr)
+							}
+						}
+					}
+					if !provided {
+						return fmt.Errorf("workerSet[%d] (%s): worker[%d] (%s): clusterQueue '%s': resourceGroup[%d]: no flavor applies to this worker",
+							i, ws.Name, j, worker.Name, cq.Name, k)
 					}
 				}
 			}
 		}
+		if simulated == 0 {
+			return fmt.Errorf("workerSet[%d] (%s): at least one non-standby worker with nodePools is required to derive management quotas", i, ws.Name)
+		}
+
+		if err := validateDispatch(ws); err != nil {
+			return fmt.Errorf("workerSet[%d] (%s): dispatch: %w", i, ws.Name, err)
+		}
+		if d := ws.Dispatch; d != nil && d.Dispatcher != "" {
+			management := ws.managementCluster(defaultManagement)
+			if dispatcher := dispatchers[management]; dispatcher != "" && d.Dispatcher != dispatcher {
+				return fmt.Errorf("workerSet[%d] (%s): dispatch: dispatcher '%s' conflicts with '%s' set by another workerSet (Kueue's dispatcher is cluster-wide)",
+					i, ws.Name, d.Dispatcher, dispatcher)
+			}
+			dispatchers[management] = d.Dispatcher
+		}
+
+		if err := validateNetwork(ws); err != nil {
+			return fmt.Errorf("workerSet[%d] (%s): network: %w", i, ws.Name, err)
+		}
+
+		if ws.KueueNamespace != "" {
+			if errs := validation.IsDNS1123Label(ws.KueueNamespace); len(errs) > 0 {
+				return fmt.Errorf("workerSet[%d] (%s): invalid kueueNamespace '%s': %s",
+					i, ws.Name, ws.KueueNamespace, strings.Join(errs, "; "))
+			}
+		}
+		for _, worker := range ws.Workers {
+			name, err := ws.KubeconfigSecretName(worker.Name)
+			if err != nil {
+				return fmt.Errorf("workerSet[%d] (%s): %w", i, ws.Name, err)
+			}
+			if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+				return fmt.Errorf("workerSet[%d] (%s): secretNameTemplate: invalid Secret name '%s' for worker '%s': %s",
+					i, ws.Name, name, worker.Name, strings.Join(errs, "; "))
+			}
+			// A shared worker's Secret may be reused, since it holds the same kubeconfig
+			key := ws.managementCluster(defaultManagement) + "/" + ws.Namespace() + "/" + name
+			if owner, ok := secretNames[key]; ok && owner != worker.Name {
+				return fmt.Errorf("workerSet[%d] (%s): secretNameTemplate: duplicate Secret name '%s/%s' for worker '%s'",
+					i, ws.Name, ws.Namespace(), name, worker.Name)
+			}
+			secretNames[key] = worker.Name
+		}
+	}
+
+	// Shared workers merge the Kueue objects of their WorkerSets, which must not conflict
+	if _, err := ExpandWorkerSets(workerSets); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateSharedWorker checks that two WorkerSets sharing a worker agree on how the
+// management cluster reaches it. An empty managementRef stands for defaultManagement.
+func validateSharedWorker(first, ws WorkerSet, defaultManagement string) error {
+	if first.managementCluster(defaultManagement) != ws.managementCluster(defaultManagement) {
+		return fmt.Errorf("managementRef must match (a worker has a single management cluster)")
+	}
+	if (first.Credentials == CredentialsServiceAccount) != (ws.Credentials == CredentialsServiceAccount) {
+		return fmt.Errorf("credentials must match")
+	}
+	if ws.Credentials == CredentialsServiceAccount && first.Namespace() != ws.Namespace() {
+		return fmt.Errorf("kueueNamespace must match with serviceAccount credentials")
+	}
+	if !reflect.DeepEqual(first.Network, ws.Network) {
+		return fmt.Errorf("network must match")
+	}
+	return nil
+}
+
+// soleManagementCluster returns the name of the topology's management cluster, which
+// WorkerSets without a managementRef default to, or "" unless there is exactly one
+func soleManagementCluster(clusters []ClusterConfig) string {
+	var management string
+	for _, c := range clusters {
+		if c.Role != RoleManagement {
+			continue
+		}
+		if management != "" {
+			return ""
+		}
+		management = c.Name
+	}
+	return management
+}
+
+// validateDispatch validates a WorkerSet's dispatcher and worker order
+func validateDispatch(ws WorkerSet) error {
+	d := ws.Dispatch
+	if d == nil {
+		return nil
+	}
+	if d.Dispatcher != "" && d.Dispatcher != DispatcherAllAtOnce && d.Dispatcher != DispatcherIncremental {
+		return fmt.Errorf("invalid dispatcher '%s' (must be %s or %s)", d.Dispatcher, DispatcherAllAtOnce, DispatcherIncremental)
+	}
+	if len(d.Order) > 0 && d.Dispatcher != DispatcherIncremental {
+		return fmt.Errorf("order requires the %s dispatcher", DispatcherIncremental)
+	}
+	seen := make(map[string]bool, len(d.Order))
+	for _, name := range d.Order {
+		if !slices.ContainsFunc(ws.Workers, func(w Worker) bool { return w.Name == name }) {
+			return fmt.Errorf("order: unknown worker '%s'", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("order: duplicate worker '%s'", name)
+		}
+		seen[name] = true
 	}
+	return nil
+}
 
+// tcRatePattern matches tc rate values such as "10mbit" or "1.5gbps"
+var tcRatePattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?([kmgt]?(bit|bps))$`)
+
+// validateNetwork validates a WorkerSet's network shaping
+func validateNetwork(ws WorkerSet) error {
+	n := ws.Network
+	if n == nil {
+		return nil
+	}
+	if n.Latency == "" && n.Loss == 0 && n.Rate == "" {
+		return fmt.Errorf("at least one of latency, loss or rate is required")
+	}
+	if n.Latency != "" {
+		if d, err := time.ParseDuration(n.Latency); err != nil {
+			return fmt.Errorf("invalid latency %q: %w", n.Latency, err)
+		} else if d < 0 {
+			return fmt.Errorf("latency must be >= 0, got %s", n.Latency)
+		}
+	}
+	if n.Jitter != "" {
+		if n.Latency == "" {
+			return fmt.Errorf("jitter requires latency")
+		}
+		if d, err := time.ParseDuration(n.Jitter); err != nil {
+			return fmt.Errorf("invalid jitter %q: %w", n.Jitter, err)
+		} else if d < 0 {
+			return fmt.Errorf("jitter must be >= 0, got %s", n.Jitter)
+		}
+	}
+	if n.Loss < 0 || n.Loss > 100 {
+		return fmt.Errorf("loss must be in [0, 100], got %g", n.Loss)
+	}
+	if n.Rate != "" && !tcRatePattern.MatchString(n.Rate) {
+		return fmt.Errorf("invalid rate %q (e.g. 10mbit, 1gbit, 500kbps)", n.Rate)
+	}
+	for _, worker := range ws.Workers {
+		if worker.Kubeconfig != "" {
+			return fmt.Errorf("worker '%s' is external; only simulated workers can be shaped", worker.Name)
+		}
+	}
 	return nil
 }
 
@@ -379,6 +967,10 @@ func validateCohorts(cohorts []Cohort, clusterIndex int, clusterName string) (ma
 		}
 
 		cohortNames[cohort.Name] = true
+
+		if err := validateFairSharing(cohort.FairSharing); err != nil {
+			return nil, fmt.Errorf("cluster[%d] (%s): cohort[%d] (%s): %w", clusterIndex, clusterName, i, cohort.Name, err)
+		}
 	}
 
 	// Validate that parent cohorts exist (order-independent: map is fully populated above)
@@ -392,69 +984,892 @@ func validateCohorts(cohorts []Cohort, clusterIndex int, clusterName string) (ma
 	return cohortNames, nil
 }
 
-// validateMultiKueueTopology validates MultiKueue topology requirements.
-// When WorkerSets exist, exactly one cluster must have role: management.
-func validateMultiKueueTopology(clusters []ClusterConfig) error {
-	managementCount := 0
-	for _, cluster := range clusters {
-		if cluster.Role == RoleManagement {
-			managementCount++
-		}
-	}
+// maxResourceGroups is the number of resource groups Kueue allows in a ClusterQueue or Cohort
+const maxResourceGroups = 16
 
-	if managementCount != 1 {
-		return fmt.Errorf("workerSets require exactly one cluster with role 'management', found %d", managementCount)
+// validateResourceGroups applies Kueue's quota rules to the resource groups of a ClusterQueue
+// or Cohort: each resource and flavor belongs to one group, every flavor lists the group's
+// covered resources in order, and quotas are non-negative. Borrowing and lending limits
+// require a parent, named by parentField in errors, and lendingLimit may not exceed nominalQuota.
+func validateResourceGroups(groups []ResourceGroup, flavorNames map[string]bool, hasParent bool, parentField string) error {
+	if len(groups) > maxResourceGroups {
+		return fmt.Errorf("at most %d resourceGroups are allowed, got %d", maxResourceGroups, len(groups))
 	}
-	return nil
-}
-
-// validateExtensions validates extensions configuration for a cluster.
-func validateExtensions(extensions []Extension, clusterIndex int, clusterName string) error {
-	names := make(map[string]bool, len(extensions))
 
-	for i, ext := range extensions {
-		if ext.Name == "" {
-			return fmt.Errorf("cluster[%d] (%s): extension[%d]: name is required",
-				clusterIndex, clusterName, i)
+	seenResources := make(map[string]bool)
+	seenFlavors := make(map[string]bool)
+	for i, rg := range groups {
+		if len(rg.CoveredResources) == 0 {
+			return fmt.Errorf("resourceGroup[%d]: at least one coveredResource is required", i)
 		}
-
-		if names[ext.Name] {
-			return fmt.Errorf("cluster[%d] (%s): extension[%d]: duplicate extension name '%s'",
-				clusterIndex, clusterName, i, ext.Name)
+		for _, name := range rg.CoveredResources {
+			if seenResources[name] {
+				return fmt.Errorf("resourceGroup[%d]: resource '%s' is covered by more than one resourceGroup", i, name)
+			}
+			seenResources[name] = true
+		}
+		if len(rg.Flavors) == 0 {
+			return fmt.Errorf("resourceGroup[%d]: at least one flavor is required", i)
 		}
-		names[ext.Name] = true
+
+		for j, fq := range rg.Flavors {
+			if !flavorNames[fq.Name] {
+				return fmt.Errorf("resourceGroup[%d]: flavor[%d]: unknown resourceFlavor '%s'", i, j, fq.Name)
+			}
+			if seenFlavors[fq.Name] {
+				return fmt.Errorf("resourceGroup[%d]: flavor[%d]: flavor '%s' appears more than once", i, j, fq.Name)
+			}
+			seenFlavors[fq.Name] = true
+
+			if len(fq.Resources) != len(rg.CoveredResources) {
+				return fmt.Errorf("resourceGroup[%d]: flavor[%d] (%s): must list the %d coveredResources, got %d resources",
+					i, j, fq.Name, len(rg.CoveredResources), len(fq.Resources))
+			}
+			for l, res := range fq.Resources {
+				if err := validateResourceQuota(res, rg.CoveredResources[l], hasParent, parentField); err != nil {
+					return fmt.Errorf("resourceGroup[%d]: flavor[%d] (%s): resource[%d]: %w", i, j, fq.Name, l, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateResourceQuota validates the quota of one resource of a flavor
+func validateResourceQuota(res Resource, coveredResource string, hasParent bool, parentField string) error {
+	if res.Name != coveredResource {
+		return fmt.Errorf("name '%s' must match coveredResource '%s'", res.Name, coveredResource)
+	}
+	nominal, err := parseNonNegativeQuantity(res.NominalQuota, "nominalQuota")
+	if err != nil {
+		return err
+	}
+	if res.BorrowingLimit != "" {
+		if !hasParent {
+			return fmt.Errorf("borrowingLimit requires %s to be set", parentField)
+		}
+		if _, err := parseNonNegativeQuantity(res.BorrowingLimit, "borrowingLimit"); err != nil {
+			return err
+		}
+	}
+	if res.LendingLimit != "" {
+		if !hasParent {
+			return fmt.Errorf("lendingLimit requires %s to be set", parentField)
+		}
+		lending, err := parseNonNegativeQuantity(res.LendingLimit, "lendingLimit")
+		if err != nil {
+			return err
+		}
+		if lending.Cmp(nominal) > 0 {
+			return fmt.Errorf("lendingLimit %s must not exceed nominalQuota %s", res.LendingLimit, res.NominalQuota)
+		}
+	}
+	return nil
+}
+
+// parseNonNegativeQuantity parses a quota field as a non-negative quantity
+func parseNonNegativeQuantity(value, field string) (resource.Quantity, error) {
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("invalid %s: %w", field, err)
+	}
+	if q.Sign() < 0 {
+		return resource.Quantity{}, fmt.Errorf("%s must not be negative, got %s", field, value)
+	}
+	return q, nil
+}
+
+// validateFairSharing checks that a fair sharing weight, if set, is a non-negative quantity
+func validateFairSharing(fs *FairSharing) error {
+	if fs == nil || fs.Weight == "" {
+		return nil
+	}
+	weight, err := resource.ParseQuantity(fs.Weight)
+	if err != nil {
+		return fmt.Errorf("fairSharing: invalid weight '%s': %w", fs.Weight, err)
+	}
+	if weight.Sign() < 0 {
+		return fmt.Errorf("fairSharing: weight must be >= 0")
+	}
+	return nil
+}
+
+// maxTopologyLevels is the maximum number of levels Kueue allows in a Topology
+const maxTopologyLevels = 16
+
+// validateKueueTopologies validates TAS Topologies and returns the set of topology names
+func validateKueueTopologies(topologies []KueueTopology, clusterIndex int, clusterName string) (map[string]bool, error) {
+	topologyNames := make(map[string]bool, len(topologies))
+	for i, t := range topologies {
+		if t.Name == "" {
+			return nil, fmt.Errorf("cluster[%d] (%s): topology[%d]: name is required",
+				clusterIndex, clusterName, i)
+		}
+
+		if topologyNames[t.Name] {
+			return nil, fmt.Errorf("cluster[%d] (%s): topology[%d]: duplicate topology name '%s'",
+				clusterIndex, clusterName, i, t.Name)
+		}
+		topologyNames[t.Name] = true
+
+		if len(t.Levels) == 0 || len(t.Levels) > maxTopologyLevels {
+			return nil, fmt.Errorf("cluster[%d] (%s): topology[%d] (%s): must have between 1 and %d levels",
+				clusterIndex, clusterName, i, t.Name, maxTopologyLevels)
+		}
+
+		seenLevels := make(map[string]bool, len(t.Levels))
+		for j, level := range t.Levels {
+			if errs := validation.IsQualifiedName(level); len(errs) > 0 {
+				return nil, fmt.Errorf("cluster[%d] (%s): topology[%d] (%s): level[%d]: invalid node label '%s'",
+					clusterIndex, clusterName, i, t.Name, j, level)
+			}
+			if seenLevels[level] {
+				return nil, fmt.Errorf("cluster[%d] (%s): topology[%d] (%s): level[%d]: duplicate level '%s'",
+					clusterIndex, clusterName, i, t.Name, j, level)
+			}
+			seenLevels[level] = true
+		}
+	}
+
+	return topologyNames, nil
+}
+
+// validateMultiKueueTopology validates MultiKueue topology requirements.
+// When WorkerSets exist, at least one cluster must have role: management. With several,
+// every WorkerSet must name its management cluster in managementRef.
+func validateMultiKueueTopology(clusters []ClusterConfig, workerSets []WorkerSet) error {
+	managers := make(map[string]bool)
+	for _, cluster := range clusters {
+		if cluster.Role == RoleManagement {
+			managers[cluster.Name] = true
+		}
+	}
+	if len(managers) == 0 {
+		return fmt.Errorf("workerSets require a cluster with role 'management'")
+	}
+
+	for i, ws := range workerSets {
+		switch {
+		case ws.ManagementRef == "" && len(managers) > 1:
+			return fmt.Errorf("workerSet[%d] (%s): managementRef is required with %d management clusters",
+				i, ws.Name, len(managers))
+		case ws.ManagementRef != "" && !managers[ws.ManagementRef]:
+			return fmt.Errorf("workerSet[%d] (%s): managementRef '%s' is not a cluster with role 'management'",
+				i, ws.Name, ws.ManagementRef)
+		}
+	}
+	return nil
+}
+
+// validateImages validates the topology-level list of images to preload.
+func validateImages(images []string) error {
+	seen := make(map[string]bool, len(images))
+	for i, image := range images {
+		if strings.TrimSpace(image) == "" {
+			return fmt.Errorf("images[%d]: image reference is required", i)
+		}
+		if seen[image] {
+			return fmt.Errorf("images[%d]: duplicate image '%s'", i, image)
+		}
+		seen[image] = true
+	}
+	return nil
+}
+
+// validateRegistry validates registry mirror and local registry settings.
+func validateRegistry(r *RegistrySettings) error {
+	registries := make(map[string]bool, len(r.Mirrors))
+	for i, m := range r.Mirrors {
+		if m.Registry == "" {
+			return fmt.Errorf("registry.mirrors[%d]: registry is required", i)
+		}
+		if strings.Contains(m.Registry, "://") {
+			return fmt.Errorf("registry.mirrors[%d] (%s): registry must be a host name without scheme", i, m.Registry)
+		}
+		if registries[m.Registry] {
+			return fmt.Errorf("registry.mirrors[%d]: duplicate registry '%s'", i, m.Registry)
+		}
+		registries[m.Registry] = true
+
+		if len(m.Endpoints) == 0 {
+			return fmt.Errorf("registry.mirrors[%d] (%s): at least one endpoint is required", i, m.Registry)
+		}
+		for j, ep := range m.Endpoints {
+			if !isHTTPURL(ep) {
+				return fmt.Errorf("registry.mirrors[%d] (%s): endpoints[%d]: must start with http:// or https://", i, m.Registry, j)
+			}
+		}
+	}
+
+	if lr := r.LocalRegistry; lr != nil {
+		if lr.Port < 0 || lr.Port > 65535 {
+			return fmt.Errorf("registry.localRegistry: invalid port %d", lr.Port)
+		}
+		if lr.RemoteURL != "" && !isHTTPURL(lr.RemoteURL) {
+			return fmt.Errorf("registry.localRegistry: remoteURL must start with http:// or https://")
+		}
+	}
+
+	return nil
+}
+
+// validateManifestPath checks that a manifest path names an existing file or directory, or is
+// a glob pattern matching at least one file
+func validateManifestPath(path string) error {
+	if strings.ContainsAny(path, "*?[") {
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return fmt.Errorf("invalid glob pattern '%s': %w", path, err)
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("no files match '%s'", path)
+		}
+		return nil
+	}
+	_, err := os.Stat(path)
+	return err
+}
+
+// isHTTPURL reports whether s has an http:// or https:// scheme prefix.
+func isHTTPURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// validateExtensions validates extensions configuration for a cluster.
+func validateExtensions(extensions []Extension) error {
+	names := make(map[string]bool, len(extensions))
+
+	for i, ext := range extensions {
+		if ext.Name == "" {
+			return fmt.Errorf("extension[%d]: name is required", i)
+		}
+
+		if names[ext.Name] {
+			return fmt.Errorf("extension[%d]: duplicate extension name '%s'", i, ext.Name)
+		}
+		names[ext.Name] = true
 
 		hasHelm := ext.Helm != nil
 		hasManifest := ext.Manifest != nil
+		hasKustomize := ext.Kustomize != nil
+
+		if ext.Preset != "" {
+			if hasManifest {
+				return fmt.Errorf("extension[%d] (%s): cannot specify both 'preset' and 'manifest'",
+					i, ext.Name)
+			}
+			if hasKustomize {
+				return fmt.Errorf("extension[%d] (%s): cannot specify both 'preset' and 'kustomize'",
+					i, ext.Name)
+			}
+			resolved, err := ext.ResolvePreset()
+			if err != nil {
+				return fmt.Errorf("extension[%d] (%s): %w", i, ext.Name, err)
+			}
+			ext, hasHelm = resolved, true
+		}
 
-		if !hasHelm && !hasManifest {
-			return fmt.Errorf("cluster[%d] (%s): extension[%d] (%s): exactly one of 'helm' or 'manifest' is required",
-				clusterIndex, clusterName, i, ext.Name)
+		if !hasHelm && !hasManifest && !hasKustomize {
+			return fmt.Errorf("extension[%d] (%s): exactly one of 'helm', 'manifest', 'kustomize' or 'preset' is required",
+				i, ext.Name)
 		}
 
 		if hasHelm && hasManifest {
-			return fmt.Errorf("cluster[%d] (%s): extension[%d] (%s): cannot specify both 'helm' and 'manifest'",
-				clusterIndex, clusterName, i, ext.Name)
+			return fmt.Errorf("extension[%d] (%s): cannot specify both 'helm' and 'manifest'",
+				i, ext.Name)
+		}
+		if hasKustomize && (hasHelm || hasManifest) {
+			return fmt.Errorf("extension[%d] (%s): 'kustomize' cannot be combined with 'helm' or 'manifest'",
+				i, ext.Name)
+		}
+
+		if hasKustomize {
+			k := ext.Kustomize
+			if (k.URL == "") == (k.Path == "") {
+				return fmt.Errorf("extension[%d] (%s): exactly one of kustomize.url or kustomize.path is required",
+					i, ext.Name)
+			}
+			if k.Path != "" {
+				if info, err := os.Stat(k.Path); err != nil {
+					return fmt.Errorf("extension[%d] (%s): kustomize.path: %w",
+						i, ext.Name, err)
+				} else if !info.IsDir() {
+					return fmt.Errorf("extension[%d] (%s): kustomize.path must be a directory containing a kustomization",
+						i, ext.Name)
+				}
+			}
 		}
 
 		if hasHelm {
 			if ext.Helm.Chart == "" {
-				return fmt.Errorf("cluster[%d] (%s): extension[%d] (%s): helm.chart is required",
-					clusterIndex, clusterName, i, ext.Name)
+				return fmt.Errorf("extension[%d] (%s): helm.chart is required",
+					i, ext.Name)
+			}
+			if ext.Helm.ValuesFile != "" {
+				if _, err := os.Stat(ext.Helm.ValuesFile); err != nil {
+					return fmt.Errorf("extension[%d] (%s): helm.valuesFile: %w",
+						i, ext.Name, err)
+				}
+			}
+			if ext.Helm.Auth != nil {
+				if err := validateHelmAuth(ext.Helm.Auth); err != nil {
+					return fmt.Errorf("extension[%d] (%s): helm.auth: %w",
+						i, ext.Name, err)
+				}
 			}
 		}
 
 		if hasManifest {
-			if ext.Manifest.URL == "" {
-				return fmt.Errorf("cluster[%d] (%s): extension[%d] (%s): manifest.url is required",
-					clusterIndex, clusterName, i, ext.Name)
+			if err := validateManifest(ext.Manifest, "manifest"); err != nil {
+				return fmt.Errorf("extension[%d] (%s): %w",
+					i, ext.Name, err)
+			}
+		}
+
+		for j, action := range ext.PostInstall {
+			if err := validatePostInstallAction(action, fmt.Sprintf("postInstall[%d]", j)); err != nil {
+				return fmt.Errorf("extension[%d] (%s): %w",
+					i, ext.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateTopologyExtensions validates spec.extensions and their targets. Targets are only
+// allowed there, not on cluster or workerSet extensions.
+func validateTopologyExtensions(spec TopologySpec) error {
+	for i, c := range spec.Clusters {
+		for j, ext := range c.Extensions {
+			if ext.Targets != nil {
+				return fmt.Errorf("cluster[%d] (%s): extension[%d] (%s): targets are only allowed on spec.extensions", i, c.Name, j, ext.Name)
+			}
+		}
+	}
+	names := make(map[string]bool)
+	for _, c := range spec.Clusters {
+		names[c.Name] = true
+	}
+	for i, ws := range spec.WorkerSets {
+		for j, ext := range ws.Extensions {
+			if ext.Targets != nil {
+				return fmt.Errorf("workerSet[%d] (%s): extension[%d] (%s): targets are only allowed on spec.extensions", i, ws.Name, j, ext.Name)
+			}
+		}
+		for _, w := range ws.Workers {
+			names[w.Name] = true
+		}
+	}
+
+	if err := validateExtensions(spec.Extensions); err != nil {
+		return fmt.Errorf("spec: %w", err)
+	}
+	for i, ext := range spec.Extensions {
+		t := ext.Targets
+		if t == nil {
+			continue
+		}
+		if len(t.Roles) == 0 && len(t.Clusters) == 0 {
+			return fmt.Errorf("spec: extension[%d] (%s): targets must list at least one role or cluster", i, ext.Name)
+		}
+		for _, role := range t.Roles {
+			if role != RoleStandalone && role != RoleManagement && role != RoleWorker {
+				return fmt.Errorf("spec: extension[%d] (%s): targets: invalid role '%s' (must be standalone, management, or worker)", i, ext.Name, role)
+			}
+		}
+		for _, name := range t.Clusters {
+			if !names[name] {
+				return fmt.Errorf("spec: extension[%d] (%s): targets: unknown cluster '%s'", i, ext.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateClusterKwok validates per-cluster KWOK customizations
+func validateClusterKwok(k *ClusterKwokConfig) error {
+	if k.Scheduler != "" && k.Scheduler != SchedulerKube && k.Scheduler != SchedulerBypass {
+		return fmt.Errorf("invalid scheduler '%s' (must be %s or %s)", k.Scheduler, SchedulerKube, SchedulerBypass)
+	}
+	return validateKwokStages(k.Stages)
+}
+
+// validateKwokStages validates KWOK stage overrides: each must be given exactly one way
+// and contain only named Stage documents, with no stage name defined twice.
+func validateKwokStages(stages []KwokStage) error {
+	names := make(map[string]bool)
+	for i, stage := range stages {
+		if (stage.Inline == "") == (stage.File == "") {
+			return fmt.Errorf("stage[%d]: exactly one of 'inline' or 'file' is required", i)
+		}
+
+		docs, err := stage.Documents()
+		if err != nil {
+			return fmt.Errorf("stage[%d]: %w", i, err)
+		}
+		if len(docs) == 0 {
+			return fmt.Errorf("stage[%d]: no stage manifests found", i)
+		}
+
+		for _, doc := range docs {
+			name, err := KwokStageName(doc)
+			if err != nil {
+				return fmt.Errorf("stage[%d]: %w", i, err)
 			}
-			if !strings.HasPrefix(ext.Manifest.URL, "http://") && !strings.HasPrefix(ext.Manifest.URL, "https://") {
-				return fmt.Errorf("cluster[%d] (%s): extension[%d] (%s): manifest.url must start with http:// or https://",
-					clusterIndex, clusterName, i, ext.Name)
+			if names[name] {
+				return fmt.Errorf("stage[%d]: duplicate stage '%s'", i, name)
 			}
+			names[name] = true
 		}
 	}
+	return nil
+}
 
+// validateManifest validates a manifest source; field is the path of m in error messages
+func validateManifest(m *ManifestExtension, field string) error {
+	if (m.URL == "") == (m.Path == "") {
+		return fmt.Errorf("exactly one of %[1]s.url or %[1]s.path is required", field)
+	}
+	if m.URL != "" && !isHTTPURL(m.URL) {
+		return fmt.Errorf("%s.url must start with http:// or https://", field)
+	}
+	if m.SHA256 != "" {
+		if m.URL == "" {
+			return fmt.Errorf("%[1]s.sha256 requires %[1]s.url", field)
+		}
+		if !sha256HexPattern.MatchString(m.SHA256) {
+			return fmt.Errorf("%s.sha256 must be 64 lowercase hex characters", field)
+		}
+	}
+	if m.Auth != nil {
+		if err := validateManifestAuth(m); err != nil {
+			return fmt.Errorf("%s.auth: %w", field, err)
+		}
+	}
+	if m.Path != "" {
+		if err := validateManifestPath(m.Path); err != nil {
+			return fmt.Errorf("%s.path: %w", field, err)
+		}
+	}
+	return nil
+}
+
+// validatePostInstallAction checks a post-install action sets exactly one action;
+// field is the path of the action in error messages
+func validatePostInstallAction(a PostInstallAction, field string) error {
+	set := 0
+	for _, ok := range []bool{a.Namespace != "", a.WaitForCRD != "", a.Manifest != nil} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("%s: exactly one of 'namespace', 'waitForCRD' or 'manifest' is required", field)
+	}
+
+	if a.Namespace != "" {
+		if errs := validation.IsDNS1123Label(a.Namespace); len(errs) > 0 {
+			return fmt.Errorf("%s: invalid namespace '%s': %s", field, a.Namespace, strings.Join(errs, "; "))
+		}
+	}
+	if a.Manifest != nil {
+		if err := validateManifest(a.Manifest, field+".manifest"); err != nil {
+			return err
+		}
+	}
+	if a.Timeout != "" {
+		if a.WaitForCRD == "" {
+			return fmt.Errorf("%s: timeout requires waitForCRD", field)
+		}
+		if d, err := time.ParseDuration(a.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("%s: invalid timeout '%s' (must be a positive duration, e.g. \"2m\")", field, a.Timeout)
+		}
+	}
+	return nil
+}
+
+// validateManifestAuth checks a manifest's auth names either a bearer token or basic auth
+// credentials, for a URL download
+func validateManifestAuth(m *ManifestExtension) error {
+	a := m.Auth
+	if m.URL == "" {
+		return fmt.Errorf("requires manifest.url")
+	}
+	basic := a.UsernameEnv != "" || a.PasswordEnv != ""
+	switch {
+	case a.BearerTokenEnv != "" && basic:
+		return fmt.Errorf("bearerTokenEnv cannot be combined with usernameEnv or passwordEnv")
+	case a.BearerTokenEnv == "" && !basic:
+		return fmt.Errorf("one of bearerTokenEnv or usernameEnv and passwordEnv is required")
+	case basic && (a.UsernameEnv == "" || a.PasswordEnv == ""):
+		return fmt.Errorf("usernameEnv and passwordEnv must be set together")
+	}
+	return nil
+}
+
+// validateHelmAuth checks Helm chart credentials name a config file, basic auth
+// credentials or an identity token
+func validateHelmAuth(a *HelmAuth) error {
+	basic := a.UsernameEnv != "" || a.PasswordEnv != ""
+	switch {
+	case a.ConfigFile == "" && !basic && a.IdentityTokenEnv == "":
+		return fmt.Errorf("one of configFile, usernameEnv and passwordEnv, or identityTokenEnv is required")
+	case basic && (a.UsernameEnv == "" || a.PasswordEnv == ""):
+		return fmt.Errorf("usernameEnv and passwordEnv must be set together")
+	case basic && a.IdentityTokenEnv != "":
+		return fmt.Errorf("identityTokenEnv cannot be combined with usernameEnv and passwordEnv")
+	}
+	if a.ConfigFile != "" {
+		if _, err := os.Stat(a.ConfigFile); err != nil {
+			return fmt.Errorf("configFile: %w", err)
+		}
+	}
+	return nil
+}
+
+// sha256HexPattern matches a hex-encoded SHA-256 digest
+var sha256HexPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// imageTagPattern matches a valid container image tag
+var imageTagPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+
+// validateKwokSettings validates topology-wide Kwok settings
+func validateKwokSettings(k *KwokSettings) error {
+	if k.ManifestSHA256 != "" {
+		if k.Offline {
+			return fmt.Errorf("kwok: manifestSHA256 cannot be used with offline (embedded manifests are not downloaded)")
+		}
+		if !sha256HexPattern.MatchString(k.ManifestSHA256) {
+			return fmt.Errorf("kwok: manifestSHA256 must be 64 lowercase hex characters")
+		}
+	}
+
+	if k.PodStartup != nil {
+		if err := validatePodStartup(k.PodStartup); err != nil {
+			return fmt.Errorf("kwok.podStartup: %w", err)
+		}
+	}
+
+	if k.Heartbeat != nil {
+		if err := validateHeartbeat(k.Heartbeat); err != nil {
+			return fmt.Errorf("kwok.heartbeat: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateClientSettings validates API client rate limit and timeout overrides
+func validateClientSettings(c *ClientSettings) error {
+	if c.QPS < 0 {
+		return fmt.Errorf("client: qps must be non-negative")
+	}
+	if c.Burst < 0 {
+		return fmt.Errorf("client: burst must be non-negative")
+	}
+	if c.Timeout != "" {
+		d, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			return fmt.Errorf("client: invalid timeout '%s': %w", c.Timeout, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("client: timeout must be positive")
+		}
+	}
+	return nil
+}
+
+// validateKueueSettings validates the Kueue install source: at most one of chart,
+// manifest or buildFrom; version only selects a registry chart, and a rendered manifest
+// takes no Helm values. Whether the source exists is checked by CheckKueueSource.
+func validateKueueSettings(k *KueueSettings) error {
+	sources := 0
+	for _, set := range []bool{k.Chart != "", k.Manifest != "", k.BuildFrom != nil} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return fmt.Errorf("kueue: chart, manifest and buildFrom are mutually exclusive")
+	}
+	if sources == 1 && k.Version != "" {
+		return fmt.Errorf("kueue: version cannot be used with chart, manifest or buildFrom")
+	}
+	if k.Manifest != "" && (len(k.HelmValues) > 0 || k.Config != nil || len(k.FeatureGates) > 0) {
+		return fmt.Errorf("kueue: helmValues, config and featureGates cannot be used with manifest")
+	}
+	if err := validateFeatureGates(k.FeatureGates); err != nil {
+		return fmt.Errorf("kueue.featureGates: %w", err)
+	}
+	if k.ProvisionConcurrency < 0 {
+		return fmt.Errorf("kueue: provisionConcurrency must be >= 0")
+	}
+	if k.Config != nil {
+		if err := validateManagerConfig(k.Config); err != nil {
+			return fmt.Errorf("kueue.config: %w", err)
+		}
+	}
+
+	if b := k.BuildFrom; b != nil {
+		if b.Path == "" {
+			return fmt.Errorf("kueue.buildFrom: path is required")
+		}
+		if b.ImageTag != "" && !imageTagPattern.MatchString(b.ImageTag) {
+			return fmt.Errorf("kueue.buildFrom: invalid imageTag '%s'", b.ImageTag)
+		}
+	}
+	return nil
+}
+
+// featureGatePattern matches a Kubernetes-style feature gate name, e.g. TopologyAwareScheduling
+var featureGatePattern = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
+// validateFeatureGates validates feature gate names. Whether a gate exists is checked
+// by Kueue at startup, since the set of gates depends on the installed version.
+func validateFeatureGates(gates map[string]bool) error {
+	for _, name := range sortedKeys(gates) {
+		if !featureGatePattern.MatchString(name) {
+			return fmt.Errorf("invalid feature gate name '%s'", name)
+		}
+	}
+	return nil
+}
+
+// hasClusterFeatureGates reports whether any cluster or workerSet sets Kueue feature gates
+func hasClusterFeatureGates(t *Topology) bool {
+	for _, c := range t.Spec.Clusters {
+		if len(c.KueueFeatureGates) > 0 {
+			return true
+		}
+	}
+	for _, ws := range t.Spec.WorkerSets {
+		if len(ws.KueueFeatureGates) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// validateManagerConfig validates Kueue Configuration fields: durations, preemption
+// strategies, client rate limits and integrations
+func validateManagerConfig(c *KueueManagerConfig) error {
+	durations := map[string]string{}
+	if w := c.WaitForPodsReady; w != nil {
+		durations["waitForPodsReady.timeout"] = w.Timeout
+		durations["waitForPodsReady.recoveryTimeout"] = w.RecoveryTimeout
+	}
+	if a := c.AdmissionFairSharing; a != nil {
+		durations["admissionFairSharing.usageHalfLifeTime"] = a.UsageHalfLifeTime
+		durations["admissionFairSharing.usageSamplingInterval"] = a.UsageSamplingInterval
+	}
+	if m := c.MultiKueue; m != nil {
+		durations["multiKueue.workerLostTimeout"] = m.WorkerLostTimeout
+	}
+	for _, field := range sortedKeys(durations) {
+		if d := durations[field]; d != "" {
+			if v, err := time.ParseDuration(d); err != nil || v < 0 {
+				return fmt.Errorf("%s: invalid duration '%s'", field, d)
+			}
+		}
+	}
+
+	if f := c.FairSharing; f != nil {
+		for _, strategy := range f.PreemptionStrategies {
+			if strategy != PreemptionLessThanOrEqualToFinalShare && strategy != PreemptionLessThanInitialShare {
+				return fmt.Errorf("fairSharing: invalid preemption strategy '%s' (must be %s or %s)",
+					strategy, PreemptionLessThanOrEqualToFinalShare, PreemptionLessThanInitialShare)
+			}
+		}
+		// Kueue only accepts one of each strategy, with LessThanOrEqualToFinalShare first
+		if len(f.PreemptionStrategies) > 1 && !slices.Equal(f.PreemptionStrategies, DefaultPreemptionStrategies) {
+			return fmt.Errorf("fairSharing: preemptionStrategies must be [%s] when both are used",
+				strings.Join(DefaultPreemptionStrategies, ", "))
+		}
+	}
+
+	if a := c.AdmissionFairSharing; a != nil {
+		for name, weight := range a.ResourceWeights {
+			if weight < 0 {
+				return fmt.Errorf("admissionFairSharing: resource weight for %s must be >= 0", name)
+			}
+		}
+	}
+
+	if cc := c.ClientConnection; cc != nil && (cc.QPS < 0 || cc.Burst < 0) {
+		return fmt.Errorf("clientConnection: qps and burst must be >= 0")
+	}
+
+	if i := c.Integrations; i != nil {
+		for _, framework := range i.Frameworks {
+			if !slices.Contains(knownFrameworks, framework) {
+				return fmt.Errorf("integrations: unknown framework '%s'", framework)
+			}
+		}
+	}
+	return nil
+}
+
+// validateMultiKueueIntegrations checks that the Kueue integrations of a topology with
+// workerSets can be dispatched by MultiKueue
+func validateMultiKueueIntegrations(k *KueueSettings, workerSets []WorkerSet) error {
+	if len(workerSets) == 0 || k.Config == nil || k.Config.Integrations == nil {
+		return nil
+	}
+	for _, framework := range k.Config.Integrations.Frameworks {
+		if !slices.Contains(MultiKueueFrameworks, framework) {
+			return fmt.Errorf("kueue.config.integrations: framework '%s' has no MultiKueue adapter", framework)
+		}
+	}
+	return nil
+}
+
+// validateHeartbeat validates heartbeat mode and intervals
+func validateHeartbeat(h *HeartbeatSettings) error {
+	if h.Mode != "" && h.Mode != HeartbeatModeRealistic && h.Mode != HeartbeatModeReduced {
+		return fmt.Errorf("invalid mode '%s' (must be %s or %s)", h.Mode, HeartbeatModeRealistic, HeartbeatModeReduced)
+	}
+	if h.LeaseDuration != "" {
+		d, err := time.ParseDuration(h.LeaseDuration)
+		if err != nil || d < 4*time.Second || d%time.Second != 0 {
+			return fmt.Errorf("invalid leaseDuration %q: must be a whole number of seconds, at least 4s", h.LeaseDuration)
+		}
+	}
+	if h.StatusInterval != "" {
+		if d, err := time.ParseDuration(h.StatusInterval); err != nil || d <= 0 {
+			return fmt.Errorf("invalid statusInterval %q: must be a positive duration", h.StatusInterval)
+		}
+	}
+	return nil
+}
+
+// validatePodStartup validates the pod startup delay and its label overrides
+func validatePodStartup(p *PodStartupLatency) error {
+	if err := validateDelayRange(p.Min, p.Max); err != nil {
+		return err
+	}
+
+	labels := make(map[string]bool, len(p.Overrides))
+	for i, o := range p.Overrides {
+		key, value, ok := strings.Cut(o.Label, "=")
+		if !ok {
+			return fmt.Errorf("overrides[%d]: label must be key=value, got '%s'", i, o.Label)
+		}
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("overrides[%d]: invalid label key '%s': %s", i, key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("overrides[%d]: invalid label value '%s': %s", i, value, strings.Join(errs, "; "))
+		}
+		if labels[o.Label] {
+			return fmt.Errorf("overrides[%d]: duplicate label '%s'", i, o.Label)
+		}
+		labels[o.Label] = true
+
+		if o.Min == "" && o.Max == "" {
+			return fmt.Errorf("overrides[%d] (%s): min or max is required", i, o.Label)
+		}
+		if err := validateDelayRange(o.Min, o.Max); err != nil {
+			return fmt.Errorf("overrides[%d] (%s): %w", i, o.Label, err)
+		}
+	}
+	return nil
+}
+
+// validateDelayRange checks that min and max are non-negative durations with min <= max.
+// Either may be empty.
+func validateDelayRange(minDelay, maxDelay string) error {
+	var lo, hi time.Duration
+	var err error
+	if minDelay != "" {
+		if lo, err = time.ParseDuration(minDelay); err != nil || lo < 0 {
+			return fmt.Errorf("invalid min %q: must be a non-negative duration", minDelay)
+		}
+	}
+	if maxDelay != "" {
+		if hi, err = time.ParseDuration(maxDelay); err != nil || hi < 0 {
+			return fmt.Errorf("invalid max %q: must be a non-negative duration", maxDelay)
+		}
+		if hi < lo {
+			return fmt.Errorf("max %s must be >= min %s", maxDelay, minDelay)
+		}
+	}
+	return nil
+}
+
+// maxDevicesPerSlice is the most devices a single ResourceSlice may hold
+const maxDevicesPerSlice = 128
+
+// maxDeviceAttributesAndCapacities is the most attributes plus capacities a device may have
+const maxDeviceAttributesAndCapacities = 32
+
+// validateDeviceSet validates a simulated DRA device set
+func validateDeviceSet(d *DeviceSet) error {
+	if errs := validation.IsDNS1123Subdomain(d.Driver); len(errs) > 0 {
+		return fmt.Errorf("invalid driver '%s': %s", d.Driver, strings.Join(errs, "; "))
+	}
+	if d.Count <= 0 || d.Count > maxDevicesPerSlice {
+		return fmt.Errorf("count must be between 1 and %d", maxDevicesPerSlice)
+	}
+	if d.DeviceClass != "" {
+		if errs := validation.IsDNS1123Subdomain(d.DeviceClass); len(errs) > 0 {
+			return fmt.Errorf("invalid deviceClass '%s': %s", d.DeviceClass, strings.Join(errs, "; "))
+		}
+	}
+	if len(d.Attributes)+len(d.Capacity) > maxDeviceAttributesAndCapacities {
+		return fmt.Errorf("at most %d attributes and capacities are allowed", maxDeviceAttributesAndCapacities)
+	}
+	for name := range d.Attributes {
+		if errs := validation.IsCIdentifier(name); len(errs) > 0 {
+			return fmt.Errorf("invalid attribute name '%s': must be a C identifier", name)
+		}
+	}
+	for name, quantity := range d.Capacity {
+		if errs := validation.IsCIdentifier(name); len(errs) > 0 {
+			return fmt.Errorf("invalid capacity name '%s': must be a C identifier", name)
+		}
+		if _, err := resource.ParseQuantity(quantity); err != nil {
+			return fmt.Errorf("invalid capacity quantity for %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validateNodeNames renders every node name and templated label of a cluster's pools,
+// checking that names are valid and unique and label values are valid.
+func validateNodeNames(pools []NodePool) error {
+	names := make(map[string]string)
+	for i := range pools {
+		pool := &pools[i]
+		namer, err := NewNodeNamer(pool)
+		if err != nil {
+			return fmt.Errorf("nodePool (%s): %w", pool.Name, err)
+		}
+		for index := 0; index < pool.Count; index++ {
+			name, err := namer.Name(index)
+			if err != nil {
+				return fmt.Errorf("nodePool (%s): %w", pool.Name, err)
+			}
+			if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+				return fmt.Errorf("nodePool (%s): invalid node name '%s': %s", pool.Name, name, strings.Join(errs, "; "))
+			}
+			if other, ok := names[name]; ok {
+				if other == pool.Name {
+					return fmt.Errorf("nodePool (%s): nameTemplate generates duplicate node name '%s' (include {{.Index}})", pool.Name, name)
+				}
+				return fmt.Errorf("nodePool (%s): node name '%s' is also generated by pool '%s'", pool.Name, name, other)
+			}
+			names[name] = pool.Name
+
+			labels, err := namer.Labels(index)
+			if err != nil {
+				return fmt.Errorf("nodePool (%s): %w", pool.Name, err)
+			}
+			for key, value := range labels {
+				if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+					return fmt.Errorf("nodePool (%s): label %s renders invalid value '%s' for node %s", pool.Name, key, value, name)
+				}
+			}
+		}
+	}
 	return nil
 }
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"math"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -60,6 +63,25 @@ func TestValidateTopology(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid cluster feature gate",
+			topo: &Topology{
+				APIVersion: "kueue-bench.io/v1alpha1",
+				Kind:       "Topology",
+				Metadata:   Metadata{Name: "test"},
+				Spec: TopologySpec{
+					Clusters: []ClusterConfig{
+						{
+							Name:              "test",
+							Role:              "standalone",
+							NodePools:         []NodePool{{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}}},
+							KueueFeatureGates: map[string]bool{"tas": true},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "valid management role",
 			topo: &Topology{
@@ -228,6 +250,21 @@ func TestValidateCohorts(t *testing.T) {
 			},
 			wantErr: false, // Order doesn't matter, we build map first
 		},
+		{
+			name: "decimal fair sharing weight",
+			cohorts: []Cohort{
+				{Name: "platform", FairSharing: &FairSharing{Weight: "0.5"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid fair sharing weight",
+			cohorts: []Cohort{
+				{Name: "platform", FairSharing: &FairSharing{Weight: "half"}},
+			},
+			wantErr:     true,
+			errContains: "cohort[0] (platform): fairSharing: invalid weight 'half'",
+		},
 	}
 
 	for _, tt := range tests {
@@ -393,6 +430,142 @@ func TestValidateTopologyWithCohorts(t *testing.T) {
 	}
 }
 
+func TestValidateResourceGroups(t *testing.T) {
+	cpuMem := func(flavor, cpu, lendingLimit string) FlavorQuotas {
+		return FlavorQuotas{Name: flavor, Resources: []Resource{
+			{Name: "cpu", NominalQuota: cpu, LendingLimit: lendingLimit},
+			{Name: "memory", NominalQuota: "64Gi"},
+		}}
+	}
+	withCohort := func(cohort Cohort) *KueueConfig {
+		return &KueueConfig{
+			Cohorts:         []Cohort{{Name: "root"}, cohort},
+			ResourceFlavors: []ResourceFlavor{{Name: "on-demand"}, {Name: "spot"}, {Name: "gpu"}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		kueue       *KueueConfig
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "cohort with multiple flavors and limits",
+			kueue: withCohort(Cohort{Name: "team", ParentName: "root", ResourceGroups: []ResourceGroup{
+				{
+					CoveredResources: []string{"cpu", "memory"},
+					Flavors: []FlavorQuotas{
+						cpuMem("on-demand", "16", "8"),
+						{Name: "spot", Resources: []Resource{
+							{Name: "cpu", NominalQuota: "32", BorrowingLimit: "0"},
+							{Name: "memory", NominalQuota: "128Gi", BorrowingLimit: "64Gi", LendingLimit: "0"},
+						}},
+					},
+				},
+				{
+					CoveredResources: []string{"nvidia.com/gpu"},
+					Flavors:          []FlavorQuotas{{Name: "gpu", Resources: []Resource{{Name: "nvidia.com/gpu", NominalQuota: "8", BorrowingLimit: "4"}}}},
+				},
+			}}),
+			wantErr: false,
+		},
+		{
+			name: "cohort limits without parent",
+			kueue: withCohort(Cohort{Name: "team", ResourceGroups: []ResourceGroup{
+				{CoveredResources: []string{"cpu", "memory"}, Flavors: []FlavorQuotas{cpuMem("on-demand", "16", "8")}},
+			}}),
+			wantErr:     true,
+			errContains: "cohort[1] (team): resourceGroup[0]: flavor[0] (on-demand): resource[0]: lendingLimit requires parentName to be set",
+		},
+		{
+			name: "lending limit above nominal quota",
+			kueue: withCohort(Cohort{Name: "team", ParentName: "root", ResourceGroups: []ResourceGroup{
+				{CoveredResources: []string{"cpu", "memory"}, Flavors: []FlavorQuotas{cpuMem("on-demand", "4", "8")}},
+			}}),
+			wantErr:     true,
+			errContains: "lendingLimit 8 must not exceed nominalQuota 4",
+		},
+		{
+			name: "negative borrowing limit",
+			kueue: withCohort(Cohort{Name: "team", ParentName: "root", ResourceGroups: []ResourceGroup{
+				{CoveredResources: []string{"cpu"}, Flavors: []FlavorQuotas{{Name: "spot", Resources: []Resource{{Name: "cpu", NominalQuota: "4", BorrowingLimit: "-1"}}}}},
+			}}),
+			wantErr:     true,
+			errContains: "borrowingLimit must not be negative",
+		},
+		{
+			name: "cohort flavor unknown",
+			kueue: withCohort(Cohort{Name: "team", ResourceGroups: []ResourceGroup{
+				{CoveredResources: []string{"cpu", "memory"}, Flavors: []FlavorQuotas{cpuMem("reserved", "16", "")}},
+			}}),
+			wantErr:     true,
+			errContains: "cohort[1] (team): resourceGroup[0]: flavor[0]: unknown resourceFlavor 'reserved'",
+		},
+		{
+			name: "flavor in two resource groups",
+			kueue: withCohort(Cohort{Name: "team", ResourceGroups: []ResourceGroup{
+				{CoveredResources: []string{"cpu", "memory"}, Flavors: []FlavorQuotas{cpuMem("on-demand", "16", "")}},
+				{CoveredResources: []string{"nvidia.com/gpu"}, Flavors: []FlavorQuotas{{Name: "on-demand", Resources: []Resource{{Name: "nvidia.com/gpu", NominalQuota: "1"}}}}},
+			}}),
+			wantErr:     true,
+			errContains: "resourceGroup[1]: flavor[0]: flavor 'on-demand' appears more than once",
+		},
+		{
+			name: "resource in two resource groups",
+			kueue: withCohort(Cohort{Name: "team", ResourceGroups: []ResourceGroup{
+				{CoveredResources: []string{"cpu", "memory"}, Flavors: []FlavorQuotas{cpuMem("on-demand", "16", "")}},
+				{CoveredResources: []string{"cpu"}, Flavors: []FlavorQuotas{{Name: "spot", Resources: []Resource{{Name: "cpu", NominalQuota: "1"}}}}},
+			}}),
+			wantErr:     true,
+			errContains: "resourceGroup[1]: resource 'cpu' is covered by more than one resourceGroup",
+		},
+		{
+			name: "flavor resources out of order",
+			kueue: withCohort(Cohort{Name: "team", ResourceGroups: []ResourceGroup{
+				{CoveredResources: []string{"memory", "cpu"}, Flavors: []FlavorQuotas{cpuMem("on-demand", "16", "")}},
+			}}),
+			wantErr:     true,
+			errContains: "resource[0]: name 'cpu' must match coveredResource 'memory'",
+		},
+		{
+			name: "flavor missing a covered resource",
+			kueue: withCohort(Cohort{Name: "team", ResourceGroups: []ResourceGroup{
+				{CoveredResources: []string{"cpu", "memory"}, Flavors: []FlavorQuotas{{Name: "spot", Resources: []Resource{{Name: "cpu", NominalQuota: "1"}}}}},
+			}}),
+			wantErr:     true,
+			errContains: "flavor[0] (spot): must list the 2 coveredResources, got 1 resources",
+		},
+		{
+			name: "clusterQueue borrowing limit without cohort",
+			kueue: &KueueConfig{
+				ResourceFlavors: []ResourceFlavor{{Name: "default"}},
+				ClusterQueues: []ClusterQueue{{Name: "cq", ResourceGroups: []ResourceGroup{{
+					CoveredResources: []string{"cpu"},
+					Flavors:          []FlavorQuotas{{Name: "default", Resources: []Resource{{Name: "cpu", NominalQuota: "10", BorrowingLimit: "5"}}}},
+				}}}},
+			},
+			wantErr:     true,
+			errContains: "clusterQueue[0] (cq): resourceGroup[0]: flavor[0] (default): resource[0]: borrowingLimit requires cohort to be set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateKueueConfig(tt.kueue, 0, "test-cluster")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateKueueConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("validateKueueConfig() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
 func TestValidateWorkerSets(t *testing.T) {
 	validWorkerSet := func() WorkerSet {
 		return WorkerSet{
@@ -436,8 +609,10 @@ func TestValidateWorkerSets(t *testing.T) {
 		name         string
 		workerSets   []WorkerSet
 		clusterNames map[string]bool
-		wantErr      bool
-		errContains  string
+		// defaultManagement is the sole management cluster, if any
+		defaultManagement string
+		wantErr           bool
+		errContains       string
 	}{
 		{
 			name:         "valid workerSet",
@@ -543,16 +718,112 @@ func TestValidateWorkerSets(t *testing.T) {
 			errContains:  "conflicts with an existing cluster",
 		},
 		{
-			name: "duplicate worker names across workerSets",
+			name: "worker shared across workerSets",
 			workerSets: []WorkerSet{
 				validWorkerSet(),
 				func() WorkerSet {
 					ws := validWorkerSet()
 					ws.Name = "other-workers"
+					ws.ClusterQueues[0].Name = "other-cq"
+					ws.LocalQueues = []LocalQueue{{Name: "other-lq", Namespace: "team-ns", ClusterQueue: "other-cq"}}
 					return ws // same worker name "worker-1"
 				}(),
 			},
 			clusterNames: map[string]bool{},
+			wantErr:      false,
+		},
+		{
+			name: "shared worker with conflicting clusterQueue",
+			workerSets: []WorkerSet{
+				validWorkerSet(),
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Name = "other-workers"
+					return ws // same worker and clusterQueue names
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  `clusterQueue "team-cq" is defined by another workerSet`,
+		},
+		{
+			name: "shared worker with different node pool",
+			workerSets: []WorkerSet{
+				validWorkerSet(),
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Name = "other-workers"
+					ws.ClusterQueues[0].Name = "other-cq"
+					ws.LocalQueues = nil
+					ws.Workers[0].NodePools[0].Count = 20
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  `nodePool "gpu-pool" differs from another workerSet`,
+		},
+		{
+			name: "shared worker with different credentials",
+			workerSets: []WorkerSet{
+				validWorkerSet(),
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Name = "other-workers"
+					ws.ClusterQueues[0].Name = "other-cq"
+					ws.LocalQueues = nil
+					ws.Credentials = CredentialsServiceAccount
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "shared with workerSet 'gpu-workers': credentials must match",
+		},
+		{
+			name: "shared worker naming the default management cluster",
+			workerSets: []WorkerSet{
+				validWorkerSet(),
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Name = "other-workers"
+					ws.ClusterQueues[0].Name = "other-cq"
+					ws.LocalQueues = nil
+					ws.ManagementRef = "manager"
+					return ws
+				}(),
+			},
+			clusterNames:      map[string]bool{},
+			defaultManagement: "manager",
+		},
+		{
+			name: "shared worker with a management cluster other than the default",
+			workerSets: []WorkerSet{
+				validWorkerSet(),
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Name = "other-workers"
+					ws.ClusterQueues[0].Name = "other-cq"
+					ws.LocalQueues = nil
+					ws.ManagementRef = "other-manager"
+					return ws
+				}(),
+			},
+			clusterNames:      map[string]bool{},
+			defaultManagement: "manager",
+			wantErr:           true,
+			errContains:       "managementRef must match",
+		},
+		{
+			name: "duplicate worker names within a workerSet",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Workers = append(ws.Workers, ws.Workers[0])
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
 			wantErr:      true,
 			errContains:  "duplicate worker name 'worker-1'",
 		},
@@ -608,352 +879,2706 @@ func TestValidateWorkerSets(t *testing.T) {
 			wantErr:      true,
 			errContains:  "unknown clusterQueue 'nonexistent-cq'",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			err := validateWorkerSets(tt.workerSets, tt.clusterNames)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("validateWorkerSets() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if tt.wantErr && tt.errContains != "" {
-				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
-					t.Errorf("validateWorkerSets() error = %v, expected to contain %q", err, tt.errContains)
-				}
-			}
-		})
-	}
-}
-
-func TestValidateExtensions(t *testing.T) {
-	tests := []struct {
-		name        string
-		extensions  []Extension
-		wantErr     bool
-		errContains string
-	}{
 		{
-			name: "valid helm extension",
-			extensions: []Extension{
-				{
-					Name: "jobset",
-					Helm: &HelmExtension{
-						Chart:     "oci://registry.k8s.io/jobset/charts/jobset",
-						Version:   "0.11.0",
-						Namespace: "jobset-system",
-					},
-				},
+			name: "serviceAccount credentials",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Credentials = CredentialsServiceAccount
+					return ws
+				}(),
 			},
-			wantErr: false,
+			clusterNames: map[string]bool{},
+			wantErr:      false,
 		},
 		{
-			name: "valid manifest extension",
-			extensions: []Extension{
-				{
-					Name:     "custom-crds",
-					Manifest: &ManifestExtension{URL: "https://example.com/crds.yaml"},
-				},
+			name: "invalid credentials",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Credentials = "token"
+					return ws
+				}(),
 			},
-			wantErr: false,
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "invalid credentials 'token' (must be admin or serviceAccount)",
 		},
 		{
-			name: "missing name",
-			extensions: []Extension{
-				{
-					Helm: &HelmExtension{Chart: "oci://example.com/chart"},
-				},
+			name: "incremental dispatcher with worker order",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Dispatch = &WorkerSetDispatch{Dispatcher: DispatcherIncremental, Order: []string{"worker-1"}}
+					return ws
+				}(),
 			},
-			wantErr:     true,
-			errContains: "name is required",
+			clusterNames: map[string]bool{},
+			wantErr:      false,
 		},
 		{
-			name: "duplicate names",
-			extensions: []Extension{
-				{Name: "ext1", Helm: &HelmExtension{Chart: "oci://example.com/chart1"}},
-				{Name: "ext1", Helm: &HelmExtension{Chart: "oci://example.com/chart2"}},
+			name: "invalid dispatcher",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Dispatch = &WorkerSetDispatch{Dispatcher: "roundRobin"}
+					return ws
+				}(),
 			},
-			wantErr:     true,
-			errContains: "duplicate extension name 'ext1'",
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "dispatch: invalid dispatcher 'roundRobin'",
 		},
 		{
-			name: "neither helm nor manifest",
-			extensions: []Extension{
-				{Name: "empty"},
+			name: "worker order without incremental dispatcher",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Dispatch = &WorkerSetDispatch{Order: []string{"worker-1"}}
+					return ws
+				}(),
 			},
-			wantErr:     true,
-			errContains: "exactly one of 'helm' or 'manifest' is required",
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "order requires the incremental dispatcher",
 		},
 		{
-			name: "both helm and manifest",
-			extensions: []Extension{
-				{
-					Name:     "both",
-					Helm:     &HelmExtension{Chart: "oci://example.com/chart"},
-					Manifest: &ManifestExtension{URL: "https://example.com/manifest.yaml"},
-				},
+			name: "worker order references unknown worker",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Dispatch = &WorkerSetDispatch{Dispatcher: DispatcherIncremental, Order: []string{"worker-9"}}
+					return ws
+				}(),
 			},
-			wantErr:     true,
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "order: unknown worker 'worker-9'",
+		},
+		{
+			name: "conflicting dispatchers",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Dispatch = &WorkerSetDispatch{Dispatcher: DispatcherIncremental}
+					return ws
+				}(),
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Name = "cpu-workers"
+					ws.Workers[0].Name = "worker-2"
+					ws.Dispatch = &WorkerSetDispatch{Dispatcher: DispatcherAllAtOnce}
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "dispatcher 'allAtOnce' conflicts with 'incremental'",
+		},
+		{
+			name: "network shaping",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Network = &WorkerSetNetwork{Latency: "50ms", Jitter: "5ms", Loss: 0.5, Rate: "100mbit"}
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      false,
+		},
+		{
+			name: "network jitter without latency",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Network = &WorkerSetNetwork{Jitter: "5ms", Loss: 1}
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "network: jitter requires latency",
+		},
+		{
+			name: "network loss out of range",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Network = &WorkerSetNetwork{Loss: 150}
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "loss must be in [0, 100], got 150",
+		},
+		{
+			name: "network invalid rate",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Network = &WorkerSetNetwork{Rate: "10MB/s"}
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  `invalid rate "10MB/s"`,
+		},
+		{
+			name: "network on external worker",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Workers = append(ws.Workers, Worker{Name: "remote", Kubeconfig: "/tmp/remote.kubeconfig"})
+					ws.Network = &WorkerSetNetwork{Latency: "20ms"}
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "worker 'remote' is external",
+		},
+		{
+			name: "custom namespace and secret name template",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.KueueNamespace = "kueue-custom"
+					ws.SecretNameTemplate = "{{.WorkerSet}}-{{.Worker}}"
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      false,
+		},
+		{
+			name: "invalid kueueNamespace",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.KueueNamespace = "Kueue_System"
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "invalid kueueNamespace 'Kueue_System'",
+		},
+		{
+			name: "secret name template with unknown field",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.SecretNameTemplate = "{{.Cluster}}-kubeconfig"
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "failed to render secretNameTemplate",
+		},
+		{
+			name: "secret name template without worker",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.SecretNameTemplate = "{{.WorkerSet}}-kubeconfig"
+					ws.Workers = append(ws.Workers, ws.Workers[0])
+					ws.Workers[1].Name = "worker-2"
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "duplicate Secret name 'kueue-system/gpu-workers-kubeconfig' for worker 'worker-2'",
+		},
+		{
+			name: "external worker alongside simulated worker",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Workers = append(ws.Workers, Worker{Name: "staging", Kubeconfig: "/kubeconfigs/staging"})
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      false,
+		},
+		{
+			name: "external worker with nodePools",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Workers[0].Kubeconfig = "/kubeconfigs/staging"
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "nodePools cannot be set with kubeconfig",
+		},
+		{
+			name: "only external workers",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Workers = []Worker{{Name: "staging", Kubeconfig: "/kubeconfigs/staging"}}
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "at least one non-standby worker with nodePools is required",
+		},
+		{
+			name: "only standby workers",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.Workers[0].Standby = true
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "at least one non-standby worker with nodePools is required",
+		},
+		{
+			name: "non-positive capacityWeight",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					weight := 0.0
+					ws.Workers[0].CapacityWeight = &weight
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "capacityWeight must be > 0, got 0",
+		},
+		{
+			name: "infinite capacityWeight",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					weight := math.Inf(1)
+					ws.Workers[0].CapacityWeight = &weight
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "capacityWeight must be > 0, got +Inf",
+		},
+		{
+			name: "flavors assigned to different workers",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.ResourceFlavors = []WorkerSetFlavor{
+						{Name: "gpu-flavor", NodePoolRef: "gpu-pool", Workers: []string{"worker-1"}},
+						{Name: "h100-flavor", NodePoolRef: "h100-pool", Workers: []string{"worker-2"}},
+					}
+					ws.ClusterQueues[0].ResourceGroups[0].Flavors = []WorkerSetFlavorRef{{Name: "gpu-flavor"}, {Name: "h100-flavor"}}
+					ws.Workers = append(ws.Workers, Worker{
+						Name: "worker-2",
+						NodePools: []NodePool{
+							{Name: "h100-pool", Count: 2, Resources: map[string]string{"nvidia.com/gpu": "8", "cpu": "64"}},
+						},
+					})
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      false,
+		},
+		{
+			name: "flavor assigned to unknown worker",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.ResourceFlavors[0].Workers = []string{"worker-9"}
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "resourceFlavor[0] (gpu-flavor): unknown worker 'worker-9'",
+		},
+		{
+			name: "resourceGroup without a flavor for a worker",
+			workerSets: []WorkerSet{
+				func() WorkerSet {
+					ws := validWorkerSet()
+					ws.ResourceFlavors[0].Workers = []string{"worker-1"}
+					ws.Workers = append(ws.Workers, Worker{Name: "worker-2", NodePools: ws.Workers[0].NodePools})
+					return ws
+				}(),
+			},
+			clusterNames: map[string]bool{},
+			wantErr:      true,
+			errContains:  "worker[1] (worker-2): clusterQueue 'team-cq': resourceGroup[0]: no flavor applies to this worker",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWorkerSets(tt.workerSets, tt.clusterNames, tt.defaultManagement)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateWorkerSets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("validateWorkerSets() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateExtensions(t *testing.T) {
+	tests := []struct {
+		name        string
+		extensions  []Extension
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "valid helm extension",
+			extensions: []Extension{
+				{
+					Name: "jobset",
+					Helm: &HelmExtension{
+						Chart:     "oci://registry.k8s.io/jobset/charts/jobset",
+						Version:   "0.11.0",
+						Namespace: "jobset-system",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid manifest extension",
+			extensions: []Extension{
+				{
+					Name:     "custom-crds",
+					Manifest: &ManifestExtension{URL: "https://example.com/crds.yaml"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing name",
+			extensions: []Extension{
+				{
+					Helm: &HelmExtension{Chart: "oci://example.com/chart"},
+				},
+			},
+			wantErr:     true,
+			errContains: "name is required",
+		},
+		{
+			name: "duplicate names",
+			extensions: []Extension{
+				{Name: "ext1", Helm: &HelmExtension{Chart: "oci://example.com/chart1"}},
+				{Name: "ext1", Helm: &HelmExtension{Chart: "oci://example.com/chart2"}},
+			},
+			wantErr:     true,
+			errContains: "duplicate extension name 'ext1'",
+		},
+		{
+			name: "preset",
+			extensions: []Extension{
+				{Name: "jobset", Preset: PresetJobSet},
+				{Name: "cert-manager", Preset: PresetCertManager, Helm: &HelmExtension{Version: "v1.17.0"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown preset",
+			extensions: []Extension{
+				{Name: "volcano", Preset: "volcano"},
+			},
+			wantErr:     true,
+			errContains: "unknown preset 'volcano'",
+		},
+		{
+			name: "preset and manifest",
+			extensions: []Extension{
+				{Name: "jobset", Preset: PresetJobSet, Manifest: &ManifestExtension{URL: "https://example.com/manifest.yaml"}},
+			},
+			wantErr:     true,
+			errContains: "cannot specify both 'preset' and 'manifest'",
+		},
+		{
+			name: "neither helm nor manifest",
+			extensions: []Extension{
+				{Name: "empty"},
+			},
+			wantErr:     true,
+			errContains: "exactly one of 'helm', 'manifest', 'kustomize' or 'preset' is required",
+		},
+		{
+			name: "both helm and manifest",
+			extensions: []Extension{
+				{
+					Name:     "both",
+					Helm:     &HelmExtension{Chart: "oci://example.com/chart"},
+					Manifest: &ManifestExtension{URL: "https://example.com/manifest.yaml"},
+				},
+			},
+			wantErr:     true,
 			errContains: "cannot specify both 'helm' and 'manifest'",
 		},
 		{
-			name: "helm missing chart",
-			extensions: []Extension{
-				{Name: "no-chart", Helm: &HelmExtension{}},
-			},
+			name: "helm missing chart",
+			extensions: []Extension{
+				{Name: "no-chart", Helm: &HelmExtension{}},
+			},
+			wantErr:     true,
+			errContains: "helm.chart is required",
+		},
+		{
+			name: "helm missing values file",
+			extensions: []Extension{
+				{Name: "no-values", Helm: &HelmExtension{Chart: "oci://example.com/chart", ValuesFile: "/nonexistent/values.yaml"}},
+			},
+			wantErr:     true,
+			errContains: "helm.valuesFile",
+		},
+		{
+			name: "helm registry basic auth",
+			extensions: []Extension{
+				{Name: "private", Helm: &HelmExtension{Chart: "oci://registry.example.com/charts/operator", Auth: &HelmAuth{UsernameEnv: "REGISTRY_USER", PasswordEnv: "REGISTRY_PASSWORD"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "helm auth without credentials",
+			extensions: []Extension{
+				{Name: "private", Helm: &HelmExtension{Chart: "oci://registry.example.com/charts/operator", Auth: &HelmAuth{}}},
+			},
+			wantErr:     true,
+			errContains: "helm.auth: one of configFile",
+		},
+		{
+			name: "helm auth with identity token and basic auth",
+			extensions: []Extension{
+				{Name: "private", Helm: &HelmExtension{Chart: "oci://registry.example.com/charts/operator", Auth: &HelmAuth{UsernameEnv: "U", PasswordEnv: "P", IdentityTokenEnv: "T"}}},
+			},
+			wantErr:     true,
+			errContains: "helm.auth: identityTokenEnv cannot be combined",
+		},
+		{
+			name: "helm auth missing config file",
+			extensions: []Extension{
+				{Name: "private", Helm: &HelmExtension{Chart: "oci://registry.example.com/charts/operator", Auth: &HelmAuth{ConfigFile: "/nonexistent/config.json"}}},
+			},
+			wantErr:     true,
+			errContains: "helm.auth: configFile",
+		},
+		{
+			name: "post-install actions",
+			extensions: []Extension{
+				{Name: "kuberay", Preset: PresetKubeRay, PostInstall: []PostInstallAction{
+					{WaitForCRD: "rayclusters.ray.io", Timeout: "3m"},
+					{Namespace: "ray-workloads"},
+					{Manifest: &ManifestExtension{URL: "https://example.com/raycluster.yaml"}},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "post-install action with two actions",
+			extensions: []Extension{
+				{Name: "kuberay", Preset: PresetKubeRay, PostInstall: []PostInstallAction{
+					{Namespace: "ray-workloads", WaitForCRD: "rayclusters.ray.io"},
+				}},
+			},
+			wantErr:     true,
+			errContains: "postInstall[0]: exactly one of 'namespace', 'waitForCRD' or 'manifest' is required",
+		},
+		{
+			name: "post-install timeout without waitForCRD",
+			extensions: []Extension{
+				{Name: "kuberay", Preset: PresetKubeRay, PostInstall: []PostInstallAction{
+					{Namespace: "ray-workloads", Timeout: "1m"},
+				}},
+			},
+			wantErr:     true,
+			errContains: "postInstall[0]: timeout requires waitForCRD",
+		},
+		{
+			name: "post-install manifest without source",
+			extensions: []Extension{
+				{Name: "kuberay", Preset: PresetKubeRay, PostInstall: []PostInstallAction{
+					{Manifest: &ManifestExtension{}},
+				}},
+			},
+			wantErr:     true,
+			errContains: "exactly one of postInstall[0].manifest.url or postInstall[0].manifest.path is required",
+		},
+		{
+			name: "manifest missing url",
+			extensions: []Extension{
+				{Name: "no-url", Manifest: &ManifestExtension{}},
+			},
+			wantErr:     true,
+			errContains: "exactly one of manifest.url or manifest.path is required",
+		},
+		{
+			name: "manifest url and path",
+			extensions: []Extension{
+				{Name: "both", Manifest: &ManifestExtension{URL: "https://example.com/crds.yaml", Path: "crds.yaml"}},
+			},
+			wantErr:     true,
+			errContains: "exactly one of manifest.url or manifest.path is required",
+		},
+		{
+			name: "manifest glob without matches",
+			extensions: []Extension{
+				{Name: "none", Manifest: &ManifestExtension{Path: "/nonexistent/*.yaml"}},
+			},
+			wantErr:     true,
+			errContains: "manifest.path: no files match",
+		},
+		{
+			name: "manifest non-http url",
+			extensions: []Extension{
+				{Name: "bad-url", Manifest: &ManifestExtension{URL: "ftp://example.com/crds.yaml"}},
+			},
+			wantErr:     true,
+			errContains: "manifest.url must start with http:// or https://",
+		},
+		{
+			name: "manifest invalid sha256",
+			extensions: []Extension{
+				{Name: "bad-sum", Manifest: &ManifestExtension{URL: "https://example.com/crds.yaml", SHA256: "ABC"}},
+			},
+			wantErr:     true,
+			errContains: "manifest.sha256 must be 64 lowercase hex characters",
+		},
+		{
+			name: "manifest bearer auth",
+			extensions: []Extension{
+				{Name: "private", Manifest: &ManifestExtension{URL: "https://artifacts.example.com/crds.yaml", Auth: &ManifestAuth{BearerTokenEnv: "ARTIFACT_TOKEN"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "manifest basic auth without password",
+			extensions: []Extension{
+				{Name: "private", Manifest: &ManifestExtension{URL: "https://artifacts.example.com/crds.yaml", Auth: &ManifestAuth{UsernameEnv: "ARTIFACT_USER"}}},
+			},
+			wantErr:     true,
+			errContains: "manifest.auth: usernameEnv and passwordEnv must be set together",
+		},
+		{
+			name: "manifest bearer and basic auth",
+			extensions: []Extension{
+				{Name: "private", Manifest: &ManifestExtension{URL: "https://artifacts.example.com/crds.yaml", Auth: &ManifestAuth{BearerTokenEnv: "T", UsernameEnv: "U", PasswordEnv: "P"}}},
+			},
+			wantErr:     true,
+			errContains: "manifest.auth: bearerTokenEnv cannot be combined",
+		},
+		{
+			name: "valid kustomize url",
+			extensions: []Extension{
+				{Name: "kueue", Kustomize: &KustomizeExtension{URL: "https://github.com/kubernetes-sigs/kueue//config/default?ref=v0.14.0"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "kustomize missing url and path",
+			extensions: []Extension{
+				{Name: "empty", Kustomize: &KustomizeExtension{}},
+			},
+			wantErr:     true,
+			errContains: "exactly one of kustomize.url or kustomize.path is required",
+		},
+		{
+			name: "kustomize missing path",
+			extensions: []Extension{
+				{Name: "missing", Kustomize: &KustomizeExtension{Path: "/nonexistent/overlay"}},
+			},
+			wantErr:     true,
+			errContains: "kustomize.path",
+		},
+		{
+			name: "kustomize with manifest",
+			extensions: []Extension{
+				{Name: "both", Kustomize: &KustomizeExtension{URL: "https://example.com/repo"}, Manifest: &ManifestExtension{URL: "https://example.com/crds.yaml"}},
+			},
+			wantErr:     true,
+			errContains: "'kustomize' cannot be combined with 'helm' or 'manifest'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateExtensions(tt.extensions)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateExtensions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("validateExtensions() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateTopologyExtensions(t *testing.T) {
+	pool := []NodePool{{Name: "pool", Count: 1, Resources: map[string]string{"cpu": "8"}}}
+	spec := func(extensions ...Extension) TopologySpec {
+		return TopologySpec{
+			Extensions: extensions,
+			Clusters:   []ClusterConfig{{Name: "mgmt", Role: RoleManagement, NodePools: pool}},
+			WorkerSets: []WorkerSet{{Name: "ws", Workers: []Worker{{Name: "worker-1", NodePools: pool}}}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		spec        TopologySpec
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "targets by role and name",
+			spec: spec(
+				Extension{Name: "jobset", Preset: PresetJobSet, Targets: &ExtensionTargets{Roles: []string{RoleWorker}}},
+				Extension{Name: "lws", Preset: PresetLWS, Targets: &ExtensionTargets{Clusters: []string{"mgmt", "worker-1"}}},
+				Extension{Name: "cert-manager", Preset: PresetCertManager},
+			),
+			wantErr: false,
+		},
+		{
+			name:        "invalid extension",
+			spec:        spec(Extension{Name: "empty"}),
+			wantErr:     true,
+			errContains: "spec: extension[0] (empty): exactly one of",
+		},
+		{
+			name:        "empty targets",
+			spec:        spec(Extension{Name: "jobset", Preset: PresetJobSet, Targets: &ExtensionTargets{}}),
+			wantErr:     true,
+			errContains: "targets must list at least one role or cluster",
+		},
+		{
+			name:        "invalid role",
+			spec:        spec(Extension{Name: "jobset", Preset: PresetJobSet, Targets: &ExtensionTargets{Roles: []string{"workers"}}}),
+			wantErr:     true,
+			errContains: "targets: invalid role 'workers'",
+		},
+		{
+			name:        "unknown cluster",
+			spec:        spec(Extension{Name: "jobset", Preset: PresetJobSet, Targets: &ExtensionTargets{Clusters: []string{"worker-9"}}}),
+			wantErr:     true,
+			errContains: "targets: unknown cluster 'worker-9'",
+		},
+		{
+			name: "targets on cluster extension",
+			spec: func() TopologySpec {
+				s := spec()
+				s.Clusters[0].Extensions = []Extension{{Name: "jobset", Preset: PresetJobSet, Targets: &ExtensionTargets{Roles: []string{RoleWorker}}}}
+				return s
+			}(),
+			wantErr:     true,
+			errContains: "targets are only allowed on spec.extensions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTopologyExtensions(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateTopologyExtensions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("validateTopologyExtensions() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateMultiKueueIntegrations(t *testing.T) {
+	workerSets := []WorkerSet{{Name: "workers"}}
+	tests := []struct {
+		name        string
+		integration IntegrationsConfig
+		workerSets  []WorkerSet
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:        "MultiKueue frameworks",
+			integration: IntegrationsConfig{Frameworks: MultiKueueFrameworks},
+			workerSets:  workerSets,
+		},
+		{
+			name:        "pod-based MultiKueue frameworks",
+			integration: IntegrationsConfig{Frameworks: []string{FrameworkPod, FrameworkStatefulSet, FrameworkLeaderWorkerSet, FrameworkRayService}},
+			workerSets:  workerSets,
+		},
+		{
+			name:        "framework without MultiKueue adapter",
+			integration: IntegrationsConfig{Frameworks: []string{FrameworkBatchJob, FrameworkDeployment}},
+			workerSets:  workerSets,
+			wantErr:     true,
+			errContains: "framework 'deployment' has no MultiKueue adapter",
+		},
+		{
+			name:        "any framework without workerSets",
+			integration: IntegrationsConfig{Frameworks: []string{FrameworkPod, FrameworkDeployment}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := &KueueSettings{Config: &KueueManagerConfig{Integrations: &tt.integration}}
+			err := validateMultiKueueIntegrations(settings, tt.workerSets)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateMultiKueueIntegrations() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("validateMultiKueueIntegrations() error = %v, expected to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestValidateMultiKueueTopology(t *testing.T) {
+	tests := []struct {
+		name        string
+		topo        *Topology
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "valid: workerSet with management cluster",
+			topo: &Topology{
+				APIVersion: "kueue-bench.io/v1alpha1",
+				Kind:       "Topology",
+				Metadata:   Metadata{Name: "test"},
+				Spec: TopologySpec{
+					Clusters: []ClusterConfig{
+						{
+							Name: "management",
+							Role: "management",
+							NodePools: []NodePool{
+								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
+							},
+						},
+					},
+					WorkerSets: []WorkerSet{
+						{
+							Name: "workers",
+							ResourceFlavors: []WorkerSetFlavor{
+								{Name: "default", NodePoolRef: "pool"},
+							},
+							ClusterQueues: []WorkerSetClusterQueue{
+								{
+									Name: "cq",
+									ResourceGroups: []WorkerSetResourceGroup{
+										{
+											CoveredResources: []string{"cpu"},
+											Flavors:          []WorkerSetFlavorRef{{Name: "default"}},
+										},
+									},
+								},
+							},
+							Workers: []Worker{
+								{
+									Name: "worker-1",
+									NodePools: []NodePool{
+										{Name: "pool", Count: 1, Resources: map[string]string{"cpu": "1"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid: workerSet without management cluster",
+			topo: &Topology{
+				APIVersion: "kueue-bench.io/v1alpha1",
+				Kind:       "Topology",
+				Metadata:   Metadata{Name: "test"},
+				Spec: TopologySpec{
+					Clusters: []ClusterConfig{
+						{
+							Name: "standalone",
+							Role: "standalone",
+							NodePools: []NodePool{
+								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
+							},
+						},
+					},
+					WorkerSets: []WorkerSet{
+						{
+							Name: "workers",
+							ResourceFlavors: []WorkerSetFlavor{
+								{Name: "default", NodePoolRef: "pool"},
+							},
+							ClusterQueues: []WorkerSetClusterQueue{
+								{
+									Name: "cq",
+									ResourceGroups: []WorkerSetResourceGroup{
+										{
+											CoveredResources: []string{"cpu"},
+											Flavors:          []WorkerSetFlavorRef{{Name: "default"}},
+										},
+									},
+								},
+							},
+							Workers: []Worker{
+								{
+									Name: "worker-1",
+									NodePools: []NodePool{
+										{Name: "pool", Count: 1, Resources: map[string]string{"cpu": "1"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "workerSets require a cluster with role 'management'",
+		},
+		{
+			name: "invalid: workerSet without managementRef with multiple management clusters",
+			topo: &Topology{
+				APIVersion: "kueue-bench.io/v1alpha1",
+				Kind:       "Topology",
+				Metadata:   Metadata{Name: "test"},
+				Spec: TopologySpec{
+					Clusters: []ClusterConfig{
+						{
+							Name: "management-1",
+							Role: "management",
+							NodePools: []NodePool{
+								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
+							},
+						},
+						{
+							Name: "management-2",
+							Role: "management",
+							NodePools: []NodePool{
+								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
+							},
+						},
+					},
+					WorkerSets: []WorkerSet{
+						{
+							Name: "workers",
+							ResourceFlavors: []WorkerSetFlavor{
+								{Name: "default", NodePoolRef: "pool"},
+							},
+							ClusterQueues: []WorkerSetClusterQueue{
+								{
+									Name: "cq",
+									ResourceGroups: []WorkerSetResourceGroup{
+										{
+											CoveredResources: []string{"cpu"},
+											Flavors:          []WorkerSetFlavorRef{{Name: "default"}},
+										},
+									},
+								},
+							},
+							Workers: []Worker{
+								{
+									Name: "worker-1",
+									NodePools: []NodePool{
+										{Name: "pool", Count: 1, Resources: map[string]string{"cpu": "1"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "workerSet[0] (workers): managementRef is required with 2 management clusters",
+		},
+		{
+			name: "valid: multiple management clusters with disjoint workerSets",
+			topo: &Topology{
+				APIVersion: "kueue-bench.io/v1alpha1",
+				Kind:       "Topology",
+				Metadata:   Metadata{Name: "test"},
+				Spec: TopologySpec{
+					Clusters: []ClusterConfig{
+						{
+							Name: "management-1",
+							Role: "management",
+							NodePools: []NodePool{
+								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
+							},
+						},
+						{
+							Name: "management-2",
+							Role: "management",
+							NodePools: []NodePool{
+								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
+							},
+						},
+					},
+					WorkerSets: []WorkerSet{
+						{
+							Name:          "workers-1",
+							ManagementRef: "management-1",
+							ResourceFlavors: []WorkerSetFlavor{
+								{Name: "default", NodePoolRef: "pool"},
+							},
+							ClusterQueues: []WorkerSetClusterQueue{
+								{
+									Name: "cq-1",
+									ResourceGroups: []WorkerSetResourceGroup{
+										{
+											CoveredResources: []string{"cpu"},
+											Flavors:          []WorkerSetFlavorRef{{Name: "default"}},
+										},
+									},
+								},
+							},
+							Workers: []Worker{
+								{
+									Name: "worker-1",
+									NodePools: []NodePool{
+										{Name: "pool", Count: 1, Resources: map[string]string{"cpu": "1"}},
+									},
+								},
+							},
+						},
+						{
+							Name:          "workers-2",
+							ManagementRef: "management-2",
+							ResourceFlavors: []WorkerSetFlavor{
+								{Name: "default", NodePoolRef: "pool"},
+							},
+							ClusterQueues: []WorkerSetClusterQueue{
+								{
+									Name: "cq-2",
+									ResourceGroups: []WorkerSetResourceGroup{
+										{
+											CoveredResources: []string{"cpu"},
+											Flavors:          []WorkerSetFlavorRef{{Name: "default"}},
+										},
+									},
+								},
+							},
+							Workers: []Worker{
+								{
+									Name: "worker-2",
+									NodePools: []NodePool{
+										{Name: "pool", Count: 1, Resources: map[string]string{"cpu": "1"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid: managementRef is not a management cluster",
+			topo: &Topology{
+				APIVersion: "kueue-bench.io/v1alpha1",
+				Kind:       "Topology",
+				Metadata:   Metadata{Name: "test"},
+				Spec: TopologySpec{
+					Clusters: []ClusterConfig{
+						{
+							Name: "management-1",
+							Role: "management",
+							NodePools: []NodePool{
+								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
+							},
+						},
+						{
+							Name: "management-2",
+							Role: "management",
+							NodePools: []NodePool{
+								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
+							},
+						},
+					},
+					WorkerSets: []WorkerSet{
+						{
+							Name:          "workers",
+							ManagementRef: "management-3",
+							ResourceFlavors: []WorkerSetFlavor{
+								{Name: "default", NodePoolRef: "pool"},
+							},
+							ClusterQueues: []WorkerSetClusterQueue{
+								{
+									Name: "cq",
+									ResourceGroups: []WorkerSetResourceGroup{
+										{
+											CoveredResources: []string{"cpu"},
+											Flavors:          []WorkerSetFlavorRef{{Name: "default"}},
+										},
+									},
+								},
+							},
+							Workers: []Worker{
+								{
+									Name: "worker-1",
+									NodePools: []NodePool{
+										{Name: "pool", Count: 1, Resources: map[string]string{"cpu": "1"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "managementRef 'management-3' is not a cluster with role 'management'",
+		},
+		{
+			name: "invalid: worker shared across management clusters",
+			topo: &Topology{
+				APIVersion: "kueue-bench.io/v1alpha1",
+				Kind:       "Topology",
+				Metadata:   Metadata{Name: "test"},
+				Spec: TopologySpec{
+					Clusters: []ClusterConfig{
+						{
+							Name: "management-1",
+							Role: "management",
+							NodePools: []NodePool{
+								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
+							},
+						},
+						{
+							Name: "management-2",
+							Role: "management",
+							NodePools: []NodePool{
+								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
+							},
+						},
+					},
+					WorkerSets: []WorkerSet{
+						{
+							Name:          "workers-1",
+							ManagementRef: "management-1",
+							ResourceFlavors: []WorkerSetFlavor{
+								{Name: "default", NodePoolRef: "pool"},
+							},
+							ClusterQueues: []WorkerSetClusterQueue{
+								{
+									Name: "cq-1",
+									ResourceGroups: []WorkerSetResourceGroup{
+										{
+											CoveredResources: []string{"cpu"},
+											Flavors:          []WorkerSetFlavorRef{{Name: "default"}},
+										},
+									},
+								},
+							},
+							Workers: []Worker{
+								{
+									Name: "worker-1",
+									NodePools: []NodePool{
+										{Name: "pool", Count: 1, Resources: map[string]string{"cpu": "1"}},
+									},
+								},
+							},
+						},
+						{
+							Name:          "workers-2",
+							ManagementRef: "management-2",
+							ResourceFlavors: []WorkerSetFlavor{
+								{Name: "default", NodePoolRef: "pool"},
+							},
+							ClusterQueues: []WorkerSetClusterQueue{
+								{
+									Name: "cq-2",
+									ResourceGroups: []WorkerSetResourceGroup{
+										{
+											CoveredResources: []string{"cpu"},
+											Flavors:          []WorkerSetFlavorRef{{Name: "default"}},
+										},
+									},
+								},
+							},
+							Workers: []Worker{
+								{
+									Name: "worker-1",
+									NodePools: []NodePool{
+										{Name: "pool", Count: 1, Resources: map[string]string{"cpu": "1"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "managementRef must match",
+		},
+		{
+			name: "valid: no workerSets, no management cluster required",
+			topo: &Topology{
+				APIVersion: "kueue-bench.io/v1alpha1",
+				Kind:       "Topology",
+				Metadata:   Metadata{Name: "test"},
+				Spec: TopologySpec{
+					Clusters: []ClusterConfig{
+						{
+							Name: "standalone",
+							Role: "standalone",
+							NodePools: []NodePool{
+								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid: no workerSets, multiple standalone clusters",
+			topo: &Topology{
+				APIVersion: "kueue-bench.io/v1alpha1",
+				Kind:       "Topology",
+				Metadata:   Metadata{Name: "test"},
+				Spec: TopologySpec{
+					Clusters: []ClusterConfig{
+						{
+							Name: "cluster-1",
+							Role: "standalone",
+							NodePools: []NodePool{
+								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
+							},
+						},
+						{
+							Name: "cluster-2",
+							Role: "standalone",
+							NodePools: []NodePool{
+								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTopology(tt.topo)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTopology() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("ValidateTopology() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateImages(t *testing.T) {
+	tests := []struct {
+		name        string
+		images      []string
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:    "no images",
+			images:  nil,
+			wantErr: false,
+		},
+		{
+			name:    "valid images",
+			images:  []string{"kueue:dev", "registry.example.com/controller:v1"},
+			wantErr: false,
+		},
+		{
+			name:        "empty image reference",
+			images:      []string{"kueue:dev", " "},
+			wantErr:     true,
+			errContains: "images[1]: image reference is required",
+		},
+		{
+			name:        "duplicate image",
+			images:      []string{"kueue:dev", "kueue:dev"},
+			wantErr:     true,
+			errContains: "duplicate image 'kueue:dev'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateImages(tt.images)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateImages() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("validateImages() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateRegistry(t *testing.T) {
+	tests := []struct {
+		name        string
+		registry    *RegistrySettings
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "valid mirrors and local registry",
+			registry: &RegistrySettings{
+				Mirrors: []RegistryMirror{
+					{Registry: "docker.io", Endpoints: []string{"http://kueue-bench-registry:5000"}},
+					{Registry: "registry.k8s.io", Endpoints: []string{"https://mirror.example.com"}},
+				},
+				LocalRegistry: &LocalRegistry{Port: 5001, RemoteURL: "https://registry-1.docker.io"},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "local registry with defaults",
+			registry: &RegistrySettings{LocalRegistry: &LocalRegistry{}},
+			wantErr:  false,
+		},
+		{
+			name: "mirror missing registry",
+			registry: &RegistrySettings{
+				Mirrors: []RegistryMirror{{Endpoints: []string{"http://mirror:5000"}}},
+			},
+			wantErr:     true,
+			errContains: "registry is required",
+		},
+		{
+			name: "mirror registry with scheme",
+			registry: &RegistrySettings{
+				Mirrors: []RegistryMirror{{Registry: "https://docker.io", Endpoints: []string{"http://mirror:5000"}}},
+			},
+			wantErr:     true,
+			errContains: "without scheme",
+		},
+		{
+			name: "duplicate mirror registry",
+			registry: &RegistrySettings{
+				Mirrors: []RegistryMirror{
+					{Registry: "docker.io", Endpoints: []string{"http://a:5000"}},
+					{Registry: "docker.io", Endpoints: []string{"http://b:5000"}},
+				},
+			},
+			wantErr:     true,
+			errContains: "duplicate registry 'docker.io'",
+		},
+		{
+			name: "mirror without endpoints",
+			registry: &RegistrySettings{
+				Mirrors: []RegistryMirror{{Registry: "docker.io"}},
+			},
+			wantErr:     true,
+			errContains: "at least one endpoint is required",
+		},
+		{
+			name: "mirror endpoint without scheme",
+			registry: &RegistrySettings{
+				Mirrors: []RegistryMirror{{Registry: "docker.io", Endpoints: []string{"mirror:5000"}}},
+			},
+			wantErr:     true,
+			errContains: "endpoints[0]: must start with http:// or https://",
+		},
+		{
+			name:        "local registry invalid port",
+			registry:    &RegistrySettings{LocalRegistry: &LocalRegistry{Port: 70000}},
+			wantErr:     true,
+			errContains: "invalid port 70000",
+		},
+		{
+			name:        "local registry invalid remote URL",
+			registry:    &RegistrySettings{LocalRegistry: &LocalRegistry{RemoteURL: "registry-1.docker.io"}},
+			wantErr:     true,
+			errContains: "remoteURL must start with http:// or https://",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRegistry(tt.registry)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateRegistry() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("validateRegistry() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateNodePoolZones(t *testing.T) {
+	basePool := func() NodePool {
+		return NodePool{
+			Name:      "cpu",
+			Count:     4,
+			Resources: map[string]string{"cpu": "8"},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		modify      func(p *NodePool)
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:    "no zones",
+			modify:  func(p *NodePool) {},
+			wantErr: false,
+		},
+		{
+			name: "zones with region and racks",
+			modify: func(p *NodePool) {
+				p.Zones = []string{"zone-a", "zone-b"}
+				p.Region = "region-1"
+				p.NodesPerRack = 2
+			},
+			wantErr: false,
+		},
+		{
+			name:        "empty zone",
+			modify:      func(p *NodePool) { p.Zones = []string{"zone-a", ""} },
+			wantErr:     true,
+			errContains: "zone[1]: invalid zone name ''",
+		},
+		{
+			name:        "invalid zone label value",
+			modify:      func(p *NodePool) { p.Zones = []string{"zone a"} },
+			wantErr:     true,
+			errContains: "zone[0]: invalid zone name 'zone a'",
+		},
+		{
+			name:        "duplicate zone",
+			modify:      func(p *NodePool) { p.Zones = []string{"zone-a", "zone-a"} },
+			wantErr:     true,
+			errContains: "zone[1]: duplicate zone 'zone-a'",
+		},
+		{
+			name:        "invalid region",
+			modify:      func(p *NodePool) { p.Region = "us/east" },
+			wantErr:     true,
+			errContains: "invalid region 'us/east'",
+		},
+		{
+			name: "systemReserved below capacity",
+			modify: func(p *NodePool) {
+				p.SystemReserved = map[string]string{"cpu": "500m"}
+			},
+			wantErr: false,
+		},
+		{
+			name:        "systemReserved for unknown resource",
+			modify:      func(p *NodePool) { p.SystemReserved = map[string]string{"memory": "1Gi"} },
+			wantErr:     true,
+			errContains: "systemReserved: resource memory is not in the pool's resources",
+		},
+		{
+			name:    "systemReserved pods from the default capacity",
+			modify:  func(p *NodePool) { p.SystemReserved = map[string]string{"pods": "10"} },
+			wantErr: false,
+		},
+		{
+			name:        "systemReserved pods exceeds the default capacity",
+			modify:      func(p *NodePool) { p.SystemReserved = map[string]string{"pods": "111"} },
+			wantErr:     true,
+			errContains: "systemReserved: pods reservation 111 exceeds capacity 110",
+		},
+		{
+			name:        "systemReserved exceeds capacity",
+			modify:      func(p *NodePool) { p.SystemReserved = map[string]string{"cpu": "9"} },
+			wantErr:     true,
+			errContains: "systemReserved: cpu reservation 9 exceeds capacity 8",
+		},
+		{
+			name:        "invalid systemReserved quantity",
+			modify:      func(p *NodePool) { p.SystemReserved = map[string]string{"cpu": "lots"} },
+			wantErr:     true,
+			errContains: "systemReserved: invalid quantity for cpu",
+		},
+		{
+			name:    "cost per hour",
+			modify:  func(p *NodePool) { p.CostPerHour = 2.5 },
+			wantErr: false,
+		},
+		{
+			name:        "negative cost per hour",
+			modify:      func(p *NodePool) { p.CostPerHour = -1 },
+			wantErr:     true,
+			errContains: "costPerHour must be >= 0",
+		},
+		{
+			name:        "invalid resource name",
+			modify:      func(p *NodePool) { p.Resources["bad resource"] = "1" },
+			wantErr:     true,
+			errContains: "invalid resource name 'bad resource'",
+		},
+		{
+			name: "dra devices",
+			modify: func(p *NodePool) {
+				p.Devices = []DeviceSet{{
+					Driver:      "gpu.example.com",
+					Count:       8,
+					DeviceClass: "gpu.example.com",
+					Attributes:  map[string]string{"model": "a100"},
+					Capacity:    map[string]string{"memory": "80Gi"},
+				}}
+			},
+			wantErr: false,
+		},
+		{
+			name:        "dra devices invalid driver",
+			modify:      func(p *NodePool) { p.Devices = []DeviceSet{{Driver: "GPU_Driver", Count: 1}} },
+			wantErr:     true,
+			errContains: "devices[0]: invalid driver 'GPU_Driver'",
+		},
+		{
+			name:        "dra devices count too large",
+			modify:      func(p *NodePool) { p.Devices = []DeviceSet{{Driver: "gpu.example.com", Count: 129}} },
+			wantErr:     true,
+			errContains: "devices[0]: count must be between 1 and 128",
+		},
+		{
+			name: "dra devices invalid attribute name",
+			modify: func(p *NodePool) {
+				p.Devices = []DeviceSet{{Driver: "gpu.example.com", Count: 1, Attributes: map[string]string{"gpu-model": "a100"}}}
+			},
+			wantErr:     true,
+			errContains: "invalid attribute name 'gpu-model'",
+		},
+		{
+			name: "dra devices invalid capacity",
+			modify: func(p *NodePool) {
+				p.Devices = []DeviceSet{{Driver: "gpu.example.com", Count: 1, Capacity: map[string]string{"memory": "lots"}}}
+			},
+			wantErr:     true,
+			errContains: "invalid capacity quantity for memory",
+		},
+		{
+			name: "dra devices duplicate driver",
+			modify: func(p *NodePool) {
+				p.Devices = []DeviceSet{{Driver: "gpu.example.com", Count: 1}, {Driver: "gpu.example.com", Count: 2}}
+			},
+			wantErr:     true,
+			errContains: "devices[1]: duplicate driver 'gpu.example.com'",
+		},
+		{
+			name: "mig profiles",
+			modify: func(p *NodePool) {
+				p.MIG = &MIGConfig{Model: "a100-40gb", GPUs: 8, Profiles: map[string]int{"1g.5gb": 3, "2g.10gb": 2}}
+			},
+			wantErr: false,
+		},
+		{
+			name: "mig only",
+			modify: func(p *NodePool) {
+				p.Resources = nil
+				p.MIG = &MIGConfig{Model: "h100-80gb", GPUs: 8, Profiles: map[string]int{"7g.80gb": 1}}
+			},
+			wantErr: false,
+		},
+		{
+			name:        "mig unknown model",
+			modify:      func(p *NodePool) { p.MIG = &MIGConfig{Model: "v100", GPUs: 1, Profiles: map[string]int{"1g.5gb": 1}} },
+			wantErr:     true,
+			errContains: "mig: unknown model 'v100'",
+		},
+		{
+			name: "mig unsupported profile",
+			modify: func(p *NodePool) {
+				p.MIG = &MIGConfig{Model: "a100-80gb", GPUs: 1, Profiles: map[string]int{"1g.5gb": 1}}
+			},
+			wantErr:     true,
+			errContains: "mig: profile '1g.5gb' is not supported by a100-80gb",
+		},
+		{
+			name: "mig layout exceeds gpu",
+			modify: func(p *NodePool) {
+				p.MIG = &MIGConfig{Model: "a100-40gb", GPUs: 1, Profiles: map[string]int{"3g.20gb": 3}}
+			},
+			wantErr:     true,
+			errContains: "mig: profiles use 9/7 compute and 12/8 memory slices per GPU",
+		},
+		{
+			name:        "mig zero gpus",
+			modify:      func(p *NodePool) { p.MIG = &MIGConfig{Model: "a100-40gb", Profiles: map[string]int{"1g.5gb": 1}} },
+			wantErr:     true,
+			errContains: "mig: gpus must be > 0",
+		},
+		{
+			name: "mig resource also in resources",
+			modify: func(p *NodePool) {
+				p.Resources["nvidia.com/mig-1g.5gb"] = "7"
+				p.MIG = &MIGConfig{Model: "a100-40gb", GPUs: 1, Profiles: map[string]int{"1g.5gb": 7}}
+			},
+			wantErr:     true,
+			errContains: "mig: resource nvidia.com/mig-1g.5gb is also set in resources",
+		},
+		{
+			name:        "invalid nameTemplate",
+			modify:      func(p *NodePool) { p.NameTemplate = "{{.Pool" },
+			wantErr:     true,
+			errContains: "invalid nameTemplate",
+		},
+		{
+			name:        "invalid label value",
+			modify:      func(p *NodePool) { p.Labels = map[string]string{"tier": "batch jobs"} },
+			wantErr:     true,
+			errContains: "invalid value for label tier",
+		},
+		{
+			name:    "templated label value",
+			modify:  func(p *NodePool) { p.Labels = map[string]string{"example.com/rack": "{{.Rack}}"} },
+			wantErr: false,
+		},
+		{
+			name:        "negative nodesPerRack",
+			modify:      func(p *NodePool) { p.NodesPerRack = -1 },
+			wantErr:     true,
+			errContains: "nodesPerRack must be >= 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := basePool()
+			tt.modify(&pool)
+			err := validateNodePoolContents(&pool)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateNodePoolContents() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("validateNodePoolContents() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateKueueTopologies(t *testing.T) {
+	tests := []struct {
+		name        string
+		kueue       *KueueConfig
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "valid topology referenced by flavor",
+			kueue: &KueueConfig{
+				Topologies: []KueueTopology{
+					{Name: "dc", Levels: []string{"topology.kubernetes.io/zone", "kubernetes.io/hostname"}},
+				},
+				ResourceFlavors: []ResourceFlavor{
+					{Name: "tas", NodeLabels: map[string]string{"pool": "gpu"}, TopologyName: "dc"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "topology without name",
+			kueue: &KueueConfig{
+				Topologies: []KueueTopology{{Levels: []string{"kubernetes.io/hostname"}}},
+			},
+			wantErr:     true,
+			errContains: "topology[0]: name is required",
+		},
+		{
+			name: "duplicate topology",
+			kueue: &KueueConfig{
+				Topologies: []KueueTopology{
+					{Name: "dc", Levels: []string{"kubernetes.io/hostname"}},
+					{Name: "dc", Levels: []string{"kubernetes.io/hostname"}},
+				},
+			},
+			wantErr:     true,
+			errContains: "duplicate topology name 'dc'",
+		},
+		{
+			name: "topology without levels",
+			kueue: &KueueConfig{
+				Topologies: []KueueTopology{{Name: "dc"}},
+			},
+			wantErr:     true,
+			errContains: "must have between 1 and 16 levels",
+		},
+		{
+			name: "duplicate level",
+			kueue: &KueueConfig{
+				Topologies: []KueueTopology{
+					{Name: "dc", Levels: []string{"kubernetes.io/hostname", "kubernetes.io/hostname"}},
+				},
+			},
+			wantErr:     true,
+			errContains: "level[1]: duplicate level 'kubernetes.io/hostname'",
+		},
+		{
+			name: "invalid level label",
+			kueue: &KueueConfig{
+				Topologies: []KueueTopology{{Name: "dc", Levels: []string{"not a label"}}},
+			},
+			wantErr:     true,
+			errContains: "invalid node label 'not a label'",
+		},
+		{
+			name: "flavor references unknown topology",
+			kueue: &KueueConfig{
+				ResourceFlavors: []ResourceFlavor{
+					{Name: "tas", NodeLabels: map[string]string{"pool": "gpu"}, TopologyName: "missing"},
+				},
+			},
+			wantErr:     true,
+			errContains: "unknown topology 'missing'",
+		},
+		{
+			name: "topology flavor without node labels",
+			kueue: &KueueConfig{
+				Topologies: []KueueTopology{{Name: "dc", Levels: []string{"kubernetes.io/hostname"}}},
+				ResourceFlavors: []ResourceFlavor{
+					{Name: "tas", TopologyName: "dc"},
+				},
+			},
+			wantErr:     true,
+			errContains: "nodeLabels are required when topologyName is set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateKueueConfig(tt.kueue, 0, "test-cluster")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateKueueConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("validateKueueConfig() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateLocalQueuesAndNamespaces(t *testing.T) {
+	withQueues := func(queues ...LocalQueue) *KueueConfig {
+		return &KueueConfig{
+			ResourceFlavors: []ResourceFlavor{{Name: "default"}},
+			ClusterQueues: []ClusterQueue{{
+				Name: "cq",
+				ResourceGroups: []ResourceGroup{{
+					CoveredResources: []string{"cpu"},
+					Flavors:          []FlavorQuotas{{Name: "default", Resources: []Resource{{Name: "cpu", NominalQuota: "10"}}}},
+				}},
+			}},
+			LocalQueues: queues,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		kueue       *KueueConfig
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "stop policy and fair sharing",
+			kueue: withQueues(
+				LocalQueue{Name: "lq-a", Namespace: "team-a", ClusterQueue: "cq", StopPolicy: StopPolicyHoldAndDrain},
+				LocalQueue{Name: "lq-b", Namespace: "team-b", ClusterQueue: "cq", FairSharing: &FairSharing{Weight: "2"}},
+			),
+			wantErr: false,
+		},
+		{
+			name:        "invalid stop policy",
+			kueue:       withQueues(LocalQueue{Name: "lq", Namespace: "team-a", ClusterQueue: "cq", StopPolicy: "Pause"}),
+			wantErr:     true,
+			errContains: "localQueue[0] (lq): invalid stopPolicy 'Pause'",
+		},
+		{
+			name: "labeled namespaces",
+			kueue: &KueueConfig{Namespaces: []Namespace{
+				{Name: "team-a", Labels: map[string]string{"kueue-bench.io/team": "a"}},
+				{Name: "default", Labels: map[string]string{"shared": "true"}},
+			}},
+			wantErr: false,
+		},
+		{
+			name:        "invalid namespace name",
+			kueue:       &KueueConfig{Namespaces: []Namespace{{Name: "Team_A"}}},
+			wantErr:     true,
+			errContains: "namespace[0]: invalid name 'Team_A'",
+		},
+		{
+			name:        "duplicate namespace",
+			kueue:       &KueueConfig{Namespaces: []Namespace{{Name: "team-a"}, {Name: "team-a"}}},
+			wantErr:     true,
+			errContains: "namespace[1]: duplicate name 'team-a'",
+		},
+		{
+			name:        "invalid namespace label",
+			kueue:       &KueueConfig{Namespaces: []Namespace{{Name: "team-a", Labels: map[string]string{"team": "a b"}}}},
+			wantErr:     true,
+			errContains: "namespace[0] (team-a): invalid label value 'a b'",
+		},
+		{
+			name: "namespace fixtures and pod priority classes",
+			kueue: &KueueConfig{
+				Namespaces: []Namespace{{
+					Name:          "team-a",
+					ResourceQuota: map[string]string{"requests.cpu": "64", "count/jobs.batch": "100"},
+					LimitRange:    &LimitRange{Min: map[string]string{"cpu": "100m"}, Max: map[string]string{"cpu": "8"}},
+				}},
+				PodPriorityClasses: []PodPriorityClass{
+					{Name: "batch", Value: 100, GlobalDefault: true},
+					{Name: "serving", Value: 1000, PreemptionPolicy: PreemptNever},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "invalid resource quota quantity",
+			kueue:       &KueueConfig{Namespaces: []Namespace{{Name: "team-a", ResourceQuota: map[string]string{"pods": "lots"}}}},
+			wantErr:     true,
+			errContains: "namespace[0] (team-a): resourceQuota: invalid pods",
+		},
+		{
+			name: "limit range min above max",
+			kueue: &KueueConfig{Namespaces: []Namespace{{
+				Name:       "team-a",
+				LimitRange: &LimitRange{Min: map[string]string{"cpu": "2"}, Max: map[string]string{"cpu": "1"}},
+			}}},
+			wantErr:     true,
+			errContains: "limitRange: min cpu 2 exceeds max 1",
+		},
+		{
+			name:        "reserved pod priority class name",
+			kueue:       &KueueConfig{PodPriorityClasses: []PodPriorityClass{{Name: "system-high", Value: 10}}},
+			wantErr:     true,
+			errContains: "podPriorityClass[0] (system-high): the system- prefix is reserved",
+		},
+		{
+			name:        "pod priority above user range",
+			kueue:       &KueueConfig{PodPriorityClasses: []PodPriorityClass{{Name: "critical", Value: 2000000000}}},
+			wantErr:     true,
+			errContains: "value 2000000000 exceeds 1000000000",
+		},
+		{
+			name: "two global default pod priority classes",
+			kueue: &KueueConfig{PodPriorityClasses: []PodPriorityClass{
+				{Name: "a", Value: 1, GlobalDefault: true},
+				{Name: "b", Value: 2, GlobalDefault: true},
+			}},
+			wantErr:     true,
+			errContains: "only one class can be globalDefault",
+		},
+		{
+			name: "generated pod priority class",
+			kueue: &KueueConfig{
+				PriorityClasses:    []WorkloadPriorityClass{{Name: "high", Value: 1000, PodPriorityClass: true}},
+				PodPriorityClasses: []PodPriorityClass{{Name: "low", Value: 10}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "generated pod priority class already defined",
+			kueue: &KueueConfig{
+				PriorityClasses:    []WorkloadPriorityClass{{Name: "high", Value: 1000, PodPriorityClass: true}},
+				PodPriorityClasses: []PodPriorityClass{{Name: "high", Value: 10}},
+			},
+			wantErr:     true,
+			errContains: "priorityClass[0] (high): podPriorityClass is set, but a PriorityClass 'high' is already defined",
+		},
+		{
+			name:        "invalid pod preemption policy",
+			kueue:       &KueueConfig{PodPriorityClasses: []PodPriorityClass{{Name: "a", Value: 1, PreemptionPolicy: "Always"}}},
+			wantErr:     true,
+			errContains: "invalid preemptionPolicy 'Always'",
+		},
+		{
+			name:        "negative fair sharing weight",
+			kueue:       withQueues(LocalQueue{Name: "lq", Namespace: "team-a", ClusterQueue: "cq", FairSharing: &FairSharing{Weight: "-1"}}),
+			wantErr:     true,
+			errContains: "localQueue[0] (lq): fairSharing: weight must be >= 0",
+		},
+		{
+			name: "local queue generator",
+			kueue: func() *KueueConfig {
+				k := withQueues()
+				k.LocalQueueGenerators = []LocalQueueGenerator{{ClusterQueue: "cq", Namespaces: 20, QueuesPerNamespace: 5}}
+				return k
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "local queue generator with unknown cluster queue",
+			kueue: func() *KueueConfig {
+				k := withQueues()
+				k.LocalQueueGenerators = []LocalQueueGenerator{{ClusterQueue: "other", Namespaces: 2, QueuesPerNamespace: 2}}
+				return k
+			}(),
+			wantErr:     true,
+			errContains: "localQueueGenerator[0] (other): unknown clusterQueue 'other'",
+		},
+		{
+			name: "local queue generator pattern without index",
+			kueue: func() *KueueConfig {
+				k := withQueues()
+				k.LocalQueueGenerators = []LocalQueueGenerator{{ClusterQueue: "cq", Namespaces: 2, QueuesPerNamespace: 3, QueuePattern: "queue"}}
+				return k
+			}(),
+			wantErr:     true,
+			errContains: "queuePattern 'queue' must contain {lq}",
+		},
+		{
+			name: "local queue generator with too many queues",
+			kueue: func() *KueueConfig {
+				k := withQueues()
+				k.LocalQueueGenerators = []LocalQueueGenerator{{ClusterQueue: "cq", Namespaces: 100000000, QueuesPerNamespace: 1}}
+				return k
+			}(),
+			wantErr:     true,
+			errContains: "namespaces × queuesPerNamespace (100000000 × 1) exceeds the limit of 100000 generated LocalQueues",
+		},
+		{
+			name: "local queue generator at the queue limit",
+			kueue: func() *KueueConfig {
+				k := withQueues()
+				k.LocalQueueGenerators = []LocalQueueGenerator{{ClusterQueue: "cq", Namespaces: 1000, QueuesPerNamespace: 100}}
+				return k
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "local queue generator with invalid namespace names",
+			kueue: func() *KueueConfig {
+				k := withQueues()
+				k.LocalQueueGenerators = []LocalQueueGenerator{{ClusterQueue: "cq", Namespaces: 2, QueuesPerNamespace: 1, NamespacePattern: "Team_{ns}"}}
+				return k
+			}(),
+			wantErr:     true,
+			errContains: "generated namespace 'Team_0' is invalid",
+		},
+		{
+			name: "local queue generator overlapping a local queue",
+			kueue: func() *KueueConfig {
+				k := withQueues(LocalQueue{Name: "cq-lq-1", Namespace: "cq-ns-0", ClusterQueue: "cq"})
+				k.LocalQueueGenerators = []LocalQueueGenerator{{ClusterQueue: "cq", Namespaces: 2, QueuesPerNamespace: 2}}
+				return k
+			}(),
+			wantErr:     true,
+			errContains: "generated LocalQueue cq-ns-0/cq-lq-1 is already defined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateKueueConfig(tt.kueue, 0, "test-cluster")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateKueueConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("validateKueueConfig() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAdmissionChecks(t *testing.T) {
+	negative := int32(-1)
+	tests := []struct {
+		name        string
+		kueue       *KueueConfig
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "provisioning request check referenced by cluster queue",
+			kueue: &KueueConfig{
+				ProvisioningRequestConfigs: []ProvisioningRequestConfig{
+					{Name: "prov", ProvisioningClassName: "check-capacity.autoscaling.x-k8s.io", ManagedResources: []string{"nvidia.com/gpu"}},
+				},
+				AdmissionChecks: []AdmissionCheck{
+					{Name: "prov", ControllerName: "kueue.x-k8s.io/provisioning-request",
+						Parameters: &AdmissionCheckParameters{Kind: "ProvisioningRequestConfig", Name: "prov"}},
+					{Name: "custom", ControllerName: "example.com/check"},
+				},
+				ResourceFlavors: []ResourceFlavor{{Name: "default"}},
+				ClusterQueues: []ClusterQueue{{
+					Name:            "cq",
+					AdmissionChecks: []string{"prov", "custom"},
+					ResourceGroups: []ResourceGroup{{
+						CoveredResources: []string{"cpu"},
+						Flavors:          []FlavorQuotas{{Name: "default", Resources: []Resource{{Name: "cpu", NominalQuota: "10"}}}},
+					}},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parameters outside the kueue API group are not checked",
+			kueue: &KueueConfig{
+				AdmissionChecks: []AdmissionCheck{{Name: "ac", ControllerName: "example.com/check",
+					Parameters: &AdmissionCheckParameters{APIGroup: "example.com", Kind: "ProvisioningRequestConfig", Name: "external"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "admission check without controller name",
+			kueue: &KueueConfig{
+				AdmissionChecks: []AdmissionCheck{{Name: "ac"}},
+			},
+			wantErr:     true,
+			errContains: "admissionCheck[0] (ac): controllerName is required",
+		},
+		{
+			name: "duplicate admission check",
+			kueue: &KueueConfig{
+				AdmissionChecks: []AdmissionCheck{
+					{Name: "ac", ControllerName: "example.com/check"},
+					{Name: "ac", ControllerName: "example.com/check"},
+				},
+			},
+			wantErr:     true,
+			errContains: "admissionCheck[1]: duplicate name 'ac'",
+		},
+		{
+			name: "parameters without name",
+			kueue: &KueueConfig{
+				AdmissionChecks: []AdmissionCheck{{Name: "ac", ControllerName: "example.com/check",
+					Parameters: &AdmissionCheckParameters{Kind: "ProvisioningRequestConfig"}}},
+			},
+			wantErr:     true,
+			errContains: "parameters: kind and name are required",
+		},
+		{
+			name: "unknown provisioning request config",
+			kueue: &KueueConfig{
+				AdmissionChecks: []AdmissionCheck{{Name: "ac", ControllerName: "kueue.x-k8s.io/provisioning-request",
+					Parameters: &AdmissionCheckParameters{Kind: "ProvisioningRequestConfig", Name: "missing"}}},
+			},
+			wantErr:     true,
+			errContains: "unknown provisioningRequestConfig 'missing'",
+		},
+		{
+			name: "provisioning request config without class",
+			kueue: &KueueConfig{
+				ProvisioningRequestConfigs: []ProvisioningRequestConfig{{Name: "prov"}},
+			},
+			wantErr:     true,
+			errContains: "provisioningRequestConfig[0] (prov): provisioningClassName is required",
+		},
+		{
+			name: "negative retry backoff",
+			kueue: &KueueConfig{
+				ProvisioningRequestConfigs: []ProvisioningRequestConfig{{
+					Name:                  "prov",
+					ProvisioningClassName: "queued-provisioning.gke.io",
+					RetryStrategy:         &ProvisioningRetryStrategy{BackoffBaseSeconds: &negative},
+				}},
+			},
+			wantErr:     true,
+			errContains: "retryStrategy.backoffBaseSeconds must be >= 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateKueueConfig(tt.kueue, 0, "test-cluster")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateKueueConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("validateKueueConfig() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateKwokStages(t *testing.T) {
+	const podReady = `apiVersion: kwok.x-k8s.io/v1alpha1
+kind: Stage
+metadata:
+  name: pod-ready
+spec: {}`
+	const podSlow = `apiVersion: kwok.x-k8s.io/v1alpha1
+kind: Stage
+metadata:
+  name: pod-slow
+spec: {}`
+
+	dir := t.TempDir()
+	stageFile := filepath.Join(dir, "stages.yaml")
+	if err := os.WriteFile(stageFile, []byte(podReady+"\n---\n"+podSlow+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		stages      []KwokStage
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:    "inline stage",
+			stages:  []KwokStage{{Inline: podReady}},
+			wantErr: false,
+		},
+		{
+			name:    "multi-document file",
+			stages:  []KwokStage{{File: stageFile}},
+			wantErr: false,
+		},
+		{
+			name:        "neither inline nor file",
+			stages:      []KwokStage{{}},
+			wantErr:     true,
+			errContains: "exactly one of 'inline' or 'file' is required",
+		},
+		{
+			name:        "both inline and file",
+			stages:      []KwokStage{{Inline: podReady, File: stageFile}},
+			wantErr:     true,
+			errContains: "exactly one of 'inline' or 'file' is required",
+		},
+		{
+			name:        "missing file",
+			stages:      []KwokStage{{File: filepath.Join(dir, "missing.yaml")}},
+			wantErr:     true,
+			errContains: "failed to read stage file",
+		},
+		{
+			name:        "wrong kind",
+			stages:      []KwokStage{{Inline: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: x\n"}},
+			wantErr:     true,
+			errContains: "expected kwok.x-k8s.io/v1alpha1 Stage",
+		},
+		{
+			name:        "missing name",
+			stages:      []KwokStage{{Inline: "apiVersion: kwok.x-k8s.io/v1alpha1\nkind: Stage\n"}},
+			wantErr:     true,
+			errContains: "metadata.name is required",
+		},
+		{
+			name:        "duplicate stage across sources",
+			stages:      []KwokStage{{File: stageFile}, {Inline: podSlow}},
+			wantErr:     true,
+			errContains: "stage[1]: duplicate stage 'pod-slow'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateKwokStages(tt.stages)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateKwokStages() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("validateKwokStages() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateClusterKwok(t *testing.T) {
+	tests := []struct {
+		name        string
+		kwok        ClusterKwokConfig
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:    "default scheduler",
+			kwok:    ClusterKwokConfig{},
+			wantErr: false,
+		},
+		{
+			name:    "kube-scheduler",
+			kwok:    ClusterKwokConfig{Scheduler: SchedulerKube},
+			wantErr: false,
+		},
+		{
+			name:    "bypass",
+			kwok:    ClusterKwokConfig{Scheduler: SchedulerBypass},
+			wantErr: false,
+		},
+		{
+			name:        "unknown scheduler",
+			kwok:        ClusterKwokConfig{Scheduler: "volcano"},
+			wantErr:     true,
+			errContains: "invalid scheduler 'volcano'",
+		},
+		{
+			name:        "invalid stage",
+			kwok:        ClusterKwokConfig{Scheduler: SchedulerBypass, Stages: []KwokStage{{}}},
+			wantErr:     true,
+			errContains: "stage[0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateClusterKwok(&tt.kwok)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateClusterKwok() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("validateClusterKwok() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+func TestValidatePodStartup(t *testing.T) {
+	tests := []struct {
+		name        string
+		latency     PodStartupLatency
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:    "uniform range",
+			latency: PodStartupLatency{Min: "5s", Max: "30s"},
+			wantErr: false,
+		},
+		{
+			name: "label overrides",
+			latency: PodStartupLatency{
+				Min: "5s",
+				Overrides: []PodStartupOverride{
+					{Label: "accelerator=gpu", Min: "1m", Max: "2m"},
+					{Label: "example.com/size=large", Max: "45s"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:        "invalid duration",
+			latency:     PodStartupLatency{Min: "five"},
+			wantErr:     true,
+			errContains: `invalid min "five"`,
+		},
+		{
+			name:        "negative duration",
+			latency:     PodStartupLatency{Max: "-1s"},
+			wantErr:     true,
+			errContains: `invalid max "-1s"`,
+		},
+		{
+			name:        "max below min",
+			latency:     PodStartupLatency{Min: "30s", Max: "5s"},
+			wantErr:     true,
+			errContains: "max 5s must be >= min 30s",
+		},
+		{
+			name:        "override label without value",
+			latency:     PodStartupLatency{Overrides: []PodStartupOverride{{Label: "gpu", Min: "1m"}}},
+			wantErr:     true,
+			errContains: "overrides[0]: label must be key=value",
+		},
+		{
+			name:        "override invalid label key",
+			latency:     PodStartupLatency{Overrides: []PodStartupOverride{{Label: "bad key=x", Min: "1m"}}},
+			wantErr:     true,
+			errContains: "overrides[0]: invalid label key 'bad key'",
+		},
+		{
+			name: "duplicate override label",
+			latency: PodStartupLatency{Overrides: []PodStartupOverride{
+				{Label: "accelerator=gpu", Min: "1m"},
+				{Label: "accelerator=gpu", Min: "2m"},
+			}},
+			wantErr:     true,
+			errContains: "overrides[1]: duplicate label 'accelerator=gpu'",
+		},
+		{
+			name:        "override without delay",
+			latency:     PodStartupLatency{Overrides: []PodStartupOverride{{Label: "accelerator=gpu"}}},
+			wantErr:     true,
+			errContains: "min or max is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePodStartup(&tt.latency)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validatePodStartup() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("validatePodStartup() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateKueueSettings(t *testing.T) {
+	dir := t.TempDir()
+	manifestFile := filepath.Join(dir, "manifests.yaml")
+	if err := os.WriteFile(manifestFile, []byte("apiVersion: v1\nkind: Namespace\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	checkout := filepath.Join(dir, "kueue")
+	if err := os.MkdirAll(filepath.Join(checkout, KueueChartDir), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		settings    KueueSettings
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:     "registry chart",
+			settings: KueueSettings{Version: "0.17.0", HelmValues: map[string]interface{}{"a": 1}},
+			wantErr:  false,
+		},
+		{
+			name:     "local chart with values",
+			settings: KueueSettings{Chart: dir, HelmValues: map[string]interface{}{"a": 1}},
+			wantErr:  false,
+		},
+		{
+			name:     "manifest",
+			settings: KueueSettings{Manifest: manifestFile},
+			wantErr:  false,
+		},
+		{
+			name:     "build from checkout",
+			settings: KueueSettings{BuildFrom: &KueueBuild{Path: checkout, ImageTag: "dev"}},
+			wantErr:  false,
+		},
+		{
+			name:        "chart and manifest",
+			settings:    KueueSettings{Chart: dir, Manifest: manifestFile},
+			wantErr:     true,
+			errContains: "chart, manifest and buildFrom are mutually exclusive",
+		},
+		{
+			name:        "chart and buildFrom",
+			settings:    KueueSettings{Chart: dir, BuildFrom: &KueueBuild{Path: checkout}},
+			wantErr:     true,
+			errContains: "chart, manifest and buildFrom are mutually exclusive",
+		},
+		{
+			name:        "version with local chart",
+			settings:    KueueSettings{Version: "0.17.0", Chart: dir},
+			wantErr:     true,
+			errContains: "version cannot be used with chart, manifest or buildFrom",
+		},
+		{
+			name:        "buildFrom invalid tag",
+			settings:    KueueSettings{BuildFrom: &KueueBuild{Path: checkout, ImageTag: "dev:latest"}},
+			wantErr:     true,
+			errContains: "invalid imageTag 'dev:latest'",
+		},
+		{
+			name:        "helm values with manifest",
+			settings:    KueueSettings{Manifest: manifestFile, HelmValues: map[string]interface{}{"a": 1}},
+			wantErr:     true,
+			errContains: "helmValues, config and featureGates cannot be used with manifest",
+		},
+		{
+			name: "manager config",
+			settings: KueueSettings{Config: &KueueManagerConfig{
+				WaitForPodsReady: &WaitForPodsReadyConfig{Enable: true, Timeout: "2m"},
+				FairSharing:      &FairSharingConfig{Enable: true, PreemptionStrategies: []string{PreemptionLessThanOrEqualToFinalShare}},
+			}},
+			wantErr: false,
+		},
+		{
+			name:        "manager config with manifest",
+			settings:    KueueSettings{Manifest: manifestFile, Config: &KueueManagerConfig{}},
+			wantErr:     true,
+			errContains: "helmValues, config and featureGates cannot be used with manifest",
+		},
+		{
+			name:        "invalid waitForPodsReady timeout",
+			settings:    KueueSettings{Config: &KueueManagerConfig{WaitForPodsReady: &WaitForPodsReadyConfig{Enable: true, Timeout: "soon"}}},
+			wantErr:     true,
+			errContains: "kueue.config: waitForPodsReady.timeout: invalid duration 'soon'",
+		},
+		{
+			name:        "invalid multiKueue workerLostTimeout",
+			settings:    KueueSettings{Config: &KueueManagerConfig{MultiKueue: &MultiKueueConfig{WorkerLostTimeout: "-1m"}}},
+			wantErr:     true,
+			errContains: "kueue.config: multiKueue.workerLostTimeout: invalid duration '-1m'",
+		},
+		{
+			name:        "invalid preemption strategy",
+			settings:    KueueSettings{Config: &KueueManagerConfig{FairSharing: &FairSharingConfig{Enable: true, PreemptionStrategies: []string{"Always"}}}},
+			wantErr:     true,
+			errContains: "invalid preemption strategy 'Always'",
+		},
+		{
+			name:        "preemption strategies out of order",
+			settings:    KueueSettings{Config: &KueueManagerConfig{FairSharing: &FairSharingConfig{Enable: true, PreemptionStrategies: []string{PreemptionLessThanInitialShare, PreemptionLessThanOrEqualToFinalShare}}}},
+			wantErr:     true,
+			errContains: "preemptionStrategies must be [LessThanOrEqualToFinalShare, LessThanInitialShare] when both are used",
+		},
+		{
+			name:        "negative client qps",
+			settings:    KueueSettings{Config: &KueueManagerConfig{ClientConnection: &ClientConnectionConfig{QPS: -1}}},
+			wantErr:     true,
+			errContains: "clientConnection: qps and burst must be >= 0",
+		},
+		{
+			name:     "integrations",
+			settings: KueueSettings{Config: &KueueManagerConfig{Integrations: &IntegrationsConfig{Frameworks: []string{FrameworkBatchJob, FrameworkPyTorchJob, FrameworkSparkApp}}}},
+			wantErr:  false,
+		},
+		{
+			name:        "unknown framework",
+			settings:    KueueSettings{Config: &KueueManagerConfig{Integrations: &IntegrationsConfig{Frameworks: []string{"batch/jobs"}}}},
+			wantErr:     true,
+			errContains: "integrations: unknown framework 'batch/jobs'",
+		},
+		{
+			name:     "feature gates",
+			settings: KueueSettings{FeatureGates: map[string]bool{"TopologyAwareScheduling": true, "PartialAdmission": false}},
+			wantErr:  false,
+		},
+		{
+			name:        "invalid feature gate name",
+			settings:    KueueSettings{FeatureGates: map[string]bool{"partial-admission": true}},
+			wantErr:     true,
+			errContains: "kueue.featureGates: invalid feature gate name 'partial-admission'",
+		},
+		{
+			name:        "feature gates with manifest",
+			settings:    KueueSettings{Manifest: manifestFile, FeatureGates: map[string]bool{"PartialAdmission": true}},
+			wantErr:     true,
+			errContains: "helmValues, config and featureGates cannot be used with manifest",
+		},
+		{
+			name:     "missing chart is left to CheckKueueSource",
+			settings: KueueSettings{Chart: filepath.Join(dir, "missing")},
+			wantErr:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateKueueSettings(&tt.settings)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateKueueSettings() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("validateKueueSettings() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckKueueSource(t *testing.T) {
+	dir := t.TempDir()
+	manifestFile := filepath.Join(dir, "manifests.yaml")
+	if err := os.WriteFile(manifestFile, []byte("apiVersion: v1\nkind: Namespace\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	checkout := filepath.Join(dir, "kueue")
+	if err := os.MkdirAll(filepath.Join(checkout, KueueChartDir), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		settings    *KueueSettings
+		errContains string
+	}{
+		{name: "no kueue settings", settings: nil},
+		{name: "registry chart", settings: &KueueSettings{Version: "0.17.0"}},
+		{name: "local chart", settings: &KueueSettings{Chart: dir}},
+		{name: "manifest", settings: &KueueSettings{Manifest: manifestFile}},
+		{name: "build from checkout", settings: &KueueSettings{BuildFrom: &KueueBuild{Path: checkout}}},
+		{
+			name:        "missing chart",
+			settings:    &KueueSettings{Chart: filepath.Join(dir, "missing")},
+			errContains: "kueue: chart:",
+		},
+		{
+			name:        "missing manifest",
+			settings:    &KueueSettings{Manifest: filepath.Join(dir, "missing.yaml")},
+			errContains: "kueue: manifest:",
+		},
+		{
+			name:        "buildFrom without chart",
+			settings:    &KueueSettings{BuildFrom: &KueueBuild{Path: dir}},
+			errContains: "is not a Kueue checkout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckKueueSource(tt.settings)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("CheckKueueSource() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("CheckKueueSource() error = %v, expected to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestValidateKwokSettings(t *testing.T) {
+	const digest = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	tests := []struct {
+		name        string
+		settings    KwokSettings
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:     "offline",
+			settings: KwokSettings{Offline: true},
+			wantErr:  false,
+		},
+		{
+			name:     "pinned checksum",
+			settings: KwokSettings{Version: "v0.7.0", ManifestSHA256: digest},
+			wantErr:  false,
+		},
+		{
+			name:        "malformed checksum",
+			settings:    KwokSettings{ManifestSHA256: "sha256:" + digest},
 			wantErr:     true,
-			errContains: "helm.chart is required",
+			errContains: "manifestSHA256 must be 64 lowercase hex characters",
 		},
 		{
-			name: "manifest missing url",
-			extensions: []Extension{
-				{Name: "no-url", Manifest: &ManifestExtension{}},
-			},
+			name:     "heartbeat preset with override",
+			settings: KwokSettings{Heartbeat: &HeartbeatSettings{Mode: HeartbeatModeReduced, StatusInterval: "1h"}},
+			wantErr:  false,
+		},
+		{
+			name:        "invalid heartbeat mode",
+			settings:    KwokSettings{Heartbeat: &HeartbeatSettings{Mode: "fast"}},
 			wantErr:     true,
-			errContains: "manifest.url is required",
+			errContains: "kwok.heartbeat: invalid mode 'fast'",
 		},
 		{
-			name: "manifest non-http url",
-			extensions: []Extension{
-				{Name: "bad-url", Manifest: &ManifestExtension{URL: "ftp://example.com/crds.yaml"}},
-			},
+			name:        "fractional lease duration",
+			settings:    KwokSettings{Heartbeat: &HeartbeatSettings{LeaseDuration: "10500ms"}},
 			wantErr:     true,
-			errContains: "manifest.url must start with http:// or https://",
+			errContains: "must be a whole number of seconds",
+		},
+		{
+			name:        "lease duration too short",
+			settings:    KwokSettings{Heartbeat: &HeartbeatSettings{LeaseDuration: "2s"}},
+			wantErr:     true,
+			errContains: "at least 4s",
+		},
+		{
+			name:        "invalid status interval",
+			settings:    KwokSettings{Heartbeat: &HeartbeatSettings{StatusInterval: "0s"}},
+			wantErr:     true,
+			errContains: "invalid statusInterval",
+		},
+		{
+			name:        "checksum with offline",
+			settings:    KwokSettings{Offline: true, ManifestSHA256: digest},
+			wantErr:     true,
+			errContains: "manifestSHA256 cannot be used with offline",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateExtensions(tt.extensions, 0, "test-cluster")
+			err := validateKwokSettings(&tt.settings)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("validateExtensions() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("validateKwokSettings() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if tt.wantErr && tt.errContains != "" {
 				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
-					t.Errorf("validateExtensions() error = %v, expected to contain %q", err, tt.errContains)
+					t.Errorf("validateKwokSettings() error = %v, expected to contain %q", err, tt.errContains)
 				}
 			}
 		})
 	}
 }
 
-func TestValidateMultiKueueTopology(t *testing.T) {
+func TestValidateClientSettings(t *testing.T) {
 	tests := []struct {
 		name        string
-		topo        *Topology
+		settings    ClientSettings
 		wantErr     bool
 		errContains string
 	}{
 		{
-			name: "valid: workerSet with management cluster",
-			topo: &Topology{
-				APIVersion: "kueue-bench.io/v1alpha1",
-				Kind:       "Topology",
-				Metadata:   Metadata{Name: "test"},
-				Spec: TopologySpec{
-					Clusters: []ClusterConfig{
-						{
-							Name: "management",
-							Role: "management",
-							NodePools: []NodePool{
-								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
-							},
-						},
-					},
-					WorkerSets: []WorkerSet{
-						{
-							Name: "workers",
-							ResourceFlavors: []WorkerSetFlavor{
-								{Name: "default", NodePoolRef: "pool"},
-							},
-							ClusterQueues: []WorkerSetClusterQueue{
-								{
-									Name: "cq",
-									ResourceGroups: []WorkerSetResourceGroup{
-										{
-											CoveredResources: []string{"cpu"},
-											Flavors:          []WorkerSetFlavorRef{{Name: "default"}},
-										},
-									},
-								},
-							},
-							Workers: []Worker{
-								{
-									Name: "worker-1",
-									NodePools: []NodePool{
-										{Name: "pool", Count: 1, Resources: map[string]string{"cpu": "1"}},
-									},
-								},
-							},
-						},
-					},
-				},
+			name:     "qps, burst and timeout",
+			settings: ClientSettings{QPS: 200, Burst: 400, Timeout: "30s"},
+			wantErr:  false,
+		},
+		{
+			name:        "negative qps",
+			settings:    ClientSettings{QPS: -1},
+			wantErr:     true,
+			errContains: "client: qps must be non-negative",
+		},
+		{
+			name:        "negative burst",
+			settings:    ClientSettings{Burst: -1},
+			wantErr:     true,
+			errContains: "client: burst must be non-negative",
+		},
+		{
+			name:        "invalid timeout",
+			settings:    ClientSettings{Timeout: "soon"},
+			wantErr:     true,
+			errContains: "client: invalid timeout 'soon'",
+		},
+		{
+			name:        "zero timeout",
+			settings:    ClientSettings{Timeout: "0s"},
+			wantErr:     true,
+			errContains: "client: timeout must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateClientSettings(&tt.settings)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateClientSettings() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("validateClientSettings() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateNodeNames(t *testing.T) {
+	tests := []struct {
+		name        string
+		pools       []NodePool
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "default names",
+			pools: []NodePool{
+				{Name: "cpu", Count: 2},
+				{Name: "gpu", Count: 2},
 			},
 			wantErr: false,
 		},
 		{
-			name: "invalid: workerSet without management cluster",
-			topo: &Topology{
-				APIVersion: "kueue-bench.io/v1alpha1",
-				Kind:       "Topology",
-				Metadata:   Metadata{Name: "test"},
-				Spec: TopologySpec{
-					Clusters: []ClusterConfig{
-						{
-							Name: "standalone",
-							Role: "standalone",
-							NodePools: []NodePool{
-								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
-							},
-						},
-					},
-					WorkerSets: []WorkerSet{
-						{
-							Name: "workers",
-							ResourceFlavors: []WorkerSetFlavor{
-								{Name: "default", NodePoolRef: "pool"},
-							},
-							ClusterQueues: []WorkerSetClusterQueue{
-								{
-									Name: "cq",
-									ResourceGroups: []WorkerSetResourceGroup{
-										{
-											CoveredResources: []string{"cpu"},
-											Flavors:          []WorkerSetFlavorRef{{Name: "default"}},
-										},
-									},
-								},
-							},
-							Workers: []Worker{
-								{
-									Name: "worker-1",
-									NodePools: []NodePool{
-										{Name: "pool", Count: 1, Resources: map[string]string{"cpu": "1"}},
-									},
-								},
-							},
-						},
-					},
-				},
+			name: "templated names",
+			pools: []NodePool{
+				{Name: "cpu", Count: 4, Zones: []string{"a", "b"}, NameTemplate: "{{.Pool}}-{{.Zone}}-{{.Index}}"},
 			},
+			wantErr: false,
+		},
+		{
+			name:        "template without index",
+			pools:       []NodePool{{Name: "cpu", Count: 2, NameTemplate: "node-{{.Pool}}"}},
 			wantErr:     true,
-			errContains: "workerSets require exactly one cluster with role 'management', found 0",
+			errContains: "nameTemplate generates duplicate node name 'node-cpu'",
 		},
 		{
-			name: "invalid: workerSet with multiple management clusters",
-			topo: &Topology{
-				APIVersion: "kueue-bench.io/v1alpha1",
-				Kind:       "Topology",
-				Metadata:   Metadata{Name: "test"},
-				Spec: TopologySpec{
-					Clusters: []ClusterConfig{
-						{
-							Name: "management-1",
-							Role: "management",
-							NodePools: []NodePool{
-								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
-							},
-						},
-						{
-							Name: "management-2",
-							Role: "management",
-							NodePools: []NodePool{
-								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
-							},
-						},
-					},
-					WorkerSets: []WorkerSet{
-						{
-							Name: "workers",
-							ResourceFlavors: []WorkerSetFlavor{
-								{Name: "default", NodePoolRef: "pool"},
-							},
-							ClusterQueues: []WorkerSetClusterQueue{
-								{
-									Name: "cq",
-									ResourceGroups: []WorkerSetResourceGroup{
-										{
-											CoveredResources: []string{"cpu"},
-											Flavors:          []WorkerSetFlavorRef{{Name: "default"}},
-										},
-									},
-								},
-							},
-							Workers: []Worker{
-								{
-									Name: "worker-1",
-									NodePools: []NodePool{
-										{Name: "pool", Count: 1, Resources: map[string]string{"cpu": "1"}},
-									},
-								},
-							},
-						},
-					},
-				},
+			name: "collision across pools",
+			pools: []NodePool{
+				{Name: "cpu", Count: 1, NameTemplate: "node-{{.Index}}"},
+				{Name: "gpu", Count: 1, NameTemplate: "node-{{.Index}}"},
 			},
 			wantErr:     true,
-			errContains: "workerSets require exactly one cluster with role 'management', found 2",
+			errContains: "node name 'node-0' is also generated by pool 'cpu'",
 		},
 		{
-			name: "valid: no workerSets, no management cluster required",
-			topo: &Topology{
-				APIVersion: "kueue-bench.io/v1alpha1",
-				Kind:       "Topology",
-				Metadata:   Metadata{Name: "test"},
-				Spec: TopologySpec{
-					Clusters: []ClusterConfig{
-						{
-							Name: "standalone",
-							Role: "standalone",
-							NodePools: []NodePool{
-								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
-							},
-						},
-					},
-				},
-			},
-			wantErr: false,
+			name:        "invalid rendered name",
+			pools:       []NodePool{{Name: "cpu", Count: 1, NameTemplate: "Node_{{.Index}}"}},
+			wantErr:     true,
+			errContains: "invalid node name 'Node_0'",
 		},
 		{
-			name: "valid: no workerSets, multiple standalone clusters",
-			topo: &Topology{
-				APIVersion: "kueue-bench.io/v1alpha1",
-				Kind:       "Topology",
-				Metadata:   Metadata{Name: "test"},
-				Spec: TopologySpec{
-					Clusters: []ClusterConfig{
-						{
-							Name: "cluster-1",
-							Role: "standalone",
-							NodePools: []NodePool{
-								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
-							},
-						},
-						{
-							Name: "cluster-2",
-							Role: "standalone",
-							NodePools: []NodePool{
-								{Name: "pool1", Count: 1, Resources: map[string]string{"cpu": "1"}},
-							},
-						},
-					},
-				},
+			name:        "unknown template field",
+			pools:       []NodePool{{Name: "cpu", Count: 1, NameTemplate: "{{.Host}}-{{.Index}}"}},
+			wantErr:     true,
+			errContains: "failed to render nameTemplate",
+		},
+		{
+			name: "invalid rendered label value",
+			pools: []NodePool{
+				{Name: "cpu", Count: 1, Labels: map[string]string{"example.com/slot": "{{.Pool}} {{.Index}}"}},
 			},
-			wantErr: false,
+			wantErr:     true,
+			errContains: "label example.com/slot renders invalid value 'cpu 0'",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := ValidateTopology(tt.topo)
+			err := validateNodeNames(tt.pools)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("ValidateTopology() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("validateNodeNames() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if tt.wantErr && tt.errContains != "" {
 				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
-					t.Errorf("ValidateTopology() error = %v, expected to contain %q", err, tt.errContains)
+					t.Errorf("validateNodeNames() error = %v, expected to contain %q", err, tt.errContains)
 				}
 			}
 		})
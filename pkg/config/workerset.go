@@ -2,7 +2,12 @@ package config
 
 import (
 	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
 
+	"gopkg.in/inf.v0"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
@@ -10,21 +15,26 @@ import (
 // ExpandWorkerSets converts WorkerSets into explicit ClusterConfigs.
 // Each worker in each WorkerSet becomes a ClusterConfig with Kueue objects
 // whose values (labels, quotas) are derived from the worker's node pools.
+// External workers become ClusterConfigs with only ExternalKubeconfig set.
+// A worker shared by several WorkerSets becomes a single ClusterConfig holding
+// the merged node pools and Kueue objects of all of them.
 func ExpandWorkerSets(workerSets []WorkerSet) ([]ClusterConfig, error) {
 	var clusters []ClusterConfig
+	index := make(map[string]int)
 
 	for _, ws := range workerSets {
-		// Build flavor name to nodePoolRef lookup
-		flavorPools := make(map[string]string, len(ws.ResourceFlavors))
-		for _, f := range ws.ResourceFlavors {
-			flavorPools[f.Name] = f.NodePoolRef
-		}
-
 		for _, worker := range ws.Workers {
-			cluster, err := expandWorker(ws, worker, flavorPools)
+			cluster, err := expandWorker(ws, worker)
 			if err != nil {
 				return nil, fmt.Errorf("workerSet %s, worker %s: %w", ws.Name, worker.Name, err)
 			}
+			if i, ok := index[worker.Name]; ok {
+				if err := mergeWorkerCluster(&clusters[i], cluster); err != nil {
+					return nil, fmt.Errorf("workerSet %s, worker %s: %w", ws.Name, worker.Name, err)
+				}
+				continue
+			}
+			index[worker.Name] = len(clusters)
 			clusters = append(clusters, cluster)
 		}
 	}
@@ -32,27 +42,43 @@ func ExpandWorkerSets(workerSets []WorkerSet) ([]ClusterConfig, error) {
 	return clusters, nil
 }
 
-func expandWorker(ws WorkerSet, worker Worker, flavorPools map[string]string) (ClusterConfig, error) {
+func expandWorker(ws WorkerSet, worker Worker) (ClusterConfig, error) {
+	if worker.Kubeconfig != "" {
+		return ClusterConfig{Name: worker.Name, Role: RoleWorker, ExternalKubeconfig: worker.Kubeconfig}, nil
+	}
+
 	pools := make(map[string]NodePool, len(worker.NodePools))
 	for _, p := range worker.NodePools {
 		pools[p.Name] = p
 	}
 
-	resourceFlavors, err := deriveResourceFlavors(ws.ResourceFlavors, pools)
+	// Only the flavors this worker provides are created on it and given quota
+	var wsFlavorDefs []WorkerSetFlavor
+	flavorPools := make(map[string]string, len(ws.ResourceFlavors))
+	for _, f := range ws.ResourceFlavors {
+		if f.AppliesTo(worker.Name) {
+			wsFlavorDefs = append(wsFlavorDefs, f)
+			flavorPools[f.Name] = f.NodePoolRef
+		}
+	}
+
+	resourceFlavors, err := deriveResourceFlavors(wsFlavorDefs, pools)
 	if err != nil {
 		return ClusterConfig{}, err
 	}
 
-	clusterQueues, err := deriveClusterQueues(ws.ClusterQueues, flavorPools, pools)
+	clusterQueues, err := deriveClusterQueues(ws.ClusterQueues, flavorPools, pools, worker.Weight())
 	if err != nil {
 		return ClusterConfig{}, err
 	}
 
 	return ClusterConfig{
-		Name:       worker.Name,
-		Role:       RoleWorker,
-		NodePools:  worker.NodePools,
-		Extensions: ws.Extensions,
+		Name:              worker.Name,
+		Role:              RoleWorker,
+		NodePools:         worker.NodePools,
+		Extensions:        ws.Extensions,
+		Kwok:              ws.Kwok,
+		KueueFeatureGates: ws.KueueFeatureGates,
 		Kueue: &KueueConfig{
 			ResourceFlavors: resourceFlavors,
 			ClusterQueues:   clusterQueues,
@@ -61,6 +87,87 @@ func expandWorker(ws WorkerSet, worker Worker, flavorPools map[string]string) (C
 	}, nil
 }
 
+// mergeWorkerCluster merges the expansion of a worker for another WorkerSet into dst.
+// Node pools, ResourceFlavors and LocalQueues defined by both must be identical, and
+// ClusterQueue names must be unique across the WorkerSets.
+func mergeWorkerCluster(dst *ClusterConfig, src ClusterConfig) error {
+	if dst.ExternalKubeconfig != src.ExternalKubeconfig {
+		return fmt.Errorf("kubeconfig differs from another workerSet")
+	}
+	if dst.ExternalKubeconfig != "" {
+		return nil
+	}
+
+	// dst shares slices with the first WorkerSet's spec; clip them so appends copy
+	dst.NodePools = slices.Clip(dst.NodePools)
+	dst.Extensions = slices.Clip(dst.Extensions)
+	dst.Kueue.ResourceFlavors = slices.Clip(dst.Kueue.ResourceFlavors)
+	dst.Kueue.ClusterQueues = slices.Clip(dst.Kueue.ClusterQueues)
+	dst.Kueue.LocalQueues = slices.Clip(dst.Kueue.LocalQueues)
+
+	for _, pool := range src.NodePools {
+		i := slices.IndexFunc(dst.NodePools, func(p NodePool) bool { return p.Name == pool.Name })
+		if i < 0 {
+			dst.NodePools = append(dst.NodePools, pool)
+		} else if !reflect.DeepEqual(dst.NodePools[i], pool) {
+			return fmt.Errorf("nodePool %q differs from another workerSet", pool.Name)
+		}
+	}
+	for _, ext := range src.Extensions {
+		i := slices.IndexFunc(dst.Extensions, func(e Extension) bool { return e.Name == ext.Name })
+		if i < 0 {
+			dst.Extensions = append(dst.Extensions, ext)
+		} else if !reflect.DeepEqual(dst.Extensions[i], ext) {
+			return fmt.Errorf("extension %q differs from another workerSet", ext.Name)
+		}
+	}
+	if src.Kwok != nil {
+		if dst.Kwok != nil && !reflect.DeepEqual(dst.Kwok, src.Kwok) {
+			return fmt.Errorf("kwok settings differ from another workerSet")
+		}
+		dst.Kwok = src.Kwok
+	}
+	if len(src.KueueFeatureGates) > 0 {
+		gates := maps.Clone(dst.KueueFeatureGates)
+		if gates == nil {
+			gates = make(map[string]bool, len(src.KueueFeatureGates))
+		}
+		for gate, enabled := range src.KueueFeatureGates {
+			if v, ok := gates[gate]; ok && v != enabled {
+				return fmt.Errorf("kueueFeatureGate %q differs from another workerSet", gate)
+			}
+			gates[gate] = enabled
+		}
+		dst.KueueFeatureGates = gates
+	}
+
+	for _, rf := range src.Kueue.ResourceFlavors {
+		i := slices.IndexFunc(dst.Kueue.ResourceFlavors, func(f ResourceFlavor) bool { return f.Name == rf.Name })
+		if i < 0 {
+			dst.Kueue.ResourceFlavors = append(dst.Kueue.ResourceFlavors, rf)
+		} else if !reflect.DeepEqual(dst.Kueue.ResourceFlavors[i], rf) {
+			return fmt.Errorf("resourceFlavor %q differs from another workerSet", rf.Name)
+		}
+	}
+	for _, cq := range src.Kueue.ClusterQueues {
+		if slices.ContainsFunc(dst.Kueue.ClusterQueues, func(c ClusterQueue) bool { return c.Name == cq.Name }) {
+			return fmt.Errorf("clusterQueue %q is defined by another workerSet", cq.Name)
+		}
+		dst.Kueue.ClusterQueues = append(dst.Kueue.ClusterQueues, cq)
+	}
+	for _, lq := range src.Kueue.LocalQueues {
+		i := slices.IndexFunc(dst.Kueue.LocalQueues, func(q LocalQueue) bool {
+			return q.Namespace == lq.Namespace && q.Name == lq.Name
+		})
+		if i < 0 {
+			dst.Kueue.LocalQueues = append(dst.Kueue.LocalQueues, lq)
+		} else if !reflect.DeepEqual(dst.Kueue.LocalQueues[i], lq) {
+			return fmt.Errorf("localQueue %s/%s differs from another workerSet", lq.Namespace, lq.Name)
+		}
+	}
+	return nil
+}
+
 func deriveResourceFlavors(wsFlavorDefs []WorkerSetFlavor, pools map[string]NodePool) ([]ResourceFlavor, error) {
 	flavors := make([]ResourceFlavor, 0, len(wsFlavorDefs))
 
@@ -80,11 +187,11 @@ func deriveResourceFlavors(wsFlavorDefs []WorkerSetFlavor, pools map[string]Node
 	return flavors, nil
 }
 
-func deriveClusterQueues(wsCQs []WorkerSetClusterQueue, flavorPools map[string]string, pools map[string]NodePool) ([]ClusterQueue, error) {
+func deriveClusterQueues(wsCQs []WorkerSetClusterQueue, flavorPools map[string]string, pools map[string]NodePool, weight float64) ([]ClusterQueue, error) {
 	cqs := make([]ClusterQueue, 0, len(wsCQs))
 
 	for _, wsCQ := range wsCQs {
-		rgs, err := deriveResourceGroups(wsCQ.ResourceGroups, flavorPools, pools)
+		rgs, err := deriveResourceGroups(wsCQ.ResourceGroups, flavorPools, pools, weight)
 		if err != nil {
 			return nil, fmt.Errorf("clusterQueue %s: %w", wsCQ.Name, err)
 		}
@@ -103,16 +210,17 @@ func deriveClusterQueues(wsCQs []WorkerSetClusterQueue, flavorPools map[string]s
 	return cqs, nil
 }
 
-func deriveResourceGroups(wsRGs []WorkerSetResourceGroup, flavorPools map[string]string, pools map[string]NodePool) ([]ResourceGroup, error) {
+func deriveResourceGroups(wsRGs []WorkerSetResourceGroup, flavorPools map[string]string, pools map[string]NodePool, weight float64) ([]ResourceGroup, error) {
 	rgs := make([]ResourceGroup, 0, len(wsRGs))
 
 	for _, wsRG := range wsRGs {
 		flavors := make([]FlavorQuotas, 0, len(wsRG.Flavors))
 
 		for _, flavorRef := range wsRG.Flavors {
+			// Flavors limited to other workers are left out
 			poolName, ok := flavorPools[flavorRef.Name]
 			if !ok {
-				return nil, fmt.Errorf("flavor %q not defined in workerSet resourceFlavors", flavorRef.Name)
+				continue
 			}
 
 			pool, ok := pools[poolName]
@@ -120,7 +228,7 @@ func deriveResourceGroups(wsRGs []WorkerSetResourceGroup, flavorPools map[string
 				return nil, fmt.Errorf("nodePoolRef %q (from flavor %q) not found in worker node pools", poolName, flavorRef.Name)
 			}
 
-			resources, err := deriveQuotas(wsRG.CoveredResources, pool)
+			resources, err := deriveQuotas(wsRG.CoveredResources, pool, weight)
 			if err != nil {
 				return nil, err
 			}
@@ -130,6 +238,9 @@ func deriveResourceGroups(wsRGs []WorkerSetResourceGroup, flavorPools map[string
 				Resources: resources,
 			})
 		}
+		if len(flavors) == 0 {
+			return nil, fmt.Errorf("resourceGroup %v has no flavor for this worker", wsRG.CoveredResources)
+		}
 
 		rgs = append(rgs, ResourceGroup{
 			CoveredResources: wsRG.CoveredResources,
@@ -140,12 +251,14 @@ func deriveResourceGroups(wsRGs []WorkerSetResourceGroup, flavorPools map[string
 	return rgs, nil
 }
 
-// deriveQuotas calculates nominalQuota for each covered resource as pool.Count * pool.Resources[resource].
-func deriveQuotas(coveredResources []string, pool NodePool) ([]Resource, error) {
+// deriveQuotas calculates nominalQuota for each covered resource as
+// pool.Count * pool.Capacity()[resource] * weight.
+func deriveQuotas(coveredResources []string, pool NodePool, weight float64) ([]Resource, error) {
 	resources := make([]Resource, 0, len(coveredResources))
+	capacities := pool.Capacity()
 
 	for _, resName := range coveredResources {
-		quantityStr, ok := pool.Resources[resName]
+		quantityStr, ok := capacities[resName]
 		if !ok {
 			return nil, fmt.Errorf("covered resource %q not found in node pool %q resources", resName, pool.Name)
 		}
@@ -161,6 +274,9 @@ func deriveQuotas(coveredResources []string, pool NodePool) ([]Resource, error)
 		for i := 1; i < pool.Count; i++ {
 			total.Add(q)
 		}
+		if weight != 1 {
+			total = scaleQuantity(total, weight)
+		}
 
 		resources = append(resources, Resource{
 			Name:         resName,
@@ -171,6 +287,24 @@ func deriveQuotas(coveredResources []string, pool NodePool) ([]Resource, error)
 	return resources, nil
 }
 
+// scaleQuantity multiplies q by weight, to the nearest milli-unit. Binary quantities such as
+// memory are rounded down to whole units, since fractional bytes are meaningless. The product
+// is computed in decimal arithmetic so that quantities too large for MilliValue don't overflow.
+func scaleQuantity(q resource.Quantity, weight float64) resource.Quantity {
+	w, ok := new(inf.Dec).SetString(strconv.FormatFloat(weight, 'f', -1, 64))
+	if !ok {
+		// Unreachable: validation only admits finite weights, which FormatFloat renders as decimals
+		return q
+	}
+	scaled := new(inf.Dec).Mul(q.AsDec(), w)
+	if q.Format == resource.BinarySI {
+		scaled.Round(scaled, 0, inf.RoundDown)
+	} else {
+		scaled.Round(scaled, 3, inf.RoundHalfUp)
+	}
+	return *resource.NewDecimalQuantity(*scaled, q.Format)
+}
+
 // taintsToTolerations converts node taints to Kubernetes tolerations.
 func taintsToTolerations(taints []Taint) []corev1.Toleration {
 	if len(taints) == 0 {
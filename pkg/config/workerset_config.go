@@ -319,7 +319,7 @@ func TestExpandWorkerSets(t *testing.T) {
 							Preemption: &PreemptionConfig{
 								WithinClusterQueue: "LowerPriority",
 							},
-							FairSharing: &FairSharing{Weight: 2},
+							FairSharing: &FairSharing{Weight: "2"},
 							ResourceGroups: []WorkerSetResourceGroup{
 								{
 									CoveredResources: []string{"nvidia.com/gpu"},
@@ -360,7 +360,7 @@ func TestExpandWorkerSets(t *testing.T) {
 								Preemption: &PreemptionConfig{
 									WithinClusterQueue: "LowerPriority",
 								},
-								FairSharing: &FairSharing{Weight: 2},
+								FairSharing: &FairSharing{Weight: "2"},
 								ResourceGroups: []ResourceGroup{
 									{
 										CoveredResources: []string{"nvidia.com/gpu"},
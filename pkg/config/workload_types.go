@@ -20,6 +20,23 @@ type WorkloadProfileSpec struct {
 	Duration       string         `yaml:"duration"`
 	ArrivalPattern ArrivalPattern `yaml:"arrivalPattern"`
 	Workloads      []WorkloadSpec `yaml:"workloads"`
+	Chaos          *ChaosSpec     `yaml:"chaos,omitempty"`
+}
+
+// ChaosSpec defines disruptions injected into the cluster while workloads are submitted
+type ChaosSpec struct {
+	NodeChurn  *NodeChurn   `yaml:"nodeChurn,omitempty"`
+	NodeFaults []NodeFault  `yaml:"nodeFaults,omitempty"`
+	WorkerLoss []WorkerLoss `yaml:"workerLoss,omitempty"`
+}
+
+// NodeChurn periodically deletes a random fraction of KWOK nodes and recreates them,
+// simulating capacity flapping.
+type NodeChurn struct {
+	Interval      string            `yaml:"interval"`
+	Fraction      float64           `yaml:"fraction"`
+	RecreateAfter string            `yaml:"recreateAfter,omitempty"`
+	NodeSelector  map[string]string `yaml:"nodeSelector,omitempty"`
 }
 
 // ArrivalPattern defines how workloads are submitted over time
@@ -32,13 +49,26 @@ type ArrivalPattern struct {
 // Template holds one of *JobTemplate, *JobSetTemplate, or *RayJobTemplate
 // depending on Type, populated via custom YAML unmarshalling.
 type WorkloadSpec struct {
-	Type          string       `yaml:"type"` // Job, JobSet, RayJob
-	Weight        int          `yaml:"weight"`
-	LocalQueue    string       `yaml:"localQueue,omitempty"`
-	Namespace     string       `yaml:"namespace,omitempty"`
-	PriorityClass string       `yaml:"priorityClass,omitempty"`
-	Tolerations   []Toleration `yaml:"tolerations,omitempty"`
-	Template      interface{}  `yaml:"-"`
+	Type          string `yaml:"type"` // Job, JobSet, RayJob
+	Weight        int    `yaml:"weight"`
+	LocalQueue    string `yaml:"localQueue,omitempty"`
+	Namespace     string `yaml:"namespace,omitempty"`
+	PriorityClass string `yaml:"priorityClass,omitempty"`
+	// PodPriorityClass is the Kubernetes PriorityClass of the workload's pods, which
+	// kube-scheduler preempts by; Kueue orders workloads by PriorityClass if set
+	PodPriorityClass string       `yaml:"podPriorityClass,omitempty"`
+	Tolerations      []Toleration `yaml:"tolerations,omitempty"`
+	// Spread submits each workload to a random one of many generated LocalQueues
+	Spread   *QueueSpread `yaml:"spread,omitempty"`
+	Template interface{}  `yaml:"-"`
+}
+
+// QueueSpread spreads a workload type uniformly over the LocalQueues of a topology's
+// localQueueGenerator. Namespace and LocalQueue are then naming patterns, in which {ns}
+// and {lq} are replaced by a random namespace and queue index, from 0, for each workload.
+type QueueSpread struct {
+	Namespaces         int `yaml:"namespaces"`
+	QueuesPerNamespace int `yaml:"queuesPerNamespace"`
 }
 
 // Toleration represents a Kubernetes pod toleration.
@@ -54,13 +84,15 @@ type Toleration struct {
 // appropriate typed struct.
 func (w *WorkloadSpec) UnmarshalYAML(value *yaml.Node) error {
 	type rawWorkloadSpec struct {
-		Type          string       `yaml:"type"`
-		Weight        int          `yaml:"weight"`
-		LocalQueue    string       `yaml:"localQueue,omitempty"`
-		Namespace     string       `yaml:"namespace,omitempty"`
-		PriorityClass string       `yaml:"priorityClass,omitempty"`
-		Tolerations   []Toleration `yaml:"tolerations,omitempty"`
-		Template      yaml.Node    `yaml:"template"`
+		Type             string       `yaml:"type"`
+		Weight           int          `yaml:"weight"`
+		LocalQueue       string       `yaml:"localQueue,omitempty"`
+		Namespace        string       `yaml:"namespace,omitempty"`
+		PriorityClass    string       `yaml:"priorityClass,omitempty"`
+		PodPriorityClass string       `yaml:"podPriorityClass,omitempty"`
+		Tolerations      []Toleration `yaml:"tolerations,omitempty"`
+		Spread           *QueueSpread `yaml:"spread,omitempty"`
+		Template         yaml.Node    `yaml:"template"`
 	}
 
 	var raw rawWorkloadSpec
@@ -73,7 +105,9 @@ func (w *WorkloadSpec) UnmarshalYAML(value *yaml.Node) error {
 	w.LocalQueue = raw.LocalQueue
 	w.Namespace = raw.Namespace
 	w.PriorityClass = raw.PriorityClass
+	w.PodPriorityClass = raw.PodPriorityClass
 	w.Tolerations = raw.Tolerations
+	w.Spread = raw.Spread
 
 	if raw.Template.Kind == 0 {
 		return nil
@@ -188,3 +222,32 @@ func (d *Distribution) UnmarshalYAML(unmarshal func(interface{}) error) error {
 func (d *Distribution) IsFixed() bool {
 	return d.Value != "" && d.Type == ""
 }
+
+// Node fault types
+const (
+	NodeFaultNotReady = "notReady"
+	NodeFaultCordon   = "cordon"
+	NodeFaultTaint    = "taint"
+)
+
+// NodeFault injects a failure onto a subset of nodes at a fixed offset into the run.
+// Nodes are targeted by pool and/or labels; Count or Fraction limits how many of the
+// matching nodes are affected (all of them when neither is set).
+type NodeFault struct {
+	Type         string            `yaml:"type"` // notReady, cordon, taint
+	At           string            `yaml:"at"`
+	Duration     string            `yaml:"duration,omitempty"`
+	Pool         string            `yaml:"pool,omitempty"`
+	NodeSelector map[string]string `yaml:"nodeSelector,omitempty"`
+	Count        int               `yaml:"count,omitempty"`
+	Fraction     float64           `yaml:"fraction,omitempty"`
+	Taint        *Taint            `yaml:"taint,omitempty"`
+}
+
+// WorkerLoss deletes a MultiKueue worker cluster at a fixed offset into the run, so the
+// management cluster's lost-cluster handling can be measured. Unlike a paused worker, a
+// deleted worker does not come back.
+type WorkerLoss struct {
+	Worker string `yaml:"worker"`
+	At     string `yaml:"at"`
+}
@@ -2,7 +2,10 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // ValidateWorkloadProfile validates a workload profile configuration.
@@ -43,6 +46,121 @@ func ValidateWorkloadProfile(p *WorkloadProfile) error {
 		}
 	}
 
+	if p.Spec.Chaos != nil {
+		if err := validateChaos(p.Spec.Chaos); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateChaos(c *ChaosSpec) error {
+	if c.NodeChurn != nil {
+		if err := ValidateNodeChurn(c.NodeChurn); err != nil {
+			return fmt.Errorf("spec.chaos.nodeChurn: %w", err)
+		}
+	}
+
+	for i := range c.NodeFaults {
+		if err := validateNodeFault(&c.NodeFaults[i]); err != nil {
+			return fmt.Errorf("spec.chaos.nodeFaults[%d]: %w", i, err)
+		}
+	}
+
+	lost := make(map[string]bool, len(c.WorkerLoss))
+	for i, l := range c.WorkerLoss {
+		if l.Worker == "" {
+			return fmt.Errorf("spec.chaos.workerLoss[%d]: worker is required", i)
+		}
+		if lost[l.Worker] {
+			return fmt.Errorf("spec.chaos.workerLoss[%d]: worker %q is already lost", i, l.Worker)
+		}
+		lost[l.Worker] = true
+		if l.At == "" {
+			return fmt.Errorf("spec.chaos.workerLoss[%d]: at is required", i)
+		}
+		if at, err := time.ParseDuration(l.At); err != nil || at < 0 {
+			return fmt.Errorf("spec.chaos.workerLoss[%d]: invalid at %q: must be a non-negative duration", i, l.At)
+		}
+	}
+
+	return nil
+}
+
+func validateNodeFault(f *NodeFault) error {
+	switch f.Type {
+	case NodeFaultNotReady, NodeFaultCordon:
+		if f.Taint != nil {
+			return fmt.Errorf("taint is only allowed for type %q", NodeFaultTaint)
+		}
+	case NodeFaultTaint:
+		if f.Taint == nil || f.Taint.Key == "" {
+			return fmt.Errorf("taint.key is required for type %q", NodeFaultTaint)
+		}
+		switch f.Taint.Effect {
+		case "NoSchedule", "PreferNoSchedule", "NoExecute":
+		default:
+			return fmt.Errorf("taint: invalid effect %q", f.Taint.Effect)
+		}
+	default:
+		return fmt.Errorf("unsupported type %q (must be %s, %s, or %s)",
+			f.Type, NodeFaultNotReady, NodeFaultCordon, NodeFaultTaint)
+	}
+
+	if f.At == "" {
+		return fmt.Errorf("at is required")
+	}
+	if at, err := time.ParseDuration(f.At); err != nil || at < 0 {
+		return fmt.Errorf("invalid at %q: must be a non-negative duration", f.At)
+	}
+
+	if f.Duration != "" {
+		if d, err := time.ParseDuration(f.Duration); err != nil || d <= 0 {
+			return fmt.Errorf("invalid duration %q: must be a positive duration", f.Duration)
+		}
+	}
+
+	if f.Count < 0 {
+		return fmt.Errorf("count must be >= 0, got %d", f.Count)
+	}
+	if f.Fraction < 0 || f.Fraction > 1 {
+		return fmt.Errorf("fraction must be in [0, 1], got %g", f.Fraction)
+	}
+	if f.Count > 0 && f.Fraction > 0 {
+		return fmt.Errorf("only one of count or fraction may be set")
+	}
+
+	return nil
+}
+
+// ValidateNodeChurn validates node churn settings
+func ValidateNodeChurn(c *NodeChurn) error {
+	if c.Interval == "" {
+		return fmt.Errorf("interval is required")
+	}
+	interval, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		return fmt.Errorf("invalid interval %q: %w", c.Interval, err)
+	}
+	if interval <= 0 {
+		return fmt.Errorf("interval must be > 0, got %s", c.Interval)
+	}
+
+	if c.Fraction <= 0 || c.Fraction > 1 {
+		return fmt.Errorf("fraction must be in (0, 1], got %g", c.Fraction)
+	}
+
+	if c.RecreateAfter != "" {
+		recreateAfter, err := time.ParseDuration(c.RecreateAfter)
+		if err != nil {
+			return fmt.Errorf("invalid recreateAfter %q: %w", c.RecreateAfter, err)
+		}
+		if recreateAfter < 0 {
+			return fmt.Errorf("recreateAfter must be >= 0, got %s", c.RecreateAfter)
+		}
+	}
+
 	return nil
 }
 
@@ -67,6 +185,12 @@ func validateWorkloadSpec(w *WorkloadSpec, index int) error {
 		return fmt.Errorf("spec.workloads[%d] (%s): weight must be > 0", index, w.Type)
 	}
 
+	if w.PodPriorityClass != "" {
+		if errs := validation.IsDNS1123Subdomain(w.PodPriorityClass); len(errs) > 0 {
+			return fmt.Errorf("spec.workloads[%d] (%s): invalid podPriorityClass '%s': %s", index, w.Type, w.PodPriorityClass, strings.Join(errs, "; "))
+		}
+	}
+
 	for i, t := range w.Tolerations {
 		if t.Key == "" && t.Operator != "Exists" {
 			return fmt.Errorf("spec.workloads[%d]: tolerations[%d]: key is required unless operator is Exists", index, i)
@@ -78,6 +202,12 @@ func validateWorkloadSpec(w *WorkloadSpec, index int) error {
 		}
 	}
 
+	if w.Spread != nil {
+		if err := validateQueueSpread(w); err != nil {
+			return fmt.Errorf("spec.workloads[%d] (%s): spread: %w", index, w.Type, err)
+		}
+	}
+
 	switch w.Type {
 	case "Job":
 		t, ok := w.Template.(*JobTemplate)
@@ -110,6 +240,59 @@ func validateWorkloadSpec(w *WorkloadSpec, index int) error {
 	return nil
 }
 
+// validateQueueSpread checks that a spread workload's namespace and LocalQueue patterns
+// name as many namespaces and queues as it spreads over
+func validateQueueSpread(w *WorkloadSpec) error {
+	switch {
+	case w.Spread.Namespaces < 1:
+		return fmt.Errorf("namespaces must be at least 1")
+	case w.Spread.QueuesPerNamespace < 1:
+		return fmt.Errorf("queuesPerNamespace must be at least 1")
+	case w.LocalQueue == "":
+		return fmt.Errorf("localQueue is required")
+	case strings.Contains(w.Namespace+w.LocalQueue, "{cq}"):
+		return fmt.Errorf("namespace and localQueue cannot use {cq}; write the ClusterQueue name instead")
+	case w.Spread.Namespaces > 1 && !strings.Contains(w.Namespace, "{ns}"):
+		return fmt.Errorf("namespace '%s' must contain {ns} to spread over %d namespaces", w.Namespace, w.Spread.Namespaces)
+	case w.Spread.QueuesPerNamespace > 1 && !strings.Contains(w.LocalQueue, "{lq}"):
+		return fmt.Errorf("localQueue '%s' must contain {lq} to spread over %d queues per namespace", w.LocalQueue, w.Spread.QueuesPerNamespace)
+	}
+	return nil
+}
+
+// ValidateSpreadQueues checks that every LocalQueue the spread workloads of p can submit to
+// is provisioned by kueueConfig, so a spread that does not match the topology's
+// localQueueGenerators fails before the run rather than as missing LocalQueues
+func ValidateSpreadQueues(p *WorkloadProfile, kueueConfig *KueueConfig) error {
+	queues := make(map[string]bool)
+	for _, lq := range kueueConfig.WithGeneratedLocalQueues().LocalQueues {
+		ns := lq.Namespace
+		if ns == "" {
+			ns = "default"
+		}
+		queues[ns+"/"+lq.Name] = true
+	}
+	for i := range p.Spec.Workloads {
+		w := &p.Spec.Workloads[i]
+		if w.Spread == nil {
+			continue
+		}
+		for nsIndex := range w.Spread.Namespaces {
+			for lqIndex := range w.Spread.QueuesPerNamespace {
+				ns := QueueName(w.Namespace, "", nsIndex, lqIndex)
+				if ns == "" {
+					ns = "default"
+				}
+				name := QueueName(w.LocalQueue, "", nsIndex, lqIndex)
+				if !queues[ns+"/"+name] {
+					return fmt.Errorf("spec.workloads[%d] (%s): spread: LocalQueue %s/%s is not in the topology's Kueue config; match spread to its localQueueGenerators", i, w.Type, ns, name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func validateCommonTemplate(c *CommonTemplate, workloadType string, index int) error {
 	if c.Duration != nil {
 		if err := validateDistribution(c.Duration, "duration"); err != nil {
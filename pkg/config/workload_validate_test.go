@@ -194,6 +194,48 @@ func TestValidateWorkloadProfile(t *testing.T) {
 			wantErr:     true,
 			errContains: "unsupported type \"Deployment\"",
 		},
+		{
+			name: "spread over generated local queues",
+			profile: func() *WorkloadProfile {
+				p := validJobWorkloadProfile()
+				p.Spec.Workloads[0].Namespace = "cq-ns-{ns}"
+				p.Spec.Workloads[0].LocalQueue = "cq-lq-{lq}"
+				p.Spec.Workloads[0].Spread = &QueueSpread{Namespaces: 20, QueuesPerNamespace: 5}
+				return p
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "pod priority class",
+			profile: func() *WorkloadProfile {
+				p := validJobWorkloadProfile()
+				p.Spec.Workloads[0].PodPriorityClass = "batch-high"
+				return p
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "invalid pod priority class",
+			profile: func() *WorkloadProfile {
+				p := validJobWorkloadProfile()
+				p.Spec.Workloads[0].PodPriorityClass = "Batch_High"
+				return p
+			}(),
+			wantErr:     true,
+			errContains: "invalid podPriorityClass 'Batch_High'",
+		},
+		{
+			name: "spread without namespace index",
+			profile: func() *WorkloadProfile {
+				p := validJobWorkloadProfile()
+				p.Spec.Workloads[0].Namespace = "team"
+				p.Spec.Workloads[0].LocalQueue = "lq-{lq}"
+				p.Spec.Workloads[0].Spread = &QueueSpread{Namespaces: 2, QueuesPerNamespace: 2}
+				return p
+			}(),
+			wantErr:     true,
+			errContains: "spread: namespace 'team' must contain {ns}",
+		},
 	}
 
 	for _, tt := range tests {
@@ -271,6 +313,148 @@ func TestValidateArrivalPattern(t *testing.T) {
 	}
 }
 
+func TestValidateNodeChurn(t *testing.T) {
+	tests := []struct {
+		name        string
+		churn       NodeChurn
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:    "valid churn",
+			churn:   NodeChurn{Interval: "2m", Fraction: 0.1, RecreateAfter: "30s"},
+			wantErr: false,
+		},
+		{
+			name:        "missing interval",
+			churn:       NodeChurn{Fraction: 0.1},
+			wantErr:     true,
+			errContains: "interval is required",
+		},
+		{
+			name:        "invalid interval",
+			churn:       NodeChurn{Interval: "often", Fraction: 0.1},
+			wantErr:     true,
+			errContains: "invalid interval",
+		},
+		{
+			name:        "zero fraction",
+			churn:       NodeChurn{Interval: "1m"},
+			wantErr:     true,
+			errContains: "fraction must be in (0, 1]",
+		},
+		{
+			name:        "fraction above one",
+			churn:       NodeChurn{Interval: "1m", Fraction: 1.5},
+			wantErr:     true,
+			errContains: "fraction must be in (0, 1]",
+		},
+		{
+			name:        "negative recreateAfter",
+			churn:       NodeChurn{Interval: "1m", Fraction: 0.5, RecreateAfter: "-1s"},
+			wantErr:     true,
+			errContains: "recreateAfter must be >= 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNodeChurn(&tt.churn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateNodeChurn() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("ValidateNodeChurn() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateNodeFault(t *testing.T) {
+	tests := []struct {
+		name        string
+		fault       NodeFault
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:    "valid notReady",
+			fault:   NodeFault{Type: "notReady", At: "5m", Duration: "2m", Pool: "gpu", Count: 2},
+			wantErr: false,
+		},
+		{
+			name:    "valid cordon by fraction",
+			fault:   NodeFault{Type: "cordon", At: "0s", NodeSelector: map[string]string{"zone": "a"}, Fraction: 0.5},
+			wantErr: false,
+		},
+		{
+			name:    "valid taint",
+			fault:   NodeFault{Type: "taint", At: "1m", Taint: &Taint{Key: "maintenance", Effect: "NoExecute"}},
+			wantErr: false,
+		},
+		{
+			name:        "unsupported type",
+			fault:       NodeFault{Type: "explode", At: "1m"},
+			wantErr:     true,
+			errContains: "unsupported type \"explode\"",
+		},
+		{
+			name:        "taint type without taint",
+			fault:       NodeFault{Type: "taint", At: "1m"},
+			wantErr:     true,
+			errContains: "taint.key is required",
+		},
+		{
+			name:        "taint with invalid effect",
+			fault:       NodeFault{Type: "taint", At: "1m", Taint: &Taint{Key: "k", Effect: "Sometimes"}},
+			wantErr:     true,
+			errContains: "invalid effect \"Sometimes\"",
+		},
+		{
+			name:        "taint on cordon fault",
+			fault:       NodeFault{Type: "cordon", At: "1m", Taint: &Taint{Key: "k", Effect: "NoSchedule"}},
+			wantErr:     true,
+			errContains: "taint is only allowed for type \"taint\"",
+		},
+		{
+			name:        "missing at",
+			fault:       NodeFault{Type: "cordon"},
+			wantErr:     true,
+			errContains: "at is required",
+		},
+		{
+			name:        "invalid duration",
+			fault:       NodeFault{Type: "cordon", At: "1m", Duration: "0s"},
+			wantErr:     true,
+			errContains: "invalid duration \"0s\"",
+		},
+		{
+			name:        "count and fraction",
+			fault:       NodeFault{Type: "cordon", At: "1m", Count: 1, Fraction: 0.5},
+			wantErr:     true,
+			errContains: "only one of count or fraction",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNodeFault(&tt.fault)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateNodeFault() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("validateNodeFault() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			}
+		})
+	}
+}
+
 func TestValidateDistribution(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -632,3 +816,39 @@ func TestValidateRayJobTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSpreadQueues(t *testing.T) {
+	kueueConfig := &KueueConfig{
+		LocalQueueGenerators: []LocalQueueGenerator{{ClusterQueue: "cq", Namespaces: 4, QueuesPerNamespace: 2}},
+	}
+	tests := []struct {
+		name        string
+		spread      QueueSpread
+		namespace   string
+		localQueue  string
+		errContains string
+	}{
+		{name: "matches the generator", spread: QueueSpread{Namespaces: 4, QueuesPerNamespace: 2}, namespace: "cq-ns-{ns}", localQueue: "cq-lq-{lq}"},
+		{name: "subset of the generator", spread: QueueSpread{Namespaces: 2, QueuesPerNamespace: 1}, namespace: "cq-ns-{ns}", localQueue: "cq-lq-{lq}"},
+		{name: "more namespaces", spread: QueueSpread{Namespaces: 5, QueuesPerNamespace: 2}, namespace: "cq-ns-{ns}", localQueue: "cq-lq-{lq}", errContains: "LocalQueue cq-ns-4/cq-lq-0 is not in the topology's Kueue config"},
+		{name: "other ClusterQueue name", spread: QueueSpread{Namespaces: 1, QueuesPerNamespace: 1}, namespace: "other-ns-{ns}", localQueue: "other-lq-{lq}", errContains: "other-ns-0/other-lq-0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := validJobWorkloadProfile()
+			p.Spec.Workloads[0].Namespace = tt.namespace
+			p.Spec.Workloads[0].LocalQueue = tt.localQueue
+			p.Spec.Workloads[0].Spread = &tt.spread
+			err := ValidateSpreadQueues(p, kueueConfig)
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("ValidateSpreadQueues() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("ValidateSpreadQueues() error = %v, expected to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
@@ -3,26 +3,42 @@ package extensions
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/jhwagner/kueue-bench/pkg/config"
 	"github.com/jhwagner/kueue-bench/pkg/helm"
+	"github.com/jhwagner/kueue-bench/pkg/kueue"
 	"github.com/jhwagner/kueue-bench/pkg/manifest"
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
 	"k8s.io/utils/ptr"
 )
 
-// InstallExtensions installs all Helm chart or manifest extensions for a cluster
-func InstallExtensions(ctx context.Context, kubeconfigPath string, extensions []config.Extension) error {
+// InstallExtensions installs all Helm chart, manifest or kustomize extensions for a cluster.
+// When offline, manifest URLs are served only from the download cache.
+func InstallExtensions(ctx context.Context, kubeconfigPath string, extensions []config.Extension, offline bool, clientOpts ...restconfig.Option) error {
 	for _, ext := range extensions {
+		resolved, err := ext.ResolvePreset()
+		if err != nil {
+			return fmt.Errorf("failed to install extension '%s': %w", ext.Name, err)
+		}
+		ext = resolved
 		switch {
 		case ext.Helm != nil:
 			if err := installHelmExtension(ctx, kubeconfigPath, ext.Name, ext.Helm); err != nil {
 				return fmt.Errorf("failed to install helm extension '%s': %w", ext.Name, err)
 			}
 		case ext.Manifest != nil:
-			if err := installManifestExtension(ctx, kubeconfigPath, ext.Name, ext.Manifest); err != nil {
+			if err := installManifestExtension(ctx, kubeconfigPath, ext.Name, ext.Manifest, offline, clientOpts); err != nil {
 				return fmt.Errorf("failed to install manifest extension '%s': %w", ext.Name, err)
 			}
+		case ext.Kustomize != nil:
+			if err := installKustomizeExtension(ctx, kubeconfigPath, ext.Name, ext.Kustomize, clientOpts); err != nil {
+				return fmt.Errorf("failed to install kustomize extension '%s': %w", ext.Name, err)
+			}
+		}
+		if err := runPostInstall(ctx, kubeconfigPath, ext, offline, clientOpts); err != nil {
+			return fmt.Errorf("failed to run post-install actions of extension '%s': %w", ext.Name, err)
 		}
 	}
 	return nil
@@ -54,14 +70,13 @@ func installHelmExtension(ctx context.Context, kubeconfigPath, name string, helm
 		}
 	}
 
-	// Parse --set values using strvals to support dot notation (e.g. "foo.bar=baz")
-	var values map[string]interface{}
-	if len(helmExt.Set) > 0 {
-		var err error
-		values, err = helm.ParseSetValues(helmExt.Set)
-		if err != nil {
-			return fmt.Errorf("failed to parse values: %w", err)
-		}
+	values, err := helmValues(helmExt)
+	if err != nil {
+		return err
+	}
+	auth, err := helmAuth(helmExt.Auth)
+	if err != nil {
+		return err
 	}
 
 	// Install the chart
@@ -70,11 +85,13 @@ func installHelmExtension(ctx context.Context, kubeconfigPath, name string, helm
 		Namespace:       namespace,
 		ReleaseName:     releaseName,
 		ChartRef:        helmExt.Chart,
+		RepoURL:         helmExt.Repo,
 		Version:         helmExt.Version,
 		Values:          values,
 		CreateNamespace: ptr.Deref(helmExt.CreateNamespace, true),
 		Wait:            ptr.Deref(helmExt.Wait, true),
 		Timeout:         timeout,
+		Auth:            auth,
 	}); err != nil {
 		return err
 	}
@@ -83,12 +100,177 @@ func installHelmExtension(ctx context.Context, kubeconfigPath, name string, helm
 	return nil
 }
 
-func installManifestExtension(ctx context.Context, kubeconfigPath, name string, m *config.ManifestExtension) error {
-	fmt.Printf("Installing extension '%s' (manifest: %s)...\n", name, m.URL)
+// helmValues merges an extension's values file, inline values and --set values, in that
+// order of precedence from lowest to highest, as helm install -f ... --set ... does
+func helmValues(helmExt *config.HelmExtension) (map[string]interface{}, error) {
+	var values map[string]interface{}
+	if helmExt.ValuesFile != "" {
+		var err error
+		if values, err = helm.ReadValuesFile(helmExt.ValuesFile); err != nil {
+			return nil, err
+		}
+	}
+	if len(helmExt.Values) > 0 {
+		values = helm.MergeValues(values, helmExt.Values)
+	}
 
-	if err := manifest.ApplyURLWithKubeconfig(ctx, kubeconfigPath, m.URL); err != nil {
+	// Parse --set values using strvals to support dot notation (e.g. "foo.bar=baz")
+	if len(helmExt.Set) > 0 {
+		set, err := helm.ParseSetValues(helmExt.Set)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse values: %w", err)
+		}
+		values = helm.MergeValues(values, set)
+	}
+	return values, nil
+}
+
+func installManifestExtension(ctx context.Context, kubeconfigPath, name string, m *config.ManifestExtension, offline bool, clientOpts []restconfig.Option) error {
+	fmt.Printf("Installing extension '%s' (manifest: %s)...\n", name, manifestSource(m))
+	if err := applyManifest(ctx, kubeconfigPath, m, offline, clientOpts); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Extension '%s' installed successfully\n", name)
+	return nil
+}
+
+// applyManifest applies the manifests at a manifest extension's path or URL
+func applyManifest(ctx context.Context, kubeconfigPath string, m *config.ManifestExtension, offline bool, clientOpts []restconfig.Option) error {
+	if m.Path != "" {
+		if err := manifest.ApplyPathWithKubeconfig(ctx, kubeconfigPath, m.Path, clientOpts...); err != nil {
+			return fmt.Errorf("failed to apply manifest: %w", err)
+		}
+		return nil
+	}
+
+	auth, err := manifestAuth(m.Auth)
+	if err != nil {
+		return err
+	}
+	src := manifest.Source{URL: m.URL, SHA256: m.SHA256, Auth: auth, Offline: offline}
+	if err := manifest.ApplyURLWithKubeconfig(ctx, kubeconfigPath, src, clientOpts...); err != nil {
 		return fmt.Errorf("failed to apply manifest: %w", err)
 	}
+	return nil
+}
+
+// manifestSource returns the path or URL a manifest extension is applied from
+func manifestSource(m *config.ManifestExtension) string {
+	if m.Path != "" {
+		return m.Path
+	}
+	return m.URL
+}
+
+// defaultCRDTimeout bounds a post-install waitForCRD without a timeout
+const defaultCRDTimeout = 2 * time.Minute
+
+// runPostInstall runs an extension's post-install actions in order
+func runPostInstall(ctx context.Context, kubeconfigPath string, ext config.Extension, offline bool, clientOpts []restconfig.Option) error {
+	if len(ext.PostInstall) == 0 {
+		return nil
+	}
+	client, err := kueue.NewClient(kubeconfigPath, clientOpts...)
+	if err != nil {
+		return err
+	}
+
+	for i, action := range ext.PostInstall {
+		switch {
+		case action.Namespace != "":
+			if err := client.CreateNamespace(ctx, action.Namespace, nil); err != nil {
+				return fmt.Errorf("postInstall[%d]: %w", i, err)
+			}
+			fmt.Printf("✓ Namespace '%s' ready\n", action.Namespace)
+		case action.WaitForCRD != "":
+			timeout := defaultCRDTimeout
+			if action.Timeout != "" {
+				if timeout, err = time.ParseDuration(action.Timeout); err != nil {
+					return fmt.Errorf("postInstall[%d]: invalid timeout: %w", i, err)
+				}
+			}
+			if err := client.WaitForCRDEstablished(ctx, action.WaitForCRD, timeout); err != nil {
+				return fmt.Errorf("postInstall[%d]: %w", i, err)
+			}
+			fmt.Printf("✓ CRD '%s' established\n", action.WaitForCRD)
+		case action.Manifest != nil:
+			if err := applyManifest(ctx, kubeconfigPath, action.Manifest, offline, clientOpts); err != nil {
+				return fmt.Errorf("postInstall[%d]: %w", i, err)
+			}
+			fmt.Printf("✓ Applied %s\n", manifestSource(action.Manifest))
+		}
+	}
+	return nil
+}
+
+// helmAuth reads a Helm extension's chart credentials from the environment
+func helmAuth(a *config.HelmAuth) (*helm.RegistryAuth, error) {
+	if a == nil {
+		return nil, nil
+	}
+	auth := helm.RegistryAuth{CredentialsFile: a.ConfigFile}
+	for _, v := range []struct {
+		dst *string
+		env string
+	}{
+		{&auth.Username, a.UsernameEnv},
+		{&auth.Password, a.PasswordEnv},
+		{&auth.IdentityToken, a.IdentityTokenEnv},
+	} {
+		var err error
+		if *v.dst, err = lookupEnv(v.env); err != nil {
+			return nil, fmt.Errorf("helm auth: %w", err)
+		}
+	}
+	return &auth, nil
+}
+
+// manifestAuth reads a manifest's download credentials from the environment
+func manifestAuth(a *config.ManifestAuth) (*manifest.Auth, error) {
+	if a == nil {
+		return nil, nil
+	}
+	var auth manifest.Auth
+	for _, v := range []struct {
+		dst *string
+		env string
+	}{
+		{&auth.BearerToken, a.BearerTokenEnv},
+		{&auth.Username, a.UsernameEnv},
+		{&auth.Password, a.PasswordEnv},
+	} {
+		var err error
+		if *v.dst, err = lookupEnv(v.env); err != nil {
+			return nil, fmt.Errorf("manifest auth: %w", err)
+		}
+	}
+	return &auth, nil
+}
+
+// lookupEnv returns the value of a credential environment variable, which must be set
+// if named
+func lookupEnv(name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	v := os.Getenv(name)
+	if v == "" {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return v, nil
+}
+
+func installKustomizeExtension(ctx context.Context, kubeconfigPath, name string, k *config.KustomizeExtension, clientOpts []restconfig.Option) error {
+	target := k.Path
+	if target == "" {
+		target = k.URL
+	}
+	fmt.Printf("Installing extension '%s' (kustomize: %s)...\n", name, target)
+
+	if err := manifest.ApplyKustomizationWithKubeconfig(ctx, kubeconfigPath, target, clientOpts...); err != nil {
+		return fmt.Errorf("failed to apply kustomization: %w", err)
+	}
 
 	fmt.Printf("✓ Extension '%s' installed successfully\n", name)
 	return nil
@@ -1 +1,127 @@
 package extensions
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/helm"
+)
+
+func TestHelmValues(t *testing.T) {
+	valuesFile := filepath.Join(t.TempDir(), "values.yaml")
+	data := "grafana:\n  enabled: true\n  adminPassword: file\nprometheus:\n  retention: 1d\n"
+	if err := os.WriteFile(valuesFile, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		helmExt     *config.HelmExtension
+		want        map[string]interface{}
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:    "no values",
+			helmExt: &config.HelmExtension{Chart: "chart"},
+			want:    nil,
+		},
+		{
+			name: "inline values override values file",
+			helmExt: &config.HelmExtension{
+				ValuesFile: valuesFile,
+				Values:     map[string]interface{}{"grafana": map[string]interface{}{"adminPassword": "inline"}},
+			},
+			want: map[string]interface{}{
+				"grafana":    map[string]interface{}{"enabled": true, "adminPassword": "inline"},
+				"prometheus": map[string]interface{}{"retention": "1d"},
+			},
+		},
+		{
+			name: "set overrides inline values",
+			helmExt: &config.HelmExtension{
+				Values: map[string]interface{}{"grafana": map[string]interface{}{"enabled": true, "adminPassword": "inline"}},
+				Set:    map[string]string{"grafana.adminPassword": "set"},
+			},
+			want: map[string]interface{}{
+				"grafana": map[string]interface{}{"enabled": true, "adminPassword": "set"},
+			},
+		},
+		{
+			name:        "missing values file",
+			helmExt:     &config.HelmExtension{ValuesFile: filepath.Join(t.TempDir(), "missing.yaml")},
+			wantErr:     true,
+			errContains: "failed to read values file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := helmValues(tt.helmExt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("helmValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("helmValues() error = %v, expected to contain %q", err, tt.errContains)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("helmValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHelmAuth(t *testing.T) {
+	t.Setenv("REGISTRY_USER", "bench")
+	t.Setenv("REGISTRY_PASSWORD", "secret")
+
+	tests := []struct {
+		name        string
+		auth        *config.HelmAuth
+		want        *helm.RegistryAuth
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name: "no auth",
+			auth: nil,
+			want: nil,
+		},
+		{
+			name: "basic auth from environment",
+			auth: &config.HelmAuth{ConfigFile: "/etc/docker/config.json", UsernameEnv: "REGISTRY_USER", PasswordEnv: "REGISTRY_PASSWORD"},
+			want: &helm.RegistryAuth{CredentialsFile: "/etc/docker/config.json", Username: "bench", Password: "secret"},
+		},
+		{
+			name:        "unset variable",
+			auth:        &config.HelmAuth{IdentityTokenEnv: "KUEUE_BENCH_UNSET_TOKEN"},
+			wantErr:     true,
+			errContains: "environment variable KUEUE_BENCH_UNSET_TOKEN is not set",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := helmAuth(tt.auth)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("helmAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("helmAuth() error = %v, expected to contain %q", err, tt.errContains)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("helmAuth() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
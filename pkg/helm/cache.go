@@ -0,0 +1,134 @@
+package helm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/jhwagner/kueue-bench/pkg/state"
+)
+
+// chartCache shares chart pulls between the installs of a process and, for pinned
+// versions, between processes. Concurrent installs of the same chart wait on a single
+// pull, so creating many clusters in parallel downloads each chart and repository index
+// once instead of contending on Helm's repository cache.
+type chartCache struct {
+	// dir holds pulled archives of pinned chart versions; empty disables the disk cache
+	dir   string
+	group singleflight.Group
+
+	mu      sync.Mutex
+	located map[string]string
+}
+
+var (
+	sharedChartsOnce sync.Once
+	sharedCharts     *chartCache
+)
+
+// charts returns the process-wide chart cache under the state directory's cache/charts
+func charts() *chartCache {
+	sharedChartsOnce.Do(func() {
+		var dir string
+		if store, err := state.Open(); err == nil {
+			dir = filepath.Join(store.Root(), string(state.Cache), "charts")
+		}
+		sharedCharts = newChartCache(dir)
+	})
+	return sharedCharts
+}
+
+// newChartCache creates a chart cache storing archives in dir
+func newChartCache(dir string) *chartCache {
+	return &chartCache{dir: dir, located: make(map[string]string)}
+}
+
+// chartKey identifies a remote chart by reference, repository and version
+func chartKey(ref, repoURL, version string) string {
+	return ref + "\n" + repoURL + "\n" + version
+}
+
+// locate returns the local path of the chart identified by key, calling pull at most once
+// per process for it. Pinned charts are also kept on disk and reused by later processes.
+func (c *chartCache) locate(key string, pinned bool, pull func() (string, error)) (string, error) {
+	c.mu.Lock()
+	path, ok := c.located[key]
+	c.mu.Unlock()
+	if ok {
+		return path, nil
+	}
+
+	v, err, _ := c.group.Do(key, func() (interface{}, error) {
+		cached := c.path(key)
+		if pinned && cached != "" {
+			if _, err := os.Stat(cached); err == nil {
+				return cached, nil
+			}
+		}
+
+		path, err := pull()
+		if err != nil {
+			return "", err
+		}
+		if pinned && cached != "" {
+			// Caching is best effort: a failed copy only costs a pull next time
+			if err := c.store(path, cached); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to cache chart %s: %v\n", path, err)
+			} else {
+				path = cached
+			}
+		}
+		return path, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	path = v.(string)
+	c.mu.Lock()
+	c.located[key] = path
+	c.mu.Unlock()
+	return path, nil
+}
+
+// path returns the on-disk archive for key, or "" when the disk cache is disabled
+func (c *chartCache) path(key string) string {
+	if c.dir == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".tgz")
+}
+
+// store copies the chart archive at src to dst atomically, so concurrent processes never
+// load a partially written archive
+func (c *chartCache) store(src, dst string) error {
+	in, err := os.Open(src) //nolint:gosec // src is the archive Helm just pulled
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	if err := os.MkdirAll(c.dir, 0750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, ".pull-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := io.Copy(tmp, in); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
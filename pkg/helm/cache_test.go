@@ -0,0 +1,91 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestChartCacheLocate(t *testing.T) {
+	pullDir := t.TempDir()
+	cacheDir := filepath.Join(t.TempDir(), "charts")
+
+	var pulls atomic.Int32
+	pull := func() (string, error) {
+		pulls.Add(1)
+		// Hold the pull open so concurrent callers overlap
+		time.Sleep(50 * time.Millisecond)
+		path := filepath.Join(pullDir, "chart-1.0.0.tgz")
+		return path, os.WriteFile(path, []byte("chart"), 0600)
+	}
+
+	key := chartKey("oci://example.com/charts/chart", "", "1.0.0")
+	cache := newChartCache(cacheDir)
+	var wg sync.WaitGroup
+	paths := make([]string, 8)
+	for i := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			path, err := cache.locate(key, true, pull)
+			if err != nil {
+				t.Errorf("locate() error = %v", err)
+			}
+			paths[i] = path
+		}()
+	}
+	wg.Wait()
+
+	if got := pulls.Load(); got != 1 {
+		t.Errorf("concurrent locate() pulled %d times, want 1", got)
+	}
+	for _, path := range paths {
+		if filepath.Dir(path) != cacheDir {
+			t.Errorf("locate() = %s, want a path in %s", path, cacheDir)
+		}
+	}
+
+	// A later process reuses the pinned chart from disk
+	if _, err := newChartCache(cacheDir).locate(key, true, pull); err != nil {
+		t.Fatalf("locate() error = %v", err)
+	}
+	if got := pulls.Load(); got != 1 {
+		t.Errorf("locate() of a cached chart pulled %d times, want 1", got)
+	}
+
+	// Unpinned charts are only shared within a process
+	unpinned := chartKey("oci://example.com/charts/chart", "", "")
+	for range 2 {
+		if _, err := newChartCache(cacheDir).locate(unpinned, false, pull); err != nil {
+			t.Fatalf("locate() error = %v", err)
+		}
+	}
+	if got := pulls.Load(); got != 3 {
+		t.Errorf("locate() of an unpinned chart pulled %d times in total, want 3", got)
+	}
+}
+
+func TestExactVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{version: "", want: false},
+		{version: "0.12.0", want: true},
+		{version: "v1.18.2", want: true},
+		{version: "^1.0.0", want: false},
+		{version: ">=1.0.0 <2.0.0", want: false},
+		{version: "1.x", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := exactVersion(tt.version); got != tt.want {
+				t.Errorf("exactVersion(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
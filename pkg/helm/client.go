@@ -2,41 +2,75 @@ package helm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
 	"helm.sh/helm/v3/pkg/strvals"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/retry"
 )
 
 // InstallOptions contains configuration for a Helm chart installation
 type InstallOptions struct {
-	KubeconfigPath  string
-	Namespace       string
-	ReleaseName     string
-	ChartRef        string
-	Version         string
-	Values          map[string]interface{}
+	KubeconfigPath string
+	Namespace      string
+	ReleaseName    string
+	ChartRef       string
+	RepoURL        string // chart repository to find ChartRef in, as helm install --repo
+	Version        string
+	Values         map[string]interface{}
+	// ValuesFunc, if set, computes the install values from the chart's default values
+	// in place of Values
+	ValuesFunc      func(chartDefaults map[string]interface{}) (map[string]interface{}, error)
 	CreateNamespace bool
 	Wait            bool
 	Timeout         time.Duration
+
+	// Auth holds chart registry or repository credentials. When unset, Helm's registry
+	// config and the Docker config are used.
+	Auth *RegistryAuth
 }
 
-// Install installs a Helm chart with the given options
-func Install(ctx context.Context, opts InstallOptions) error {
-	// Set up Helm environment
+// RegistryAuth holds credentials for pulling a chart
+type RegistryAuth struct {
+	// CredentialsFile is a Docker-style config file with OCI registry credentials
+	CredentialsFile string
+	// Username and Password are used for OCI registries and chart repositories
+	Username string
+	Password string
+	// IdentityToken is an OCI registry identity (refresh) token
+	IdentityToken string
+}
+
+// UninstallOptions contains configuration for a Helm release uninstall
+type UninstallOptions struct {
+	KubeconfigPath string
+	Namespace      string
+	ReleaseName    string
+	Wait           bool
+	Timeout        time.Duration
+}
+
+// newActionConfig sets up the Helm environment and action configuration for a cluster
+func newActionConfig(kubeconfigPath, namespace string) (*action.Configuration, *cli.EnvSettings, error) {
 	settings := cli.New()
-	settings.KubeConfig = opts.KubeconfigPath
+	settings.KubeConfig = kubeconfigPath
 
-	// Create action configuration
 	actionConfig := new(action.Configuration)
 	if err := actionConfig.Init(
 		settings.RESTClientGetter(),
-		opts.Namespace,
+		namespace,
 		os.Getenv("HELM_DRIVER"),
 		func(format string, v ...interface{}) {
 			// Debug logger - prints to stdout when HELM_DEBUG is set
@@ -45,15 +79,23 @@ func Install(ctx context.Context, opts InstallOptions) error {
 			}
 		},
 	); err != nil {
-		return fmt.Errorf("failed to initialize Helm action config: %w", err)
+		return nil, nil, fmt.Errorf("failed to initialize Helm action config: %w", err)
+	}
+	return actionConfig, settings, nil
+}
+
+// Install installs a Helm chart with the given options. If the release is already deployed,
+// as when a topology is created again, it is upgraded instead, as helm upgrade --install
+// does; a release left failed or pending by an interrupted install is replaced.
+func Install(ctx context.Context, opts InstallOptions) error {
+	actionConfig, settings, err := newActionConfig(opts.KubeconfigPath, opts.Namespace)
+	if err != nil {
+		return err
 	}
 
 	// Set up registry client for OCI support
 	// Use stdout for output so users can see download progress
-	registryClient, err := registry.NewClient(
-		registry.ClientOptDebug(settings.Debug),
-		registry.ClientOptWriter(os.Stdout),
-	)
+	registryClient, err := registry.NewClient(registryClientOptions(settings.Debug, opts.Auth)...)
 	if err != nil {
 		return fmt.Errorf("failed to create registry client: %w", err)
 	}
@@ -69,9 +111,14 @@ func Install(ctx context.Context, opts InstallOptions) error {
 	if opts.Version != "" {
 		client.Version = opts.Version
 	}
+	client.RepoURL = opts.RepoURL
+	if opts.Auth != nil {
+		client.Username = opts.Auth.Username
+		client.Password = opts.Auth.Password
+	}
 
 	// Locate and load the chart (works for both OCI and traditional repos)
-	chartPath, err := client.LocateChart(opts.ChartRef, settings)
+	chartPath, err := locateChart(ctx, client, opts, settings)
 	if err != nil {
 		return fmt.Errorf("failed to locate chart %s: %w", opts.ChartRef, err)
 	}
@@ -81,8 +128,42 @@ func Install(ctx context.Context, opts InstallOptions) error {
 		return fmt.Errorf("failed to load chart: %w", err)
 	}
 
+	values := opts.Values
+	if opts.ValuesFunc != nil {
+		if values, err = opts.ValuesFunc(chart.Values); err != nil {
+			return fmt.Errorf("failed to compute chart values: %w", err)
+		}
+	}
+
+	existing, err := lastRelease(actionConfig, opts.ReleaseName)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.Info.Status == release.StatusDeployed {
+		upgrade := action.NewUpgrade(actionConfig)
+		upgrade.Namespace = opts.Namespace
+		upgrade.Wait = opts.Wait
+		upgrade.Timeout = opts.Timeout
+		if _, err := upgrade.RunWithContext(ctx, opts.ReleaseName, chart, values); err != nil {
+			return fmt.Errorf("failed to upgrade release %s: %w", opts.ReleaseName, err)
+		}
+		return nil
+	}
+	if existing != nil {
+		fmt.Printf("Replacing release %s left %s by an earlier install...\n", opts.ReleaseName, existing.Info.Status)
+		if err := Uninstall(ctx, UninstallOptions{
+			KubeconfigPath: opts.KubeconfigPath,
+			Namespace:      opts.Namespace,
+			ReleaseName:    opts.ReleaseName,
+			Wait:           opts.Wait,
+			Timeout:        opts.Timeout,
+		}); err != nil {
+			return err
+		}
+	}
+
 	// Run the install
-	_, err = client.RunWithContext(ctx, chart, opts.Values)
+	_, err = client.RunWithContext(ctx, chart, values)
 	if err != nil {
 		return fmt.Errorf("failed to install chart: %w", err)
 	}
@@ -90,6 +171,115 @@ func Install(ctx context.Context, opts InstallOptions) error {
 	return nil
 }
 
+// lastRelease returns the latest revision of a release, or nil if it was never installed
+func lastRelease(actionConfig *action.Configuration, name string) (*release.Release, error) {
+	history, err := actionConfig.Releases.History(name)
+	if err != nil && !errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil, fmt.Errorf("failed to get history of release %s: %w", name, err)
+	}
+	var last *release.Release
+	for _, r := range history {
+		if last == nil || r.Version > last.Version {
+			last = r
+		}
+	}
+	return last, nil
+}
+
+// locateChart returns the local path of the chart to install. Local charts are used in
+// place; remote charts are pulled through the shared chart cache. Helm pulls take no
+// context, so a cancelled ctx returns early and leaves the pull to finish in the background.
+func locateChart(ctx context.Context, client *action.Install, opts InstallOptions, settings *cli.EnvSettings) (string, error) {
+	if _, err := os.Stat(opts.ChartRef); err == nil {
+		return client.LocateChart(opts.ChartRef, settings)
+	}
+	key := chartKey(opts.ChartRef, opts.RepoURL, opts.Version)
+	return untilCancelled(ctx, func() (string, error) {
+		return charts().locate(key, exactVersion(opts.Version), func() (string, error) {
+			return client.LocateChart(opts.ChartRef, settings)
+		})
+	})
+}
+
+// untilCancelled runs fn, a Helm operation that takes no context, and returns as soon as
+// ctx is cancelled, leaving fn to finish in the background
+func untilCancelled[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+	type result struct {
+		v   T
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		v, err := fn()
+		done <- result{v, err}
+	}()
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// exactVersion reports whether version names a single chart version rather than a
+// constraint, so the chart it resolves to can be cached across runs
+func exactVersion(version string) bool {
+	_, err := semver.StrictNewVersion(strings.TrimPrefix(version, "v"))
+	return version != "" && err == nil
+}
+
+// registryClientOptions returns the registry client options for a chart pull with the
+// given credentials
+func registryClientOptions(debug bool, a *RegistryAuth) []registry.ClientOption {
+	options := []registry.ClientOption{
+		registry.ClientOptDebug(debug),
+		registry.ClientOptWriter(os.Stdout),
+	}
+	if a == nil {
+		return options
+	}
+	if a.CredentialsFile != "" {
+		options = append(options, registry.ClientOptCredentialsFile(a.CredentialsFile))
+	}
+	switch {
+	case a.IdentityToken != "":
+		options = append(options, registry.ClientOptAuthorizer(auth.Client{
+			Client: retry.DefaultClient,
+			Cache:  auth.NewCache(),
+			Credential: func(context.Context, string) (auth.Credential, error) {
+				return auth.Credential{RefreshToken: a.IdentityToken}, nil
+			},
+		}))
+	case a.Username != "":
+		options = append(options, registry.ClientOptBasicAuth(a.Username, a.Password))
+	}
+	return options
+}
+
+// Uninstall uninstalls a Helm release. Resources annotated with Helm's keep resource
+// policy are left in place. Helm uninstalls take no context, so a cancelled ctx returns
+// early and leaves the uninstall to finish in the background.
+func Uninstall(ctx context.Context, opts UninstallOptions) error {
+	actionConfig, _, err := newActionConfig(opts.KubeconfigPath, opts.Namespace)
+	if err != nil {
+		return err
+	}
+
+	client := action.NewUninstall(actionConfig)
+	client.Wait = opts.Wait
+	client.Timeout = opts.Timeout
+	if _, err := untilCancelled(ctx, func() (*release.UninstallReleaseResponse, error) {
+		return client.Run(opts.ReleaseName)
+	}); err != nil {
+		return fmt.Errorf("failed to uninstall release %s: %w", opts.ReleaseName, err)
+	}
+	return nil
+}
+
 // ParseSetValues parses Helm --set style key=value pairs into a values map
 // Supports dot notation (e.g. "foo.bar=baz" becomes {foo: {bar: baz}})
 func ParseSetValues(setValues map[string]string) (map[string]interface{}, error) {
@@ -103,3 +293,31 @@ func ParseSetValues(setValues map[string]string) (map[string]interface{}, error)
 
 	return values, nil
 }
+
+// ReadValuesFile reads a Helm values file, as helm install -f does
+func ReadValuesFile(path string) (map[string]interface{}, error) {
+	values, err := chartutil.ReadValuesFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read values file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// MergeValues returns values with overrides merged in recursively, overrides taking
+// precedence. Neither input map is modified.
+func MergeValues(values, overrides map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(values)+len(overrides))
+	for k, v := range values {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		if override, ok := v.(map[string]interface{}); ok {
+			if base, ok := merged[k].(map[string]interface{}); ok {
+				merged[k] = MergeValues(base, override)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
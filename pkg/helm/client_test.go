@@ -1,7 +1,13 @@
 package helm
 
 import (
+	"reflect"
 	"testing"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
 )
 
 func TestParseSetValues(t *testing.T) {
@@ -159,3 +165,51 @@ func TestParseSetValues(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeValues(t *testing.T) {
+	values := map[string]interface{}{
+		"replicas": 1,
+		"image":    map[string]interface{}{"repository": "a", "pullPolicy": "Always"},
+	}
+	overrides := map[string]interface{}{
+		"image": map[string]interface{}{"repository": "b", "tag": "dev"},
+	}
+
+	got := MergeValues(values, overrides)
+	want := map[string]interface{}{
+		"replicas": 1,
+		"image":    map[string]interface{}{"repository": "b", "tag": "dev", "pullPolicy": "Always"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeValues() = %v, want %v", got, want)
+	}
+	if image := values["image"].(map[string]interface{}); image["repository"] != "a" || len(image) != 2 {
+		t.Errorf("MergeValues() modified values: %v", values)
+	}
+}
+
+func TestLastRelease(t *testing.T) {
+	cfg := &action.Configuration{Releases: storage.Init(driver.NewMemory())}
+
+	got, err := lastRelease(cfg, "kueue")
+	if err != nil {
+		t.Fatalf("lastRelease() error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("lastRelease() of a missing release = %v, want nil", got)
+	}
+
+	for version, status := range map[int]release.Status{1: release.StatusSuperseded, 2: release.StatusDeployed, 3: release.StatusFailed} {
+		r := &release.Release{Name: "kueue", Namespace: "kueue-system", Version: version, Info: &release.Info{Status: status}}
+		if err := cfg.Releases.Create(r); err != nil {
+			t.Fatalf("failed to create release: %v", err)
+		}
+	}
+	got, err = lastRelease(cfg, "kueue")
+	if err != nil {
+		t.Fatalf("lastRelease() error = %v", err)
+	}
+	if got == nil || got.Version != 3 || got.Info.Status != release.StatusFailed {
+		t.Errorf("lastRelease() = %+v, want the failed revision 3", got)
+	}
+}
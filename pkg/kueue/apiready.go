@@ -0,0 +1,140 @@
+package kueue
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+)
+
+// DefaultAPIReadyTimeout is how long WaitForAPIReady waits by default
+const DefaultAPIReadyTimeout = 3 * time.Minute
+
+// WaitForAPIReady waits until every CRD in the cluster is Established, every admission
+// webhook backed by a Service has a ready endpoint, and the Kueue webhook admits a dry-run
+// ResourceFlavor. Objects created earlier can fail on a CRD that is not yet served or a
+// webhook that is not yet listening, e.g. right after Kueue and extensions are installed.
+// On timeout the error lists what is still pending.
+func (c *Client) WaitForAPIReady(ctx context.Context, timeout time.Duration) error {
+	fmt.Println("Waiting for CRDs and webhooks to be ready...")
+
+	var pending []string
+	var lastErr error
+	err := wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		pending, lastErr = c.pendingAPIs(ctx)
+		return lastErr == nil && len(pending) == 0, nil
+	})
+	if err != nil {
+		if lastErr != nil {
+			return fmt.Errorf("failed to check CRDs and webhooks: %w", lastErr)
+		}
+		return fmt.Errorf("%d CRD(s) or webhook(s) not ready after %s:\n  %s", len(pending), timeout, strings.Join(pending, "\n  "))
+	}
+
+	fmt.Println("✓ CRDs established and webhooks serving")
+	return nil
+}
+
+// pendingAPIs describes every CRD and webhook that is not ready yet, sorted for stable output
+func (c *Client) pendingAPIs(ctx context.Context) ([]string, error) {
+	var pending []string
+
+	crds, err := c.crdClient.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CRDs: %w", err)
+	}
+	for i := range crds.Items {
+		if !crdEstablished(&crds.Items[i]) {
+			pending = append(pending, fmt.Sprintf("CRD %s: not Established", crds.Items[i].Name))
+		}
+	}
+
+	services, err := c.webhookServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, svc := range services {
+		ready, err := c.serviceReady(ctx, svc.namespace, svc.name)
+		if err != nil {
+			return nil, err
+		}
+		if !ready {
+			pending = append(pending, fmt.Sprintf("webhook %s: service %s/%s has no ready endpoints", svc.webhook, svc.namespace, svc.name))
+		}
+	}
+
+	// The webhook path (API server → Service → Pod) is only proven by a request through it
+	probe := &kueue.ResourceFlavor{ObjectMeta: metav1.ObjectMeta{Name: "webhook-probe"}}
+	_, err = c.kueueClient.KueueV1beta2().ResourceFlavors().Create(ctx, probe, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		pending = append(pending, fmt.Sprintf("Kueue webhook: %v", err))
+	}
+
+	sort.Strings(pending)
+	return pending, nil
+}
+
+// webhookService is the Service behind an admission webhook
+type webhookService struct {
+	webhook   string
+	namespace string
+	name      string
+}
+
+// webhookServices returns the Services behind the cluster's validating and mutating
+// webhooks. Webhooks called by URL are skipped.
+func (c *Client) webhookServices(ctx context.Context) ([]webhookService, error) {
+	var services []webhookService
+	add := func(config, webhook string, cc admissionregistrationv1.WebhookClientConfig) {
+		if cc.Service != nil {
+			services = append(services, webhookService{webhook: config + "/" + webhook, namespace: cc.Service.Namespace, name: cc.Service.Name})
+		}
+	}
+
+	api := c.clientset.AdmissionregistrationV1()
+	validating, err := api.ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ValidatingWebhookConfigurations: %w", err)
+	}
+	for _, cfg := range validating.Items {
+		for _, wh := range cfg.Webhooks {
+			add(cfg.Name, wh.Name, wh.ClientConfig)
+		}
+	}
+	mutating, err := api.MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MutatingWebhookConfigurations: %w", err)
+	}
+	for _, cfg := range mutating.Items {
+		for _, wh := range cfg.Webhooks {
+			add(cfg.Name, wh.Name, wh.ClientConfig)
+		}
+	}
+	return services, nil
+}
+
+// serviceReady reports whether a Service has at least one ready endpoint
+func (c *Client) serviceReady(ctx context.Context, namespace, name string) (bool, error) {
+	slices, err := c.clientset.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + name,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to list EndpointSlices of service %s/%s: %w", namespace, name, err)
+	}
+	for _, slice := range slices.Items {
+		for _, ep := range slice.Endpoints {
+			if ep.Conditions.Ready == nil || *ep.Conditions.Ready {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
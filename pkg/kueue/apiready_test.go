@@ -0,0 +1,77 @@
+package kueue
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+	kueuefake "sigs.k8s.io/kueue/client-go/clientset/versioned/fake"
+)
+
+func TestPendingAPIs(t *testing.T) {
+	crd := func(name string, established apiextensionsv1.ConditionStatus) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{
+				Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{{Type: apiextensionsv1.Established, Status: established}},
+			},
+		}
+	}
+	webhook := func(name, namespace, service string) admissionregistrationv1.ValidatingWebhook {
+		return admissionregistrationv1.ValidatingWebhook{
+			Name: name,
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service: &admissionregistrationv1.ServiceReference{Namespace: namespace, Name: service},
+			},
+		}
+	}
+	endpoints := func(namespace, service string, ready bool) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      service + "-abcde",
+				Namespace: namespace,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: service},
+			},
+			Endpoints: []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.1"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(ready)}}},
+		}
+	}
+
+	client := &Client{
+		kueueClient: kueuefake.NewSimpleClientset(),
+		crdClient: apiextensionsfake.NewSimpleClientset(
+			crd("clusterqueues.kueue.x-k8s.io", apiextensionsv1.ConditionTrue),
+			crd("jobsets.jobset.x-k8s.io", apiextensionsv1.ConditionFalse),
+		),
+		clientset: k8sfake.NewSimpleClientset(
+			&admissionregistrationv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "kueue-validating-webhook-configuration"},
+				Webhooks:   []admissionregistrationv1.ValidatingWebhook{webhook("vclusterqueue.kb.io", "kueue-system", "kueue-webhook-service")},
+			},
+			&admissionregistrationv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{Name: "jobset-validating-webhook-configuration"},
+				Webhooks:   []admissionregistrationv1.ValidatingWebhook{webhook("vjobset.kb.io", "jobset-system", "jobset-webhook-service")},
+			},
+			endpoints("kueue-system", "kueue-webhook-service", true),
+			endpoints("jobset-system", "jobset-webhook-service", false),
+		),
+	}
+
+	pending, err := client.pendingAPIs(context.TODO())
+	if err != nil {
+		t.Fatalf("pendingAPIs() error = %v", err)
+	}
+	want := []string{
+		"CRD jobsets.jobset.x-k8s.io: not Established",
+		"webhook jobset-validating-webhook-configuration/vjobset.kb.io: service jobset-system/jobset-webhook-service has no ready endpoints",
+	}
+	if !reflect.DeepEqual(pending, want) {
+		t.Errorf("pendingAPIs() =\n%v\nwant\n%v", pending, want)
+	}
+}
@@ -0,0 +1,163 @@
+package kueue
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+)
+
+// Kinds of MultiKueue workload inconsistencies
+const (
+	// InconsistencyMissing is an admitted management Workload without a copy on the worker
+	// it was dispatched to
+	InconsistencyMissing = "missing"
+	// InconsistencyDuplicate is an admitted management Workload with copies on several workers
+	InconsistencyDuplicate = "duplicate"
+	// InconsistencyJobMissing is a dispatched Workload whose Job was not created on the worker
+	InconsistencyJobMissing = "job missing"
+	// InconsistencyOrphaned is a worker Workload whose management Workload no longer exists
+	InconsistencyOrphaned = "orphaned"
+)
+
+// ClusterWorkloads are the Workloads and batch Jobs in a cluster
+type ClusterWorkloads struct {
+	Workloads []kueue.Workload
+	// Jobs holds the batch Jobs by "namespace/name"
+	Jobs map[string]bool
+}
+
+// WorkloadInconsistency is a management Workload whose worker copies do not match what
+// MultiKueue should have created, or a worker copy left without its management Workload
+type WorkloadInconsistency struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Workers are the workers holding a copy of the Workload
+	Workers []string
+	Reason  string
+}
+
+// ClusterWorkloads lists the cluster's Workloads and batch Jobs
+func (c *Client) ClusterWorkloads(ctx context.Context) (ClusterWorkloads, error) {
+	workloads, err := c.kueueClient.KueueV1beta2().Workloads(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return ClusterWorkloads{}, fmt.Errorf("failed to list Workloads: %w", err)
+	}
+	jobs, err := c.clientset.BatchV1().Jobs(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return ClusterWorkloads{}, fmt.Errorf("failed to list Jobs: %w", err)
+	}
+
+	cw := ClusterWorkloads{Workloads: workloads.Items, Jobs: make(map[string]bool, len(jobs.Items))}
+	for _, job := range jobs.Items {
+		cw.Jobs[job.Namespace+"/"+job.Name] = true
+	}
+	return cw, nil
+}
+
+// AuditMultiKueueWorkloads checks that every admitted, unfinished management Workload has
+// exactly one copy, on the worker it was dispatched to, with its Job if it is owned by one,
+// and that no worker holds a copy whose management Workload is gone. clusterWorkers maps
+// MultiKueueCluster names to worker names. Results are sorted by namespace, name and kind.
+func AuditMultiKueueWorkloads(management ClusterWorkloads, workers map[string]ClusterWorkloads, clusterWorkers map[string]string) []WorkloadInconsistency {
+	// Remote copies are labeled with their origin; their UID annotation pins the original
+	type remote struct {
+		worker    string
+		originUID string
+	}
+	copies := make(map[string][]remote)
+	workerNames := make([]string, 0, len(workers))
+	for name := range workers {
+		workerNames = append(workerNames, name)
+	}
+	sort.Strings(workerNames)
+	for _, worker := range workerNames {
+		for _, wl := range workers[worker].Workloads {
+			if _, ok := wl.Labels[kueue.MultiKueueOriginLabel]; !ok {
+				continue
+			}
+			key := wl.Namespace + "/" + wl.Name
+			copies[key] = append(copies[key], remote{worker: worker, originUID: wl.Annotations[kueue.MultiKueueOriginUIDAnnotation]})
+		}
+	}
+
+	var found []WorkloadInconsistency
+	origins := make(map[string]string, len(management.Workloads))
+	for _, wl := range management.Workloads {
+		key := wl.Namespace + "/" + wl.Name
+		origins[key] = string(wl.UID)
+
+		if !apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadAdmitted) ||
+			apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadFinished) {
+			continue
+		}
+		inconsistency := func(kind, reason string) WorkloadInconsistency {
+			w := WorkloadInconsistency{Kind: kind, Namespace: wl.Namespace, Name: wl.Name, Reason: reason}
+			for _, r := range copies[key] {
+				w.Workers = append(w.Workers, r.worker)
+			}
+			return w
+		}
+
+		if wl.Status.ClusterName == nil {
+			found = append(found, inconsistency(InconsistencyMissing, "admitted without a dispatched worker"))
+			continue
+		}
+		worker, ok := clusterWorkers[*wl.Status.ClusterName]
+		if !ok {
+			found = append(found, inconsistency(InconsistencyMissing, fmt.Sprintf("dispatched to unknown MultiKueueCluster %q", *wl.Status.ClusterName)))
+			continue
+		}
+		if len(copies[key]) > 1 {
+			found = append(found, inconsistency(InconsistencyDuplicate, fmt.Sprintf("%d copies on workers", len(copies[key]))))
+		}
+		dispatched := false
+		for _, r := range copies[key] {
+			dispatched = dispatched || r.worker == worker
+		}
+		if !dispatched {
+			found = append(found, inconsistency(InconsistencyMissing, fmt.Sprintf("no copy on worker %q it was dispatched to", worker)))
+			continue
+		}
+		if owner := metav1.GetControllerOf(&wl); owner != nil && owner.Kind == "Job" && !workers[worker].Jobs[wl.Namespace+"/"+owner.Name] {
+			found = append(found, inconsistency(InconsistencyJobMissing, fmt.Sprintf("Job %s not found on worker %q", owner.Name, worker)))
+		}
+	}
+
+	for key, remotes := range copies {
+		originUID, exists := origins[key]
+		for _, r := range remotes {
+			var reason string
+			switch {
+			case !exists:
+				reason = "management Workload not found"
+			case r.originUID != "" && r.originUID != originUID:
+				reason = "management Workload was recreated"
+			default:
+				continue
+			}
+			namespace, name, _ := strings.Cut(key, "/")
+			found = append(found, WorkloadInconsistency{Kind: InconsistencyOrphaned, Namespace: namespace, Name: name, Workers: []string{r.worker}, Reason: reason})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		a, b := found[i], found[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return fmt.Sprint(a.Workers) < fmt.Sprint(b.Workers)
+	})
+	return found
+}
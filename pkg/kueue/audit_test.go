@@ -0,0 +1,81 @@
+package kueue
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+)
+
+func TestAuditMultiKueueWorkloads(t *testing.T) {
+	isController := true
+	admitted := []metav1.Condition{{Type: kueue.WorkloadAdmitted, Status: metav1.ConditionTrue}}
+	finished := append([]metav1.Condition{{Type: kueue.WorkloadFinished, Status: metav1.ConditionTrue}}, admitted...)
+
+	origin := func(name, uid string) kueue.Workload {
+		return kueue.Workload{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			UID:       types.UID(uid),
+			OwnerReferences: []metav1.OwnerReference{{
+				Kind: "Job", Name: "job-" + name, Controller: &isController,
+			}},
+		}}
+	}
+	dispatched := func(name, uid, cluster string, conditions []metav1.Condition) kueue.Workload {
+		wl := origin(name, uid)
+		wl.Status.Conditions = conditions
+		wl.Status.ClusterName = &cluster
+		return wl
+	}
+	remote := func(name, originUID string) kueue.Workload {
+		wl := origin(name, "remote-"+name)
+		wl.Labels = map[string]string{kueue.MultiKueueOriginLabel: "multikueue"}
+		wl.Annotations = map[string]string{kueue.MultiKueueOriginUIDAnnotation: originUID}
+		return wl
+	}
+
+	management := ClusterWorkloads{Workloads: []kueue.Workload{
+		dispatched("ok", "uid-ok", "ws-worker-1", admitted),
+		dispatched("duplicated", "uid-duplicated", "ws-worker-1", admitted),
+		dispatched("lost", "uid-lost", "ws-worker-2", admitted),
+		dispatched("no-job", "uid-no-job", "ws-worker-2", admitted),
+		dispatched("done", "uid-done", "ws-worker-1", finished),
+		dispatched("recreated", "uid-new", "ws-worker-1", nil),
+		origin("queued", "uid-queued"),
+	}}
+	workers := map[string]ClusterWorkloads{
+		"worker-1": {
+			Workloads: []kueue.Workload{
+				remote("ok", "uid-ok"),
+				remote("duplicated", "uid-duplicated"),
+				remote("recreated", "uid-old"),
+				remote("deleted", "uid-deleted"),
+				// Not created by MultiKueue
+				origin("local", "uid-local"),
+			},
+			Jobs: map[string]bool{"default/job-ok": true, "default/job-duplicated": true},
+		},
+		"worker-2": {
+			Workloads: []kueue.Workload{
+				remote("duplicated", "uid-duplicated"),
+				remote("no-job", "uid-no-job"),
+			},
+		},
+	}
+	clusterWorkers := map[string]string{"ws-worker-1": "worker-1", "ws-worker-2": "worker-2"}
+
+	got := AuditMultiKueueWorkloads(management, workers, clusterWorkers)
+	want := []WorkloadInconsistency{
+		{Kind: InconsistencyOrphaned, Namespace: "default", Name: "deleted", Workers: []string{"worker-1"}, Reason: "management Workload not found"},
+		{Kind: InconsistencyDuplicate, Namespace: "default", Name: "duplicated", Workers: []string{"worker-1", "worker-2"}, Reason: "2 copies on workers"},
+		{Kind: InconsistencyMissing, Namespace: "default", Name: "lost", Reason: `no copy on worker "worker-2" it was dispatched to`},
+		{Kind: InconsistencyJobMissing, Namespace: "default", Name: "no-job", Workers: []string{"worker-2"}, Reason: `Job job-no-job not found on worker "worker-2"`},
+		{Kind: InconsistencyOrphaned, Namespace: "default", Name: "recreated", Workers: []string{"worker-1"}, Reason: "management Workload was recreated"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AuditMultiKueueWorkloads() =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -1,6 +1,8 @@
 package kueue
 
 import (
+	"fmt"
+
 	"github.com/jhwagner/kueue-bench/pkg/config"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -9,7 +11,7 @@ import (
 )
 
 // BuildCohort builds a Kueue Cohort from a config Cohort
-func BuildCohort(c config.Cohort) *kueue.Cohort {
+func BuildCohort(c config.Cohort) (*kueue.Cohort, error) {
 	spec := kueue.CohortSpec{}
 
 	// Set parent name if present
@@ -19,33 +21,62 @@ func BuildCohort(c config.Cohort) *kueue.Cohort {
 
 	// Build resource groups if present
 	if len(c.ResourceGroups) > 0 {
-		spec.ResourceGroups = buildResourceGroups(c.ResourceGroups)
+		groups, err := buildResourceGroups(c.ResourceGroups)
+		if err != nil {
+			return nil, fmt.Errorf("cohort %s: %w", c.Name, err)
+		}
+		spec.ResourceGroups = groups
 	}
 
 	// Build fair sharing if present
 	if c.FairSharing != nil {
-		spec.FairSharing = buildFairSharing(c.FairSharing)
+		fairSharing, err := buildFairSharing(c.FairSharing)
+		if err != nil {
+			return nil, fmt.Errorf("cohort %s: %w", c.Name, err)
+		}
+		spec.FairSharing = fairSharing
 	}
 
 	return &kueue.Cohort{
 		TypeMeta:   metav1.TypeMeta{APIVersion: kueue.SchemeGroupVersion.String(), Kind: "Cohort"},
 		ObjectMeta: metav1.ObjectMeta{Name: c.Name},
 		Spec:       spec,
-	}
+	}, nil
 }
 
-// buildFairSharing builds FairSharing from config
-func buildFairSharing(fs *config.FairSharing) *kueue.FairSharing {
-	// Convert int32 weight to resource.Quantity
-	weight := resource.NewQuantity(int64(fs.Weight), resource.DecimalSI)
+// buildFairSharing builds FairSharing from config. An unset weight is left for Kueue to default.
+func buildFairSharing(fs *config.FairSharing) (*kueue.FairSharing, error) {
+	if fs.Weight == "" {
+		return &kueue.FairSharing{}, nil
+	}
+	weight, err := resource.ParseQuantity(fs.Weight)
+	if err != nil {
+		return nil, fmt.Errorf("fairSharing: invalid weight '%s': %w", fs.Weight, err)
+	}
 	return &kueue.FairSharing{
-		Weight: weight,
+		Weight: &weight,
+	}, nil
+}
+
+// BuildTopology builds a Kueue TAS Topology from a config KueueTopology
+func BuildTopology(t config.KueueTopology) *kueue.Topology {
+	levels := make([]kueue.TopologyLevel, 0, len(t.Levels))
+	for _, label := range t.Levels {
+		levels = append(levels, kueue.TopologyLevel{NodeLabel: label})
+	}
+
+	return &kueue.Topology{
+		TypeMeta:   metav1.TypeMeta{APIVersion: kueue.SchemeGroupVersion.String(), Kind: "Topology"},
+		ObjectMeta: metav1.ObjectMeta{Name: t.Name},
+		Spec: kueue.TopologySpec{
+			Levels: levels,
+		},
 	}
 }
 
 // BuildResourceFlavor builds a Kueue ResourceFlavor from a config ResourceFlavor
 func BuildResourceFlavor(rf config.ResourceFlavor) *kueue.ResourceFlavor {
-	return &kueue.ResourceFlavor{
+	flavor := &kueue.ResourceFlavor{
 		TypeMeta:   metav1.TypeMeta{APIVersion: kueue.SchemeGroupVersion.String(), Kind: "ResourceFlavor"},
 		ObjectMeta: metav1.ObjectMeta{Name: rf.Name},
 		Spec: kueue.ResourceFlavorSpec{
@@ -53,16 +84,28 @@ func BuildResourceFlavor(rf config.ResourceFlavor) *kueue.ResourceFlavor {
 			Tolerations: rf.Tolerations,
 		},
 	}
+
+	if rf.TopologyName != "" {
+		topologyName := kueue.TopologyReference(rf.TopologyName)
+		flavor.Spec.TopologyName = &topologyName
+	}
+
+	return flavor
 }
 
 // BuildClusterQueue builds a Kueue ClusterQueue from a config ClusterQueue
-func BuildClusterQueue(cq config.ClusterQueue) *kueue.ClusterQueue {
+func BuildClusterQueue(cq config.ClusterQueue) (*kueue.ClusterQueue, error) {
+	groups, err := buildResourceGroups(cq.ResourceGroups)
+	if err != nil {
+		return nil, fmt.Errorf("clusterQueue %s: %w", cq.Name, err)
+	}
+
 	kueueCQ := &kueue.ClusterQueue{
 		TypeMeta:   metav1.TypeMeta{APIVersion: kueue.SchemeGroupVersion.String(), Kind: "ClusterQueue"},
 		ObjectMeta: metav1.ObjectMeta{Name: cq.Name},
 		Spec: kueue.ClusterQueueSpec{
 			CohortName:     kueue.CohortReference(cq.Cohort),
-			ResourceGroups: buildResourceGroups(cq.ResourceGroups),
+			ResourceGroups: groups,
 		},
 	}
 
@@ -89,10 +132,14 @@ func BuildClusterQueue(cq config.ClusterQueue) *kueue.ClusterQueue {
 
 	// Build fair sharing if present
 	if cq.FairSharing != nil {
-		kueueCQ.Spec.FairSharing = buildFairSharing(cq.FairSharing)
+		fairSharing, err := buildFairSharing(cq.FairSharing)
+		if err != nil {
+			return nil, fmt.Errorf("clusterQueue %s: %w", cq.Name, err)
+		}
+		kueueCQ.Spec.FairSharing = fairSharing
 	}
 
-	return kueueCQ
+	return kueueCQ, nil
 }
 
 // buildPreemptionConfig builds ClusterQueuePreemption from config
@@ -125,15 +172,19 @@ func buildPreemptionConfig(pc *config.PreemptionConfig) *kueue.ClusterQueuePreem
 }
 
 // buildResourceGroups builds ResourceGroups from config
-func buildResourceGroups(groups []config.ResourceGroup) []kueue.ResourceGroup {
+func buildResourceGroups(groups []config.ResourceGroup) ([]kueue.ResourceGroup, error) {
 	result := make([]kueue.ResourceGroup, len(groups))
 	for i, group := range groups {
+		flavors, err := buildFlavors(group.Flavors)
+		if err != nil {
+			return nil, fmt.Errorf("resourceGroup[%d]: %w", i, err)
+		}
 		result[i] = kueue.ResourceGroup{
 			CoveredResources: buildCoveredResources(group.CoveredResources),
-			Flavors:          buildFlavors(group.Flavors),
+			Flavors:          flavors,
 		}
 	}
-	return result
+	return result, nil
 }
 
 // buildCoveredResources builds ResourceName slice from covered resources
@@ -146,57 +197,101 @@ func buildCoveredResources(resources []string) []corev1.ResourceName {
 }
 
 // buildFlavors builds flavor quotas
-func buildFlavors(flavors []config.FlavorQuotas) []kueue.FlavorQuotas {
+func buildFlavors(flavors []config.FlavorQuotas) ([]kueue.FlavorQuotas, error) {
 	result := make([]kueue.FlavorQuotas, len(flavors))
 	for i, flavor := range flavors {
+		resources, err := buildResources(flavor.Resources)
+		if err != nil {
+			return nil, fmt.Errorf("flavor[%d] (%s): %w", i, flavor.Name, err)
+		}
 		result[i] = kueue.FlavorQuotas{
 			Name:      kueue.ResourceFlavorReference(flavor.Name),
-			Resources: buildResources(flavor.Resources),
+			Resources: resources,
 		}
 	}
-	return result
+	return result, nil
 }
 
 // buildResources builds resource quotas
-func buildResources(resources []config.Resource) []kueue.ResourceQuota {
+func buildResources(resources []config.Resource) ([]kueue.ResourceQuota, error) {
 	result := make([]kueue.ResourceQuota, len(resources))
 	for i, res := range resources {
+		nominalQuota, err := parseQuota(res.NominalQuota, "nominalQuota")
+		if err != nil {
+			return nil, fmt.Errorf("resource[%d] (%s): %w", i, res.Name, err)
+		}
 		quota := kueue.ResourceQuota{
 			Name:         corev1.ResourceName(res.Name),
-			NominalQuota: resource.MustParse(res.NominalQuota),
+			NominalQuota: nominalQuota,
 		}
 
 		// Build optional borrowing limit
 		if res.BorrowingLimit != "" {
-			borrowingLimit := resource.MustParse(res.BorrowingLimit)
+			borrowingLimit, err := parseQuota(res.BorrowingLimit, "borrowingLimit")
+			if err != nil {
+				return nil, fmt.Errorf("resource[%d] (%s): %w", i, res.Name, err)
+			}
 			quota.BorrowingLimit = &borrowingLimit
 		}
 
 		// Build optional lending limit
 		if res.LendingLimit != "" {
-			lendingLimit := resource.MustParse(res.LendingLimit)
+			lendingLimit, err := parseQuota(res.LendingLimit, "lendingLimit")
+			if err != nil {
+				return nil, fmt.Errorf("resource[%d] (%s): %w", i, res.Name, err)
+			}
 			quota.LendingLimit = &lendingLimit
 		}
 
 		result[i] = quota
 	}
-	return result
+	return result, nil
+}
+
+// parseQuota parses a quota quantity, naming the field on error
+func parseQuota(value, field string) (resource.Quantity, error) {
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("invalid %s '%s': %w", field, value, err)
+	}
+	return quantity, nil
 }
 
 // BuildLocalQueue builds a Kueue LocalQueue from a config LocalQueue
-func BuildLocalQueue(lq config.LocalQueue) *kueue.LocalQueue {
-	namespace := lq.Namespace
-	if namespace == "" {
-		namespace = "default"
+func BuildLocalQueue(lq config.LocalQueue) (*kueue.LocalQueue, error) {
+	namespace := localQueueNamespace(lq)
+
+	spec := kueue.LocalQueueSpec{
+		ClusterQueue: kueue.ClusterQueueReference(lq.ClusterQueue),
+	}
+
+	if lq.StopPolicy != "" {
+		stopPolicy := kueue.StopPolicy(lq.StopPolicy)
+		spec.StopPolicy = &stopPolicy
+	}
+
+	// Build fair sharing if present (used by AdmissionFairSharing)
+	if lq.FairSharing != nil {
+		fairSharing, err := buildFairSharing(lq.FairSharing)
+		if err != nil {
+			return nil, fmt.Errorf("localQueue %s/%s: %w", namespace, lq.Name, err)
+		}
+		spec.FairSharing = fairSharing
 	}
 
 	return &kueue.LocalQueue{
 		TypeMeta:   metav1.TypeMeta{APIVersion: kueue.SchemeGroupVersion.String(), Kind: "LocalQueue"},
 		ObjectMeta: metav1.ObjectMeta{Name: lq.Name, Namespace: namespace},
-		Spec: kueue.LocalQueueSpec{
-			ClusterQueue: kueue.ClusterQueueReference(lq.ClusterQueue),
-		},
+		Spec:       spec,
+	}, nil
+}
+
+// localQueueNamespace returns the LocalQueue's namespace, defaulting to "default"
+func localQueueNamespace(lq config.LocalQueue) string {
+	if lq.Namespace == "" {
+		return "default"
 	}
+	return lq.Namespace
 }
 
 // BuildWorkloadPriorityClass builds a Kueue WorkloadPriorityClass from a config WorkloadPriorityClass
@@ -236,8 +331,8 @@ func BuildMultiKueueConfig(name string, clusterNames []string) *kueue.MultiKueue
 	}
 }
 
-// BuildAdmissionCheck builds a Kueue AdmissionCheck for MultiKueue
-func BuildAdmissionCheck(name, configName string) *kueue.AdmissionCheck {
+// BuildMultiKueueAdmissionCheck builds a Kueue AdmissionCheck for MultiKueue
+func BuildMultiKueueAdmissionCheck(name, configName string) *kueue.AdmissionCheck {
 	return &kueue.AdmissionCheck{
 		TypeMeta:   metav1.TypeMeta{APIVersion: kueue.SchemeGroupVersion.String(), Kind: "AdmissionCheck"},
 		ObjectMeta: metav1.ObjectMeta{Name: name},
@@ -251,3 +346,57 @@ func BuildAdmissionCheck(name, configName string) *kueue.AdmissionCheck {
 		},
 	}
 }
+
+// BuildAdmissionCheck builds a Kueue AdmissionCheck from a config AdmissionCheck
+func BuildAdmissionCheck(ac config.AdmissionCheck) *kueue.AdmissionCheck {
+	spec := kueue.AdmissionCheckSpec{ControllerName: ac.ControllerName}
+
+	if p := ac.Parameters; p != nil {
+		apiGroup := p.APIGroup
+		if apiGroup == "" {
+			apiGroup = kueue.SchemeGroupVersion.Group
+		}
+		spec.Parameters = &kueue.AdmissionCheckParametersReference{
+			APIGroup: apiGroup,
+			Kind:     p.Kind,
+			Name:     p.Name,
+		}
+	}
+
+	return &kueue.AdmissionCheck{
+		TypeMeta:   metav1.TypeMeta{APIVersion: kueue.SchemeGroupVersion.String(), Kind: "AdmissionCheck"},
+		ObjectMeta: metav1.ObjectMeta{Name: ac.Name},
+		Spec:       spec,
+	}
+}
+
+// BuildProvisioningRequestConfig builds a Kueue ProvisioningRequestConfig from config
+func BuildProvisioningRequestConfig(prc config.ProvisioningRequestConfig) *kueue.ProvisioningRequestConfig {
+	spec := kueue.ProvisioningRequestConfigSpec{ProvisioningClassName: prc.ProvisioningClassName}
+
+	if len(prc.Parameters) > 0 {
+		spec.Parameters = make(map[string]kueue.Parameter, len(prc.Parameters))
+		for k, v := range prc.Parameters {
+			spec.Parameters[k] = kueue.Parameter(v)
+		}
+	}
+
+	for _, r := range prc.ManagedResources {
+		spec.ManagedResources = append(spec.ManagedResources, corev1.ResourceName(r))
+	}
+
+	// Unset retry fields are defaulted by Kueue
+	if r := prc.RetryStrategy; r != nil {
+		spec.RetryStrategy = &kueue.ProvisioningRequestRetryStrategy{
+			BackoffLimitCount:  r.BackoffLimitCount,
+			BackoffBaseSeconds: r.BackoffBaseSeconds,
+			BackoffMaxSeconds:  r.BackoffMaxSeconds,
+		}
+	}
+
+	return &kueue.ProvisioningRequestConfig{
+		TypeMeta:   metav1.TypeMeta{APIVersion: kueue.SchemeGroupVersion.String(), Kind: "ProvisioningRequestConfig"},
+		ObjectMeta: metav1.ObjectMeta{Name: prc.Name},
+		Spec:       spec,
+	}
+}
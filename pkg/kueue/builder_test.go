@@ -1,6 +1,8 @@
 package kueue
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/jhwagner/kueue-bench/pkg/config"
@@ -50,7 +52,7 @@ func TestBuildCohort(t *testing.T) {
 				Name:       "team-a",
 				ParentName: "platform",
 				FairSharing: &config.FairSharing{
-					Weight: 2,
+					Weight: "2",
 				},
 			},
 			checkFn: func(t *testing.T, cohort *kueue.Cohort) {
@@ -72,7 +74,7 @@ func TestBuildCohort(t *testing.T) {
 				Name:       "low-priority",
 				ParentName: "platform",
 				FairSharing: &config.FairSharing{
-					Weight: 0,
+					Weight: "0",
 				},
 			},
 			checkFn: func(t *testing.T, cohort *kueue.Cohort) {
@@ -88,6 +90,33 @@ func TestBuildCohort(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "cohort with decimal weight",
+			input: config.Cohort{
+				Name:        "fractional",
+				FairSharing: &config.FairSharing{Weight: "0.5"},
+			},
+			checkFn: func(t *testing.T, cohort *kueue.Cohort) {
+				if cohort.Spec.FairSharing == nil || cohort.Spec.FairSharing.Weight == nil {
+					t.Fatal("expected Weight to be set")
+				}
+				if cohort.Spec.FairSharing.Weight.Cmp(resource.MustParse("500m")) != 0 {
+					t.Errorf("expected weight 0.5, got %v", cohort.Spec.FairSharing.Weight)
+				}
+			},
+		},
+		{
+			name: "cohort with unset weight leaves Kueue default",
+			input: config.Cohort{
+				Name:        "defaulted",
+				FairSharing: &config.FairSharing{},
+			},
+			checkFn: func(t *testing.T, cohort *kueue.Cohort) {
+				if cohort.Spec.FairSharing == nil || cohort.Spec.FairSharing.Weight != nil {
+					t.Errorf("expected FairSharing without Weight, got %+v", cohort.Spec.FairSharing)
+				}
+			},
+		},
 		{
 			name: "cohort with resource groups",
 			input: config.Cohort{
@@ -126,12 +155,63 @@ func TestBuildCohort(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := BuildCohort(tt.input)
+			result, err := BuildCohort(tt.input)
+			if err != nil {
+				t.Fatalf("BuildCohort() error = %v", err)
+			}
 			tt.checkFn(t, result)
 		})
 	}
 }
 
+func TestBuildCohortResourceGroupParity(t *testing.T) {
+	groups := []config.ResourceGroup{
+		{
+			CoveredResources: []string{"cpu", "memory"},
+			Flavors: []config.FlavorQuotas{
+				{Name: "on-demand", Resources: []config.Resource{
+					{Name: "cpu", NominalQuota: "16", LendingLimit: "8"},
+					{Name: "memory", NominalQuota: "64Gi"},
+				}},
+				{Name: "spot", Resources: []config.Resource{
+					{Name: "cpu", NominalQuota: "32", BorrowingLimit: "0"},
+					{Name: "memory", NominalQuota: "128Gi", BorrowingLimit: "64Gi", LendingLimit: "0"},
+				}},
+			},
+		},
+		{
+			CoveredResources: []string{"nvidia.com/gpu"},
+			Flavors: []config.FlavorQuotas{{Name: "gpu", Resources: []config.Resource{
+				{Name: "nvidia.com/gpu", NominalQuota: "8", BorrowingLimit: "4"},
+			}}},
+		},
+	}
+
+	cohort, err := BuildCohort(config.Cohort{Name: "team", ParentName: "root", ResourceGroups: groups})
+	if err != nil {
+		t.Fatalf("BuildCohort() error = %v", err)
+	}
+	cq, err := BuildClusterQueue(config.ClusterQueue{Name: "team-cq", Cohort: "team", ResourceGroups: groups})
+	if err != nil {
+		t.Fatalf("BuildClusterQueue() error = %v", err)
+	}
+	if !reflect.DeepEqual(cohort.Spec.ResourceGroups, cq.Spec.ResourceGroups) {
+		t.Errorf("cohort resource groups differ from ClusterQueue:\ncohort: %+v\ncq:     %+v", cohort.Spec.ResourceGroups, cq.Spec.ResourceGroups)
+	}
+
+	spot := cohort.Spec.ResourceGroups[0].Flavors[1].Resources[1]
+	if spot.BorrowingLimit == nil || !spot.BorrowingLimit.Equal(resource.MustParse("64Gi")) {
+		t.Errorf("expected spot memory borrowingLimit 64Gi, got %v", spot.BorrowingLimit)
+	}
+	if spot.LendingLimit == nil || !spot.LendingLimit.IsZero() {
+		t.Errorf("expected spot memory lendingLimit 0, got %v", spot.LendingLimit)
+	}
+	onDemand := cohort.Spec.ResourceGroups[0].Flavors[0].Resources[1]
+	if onDemand.BorrowingLimit != nil || onDemand.LendingLimit != nil {
+		t.Errorf("expected no limits on on-demand memory, got %+v", onDemand)
+	}
+}
+
 func TestBuildResourceFlavor(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -179,6 +259,19 @@ func TestBuildResourceFlavor(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "resource flavor with topology",
+			input: config.ResourceFlavor{
+				Name:         "tas-flavor",
+				NodeLabels:   map[string]string{"node-type": "gpu"},
+				TopologyName: "datacenter",
+			},
+			checkFn: func(t *testing.T, rf *kueue.ResourceFlavor) {
+				if rf.Spec.TopologyName == nil || *rf.Spec.TopologyName != "datacenter" {
+					t.Errorf("expected topologyName 'datacenter', got %v", rf.Spec.TopologyName)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -189,6 +282,26 @@ func TestBuildResourceFlavor(t *testing.T) {
 	}
 }
 
+func TestBuildTopology(t *testing.T) {
+	topology := BuildTopology(config.KueueTopology{
+		Name:   "datacenter",
+		Levels: []string{"topology.kubernetes.io/zone", "kubernetes.io/hostname"},
+	})
+
+	if topology.Name != "datacenter" {
+		t.Errorf("expected name 'datacenter', got '%s'", topology.Name)
+	}
+	if len(topology.Spec.Levels) != 2 {
+		t.Fatalf("expected 2 levels, got %d", len(topology.Spec.Levels))
+	}
+	if topology.Spec.Levels[0].NodeLabel != "topology.kubernetes.io/zone" {
+		t.Errorf("expected first level 'topology.kubernetes.io/zone', got '%s'", topology.Spec.Levels[0].NodeLabel)
+	}
+	if topology.Spec.Levels[1].NodeLabel != "kubernetes.io/hostname" {
+		t.Errorf("expected second level 'kubernetes.io/hostname', got '%s'", topology.Spec.Levels[1].NodeLabel)
+	}
+}
+
 func TestBuildClusterQueue(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -323,7 +436,7 @@ func TestBuildClusterQueue(t *testing.T) {
 				Name:   "team-a-cq",
 				Cohort: "platform",
 				FairSharing: &config.FairSharing{
-					Weight: 2,
+					Weight: "2",
 				},
 				ResourceGroups: []config.ResourceGroup{
 					{
@@ -362,7 +475,7 @@ func TestBuildClusterQueue(t *testing.T) {
 				Cohort:          "platform",
 				AdmissionChecks: []string{"multikueue-ac"},
 				FairSharing: &config.FairSharing{
-					Weight: 3,
+					Weight: "3",
 				},
 				ResourceGroups: []config.ResourceGroup{
 					{
@@ -405,12 +518,64 @@ func TestBuildClusterQueue(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := BuildClusterQueue(tt.input)
+			result, err := BuildClusterQueue(tt.input)
+			if err != nil {
+				t.Fatalf("BuildClusterQueue() error = %v", err)
+			}
 			tt.checkFn(t, result)
 		})
 	}
 }
 
+func TestBuildInvalidQuantities(t *testing.T) {
+	rg := func(quota string) []config.ResourceGroup {
+		return []config.ResourceGroup{{
+			CoveredResources: []string{"cpu"},
+			Flavors:          []config.FlavorQuotas{{Name: "default", Resources: []config.Resource{{Name: "cpu", NominalQuota: "10", LendingLimit: quota}}}},
+		}}
+	}
+
+	tests := []struct {
+		name        string
+		build       func() error
+		errContains string
+	}{
+		{
+			name: "cluster queue quota",
+			build: func() error {
+				_, err := BuildClusterQueue(config.ClusterQueue{Name: "cq", ResourceGroups: rg("lots")})
+				return err
+			},
+			errContains: "clusterQueue cq: resourceGroup[0]: flavor[0] (default): resource[0] (cpu): invalid lendingLimit 'lots'",
+		},
+		{
+			name: "cohort weight",
+			build: func() error {
+				_, err := BuildCohort(config.Cohort{Name: "root", FairSharing: &config.FairSharing{Weight: "heavy"}})
+				return err
+			},
+			errContains: "cohort root: fairSharing: invalid weight 'heavy'",
+		},
+		{
+			name: "local queue weight",
+			build: func() error {
+				_, err := BuildLocalQueue(config.LocalQueue{Name: "lq", ClusterQueue: "cq", FairSharing: &config.FairSharing{Weight: "x"}})
+				return err
+			},
+			errContains: "localQueue default/lq: fairSharing: invalid weight 'x'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.build()
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("error = %v, expected to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
 func TestBuildLocalQueue(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -446,13 +611,37 @@ func TestBuildLocalQueue(t *testing.T) {
 				if lq.Namespace != "default" {
 					t.Errorf("expected namespace 'default', got '%s'", lq.Namespace)
 				}
+				if lq.Spec.StopPolicy != nil || lq.Spec.FairSharing != nil {
+					t.Errorf("expected no stopPolicy or fairSharing, got %+v", lq.Spec)
+				}
+			},
+		},
+		{
+			name: "local queue with stop policy and fair sharing",
+			input: config.LocalQueue{
+				Name:         "team-queue",
+				Namespace:    "team-a",
+				ClusterQueue: "main-queue",
+				StopPolicy:   config.StopPolicyHold,
+				FairSharing:  &config.FairSharing{Weight: "3"},
+			},
+			checkFn: func(t *testing.T, lq *kueue.LocalQueue) {
+				if lq.Spec.StopPolicy == nil || *lq.Spec.StopPolicy != kueue.Hold {
+					t.Errorf("expected stopPolicy Hold, got %v", lq.Spec.StopPolicy)
+				}
+				if lq.Spec.FairSharing == nil || lq.Spec.FairSharing.Weight.Value() != 3 {
+					t.Errorf("expected fairSharing weight 3, got %+v", lq.Spec.FairSharing)
+				}
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := BuildLocalQueue(tt.input)
+			result, err := BuildLocalQueue(tt.input)
+			if err != nil {
+				t.Fatalf("BuildLocalQueue() error = %v", err)
+			}
 			tt.checkFn(t, result)
 		})
 	}
@@ -636,7 +825,7 @@ func TestBuildMultiKueueConfig(t *testing.T) {
 	}
 }
 
-func TestBuildAdmissionCheck(t *testing.T) {
+func TestBuildMultiKueueAdmissionCheck(t *testing.T) {
 	tests := []struct {
 		name       string
 		checkName  string
@@ -672,8 +861,56 @@ func TestBuildAdmissionCheck(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := BuildAdmissionCheck(tt.checkName, tt.configName)
+			result := BuildMultiKueueAdmissionCheck(tt.checkName, tt.configName)
 			tt.checkFn(t, result)
 		})
 	}
 }
+
+func TestBuildAdmissionCheck(t *testing.T) {
+	ac := BuildAdmissionCheck(config.AdmissionCheck{
+		Name:           "prov",
+		ControllerName: kueue.ProvisioningRequestControllerName,
+		Parameters:     &config.AdmissionCheckParameters{Kind: "ProvisioningRequestConfig", Name: "prov-config"},
+	})
+	if ac.Name != "prov" || ac.Spec.ControllerName != kueue.ProvisioningRequestControllerName {
+		t.Errorf("unexpected admission check: %+v", ac)
+	}
+	want := &kueue.AdmissionCheckParametersReference{
+		APIGroup: kueue.SchemeGroupVersion.Group,
+		Kind:     "ProvisioningRequestConfig",
+		Name:     "prov-config",
+	}
+	if !reflect.DeepEqual(ac.Spec.Parameters, want) {
+		t.Errorf("expected parameters %+v, got %+v", want, ac.Spec.Parameters)
+	}
+
+	if ac := BuildAdmissionCheck(config.AdmissionCheck{Name: "custom", ControllerName: "example.com/check"}); ac.Spec.Parameters != nil {
+		t.Errorf("expected no parameters, got %+v", ac.Spec.Parameters)
+	}
+}
+
+func TestBuildProvisioningRequestConfig(t *testing.T) {
+	limit := int32(1)
+	prc := BuildProvisioningRequestConfig(config.ProvisioningRequestConfig{
+		Name:                  "prov-config",
+		ProvisioningClassName: "check-capacity.autoscaling.x-k8s.io",
+		Parameters:            map[string]string{"ValidUntilSeconds": "60"},
+		ManagedResources:      []string{"nvidia.com/gpu"},
+		RetryStrategy:         &config.ProvisioningRetryStrategy{BackoffLimitCount: &limit},
+	})
+
+	if prc.Name != "prov-config" || prc.Spec.ProvisioningClassName != "check-capacity.autoscaling.x-k8s.io" {
+		t.Errorf("unexpected provisioning request config: %+v", prc)
+	}
+	if prc.Spec.Parameters["ValidUntilSeconds"] != "60" {
+		t.Errorf("expected parameter ValidUntilSeconds=60, got %v", prc.Spec.Parameters)
+	}
+	if !reflect.DeepEqual(prc.Spec.ManagedResources, []corev1.ResourceName{"nvidia.com/gpu"}) {
+		t.Errorf("unexpected managed resources: %v", prc.Spec.ManagedResources)
+	}
+	r := prc.Spec.RetryStrategy
+	if r == nil || *r.BackoffLimitCount != 1 || r.BackoffBaseSeconds != nil {
+		t.Errorf("unexpected retry strategy: %+v", r)
+	}
+}
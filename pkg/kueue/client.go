@@ -4,28 +4,61 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/jhwagner/kueue-bench/pkg/ownership"
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+
 	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
 	kueueclientset "sigs.k8s.io/kueue/client-go/clientset/versioned"
 )
 
+// clientQPS bounds Kueue object API calls, which are issued in parallel when provisioning
+const clientQPS = 100
+
 // Client wraps Kubernetes clients for Kueue object operations
 type Client struct {
 	kueueClient kueueclientset.Interface
 	clientset   kubernetes.Interface
+	crdClient   apiextensionsclientset.Interface
 	config      *rest.Config
+	// topology labels the objects the client creates; see SetTopology
+	topology string
+}
+
+// ObjectCreator creates or updates the objects ProvisionKueueObjects and
+// SetupMultiKueueInfrastructure provision. Client implements it; tests use a fake that
+// records what was created.
+type ObjectCreator interface {
+	CreateCohort(ctx context.Context, cohort *kueue.Cohort) error
+	CreateTopology(ctx context.Context, topology *kueue.Topology) error
+	CreateResourceFlavor(ctx context.Context, rf *kueue.ResourceFlavor) error
+	CreateProvisioningRequestConfig(ctx context.Context, prc *kueue.ProvisioningRequestConfig) error
+	CreateAdmissionCheck(ctx context.Context, ac *kueue.AdmissionCheck) error
+	CreateClusterQueue(ctx context.Context, cq *kueue.ClusterQueue) error
+	CreateWorkloadPriorityClass(ctx context.Context, wpc *kueue.WorkloadPriorityClass) error
+	CreatePriorityClass(ctx context.Context, pc *schedulingv1.PriorityClass) error
+	CreateNamespace(ctx context.Context, name string, labels map[string]string) error
+	CreateResourceQuota(ctx context.Context, quota *corev1.ResourceQuota) error
+	CreateLimitRange(ctx context.Context, limitRange *corev1.LimitRange) error
+	CreateLocalQueue(ctx context.Context, lq *kueue.LocalQueue) error
+	CreateKubeconfigSecret(ctx context.Context, namespace, name string, kubeconfigData []byte) error
+	CreateMultiKueueCluster(ctx context.Context, mkc *kueue.MultiKueueCluster) error
+	CreateMultiKueueConfig(ctx context.Context, mkc *kueue.MultiKueueConfig) error
 }
 
+var _ ObjectCreator = (*Client)(nil)
+
 // NewClient creates a new Kueue client from a kubeconfig path
-func NewClient(kubeconfigPath string) (*Client, error) {
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+func NewClient(kubeconfigPath string, opts ...restconfig.Option) (*Client, error) {
+	config, err := restconfig.ForKubeconfig(kubeconfigPath, clientQPS, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+		return nil, err
 	}
 
 	kueueClient, err := kueueclientset.NewForConfig(config)
@@ -38,15 +71,33 @@ func NewClient(kubeconfigPath string) (*Client, error) {
 		return nil, fmt.Errorf("failed to create clientset: %w", err)
 	}
 
+	crdClient, err := apiextensionsclientset.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CRD clientset: %w", err)
+	}
+
 	return &Client{
 		kueueClient: kueueClient,
 		clientset:   clientset,
+		crdClient:   crdClient,
 		config:      config,
 	}, nil
 }
 
+// SetTopology labels the objects the client creates from now on with the topology they
+// belong to, in addition to the managed-by label they always get
+func (c *Client) SetTopology(name string) {
+	c.topology = name
+}
+
+// own adds the ownership labels to an object the client creates
+func (c *Client) own(obj metav1.Object) {
+	ownership.Apply(obj, c.topology, "")
+}
+
 // CreateCohort creates or updates a Cohort
 func (c *Client) CreateCohort(ctx context.Context, cohort *kueue.Cohort) error {
+	c.own(cohort)
 	_, err := c.kueueClient.KueueV1beta2().Cohorts().Create(ctx, cohort, metav1.CreateOptions{})
 	if apierrors.IsAlreadyExists(err) {
 		existing, getErr := c.kueueClient.KueueV1beta2().Cohorts().Get(ctx, cohort.Name, metav1.GetOptions{})
@@ -62,8 +113,27 @@ func (c *Client) CreateCohort(ctx context.Context, cohort *kueue.Cohort) error {
 	return nil
 }
 
+// CreateTopology creates or updates a TAS Topology
+func (c *Client) CreateTopology(ctx context.Context, topology *kueue.Topology) error {
+	c.own(topology)
+	_, err := c.kueueClient.KueueV1beta2().Topologies().Create(ctx, topology, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		existing, getErr := c.kueueClient.KueueV1beta2().Topologies().Get(ctx, topology.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return fmt.Errorf("failed to get Topology %s: %w", topology.Name, getErr)
+		}
+		topology.ResourceVersion = existing.ResourceVersion
+		_, err = c.kueueClient.KueueV1beta2().Topologies().Update(ctx, topology, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create or update Topology %s: %w", topology.Name, err)
+	}
+	return nil
+}
+
 // CreateResourceFlavor creates or updates a ResourceFlavor
 func (c *Client) CreateResourceFlavor(ctx context.Context, rf *kueue.ResourceFlavor) error {
+	c.own(rf)
 	_, err := c.kueueClient.KueueV1beta2().ResourceFlavors().Create(ctx, rf, metav1.CreateOptions{})
 	if apierrors.IsAlreadyExists(err) {
 		existing, getErr := c.kueueClient.KueueV1beta2().ResourceFlavors().Get(ctx, rf.Name, metav1.GetOptions{})
@@ -81,6 +151,7 @@ func (c *Client) CreateResourceFlavor(ctx context.Context, rf *kueue.ResourceFla
 
 // CreateClusterQueue creates or updates a ClusterQueue
 func (c *Client) CreateClusterQueue(ctx context.Context, cq *kueue.ClusterQueue) error {
+	c.own(cq)
 	_, err := c.kueueClient.KueueV1beta2().ClusterQueues().Create(ctx, cq, metav1.CreateOptions{})
 	if apierrors.IsAlreadyExists(err) {
 		existing, getErr := c.kueueClient.KueueV1beta2().ClusterQueues().Get(ctx, cq.Name, metav1.GetOptions{})
@@ -98,6 +169,7 @@ func (c *Client) CreateClusterQueue(ctx context.Context, cq *kueue.ClusterQueue)
 
 // CreateLocalQueue creates or updates a LocalQueue
 func (c *Client) CreateLocalQueue(ctx context.Context, lq *kueue.LocalQueue) error {
+	c.own(lq)
 	namespace := lq.Namespace
 	if namespace == "" {
 		namespace = "default"
@@ -120,6 +192,7 @@ func (c *Client) CreateLocalQueue(ctx context.Context, lq *kueue.LocalQueue) err
 
 // CreateWorkloadPriorityClass creates or updates a WorkloadPriorityClass
 func (c *Client) CreateWorkloadPriorityClass(ctx context.Context, wpc *kueue.WorkloadPriorityClass) error {
+	c.own(wpc)
 	_, err := c.kueueClient.KueueV1beta2().WorkloadPriorityClasses().Create(ctx, wpc, metav1.CreateOptions{})
 	if apierrors.IsAlreadyExists(err) {
 		existing, getErr := c.kueueClient.KueueV1beta2().WorkloadPriorityClasses().Get(ctx, wpc.Name, metav1.GetOptions{})
@@ -135,20 +208,22 @@ func (c *Client) CreateWorkloadPriorityClass(ctx context.Context, wpc *kueue.Wor
 	return nil
 }
 
-// CreateNamespace creates a namespace if it doesn't exist
-func (c *Client) CreateNamespace(ctx context.Context, name string) error {
-	_, err := c.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+// CreateNamespace creates a namespace if it doesn't exist. Labels are added to an
+// existing namespace, leaving its other labels in place.
+func (c *Client) CreateNamespace(ctx context.Context, name string, labels map[string]string) error {
+	existing, err := c.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
 	if err == nil {
-		// Namespace already exists
-		return nil
+		return c.labelNamespace(ctx, existing, labels)
 	}
 
-	// Create namespace
+	// Create namespace; namespaces that already existed are not labeled as kueue-bench's
 	ns := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: name,
+			Name:   name,
+			Labels: labels,
 		},
 	}
+	c.own(ns)
 
 	_, err = c.clientset.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
 	if err != nil {
@@ -158,6 +233,32 @@ func (c *Client) CreateNamespace(ctx context.Context, name string) error {
 	return nil
 }
 
+// labelNamespace adds labels to an existing namespace if any are missing or different
+func (c *Client) labelNamespace(ctx context.Context, ns *corev1.Namespace, labels map[string]string) error {
+	changed := false
+	for k, v := range labels {
+		if ns.Labels[k] != v {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	ns = ns.DeepCopy()
+	if ns.Labels == nil {
+		ns.Labels = make(map[string]string, len(labels))
+	}
+	for k, v := range labels {
+		ns.Labels[k] = v
+	}
+	if _, err := c.clientset.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to label namespace %s: %w", ns.Name, err)
+	}
+	return nil
+}
+
 // CreateKubeconfigSecret creates a Secret containing kubeconfig data
 func (c *Client) CreateKubeconfigSecret(ctx context.Context, namespace, name string, kubeconfigData []byte) error {
 	secret := &corev1.Secret{
@@ -170,6 +271,7 @@ func (c *Client) CreateKubeconfigSecret(ctx context.Context, namespace, name str
 			kueue.MultiKueueConfigSecretKey: kubeconfigData,
 		},
 	}
+	c.own(secret)
 
 	_, err := c.clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
 	if apierrors.IsAlreadyExists(err) {
@@ -188,6 +290,7 @@ func (c *Client) CreateKubeconfigSecret(ctx context.Context, namespace, name str
 
 // CreateMultiKueueCluster creates or updates a MultiKueueCluster
 func (c *Client) CreateMultiKueueCluster(ctx context.Context, mkc *kueue.MultiKueueCluster) error {
+	c.own(mkc)
 	_, err := c.kueueClient.KueueV1beta2().MultiKueueClusters().Create(ctx, mkc, metav1.CreateOptions{})
 	if apierrors.IsAlreadyExists(err) {
 		existing, getErr := c.kueueClient.KueueV1beta2().MultiKueueClusters().Get(ctx, mkc.Name, metav1.GetOptions{})
@@ -205,6 +308,7 @@ func (c *Client) CreateMultiKueueCluster(ctx context.Context, mkc *kueue.MultiKu
 
 // CreateMultiKueueConfig creates or updates a MultiKueueConfig
 func (c *Client) CreateMultiKueueConfig(ctx context.Context, mkc *kueue.MultiKueueConfig) error {
+	c.own(mkc)
 	_, err := c.kueueClient.KueueV1beta2().MultiKueueConfigs().Create(ctx, mkc, metav1.CreateOptions{})
 	if apierrors.IsAlreadyExists(err) {
 		existing, getErr := c.kueueClient.KueueV1beta2().MultiKueueConfigs().Get(ctx, mkc.Name, metav1.GetOptions{})
@@ -222,6 +326,7 @@ func (c *Client) CreateMultiKueueConfig(ctx context.Context, mkc *kueue.MultiKue
 
 // CreateAdmissionCheck creates or updates an AdmissionCheck
 func (c *Client) CreateAdmissionCheck(ctx context.Context, ac *kueue.AdmissionCheck) error {
+	c.own(ac)
 	_, err := c.kueueClient.KueueV1beta2().AdmissionChecks().Create(ctx, ac, metav1.CreateOptions{})
 	if apierrors.IsAlreadyExists(err) {
 		existing, getErr := c.kueueClient.KueueV1beta2().AdmissionChecks().Get(ctx, ac.Name, metav1.GetOptions{})
@@ -236,3 +341,301 @@ func (c *Client) CreateAdmissionCheck(ctx context.Context, ac *kueue.AdmissionCh
 	}
 	return nil
 }
+
+// CreateProvisioningRequestConfig creates or updates a ProvisioningRequestConfig
+func (c *Client) CreateProvisioningRequestConfig(ctx context.Context, prc *kueue.ProvisioningRequestConfig) error {
+	c.own(prc)
+	_, err := c.kueueClient.KueueV1beta2().ProvisioningRequestConfigs().Create(ctx, prc, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		existing, getErr := c.kueueClient.KueueV1beta2().ProvisioningRequestConfigs().Get(ctx, prc.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return fmt.Errorf("failed to get ProvisioningRequestConfig %s: %w", prc.Name, getErr)
+		}
+		prc.ResourceVersion = existing.ResourceVersion
+		_, err = c.kueueClient.KueueV1beta2().ProvisioningRequestConfigs().Update(ctx, prc, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create or update ProvisioningRequestConfig %s: %w", prc.Name, err)
+	}
+	return nil
+}
+
+// GetCohort returns a Cohort
+func (c *Client) GetCohort(ctx context.Context, name string) (*kueue.Cohort, error) {
+	obj, err := c.kueueClient.KueueV1beta2().Cohorts().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Cohort %s: %w", name, err)
+	}
+	return obj, nil
+}
+
+// ListCohorts returns all Cohorts
+func (c *Client) ListCohorts(ctx context.Context) ([]kueue.Cohort, error) {
+	list, err := c.kueueClient.KueueV1beta2().Cohorts().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Cohorts: %w", err)
+	}
+	return list.Items, nil
+}
+
+// DeleteCohort deletes a Cohort. A missing Cohort is not an error.
+func (c *Client) DeleteCohort(ctx context.Context, name string) error {
+	err := c.kueueClient.KueueV1beta2().Cohorts().Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete Cohort %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetTopology returns a TAS Topology
+func (c *Client) GetTopology(ctx context.Context, name string) (*kueue.Topology, error) {
+	obj, err := c.kueueClient.KueueV1beta2().Topologies().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Topology %s: %w", name, err)
+	}
+	return obj, nil
+}
+
+// ListTopologies returns all Topologies
+func (c *Client) ListTopologies(ctx context.Context) ([]kueue.Topology, error) {
+	list, err := c.kueueClient.KueueV1beta2().Topologies().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Topologies: %w", err)
+	}
+	return list.Items, nil
+}
+
+// DeleteTopology deletes a TAS Topology. A missing Topology is not an error.
+func (c *Client) DeleteTopology(ctx context.Context, name string) error {
+	err := c.kueueClient.KueueV1beta2().Topologies().Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete Topology %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetResourceFlavor returns a ResourceFlavor
+func (c *Client) GetResourceFlavor(ctx context.Context, name string) (*kueue.ResourceFlavor, error) {
+	obj, err := c.kueueClient.KueueV1beta2().ResourceFlavors().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ResourceFlavor %s: %w", name, err)
+	}
+	return obj, nil
+}
+
+// ListResourceFlavors returns all ResourceFlavors
+func (c *Client) ListResourceFlavors(ctx context.Context) ([]kueue.ResourceFlavor, error) {
+	list, err := c.kueueClient.KueueV1beta2().ResourceFlavors().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ResourceFlavors: %w", err)
+	}
+	return list.Items, nil
+}
+
+// DeleteResourceFlavor deletes a ResourceFlavor. A missing ResourceFlavor is not an error.
+func (c *Client) DeleteResourceFlavor(ctx context.Context, name string) error {
+	err := c.kueueClient.KueueV1beta2().ResourceFlavors().Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete ResourceFlavor %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetClusterQueue returns a ClusterQueue
+func (c *Client) GetClusterQueue(ctx context.Context, name string) (*kueue.ClusterQueue, error) {
+	obj, err := c.kueueClient.KueueV1beta2().ClusterQueues().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ClusterQueue %s: %w", name, err)
+	}
+	return obj, nil
+}
+
+// ListClusterQueues returns all ClusterQueues
+func (c *Client) ListClusterQueues(ctx context.Context) ([]kueue.ClusterQueue, error) {
+	list, err := c.kueueClient.KueueV1beta2().ClusterQueues().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ClusterQueues: %w", err)
+	}
+	return list.Items, nil
+}
+
+// DeleteClusterQueue deletes a ClusterQueue. A missing ClusterQueue is not an error.
+func (c *Client) DeleteClusterQueue(ctx context.Context, name string) error {
+	err := c.kueueClient.KueueV1beta2().ClusterQueues().Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete ClusterQueue %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetLocalQueue returns a LocalQueue
+func (c *Client) GetLocalQueue(ctx context.Context, namespace, name string) (*kueue.LocalQueue, error) {
+	obj, err := c.kueueClient.KueueV1beta2().LocalQueues(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LocalQueue %s/%s: %w", namespace, name, err)
+	}
+	return obj, nil
+}
+
+// ListLocalQueues returns the LocalQueues in a namespace (metav1.NamespaceAll for all namespaces)
+func (c *Client) ListLocalQueues(ctx context.Context, namespace string) ([]kueue.LocalQueue, error) {
+	list, err := c.kueueClient.KueueV1beta2().LocalQueues(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list LocalQueues: %w", err)
+	}
+	return list.Items, nil
+}
+
+// DeleteLocalQueue deletes a LocalQueue. A missing LocalQueue is not an error.
+func (c *Client) DeleteLocalQueue(ctx context.Context, namespace, name string) error {
+	err := c.kueueClient.KueueV1beta2().LocalQueues(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete LocalQueue %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// GetWorkloadPriorityClass returns a WorkloadPriorityClass
+func (c *Client) GetWorkloadPriorityClass(ctx context.Context, name string) (*kueue.WorkloadPriorityClass, error) {
+	obj, err := c.kueueClient.KueueV1beta2().WorkloadPriorityClasses().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get WorkloadPriorityClass %s: %w", name, err)
+	}
+	return obj, nil
+}
+
+// ListWorkloadPriorityClasses returns all WorkloadPriorityClasses
+func (c *Client) ListWorkloadPriorityClasses(ctx context.Context) ([]kueue.WorkloadPriorityClass, error) {
+	list, err := c.kueueClient.KueueV1beta2().WorkloadPriorityClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list WorkloadPriorityClasses: %w", err)
+	}
+	return list.Items, nil
+}
+
+// DeleteWorkloadPriorityClass deletes a WorkloadPriorityClass. A missing WorkloadPriorityClass is not an error.
+func (c *Client) DeleteWorkloadPriorityClass(ctx context.Context, name string) error {
+	err := c.kueueClient.KueueV1beta2().WorkloadPriorityClasses().Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete WorkloadPriorityClass %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetMultiKueueCluster returns a MultiKueueCluster
+func (c *Client) GetMultiKueueCluster(ctx context.Context, name string) (*kueue.MultiKueueCluster, error) {
+	obj, err := c.kueueClient.KueueV1beta2().MultiKueueClusters().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MultiKueueCluster %s: %w", name, err)
+	}
+	return obj, nil
+}
+
+// ListMultiKueueClusters returns all MultiKueueClusters
+func (c *Client) ListMultiKueueClusters(ctx context.Context) ([]kueue.MultiKueueCluster, error) {
+	list, err := c.kueueClient.KueueV1beta2().MultiKueueClusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MultiKueueClusters: %w", err)
+	}
+	return list.Items, nil
+}
+
+// DeleteMultiKueueCluster deletes a MultiKueueCluster. A missing MultiKueueCluster is not an error.
+func (c *Client) DeleteMultiKueueCluster(ctx context.Context, name string) error {
+	err := c.kueueClient.KueueV1beta2().MultiKueueClusters().Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete MultiKueueCluster %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetMultiKueueConfig returns a MultiKueueConfig
+func (c *Client) GetMultiKueueConfig(ctx context.Context, name string) (*kueue.MultiKueueConfig, error) {
+	obj, err := c.kueueClient.KueueV1beta2().MultiKueueConfigs().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MultiKueueConfig %s: %w", name, err)
+	}
+	return obj, nil
+}
+
+// ListMultiKueueConfigs returns all MultiKueueConfigs
+func (c *Client) ListMultiKueueConfigs(ctx context.Context) ([]kueue.MultiKueueConfig, error) {
+	list, err := c.kueueClient.KueueV1beta2().MultiKueueConfigs().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MultiKueueConfigs: %w", err)
+	}
+	return list.Items, nil
+}
+
+// DeleteMultiKueueConfig deletes a MultiKueueConfig. A missing MultiKueueConfig is not an error.
+func (c *Client) DeleteMultiKueueConfig(ctx context.Context, name string) error {
+	err := c.kueueClient.KueueV1beta2().MultiKueueConfigs().Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete MultiKueueConfig %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetAdmissionCheck returns an AdmissionCheck
+func (c *Client) GetAdmissionCheck(ctx context.Context, name string) (*kueue.AdmissionCheck, error) {
+	obj, err := c.kueueClient.KueueV1beta2().AdmissionChecks().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AdmissionCheck %s: %w", name, err)
+	}
+	return obj, nil
+}
+
+// ListAdmissionChecks returns all AdmissionChecks
+func (c *Client) ListAdmissionChecks(ctx context.Context) ([]kueue.AdmissionCheck, error) {
+	list, err := c.kueueClient.KueueV1beta2().AdmissionChecks().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list AdmissionChecks: %w", err)
+	}
+	return list.Items, nil
+}
+
+// DeleteAdmissionCheck deletes an AdmissionCheck. A missing AdmissionCheck is not an error.
+func (c *Client) DeleteAdmissionCheck(ctx context.Context, name string) error {
+	err := c.kueueClient.KueueV1beta2().AdmissionChecks().Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete AdmissionCheck %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetProvisioningRequestConfig returns a ProvisioningRequestConfig
+func (c *Client) GetProvisioningRequestConfig(ctx context.Context, name string) (*kueue.ProvisioningRequestConfig, error) {
+	obj, err := c.kueueClient.KueueV1beta2().ProvisioningRequestConfigs().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ProvisioningRequestConfig %s: %w", name, err)
+	}
+	return obj, nil
+}
+
+// ListProvisioningRequestConfigs returns all ProvisioningRequestConfigs
+func (c *Client) ListProvisioningRequestConfigs(ctx context.Context) ([]kueue.ProvisioningRequestConfig, error) {
+	list, err := c.kueueClient.KueueV1beta2().ProvisioningRequestConfigs().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ProvisioningRequestConfigs: %w", err)
+	}
+	return list.Items, nil
+}
+
+// DeleteProvisioningRequestConfig deletes a ProvisioningRequestConfig. A missing
+// ProvisioningRequestConfig is not an error.
+func (c *Client) DeleteProvisioningRequestConfig(ctx context.Context, name string) error {
+	err := c.kueueClient.KueueV1beta2().ProvisioningRequestConfigs().Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete ProvisioningRequestConfig %s: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteKubeconfigSecret deletes a kubeconfig Secret. A missing Secret is not an error.
+func (c *Client) DeleteKubeconfigSecret(ctx context.Context, namespace, name string) error {
+	err := c.clientset.CoreV1().Secrets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete Secret %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
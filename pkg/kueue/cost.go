@@ -0,0 +1,157 @@
+package kueue
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+)
+
+// FlavorCost is the capacity and hourly cost of the nodes a ResourceFlavor selects
+type FlavorCost struct {
+	Nodes       int
+	CostPerHour float64
+	Allocatable corev1.ResourceList
+}
+
+// QueueCostRate is the hourly cost of a ClusterQueue's capacity at one point in time
+type QueueCostRate struct {
+	// Nominal is the cost of the queue's nominal quota
+	Nominal float64
+	// Used is the cost of the quota its admitted workloads use, including borrowed quota
+	Used float64
+}
+
+// FlavorCosts returns the capacity and cost of every ResourceFlavor's nodes, from the
+// NodeCostAnnotation set on nodes of pools with a costPerHour. Each node is attributed to
+// one flavor only, the most specific one selecting it (see flavorCosts), so a flavor
+// without nodeLabels is priced from the nodes no other flavor selects. Flavors whose nodes
+// carry no cost are omitted.
+func (c *Client) FlavorCosts(ctx context.Context) (map[string]FlavorCost, error) {
+	flavors, err := c.kueueClient.KueueV1beta2().ResourceFlavors().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ResourceFlavors: %w", err)
+	}
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	return flavorCosts(flavors.Items, nodes.Items)
+}
+
+// flavorCosts sums the cost and allocatable resources of the nodes of each flavor. A node
+// selected by several flavors counts only towards the one with the most nodeLabels, the
+// first by name on a tie, so that no node's cost is counted twice.
+func flavorCosts(flavors []kueue.ResourceFlavor, nodes []corev1.Node) (map[string]FlavorCost, error) {
+	bySpecificity := slices.Clone(flavors)
+	slices.SortStableFunc(bySpecificity, func(a, b kueue.ResourceFlavor) int {
+		if c := cmp.Compare(len(b.Spec.NodeLabels), len(a.Spec.NodeLabels)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	costs := make(map[string]FlavorCost)
+	for _, node := range nodes {
+		value, ok := node.Annotations[config.NodeCostAnnotation]
+		if !ok {
+			continue
+		}
+		i := slices.IndexFunc(bySpecificity, func(rf kueue.ResourceFlavor) bool {
+			return labels.SelectorFromSet(rf.Spec.NodeLabels).Matches(labels.Set(node.Labels))
+		})
+		if i < 0 {
+			continue
+		}
+		perHour, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: invalid %s annotation %q: %w", node.Name, config.NodeCostAnnotation, value, err)
+		}
+
+		name := bySpecificity[i].Name
+		cost, ok := costs[name]
+		if !ok {
+			cost.Allocatable = corev1.ResourceList{}
+		}
+		cost.Nodes++
+		cost.CostPerHour += perHour
+		for resourceName, quantity := range node.Status.Allocatable {
+			total := cost.Allocatable[resourceName]
+			total.Add(quantity)
+			cost.Allocatable[resourceName] = total
+		}
+		costs[name] = cost
+	}
+
+	for name, cost := range costs {
+		if cost.CostPerHour <= 0 {
+			delete(costs, name)
+		}
+	}
+	return costs, nil
+}
+
+// QueueCostRates prices each ClusterQueue's nominal quota and current usage. A queue's
+// share of a flavor is its dominant resource: the largest fraction of the flavor's
+// allocatable capacity across the resources it covers, so a queue holding all of a node
+// pool's GPUs pays for the whole pool even if it uses little of its CPU. Queues with no
+// quota in a costed flavor are omitted.
+func QueueCostRates(cqs []kueue.ClusterQueue, flavors map[string]FlavorCost) map[string]QueueCostRate {
+	rates := make(map[string]QueueCostRate)
+	for _, cq := range cqs {
+		var rate QueueCostRate
+		costed := false
+		for _, rg := range cq.Spec.ResourceGroups {
+			for _, fq := range rg.Flavors {
+				cost, ok := flavors[string(fq.Name)]
+				if !ok {
+					continue
+				}
+				costed = true
+				nominal := make(corev1.ResourceList, len(fq.Resources))
+				for _, r := range fq.Resources {
+					nominal[r.Name] = r.NominalQuota
+				}
+				rate.Nominal += cost.CostPerHour * dominantShare(nominal, cost.Allocatable)
+			}
+		}
+		if !costed {
+			continue
+		}
+		for _, fu := range cq.Status.FlavorsUsage {
+			cost, ok := flavors[string(fu.Name)]
+			if !ok {
+				continue
+			}
+			used := make(corev1.ResourceList, len(fu.Resources))
+			for _, r := range fu.Resources {
+				used[r.Name] = r.Total
+			}
+			rate.Used += cost.CostPerHour * dominantShare(used, cost.Allocatable)
+		}
+		rates[cq.Name] = rate
+	}
+	return rates
+}
+
+// dominantShare returns the largest fraction of capacity any resource of amounts takes,
+// capped at 1. Resources the capacity does not have are ignored.
+func dominantShare(amounts, capacity corev1.ResourceList) float64 {
+	share := 0.0
+	for name, amount := range amounts {
+		total, ok := capacity[name]
+		if !ok || total.IsZero() {
+			continue
+		}
+		share = max(share, amount.AsApproximateFloat64()/total.AsApproximateFloat64())
+	}
+	return min(share, 1)
+}
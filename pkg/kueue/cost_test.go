@@ -0,0 +1,140 @@
+package kueue
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+	kueuefake "sigs.k8s.io/kueue/client-go/clientset/versioned/fake"
+)
+
+// costNode returns a node with the given labels, cost annotation and allocatable CPU and GPUs
+func costNode(name, pool, cost string, cpu, gpus int64) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"pool": pool}},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU: *resource.NewQuantity(cpu, resource.DecimalSI),
+			"nvidia.com/gpu":   *resource.NewQuantity(gpus, resource.DecimalSI),
+		}},
+	}
+	if cost != "" {
+		node.Annotations = map[string]string{config.NodeCostAnnotation: cost}
+	}
+	return node
+}
+
+func TestFlavorCosts(t *testing.T) {
+	client := &Client{
+		kueueClient: kueuefake.NewSimpleClientset(
+			&kueue.ResourceFlavor{ObjectMeta: metav1.ObjectMeta{Name: "gpu"}, Spec: kueue.ResourceFlavorSpec{NodeLabels: map[string]string{"pool": "gpu"}}},
+			&kueue.ResourceFlavor{ObjectMeta: metav1.ObjectMeta{Name: "cpu"}, Spec: kueue.ResourceFlavorSpec{NodeLabels: map[string]string{"pool": "cpu"}}},
+			&kueue.ResourceFlavor{ObjectMeta: metav1.ObjectMeta{Name: "any"}},
+		),
+		clientset: fake.NewClientset(
+			costNode("gpu-0", "gpu", "4", 32, 8),
+			costNode("gpu-1", "gpu", "4.5", 32, 8),
+			costNode("cpu-0", "cpu", "", 16, 0),
+			costNode("other-0", "other", "1", 8, 0),
+		),
+	}
+
+	costs, err := client.FlavorCosts(context.Background())
+	if err != nil {
+		t.Fatalf("FlavorCosts() error = %v", err)
+	}
+	if _, ok := costs["cpu"]; ok {
+		t.Errorf("flavor without costed nodes should be omitted, got %+v", costs["cpu"])
+	}
+	cost := costs["gpu"]
+	gpus := cost.Allocatable["nvidia.com/gpu"]
+	if cost.Nodes != 2 || cost.CostPerHour != 8.5 || gpus.Value() != 16 {
+		t.Errorf("flavor gpu = %d nodes, %v/h, %s GPUs; want 2 nodes, 8.5/h, 16 GPUs", cost.Nodes, cost.CostPerHour, gpus.String())
+	}
+	// The flavor without nodeLabels only gets the nodes no other flavor selects
+	cost = costs["any"]
+	cpu := cost.Allocatable[corev1.ResourceCPU]
+	if cost.Nodes != 1 || cost.CostPerHour != 1 || cpu.Value() != 8 {
+		t.Errorf("flavor any = %d nodes, %v/h, %s CPUs; want 1 node, 1/h, 8 CPUs", cost.Nodes, cost.CostPerHour, cpu.String())
+	}
+	total := 0.0
+	for _, c := range costs {
+		total += c.CostPerHour
+	}
+	if total != 9.5 {
+		t.Errorf("total flavor cost = %v/h, want 9.5/h: no node counted twice", total)
+	}
+
+	// Flavors selecting the same nodes with as many labels: the first by name gets them
+	twins := []kueue.ResourceFlavor{
+		{ObjectMeta: metav1.ObjectMeta{Name: "b"}, Spec: kueue.ResourceFlavorSpec{NodeLabels: map[string]string{"pool": "gpu"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "a"}, Spec: kueue.ResourceFlavorSpec{NodeLabels: map[string]string{"pool": "gpu"}}},
+	}
+	twinCosts, err := flavorCosts(twins, []corev1.Node{*costNode("gpu-0", "gpu", "4", 32, 8)})
+	if err != nil {
+		t.Fatalf("flavorCosts() error = %v", err)
+	}
+	if _, ok := twinCosts["b"]; ok || twinCosts["a"].CostPerHour != 4 {
+		t.Errorf("flavorCosts() = %+v, want the node attributed to flavor a only", twinCosts)
+	}
+
+	if _, err := flavorCosts([]kueue.ResourceFlavor{{}}, []corev1.Node{*costNode("n", "p", "cheap", 1, 0)}); err == nil {
+		t.Error("expected an error for an invalid cost annotation")
+	}
+}
+
+func TestQueueCostRates(t *testing.T) {
+	flavors := map[string]FlavorCost{
+		"gpu": {CostPerHour: 100, Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("64"),
+			"nvidia.com/gpu":   resource.MustParse("16"),
+		}},
+	}
+	quota := func(flavor, cpu, gpus string) kueue.FlavorQuotas {
+		return kueue.FlavorQuotas{Name: kueue.ResourceFlavorReference(flavor), Resources: []kueue.ResourceQuota{
+			{Name: corev1.ResourceCPU, NominalQuota: resource.MustParse(cpu)},
+			{Name: "nvidia.com/gpu", NominalQuota: resource.MustParse(gpus)},
+		}}
+	}
+	usage := func(flavor, cpu, gpus string) kueue.FlavorUsage {
+		return kueue.FlavorUsage{Name: kueue.ResourceFlavorReference(flavor), Resources: []kueue.ResourceUsage{
+			{Name: corev1.ResourceCPU, Total: resource.MustParse(cpu)},
+			{Name: "nvidia.com/gpu", Total: resource.MustParse(gpus)},
+		}}
+	}
+	cq := func(name string, q kueue.FlavorQuotas, u ...kueue.FlavorUsage) kueue.ClusterQueue {
+		return kueue.ClusterQueue{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       kueue.ClusterQueueSpec{ResourceGroups: []kueue.ResourceGroup{{Flavors: []kueue.FlavorQuotas{q}}}},
+			Status:     kueue.ClusterQueueStatus{FlavorsUsage: u},
+		}
+	}
+
+	rates := QueueCostRates([]kueue.ClusterQueue{
+		// GPUs dominate: a quarter of the GPUs, half of them in use
+		cq("training", quota("gpu", "8", "4"), usage("gpu", "4", "2")),
+		// CPU dominates, borrowing beyond its nominal quota
+		cq("inference", quota("gpu", "16", "0"), usage("gpu", "32", "0")),
+		cq("uncosted", quota("cpu-only", "8", "0"), usage("cpu-only", "8", "0")),
+	}, flavors)
+
+	want := map[string]QueueCostRate{
+		"training":  {Nominal: 25, Used: 12.5},
+		"inference": {Nominal: 25, Used: 50},
+	}
+	if len(rates) != len(want) {
+		t.Fatalf("QueueCostRates() = %+v, want %+v", rates, want)
+	}
+	for name, w := range want {
+		got := rates[name]
+		if math.Abs(got.Nominal-w.Nominal) > 1e-9 || math.Abs(got.Used-w.Used) > 1e-9 {
+			t.Errorf("rate of %s = %+v, want %+v", name, got, w)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package kueue
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/wait"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+)
+
+// helmKeepPatch sets Helm's keep resource policy, which leaves a resource in place when
+// its release is uninstalled
+const helmKeepPatch = `{"metadata":{"annotations":{"helm.sh/resource-policy":"keep"}}}`
+
+// listKueueCRDs returns the CRDs of the Kueue API group, sorted by name
+func (c *Client) listKueueCRDs(ctx context.Context) ([]apiextensionsv1.CustomResourceDefinition, error) {
+	list, err := c.crdClient.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CRDs: %w", err)
+	}
+
+	var crds []apiextensionsv1.CustomResourceDefinition
+	for _, crd := range list.Items {
+		if crd.Spec.Group == kueue.SchemeGroupVersion.Group {
+			crds = append(crds, crd)
+		}
+	}
+	sort.Slice(crds, func(i, j int) bool { return crds[i].Name < crds[j].Name })
+	return crds, nil
+}
+
+// keepCRDs sets Helm's keep resource policy on Kueue's CRDs, so uninstalling the release
+// leaves them, and every Kueue object, in place. The next install of the release adopts
+// and updates them.
+func (c *Client) keepCRDs(ctx context.Context) error {
+	crds, err := c.listKueueCRDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, crd := range crds {
+		if _, err := c.crdClient.ApiextensionsV1().CustomResourceDefinitions().Patch(ctx, crd.Name,
+			types.MergePatchType, []byte(helmKeepPatch), metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("failed to annotate CRD %s: %w", crd.Name, err)
+		}
+	}
+	return nil
+}
+
+// WaitForCRDEstablished waits until a CRD exists and has the Established condition, so
+// objects of its kind can be created
+func (c *Client) WaitForCRDEstablished(ctx context.Context, name string, timeout time.Duration) error {
+	return wait.CRDEstablished(ctx, c.crdClient, name, timeout)
+}
+
+// crdEstablished reports whether a CRD has the Established condition
+func crdEstablished(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	for _, cond := range crd.Status.Conditions {
+		if cond.Type == apiextensionsv1.Established {
+			return cond.Status == apiextensionsv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// CRDVersion records the API versions of an installed Kueue CRD
+type CRDVersion struct {
+	// Name is the CRD name, e.g. clusterqueues.kueue.x-k8s.io
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	// Served are the API versions the CRD serves
+	Served []string `json:"served"`
+	// Storage is the version objects are persisted as
+	Storage string `json:"storage"`
+	// StoredVersions are all versions objects have been persisted as (status.storedVersions)
+	StoredVersions []string `json:"storedVersions,omitempty"`
+}
+
+// usedKinds are the Kueue kinds kueue-bench creates or reads
+var usedKinds = append([]string{"MultiKueueCluster", "MultiKueueConfig", "Workload"}, diffKinds...)
+
+// CRDVersions returns the API versions of the Kueue CRDs installed in the cluster, sorted by name
+func (c *Client) CRDVersions(ctx context.Context) ([]CRDVersion, error) {
+	crds, err := c.listKueueCRDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]CRDVersion, 0, len(crds))
+	for _, crd := range crds {
+		v := CRDVersion{
+			Name:           crd.Name,
+			Kind:           crd.Spec.Names.Kind,
+			StoredVersions: crd.Status.StoredVersions,
+		}
+		for _, version := range crd.Spec.Versions {
+			if version.Served {
+				v.Served = append(v.Served, version.Name)
+			}
+			if version.Storage {
+				v.Storage = version.Name
+			}
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+// CheckCRDVersions verifies that the Kueue CRDs kueue-bench uses are installed and serve
+// the API version its objects are built with
+func CheckCRDVersions(crds []CRDVersion) error {
+	if problems := crdVersionProblems(crds); len(problems) > 0 {
+		return fmt.Errorf("kueue CRDs do not serve %s, the API version kueue-bench uses:\n  %s", kueue.SchemeGroupVersion.Version, strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// crdVersionProblems describes every Kueue kind kueue-bench uses whose CRD is missing or
+// does not serve the API version its objects are built with
+func crdVersionProblems(crds []CRDVersion) []string {
+	want := kueue.SchemeGroupVersion.Version
+	installed := make(map[string]CRDVersion, len(crds))
+	for _, crd := range crds {
+		installed[crd.Kind] = crd
+	}
+
+	var problems []string
+	for _, kind := range usedKinds {
+		crd, ok := installed[kind]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%s: CRD not installed", kind))
+		case !slices.Contains(crd.Served, want):
+			problems = append(problems, fmt.Sprintf("%s: serves %s", kind, strings.Join(crd.Served, ", ")))
+		}
+	}
+	return problems
+}
+
+// CRDVersionChanges describes how Kueue CRD versions changed from before to after,
+// sorted by CRD name
+func CRDVersionChanges(before, after []CRDVersion) []string {
+	old := make(map[string]CRDVersion, len(before))
+	for _, crd := range before {
+		old[crd.Name] = crd
+	}
+	current := make(map[string]CRDVersion, len(after))
+	for _, crd := range after {
+		current[crd.Name] = crd
+	}
+
+	var changes []string
+	for _, crd := range after {
+		prev, ok := old[crd.Name]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("%s: added", crd.Name))
+		case prev.Storage != crd.Storage:
+			changes = append(changes, fmt.Sprintf("%s: storage version %s → %s", crd.Name, prev.Storage, crd.Storage))
+		case !slices.Equal(prev.Served, crd.Served):
+			changes = append(changes, fmt.Sprintf("%s: served versions %s → %s", crd.Name,
+				strings.Join(prev.Served, ", "), strings.Join(crd.Served, ", ")))
+		}
+	}
+	for _, crd := range before {
+		if _, ok := current[crd.Name]; !ok {
+			changes = append(changes, fmt.Sprintf("%s: removed", crd.Name))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
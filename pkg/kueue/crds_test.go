@@ -0,0 +1,169 @@
+package kueue
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestKeepCRDs(t *testing.T) {
+	ctx := context.TODO()
+	crd := func(name, group string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: group},
+		}
+	}
+	client := &Client{crdClient: apiextensionsfake.NewSimpleClientset(
+		crd("clusterqueues.kueue.x-k8s.io", "kueue.x-k8s.io"),
+		crd("workloads.kueue.x-k8s.io", "kueue.x-k8s.io"),
+		crd("jobsets.jobset.x-k8s.io", "jobset.x-k8s.io"),
+	)}
+
+	if err := client.keepCRDs(ctx); err != nil {
+		t.Fatalf("keepCRDs() error = %v", err)
+	}
+
+	crds := client.crdClient.ApiextensionsV1().CustomResourceDefinitions()
+	for name, wantKeep := range map[string]bool{
+		"clusterqueues.kueue.x-k8s.io": true,
+		"workloads.kueue.x-k8s.io":     true,
+		"jobsets.jobset.x-k8s.io":      false,
+	} {
+		got, err := crds.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Get(%s) error = %v", name, err)
+		}
+		if keep := got.Annotations["helm.sh/resource-policy"] == "keep"; keep != wantKeep {
+			t.Errorf("CRD %s keep policy = %v, want %v", name, keep, wantKeep)
+		}
+	}
+}
+
+func TestCRDVersions(t *testing.T) {
+	ctx := context.TODO()
+	crd := func(kind string, storage string, versions ...string) *apiextensionsv1.CustomResourceDefinition {
+		c := &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: strings.ToLower(kind) + "s.kueue.x-k8s.io"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "kueue.x-k8s.io",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: kind},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: []string{storage}},
+		}
+		for _, v := range versions {
+			c.Spec.Versions = append(c.Spec.Versions, apiextensionsv1.CustomResourceDefinitionVersion{
+				Name: v, Served: true, Storage: v == storage,
+			})
+		}
+		return c
+	}
+
+	var objects []runtime.Object
+	for _, kind := range usedKinds {
+		objects = append(objects, crd(kind, "v1beta2", "v1beta1", "v1beta2"))
+	}
+	client := &Client{crdClient: apiextensionsfake.NewSimpleClientset(objects...)}
+
+	crds, err := client.CRDVersions(ctx)
+	if err != nil {
+		t.Fatalf("CRDVersions() error = %v", err)
+	}
+	if len(crds) != len(usedKinds) {
+		t.Fatalf("CRDVersions() returned %d CRDs, want %d", len(crds), len(usedKinds))
+	}
+	want := CRDVersion{
+		Name:           "admissionchecks.kueue.x-k8s.io",
+		Kind:           "AdmissionCheck",
+		Served:         []string{"v1beta1", "v1beta2"},
+		Storage:        "v1beta2",
+		StoredVersions: []string{"v1beta2"},
+	}
+	if !reflect.DeepEqual(crds[0], want) {
+		t.Errorf("CRDVersions()[0] = %+v, want %+v", crds[0], want)
+	}
+	if err := CheckCRDVersions(crds); err != nil {
+		t.Errorf("CheckCRDVersions() error = %v", err)
+	}
+
+	// An older release that only serves v1beta1, missing the Cohort CRD
+	var old []CRDVersion
+	for _, c := range crds {
+		if c.Kind == "Cohort" {
+			continue
+		}
+		c.Served, c.Storage = []string{"v1beta1"}, "v1beta1"
+		old = append(old, c)
+	}
+	err = CheckCRDVersions(old)
+	if err == nil {
+		t.Fatal("CheckCRDVersions() expected error for v1beta1-only CRDs")
+	}
+	for _, want := range []string{"Cohort: CRD not installed", "ClusterQueue: serves v1beta1"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("CheckCRDVersions() error = %v, want containing %q", err, want)
+		}
+	}
+}
+
+func TestCRDVersionChanges(t *testing.T) {
+	before := []CRDVersion{
+		{Name: "clusterqueues.kueue.x-k8s.io", Served: []string{"v1beta1"}, Storage: "v1beta1"},
+		{Name: "localqueues.kueue.x-k8s.io", Served: []string{"v1beta1"}, Storage: "v1beta1"},
+		{Name: "topologies.kueue.x-k8s.io", Served: []string{"v1alpha1"}, Storage: "v1alpha1"},
+		{Name: "workloads.kueue.x-k8s.io", Served: []string{"v1beta1"}, Storage: "v1beta1"},
+	}
+	after := []CRDVersion{
+		{Name: "clusterqueues.kueue.x-k8s.io", Served: []string{"v1beta1", "v1beta2"}, Storage: "v1beta2"},
+		{Name: "cohorts.kueue.x-k8s.io", Served: []string{"v1beta2"}, Storage: "v1beta2"},
+		{Name: "localqueues.kueue.x-k8s.io", Served: []string{"v1beta1", "v1beta2"}, Storage: "v1beta1"},
+		{Name: "workloads.kueue.x-k8s.io", Served: []string{"v1beta1"}, Storage: "v1beta1"},
+	}
+
+	want := []string{
+		"clusterqueues.kueue.x-k8s.io: storage version v1beta1 → v1beta2",
+		"cohorts.kueue.x-k8s.io: added",
+		"localqueues.kueue.x-k8s.io: served versions v1beta1 → v1beta1, v1beta2",
+		"topologies.kueue.x-k8s.io: removed",
+	}
+	if got := CRDVersionChanges(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("CRDVersionChanges() =\n%q\nwant\n%q", got, want)
+	}
+	if got := CRDVersionChanges(after, after); len(got) != 0 {
+		t.Errorf("CRDVersionChanges() of identical CRDs = %q, want none", got)
+	}
+}
+
+func TestWaitForCRDEstablished(t *testing.T) {
+	ctx := context.TODO()
+	crd := func(name string, established apiextensionsv1.ConditionStatus) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{
+				Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+					{Type: apiextensionsv1.Established, Status: established},
+				},
+			},
+		}
+	}
+	client := &Client{crdClient: apiextensionsfake.NewSimpleClientset(
+		crd("jobsets.jobset.x-k8s.io", apiextensionsv1.ConditionTrue),
+		crd("rayjobs.ray.io", apiextensionsv1.ConditionFalse),
+	)}
+
+	if err := client.WaitForCRDEstablished(ctx, "jobsets.jobset.x-k8s.io", time.Second); err != nil {
+		t.Errorf("WaitForCRDEstablished(established) error = %v", err)
+	}
+	for _, name := range []string{"rayjobs.ray.io", "missing.example.com"} {
+		if err := client.WaitForCRDEstablished(ctx, name, 10*time.Millisecond); err == nil {
+			t.Errorf("WaitForCRDEstablished(%s) succeeded, want timeout", name)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package kueue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+)
+
+// DiffState is how a live object differs from the Kueue config
+type DiffState string
+
+const (
+	// DiffMissing objects are in the config but not in the cluster
+	DiffMissing DiffState = "missing"
+	// DiffUnexpected objects are in the cluster but not in the config
+	DiffUnexpected DiffState = "unexpected"
+	// DiffChanged objects exist in both with different fields
+	DiffChanged DiffState = "changed"
+)
+
+// ObjectDiff describes a Kueue object that differs from the config that provisioned it
+type ObjectDiff struct {
+	Kind string
+	// Name is namespace/name for LocalQueues
+	Name  string
+	State DiffState
+	// Fields lists the differing fields of a changed object
+	Fields []FieldDiff
+}
+
+// FieldDiff is a field whose live value differs from the config
+type FieldDiff struct {
+	// Path is the field path, e.g. spec.resourceGroups[0].flavors[0].resources[0].nominalQuota
+	Path string
+	// Want and Got are the JSON-encoded configured and live values; empty if the field is unset
+	Want string
+	Got  string
+}
+
+// NotInConfig reports whether the field is set in the cluster but not by the config,
+// typically by API server or Kueue defaulting
+func (f FieldDiff) NotInConfig() bool {
+	return f.Want == ""
+}
+
+// diffKinds are the Kueue kinds compared by DiffKueueObjects, in provisioning order
+var diffKinds = []string{
+	"Cohort", "Topology", "ResourceFlavor", "ProvisioningRequestConfig", "AdmissionCheck",
+	"ClusterQueue", "WorkloadPriorityClass", "LocalQueue",
+}
+
+// namedObjects maps object names to Kueue objects of one kind
+type namedObjects map[string]interface{}
+
+// DiffKueueObjects compares the Kueue objects in the cluster against those kueueConfig
+// provisions. Objects are compared on everything but metadata and status, so fields
+// defaulted by the API server or Kueue show up as not in the config. Namespaces are only
+// checked for existence and their configured labels. MultiKueue AdmissionChecks are
+// set up from WorkerSets rather than the config and are ignored.
+func DiffKueueObjects(ctx context.Context, client *Client, kueueConfig *config.KueueConfig) ([]ObjectDiff, error) {
+	if kueueConfig == nil {
+		kueueConfig = &config.KueueConfig{}
+	}
+
+	want, err := intendedObjects(kueueConfig)
+	if err != nil {
+		return nil, err
+	}
+	live, err := client.liveObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []ObjectDiff
+	for _, kind := range diffKinds {
+		kindDiffs, err := diffObjects(kind, want[kind], live[kind])
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, kindDiffs...)
+	}
+
+	nsDiffs, err := client.diffNamespaces(ctx, namespacesToCreate(kueueConfig))
+	if err != nil {
+		return nil, err
+	}
+	return append(diffs, nsDiffs...), nil
+}
+
+// intendedObjects builds the objects kueueConfig provisions, by kind
+func intendedObjects(kueueConfig *config.KueueConfig) (map[string]namedObjects, error) {
+	objects := map[string]namedObjects{}
+	for _, kind := range diffKinds {
+		objects[kind] = namedObjects{}
+	}
+
+	for _, c := range kueueConfig.Cohorts {
+		cohort, err := BuildCohort(c)
+		if err != nil {
+			return nil, err
+		}
+		objects["Cohort"][cohort.Name] = cohort
+	}
+	for _, t := range kueueConfig.Topologies {
+		objects["Topology"][t.Name] = BuildTopology(t)
+	}
+	for _, rf := range kueueConfig.ResourceFlavors {
+		objects["ResourceFlavor"][rf.Name] = BuildResourceFlavor(rf)
+	}
+	for _, prc := range kueueConfig.ProvisioningRequestConfigs {
+		objects["ProvisioningRequestConfig"][prc.Name] = BuildProvisioningRequestConfig(prc)
+	}
+	for _, ac := range kueueConfig.AdmissionChecks {
+		objects["AdmissionCheck"][ac.Name] = BuildAdmissionCheck(ac)
+	}
+	for _, c := range kueueConfig.ClusterQueues {
+		cq, err := BuildClusterQueue(c)
+		if err != nil {
+			return nil, err
+		}
+		objects["ClusterQueue"][cq.Name] = cq
+	}
+	for _, wpc := range kueueConfig.PriorityClasses {
+		objects["WorkloadPriorityClass"][wpc.Name] = BuildWorkloadPriorityClass(wpc)
+	}
+	for _, l := range kueueConfig.LocalQueues {
+		lq, err := BuildLocalQueue(l)
+		if err != nil {
+			return nil, err
+		}
+		objects["LocalQueue"][lq.Namespace+"/"+lq.Name] = lq
+	}
+	return objects, nil
+}
+
+// liveObjects lists the Kueue objects in the cluster, by kind
+func (c *Client) liveObjects(ctx context.Context) (map[string]namedObjects, error) {
+	objects := map[string]namedObjects{}
+	for _, kind := range diffKinds {
+		objects[kind] = namedObjects{}
+	}
+
+	cohorts, err := c.ListCohorts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cohorts {
+		objects["Cohort"][cohorts[i].Name] = &cohorts[i]
+	}
+	topologies, err := c.ListTopologies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range topologies {
+		objects["Topology"][topologies[i].Name] = &topologies[i]
+	}
+	rfs, err := c.ListResourceFlavors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rfs {
+		objects["ResourceFlavor"][rfs[i].Name] = &rfs[i]
+	}
+	prcs, err := c.ListProvisioningRequestConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range prcs {
+		objects["ProvisioningRequestConfig"][prcs[i].Name] = &prcs[i]
+	}
+	acs, err := c.ListAdmissionChecks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range acs {
+		if acs[i].Spec.ControllerName == kueue.MultiKueueControllerName {
+			continue
+		}
+		objects["AdmissionCheck"][acs[i].Name] = &acs[i]
+	}
+	cqs, err := c.ListClusterQueues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cqs {
+		objects["ClusterQueue"][cqs[i].Name] = &cqs[i]
+	}
+	wpcs, err := c.ListWorkloadPriorityClasses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range wpcs {
+		objects["WorkloadPriorityClass"][wpcs[i].Name] = &wpcs[i]
+	}
+	lqs, err := c.ListLocalQueues(ctx, metav1.NamespaceAll)
+	if err != nil {
+		return nil, err
+	}
+	for i := range lqs {
+		objects["LocalQueue"][lqs[i].Namespace+"/"+lqs[i].Name] = &lqs[i]
+	}
+	return objects, nil
+}
+
+// diffObjects compares the intended and live objects of one kind, sorted by name
+func diffObjects(kind string, want, live namedObjects) ([]ObjectDiff, error) {
+	names := make([]string, 0, len(want)+len(live))
+	for name := range want {
+		names = append(names, name)
+	}
+	for name := range live {
+		if _, ok := want[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diffs []ObjectDiff
+	for _, name := range names {
+		wantObj, inConfig := want[name]
+		liveObj, inCluster := live[name]
+		switch {
+		case !inCluster:
+			diffs = append(diffs, ObjectDiff{Kind: kind, Name: name, State: DiffMissing})
+		case !inConfig:
+			diffs = append(diffs, ObjectDiff{Kind: kind, Name: name, State: DiffUnexpected})
+		default:
+			wantFields, err := objectFields(wantObj)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert %s %s: %w", kind, name, err)
+			}
+			liveFields, err := objectFields(liveObj)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert live %s %s: %w", kind, name, err)
+			}
+			var fields []FieldDiff
+			compareFields("", wantFields, liveFields, &fields)
+			if len(fields) > 0 {
+				diffs = append(diffs, ObjectDiff{Kind: kind, Name: name, State: DiffChanged, Fields: fields})
+			}
+		}
+	}
+	return diffs, nil
+}
+
+// objectFields returns the fields of an object compared by DiffKueueObjects: everything
+// except type information, metadata and status
+func objectFields(obj interface{}) (map[string]interface{}, error) {
+	fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"apiVersion", "kind", "metadata", "status"} {
+		delete(fields, key)
+	}
+	return fields, nil
+}
+
+// compareFields appends the differences between want and got under path to diffs.
+// Maps are compared by key and lists by index.
+func compareFields(path string, want, got interface{}, diffs *[]FieldDiff) {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		if g, ok := got.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(w)+len(g))
+			for k := range w {
+				keys = append(keys, k)
+			}
+			for k := range g {
+				if _, ok := w[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				p := k
+				if path != "" {
+					p = path + "." + k
+				}
+				compareField(p, w, g, k, diffs)
+			}
+			return
+		}
+	case []interface{}:
+		if g, ok := got.([]interface{}); ok {
+			for i := 0; i < max(len(w), len(g)); i++ {
+				p := fmt.Sprintf("%s[%d]", path, i)
+				switch {
+				case i >= len(g):
+					*diffs = append(*diffs, FieldDiff{Path: p, Want: encodeField(w[i])})
+				case i >= len(w):
+					*diffs = append(*diffs, FieldDiff{Path: p, Got: encodeField(g[i])})
+				default:
+					compareFields(p, w[i], g[i], diffs)
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		*diffs = append(*diffs, FieldDiff{Path: path, Want: encodeField(want), Got: encodeField(got)})
+	}
+}
+
+// compareField compares key of the want and got maps
+func compareField(path string, want, got map[string]interface{}, key string, diffs *[]FieldDiff) {
+	w, inConfig := want[key]
+	g, inCluster := got[key]
+	switch {
+	case !inCluster:
+		*diffs = append(*diffs, FieldDiff{Path: path, Want: encodeField(w)})
+	case !inConfig:
+		*diffs = append(*diffs, FieldDiff{Path: path, Got: encodeField(g)})
+	default:
+		compareFields(path, w, g, diffs)
+	}
+}
+
+// encodeField returns v as compact JSON
+func encodeField(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// diffNamespaces checks the namespaces exist and carry their configured labels
+func (c *Client) diffNamespaces(ctx context.Context, namespaces []config.Namespace) ([]ObjectDiff, error) {
+	var diffs []ObjectDiff
+	for _, ns := range namespaces {
+		live, err := c.clientset.CoreV1().Namespaces().Get(ctx, ns.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			diffs = append(diffs, ObjectDiff{Kind: "Namespace", Name: ns.Name, State: DiffMissing})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get namespace %s: %w", ns.Name, err)
+		}
+
+		keys := make([]string, 0, len(ns.Labels))
+		for k := range ns.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var fields []FieldDiff
+		for _, k := range keys {
+			got, ok := live.Labels[k]
+			if ok && got == ns.Labels[k] {
+				continue
+			}
+			field := FieldDiff{Path: "metadata.labels." + k, Want: encodeField(ns.Labels[k])}
+			if ok {
+				field.Got = encodeField(got)
+			}
+			fields = append(fields, field)
+		}
+		if len(fields) > 0 {
+			diffs = append(diffs, ObjectDiff{Kind: "Namespace", Name: ns.Name, State: DiffChanged, Fields: fields})
+		}
+	}
+	return diffs, nil
+}
@@ -0,0 +1,96 @@
+package kueue
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+	kueuefake "sigs.k8s.io/kueue/client-go/clientset/versioned/fake"
+)
+
+func TestDiffKueueObjects(t *testing.T) {
+	ctx := context.TODO()
+	client := &Client{kueueClient: kueuefake.NewSimpleClientset(), clientset: fake.NewClientset()}
+	cfg := &config.KueueConfig{
+		ResourceFlavors: []config.ResourceFlavor{{Name: "default"}},
+		ClusterQueues: []config.ClusterQueue{{
+			Name: "cq",
+			ResourceGroups: []config.ResourceGroup{{
+				CoveredResources: []string{"cpu"},
+				Flavors: []config.FlavorQuotas{{
+					Name:      "default",
+					Resources: []config.Resource{{Name: "cpu", NominalQuota: "10"}},
+				}},
+			}},
+		}},
+		LocalQueues: []config.LocalQueue{{Name: "lq", Namespace: "team-a", ClusterQueue: "cq"}},
+		Namespaces:  []config.Namespace{{Name: "team-a", Labels: map[string]string{"team": "a"}}},
+	}
+
+	if err := ProvisionKueueObjects(ctx, client, cfg, 0); err != nil {
+		t.Fatalf("ProvisionKueueObjects() error = %v", err)
+	}
+	diffs, err := DiffKueueObjects(ctx, client, cfg)
+	if err != nil {
+		t.Fatalf("DiffKueueObjects() error = %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Fatalf("expected no diffs right after provisioning, got %+v", diffs)
+	}
+
+	// Drift: edited quota, defaulted field, deleted LocalQueue, extra flavor, relabeled namespace
+	api := client.kueueClient.KueueV1beta2()
+	cq, err := client.GetClusterQueue(ctx, "cq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cq.Spec.ResourceGroups[0].Flavors[0].Resources[0].NominalQuota = resource.MustParse("8")
+	cq.Spec.QueueingStrategy = kueue.BestEffortFIFO
+	if _, err := api.ClusterQueues().Update(ctx, cq, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteLocalQueue(ctx, "team-a", "lq"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateResourceFlavor(ctx, BuildResourceFlavor(config.ResourceFlavor{Name: "manual"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateAdmissionCheck(ctx, BuildMultiKueueAdmissionCheck("multikueue", "multikueue")); err != nil {
+		t.Fatal(err)
+	}
+	ns, err := client.clientset.CoreV1().Namespaces().Get(ctx, "team-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns.Labels["team"] = "b"
+	if _, err := client.clientset.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	diffs, err = DiffKueueObjects(ctx, client, cfg)
+	if err != nil {
+		t.Fatalf("DiffKueueObjects() error = %v", err)
+	}
+	want := []ObjectDiff{
+		{Kind: "ResourceFlavor", Name: "manual", State: DiffUnexpected},
+		{Kind: "ClusterQueue", Name: "cq", State: DiffChanged, Fields: []FieldDiff{
+			{Path: "spec.queueingStrategy", Got: `"BestEffortFIFO"`},
+			{Path: "spec.resourceGroups[0].flavors[0].resources[0].nominalQuota", Want: `"10"`, Got: `"8"`},
+		}},
+		{Kind: "LocalQueue", Name: "team-a/lq", State: DiffMissing},
+		{Kind: "Namespace", Name: "team-a", State: DiffChanged, Fields: []FieldDiff{
+			{Path: "metadata.labels.team", Want: `"a"`, Got: `"b"`},
+		}},
+	}
+	if !reflect.DeepEqual(diffs, want) {
+		t.Errorf("DiffKueueObjects() =\n%+v\nwant\n%+v", diffs, want)
+	}
+	if !diffs[1].Fields[0].NotInConfig() || diffs[1].Fields[1].NotInConfig() {
+		t.Errorf("NotInConfig() should only be true for fields the config does not set")
+	}
+}
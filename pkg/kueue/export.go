@@ -0,0 +1,208 @@
+package kueue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+	"sigs.k8s.io/yaml"
+)
+
+// Formats EncodeList writes
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// serverSetFields are the metadata fields the API server sets, dropped from exported items
+// so the list can be created on another cluster
+var serverSetFields = []string{
+	"uid", "resourceVersion", "generation", "creationTimestamp", "selfLink", "managedFields",
+}
+
+// ExportObjects returns the Kueue objects in the cluster as a v1 List, kinds in
+// provisioning order and preceded by the namespaces of the LocalQueues. Every item carries
+// its apiVersion and kind, and server-set metadata and status are dropped, so kubectl, the
+// kubectl-kueue plugin and other Kueue tooling can read the list or apply it to another
+// cluster.
+func (c *Client) ExportObjects(ctx context.Context) (*corev1.List, error) {
+	var objects []runtime.Object
+	cohorts, err := c.ListCohorts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects = appendObjects(objects, cohorts)
+	topologies, err := c.ListTopologies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects = appendObjects(objects, topologies)
+	rfs, err := c.ListResourceFlavors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects = appendObjects(objects, rfs)
+	mkcs, err := c.ListMultiKueueClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects = appendObjects(objects, mkcs)
+	mkConfigs, err := c.ListMultiKueueConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects = appendObjects(objects, mkConfigs)
+	prcs, err := c.ListProvisioningRequestConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects = appendObjects(objects, prcs)
+	acs, err := c.ListAdmissionChecks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects = appendObjects(objects, acs)
+	cqs, err := c.ListClusterQueues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects = appendObjects(objects, cqs)
+	wpcs, err := c.ListWorkloadPriorityClasses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects = appendObjects(objects, wpcs)
+	lqs, err := c.ListLocalQueues(ctx, metav1.NamespaceAll)
+	if err != nil {
+		return nil, err
+	}
+	objects = appendObjects(objects, lqs)
+
+	seen := make(map[string]bool)
+	var namespaces []runtime.Object
+	for _, lq := range lqs {
+		if lq.Namespace == metav1.NamespaceDefault || seen[lq.Namespace] {
+			continue
+		}
+		seen[lq.Namespace] = true
+		namespaces = append(namespaces, newNamespace(config.Namespace{Name: lq.Namespace}))
+	}
+	return newList(append(namespaces, objects...))
+}
+
+// ConfigObjects returns the objects kueueConfig provisions as a v1 List: the declared and
+// LocalQueue namespaces with their ResourceQuotas and LimitRanges, then the Kueue objects
+// ordered as ExportObjects orders them. MultiKueue objects are set up from WorkerSets
+// rather than the config and are not included.
+func ConfigObjects(kueueConfig *config.KueueConfig) (*corev1.List, error) {
+	if kueueConfig == nil {
+		kueueConfig = &config.KueueConfig{}
+	}
+	intended, err := intendedObjects(kueueConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []runtime.Object
+	for _, ns := range namespacesToCreate(kueueConfig) {
+		objects = append(objects, newNamespace(ns))
+		quota, err := BuildResourceQuota(ns)
+		if err != nil {
+			return nil, err
+		}
+		if quota != nil {
+			objects = append(objects, quota)
+		}
+		limitRange, err := BuildLimitRange(ns)
+		if err != nil {
+			return nil, err
+		}
+		if limitRange != nil {
+			objects = append(objects, limitRange)
+		}
+	}
+	for _, kind := range diffKinds {
+		names := make([]string, 0, len(intended[kind]))
+		for name := range intended[kind] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			objects = append(objects, intended[kind][name].(runtime.Object))
+		}
+	}
+	return newList(objects)
+}
+
+// EncodeList writes a List in one of the formats kubectl accepts
+func EncodeList(list *corev1.List, format string) ([]byte, error) {
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode object list: %w", err)
+	}
+	switch format {
+	case FormatJSON:
+		return append(data, '\n'), nil
+	case FormatYAML:
+		return yaml.JSONToYAML(data)
+	default:
+		return nil, fmt.Errorf("unknown format %q: must be %s or %s", format, FormatYAML, FormatJSON)
+	}
+}
+
+// appendObjects appends pointers to listed Kueue objects
+func appendObjects[T any, P interface {
+	*T
+	runtime.Object
+}](objects []runtime.Object, items []T) []runtime.Object {
+	for i := range items {
+		objects = append(objects, P(&items[i]))
+	}
+	return objects
+}
+
+// newNamespace builds a v1 Namespace with the labels of ns
+func newNamespace(ns config.Namespace) *corev1.Namespace {
+	return &corev1.Namespace{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+		ObjectMeta: metav1.ObjectMeta{Name: ns.Name, Labels: ns.Labels},
+	}
+}
+
+// newList wraps objects in a v1 List, setting the apiVersion and kind the typed clients
+// leave empty on Kueue objects and dropping status and server-set metadata. Kueue Go types
+// are named after their kinds.
+func newList(objects []runtime.Object) (*corev1.List, error) {
+	list := &corev1.List{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "List"},
+		Items:    make([]runtime.RawExtension, 0, len(objects)),
+	}
+	for _, obj := range objects {
+		kind := reflect.TypeOf(obj).Elem().Name()
+		if obj.GetObjectKind().GroupVersionKind().Empty() {
+			obj.GetObjectKind().SetGroupVersionKind(kueue.SchemeGroupVersion.WithKind(kind))
+		}
+		item, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", kind, err)
+		}
+		delete(item, "status")
+		for _, field := range serverSetFields {
+			unstructured.RemoveNestedField(item, "metadata", field)
+		}
+		raw, err := json.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", kind, err)
+		}
+		list.Items = append(list.Items, runtime.RawExtension{Raw: raw})
+	}
+	return list, nil
+}
@@ -0,0 +1,112 @@
+package kueue
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+	kueuefake "sigs.k8s.io/kueue/client-go/clientset/versioned/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestExportObjects(t *testing.T) {
+	client := &Client{kueueClient: kueuefake.NewSimpleClientset(
+		&kueue.LocalQueue{ObjectMeta: metav1.ObjectMeta{Name: "lq", Namespace: "team-a"}},
+		&kueue.ClusterQueue{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "cq",
+				UID:               "0b6f3c1e",
+				ResourceVersion:   "42",
+				Generation:        3,
+				CreationTimestamp: metav1.Now(),
+				ManagedFields:     []metav1.ManagedFieldsEntry{{Manager: "kueue-bench"}},
+			},
+			Status: kueue.ClusterQueueStatus{PendingWorkloads: 2},
+		},
+		&kueue.ResourceFlavor{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+	)}
+
+	list, err := client.ExportObjects(context.TODO())
+	if err != nil {
+		t.Fatalf("ExportObjects() error = %v", err)
+	}
+	data, err := EncodeList(list, FormatYAML)
+	if err != nil {
+		t.Fatalf("EncodeList() error = %v", err)
+	}
+
+	var decoded unstructured.UnstructuredList
+	if err := yaml.Unmarshal(data, &decoded.Object); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.GetAPIVersion() != "v1" || decoded.GetKind() != "List" {
+		t.Errorf("list = %s %s, want v1 List", decoded.GetAPIVersion(), decoded.GetKind())
+	}
+	items, _, _ := unstructured.NestedSlice(decoded.Object, "items")
+	var got []string
+	for _, item := range items {
+		obj := unstructured.Unstructured{Object: item.(map[string]interface{})}
+		got = append(got, obj.GetAPIVersion()+" "+obj.GetKind()+" "+obj.GetName())
+		for _, field := range serverSetFields {
+			if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "metadata", field); found {
+				t.Errorf("%s %s keeps metadata.%s", obj.GetKind(), obj.GetName(), field)
+			}
+		}
+		if _, found := obj.Object["status"]; found {
+			t.Errorf("%s %s keeps status", obj.GetKind(), obj.GetName())
+		}
+	}
+	want := "v1 Namespace team-a,kueue.x-k8s.io/v1beta2 ResourceFlavor default,kueue.x-k8s.io/v1beta2 ClusterQueue cq,kueue.x-k8s.io/v1beta2 LocalQueue lq"
+	if strings.Join(got, ",") != want {
+		t.Errorf("items = %v, want %s", got, want)
+	}
+}
+
+func TestConfigObjects(t *testing.T) {
+	list, err := ConfigObjects(&config.KueueConfig{
+		ResourceFlavors: []config.ResourceFlavor{{Name: "spot"}, {Name: "on-demand"}},
+		Namespaces:      []config.Namespace{{Name: "team-b", ResourceQuota: map[string]string{"requests.cpu": "8"}}},
+		LocalQueues: []config.LocalQueue{
+			{Name: "lq", Namespace: "team-a", ClusterQueue: "cq"},
+			{Name: "default-lq", Namespace: "default", ClusterQueue: "cq"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ConfigObjects() error = %v", err)
+	}
+	data, err := EncodeList(list, FormatJSON)
+	if err != nil {
+		t.Fatalf("EncodeList() error = %v", err)
+	}
+	for _, fragment := range []string{`"kind": "List"`, `"kind": "ResourceFlavor"`, `"kind": "LocalQueue"`, `"kind": "ResourceQuota"`} {
+		if !strings.Contains(string(data), fragment) {
+			t.Errorf("JSON output lacks %s:\n%s", fragment, data)
+		}
+	}
+	if strings.Index(string(data), `"on-demand"`) > strings.Index(string(data), `"spot"`) {
+		t.Error("ResourceFlavors are not sorted by name")
+	}
+	var namespaces []string
+	for _, item := range list.Items {
+		var obj unstructured.Unstructured
+		if err := obj.UnmarshalJSON(item.Raw); err != nil {
+			t.Fatal(err)
+		}
+		if obj.GetKind() == "Namespace" {
+			namespaces = append(namespaces, obj.GetName())
+		}
+	}
+	if strings.Join(namespaces, ",") != "team-b,team-a" {
+		t.Errorf("namespaces = %v, want the declared and LocalQueue namespaces [team-b team-a]", namespaces)
+	}
+	if strings.Index(string(data), `"kind": "Namespace"`) > strings.Index(string(data), `"kind": "LocalQueue"`) {
+		t.Error("namespaces are not listed before the LocalQueues")
+	}
+	if _, err := EncodeList(list, "table"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
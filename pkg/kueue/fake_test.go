@@ -0,0 +1,114 @@
+package kueue
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+)
+
+// recordingCreator is an ObjectCreator that records every object it is asked to create
+type recordingCreator struct {
+	mu sync.Mutex
+	// kinds lists the kinds created, in the order each was first created
+	kinds []string
+	// objects holds the created objects, keyed by "Kind/name" or "Kind/namespace/name"
+	objects map[string]any
+	// failKind makes creating objects of this kind fail
+	failKind string
+}
+
+func newRecordingCreator() *recordingCreator {
+	return &recordingCreator{objects: make(map[string]any)}
+}
+
+var _ ObjectCreator = (*recordingCreator)(nil)
+
+func (r *recordingCreator) record(kind, namespace, name string, obj any) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if kind == r.failKind {
+		return fmt.Errorf("injected failure creating %s %s", kind, name)
+	}
+	if len(r.kinds) == 0 || r.kinds[len(r.kinds)-1] != kind {
+		r.kinds = append(r.kinds, kind)
+	}
+	key := kind + "/" + name
+	if namespace != "" {
+		key = kind + "/" + namespace + "/" + name
+	}
+	r.objects[key] = obj
+	return nil
+}
+
+// keys returns the keys of the created objects, sorted
+func (r *recordingCreator) keys() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Sorted(maps.Keys(r.objects))
+}
+
+func (r *recordingCreator) CreateCohort(_ context.Context, cohort *kueue.Cohort) error {
+	return r.record("Cohort", "", cohort.Name, cohort)
+}
+
+func (r *recordingCreator) CreateTopology(_ context.Context, topology *kueue.Topology) error {
+	return r.record("Topology", "", topology.Name, topology)
+}
+
+func (r *recordingCreator) CreateResourceFlavor(_ context.Context, rf *kueue.ResourceFlavor) error {
+	return r.record("ResourceFlavor", "", rf.Name, rf)
+}
+
+func (r *recordingCreator) CreateProvisioningRequestConfig(_ context.Context, prc *kueue.ProvisioningRequestConfig) error {
+	return r.record("ProvisioningRequestConfig", "", prc.Name, prc)
+}
+
+func (r *recordingCreator) CreateAdmissionCheck(_ context.Context, ac *kueue.AdmissionCheck) error {
+	return r.record("AdmissionCheck", "", ac.Name, ac)
+}
+
+func (r *recordingCreator) CreateClusterQueue(_ context.Context, cq *kueue.ClusterQueue) error {
+	return r.record("ClusterQueue", "", cq.Name, cq)
+}
+
+func (r *recordingCreator) CreatePriorityClass(_ context.Context, pc *schedulingv1.PriorityClass) error {
+	return r.record("PriorityClass", "", pc.Name, pc)
+}
+
+func (r *recordingCreator) CreateResourceQuota(_ context.Context, quota *corev1.ResourceQuota) error {
+	return r.record("ResourceQuota", quota.Namespace, quota.Name, quota)
+}
+
+func (r *recordingCreator) CreateLimitRange(_ context.Context, limitRange *corev1.LimitRange) error {
+	return r.record("LimitRange", limitRange.Namespace, limitRange.Name, limitRange)
+}
+
+func (r *recordingCreator) CreateWorkloadPriorityClass(_ context.Context, wpc *kueue.WorkloadPriorityClass) error {
+	return r.record("WorkloadPriorityClass", "", wpc.Name, wpc)
+}
+
+func (r *recordingCreator) CreateNamespace(_ context.Context, name string, labels map[string]string) error {
+	return r.record("Namespace", "", name, labels)
+}
+
+func (r *recordingCreator) CreateLocalQueue(_ context.Context, lq *kueue.LocalQueue) error {
+	return r.record("LocalQueue", lq.Namespace, lq.Name, lq)
+}
+
+func (r *recordingCreator) CreateKubeconfigSecret(_ context.Context, namespace, name string, kubeconfigData []byte) error {
+	return r.record("Secret", namespace, name, kubeconfigData)
+}
+
+func (r *recordingCreator) CreateMultiKueueCluster(_ context.Context, mkc *kueue.MultiKueueCluster) error {
+	return r.record("MultiKueueCluster", "", mkc.Name, mkc)
+}
+
+func (r *recordingCreator) CreateMultiKueueConfig(_ context.Context, mkc *kueue.MultiKueueConfig) error {
+	return r.record("MultiKueueConfig", "", mkc.Name, mkc)
+}
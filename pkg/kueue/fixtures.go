@@ -0,0 +1,210 @@
+package kueue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BuildResourceQuota builds the ResourceQuota of a declared namespace, or returns nil if
+// the namespace sets none
+func BuildResourceQuota(ns config.Namespace) (*corev1.ResourceQuota, error) {
+	if len(ns.ResourceQuota) == 0 {
+		return nil, nil
+	}
+	hard, err := parseResourceList(ns.ResourceQuota)
+	if err != nil {
+		return nil, fmt.Errorf("namespace %s: resourceQuota: %w", ns.Name, err)
+	}
+	return &corev1.ResourceQuota{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ResourceQuota"},
+		ObjectMeta: metav1.ObjectMeta{Name: config.FixtureName, Namespace: ns.Name},
+		Spec:       corev1.ResourceQuotaSpec{Hard: hard},
+	}, nil
+}
+
+// BuildLimitRange builds the LimitRange of a declared namespace, or returns nil if the
+// namespace sets none
+func BuildLimitRange(ns config.Namespace) (*corev1.LimitRange, error) {
+	if ns.LimitRange == nil {
+		return nil, nil
+	}
+	item := corev1.LimitRangeItem{Type: corev1.LimitTypeContainer}
+	var err error
+	for _, field := range []struct {
+		name string
+		list map[string]string
+		dst  *corev1.ResourceList
+	}{
+		{"default", ns.LimitRange.Default, &item.Default},
+		{"defaultRequest", ns.LimitRange.DefaultRequest, &item.DefaultRequest},
+		{"min", ns.LimitRange.Min, &item.Min},
+		{"max", ns.LimitRange.Max, &item.Max},
+	} {
+		if *field.dst, err = parseResourceList(field.list); err != nil {
+			return nil, fmt.Errorf("namespace %s: limitRange: %s: %w", ns.Name, field.name, err)
+		}
+	}
+	return &corev1.LimitRange{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "LimitRange"},
+		ObjectMeta: metav1.ObjectMeta{Name: config.FixtureName, Namespace: ns.Name},
+		Spec:       corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{item}},
+	}, nil
+}
+
+// parseResourceList parses resource quantities by name, returning nil for an empty list
+func parseResourceList(list map[string]string) (corev1.ResourceList, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	parsed := make(corev1.ResourceList, len(list))
+	for name, value := range list {
+		q, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity '%s' for %s: %w", value, name, err)
+		}
+		parsed[corev1.ResourceName(name)] = q
+	}
+	return parsed, nil
+}
+
+// BuildPriorityClass builds a Kubernetes PriorityClass from a config PodPriorityClass
+func BuildPriorityClass(pc config.PodPriorityClass) *schedulingv1.PriorityClass {
+	class := &schedulingv1.PriorityClass{
+		TypeMeta:      metav1.TypeMeta{APIVersion: schedulingv1.SchemeGroupVersion.String(), Kind: "PriorityClass"},
+		ObjectMeta:    metav1.ObjectMeta{Name: pc.Name},
+		Value:         pc.Value,
+		GlobalDefault: pc.GlobalDefault,
+		Description:   pc.Description,
+	}
+	if pc.PreemptionPolicy != "" {
+		policy := corev1.PreemptionPolicy(pc.PreemptionPolicy)
+		class.PreemptionPolicy = &policy
+	}
+	return class
+}
+
+// CreateResourceQuota creates or updates a ResourceQuota
+func (c *Client) CreateResourceQuota(ctx context.Context, quota *corev1.ResourceQuota) error {
+	c.own(quota)
+	quotas := c.clientset.CoreV1().ResourceQuotas(quota.Namespace)
+	_, err := quotas.Create(ctx, quota, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		existing, getErr := quotas.Get(ctx, quota.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return fmt.Errorf("failed to get ResourceQuota %s/%s: %w", quota.Namespace, quota.Name, getErr)
+		}
+		quota.ResourceVersion = existing.ResourceVersion
+		_, err = quotas.Update(ctx, quota, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create or update ResourceQuota %s/%s: %w", quota.Namespace, quota.Name, err)
+	}
+	return nil
+}
+
+// CreateLimitRange creates or updates a LimitRange
+func (c *Client) CreateLimitRange(ctx context.Context, limitRange *corev1.LimitRange) error {
+	c.own(limitRange)
+	limitRanges := c.clientset.CoreV1().LimitRanges(limitRange.Namespace)
+	_, err := limitRanges.Create(ctx, limitRange, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		existing, getErr := limitRanges.Get(ctx, limitRange.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return fmt.Errorf("failed to get LimitRange %s/%s: %w", limitRange.Namespace, limitRange.Name, getErr)
+		}
+		limitRange.ResourceVersion = existing.ResourceVersion
+		_, err = limitRanges.Update(ctx, limitRange, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create or update LimitRange %s/%s: %w", limitRange.Namespace, limitRange.Name, err)
+	}
+	return nil
+}
+
+// CreatePriorityClass creates or updates a Kubernetes PriorityClass. A PriorityClass's
+// value cannot change, so a class whose value differs is deleted and recreated.
+func (c *Client) CreatePriorityClass(ctx context.Context, pc *schedulingv1.PriorityClass) error {
+	c.own(pc)
+	classes := c.clientset.SchedulingV1().PriorityClasses()
+	_, err := classes.Create(ctx, pc, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		existing, getErr := classes.Get(ctx, pc.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return fmt.Errorf("failed to get PriorityClass %s: %w", pc.Name, getErr)
+		}
+		if existing.Value != pc.Value {
+			if err := classes.Delete(ctx, pc.Name, metav1.DeleteOptions{}); err != nil {
+				return fmt.Errorf("failed to delete PriorityClass %s to change its value: %w", pc.Name, err)
+			}
+			_, err = classes.Create(ctx, pc, metav1.CreateOptions{})
+		} else {
+			pc.ResourceVersion = existing.ResourceVersion
+			_, err = classes.Update(ctx, pc, metav1.UpdateOptions{})
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create or update PriorityClass %s: %w", pc.Name, err)
+	}
+	return nil
+}
+
+// ListResourceQuotas returns the ResourceQuotas in a namespace (metav1.NamespaceAll for all namespaces)
+func (c *Client) ListResourceQuotas(ctx context.Context, namespace string) ([]corev1.ResourceQuota, error) {
+	list, err := c.clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ResourceQuotas: %w", err)
+	}
+	return list.Items, nil
+}
+
+// ListLimitRanges returns the LimitRanges in a namespace (metav1.NamespaceAll for all namespaces)
+func (c *Client) ListLimitRanges(ctx context.Context, namespace string) ([]corev1.LimitRange, error) {
+	list, err := c.clientset.CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list LimitRanges: %w", err)
+	}
+	return list.Items, nil
+}
+
+// ListPriorityClasses returns all Kubernetes PriorityClasses
+func (c *Client) ListPriorityClasses(ctx context.Context) ([]schedulingv1.PriorityClass, error) {
+	list, err := c.clientset.SchedulingV1().PriorityClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PriorityClasses: %w", err)
+	}
+	return list.Items, nil
+}
+
+// DeleteResourceQuota deletes a ResourceQuota. A missing ResourceQuota is not an error.
+func (c *Client) DeleteResourceQuota(ctx context.Context, namespace, name string) error {
+	err := c.clientset.CoreV1().ResourceQuotas(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete ResourceQuota %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// DeleteLimitRange deletes a LimitRange. A missing LimitRange is not an error.
+func (c *Client) DeleteLimitRange(ctx context.Context, namespace, name string) error {
+	err := c.clientset.CoreV1().LimitRanges(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete LimitRange %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
+
+// DeletePriorityClass deletes a Kubernetes PriorityClass. A missing PriorityClass is not an error.
+func (c *Client) DeletePriorityClass(ctx context.Context, name string) error {
+	err := c.clientset.SchedulingV1().PriorityClasses().Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete PriorityClass %s: %w", name, err)
+	}
+	return nil
+}
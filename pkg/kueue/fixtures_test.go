@@ -0,0 +1,71 @@
+package kueue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBuildLimitRange(t *testing.T) {
+	ns := config.Namespace{
+		Name: "team-a",
+		LimitRange: &config.LimitRange{
+			Default:        map[string]string{"cpu": "2", "memory": "4Gi"},
+			DefaultRequest: map[string]string{"cpu": "500m"},
+			Max:            map[string]string{"nvidia.com/gpu": "8"},
+		},
+	}
+	lr, err := BuildLimitRange(ns)
+	if err != nil {
+		t.Fatalf("BuildLimitRange() error = %v", err)
+	}
+	if lr.Name != config.FixtureName || lr.Namespace != "team-a" || len(lr.Spec.Limits) != 1 {
+		t.Fatalf("BuildLimitRange() = %+v, want one limit in team-a/%s", lr, config.FixtureName)
+	}
+	item := lr.Spec.Limits[0]
+	if item.Type != corev1.LimitTypeContainer {
+		t.Errorf("limit type = %s, want Container", item.Type)
+	}
+	if got := item.DefaultRequest[corev1.ResourceCPU]; got.String() != "500m" {
+		t.Errorf("defaultRequest cpu = %s, want 500m", got.String())
+	}
+	if got := item.Max["nvidia.com/gpu"]; got.String() != "8" {
+		t.Errorf("max nvidia.com/gpu = %s, want 8", got.String())
+	}
+	if item.Min != nil {
+		t.Errorf("min = %v, want unset", item.Min)
+	}
+
+	if lr, err := BuildLimitRange(config.Namespace{Name: "team-b"}); lr != nil || err != nil {
+		t.Errorf("BuildLimitRange() without limitRange = %v, %v; want nil", lr, err)
+	}
+	if _, err := BuildResourceQuota(config.Namespace{Name: "team-c", ResourceQuota: map[string]string{"pods": "many"}}); err == nil {
+		t.Error("expected an error for an invalid quota quantity")
+	}
+}
+
+func TestCreatePriorityClass(t *testing.T) {
+	ctx := context.TODO()
+	client := &Client{clientset: fake.NewClientset()}
+
+	if err := client.CreatePriorityClass(ctx, BuildPriorityClass(config.PodPriorityClass{Name: "batch", Value: 100})); err != nil {
+		t.Fatalf("CreatePriorityClass() error = %v", err)
+	}
+	// The value of a PriorityClass is immutable, so changing it recreates the class
+	pc := BuildPriorityClass(config.PodPriorityClass{Name: "batch", Value: 200, PreemptionPolicy: config.PreemptNever})
+	if err := client.CreatePriorityClass(ctx, pc); err != nil {
+		t.Fatalf("CreatePriorityClass() with a new value error = %v", err)
+	}
+
+	got, err := client.clientset.SchedulingV1().PriorityClasses().Get(ctx, "batch", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Value != 200 || got.PreemptionPolicy == nil || *got.PreemptionPolicy != corev1.PreemptNever {
+		t.Errorf("PriorityClass = value %d, policy %v; want 200, Never", got.Value, got.PreemptionPolicy)
+	}
+}
@@ -3,13 +3,17 @@ package kueue
 import (
 	"context"
 	"fmt"
+	"os"
+	"sort"
 	"time"
 
+	"github.com/jhwagner/kueue-bench/pkg/config"
 	"github.com/jhwagner/kueue-bench/pkg/helm"
+	"github.com/jhwagner/kueue-bench/pkg/manifest"
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/client-go/tools/clientcmd"
 	kueuev1beta2 "sigs.k8s.io/kueue/apis/kueue/v1beta2"
 	kueueclientset "sigs.k8s.io/kueue/client-go/clientset/versioned"
 )
@@ -24,25 +28,56 @@ const (
 	// Kueue installation details
 	kueueNamespace   = "kueue-system"
 	kueueReleaseName = "kueue"
-)
 
-// Install installs Kueue into the cluster via Helm
-func Install(ctx context.Context, kubeconfigPath string, version string, helmValues map[string]interface{}) error {
-	if version == "" {
-		version = DefaultKueueVersion
-	}
+	// webhookReadyTimeout bounds the wait for the Kueue webhook to serve
+	webhookReadyTimeout = 180 * time.Second
+)
 
-	fmt.Printf("Installing Kueue %s...\n", version)
+// InstallOptions selects where Kueue is installed from. By default the release chart of
+// Version is pulled from the Kueue registry; Chart or Manifest install an unreleased build.
+type InstallOptions struct {
+	Version    string
+	HelmValues map[string]interface{}
+	// Chart is a local chart directory or packaged .tgz (optional)
+	Chart string
+	// Manifest is a rendered manifest file applied instead of a chart (optional)
+	Manifest string
+	// ManagerConfig is rendered into the chart's manager Configuration (optional). Chart
+	// installs always configure Kueue to copy the run label onto Workloads; see
+	// CopiesRunLabel.
+	ManagerConfig *config.KueueManagerConfig
+	// FeatureGates are passed to the Kueue manager's --feature-gates flag (optional)
+	FeatureGates map[string]bool
+}
 
-	// Install Kueue via Helm
-	if err := installKueueChart(ctx, kubeconfigPath, version, helmValues); err != nil {
-		return fmt.Errorf("failed to install Kueue chart: %w", err)
+// Install installs Kueue into the cluster via Helm, or from a rendered manifest
+func Install(ctx context.Context, kubeconfigPath string, opts InstallOptions, clientOpts ...restconfig.Option) error {
+	switch {
+	case opts.Manifest != "":
+		fmt.Printf("Installing Kueue from manifest %s...\n", opts.Manifest)
+		if err := installKueueManifest(ctx, kubeconfigPath, opts.Manifest, clientOpts); err != nil {
+			return fmt.Errorf("failed to install Kueue manifest: %w", err)
+		}
+	case opts.Chart != "":
+		fmt.Printf("Installing Kueue from chart %s...\n", opts.Chart)
+		if err := installKueueChart(ctx, kubeconfigPath, opts.Chart, "", opts); err != nil {
+			return fmt.Errorf("failed to install Kueue chart: %w", err)
+		}
+	default:
+		version := opts.Version
+		if version == "" {
+			version = DefaultKueueVersion
+		}
+		fmt.Printf("Installing Kueue %s...\n", version)
+		if err := installKueueChart(ctx, kubeconfigPath, kueueHelmRegistryURL, version, opts); err != nil {
+			return fmt.Errorf("failed to install Kueue chart: %w", err)
+		}
 	}
 
 	// Wait for the webhook to be serving before returning, otherwise callers
 	// creating Kueue objects may hit "connection refused" on the webhook
 	fmt.Println("Waiting for Kueue webhook to be ready...")
-	if err := waitForWebhookReady(ctx, kubeconfigPath); err != nil {
+	if err := waitForWebhookReady(ctx, kubeconfigPath, clientOpts); err != nil {
 		return fmt.Errorf("kueue webhook failed to become ready: %w", err)
 	}
 
@@ -50,28 +85,100 @@ func Install(ctx context.Context, kubeconfigPath string, version string, helmVal
 	return nil
 }
 
-// installKueueChart installs the Kueue Helm chart using the Helm SDK
-func installKueueChart(ctx context.Context, kubeconfigPath string, version string, helmValues map[string]interface{}) error {
-	return helm.Install(ctx, helm.InstallOptions{
+// Uninstall removes the Kueue Helm release. Kueue's CRDs are kept, so Kueue objects
+// survive and are picked up by the next Install. Manifest installs cannot be uninstalled.
+func Uninstall(ctx context.Context, kubeconfigPath string, clientOpts ...restconfig.Option) error {
+	fmt.Println("Uninstalling Kueue...")
+	client, err := NewClient(kubeconfigPath, clientOpts...)
+	if err != nil {
+		return err
+	}
+	if err := client.keepCRDs(ctx); err != nil {
+		return fmt.Errorf("failed to keep Kueue CRDs: %w", err)
+	}
+
+	if err := helm.Uninstall(ctx, helm.UninstallOptions{
+		KubeconfigPath: kubeconfigPath,
+		Namespace:      kueueNamespace,
+		ReleaseName:    kueueReleaseName,
+		Wait:           true,
+		Timeout:        5 * time.Minute,
+	}); err != nil {
+		return fmt.Errorf("failed to uninstall Kueue chart: %w", err)
+	}
+
+	fmt.Println("✓ Kueue uninstalled")
+	return nil
+}
+
+// installKueueChart installs the Kueue Helm chart using the Helm SDK. chartRef is the
+// registry URL or a local chart path; version is empty for local charts.
+func installKueueChart(ctx context.Context, kubeconfigPath, chartRef, version string, opts InstallOptions) error {
+	if len(opts.FeatureGates) > 0 {
+		opts.HelmValues = helm.MergeValues(opts.HelmValues, featureGateValues(opts.FeatureGates))
+	}
+
+	installOpts := helm.InstallOptions{
 		KubeconfigPath:  kubeconfigPath,
 		Namespace:       kueueNamespace,
 		ReleaseName:     kueueReleaseName,
-		ChartRef:        kueueHelmRegistryURL,
+		ChartRef:        chartRef,
 		Version:         version,
-		Values:          helmValues,
+		Values:          opts.HelmValues,
 		CreateNamespace: true,
 		Wait:            true,
 		Timeout:         5 * time.Minute,
-	})
+	}
+	managerConfig := opts.ManagerConfig
+	if managerConfig == nil {
+		managerConfig = &config.KueueManagerConfig{}
+	}
+	installOpts.ValuesFunc = func(chartDefaults map[string]interface{}) (map[string]interface{}, error) {
+		return managerConfigValues(managerConfig, opts.HelmValues, chartDefaults)
+	}
+	return helm.Install(ctx, installOpts)
+}
+
+// CopiesRunLabel reports whether Kueue installed with opts copies the run label of jobs
+// onto their Workloads. Manifest installs keep the manifest's manager Configuration.
+func (opts InstallOptions) CopiesRunLabel() bool {
+	return opts.Manifest == ""
+}
+
+// featureGateValues returns chart values setting the manager's feature gates, sorted by name
+func featureGateValues(gates map[string]bool) map[string]interface{} {
+	names := make([]string, 0, len(gates))
+	for name := range gates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		list = append(list, map[string]interface{}{"name": name, "enabled": gates[name]})
+	}
+	return map[string]interface{}{
+		"controllerManager": map[string]interface{}{"featureGates": list},
+	}
+}
+
+// installKueueManifest applies a rendered Kueue manifest (e.g. `make artifacts` output)
+func installKueueManifest(ctx context.Context, kubeconfigPath, path string, clientOpts []restconfig.Option) error {
+	data, err := os.ReadFile(path) //nolint:gosec // path is user-provided topology input
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return manifest.ApplyBytesWithKubeconfig(ctx, kubeconfigPath, data, clientOpts...)
 }
 
 // waitForWebhookReady probes the Kueue webhook by performing a dry-run create of a
 // ResourceFlavor. This exercises the full webhook path (API server → Service routing →
-// Pod → webhook handler) and only succeeds when the webhook is truly serving.
-func waitForWebhookReady(ctx context.Context, kubeconfigPath string) error {
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+// Pod → webhook handler) and only succeeds when the webhook is truly serving. There is no
+// object to watch for this, so it polls; on timeout the error carries the last failure.
+func waitForWebhookReady(ctx context.Context, kubeconfigPath string, clientOpts []restconfig.Option) error {
+	config, err := restconfig.ForKubeconfig(kubeconfigPath, 0, clientOpts...)
 	if err != nil {
-		return fmt.Errorf("failed to load kubeconfig: %w", err)
+		return err
 	}
 
 	client, err := kueueclientset.NewForConfig(config)
@@ -84,8 +191,13 @@ func waitForWebhookReady(ctx context.Context, kubeconfigPath string) error {
 	}
 	dryRun := metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}}
 
-	return wait.PollUntilContextTimeout(ctx, 2*time.Second, 180*time.Second, true, func(ctx context.Context) (bool, error) {
-		_, err := client.KueueV1beta2().ResourceFlavors().Create(ctx, probe, dryRun)
-		return err == nil, nil
+	var lastErr error
+	err = wait.PollUntilContextTimeout(ctx, 2*time.Second, webhookReadyTimeout, true, func(ctx context.Context) (bool, error) {
+		_, lastErr = client.KueueV1beta2().ResourceFlavors().Create(ctx, probe, dryRun)
+		return lastErr == nil, nil
 	})
+	if err != nil && lastErr != nil {
+		return fmt.Errorf("webhook not ready after %s: %w", webhookReadyTimeout, lastErr)
+	}
+	return err
 }
@@ -0,0 +1,21 @@
+package kueue
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFeatureGateValues(t *testing.T) {
+	got := featureGateValues(map[string]bool{"TopologyAwareScheduling": true, "PartialAdmission": false})
+	want := map[string]interface{}{
+		"controllerManager": map[string]interface{}{
+			"featureGates": []interface{}{
+				map[string]interface{}{"name": "PartialAdmission", "enabled": false},
+				map[string]interface{}{"name": "TopologyAwareScheduling", "enabled": true},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("featureGateValues() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package kueue
+
+import (
+	"context"
+	"fmt"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+)
+
+// How the management cluster handled a Workload dispatched to a lost MultiKueue worker
+const (
+	// LostClusterRequeued is a Workload evicted from the lost worker, whether or not it was
+	// admitted again elsewhere
+	LostClusterRequeued = "requeued"
+	// LostClusterRejected is a Workload that finished, was deactivated or deleted, or had an
+	// AdmissionCheck rejected
+	LostClusterRejected = "rejected"
+)
+
+// ListWorkloads returns the Workloads in all namespaces
+func (c *Client) ListWorkloads(ctx context.Context) ([]kueue.Workload, error) {
+	list, err := c.kueueClient.KueueV1beta2().Workloads(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Workloads: %w", err)
+	}
+	return list.Items, nil
+}
+
+// DispatchedTo reports whether a management Workload holds quota and runs on one of the
+// given MultiKueueClusters
+func DispatchedTo(wl *kueue.Workload, clusters map[string]bool) bool {
+	return wl.Status.ClusterName != nil && clusters[*wl.Status.ClusterName] &&
+		apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadQuotaReserved) &&
+		!apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadFinished)
+}
+
+// LostClusterOutcome reports how the management cluster handled a Workload that was
+// dispatched to one of the given MultiKueueClusters before its worker was lost. ok is false
+// while the Workload still waits on the lost worker. A nil Workload was deleted.
+func LostClusterOutcome(wl *kueue.Workload, clusters map[string]bool) (outcome string, ok bool) {
+	if wl == nil || apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadFinished) ||
+		(wl.Spec.Active != nil && !*wl.Spec.Active) {
+		return LostClusterRejected, true
+	}
+	for _, check := range wl.Status.AdmissionChecks {
+		if check.State == kueue.CheckStateRejected {
+			return LostClusterRejected, true
+		}
+	}
+	if !DispatchedTo(wl, clusters) {
+		return LostClusterRequeued, true
+	}
+	return "", false
+}
@@ -0,0 +1,89 @@
+package kueue
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+)
+
+func TestLostClusterOutcome(t *testing.T) {
+	lost := map[string]bool{"ws-worker-1": true}
+	reserved := metav1.Condition{Type: kueue.WorkloadQuotaReserved, Status: metav1.ConditionTrue}
+	inactive := false
+
+	workload := func(cluster string, conditions ...metav1.Condition) *kueue.Workload {
+		wl := &kueue.Workload{ObjectMeta: metav1.ObjectMeta{Name: "wl", Namespace: "default"}}
+		if cluster != "" {
+			wl.Status.ClusterName = &cluster
+		}
+		wl.Status.Conditions = conditions
+		return wl
+	}
+
+	tests := []struct {
+		name        string
+		wl          *kueue.Workload
+		wantOutcome string
+		wantOK      bool
+	}{
+		{
+			name:   "still on lost worker",
+			wl:     workload("ws-worker-1", reserved),
+			wantOK: false,
+		},
+		{
+			name:        "evicted",
+			wl:          workload(""),
+			wantOutcome: LostClusterRequeued,
+			wantOK:      true,
+		},
+		{
+			name:        "admitted on another worker",
+			wl:          workload("ws-worker-2", reserved),
+			wantOutcome: LostClusterRequeued,
+			wantOK:      true,
+		},
+		{
+			name:        "finished",
+			wl:          workload("ws-worker-1", reserved, metav1.Condition{Type: kueue.WorkloadFinished, Status: metav1.ConditionTrue}),
+			wantOutcome: LostClusterRejected,
+			wantOK:      true,
+		},
+		{
+			name: "deactivated",
+			wl: func() *kueue.Workload {
+				wl := workload("ws-worker-1", reserved)
+				wl.Spec.Active = &inactive
+				return wl
+			}(),
+			wantOutcome: LostClusterRejected,
+			wantOK:      true,
+		},
+		{
+			name: "admission check rejected",
+			wl: func() *kueue.Workload {
+				wl := workload("ws-worker-1", reserved)
+				wl.Status.AdmissionChecks = []kueue.AdmissionCheckState{{Name: "ws", State: kueue.CheckStateRejected}}
+				return wl
+			}(),
+			wantOutcome: LostClusterRejected,
+			wantOK:      true,
+		},
+		{
+			name:        "deleted",
+			wl:          nil,
+			wantOutcome: LostClusterRejected,
+			wantOK:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome, ok := LostClusterOutcome(tt.wl, lost)
+			if outcome != tt.wantOutcome || ok != tt.wantOK {
+				t.Errorf("LostClusterOutcome() = (%q, %v), want (%q, %v)", outcome, ok, tt.wantOutcome, tt.wantOK)
+			}
+		})
+	}
+}
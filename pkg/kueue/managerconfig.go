@@ -0,0 +1,159 @@
+package kueue
+
+import (
+	"fmt"
+	"slices"
+
+	configv1beta2 "sigs.k8s.io/kueue/apis/config/v1beta2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/helm"
+	"github.com/jhwagner/kueue-bench/pkg/ownership"
+)
+
+const (
+	// managerConfigYAMLKey holds the manager Configuration in the chart's managerConfig values
+	managerConfigYAMLKey = "controllerManagerConfigYaml"
+
+	// Defaults applied when an enabled section leaves a required duration unset
+	defaultPodsReadyTimeout      = "5m"
+	defaultUsageHalfLifeTime     = "10m"
+	defaultUsageSamplingInterval = "5m"
+)
+
+// multiKueueDispatcherNames maps config dispatchers to Kueue's dispatcher names
+var multiKueueDispatcherNames = map[string]string{
+	config.DispatcherAllAtOnce:   configv1beta2.MultiKueueDispatcherModeAllAtOnce,
+	config.DispatcherIncremental: configv1beta2.MultiKueueDispatcherModeIncremental,
+}
+
+// managerConfigValues returns values with cfg rendered into the manager Configuration.
+// The Configuration is read from values when set there, otherwise from the chart defaults,
+// so fields not managed by cfg keep their existing settings.
+func managerConfigValues(cfg *config.KueueManagerConfig, values, chartDefaults map[string]interface{}) (map[string]interface{}, error) {
+	base, ok := managerConfigYAML(values)
+	if !ok {
+		base, _ = managerConfigYAML(chartDefaults)
+	}
+
+	conf := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(base), &conf); err != nil {
+		return nil, fmt.Errorf("failed to parse manager configuration: %w", err)
+	}
+	if conf == nil {
+		conf = map[string]interface{}{}
+	}
+	applyManagerConfig(conf, cfg)
+
+	data, err := yaml.Marshal(conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manager configuration: %w", err)
+	}
+	return helm.MergeValues(values, map[string]interface{}{
+		"managerConfig": map[string]interface{}{managerConfigYAMLKey: string(data)},
+	}), nil
+}
+
+// managerConfigYAML returns the manager Configuration YAML from chart values, if set
+func managerConfigYAML(values map[string]interface{}) (string, bool) {
+	managerConfig, ok := values["managerConfig"].(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	data, ok := managerConfig[managerConfigYAMLKey].(string)
+	return data, ok
+}
+
+// applyManagerConfig sets the Configuration fields managed by cfg on a decoded Configuration.
+// Disabled sections are removed so Kueue falls back to its defaults.
+func applyManagerConfig(conf map[string]interface{}, cfg *config.KueueManagerConfig) {
+	if w := cfg.WaitForPodsReady; w != nil {
+		if w.Enable {
+			section := map[string]interface{}{"timeout": valueOr(w.Timeout, defaultPodsReadyTimeout)}
+			if w.BlockAdmission != nil {
+				section["blockAdmission"] = *w.BlockAdmission
+			}
+			if w.RecoveryTimeout != "" {
+				section["recoveryTimeout"] = w.RecoveryTimeout
+			}
+			conf["waitForPodsReady"] = section
+		} else {
+			delete(conf, "waitForPodsReady")
+		}
+	}
+
+	if f := cfg.FairSharing; f != nil {
+		if f.Enable {
+			// Kueue rejects a fairSharing section without preemptionStrategies
+			strategies := f.PreemptionStrategies
+			if len(strategies) == 0 {
+				strategies = config.DefaultPreemptionStrategies
+			}
+			conf["fairSharing"] = map[string]interface{}{"preemptionStrategies": strategies}
+		} else {
+			delete(conf, "fairSharing")
+		}
+	}
+
+	if a := cfg.AdmissionFairSharing; a != nil {
+		section := map[string]interface{}{
+			"usageHalfLifeTime":     valueOr(a.UsageHalfLifeTime, defaultUsageHalfLifeTime),
+			"usageSamplingInterval": valueOr(a.UsageSamplingInterval, defaultUsageSamplingInterval),
+		}
+		if len(a.ResourceWeights) > 0 {
+			section["resourceWeights"] = a.ResourceWeights
+		}
+		conf["admissionFairSharing"] = section
+	}
+
+	if len(cfg.ExcludeResourcePrefixes) > 0 {
+		subsection(conf, "resources")["excludeResourcePrefixes"] = cfg.ExcludeResourcePrefixes
+	}
+
+	if name, ok := multiKueueDispatcherNames[cfg.MultiKueueDispatcher]; ok {
+		subsection(conf, "multiKueue")["dispatcherName"] = name
+	}
+	if m := cfg.MultiKueue; m != nil && m.WorkerLostTimeout != "" {
+		subsection(conf, "multiKueue")["workerLostTimeout"] = m.WorkerLostTimeout
+	}
+
+	if i := cfg.Integrations; i != nil && len(i.Frameworks) > 0 {
+		subsection(conf, "integrations")["frameworks"] = i.Frameworks
+	}
+
+	// Workloads get their job's run label, so a run's Workloads can be watched by it
+	integrations := subsection(conf, "integrations")
+	keys, _ := integrations["labelKeysToCopy"].([]interface{})
+	if !slices.Contains(keys, interface{}(ownership.RunLabel)) {
+		integrations["labelKeysToCopy"] = append(keys, ownership.RunLabel)
+	}
+
+	if c := cfg.ClientConnection; c != nil {
+		section := subsection(conf, "clientConnection")
+		if c.QPS > 0 {
+			section["qps"] = c.QPS
+		}
+		if c.Burst > 0 {
+			section["burst"] = c.Burst
+		}
+	}
+}
+
+// subsection returns conf[key] as a map, creating it if missing
+func subsection(conf map[string]interface{}, key string) map[string]interface{} {
+	section, ok := conf[key].(map[string]interface{})
+	if !ok {
+		section = map[string]interface{}{}
+		conf[key] = section
+	}
+	return section
+}
+
+// valueOr returns v, or def if v is empty
+func valueOr(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
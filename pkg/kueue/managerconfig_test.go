@@ -0,0 +1,165 @@
+package kueue
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/ownership"
+	configv1beta2 "sigs.k8s.io/kueue/apis/config/v1beta2"
+)
+
+func TestManagerConfigValues(t *testing.T) {
+	const chartConfig = `apiVersion: config.kueue.x-k8s.io/v1beta2
+kind: Configuration
+integrations:
+  frameworks:
+  - batch/job
+  - jobset.x-k8s.io/jobset
+waitForPodsReady:
+  timeout: 1m
+`
+	chartDefaults := map[string]interface{}{
+		"managerConfig": map[string]interface{}{managerConfigYAMLKey: chartConfig},
+	}
+	blockAdmission := true
+
+	tests := []struct {
+		name    string
+		cfg     config.KueueManagerConfig
+		values  map[string]interface{}
+		checkFn func(*testing.T, *configv1beta2.Configuration)
+	}{
+		{
+			name: "fields rendered over chart defaults",
+			cfg: config.KueueManagerConfig{
+				FairSharing:             &config.FairSharingConfig{Enable: true, PreemptionStrategies: []string{config.PreemptionLessThanInitialShare}},
+				ExcludeResourcePrefixes: []string{"example.com/"},
+				ClientConnection:        &config.ClientConnectionConfig{QPS: 100, Burst: 200},
+			},
+			checkFn: func(t *testing.T, c *configv1beta2.Configuration) {
+				if c.Integrations == nil || len(c.Integrations.Frameworks) != 2 {
+					t.Errorf("expected chart integrations to be kept, got %+v", c.Integrations)
+				}
+				if c.WaitForPodsReady == nil || c.WaitForPodsReady.Timeout.Duration != time.Minute {
+					t.Errorf("expected chart waitForPodsReady to be kept, got %+v", c.WaitForPodsReady)
+				}
+				if c.FairSharing == nil || !reflect.DeepEqual(c.FairSharing.PreemptionStrategies, []configv1beta2.PreemptionStrategy{configv1beta2.LessThanInitialShare}) {
+					t.Errorf("unexpected fairSharing: %+v", c.FairSharing)
+				}
+				if c.Resources == nil || !reflect.DeepEqual(c.Resources.ExcludeResourcePrefixes, []string{"example.com/"}) {
+					t.Errorf("unexpected resources: %+v", c.Resources)
+				}
+				if c.ClientConnection == nil || *c.ClientConnection.QPS != 100 || *c.ClientConnection.Burst != 200 {
+					t.Errorf("unexpected clientConnection: %+v", c.ClientConnection)
+				}
+			},
+		},
+		{
+			name: "fair sharing enabled without strategies uses Kueue default",
+			cfg:  config.KueueManagerConfig{FairSharing: &config.FairSharingConfig{Enable: true}},
+			checkFn: func(t *testing.T, c *configv1beta2.Configuration) {
+				want := []configv1beta2.PreemptionStrategy{configv1beta2.LessThanOrEqualToFinalShare, configv1beta2.LessThanInitialShare}
+				if c.FairSharing == nil || !reflect.DeepEqual(c.FairSharing.PreemptionStrategies, want) {
+					t.Errorf("unexpected fairSharing: %+v", c.FairSharing)
+				}
+			},
+		},
+		{
+			name: "MultiKueue dispatcher",
+			cfg:  config.KueueManagerConfig{MultiKueueDispatcher: config.DispatcherIncremental},
+			checkFn: func(t *testing.T, c *configv1beta2.Configuration) {
+				if c.MultiKueue == nil || c.MultiKueue.DispatcherName == nil || *c.MultiKueue.DispatcherName != configv1beta2.MultiKueueDispatcherModeIncremental {
+					t.Errorf("unexpected multiKueue: %+v", c.MultiKueue)
+				}
+			},
+		},
+		{
+			name: "MultiKueue worker lost timeout",
+			cfg: config.KueueManagerConfig{
+				MultiKueue:           &config.MultiKueueConfig{WorkerLostTimeout: "90s"},
+				MultiKueueDispatcher: config.DispatcherAllAtOnce,
+			},
+			checkFn: func(t *testing.T, c *configv1beta2.Configuration) {
+				if c.MultiKueue == nil || c.MultiKueue.WorkerLostTimeout == nil || c.MultiKueue.WorkerLostTimeout.Duration != 90*time.Second {
+					t.Errorf("unexpected multiKueue: %+v", c.MultiKueue)
+				}
+				if c.MultiKueue == nil || c.MultiKueue.DispatcherName == nil || *c.MultiKueue.DispatcherName != configv1beta2.MultiKueueDispatcherModeAllAtOnce {
+					t.Errorf("expected dispatcher to be kept, got %+v", c.MultiKueue)
+				}
+			},
+		},
+		{
+			name: "integrations frameworks replace chart defaults",
+			cfg: config.KueueManagerConfig{Integrations: &config.IntegrationsConfig{
+				Frameworks: []string{config.FrameworkBatchJob, config.FrameworkPyTorchJob},
+			}},
+			checkFn: func(t *testing.T, c *configv1beta2.Configuration) {
+				want := []string{config.FrameworkBatchJob, config.FrameworkPyTorchJob}
+				if c.Integrations == nil || !reflect.DeepEqual(c.Integrations.Frameworks, want) {
+					t.Errorf("unexpected integrations: %+v", c.Integrations)
+				}
+			},
+		},
+		{
+			name: "disabled section removed",
+			cfg:  config.KueueManagerConfig{WaitForPodsReady: &config.WaitForPodsReadyConfig{Enable: false}},
+			checkFn: func(t *testing.T, c *configv1beta2.Configuration) {
+				if c.WaitForPodsReady != nil {
+					t.Errorf("expected waitForPodsReady to be removed, got %+v", c.WaitForPodsReady)
+				}
+			},
+		},
+		{
+			name: "helm values configuration takes precedence over chart defaults",
+			cfg: config.KueueManagerConfig{
+				WaitForPodsReady:     &config.WaitForPodsReadyConfig{Enable: true, BlockAdmission: &blockAdmission},
+				AdmissionFairSharing: &config.AdmissionFairSharingConfig{UsageHalfLifeTime: "1h"},
+			},
+			values: map[string]interface{}{
+				"managerConfig": map[string]interface{}{managerConfigYAMLKey: "manageJobsWithoutQueueName: true\n"},
+			},
+			checkFn: func(t *testing.T, c *configv1beta2.Configuration) {
+				if !c.ManageJobsWithoutQueueName {
+					t.Errorf("expected helmValues configuration to be kept")
+				}
+				if c.Integrations == nil || len(c.Integrations.Frameworks) > 0 {
+					t.Errorf("expected chart defaults to be ignored, got %+v", c.Integrations)
+				}
+				w := c.WaitForPodsReady
+				if w == nil || w.Timeout.Duration != 5*time.Minute || w.BlockAdmission == nil || !*w.BlockAdmission {
+					t.Errorf("unexpected waitForPodsReady: %+v", w)
+				}
+				a := c.AdmissionFairSharing
+				if a == nil || a.UsageHalfLifeTime.Duration != time.Hour || a.UsageSamplingInterval.Duration != 5*time.Minute {
+					t.Errorf("unexpected admissionFairSharing: %+v", a)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := managerConfigValues(&tt.cfg, tt.values, chartDefaults)
+			if err != nil {
+				t.Fatalf("managerConfigValues() error = %v", err)
+			}
+			data, ok := managerConfigYAML(values)
+			if !ok {
+				t.Fatalf("managerConfigValues() did not set %s", managerConfigYAMLKey)
+			}
+			var c configv1beta2.Configuration
+			if err := yaml.UnmarshalStrict([]byte(data), &c); err != nil {
+				t.Fatalf("rendered configuration is not a valid Configuration: %v\n%s", err, data)
+			}
+			if c.Integrations == nil || !slices.Contains(c.Integrations.LabelKeysToCopy, ownership.RunLabel) {
+				t.Errorf("expected the run label to be copied onto Workloads, got %+v", c.Integrations)
+			}
+			tt.checkFn(t, &c)
+		})
+	}
+}
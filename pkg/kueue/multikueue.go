@@ -3,52 +3,64 @@ package kueue
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/jhwagner/kueue-bench/pkg/config"
-)
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
 
-const (
-	// MultiKueueNamespace is the namespace where MultiKueue secrets and resources are created
-	MultiKueueNamespace = "kueue-system"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	kueueclientset "sigs.k8s.io/kueue/client-go/clientset/versioned"
 )
 
 // SetupMultiKueueInfrastructure creates MultiKueue infrastructure on the management cluster.
 // For each WorkerSet, it:
-// - Creates kubeconfig Secrets in kueue-system (one per worker)
-// - Creates MultiKueueCluster objects (one per worker)
+// - Creates kubeconfig Secrets in the WorkerSet's Kueue namespace (one per worker)
+// - Creates MultiKueueCluster objects (one per worker, see WorkerSet.MultiKueueClusterName)
 // - Creates MultiKueueConfig object (named after WorkerSet, references all workers)
 // - Creates AdmissionCheck object (named after WorkerSet, references MultiKueueConfig)
 //
 // Parameters:
 // - ctx: Context for Kubernetes API calls
-// - client: Kueue client connected to management cluster
+// - client: Kueue client connected to management cluster, or any ObjectCreator
 // - workerSets: WorkerSet definitions from topology spec
 // - workerKubeconfigs: Map of worker name -> internal kubeconfig bytes
-func SetupMultiKueueInfrastructure(ctx context.Context, client *Client, workerSets []config.WorkerSet, workerKubeconfigs map[string][]byte) error {
+func SetupMultiKueueInfrastructure(ctx context.Context, client ObjectCreator, workerSets []config.WorkerSet, workerKubeconfigs map[string][]byte) error {
 	for _, ws := range workerSets {
-		// Collect worker cluster names for this WorkerSet
+		namespace := ws.Namespace()
+		if err := client.CreateNamespace(ctx, namespace, nil); err != nil {
+			return err
+		}
+
+		// Collect MultiKueueCluster names for this WorkerSet, in dispatch preference order
 		var clusterNames []string
 
 		// Create Secrets and MultiKueueCluster objects for each worker
-		for _, worker := range ws.Workers {
-			kubeconfigData, ok := workerKubeconfigs[worker.Name]
+		for _, worker := range ws.RankedWorkers() {
+			kubeconfigData, ok := workerKubeconfigs[worker]
 			if !ok {
-				return fmt.Errorf("kubeconfig not found for worker %q", worker.Name)
+				return fmt.Errorf("kubeconfig not found for worker %q", worker)
 			}
 
 			// Create Secret with kubeconfig
-			secretName := fmt.Sprintf("%s-kubeconfig", worker.Name)
-			if err := client.CreateKubeconfigSecret(ctx, MultiKueueNamespace, secretName, kubeconfigData); err != nil {
-				return fmt.Errorf("failed to create kubeconfig secret for worker %q: %w", worker.Name, err)
+			secretName, err := ws.KubeconfigSecretName(worker)
+			if err != nil {
+				return err
+			}
+			if err := client.CreateKubeconfigSecret(ctx, namespace, secretName, kubeconfigData); err != nil {
+				return fmt.Errorf("failed to create kubeconfig secret for worker %q: %w", worker, err)
 			}
 
 			// Create MultiKueueCluster object
-			mkc := BuildMultiKueueCluster(worker.Name, secretName)
+			mkc := BuildMultiKueueCluster(ws.MultiKueueClusterName(worker), secretName)
 			if err := client.CreateMultiKueueCluster(ctx, mkc); err != nil {
-				return fmt.Errorf("failed to create MultiKueueCluster for worker %q: %w", worker.Name, err)
+				return fmt.Errorf("failed to create MultiKueueCluster for worker %q: %w", worker, err)
 			}
 
-			clusterNames = append(clusterNames, worker.Name)
+			clusterNames = append(clusterNames, mkc.Name)
 		}
 
 		// Create MultiKueueConfig object (named after WorkerSet)
@@ -58,7 +70,7 @@ func SetupMultiKueueInfrastructure(ctx context.Context, client *Client, workerSe
 		}
 
 		// Create AdmissionCheck object (named after WorkerSet)
-		ac := BuildAdmissionCheck(ws.Name, ws.Name)
+		ac := BuildMultiKueueAdmissionCheck(ws.Name, ws.Name)
 		if err := client.CreateAdmissionCheck(ctx, ac); err != nil {
 			return fmt.Errorf("failed to create AdmissionCheck for workerSet %q: %w", ws.Name, err)
 		}
@@ -66,3 +78,115 @@ func SetupMultiKueueInfrastructure(ctx context.Context, client *Client, workerSe
 
 	return nil
 }
+
+// TeardownMultiKueueInfrastructure deletes the objects created by
+// SetupMultiKueueInfrastructure for the given WorkerSets. Objects that are already gone
+// are skipped.
+func TeardownMultiKueueInfrastructure(ctx context.Context, client *Client, workerSets []config.WorkerSet) error {
+	for _, ws := range workerSets {
+		if err := client.DeleteAdmissionCheck(ctx, ws.Name); err != nil {
+			return err
+		}
+		if err := client.DeleteMultiKueueConfig(ctx, ws.Name); err != nil {
+			return err
+		}
+		for _, worker := range ws.Workers {
+			if err := client.DeleteMultiKueueCluster(ctx, ws.MultiKueueClusterName(worker.Name)); err != nil {
+				return err
+			}
+			secretName, err := ws.KubeconfigSecretName(worker.Name)
+			if err != nil {
+				return err
+			}
+			if err := client.DeleteKubeconfigSecret(ctx, ws.Namespace(), secretName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WorkerConnection is the connection state of a MultiKueueCluster
+type WorkerConnection struct {
+	// Cluster is the MultiKueueCluster name
+	Cluster   string
+	Connected bool
+	// Reason explains why the cluster is not connected, from its Active condition
+	Reason string
+}
+
+// MultiKueueConnections returns the connection state of every MultiKueueCluster, sorted by name
+func (c *Client) MultiKueueConnections(ctx context.Context) ([]WorkerConnection, error) {
+	mkcs, err := c.ListMultiKueueClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	connections := make([]WorkerConnection, 0, len(mkcs))
+	for _, mkc := range mkcs {
+		reason, inactive := inactiveReason(mkc.Status.Conditions)
+		connections = append(connections, WorkerConnection{Cluster: mkc.Name, Connected: !inactive, Reason: reason})
+	}
+	sort.Slice(connections, func(i, j int) bool { return connections[i].Cluster < connections[j].Cluster })
+	return connections, nil
+}
+
+// workerCheckTimeout bounds CheckWorkerKubeconfig's retries
+const workerCheckTimeout = 30 * time.Second
+
+// CheckWorkerKubeconfig checks the credentials in a MultiKueue worker kubeconfig work, by
+// listing Workloads on the worker as the MultiKueue controller does. The kubeconfig's API
+// server address may only resolve inside the cluster network, so the worker is reached at
+// the server of reachableKubeconfigPath, e.g. its host kubeconfig. An unchanged Active
+// condition cannot tell whether the management cluster reconnected with new credentials;
+// this can.
+func CheckWorkerKubeconfig(ctx context.Context, kubeconfigData []byte, reachableKubeconfigPath string) error {
+	kubeconfig, err := clientcmd.Load(kubeconfigData)
+	if err != nil {
+		return fmt.Errorf("failed to parse kubeconfig: %w", err)
+	}
+	reachable, err := clientcmd.LoadFromFile(reachableKubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	server, err := currentCluster(reachable)
+	if err != nil {
+		return err
+	}
+	cluster, err := currentCluster(kubeconfig)
+	if err != nil {
+		return err
+	}
+	cluster.Server = server.Server
+
+	restConfig, err := restconfig.ForConfig(kubeconfig, 0)
+	if err != nil {
+		return err
+	}
+	kueueClient, err := kueueclientset.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create Kueue clientset: %w", err)
+	}
+
+	var lastErr error
+	err = wait.PollUntilContextTimeout(ctx, time.Second, workerCheckTimeout, true, func(ctx context.Context) (bool, error) {
+		_, lastErr = kueueClient.KueueV1beta2().Workloads(metav1.NamespaceAll).List(ctx, metav1.ListOptions{Limit: 1})
+		return lastErr == nil, nil
+	})
+	if err != nil && lastErr != nil {
+		return fmt.Errorf("worker rejected the kubeconfig's credentials: %w", lastErr)
+	}
+	return err
+}
+
+// currentCluster returns the cluster of a kubeconfig's current context
+func currentCluster(kubeconfig *clientcmdapi.Config) (*clientcmdapi.Cluster, error) {
+	currentContext, ok := kubeconfig.Contexts[kubeconfig.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig has no current context")
+	}
+	cluster, ok := kubeconfig.Clusters[currentContext.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig has no cluster %q", currentContext.Cluster)
+	}
+	return cluster, nil
+}
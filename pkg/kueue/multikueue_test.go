@@ -0,0 +1,215 @@
+package kueue
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+	kueuefake "sigs.k8s.io/kueue/client-go/clientset/versioned/fake"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+)
+
+func TestSetupMultiKueueInfrastructure(t *testing.T) {
+	ctx := context.TODO()
+	client := &Client{kueueClient: kueuefake.NewSimpleClientset(), clientset: fake.NewClientset()}
+	workerSets := []config.WorkerSet{{
+		Name:               "ws",
+		KueueNamespace:     "kueue-custom",
+		SecretNameTemplate: "mk-{{.Worker}}",
+		Dispatch:           &config.WorkerSetDispatch{Dispatcher: config.DispatcherIncremental, Order: []string{"worker-2"}},
+		Workers:            []config.Worker{{Name: "worker-1"}, {Name: "worker-2"}},
+	}}
+	kubeconfigs := map[string][]byte{"worker-1": []byte("kubeconfig-1"), "worker-2": []byte("kubeconfig-2")}
+
+	if err := SetupMultiKueueInfrastructure(ctx, client, workerSets, kubeconfigs); err != nil {
+		t.Fatalf("SetupMultiKueueInfrastructure() error = %v", err)
+	}
+
+	secret, err := client.clientset.CoreV1().Secrets("kueue-custom").Get(ctx, "mk-worker-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("kubeconfig Secret not created: %v", err)
+	}
+	if string(secret.Data["kubeconfig"]) != "kubeconfig-1" {
+		t.Errorf("Secret data = %q, want worker-1's kubeconfig", secret.Data["kubeconfig"])
+	}
+	mkc, err := client.GetMultiKueueCluster(ctx, "02-worker-1")
+	if err != nil {
+		t.Fatalf("MultiKueueCluster not created: %v", err)
+	}
+	if mkc.Spec.ClusterSource.KubeConfig == nil || mkc.Spec.ClusterSource.KubeConfig.Location != "mk-worker-1" {
+		t.Errorf("MultiKueueCluster kubeconfig = %+v, want Secret mk-worker-1", mkc.Spec.ClusterSource.KubeConfig)
+	}
+	mkConfig, err := client.kueueClient.KueueV1beta2().MultiKueueConfigs().Get(ctx, "ws", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("MultiKueueConfig not created: %v", err)
+	}
+	if got := mkConfig.Spec.Clusters; len(got) != 2 || got[0] != "01-worker-2" || got[1] != "02-worker-1" {
+		t.Errorf("MultiKueueConfig clusters = %v, want [01-worker-2 02-worker-1]", got)
+	}
+
+	if err := TeardownMultiKueueInfrastructure(ctx, client, workerSets); err != nil {
+		t.Fatalf("TeardownMultiKueueInfrastructure() error = %v", err)
+	}
+	if secrets, _ := client.clientset.CoreV1().Secrets("kueue-custom").List(ctx, metav1.ListOptions{}); len(secrets.Items) != 0 {
+		t.Errorf("expected kubeconfig Secrets to be deleted, got %d", len(secrets.Items))
+	}
+	if clusters, _ := client.ListMultiKueueClusters(ctx); len(clusters) != 0 {
+		t.Errorf("expected MultiKueueClusters to be deleted, got %d", len(clusters))
+	}
+}
+
+func TestSetupMultiKueueInfrastructure_Objects(t *testing.T) {
+	creator := newRecordingCreator()
+	workerSets := []config.WorkerSet{
+		{Name: "east", Workers: []config.Worker{{Name: "worker-1"}, {Name: "worker-2"}}},
+		{Name: "west", KueueNamespace: "kueue-west", Workers: []config.Worker{{Name: "worker-3"}}},
+	}
+	kubeconfigs := map[string][]byte{
+		"worker-1": []byte("kubeconfig-1"),
+		"worker-2": []byte("kubeconfig-2"),
+		"worker-3": []byte("kubeconfig-3"),
+	}
+
+	if err := SetupMultiKueueInfrastructure(context.TODO(), creator, workerSets, kubeconfigs); err != nil {
+		t.Fatalf("SetupMultiKueueInfrastructure() error = %v", err)
+	}
+
+	var wantKeys []string
+	for _, ws := range workerSets {
+		wantKeys = append(wantKeys, "Namespace/"+ws.Namespace(), "MultiKueueConfig/"+ws.Name, "AdmissionCheck/"+ws.Name)
+		for _, worker := range ws.Workers {
+			secretName, err := ws.KubeconfigSecretName(worker.Name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantKeys = append(wantKeys,
+				"Secret/"+ws.Namespace()+"/"+secretName,
+				"MultiKueueCluster/"+ws.MultiKueueClusterName(worker.Name))
+		}
+	}
+	slices.Sort(wantKeys)
+	wantKeys = slices.Compact(wantKeys)
+	if got := creator.keys(); !reflect.DeepEqual(got, wantKeys) {
+		t.Errorf("created objects = %v, want %v", got, wantKeys)
+	}
+
+	ac := creator.objects["AdmissionCheck/west"].(*kueue.AdmissionCheck)
+	if ac.Spec.ControllerName != kueue.MultiKueueControllerName || ac.Spec.Parameters == nil || ac.Spec.Parameters.Name != "west" {
+		t.Errorf("AdmissionCheck west = %+v, want MultiKueue check referencing MultiKueueConfig west", ac.Spec)
+	}
+	mkConfig := creator.objects["MultiKueueConfig/east"].(*kueue.MultiKueueConfig)
+	if len(mkConfig.Spec.Clusters) != 2 {
+		t.Errorf("MultiKueueConfig east clusters = %v, want both east workers", mkConfig.Spec.Clusters)
+	}
+}
+
+func TestSetupMultiKueueInfrastructure_MissingKubeconfig(t *testing.T) {
+	creator := newRecordingCreator()
+	workerSets := []config.WorkerSet{{Name: "ws", Workers: []config.Worker{{Name: "worker-1"}}}}
+
+	if err := SetupMultiKueueInfrastructure(context.TODO(), creator, workerSets, nil); err == nil {
+		t.Fatal("expected an error for a worker without a kubeconfig")
+	}
+	for _, key := range creator.keys() {
+		if !strings.HasPrefix(key, "Namespace/") {
+			t.Errorf("created %s before failing, want only the namespace", key)
+		}
+	}
+}
+
+func TestMultiKueueConnections(t *testing.T) {
+	ctx := context.TODO()
+	client := &Client{kueueClient: kueuefake.NewSimpleClientset(), clientset: fake.NewClientset()}
+	for _, mkc := range []struct {
+		name   string
+		status metav1.ConditionStatus
+		reason string
+	}{
+		{name: "worker-2", status: metav1.ConditionFalse, reason: "ClientConnectionFailed"},
+		{name: "worker-1", status: metav1.ConditionTrue, reason: "Active"},
+	} {
+		obj := BuildMultiKueueCluster(mkc.name, mkc.name+"-kubeconfig")
+		obj.Status.Conditions = []metav1.Condition{{Type: "Active", Status: mkc.status, Reason: mkc.reason}}
+		if err := client.CreateMultiKueueCluster(ctx, obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := client.MultiKueueConnections(ctx)
+	if err != nil {
+		t.Fatalf("MultiKueueConnections() error = %v", err)
+	}
+	want := []WorkerConnection{
+		{Cluster: "worker-1", Connected: true},
+		{Cluster: "worker-2", Reason: "Active=False (ClientConnectionFailed)"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MultiKueueConnections() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCheckWorkerKubeconfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer new-token" {
+			http.Error(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Unauthorized","code":401}`, http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"kind":"WorkloadList","apiVersion":"kueue.x-k8s.io/v1beta2","items":[]}`))
+	}))
+	defer server.Close()
+
+	// Both kubeconfigs trust the worker's CA, as kind's internal and host kubeconfigs do
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	kubeconfig := func(server, token string) *clientcmdapi.Config {
+		cfg := clientcmdapi.NewConfig()
+		cfg.Clusters["worker"] = &clientcmdapi.Cluster{Server: server, CertificateAuthorityData: ca}
+		cfg.AuthInfos["worker"] = &clientcmdapi.AuthInfo{Token: token}
+		cfg.Contexts["worker"] = &clientcmdapi.Context{Cluster: "worker", AuthInfo: "worker"}
+		cfg.CurrentContext = "worker"
+		return cfg
+	}
+	// The worker is only reachable at the host kubeconfig's server
+	reachable := filepath.Join(t.TempDir(), "worker.kubeconfig")
+	if err := clientcmd.WriteToFile(*kubeconfig(server.URL, "admin"), reachable); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "accepted credentials", token: "new-token"},
+		{name: "revoked credentials", token: "old-token", wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := clientcmd.Write(*kubeconfig("https://worker-control-plane:6443", tc.token))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			err = CheckWorkerKubeconfig(ctx, data, reachable)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("CheckWorkerKubeconfig() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr && !strings.Contains(err.Error(), "rejected") {
+				t.Errorf("CheckWorkerKubeconfig() error = %v, want the credentials rejected", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package kueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/utils/ptr"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/wait"
+)
+
+// DefaultProbeTimeout is how long ProbeCapacity waits for each probe Job to be admitted
+const DefaultProbeTimeout = 30 * time.Second
+
+const (
+	// probeLabel marks capacity probe Jobs with the ClusterQueue they probe
+	probeLabel = "kueue-bench.io/capacity-probe"
+	// probeNamePrefix prefixes capacity probe Job names
+	probeNamePrefix = "kueue-bench-probe-"
+
+	// queueNameLabel routes a Job to a LocalQueue
+	queueNameLabel = "kueue.x-k8s.io/queue-name"
+	// jobUIDLabel is set by Kueue on the Workload it creates for a Job
+	jobUIDLabel = "kueue.x-k8s.io/job-uid"
+)
+
+// ProbeCapacity submits one Job per ClusterQueue, sized at the nominal quota of the first
+// flavor of each resource group, and checks Kueue admits it. This catches quota no workload
+// can use, e.g. a flavor whose resources do not match the ClusterQueue's. Probes run one at
+// a time through a LocalQueue of the ClusterQueue and are deleted once checked. ClusterQueues
+// with admission checks, without nominal quota or without an active LocalQueue are skipped.
+func ProbeCapacity(ctx context.Context, client *Client, kueueConfig *config.KueueConfig, timeout time.Duration) error {
+	if kueueConfig == nil {
+		return nil
+	}
+
+	var failed []string
+	probed := 0
+	for _, cq := range kueueConfig.ClusterQueues {
+		lq, ok := probeLocalQueue(kueueConfig.LocalQueues, cq.Name)
+		requests := probeRequests(cq)
+		if len(cq.AdmissionChecks) > 0 || !ok || len(requests) == 0 {
+			continue
+		}
+
+		job := buildProbeJob(cq.Name, lq, requests)
+		reason, err := client.runProbe(ctx, job, timeout)
+		if err != nil {
+			return fmt.Errorf("failed to probe ClusterQueue %s: %w", cq.Name, err)
+		}
+		if reason != "" {
+			failed = append(failed, fmt.Sprintf("ClusterQueue %s: %s", cq.Name, reason))
+		}
+		probed++
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d ClusterQueue(s) could not admit a job sized at their nominal quota:\n  %s",
+			len(failed), strings.Join(failed, "\n  "))
+	}
+	if probed > 0 {
+		fmt.Printf("✓ Capacity probe admitted in %d ClusterQueue(s)\n", probed)
+	}
+	return nil
+}
+
+// probeLocalQueue returns a LocalQueue of the ClusterQueue that admits workloads
+func probeLocalQueue(localQueues []config.LocalQueue, clusterQueue string) (config.LocalQueue, bool) {
+	for _, lq := range localQueues {
+		if lq.ClusterQueue != clusterQueue {
+			continue
+		}
+		if lq.StopPolicy == "" || lq.StopPolicy == config.StopPolicyNone {
+			return lq, true
+		}
+	}
+	return config.LocalQueue{}, false
+}
+
+// probeRequests returns the non-zero nominal quotas of the first flavor in each resource group
+func probeRequests(cq config.ClusterQueue) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, rg := range cq.ResourceGroups {
+		if len(rg.Flavors) == 0 {
+			continue
+		}
+		for _, res := range rg.Flavors[0].Resources {
+			quota, err := resource.ParseQuantity(res.NominalQuota)
+			if err != nil || quota.Sign() <= 0 {
+				continue
+			}
+			requests[corev1.ResourceName(res.Name)] = quota
+		}
+	}
+	return requests
+}
+
+// buildProbeJob builds a suspended single-pod Job for a LocalQueue requesting requests
+func buildProbeJob(clusterQueue string, lq config.LocalQueue, requests corev1.ResourceList) *batchv1.Job {
+	name := probeNamePrefix + clusterQueue
+	if len(name) > 63 {
+		name = strings.TrimRight(name[:63], "-.")
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: localQueueNamespace(lq),
+			Labels: map[string]string{
+				queueNameLabel: lq.Name,
+				probeLabel:     clusterQueue,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			Suspend:      ptr.To(true),
+			BackoffLimit: ptr.To[int32](0),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Tolerations: []corev1.Toleration{{
+						Key: "kwok.x-k8s.io/node", Operator: corev1.TolerationOpEqual, Value: "fake", Effect: corev1.TaintEffectNoSchedule,
+					}},
+					Containers: []corev1.Container{{
+						Name:      "probe",
+						Image:     "gcr.io/kwok/kwok",
+						Resources: corev1.ResourceRequirements{Requests: requests, Limits: requests},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// runProbe creates the probe Job, waits for its Workload to be admitted and deletes the Job.
+// It returns why the Workload was not admitted, or "" if it was.
+func (c *Client) runProbe(ctx context.Context, job *batchv1.Job, timeout time.Duration) (string, error) {
+	jobs := c.clientset.BatchV1().Jobs(job.Namespace)
+	if err := c.deleteProbeJob(ctx, job); err != nil {
+		return "", err
+	}
+	c.own(job)
+	created, err := jobs.Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create probe Job %s/%s: %w", job.Namespace, job.Name, err)
+	}
+	defer func() { _ = c.deleteProbeJob(context.WithoutCancel(ctx), job) }()
+
+	workloads := c.kueueClient.KueueV1beta2().Workloads(job.Namespace)
+	selector := labels.SelectorFromSet(labels.Set{jobUIDLabel: string(created.UID)})
+	lw := wait.ByLabel(selector, c.kueueClient,
+		func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return workloads.List(ctx, opts)
+		},
+		func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+			return workloads.Watch(ctx, opts)
+		},
+	)
+	err = wait.For(ctx, "probe Workload", lw, &kueue.Workload{}, timeout, probeAdmitted)
+	var timeoutErr *wait.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return timeoutErr.Cause, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to check probe Workload: %w", err)
+	}
+	return "", nil
+}
+
+// probeAdmitted is the wait.Check for a probe Job's Workload
+func probeAdmitted(obj runtime.Object) (bool, string) {
+	wl, ok := obj.(*kueue.Workload)
+	if !ok {
+		return false, "no Workload created for the probe Job"
+	}
+	if apimeta.IsStatusConditionTrue(wl.Status.Conditions, kueue.WorkloadAdmitted) {
+		return true, ""
+	}
+	if cond := apimeta.FindStatusCondition(wl.Status.Conditions, kueue.WorkloadQuotaReserved); cond != nil && cond.Message != "" {
+		return false, fmt.Sprintf("not admitted (%s)", cond.Message)
+	}
+	return false, "not admitted"
+}
+
+// deleteProbeJob deletes a probe Job and its pods. A missing Job is not an error.
+func (c *Client) deleteProbeJob(ctx context.Context, job *batchv1.Job) error {
+	err := c.clientset.BatchV1().Jobs(job.Namespace).Delete(ctx, job.Name, metav1.DeleteOptions{
+		PropagationPolicy: ptr.To(metav1.DeletePropagationBackground),
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete probe Job %s/%s: %w", job.Namespace, job.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package kueue
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+	kueuefake "sigs.k8s.io/kueue/client-go/clientset/versioned/fake"
+)
+
+func TestProbeCapacity(t *testing.T) {
+	cq := func(name, quota string, admissionChecks ...string) config.ClusterQueue {
+		return config.ClusterQueue{
+			Name:            name,
+			AdmissionChecks: admissionChecks,
+			ResourceGroups: []config.ResourceGroup{{
+				CoveredResources: []string{"cpu"},
+				Flavors:          []config.FlavorQuotas{{Name: "default", Resources: []config.Resource{{Name: "cpu", NominalQuota: quota}}}},
+			}},
+		}
+	}
+	cfg := &config.KueueConfig{
+		ClusterQueues: []config.ClusterQueue{
+			cq("ok", "10"),
+			cq("mismatch", "10"),
+			cq("multikueue", "10", "workers"),
+			cq("no-quota", "0"),
+			cq("no-local-queue", "10"),
+		},
+		LocalQueues: []config.LocalQueue{
+			{Name: "lq-ok", Namespace: "team-a", ClusterQueue: "ok"},
+			{Name: "lq-mismatch", Namespace: "team-a", ClusterQueue: "mismatch"},
+			{Name: "lq-mk", Namespace: "team-a", ClusterQueue: "multikueue"},
+			{Name: "lq-none", Namespace: "team-a", ClusterQueue: "no-quota"},
+		},
+	}
+
+	// Stand in for Kueue: create a Workload for each probe Job, admitted unless it targets "mismatch"
+	kueueClient := kueuefake.NewSimpleClientset()
+	clientset := fake.NewClientset()
+	var probed []string
+	clientset.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		job := action.(k8stesting.CreateAction).GetObject().(*batchv1.Job)
+		job.UID = types.UID("uid-" + job.Name)
+		cqName := job.Labels[probeLabel]
+		probed = append(probed, cqName)
+
+		cond := metav1.Condition{Type: kueue.WorkloadAdmitted, Status: metav1.ConditionTrue, Reason: "Admitted"}
+		if cqName == "mismatch" {
+			cond = metav1.Condition{Type: kueue.WorkloadQuotaReserved, Status: metav1.ConditionFalse, Reason: "Pending",
+				Message: "couldn't assign flavors to pod set main: insufficient quota"}
+		}
+		wl := &kueue.Workload{
+			ObjectMeta: metav1.ObjectMeta{Name: "job-" + job.Name, Namespace: job.Namespace, Labels: map[string]string{jobUIDLabel: string(job.UID)}},
+			Status:     kueue.WorkloadStatus{Conditions: []metav1.Condition{cond}},
+		}
+		_, err := kueueClient.KueueV1beta2().Workloads(job.Namespace).Create(context.TODO(), wl, metav1.CreateOptions{})
+		return false, nil, err
+	})
+	client := &Client{kueueClient: kueueClient, clientset: clientset}
+
+	err := ProbeCapacity(context.TODO(), client, cfg, 2*time.Second)
+	if err == nil || !strings.Contains(err.Error(), "ClusterQueue mismatch: not admitted (couldn't assign flavors") {
+		t.Fatalf("ProbeCapacity() error = %v, expected mismatch to fail", err)
+	}
+	if strings.Contains(err.Error(), "ClusterQueue ok") {
+		t.Errorf("ProbeCapacity() reported admitted queue: %v", err)
+	}
+	if strings.Join(probed, ",") != "ok,mismatch" {
+		t.Errorf("probed %v, want [ok mismatch]", probed)
+	}
+
+	jobs, err := clientset.BatchV1().Jobs("team-a").List(context.TODO(), metav1.ListOptions{})
+	if err != nil || len(jobs.Items) != 0 {
+		t.Errorf("expected probe Jobs to be deleted, got %d (err %v)", len(jobs.Items), err)
+	}
+}
@@ -1,73 +1,445 @@
 package kueue
 
 import (
+	"cmp"
 	"context"
+	"fmt"
+	"slices"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
 
 	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/ownership"
 )
 
-// TODO: I'm not actually sure all objects need to be created in dependency order.
-// We already validate configs are valid, so we may be able to simplify ProvisionKueueObjects
-// e.g. creating a child cohort before parent cohort is created is perfectly fine
+// DefaultProvisionConcurrency is how many objects of one kind ProvisionKueueObjects
+// creates in parallel by default
+const DefaultProvisionConcurrency = 10
+
+// progressBatchSize is the batch size above which per-kind progress is printed
+const progressBatchSize = 100
 
-// ProvisionKueueObjects creates all Kueue objects from the configuration
-// Objects are created in dependency order:
+// ProvisionKueueObjects creates all Kueue objects from the configuration.
+// Objects are created in dependency order, one kind at a time, with up to concurrency
+// objects of the kind in flight (DefaultProvisionConcurrency if concurrency <= 0):
 // 1. Cohorts (Kueue handles parent references automatically)
-// 2. ResourceFlavors (referenced by ClusterQueues)
-// 3. ClusterQueues (referenced by LocalQueues)
-// 4. WorkloadPriorityClasses (independent)
-// 5. Namespaces (for LocalQueues)
-// 6. LocalQueues (last, depends on ClusterQueues and namespaces)
-func ProvisionKueueObjects(ctx context.Context, client *Client, kueueConfig *config.KueueConfig) error {
+// 2. Topologies (referenced by ResourceFlavors)
+// 3. ResourceFlavors (referenced by ClusterQueues)
+// 4. ProvisioningRequestConfigs (referenced by AdmissionChecks)
+// 5. AdmissionChecks (referenced by ClusterQueues)
+// 6. ClusterQueues (referenced by LocalQueues)
+// 7. WorkloadPriorityClasses and Kubernetes PriorityClasses (independent)
+// 8. Namespaces (declared, and for LocalQueues)
+// 9. ResourceQuotas and LimitRanges of declared namespaces
+// 10. LocalQueues (last, depends on ClusterQueues and namespaces)
+func ProvisionKueueObjects(ctx context.Context, client ObjectCreator, kueueConfig *config.KueueConfig, concurrency int) error {
 	if kueueConfig == nil {
 		return nil
 	}
+	if concurrency <= 0 {
+		concurrency = DefaultProvisionConcurrency
+	}
 
 	// Step 1: Create Cohorts
-	for _, cohort := range kueueConfig.Cohorts {
-		if err := client.CreateCohort(ctx, BuildCohort(cohort)); err != nil {
+	if err := createAll(ctx, "Cohorts", len(kueueConfig.Cohorts), concurrency, func(ctx context.Context, i int) error {
+		cohort, err := BuildCohort(kueueConfig.Cohorts[i])
+		if err != nil {
 			return err
 		}
+		return client.CreateCohort(ctx, cohort)
+	}); err != nil {
+		return err
 	}
 
-	// Step 2: Create ResourceFlavors
-	for _, rf := range kueueConfig.ResourceFlavors {
-		if err := client.CreateResourceFlavor(ctx, BuildResourceFlavor(rf)); err != nil {
-			return err
-		}
+	// Step 2: Create Topologies
+	if err := createAll(ctx, "Topologies", len(kueueConfig.Topologies), concurrency, func(ctx context.Context, i int) error {
+		return client.CreateTopology(ctx, BuildTopology(kueueConfig.Topologies[i]))
+	}); err != nil {
+		return err
+	}
+
+	// Step 3: Create ResourceFlavors
+	if err := createAll(ctx, "ResourceFlavors", len(kueueConfig.ResourceFlavors), concurrency, func(ctx context.Context, i int) error {
+		return client.CreateResourceFlavor(ctx, BuildResourceFlavor(kueueConfig.ResourceFlavors[i]))
+	}); err != nil {
+		return err
+	}
+
+	// Step 4: Create ProvisioningRequestConfigs
+	if err := createAll(ctx, "ProvisioningRequestConfigs", len(kueueConfig.ProvisioningRequestConfigs), concurrency, func(ctx context.Context, i int) error {
+		return client.CreateProvisioningRequestConfig(ctx, BuildProvisioningRequestConfig(kueueConfig.ProvisioningRequestConfigs[i]))
+	}); err != nil {
+		return err
+	}
+
+	// Step 5: Create AdmissionChecks
+	if err := createAll(ctx, "AdmissionChecks", len(kueueConfig.AdmissionChecks), concurrency, func(ctx context.Context, i int) error {
+		return client.CreateAdmissionCheck(ctx, BuildAdmissionCheck(kueueConfig.AdmissionChecks[i]))
+	}); err != nil {
+		return err
 	}
 
-	// Step 3: Create ClusterQueues
-	for _, cq := range kueueConfig.ClusterQueues {
-		if err := client.CreateClusterQueue(ctx, BuildClusterQueue(cq)); err != nil {
+	// Step 6: Create ClusterQueues
+	if err := createAll(ctx, "ClusterQueues", len(kueueConfig.ClusterQueues), concurrency, func(ctx context.Context, i int) error {
+		cq, err := BuildClusterQueue(kueueConfig.ClusterQueues[i])
+		if err != nil {
 			return err
 		}
+		return client.CreateClusterQueue(ctx, cq)
+	}); err != nil {
+		return err
+	}
+
+	// Step 7: Create WorkloadPriorityClasses
+	if err := createAll(ctx, "WorkloadPriorityClasses", len(kueueConfig.PriorityClasses), concurrency, func(ctx context.Context, i int) error {
+		return client.CreateWorkloadPriorityClass(ctx, BuildWorkloadPriorityClass(kueueConfig.PriorityClasses[i]))
+	}); err != nil {
+		return err
+	}
+
+	podPriorityClasses := kueueConfig.AllPodPriorityClasses()
+	if err := createAll(ctx, "PriorityClasses", len(podPriorityClasses), concurrency, func(ctx context.Context, i int) error {
+		return client.CreatePriorityClass(ctx, BuildPriorityClass(podPriorityClasses[i]))
+	}); err != nil {
+		return err
 	}
 
-	// Step 4: Create WorkloadPriorityClasses
-	for _, wpc := range kueueConfig.PriorityClasses {
-		if err := client.CreateWorkloadPriorityClass(ctx, BuildWorkloadPriorityClass(wpc)); err != nil {
+	// Step 8: Create declared namespaces and namespaces for LocalQueues
+	namespaces := namespacesToCreate(kueueConfig)
+	if err := createAll(ctx, "namespaces", len(namespaces), concurrency, func(ctx context.Context, i int) error {
+		return client.CreateNamespace(ctx, namespaces[i].Name, namespaces[i].Labels)
+	}); err != nil {
+		return err
+	}
+
+	// Step 9: Create ResourceQuotas and LimitRanges of declared namespaces
+	if err := createAll(ctx, "namespace fixtures", len(namespaces), concurrency, func(ctx context.Context, i int) error {
+		quota, err := BuildResourceQuota(namespaces[i])
+		if err != nil {
+			return err
+		}
+		if quota != nil {
+			if err := client.CreateResourceQuota(ctx, quota); err != nil {
+				return err
+			}
+		}
+		limitRange, err := BuildLimitRange(namespaces[i])
+		if err != nil || limitRange == nil {
 			return err
 		}
+		return client.CreateLimitRange(ctx, limitRange)
+	}); err != nil {
+		return err
 	}
 
-	// Step 5: Create namespaces for LocalQueues
-	for _, ns := range getUniqueNamespaces(kueueConfig.LocalQueues) {
-		if err := client.CreateNamespace(ctx, ns); err != nil {
+	// Step 10: Create LocalQueues
+	return createAll(ctx, "LocalQueues", len(kueueConfig.LocalQueues), concurrency, func(ctx context.Context, i int) error {
+		lq, err := BuildLocalQueue(kueueConfig.LocalQueues[i])
+		if err != nil {
 			return err
 		}
+		return client.CreateLocalQueue(ctx, lq)
+	})
+}
+
+// createAll calls create for indexes 0..n-1 with at most concurrency calls in flight and
+// returns the first error. Progress is printed for large batches.
+func createAll(ctx context.Context, kind string, n, concurrency int, create func(ctx context.Context, i int) error) error {
+	if n == 0 {
+		return nil
+	}
+	report := n >= progressBatchSize
+	if report {
+		fmt.Printf("Creating %d %s...\n", n, kind)
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	var done atomic.Int64
+	for i := 0; i < n; i++ {
+		g.Go(func() error {
+			if err := create(ctx, i); err != nil {
+				return err
+			}
+			if d := done.Add(1); report && d%int64(n/10) == 0 && d < int64(n) {
+				fmt.Printf("  %d/%d %s created\n", d, n, kind)
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
 	}
 
-	// Step 6: Create LocalQueues
+	if report {
+		fmt.Printf("✓ Created %d %s\n", n, kind)
+	}
+	return nil
+}
+
+// DeprovisionKueueObjects deletes the Kueue objects of a configuration in reverse
+// dependency order:
+// 1. LocalQueues, and ResourceQuotas and LimitRanges of declared namespaces
+// 2. WorkloadPriorityClasses and Kubernetes PriorityClasses
+// 3. ClusterQueues
+// 4. AdmissionChecks
+// 5. ProvisioningRequestConfigs
+// 6. ResourceFlavors
+// 7. Topologies
+// 8. Cohorts, children before their parents
+// Objects are found by the ownership labels of the client's topology (see
+// Client.SetTopology), so objects dropped from the configuration since they were created
+// are deleted too. Configured objects created before kueue-bench labeled its objects are
+// deleted by name; objects another topology or tool owns are kept.
+// Namespaces created for LocalQueues are kept, since they may hold user objects.
+// Objects that are already gone are skipped. Kueue finalizers keep in-use ClusterQueues
+// and ResourceFlavors until their workloads finish, so deletion may complete later.
+func DeprovisionKueueObjects(ctx context.Context, client *Client, kueueConfig *config.KueueConfig) error {
+	if kueueConfig == nil {
+		return nil
+	}
+
+	var localQueues, quotas, limitRanges []objectKey
 	for _, lq := range kueueConfig.LocalQueues {
-		if err := client.CreateLocalQueue(ctx, BuildLocalQueue(lq)); err != nil {
+		localQueues = append(localQueues, objectKey{localQueueNamespace(lq), lq.Name})
+	}
+	for _, ns := range kueueConfig.Namespaces {
+		if len(ns.ResourceQuota) > 0 {
+			quotas = append(quotas, objectKey{ns.Name, config.FixtureName})
+		}
+		if ns.LimitRange != nil {
+			limitRanges = append(limitRanges, objectKey{ns.Name, config.FixtureName})
+		}
+	}
+
+	steps := []struct {
+		configured []objectKey
+		list       func(context.Context) ([]metav1.Object, error)
+		delete     func(ctx context.Context, namespace, name string) error
+		// order reorders the objects to delete, if set
+		order func(objects []metav1.Object, keys []objectKey)
+	}{
+		{
+			localQueues,
+			func(ctx context.Context) ([]metav1.Object, error) {
+				return listObjects(client.ListLocalQueues(ctx, metav1.NamespaceAll))
+			},
+			client.DeleteLocalQueue,
+			nil,
+		},
+		{
+			quotas,
+			func(ctx context.Context) ([]metav1.Object, error) {
+				return listObjects(client.ListResourceQuotas(ctx, metav1.NamespaceAll))
+			},
+			client.DeleteResourceQuota,
+			nil,
+		},
+		{
+			limitRanges,
+			func(ctx context.Context) ([]metav1.Object, error) {
+				return listObjects(client.ListLimitRanges(ctx, metav1.NamespaceAll))
+			},
+			client.DeleteLimitRange,
+			nil,
+		},
+		{
+			clusterKeys(kueueConfig.PriorityClasses, func(wpc config.WorkloadPriorityClass) string { return wpc.Name }),
+			func(ctx context.Context) ([]metav1.Object, error) {
+				return listObjects(client.ListWorkloadPriorityClasses(ctx))
+			},
+			clusterScoped(client.DeleteWorkloadPriorityClass),
+			nil,
+		},
+		{
+			clusterKeys(kueueConfig.AllPodPriorityClasses(), func(pc config.PodPriorityClass) string { return pc.Name }),
+			func(ctx context.Context) ([]metav1.Object, error) {
+				return listObjects(client.ListPriorityClasses(ctx))
+			},
+			clusterScoped(client.DeletePriorityClass),
+			nil,
+		},
+		{
+			clusterKeys(kueueConfig.ClusterQueues, func(cq config.ClusterQueue) string { return cq.Name }),
+			func(ctx context.Context) ([]metav1.Object, error) {
+				return listObjects(client.ListClusterQueues(ctx))
+			},
+			clusterScoped(client.DeleteClusterQueue),
+			nil,
+		},
+		{
+			clusterKeys(kueueConfig.AdmissionChecks, func(ac config.AdmissionCheck) string { return ac.Name }),
+			func(ctx context.Context) ([]metav1.Object, error) {
+				return listObjects(client.ListAdmissionChecks(ctx))
+			},
+			clusterScoped(client.DeleteAdmissionCheck),
+			nil,
+		},
+		{
+			clusterKeys(kueueConfig.ProvisioningRequestConfigs, func(prc config.ProvisioningRequestConfig) string { return prc.Name }),
+			func(ctx context.Context) ([]metav1.Object, error) {
+				return listObjects(client.ListProvisioningRequestConfigs(ctx))
+			},
+			clusterScoped(client.DeleteProvisioningRequestConfig),
+			nil,
+		},
+		{
+			clusterKeys(kueueConfig.ResourceFlavors, func(rf config.ResourceFlavor) string { return rf.Name }),
+			func(ctx context.Context) ([]metav1.Object, error) {
+				return listObjects(client.ListResourceFlavors(ctx))
+			},
+			clusterScoped(client.DeleteResourceFlavor),
+			nil,
+		},
+		{
+			clusterKeys(kueueConfig.Topologies, func(topology config.KueueTopology) string { return topology.Name }),
+			func(ctx context.Context) ([]metav1.Object, error) {
+				return listObjects(client.ListTopologies(ctx))
+			},
+			clusterScoped(client.DeleteTopology),
+			nil,
+		},
+		{
+			clusterKeys(kueueConfig.Cohorts, func(cohort config.Cohort) string { return cohort.Name }),
+			func(ctx context.Context) ([]metav1.Object, error) {
+				return listObjects(client.ListCohorts(ctx))
+			},
+			clusterScoped(client.DeleteCohort),
+			childCohortsFirst,
+		},
+	}
+
+	for _, step := range steps {
+		objects, err := step.list(ctx)
+		if err != nil {
 			return err
 		}
+		keys := deletionOrder(objects, step.configured, client.topology)
+		if step.order != nil {
+			step.order(objects, keys)
+		}
+		for _, key := range keys {
+			if err := step.delete(ctx, key.namespace, key.name); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+// objectKey identifies an object by namespace (empty for cluster-scoped objects) and name
+type objectKey struct {
+	namespace, name string
+}
+
+// clusterKeys returns the keys of configured cluster-scoped objects
+func clusterKeys[T any](items []T, name func(T) string) []objectKey {
+	keys := make([]objectKey, len(items))
+	for i, item := range items {
+		keys[i] = objectKey{name: name(item)}
+	}
+	return keys
+}
+
+// clusterScoped adapts the delete function of a cluster-scoped kind to take a namespace
+func clusterScoped(deleteFn func(ctx context.Context, name string) error) func(context.Context, string, string) error {
+	return func(ctx context.Context, _, name string) error {
+		return deleteFn(ctx, name)
+	}
+}
+
+// listObjects returns the object metadata of listed items
+func listObjects[T any, PT interface {
+	*T
+	metav1.Object
+}](items []T, err error) ([]metav1.Object, error) {
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]metav1.Object, len(items))
+	for i := range items {
+		objects[i] = PT(&items[i])
+	}
+	return objects, nil
+}
+
+// deletionOrder returns the objects of one kind to delete: the configured objects that
+// exist, in configured order, unless kueue-bench did not create them for the topology,
+// followed by the other objects labeled as the topology's, sorted by namespace and name
+func deletionOrder(objects []metav1.Object, configured []objectKey, topology string) []objectKey {
+	existing := make(map[objectKey]map[string]string, len(objects))
+	for _, obj := range objects {
+		existing[objectKey{obj.GetNamespace(), obj.GetName()}] = obj.GetLabels()
+	}
+
+	var keys []objectKey
+	seen := make(map[objectKey]bool, len(configured))
+	for _, key := range configured {
+		labels, ok := existing[key]
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if ownership.Owned(labels, topology) || ownership.Legacy(labels) {
+			keys = append(keys, key)
+		}
+	}
+
+	var extra []objectKey
+	for key, labels := range existing {
+		if !seen[key] && ownership.Owned(labels, topology) {
+			extra = append(extra, key)
+		}
+	}
+	slices.SortFunc(extra, func(a, b objectKey) int {
+		return cmp.Or(cmp.Compare(a.namespace, b.namespace), cmp.Compare(a.name, b.name))
+	})
+	return append(keys, extra...)
+}
+
+// childCohortsFirst orders Cohorts to delete so every Cohort comes before its parent,
+// whatever order the config lists them in. Depth in the live Cohort hierarchy decides;
+// Cohorts at the same depth keep their order.
+func childCohortsFirst(objects []metav1.Object, keys []objectKey) {
+	parents := make(map[string]string, len(objects))
+	for _, obj := range objects {
+		if cohort, ok := obj.(*kueue.Cohort); ok {
+			parents[cohort.Name] = string(cohort.Spec.ParentName)
+		}
+	}
+	depth := func(name string) int {
+		d := 0
+		// Bounded by the number of Cohorts in case of a parent cycle
+		for parent := parents[name]; parent != "" && d < len(parents); parent = parents[parent] {
+			d++
+		}
+		return d
+	}
+	slices.SortStableFunc(keys, func(a, b objectKey) int {
+		return cmp.Compare(depth(b.name), depth(a.name))
+	})
+}
+
+// namespacesToCreate returns the declared namespaces followed by LocalQueue namespaces
+// that are not declared
+func namespacesToCreate(kueueConfig *config.KueueConfig) []config.Namespace {
+	namespaces := append([]config.Namespace(nil), kueueConfig.Namespaces...)
+	declared := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		declared[ns.Name] = true
+	}
+	for _, name := range getUniqueNamespaces(kueueConfig.LocalQueues) {
+		if !declared[name] {
+			namespaces = append(namespaces, config.Namespace{Name: name})
+		}
+	}
+	return namespaces
+}
+
 // getUniqueNamespaces extracts unique namespaces from LocalQueues, excluding "default"
 func getUniqueNamespaces(localQueues []config.LocalQueue) []string {
 	namespaceMap := make(map[string]bool)
@@ -2,24 +2,376 @@ package kueue
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/ownership"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+	kueuefake "sigs.k8s.io/kueue/client-go/clientset/versioned/fake"
 )
 
 func TestProvisionKueueObjects_NilConfig(t *testing.T) {
-	// Verify that nil config doesn't cause errors
-	err := ProvisionKueueObjects(context.TODO(), nil, nil)
-	if err != nil {
+	creator := newRecordingCreator()
+	if err := ProvisionKueueObjects(context.TODO(), creator, nil, 0); err != nil {
 		t.Errorf("expected no error with nil config, got: %v", err)
 	}
+	if keys := creator.keys(); len(keys) != 0 {
+		t.Errorf("expected no objects with nil config, got %v", keys)
+	}
 }
 
 func TestProvisionKueueObjects_EmptyConfig(t *testing.T) {
-	// Verify that empty config doesn't cause errors
-	emptyConfig := &config.KueueConfig{}
-	err := ProvisionKueueObjects(context.TODO(), nil, emptyConfig)
-	if err != nil {
+	creator := newRecordingCreator()
+	if err := ProvisionKueueObjects(context.TODO(), creator, &config.KueueConfig{}, 0); err != nil {
 		t.Errorf("expected no error with empty config, got: %v", err)
 	}
+	if keys := creator.keys(); len(keys) != 0 {
+		t.Errorf("expected no objects with empty config, got %v", keys)
+	}
+}
+
+// provisionTestConfig declares every kind of object ProvisionKueueObjects creates
+func provisionTestConfig() *config.KueueConfig {
+	return &config.KueueConfig{
+		Cohorts:         []config.Cohort{{Name: "root"}, {Name: "team", ParentName: "root"}},
+		Topologies:      []config.KueueTopology{{Name: "dc", Levels: []string{"kubernetes.io/hostname"}}},
+		ResourceFlavors: []config.ResourceFlavor{{Name: "default", TopologyName: "dc"}},
+		ProvisioningRequestConfigs: []config.ProvisioningRequestConfig{
+			{Name: "prc", ProvisioningClassName: "check-capacity.autoscaling.x-k8s.io"},
+		},
+		AdmissionChecks: []config.AdmissionCheck{{
+			Name:           "prov",
+			ControllerName: "kueue.x-k8s.io/provisioning-request",
+			Parameters:     &config.AdmissionCheckParameters{Kind: "ProvisioningRequestConfig", Name: "prc"},
+		}},
+		ClusterQueues:      []config.ClusterQueue{{Name: "cq", Cohort: "team", AdmissionChecks: []string{"prov"}}},
+		PriorityClasses:    []config.WorkloadPriorityClass{{Name: "high", Value: 1000, PodPriorityClass: true}},
+		PodPriorityClasses: []config.PodPriorityClass{{Name: "pod-high", Value: 1000}},
+		Namespaces: []config.Namespace{{
+			Name:          "team-a",
+			Labels:        map[string]string{"team": "a"},
+			ResourceQuota: map[string]string{"requests.cpu": "16"},
+			LimitRange:    &config.LimitRange{DefaultRequest: map[string]string{"cpu": "500m"}},
+		}},
+		LocalQueues: []config.LocalQueue{
+			{Name: "lq", Namespace: "team-a", ClusterQueue: "cq"},
+			{Name: "lq", Namespace: "team-b", ClusterQueue: "cq"},
+			{Name: "lq", ClusterQueue: "cq"},
+		},
+	}
+}
+
+func TestProvisionKueueObjects(t *testing.T) {
+	creator := newRecordingCreator()
+	if err := ProvisionKueueObjects(context.TODO(), creator, provisionTestConfig(), 0); err != nil {
+		t.Fatalf("ProvisionKueueObjects() error = %v", err)
+	}
+
+	wantKeys := []string{
+		"AdmissionCheck/prov",
+		"ClusterQueue/cq",
+		"Cohort/root",
+		"Cohort/team",
+		"LimitRange/team-a/kueue-bench",
+		"LocalQueue/default/lq",
+		"LocalQueue/team-a/lq",
+		"LocalQueue/team-b/lq",
+		"Namespace/team-a",
+		"Namespace/team-b",
+		"PriorityClass/high",
+		"PriorityClass/pod-high",
+		"ProvisioningRequestConfig/prc",
+		"ResourceFlavor/default",
+		"ResourceQuota/team-a/kueue-bench",
+		"Topology/dc",
+		"WorkloadPriorityClass/high",
+	}
+	if got := creator.keys(); !reflect.DeepEqual(got, wantKeys) {
+		t.Errorf("created objects = %v, want %v", got, wantKeys)
+	}
+
+	// Kinds are created in dependency order
+	wantKinds := []string{
+		"Cohort", "Topology", "ResourceFlavor", "ProvisioningRequestConfig", "AdmissionCheck",
+		"ClusterQueue", "WorkloadPriorityClass", "PriorityClass", "Namespace", "ResourceQuota", "LimitRange", "LocalQueue",
+	}
+	if !reflect.DeepEqual(creator.kinds, wantKinds) {
+		t.Errorf("creation order = %v, want %v", creator.kinds, wantKinds)
+	}
+
+	if labels := creator.objects["Namespace/team-a"].(map[string]string); labels["team"] != "a" {
+		t.Errorf("declared namespace labels = %v, want team=a", labels)
+	}
+	if labels := creator.objects["Namespace/team-b"].(map[string]string); len(labels) != 0 {
+		t.Errorf("LocalQueue namespace labels = %v, want none", labels)
+	}
+	cq := creator.objects["ClusterQueue/cq"].(*kueue.ClusterQueue)
+	if cq.Spec.CohortName != "team" {
+		t.Errorf("ClusterQueue cohort = %q, want team", cq.Spec.CohortName)
+	}
+	if lq := creator.objects["LocalQueue/team-b/lq"].(*kueue.LocalQueue); lq.Spec.ClusterQueue != "cq" {
+		t.Errorf("LocalQueue clusterQueue = %q, want cq", lq.Spec.ClusterQueue)
+	}
+	quota := creator.objects["ResourceQuota/team-a/kueue-bench"].(*corev1.ResourceQuota)
+	if cpu := quota.Spec.Hard[corev1.ResourceRequestsCPU]; cpu.String() != "16" {
+		t.Errorf("ResourceQuota requests.cpu = %s, want 16", cpu.String())
+	}
+}
+
+func TestProvisionKueueObjects_StopsAtFailedKind(t *testing.T) {
+	creator := newRecordingCreator()
+	creator.failKind = "ClusterQueue"
+	if err := ProvisionKueueObjects(context.TODO(), creator, provisionTestConfig(), 0); err == nil {
+		t.Fatal("expected ProvisionKueueObjects() to fail")
+	}
+
+	// Kinds that depend on ClusterQueues, and those created after them, are not created
+	wantKinds := []string{"Cohort", "Topology", "ResourceFlavor", "ProvisioningRequestConfig", "AdmissionCheck"}
+	if !reflect.DeepEqual(creator.kinds, wantKinds) {
+		t.Errorf("created kinds = %v, want %v", creator.kinds, wantKinds)
+	}
+}
+
+func TestProvisionKueueObjects_Concurrency(t *testing.T) {
+	cfg := &config.KueueConfig{}
+	for i := range 250 {
+		cfg.LocalQueues = append(cfg.LocalQueues, config.LocalQueue{Name: fmt.Sprintf("lq-%03d", i), Namespace: "team", ClusterQueue: "cq"})
+	}
+
+	creator := newRecordingCreator()
+	if err := ProvisionKueueObjects(context.TODO(), creator, cfg, 25); err != nil {
+		t.Fatalf("ProvisionKueueObjects() error = %v", err)
+	}
+	// 250 LocalQueues plus their namespace
+	if got := len(creator.keys()); got != 251 {
+		t.Errorf("created %d objects, want 251", got)
+	}
+}
+
+func TestDeprovisionKueueObjects(t *testing.T) {
+	ctx := context.TODO()
+	client := &Client{kueueClient: kueuefake.NewSimpleClientset(), clientset: fake.NewClientset()}
+	cfg := &config.KueueConfig{
+		Cohorts:            []config.Cohort{{Name: "root"}, {Name: "team", ParentName: "root"}},
+		Topologies:         []config.KueueTopology{{Name: "dc", Levels: []string{"kubernetes.io/hostname"}}},
+		ResourceFlavors:    []config.ResourceFlavor{{Name: "default"}},
+		ClusterQueues:      []config.ClusterQueue{{Name: "cq", Cohort: "team"}},
+		LocalQueues:        []config.LocalQueue{{Name: "lq", Namespace: "team-a", ClusterQueue: "cq"}, {Name: "lq", ClusterQueue: "cq"}},
+		PriorityClasses:    []config.WorkloadPriorityClass{{Name: "high", Value: 1000, PodPriorityClass: true}},
+		PodPriorityClasses: []config.PodPriorityClass{{Name: "pod-high", Value: 1000}},
+		Namespaces: []config.Namespace{{
+			Name:          "team-a",
+			ResourceQuota: map[string]string{"count/jobs.batch": "10"},
+			LimitRange:    &config.LimitRange{Max: map[string]string{"cpu": "4"}},
+		}},
+	}
+
+	if err := ProvisionKueueObjects(ctx, client, cfg, 0); err != nil {
+		t.Fatalf("ProvisionKueueObjects() error = %v", err)
+	}
+	if lqs, err := client.ListLocalQueues(ctx, ""); err != nil || len(lqs) != 2 {
+		t.Fatalf("ListLocalQueues() = %d queues, err %v; want 2", len(lqs), err)
+	}
+
+	if err := DeprovisionKueueObjects(ctx, client, cfg); err != nil {
+		t.Fatalf("DeprovisionKueueObjects() error = %v", err)
+	}
+	// Deprovisioning again is a no-op
+	if err := DeprovisionKueueObjects(ctx, client, cfg); err != nil {
+		t.Fatalf("second DeprovisionKueueObjects() error = %v", err)
+	}
+
+	counts := map[string]func() (int, error){
+		"Cohorts":         func() (int, error) { l, err := client.ListCohorts(ctx); return len(l), err },
+		"Topologies":      func() (int, error) { l, err := client.ListTopologies(ctx); return len(l), err },
+		"ResourceFlavors": func() (int, error) { l, err := client.ListResourceFlavors(ctx); return len(l), err },
+		"ClusterQueues":   func() (int, error) { l, err := client.ListClusterQueues(ctx); return len(l), err },
+		"LocalQueues":     func() (int, error) { l, err := client.ListLocalQueues(ctx, ""); return len(l), err },
+		"WorkloadPriorityClasses": func() (int, error) {
+			l, err := client.ListWorkloadPriorityClasses(ctx)
+			return len(l), err
+		},
+		"PriorityClasses": func() (int, error) {
+			l, err := client.clientset.SchedulingV1().PriorityClasses().List(ctx, metav1.ListOptions{})
+			return len(l.Items), err
+		},
+		"ResourceQuotas": func() (int, error) {
+			l, err := client.clientset.CoreV1().ResourceQuotas("team-a").List(ctx, metav1.ListOptions{})
+			return len(l.Items), err
+		},
+		"LimitRanges": func() (int, error) {
+			l, err := client.clientset.CoreV1().LimitRanges("team-a").List(ctx, metav1.ListOptions{})
+			return len(l.Items), err
+		},
+	}
+	for kind, count := range counts {
+		n, err := count()
+		if err != nil {
+			t.Fatalf("List%s() error = %v", kind, err)
+		}
+		if n != 0 {
+			t.Errorf("expected no %s after deprovisioning, got %d", kind, n)
+		}
+	}
+}
+
+func TestDeprovisionKueueObjectsOwnership(t *testing.T) {
+	ctx := context.TODO()
+	flavor := func(name string, labels map[string]string) *kueue.ResourceFlavor {
+		return &kueue.ResourceFlavor{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	client := &Client{
+		kueueClient: kueuefake.NewSimpleClientset(
+			flavor("legacy", nil),                                                  // configured, created before labeling
+			flavor("dropped", ownership.Labels("bench", "")),                       // no longer configured
+			flavor("other", ownership.Labels("other", "")),                         // another topology's
+			flavor("foreign", map[string]string{ownership.ManagedByLabel: "helm"}), // configured, not ours
+		),
+		clientset: fake.NewClientset(),
+	}
+	client.SetTopology("bench")
+	cfg := &config.KueueConfig{
+		ResourceFlavors: []config.ResourceFlavor{{Name: "legacy"}, {Name: "foreign"}, {Name: "missing"}},
+	}
+
+	if err := DeprovisionKueueObjects(ctx, client, cfg); err != nil {
+		t.Fatalf("DeprovisionKueueObjects() error = %v", err)
+	}
+
+	flavors, err := client.ListResourceFlavors(ctx)
+	if err != nil {
+		t.Fatalf("ListResourceFlavors() error = %v", err)
+	}
+	var kept []string
+	for _, rf := range flavors {
+		kept = append(kept, rf.Name)
+	}
+	if want := []string{"foreign", "other"}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("kept ResourceFlavors %v, want %v", kept, want)
+	}
+}
+
+func TestProvisionKueueObjectsParallel(t *testing.T) {
+	ctx := context.TODO()
+	client := &Client{kueueClient: kueuefake.NewSimpleClientset(), clientset: fake.NewClientset()}
+	cfg := &config.KueueConfig{ResourceFlavors: []config.ResourceFlavor{{Name: "default"}}}
+	for i := 0; i < 250; i++ {
+		name := fmt.Sprintf("cq-%d", i)
+		cfg.ClusterQueues = append(cfg.ClusterQueues, config.ClusterQueue{Name: name})
+		cfg.LocalQueues = append(cfg.LocalQueues, config.LocalQueue{Name: "lq", Namespace: fmt.Sprintf("ns-%d", i), ClusterQueue: name})
+	}
+
+	if err := ProvisionKueueObjects(ctx, client, cfg, 16); err != nil {
+		t.Fatalf("ProvisionKueueObjects() error = %v", err)
+	}
+	if cqs, err := client.ListClusterQueues(ctx); err != nil || len(cqs) != 250 {
+		t.Errorf("ListClusterQueues() = %d queues, err %v; want 250", len(cqs), err)
+	}
+	if lqs, err := client.ListLocalQueues(ctx, ""); err != nil || len(lqs) != 250 {
+		t.Errorf("ListLocalQueues() = %d queues, err %v; want 250", len(lqs), err)
+	}
+}
+
+func TestProvisionKueueObjectsNamespaces(t *testing.T) {
+	ctx := context.TODO()
+	clientset := fake.NewClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"kubernetes.io/metadata.name": "default"}},
+	})
+	client := &Client{kueueClient: kueuefake.NewSimpleClientset(), clientset: clientset}
+	cfg := &config.KueueConfig{
+		Namespaces: []config.Namespace{
+			{Name: "team-a", Labels: map[string]string{"team": "a"}},
+			{Name: "default", Labels: map[string]string{"shared": "true"}},
+		},
+		LocalQueues: []config.LocalQueue{
+			{Name: "lq", Namespace: "team-a", ClusterQueue: "cq"},
+			{Name: "lq", Namespace: "team-b", ClusterQueue: "cq"},
+		},
+	}
+
+	if err := ProvisionKueueObjects(ctx, client, cfg, 0); err != nil {
+		t.Fatalf("ProvisionKueueObjects() error = %v", err)
+	}
+
+	// Namespaces kueue-bench created are labeled as its own; existing ones are not
+	want := map[string]map[string]string{
+		"team-a":  {"team": "a", ownership.ManagedByLabel: ownership.ManagedBy},
+		"team-b":  {ownership.ManagedByLabel: ownership.ManagedBy},
+		"default": {"kubernetes.io/metadata.name": "default", "shared": "true"},
+	}
+	for name, labels := range want {
+		ns, err := clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("namespace %s not created: %v", name, err)
+		}
+		if !reflect.DeepEqual(ns.Labels, labels) {
+			t.Errorf("namespace %s labels = %v, want %v", name, ns.Labels, labels)
+		}
+	}
+}
+
+func TestProvisionKueueObjectsOwnership(t *testing.T) {
+	ctx := context.TODO()
+	client := &Client{kueueClient: kueuefake.NewSimpleClientset(), clientset: fake.NewClientset()}
+	client.SetTopology("bench")
+	cfg := &config.KueueConfig{
+		ResourceFlavors: []config.ResourceFlavor{{Name: "default"}},
+		ClusterQueues:   []config.ClusterQueue{{Name: "cq"}},
+		LocalQueues:     []config.LocalQueue{{Name: "lq", Namespace: "team", ClusterQueue: "cq"}},
+	}
+	if err := ProvisionKueueObjects(ctx, client, cfg, 0); err != nil {
+		t.Fatalf("ProvisionKueueObjects() error = %v", err)
+	}
+
+	want := ownership.Labels("bench", "")
+	rf, _ := client.GetResourceFlavor(ctx, "default")
+	cq, _ := client.GetClusterQueue(ctx, "cq")
+	lq, _ := client.GetLocalQueue(ctx, "team", "lq")
+	for _, obj := range []metav1.Object{rf, cq, lq} {
+		if !reflect.DeepEqual(obj.GetLabels(), want) {
+			t.Errorf("%s labels = %v, want %v", obj.GetName(), obj.GetLabels(), want)
+		}
+	}
+}
+
+func TestChildCohortsFirst(t *testing.T) {
+	cohort := func(name, parent string) metav1.Object {
+		return &kueue.Cohort{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       kueue.CohortSpec{ParentName: kueue.CohortReference(parent)},
+		}
+	}
+	objects := []metav1.Object{
+		cohort("root", ""), cohort("team", "root"), cohort("sub", "team"), cohort("other", "root"),
+		cohort("loop-a", "loop-b"), cohort("loop-b", "loop-a"),
+	}
+	// Configured parents first, followed by an unconfigured Cohort and a parent cycle
+	keys := []objectKey{{name: "root"}, {name: "team"}, {name: "sub"}, {name: "other"}, {name: "loop-a"}, {name: "loop-b"}}
+	childCohortsFirst(objects, keys)
+
+	var got []string
+	for _, key := range keys {
+		got = append(got, key.name)
+	}
+	position := make(map[string]int, len(got))
+	for i, name := range got {
+		position[name] = i
+	}
+	for _, obj := range objects {
+		parent := string(obj.(*kueue.Cohort).Spec.ParentName)
+		if parent == "" || parent == "loop-a" || parent == "loop-b" {
+			continue
+		}
+		if position[obj.GetName()] > position[parent] {
+			t.Errorf("Cohort %s is deleted after its parent %s: %v", obj.GetName(), parent, got)
+		}
+	}
+	if got[len(got)-1] != "root" || position["team"] > position["other"] {
+		t.Errorf("deletion order = %v, want root last and Cohorts of one depth in their order", got)
+	}
 }
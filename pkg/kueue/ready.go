@@ -0,0 +1,174 @@
+package kueue
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+
+	kbwait "github.com/jhwagner/kueue-bench/pkg/wait"
+)
+
+// DefaultActiveTimeout is how long WaitForActive waits for Kueue objects by default
+const DefaultActiveTimeout = 3 * time.Minute
+
+// activeCondition is the condition type ClusterQueues, LocalQueues, AdmissionChecks and
+// MultiKueueClusters use to report they are usable (e.g. kueue.ClusterQueueActive)
+const activeCondition = "Active"
+
+// WaitForActive waits until every ClusterQueue, LocalQueue, AdmissionCheck and
+// MultiKueueCluster in the cluster reports Active=True. Workloads submitted earlier are
+// not admitted, so benchmarks must not start before this. On timeout the error lists the
+// inactive objects with the reason reported by their Active condition.
+func (c *Client) WaitForActive(ctx context.Context, timeout time.Duration) error {
+	fmt.Println("Waiting for Kueue objects to become active...")
+
+	var inactive []string
+	var lastErr error
+	err := wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		inactive, lastErr = c.inactiveObjects(ctx)
+		return lastErr == nil && len(inactive) == 0, nil
+	})
+	if err != nil {
+		if lastErr != nil {
+			return fmt.Errorf("failed to check Kueue objects: %w", lastErr)
+		}
+		return fmt.Errorf("%d Kueue object(s) not active after %s:\n  %s", len(inactive), timeout, strings.Join(inactive, "\n  "))
+	}
+
+	fmt.Println("✓ Kueue objects active")
+	return nil
+}
+
+// inactiveObjects describes every queue, admission check and MultiKueue cluster that is
+// not yet active, sorted for stable output
+func (c *Client) inactiveObjects(ctx context.Context) ([]string, error) {
+	api := c.kueueClient.KueueV1beta2()
+	var inactive []string
+	check := func(kind, name string, conditions []metav1.Condition) {
+		if reason, ok := inactiveReason(conditions); ok {
+			inactive = append(inactive, fmt.Sprintf("%s %s: %s", kind, name, reason))
+		}
+	}
+
+	cqs, err := api.ClusterQueues().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ClusterQueues: %w", err)
+	}
+	for _, cq := range cqs.Items {
+		check("ClusterQueue", cq.Name, cq.Status.Conditions)
+	}
+
+	lqs, err := api.LocalQueues(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list LocalQueues: %w", err)
+	}
+	for _, lq := range lqs.Items {
+		// Stopped LocalQueues are inactive by design
+		if lq.Spec.StopPolicy != nil && *lq.Spec.StopPolicy != kueue.None {
+			continue
+		}
+		check("LocalQueue", lq.Namespace+"/"+lq.Name, lq.Status.Conditions)
+	}
+
+	acs, err := api.AdmissionChecks().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list AdmissionChecks: %w", err)
+	}
+	for _, ac := range acs.Items {
+		check("AdmissionCheck", ac.Name, ac.Status.Conditions)
+	}
+
+	mkcs, err := api.MultiKueueClusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MultiKueueClusters: %w", err)
+	}
+	for _, mkc := range mkcs.Items {
+		check("MultiKueueCluster", mkc.Name, mkc.Status.Conditions)
+	}
+
+	sort.Strings(inactive)
+	return inactive, nil
+}
+
+// inactiveReason explains why an object is not active, or returns false if it is
+func inactiveReason(conditions []metav1.Condition) (string, bool) {
+	cond := apimeta.FindStatusCondition(conditions, activeCondition)
+	switch {
+	case cond == nil:
+		return "no Active condition reported yet", true
+	case cond.Status == metav1.ConditionTrue:
+		return "", false
+	case cond.Message != "":
+		return fmt.Sprintf("Active=%s (%s: %s)", cond.Status, cond.Reason, cond.Message), true
+	default:
+		return fmt.Sprintf("Active=%s (%s)", cond.Status, cond.Reason), true
+	}
+}
+
+// DefaultIdleTimeout is how long WaitForIdle waits for queues to drain by default
+const DefaultIdleTimeout = 5 * time.Minute
+
+// WaitForIdle waits until no ClusterQueue has pending or quota-reserving Workloads, e.g.
+// after deleting a run's workloads so the next run starts from empty queues. It watches
+// ClusterQueues, so it returns as soon as the last one drains. On timeout the error lists
+// the queues still busy.
+func (c *Client) WaitForIdle(ctx context.Context, timeout time.Duration) error {
+	cqs := c.kueueClient.KueueV1beta2().ClusterQueues()
+	lw := cache.ToListWatcherWithWatchListSemantics(&cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return cqs.List(ctx, opts)
+		},
+		WatchFuncWithContext: func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+			return cqs.Watch(ctx, opts)
+		},
+	}, c.kueueClient)
+	return kbwait.ForList(ctx, "ClusterQueues", lw, &kueue.ClusterQueue{}, timeout, func(objs []runtime.Object) (bool, string) {
+		busy := busyQueues(objs)
+		if len(busy) == 0 {
+			return true, ""
+		}
+		return false, fmt.Sprintf("%d still busy:\n  %s", len(busy), strings.Join(busy, "\n  "))
+	})
+}
+
+// PendingWorkloads returns the number of Workloads waiting for admission across all
+// ClusterQueues
+func (c *Client) PendingWorkloads(ctx context.Context) (int32, error) {
+	cqs, err := c.kueueClient.KueueV1beta2().ClusterQueues().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list ClusterQueues: %w", err)
+	}
+	var pending int32
+	for _, cq := range cqs.Items {
+		pending += cq.Status.PendingWorkloads
+	}
+	return pending, nil
+}
+
+// busyQueues describes every ClusterQueue in cqs with pending or quota-reserving Workloads,
+// sorted for stable output
+func busyQueues(cqs []runtime.Object) []string {
+	var busy []string
+	for _, obj := range cqs {
+		cq, ok := obj.(*kueue.ClusterQueue)
+		if !ok {
+			continue
+		}
+		if cq.Status.PendingWorkloads > 0 || cq.Status.ReservingWorkloads > 0 {
+			busy = append(busy, fmt.Sprintf("%s: %d pending, %d reserving",
+				cq.Name, cq.Status.PendingWorkloads, cq.Status.ReservingWorkloads))
+		}
+	}
+	sort.Strings(busy)
+	return busy
+}
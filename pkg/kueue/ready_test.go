@@ -0,0 +1,96 @@
+package kueue
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+	kueuefake "sigs.k8s.io/kueue/client-go/clientset/versioned/fake"
+)
+
+func TestInactiveObjects(t *testing.T) {
+	active := []metav1.Condition{{Type: "Active", Status: metav1.ConditionTrue, Reason: "Ready"}}
+	held := kueue.Hold
+
+	client := &Client{kueueClient: kueuefake.NewSimpleClientset(
+		&kueue.ClusterQueue{
+			ObjectMeta: metav1.ObjectMeta{Name: "cq-ready"},
+			Status:     kueue.ClusterQueueStatus{Conditions: active},
+		},
+		&kueue.ClusterQueue{
+			ObjectMeta: metav1.ObjectMeta{Name: "cq-missing-flavor"},
+			Status: kueue.ClusterQueueStatus{Conditions: []metav1.Condition{{
+				Type: "Active", Status: metav1.ConditionFalse, Reason: "FlavorNotFound", Message: "Can't admit new workloads",
+			}}},
+		},
+		&kueue.LocalQueue{
+			ObjectMeta: metav1.ObjectMeta{Name: "lq", Namespace: "team-a"},
+			Status:     kueue.LocalQueueStatus{Conditions: active},
+		},
+		&kueue.LocalQueue{
+			ObjectMeta: metav1.ObjectMeta{Name: "lq-held", Namespace: "team-a"},
+			Spec:       kueue.LocalQueueSpec{StopPolicy: &held},
+			Status: kueue.LocalQueueStatus{Conditions: []metav1.Condition{{
+				Type: "Active", Status: metav1.ConditionFalse, Reason: "Stopped",
+			}}},
+		},
+		&kueue.AdmissionCheck{
+			ObjectMeta: metav1.ObjectMeta{Name: "workers"},
+			Status: kueue.AdmissionCheckStatus{Conditions: []metav1.Condition{{
+				Type: "Active", Status: metav1.ConditionFalse, Reason: "NoUsableClusters",
+			}}},
+		},
+		&kueue.MultiKueueCluster{ObjectMeta: metav1.ObjectMeta{Name: "worker-1"}},
+	)}
+
+	got, err := client.inactiveObjects(context.Background())
+	if err != nil {
+		t.Fatalf("inactiveObjects() error = %v", err)
+	}
+	want := []string{
+		"AdmissionCheck workers: Active=False (NoUsableClusters)",
+		"ClusterQueue cq-missing-flavor: Active=False (FlavorNotFound: Can't admit new workloads)",
+		"MultiKueueCluster worker-1: no Active condition reported yet",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("inactiveObjects() = %q, want %q", got, want)
+	}
+}
+
+func TestWaitForIdle(t *testing.T) {
+	ctx := context.Background()
+	pending := &kueue.ClusterQueue{
+		ObjectMeta: metav1.ObjectMeta{Name: "pending"},
+		Status:     kueue.ClusterQueueStatus{PendingWorkloads: 3},
+	}
+	kueueClient := kueuefake.NewSimpleClientset(
+		&kueue.ClusterQueue{ObjectMeta: metav1.ObjectMeta{Name: "idle"}},
+		pending,
+		&kueue.ClusterQueue{
+			ObjectMeta: metav1.ObjectMeta{Name: "draining"},
+			Status:     kueue.ClusterQueueStatus{ReservingWorkloads: 2, AdmittedWorkloads: 2},
+		},
+	)
+	client := &Client{kueueClient: kueueClient}
+
+	err := client.WaitForIdle(ctx, 500*time.Millisecond)
+	want := "2 still busy:\n  draining: 0 pending, 2 reserving\n  pending: 3 pending, 0 reserving"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("WaitForIdle() error = %v, want it to contain %q", err, want)
+	}
+
+	// Draining the last queues ends the wait
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = kueueClient.Tracker().Delete(kueue.SchemeGroupVersion.WithResource("clusterqueues"), "", "draining")
+		pending.Status.PendingWorkloads = 0
+		_, _ = kueueClient.KueueV1beta2().ClusterQueues().UpdateStatus(ctx, pending, metav1.UpdateOptions{})
+	}()
+	if err := client.WaitForIdle(ctx, 5*time.Second); err != nil {
+		t.Errorf("WaitForIdle() error = %v", err)
+	}
+}
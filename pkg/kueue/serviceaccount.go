@@ -0,0 +1,176 @@
+package kueue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/jhwagner/kueue-bench/pkg/wait"
+)
+
+const (
+	// MultiKueueServiceAccount is the ServiceAccount MultiKueue uses to reach a worker
+	// when the worker's credentials are scoped
+	MultiKueueServiceAccount = "multikueue-sa"
+
+	// multiKueueRole names the worker ClusterRole and ClusterRoleBinding
+	multiKueueRole = "multikueue-role"
+
+	// serviceAccountTokenTimeout bounds the wait for the token controller to fill the token Secret
+	serviceAccountTokenTimeout = 30 * time.Second
+)
+
+// multiKueueRules are the permissions the MultiKueue controller needs on a worker, following
+// Kueue's documented MultiKueue setup for every framework with a MultiKueue adapter
+var multiKueueRules = []rbacv1.PolicyRule{
+	{APIGroups: []string{"batch"}, Resources: []string{"jobs"}, Verbs: []string{"create", "delete", "get", "list", "watch"}},
+	{APIGroups: []string{"batch"}, Resources: []string{"jobs/status"}, Verbs: []string{"get"}},
+	{APIGroups: []string{"jobset.x-k8s.io"}, Resources: []string{"jobsets"}, Verbs: []string{"create", "delete", "get", "list", "watch"}},
+	{APIGroups: []string{"jobset.x-k8s.io"}, Resources: []string{"jobsets/status"}, Verbs: []string{"get"}},
+	{APIGroups: []string{"ray.io"}, Resources: []string{"rayjobs", "rayclusters", "rayservices"}, Verbs: []string{"create", "delete", "get", "list", "watch"}},
+	{APIGroups: []string{"ray.io"}, Resources: []string{"rayjobs/status", "rayclusters/status", "rayservices/status"}, Verbs: []string{"get"}},
+	{APIGroups: []string{"kubeflow.org"}, Resources: []string{"mpijobs", "paddlejobs", "pytorchjobs", "tfjobs", "xgboostjobs", "jaxjobs"}, Verbs: []string{"create", "delete", "get", "list", "watch"}},
+	{APIGroups: []string{"kubeflow.org"}, Resources: []string{"mpijobs/status", "paddlejobs/status", "pytorchjobs/status", "tfjobs/status", "xgboostjobs/status", "jaxjobs/status"}, Verbs: []string{"get"}},
+	{APIGroups: []string{"trainer.kubeflow.org"}, Resources: []string{"trainjobs"}, Verbs: []string{"create", "delete", "get", "list", "watch"}},
+	{APIGroups: []string{"trainer.kubeflow.org"}, Resources: []string{"trainjobs/status"}, Verbs: []string{"get"}},
+	{APIGroups: []string{"workload.codeflare.dev"}, Resources: []string{"appwrappers"}, Verbs: []string{"create", "delete", "get", "list", "watch"}},
+	{APIGroups: []string{"workload.codeflare.dev"}, Resources: []string{"appwrappers/status"}, Verbs: []string{"get"}},
+	{APIGroups: []string{"apps"}, Resources: []string{"statefulsets"}, Verbs: []string{"create", "delete", "get", "list", "watch"}},
+	{APIGroups: []string{"apps"}, Resources: []string{"statefulsets/status"}, Verbs: []string{"get"}},
+	{APIGroups: []string{"leaderworkerset.x-k8s.io"}, Resources: []string{"leaderworkersets"}, Verbs: []string{"create", "delete", "get", "list", "watch"}},
+	{APIGroups: []string{"leaderworkerset.x-k8s.io"}, Resources: []string{"leaderworkersets/status"}, Verbs: []string{"get"}},
+	{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"create", "delete", "get", "list", "watch"}},
+	{APIGroups: []string{""}, Resources: []string{"pods/status"}, Verbs: []string{"get"}},
+	{APIGroups: []string{"kueue.x-k8s.io"}, Resources: []string{"workloads"}, Verbs: []string{"create", "delete", "get", "list", "watch"}},
+	{APIGroups: []string{"kueue.x-k8s.io"}, Resources: []string{"workloads/status"}, Verbs: []string{"get", "patch", "update"}},
+}
+
+// ServiceAccountKubeconfig creates the MultiKueue ServiceAccount and its RBAC on a worker
+// cluster and returns a token-based kubeconfig for it. The API server address is taken from
+// baseKubeconfig, e.g. the worker's internal admin kubeconfig. The ServiceAccount and its
+// token Secret are created in namespace.
+func ServiceAccountKubeconfig(ctx context.Context, worker *Client, namespace string, baseKubeconfig []byte) ([]byte, error) {
+	base, err := clientcmd.Load(baseKubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig: %w", err)
+	}
+	currentContext, ok := base.Contexts[base.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig has no current context")
+	}
+	server := base.Clusters[currentContext.Cluster]
+	if server == nil {
+		return nil, fmt.Errorf("kubeconfig has no cluster %q", currentContext.Cluster)
+	}
+
+	token, ca, err := worker.createMultiKueueServiceAccount(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(ca) == 0 {
+		ca = server.CertificateAuthorityData
+	}
+
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters[currentContext.Cluster] = &clientcmdapi.Cluster{
+		Server:                   server.Server,
+		CertificateAuthorityData: ca,
+	}
+	kubeconfig.AuthInfos[MultiKueueServiceAccount] = &clientcmdapi.AuthInfo{Token: string(token)}
+	kubeconfig.Contexts[MultiKueueServiceAccount] = &clientcmdapi.Context{
+		Cluster:   currentContext.Cluster,
+		AuthInfo:  MultiKueueServiceAccount,
+		Namespace: namespace,
+	}
+	kubeconfig.CurrentContext = MultiKueueServiceAccount
+
+	data, err := clientcmd.Write(*kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	return data, nil
+}
+
+// RotateServiceAccountKubeconfig revokes the worker's MultiKueue ServiceAccount token by
+// deleting its token Secret, then issues a new token and returns a kubeconfig for it, as
+// ServiceAccountKubeconfig does. Kubeconfigs holding the old token stop working immediately.
+func RotateServiceAccountKubeconfig(ctx context.Context, worker *Client, namespace string, baseKubeconfig []byte) ([]byte, error) {
+	secretName := MultiKueueServiceAccount + "-token"
+	err := worker.clientset.CoreV1().Secrets(namespace).Delete(ctx, secretName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to delete Secret %s/%s: %w", namespace, secretName, err)
+	}
+	return ServiceAccountKubeconfig(ctx, worker, namespace, baseKubeconfig)
+}
+
+// createMultiKueueServiceAccount creates the MultiKueue ServiceAccount, ClusterRole,
+// ClusterRoleBinding and a long-lived token Secret, and returns the token and cluster CA
+func (c *Client) createMultiKueueServiceAccount(ctx context.Context, namespace string) (token, ca []byte, err error) {
+	if err := c.CreateNamespace(ctx, namespace, nil); err != nil {
+		return nil, nil, err
+	}
+
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: MultiKueueServiceAccount, Namespace: namespace},
+	}
+	c.own(sa)
+	if _, err := c.clientset.CoreV1().ServiceAccounts(namespace).Create(ctx, sa, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, nil, fmt.Errorf("failed to create ServiceAccount %s/%s: %w", namespace, MultiKueueServiceAccount, err)
+	}
+
+	role := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: multiKueueRole},
+		Rules:      multiKueueRules,
+	}
+	c.own(role)
+	_, err = c.clientset.RbacV1().ClusterRoles().Create(ctx, role, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		_, err = c.clientset.RbacV1().ClusterRoles().Update(ctx, role, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create or update ClusterRole %s: %w", multiKueueRole, err)
+	}
+
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: multiKueueRole},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: multiKueueRole},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      MultiKueueServiceAccount,
+			Namespace: namespace,
+		}},
+	}
+	c.own(binding)
+	if _, err := c.clientset.RbacV1().ClusterRoleBindings().Create(ctx, binding, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, nil, fmt.Errorf("failed to create ClusterRoleBinding %s: %w", multiKueueRole, err)
+	}
+
+	secretName := MultiKueueServiceAccount + "-token"
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        secretName,
+			Namespace:   namespace,
+			Annotations: map[string]string{corev1.ServiceAccountNameKey: MultiKueueServiceAccount},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}
+	c.own(secret)
+	secrets := c.clientset.CoreV1().Secrets(namespace)
+	if _, err := secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, nil, fmt.Errorf("failed to create Secret %s/%s: %w", namespace, secretName, err)
+	}
+
+	// The token controller fills in the token and CA asynchronously
+	s, err := wait.SecretData(ctx, c.clientset, namespace, secretName, corev1.ServiceAccountTokenKey, serviceAccountTokenTimeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("token for ServiceAccount %s/%s was not issued: %w", namespace, MultiKueueServiceAccount, err)
+	}
+	return s.Data[corev1.ServiceAccountTokenKey], s.Data[corev1.ServiceAccountRootCAKey], nil
+}
@@ -0,0 +1,162 @@
+package kueue
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+)
+
+func TestServiceAccountKubeconfig(t *testing.T) {
+	ctx := context.TODO()
+	base := []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: kind-worker-1
+  cluster:
+    server: https://worker-1-control-plane:6443
+    certificate-authority-data: YWRtaW4tY2E=
+users:
+- name: kind-worker-1
+  user:
+    client-certificate-data: Y2VydA==
+    client-key-data: a2V5
+contexts:
+- name: kind-worker-1
+  context:
+    cluster: kind-worker-1
+    user: kind-worker-1
+current-context: kind-worker-1
+`)
+	// Stand in for the token controller, which fills the token Secret asynchronously
+	tokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: MultiKueueServiceAccount + "-token", Namespace: config.DefaultKueueNamespace},
+		Type:       corev1.SecretTypeServiceAccountToken,
+		Data: map[string][]byte{
+			corev1.ServiceAccountTokenKey:  []byte("sa-token"),
+			corev1.ServiceAccountRootCAKey: []byte("sa-ca"),
+		},
+	}
+	worker := &Client{clientset: fake.NewClientset(tokenSecret)}
+
+	data, err := ServiceAccountKubeconfig(ctx, worker, config.DefaultKueueNamespace, base)
+	if err != nil {
+		t.Fatalf("ServiceAccountKubeconfig() error = %v", err)
+	}
+
+	kubeconfig, err := clientcmd.Load(data)
+	if err != nil {
+		t.Fatalf("failed to parse generated kubeconfig: %v", err)
+	}
+	current := kubeconfig.Contexts[kubeconfig.CurrentContext]
+	if current == nil {
+		t.Fatalf("generated kubeconfig has no current context")
+	}
+	cluster, user := kubeconfig.Clusters[current.Cluster], kubeconfig.AuthInfos[current.AuthInfo]
+	if cluster.Server != "https://worker-1-control-plane:6443" || string(cluster.CertificateAuthorityData) != "sa-ca" {
+		t.Errorf("cluster = %+v, want worker server with ServiceAccount CA", cluster)
+	}
+	if user.Token != "sa-token" || len(user.ClientCertificateData) != 0 {
+		t.Errorf("user = %+v, want only the ServiceAccount token", user)
+	}
+
+	if _, err := worker.clientset.CoreV1().ServiceAccounts(config.DefaultKueueNamespace).Get(ctx, MultiKueueServiceAccount, metav1.GetOptions{}); err != nil {
+		t.Errorf("ServiceAccount not created: %v", err)
+	}
+	binding, err := worker.clientset.RbacV1().ClusterRoleBindings().Get(ctx, multiKueueRole, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("ClusterRoleBinding not created: %v", err)
+	}
+	if binding.RoleRef.Name != multiKueueRole || binding.Subjects[0].Name != MultiKueueServiceAccount {
+		t.Errorf("ClusterRoleBinding = %+v, want %s bound to %s", binding, multiKueueRole, MultiKueueServiceAccount)
+	}
+
+	// Idempotent when the objects already exist
+	if _, err := ServiceAccountKubeconfig(ctx, worker, config.DefaultKueueNamespace, base); err != nil {
+		t.Errorf("second ServiceAccountKubeconfig() error = %v", err)
+	}
+}
+
+func TestRotateServiceAccountKubeconfig(t *testing.T) {
+	ctx := context.TODO()
+	base := []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: worker
+  cluster:
+    server: https://worker:6443
+contexts:
+- name: worker
+  context:
+    cluster: worker
+current-context: worker
+`)
+	// Stand in for the token controller: issue a new token for every token Secret created
+	clientset := fake.NewClientset()
+	issued := 0
+	clientset.PrependReactor("create", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		secret := action.(k8stesting.CreateAction).GetObject().(*corev1.Secret)
+		if secret.Type == corev1.SecretTypeServiceAccountToken {
+			issued++
+			secret.Data = map[string][]byte{corev1.ServiceAccountTokenKey: []byte(fmt.Sprintf("token-%d", issued))}
+		}
+		return false, nil, nil
+	})
+	worker := &Client{clientset: clientset}
+
+	tokenOf := func(data []byte) string {
+		t.Helper()
+		kubeconfig, err := clientcmd.Load(data)
+		if err != nil {
+			t.Fatalf("failed to parse generated kubeconfig: %v", err)
+		}
+		return kubeconfig.AuthInfos[MultiKueueServiceAccount].Token
+	}
+
+	data, err := ServiceAccountKubeconfig(ctx, worker, config.DefaultKueueNamespace, base)
+	if err != nil {
+		t.Fatalf("ServiceAccountKubeconfig() error = %v", err)
+	}
+	if got := tokenOf(data); got != "token-1" {
+		t.Fatalf("token = %q, want token-1", got)
+	}
+
+	data, err = RotateServiceAccountKubeconfig(ctx, worker, config.DefaultKueueNamespace, base)
+	if err != nil {
+		t.Fatalf("RotateServiceAccountKubeconfig() error = %v", err)
+	}
+	if got := tokenOf(data); got != "token-2" {
+		t.Errorf("token after rotation = %q, want token-2", got)
+	}
+}
+
+// TestMultiKueueRulesAllowWorkerCheck checks that CheckWorkerKubeconfig only probes what the
+// MultiKueue ServiceAccount is granted: listing Workloads, not Pods or other core resources
+func TestMultiKueueRulesAllowWorkerCheck(t *testing.T) {
+	allowed := func(group, resource, verb string) bool {
+		for _, rule := range multiKueueRules {
+			if slices.Contains(rule.APIGroups, group) && slices.Contains(rule.Resources, resource) && slices.Contains(rule.Verbs, verb) {
+				return true
+			}
+		}
+		return false
+	}
+	if !allowed(kueue.GroupVersion.Group, "workloads", "list") {
+		t.Error("the MultiKueue role does not allow listing Workloads, which CheckWorkerKubeconfig probes")
+	}
+	for _, resource := range []string{"namespaces", "nodes", "secrets"} {
+		if allowed("", resource, "list") {
+			t.Errorf("the MultiKueue role allows listing %s", resource)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package kueue
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// DefaultReadyTimeout is how long WaitForReady waits by default
+const DefaultReadyTimeout = 5 * time.Minute
+
+// kueueControllerDeployment is the Deployment running the Kueue controller manager
+const kueueControllerDeployment = "kueue-controller-manager"
+
+// WaitForReady waits until the cluster can run benchmarks: the Kueue controller Deployment
+// is available, CRDs and webhooks are ready as WaitForAPIReady checks, the Kueue CRDs serve
+// the API version kueue-bench uses, every queue, admission check and MultiKueueCluster is
+// active as WaitForActive checks, and each of multiKueueClusters exists. Unlike the other
+// waits it prints nothing, so several clusters can be waited for concurrently. On timeout
+// the error lists everything still not ready.
+func (c *Client) WaitForReady(ctx context.Context, timeout time.Duration, multiKueueClusters []string) error {
+	var pending []string
+	var lastErr error
+	err := wait.PollUntilContextTimeout(ctx, 2*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		pending, lastErr = c.notReady(ctx, multiKueueClusters)
+		return lastErr == nil && len(pending) == 0, nil
+	})
+	if err != nil {
+		if lastErr != nil {
+			return fmt.Errorf("failed to check readiness: %w", lastErr)
+		}
+		return fmt.Errorf("%d check(s) not ready after %s:\n  %s", len(pending), timeout, strings.Join(pending, "\n  "))
+	}
+	return nil
+}
+
+// notReady describes everything WaitForReady is still waiting for, sorted for stable output
+func (c *Client) notReady(ctx context.Context, multiKueueClusters []string) ([]string, error) {
+	var pending []string
+
+	deployment, err := c.clientset.AppsV1().Deployments(kueueNamespace).Get(ctx, kueueControllerDeployment, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		pending = append(pending, fmt.Sprintf("deployment %s/%s: not found", kueueNamespace, kueueControllerDeployment))
+	case err != nil:
+		return nil, fmt.Errorf("failed to get deployment %s/%s: %w", kueueNamespace, kueueControllerDeployment, err)
+	case !deploymentAvailable(deployment):
+		pending = append(pending, fmt.Sprintf("deployment %s/%s: %d/%d replicas available", kueueNamespace, kueueControllerDeployment,
+			deployment.Status.AvailableReplicas, deployment.Status.Replicas))
+	}
+
+	apis, err := c.pendingAPIs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pending = append(pending, apis...)
+
+	crds, err := c.CRDVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pending = append(pending, crdVersionProblems(crds)...)
+
+	inactive, err := c.inactiveObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pending = append(pending, inactive...)
+
+	if len(multiKueueClusters) > 0 {
+		mkcs, err := c.kueueClient.KueueV1beta2().MultiKueueClusters().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list MultiKueueClusters: %w", err)
+		}
+		existing := make(map[string]bool, len(mkcs.Items))
+		for _, mkc := range mkcs.Items {
+			existing[mkc.Name] = true
+		}
+		for _, name := range multiKueueClusters {
+			if !existing[name] {
+				pending = append(pending, fmt.Sprintf("MultiKueueCluster %s: not found", name))
+			}
+		}
+	}
+
+	sort.Strings(pending)
+	return pending, nil
+}
+
+// deploymentAvailable reports whether a Deployment has the Available condition
+func deploymentAvailable(deployment *appsv1.Deployment) bool {
+	for _, cond := range deployment.Status.Conditions {
+		if cond.Type == appsv1.DeploymentAvailable {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
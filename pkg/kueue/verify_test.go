@@ -0,0 +1,71 @@
+package kueue
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	kueue "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+	kueuefake "sigs.k8s.io/kueue/client-go/clientset/versioned/fake"
+)
+
+func TestNotReady(t *testing.T) {
+	var crds []runtime.Object
+	for _, kind := range usedKinds {
+		if kind == "Cohort" {
+			continue
+		}
+		crds = append(crds, &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: strings.ToLower(kind) + "s.kueue.x-k8s.io"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group:    "kueue.x-k8s.io",
+				Names:    apiextensionsv1.CustomResourceDefinitionNames{Kind: kind},
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1beta2", Served: true, Storage: true}},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{
+				Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue}},
+			},
+		})
+	}
+	active := []metav1.Condition{{Type: "Active", Status: metav1.ConditionTrue, Reason: "Active"}}
+
+	client := &Client{
+		kueueClient: kueuefake.NewSimpleClientset(
+			&kueue.ClusterQueue{ObjectMeta: metav1.ObjectMeta{Name: "cq"}},
+			&kueue.MultiKueueCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+				Status:     kueue.MultiKueueClusterStatus{Conditions: active},
+			},
+		),
+		crdClient: apiextensionsfake.NewSimpleClientset(crds...),
+		clientset: k8sfake.NewSimpleClientset(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: kueueControllerDeployment, Namespace: kueueNamespace},
+			Status: appsv1.DeploymentStatus{
+				Replicas:   1,
+				Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse}},
+			},
+		}),
+	}
+
+	pending, err := client.notReady(context.TODO(), []string{"worker-1", "worker-2"})
+	if err != nil {
+		t.Fatalf("notReady() error = %v", err)
+	}
+	want := []string{
+		"ClusterQueue cq: no Active condition reported yet",
+		"Cohort: CRD not installed",
+		"MultiKueueCluster worker-2: not found",
+		"deployment kueue-system/kueue-controller-manager: 0/1 replicas available",
+	}
+	if !reflect.DeepEqual(pending, want) {
+		t.Errorf("notReady() =\n%v\nwant\n%v", pending, want)
+	}
+}
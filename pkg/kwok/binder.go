@@ -0,0 +1,238 @@
+package kwok
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// BypassSchedulerName is the schedulerName of pods bound directly to Kwok nodes by a
+	// Binder instead of kube-scheduler
+	BypassSchedulerName = "kueue-bench-bypass"
+
+	// binderQPS bounds pod binding calls
+	binderQPS = 200
+)
+
+// bindableNodes matches the nodes a Binder may bind pods to
+var bindableNodes = labels.SelectorFromSet(labels.Set{"type": "kwok"})
+
+// Binder binds pending pods that request BypassSchedulerName straight to Kwok nodes,
+// skipping kube-scheduler. A node is eligible if it matches the pod's node selector and
+// required node affinity and the pod tolerates its taints; eligible nodes are used
+// round-robin. Resource fit is not checked: Kueue quota is the only admission control.
+type Binder struct {
+	clientset kubernetes.Interface
+	factory   informers.SharedInformerFactory
+	pods      corelisters.PodLister
+	nodes     corelisters.NodeLister
+	queue     workqueue.TypedRateLimitingInterface[string]
+
+	mu   sync.Mutex
+	next int // round-robin offset across eligible nodes
+}
+
+// NewBinder creates a Binder for the cluster at kubeconfigPath
+func NewBinder(kubeconfigPath string, opts ...restconfig.Option) (*Binder, error) {
+	restConfig, err := restconfig.ForKubeconfig(kubeconfigPath, binderQPS, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	return newBinder(clientset), nil
+}
+
+// newBinder creates a Binder using the given clientset
+func newBinder(clientset kubernetes.Interface) *Binder {
+	factory := informers.NewSharedInformerFactory(clientset, 0)
+	return &Binder{
+		clientset: clientset,
+		factory:   factory,
+		pods:      factory.Core().V1().Pods().Lister(),
+		nodes:     factory.Core().V1().Nodes().Lister(),
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(
+			workqueue.DefaultTypedControllerRateLimiter[string](),
+			workqueue.TypedRateLimitingQueueConfig[string]{Name: "kwok-binder"},
+		),
+	}
+}
+
+// Run binds pods until ctx is cancelled. It returns an error only if the informers
+// cannot be set up or synced.
+func (b *Binder) Run(ctx context.Context) error {
+	defer b.queue.ShutDown()
+
+	enqueue := func(obj interface{}) {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || !needsBinding(pod) {
+			return
+		}
+		if key, err := cache.MetaNamespaceKeyFunc(pod); err == nil {
+			b.queue.Add(key)
+		}
+	}
+	if _, err := b.factory.Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    enqueue,
+		UpdateFunc: func(_, newObj interface{}) { enqueue(newObj) },
+	}); err != nil {
+		return fmt.Errorf("failed to add pod event handler: %w", err)
+	}
+	// Register the node informer so the node lister is populated
+	b.factory.Core().V1().Nodes().Informer()
+
+	b.factory.Start(ctx.Done())
+	defer b.factory.Shutdown()
+	for informer, ok := range b.factory.WaitForCacheSync(ctx.Done()) {
+		if !ok {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("cache sync failed for %v", informer)
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		b.queue.ShutDown()
+	}()
+	for b.processNext(ctx) {
+	}
+	return nil
+}
+
+// processNext binds the next queued pod, requeueing it with backoff on failure
+func (b *Binder) processNext(ctx context.Context) bool {
+	key, shutdown := b.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer b.queue.Done(key)
+
+	if err := b.bind(ctx, key); err != nil {
+		b.queue.AddRateLimited(key)
+		return true
+	}
+	b.queue.Forget(key)
+	return true
+}
+
+// bind binds the pod with the given key to an eligible node
+func (b *Binder) bind(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return nil
+	}
+	pod, err := b.pods.Pods(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !needsBinding(pod) {
+		return nil
+	}
+
+	nodes, err := b.nodes.List(bindableNodes)
+	if err != nil {
+		return err
+	}
+	node := b.pickNode(pod, nodes)
+	if node == "" {
+		return fmt.Errorf("no eligible node for pod %s", key)
+	}
+
+	binding := &corev1.Binding{
+		ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace, UID: pod.UID},
+		Target:     corev1.ObjectReference{Kind: "Node", Name: node},
+	}
+	err = b.clientset.CoreV1().Pods(pod.Namespace).Bind(ctx, binding, metav1.CreateOptions{})
+	if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+		// Already bound or deleted
+		return nil
+	}
+	return err
+}
+
+// pickNode returns the next eligible node for the pod in round-robin order, or "".
+// Cordoned and NotReady nodes are never eligible.
+func (b *Binder) pickNode(pod *corev1.Pod, nodes []*corev1.Node) string {
+	affinity := nodeaffinity.GetRequiredNodeAffinity(pod)
+	var eligible []string
+	for _, node := range nodes {
+		if node.Spec.Unschedulable || !isNodeReady(node) {
+			continue
+		}
+		if ok, err := affinity.Match(node); err != nil || !ok {
+			continue
+		}
+		if _, untolerated := corev1helpers.FindMatchingUntoleratedTaint(klog.Background(), node.Spec.Taints, pod.Spec.Tolerations, schedulingTaint, false); untolerated {
+			continue
+		}
+		eligible = append(eligible, node.Name)
+	}
+	if len(eligible) == 0 {
+		return ""
+	}
+	sort.Strings(eligible)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.next++
+	return eligible[b.next%len(eligible)]
+}
+
+// Unbound returns the number of pods waiting for the Binder. It fails until Run has
+// synced the pod cache.
+func (b *Binder) Unbound() (int, error) {
+	if !b.factory.Core().V1().Pods().Informer().HasSynced() {
+		return 0, fmt.Errorf("pod cache not synced")
+	}
+	pods, err := b.pods.List(labels.Everything())
+	if err != nil {
+		return 0, err
+	}
+	unbound := 0
+	for _, pod := range pods {
+		if needsBinding(pod) {
+			unbound++
+		}
+	}
+	return unbound, nil
+}
+
+// needsBinding reports whether a pod is waiting for the Binder: it requests the bypass
+// scheduler, is unbound, has no scheduling gates, and is not terminating
+func needsBinding(pod *corev1.Pod) bool {
+	return pod.Spec.SchedulerName == BypassSchedulerName &&
+		pod.Spec.NodeName == "" &&
+		len(pod.Spec.SchedulingGates) == 0 &&
+		pod.DeletionTimestamp == nil
+}
+
+// schedulingTaint selects the taints that prevent scheduling
+func schedulingTaint(t *corev1.Taint) bool {
+	return t.Effect == corev1.TaintEffectNoSchedule || t.Effect == corev1.TaintEffectNoExecute
+}
@@ -0,0 +1,126 @@
+package kwok
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBinderPickNode(t *testing.T) {
+	node := func(name, zone string, ready bool, taints ...corev1.Taint) *corev1.Node {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"type": "kwok", "zone": zone}},
+			Spec:       corev1.NodeSpec{Taints: taints},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: status},
+			}},
+		}
+	}
+	cordoned := node("node-e", "b", true)
+	cordoned.Spec.Unschedulable = true
+	gpuTaint := corev1.Taint{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule}
+	nodes := []*corev1.Node{
+		node("node-a", "a", true),
+		node("node-b", "b", true),
+		node("node-c", "a", false),
+		node("node-d", "a", true, gpuTaint),
+		cordoned,
+	}
+
+	tests := []struct {
+		name string
+		spec corev1.PodSpec
+		want []string // nodes picked by successive calls
+	}{
+		{
+			name: "round-robin across ready untainted uncordoned nodes",
+			spec: corev1.PodSpec{},
+			want: []string{"node-b", "node-a", "node-b"},
+		},
+		{
+			name: "node selector",
+			spec: corev1.PodSpec{NodeSelector: map[string]string{"zone": "a"}},
+			want: []string{"node-a", "node-a"},
+		},
+		{
+			name: "tolerated taint",
+			spec: corev1.PodSpec{
+				NodeSelector: map[string]string{"zone": "a"},
+				Tolerations:  []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}},
+			},
+			want: []string{"node-d", "node-a"},
+		},
+		{
+			name: "no eligible node",
+			spec: corev1.PodSpec{NodeSelector: map[string]string{"zone": "c"}},
+			want: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBinder(fake.NewClientset())
+			pod := &corev1.Pod{Spec: tt.spec}
+			for i, want := range tt.want {
+				if got := b.pickNode(pod, nodes); got != want {
+					t.Errorf("pickNode() call %d = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNeedsBinding(t *testing.T) {
+	now := metav1.Now()
+	tests := []struct {
+		name string
+		pod  corev1.Pod
+		want bool
+	}{
+		{
+			name: "pending bypass pod",
+			pod:  corev1.Pod{Spec: corev1.PodSpec{SchedulerName: BypassSchedulerName}},
+			want: true,
+		},
+		{
+			name: "default scheduler",
+			pod:  corev1.Pod{Spec: corev1.PodSpec{SchedulerName: corev1.DefaultSchedulerName}},
+			want: false,
+		},
+		{
+			name: "already bound",
+			pod:  corev1.Pod{Spec: corev1.PodSpec{SchedulerName: BypassSchedulerName, NodeName: "node-a"}},
+			want: false,
+		},
+		{
+			name: "scheduling gated",
+			pod: corev1.Pod{Spec: corev1.PodSpec{
+				SchedulerName:   BypassSchedulerName,
+				SchedulingGates: []corev1.PodSchedulingGate{{Name: "kueue.x-k8s.io/topology"}},
+			}},
+			want: false,
+		},
+		{
+			name: "terminating",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now},
+				Spec:       corev1.PodSpec{SchedulerName: BypassSchedulerName},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := needsBinding(&tt.pod); got != tt.want {
+				t.Errorf("needsBinding() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package kwok
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// Compatibility is the range of Kubernetes minor versions a Kwok release is tested against
+type Compatibility struct {
+	KwokVersion   string
+	MinKubernetes string
+	MaxKubernetes string
+}
+
+// compatibilityMatrix lists tested Kubernetes versions per Kwok release, newest release
+// first. Ranges come from the supported_releases.txt published with each release, which
+// records the Kubernetes versions its e2e suite runs against.
+var compatibilityMatrix = []Compatibility{
+	{KwokVersion: "v0.7.0", MinKubernetes: "1.10", MaxKubernetes: "1.33"},
+}
+
+// SelectVersion picks the Kwok version to install into a cluster running kubernetesVersion.
+// A requested version is always honored; otherwise the newest release tested against
+// kubernetesVersion is chosen, falling back to DefaultKwokVersion. A non-empty warning
+// is returned when the chosen pair is untested.
+func SelectVersion(requested, kubernetesVersion string) (selected, warning string) {
+	kube, _ := version.ParseGeneric(kubernetesVersion) // nil if unparseable
+
+	if requested != "" {
+		return requested, compatibilityWarning(requested, kubernetesVersion, kube)
+	}
+
+	if kube != nil {
+		for _, c := range compatibilityMatrix {
+			if c.supports(kube) {
+				return c.KwokVersion, ""
+			}
+		}
+	}
+	return DefaultKwokVersion, compatibilityWarning(DefaultKwokVersion, kubernetesVersion, kube)
+}
+
+// compatibilityWarning describes why a Kwok/Kubernetes pair is untested, or returns ""
+func compatibilityWarning(kwokVersion, kubernetesVersion string, kube *version.Version) string {
+	if kube == nil {
+		return fmt.Sprintf("cannot determine Kubernetes version from %q; Kwok %s compatibility is unchecked", kubernetesVersion, kwokVersion)
+	}
+	for _, c := range compatibilityMatrix {
+		if c.KwokVersion != kwokVersion {
+			continue
+		}
+		if c.supports(kube) {
+			return ""
+		}
+		return fmt.Sprintf("Kwok %s is tested with Kubernetes %s to %s, not %s",
+			kwokVersion, c.MinKubernetes, c.MaxKubernetes, kubernetesVersion)
+	}
+	return fmt.Sprintf("no compatibility data for Kwok %s; Kubernetes %s is untested", kwokVersion, kubernetesVersion)
+}
+
+// supports reports whether kube's minor version falls within the tested range
+func (c Compatibility) supports(kube *version.Version) bool {
+	minor := version.MajorMinor(kube.Major(), kube.Minor())
+	return minor.AtLeast(version.MustParseGeneric(c.MinKubernetes)) &&
+		version.MustParseGeneric(c.MaxKubernetes).AtLeast(minor)
+}
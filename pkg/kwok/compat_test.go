@@ -0,0 +1,72 @@
+package kwok
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jhwagner/kueue-bench/pkg/cluster"
+)
+
+func TestSelectVersion(t *testing.T) {
+	tests := []struct {
+		name              string
+		requested         string
+		kubernetesVersion string
+		want              string
+		warningContains   string
+	}{
+		{
+			name:              "auto-select for tested version",
+			kubernetesVersion: "v1.31.0",
+			want:              "v0.7.0",
+		},
+		{
+			name:              "auto-select falls back to default with warning",
+			kubernetesVersion: "v1.35.0",
+			want:              DefaultKwokVersion,
+			warningContains:   "tested with Kubernetes 1.10 to 1.33, not v1.35.0",
+		},
+		{
+			name:              "requested version is honored",
+			requested:         "v0.7.0",
+			kubernetesVersion: "v1.33.4",
+			want:              "v0.7.0",
+		},
+		{
+			name:              "requested version without compatibility data",
+			requested:         "v0.8.0",
+			kubernetesVersion: "v1.33.0",
+			want:              "v0.8.0",
+			warningContains:   "no compatibility data for Kwok v0.8.0",
+		},
+		{
+			name:              "unknown kubernetes version",
+			kubernetesVersion: "",
+			want:              DefaultKwokVersion,
+			warningContains:   "cannot determine Kubernetes version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warning := SelectVersion(tt.requested, tt.kubernetesVersion)
+			if got != tt.want {
+				t.Errorf("SelectVersion() = %q, want %q", got, tt.want)
+			}
+			if tt.warningContains == "" && warning != "" {
+				t.Errorf("SelectVersion() unexpected warning %q", warning)
+			}
+			if tt.warningContains != "" && !strings.Contains(warning, tt.warningContains) {
+				t.Errorf("SelectVersion() warning = %q, expected to contain %q", warning, tt.warningContains)
+			}
+		})
+	}
+}
+
+func TestDefaultKubernetesVersionIsTested(t *testing.T) {
+	selected, warning := SelectVersion("", cluster.KubernetesVersion(""))
+	if selected != DefaultKwokVersion || warning != "" {
+		t.Errorf("SelectVersion() for the default Kubernetes %s = %s, %q; want %s without a warning",
+			cluster.DefaultKubernetesVersion, selected, warning, DefaultKwokVersion)
+	}
+}
@@ -0,0 +1,132 @@
+package kwok
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+	resourcev1 "k8s.io/api/resource/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	resourcev1ac "k8s.io/client-go/applyconfigurations/resource/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// deviceApplyQPS bounds ResourceSlice creation; one slice is applied per node and driver
+const deviceApplyQPS = 100
+
+// CreateDevices publishes the simulated DRA devices declared by node pools: one
+// ResourceSlice per node and driver, plus any requested DeviceClasses. Pools without
+// devices are skipped, so this is a no-op for topologies that do not use DRA.
+func CreateDevices(ctx context.Context, kubeconfigPath string, nodePools []config.NodePool, opts ...restconfig.Option) error {
+	total := 0
+	for _, pool := range nodePools {
+		total += pool.Count * len(pool.Devices)
+	}
+	if total == 0 {
+		return nil
+	}
+
+	restConfig, err := restconfig.ForKubeconfig(kubeconfigPath, deviceApplyQPS, opts...)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	fmt.Printf("Creating %d ResourceSlices...\n", total)
+	if err := createDevices(ctx, clientset, nodePools); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Devices created successfully\n")
+	return nil
+}
+
+// createDevices applies the DeviceClasses and ResourceSlices of the node pools
+func createDevices(ctx context.Context, clientset kubernetes.Interface, nodePools []config.NodePool) error {
+	applyOpts := metav1.ApplyOptions{FieldManager: fieldManager, Force: true}
+	classes := make(map[string]bool)
+	for _, pool := range nodePools {
+		for _, set := range pool.Devices {
+			if set.DeviceClass != "" && !classes[set.DeviceClass] {
+				if _, err := clientset.ResourceV1().DeviceClasses().Apply(ctx, deviceClass(&set), applyOpts); err != nil {
+					return fmt.Errorf("failed to apply DeviceClass %s: %w", set.DeviceClass, err)
+				}
+				classes[set.DeviceClass] = true
+			}
+
+			namer, err := config.NewNodeNamer(&pool)
+			if err != nil {
+				return fmt.Errorf("pool '%s': %w", pool.Name, err)
+			}
+			for i := 0; i < pool.Count; i++ {
+				node, err := namer.Name(i)
+				if err != nil {
+					return fmt.Errorf("pool '%s': %w", pool.Name, err)
+				}
+				slice, err := resourceSlice(node, &set)
+				if err != nil {
+					return fmt.Errorf("pool '%s': %w", pool.Name, err)
+				}
+				if _, err := clientset.ResourceV1().ResourceSlices().Apply(ctx, slice, applyOpts); err != nil {
+					return fmt.Errorf("failed to apply ResourceSlice for pool %s: %w", pool.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resourceSlice builds the ResourceSlice publishing a device set on one node. Each node
+// is its own resource pool, as with a real node-local DRA driver.
+func resourceSlice(node string, set *config.DeviceSet) (*resourcev1ac.ResourceSliceApplyConfiguration, error) {
+	attributes := make(map[resourcev1.QualifiedName]resourcev1ac.DeviceAttributeApplyConfiguration, len(set.Attributes))
+	for name, value := range set.Attributes {
+		attributes[resourcev1.QualifiedName(name)] = *resourcev1ac.DeviceAttribute().WithStringValue(value)
+	}
+	capacity := make(map[resourcev1.QualifiedName]resourcev1ac.DeviceCapacityApplyConfiguration, len(set.Capacity))
+	for name, value := range set.Capacity {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, fmt.Errorf("device set '%s': invalid capacity %s '%s': %w", set.Driver, name, value, err)
+		}
+		capacity[resourcev1.QualifiedName(name)] = *resourcev1ac.DeviceCapacity().WithValue(quantity)
+	}
+
+	devices := make([]*resourcev1ac.DeviceApplyConfiguration, set.Count)
+	for i := range devices {
+		device := resourcev1ac.Device().WithName(fmt.Sprintf("device-%d", i))
+		if len(attributes) > 0 {
+			device.WithAttributes(attributes)
+		}
+		if len(capacity) > 0 {
+			device.WithCapacity(capacity)
+		}
+		devices[i] = device
+	}
+
+	return resourcev1ac.ResourceSlice(fmt.Sprintf("%s-%s", node, strings.ReplaceAll(set.Driver, ".", "-"))).
+		WithLabels(map[string]string{"type": "kwok"}).
+		WithSpec(resourcev1ac.ResourceSliceSpec().
+			WithDriver(set.Driver).
+			WithNodeName(node).
+			WithPool(resourcev1ac.ResourcePool().
+				WithName(node).
+				WithGeneration(1).
+				WithResourceSliceCount(1)).
+			WithDevices(devices...)), nil
+}
+
+// deviceClass builds a DeviceClass selecting every device published by the set's driver
+func deviceClass(set *config.DeviceSet) *resourcev1ac.DeviceClassApplyConfiguration {
+	return resourcev1ac.DeviceClass(set.DeviceClass).
+		WithSpec(resourcev1ac.DeviceClassSpec().
+			WithSelectors(resourcev1ac.DeviceSelector().
+				WithCEL(resourcev1ac.CELDeviceSelector().
+					WithExpression(fmt.Sprintf("device.driver == %q", set.Driver)))))
+}
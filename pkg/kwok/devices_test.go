@@ -0,0 +1,77 @@
+package kwok
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	resourcev1 "k8s.io/api/resource/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+)
+
+func TestCreateDevices(t *testing.T) {
+	gpus := config.DeviceSet{
+		Driver:      "gpu.example.com",
+		Count:       4,
+		DeviceClass: "gpu",
+		Attributes:  map[string]string{"model": "a100"},
+		Capacity:    map[string]string{"memory": "80Gi"},
+	}
+	pools := []config.NodePool{
+		{Name: "cpu", Count: 2},
+		{Name: "a100", Count: 2, Devices: []config.DeviceSet{gpus}},
+		{Name: "h100", Count: 1, Devices: []config.DeviceSet{gpus}},
+	}
+	clientset := fake.NewClientset()
+	ctx := context.Background()
+	if err := createDevices(ctx, clientset, pools); err != nil {
+		t.Fatalf("createDevices() error = %v", err)
+	}
+
+	classes, err := clientset.ResourceV1().DeviceClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(classes.Items) != 1 || classes.Items[0].Name != "gpu" {
+		t.Errorf("DeviceClasses = %v, want one named gpu", classes.Items)
+	}
+
+	slices, err := clientset.ResourceV1().ResourceSlices().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, slice := range slices.Items {
+		names = append(names, slice.Name)
+		if slice.Spec.NodeName == nil || slice.Spec.Pool.Name != *slice.Spec.NodeName {
+			t.Errorf("ResourceSlice %s is not its node's own pool", slice.Name)
+		}
+		if len(slice.Spec.Devices) != 4 {
+			t.Errorf("ResourceSlice %s has %d devices, want 4", slice.Name, len(slice.Spec.Devices))
+			continue
+		}
+		memory := slice.Spec.Devices[0].Capacity[resourcev1.QualifiedName("memory")].Value
+		if memory.String() != "80Gi" {
+			t.Errorf("ResourceSlice %s device memory = %s, want 80Gi", slice.Name, memory.String())
+		}
+	}
+	sort.Strings(names)
+	want := "kwok-node-a100-000-gpu-example-com,kwok-node-a100-001-gpu-example-com,kwok-node-h100-000-gpu-example-com"
+	if strings.Join(names, ",") != want {
+		t.Errorf("ResourceSlices = %v, want %s", names, want)
+	}
+}
+
+func TestCreateDevicesInvalidCapacity(t *testing.T) {
+	pools := []config.NodePool{{Name: "gpu", Count: 1, Devices: []config.DeviceSet{
+		{Driver: "gpu.example.com", Count: 1, Capacity: map[string]string{"memory": "lots"}},
+	}}}
+	err := createDevices(context.Background(), fake.NewClientset(), pools)
+	if err == nil || !strings.Contains(err.Error(), "invalid capacity memory 'lots'") {
+		t.Errorf("createDevices() error = %v, expected an invalid capacity", err)
+	}
+}
@@ -0,0 +1,134 @@
+package kwok
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Intervals used when no heartbeat settings are given (the embedded stage and manifest defaults)
+	defaultLeaseDuration  = 40 * time.Second
+	defaultStatusInterval = 10 * time.Minute
+
+	// defaultNodeMonitorGracePeriod is kube-controller-manager's default: nodes whose lease
+	// is not renewed within it are marked NotReady
+	defaultNodeMonitorGracePeriod = 50 * time.Second
+
+	// kwokConfigMapName holds the Kwok controller's KwokConfiguration
+	kwokConfigMapName = "kwok"
+)
+
+// heartbeatPresets are the intervals for each heartbeat mode
+var heartbeatPresets = map[string]Heartbeat{
+	// kubelet defaults: nodeLeaseDurationSeconds=40, nodeStatusReportFrequency=5m
+	config.HeartbeatModeRealistic: {LeaseDuration: 40 * time.Second, StatusInterval: 5 * time.Minute},
+	config.HeartbeatModeReduced:   {LeaseDuration: 10 * time.Minute, StatusInterval: 30 * time.Minute},
+}
+
+// Heartbeat holds resolved node heartbeat intervals
+type Heartbeat struct {
+	// LeaseDuration is the node lease duration; Kwok renews leases every quarter of it
+	LeaseDuration time.Duration
+	// StatusInterval is the delay between node status heartbeats
+	StatusInterval time.Duration
+}
+
+// ResolveHeartbeat applies the mode preset and explicit overrides from settings.
+// Nil settings keep Kwok's default intervals.
+func ResolveHeartbeat(settings *config.HeartbeatSettings) (Heartbeat, error) {
+	hb := Heartbeat{LeaseDuration: defaultLeaseDuration, StatusInterval: defaultStatusInterval}
+	if settings == nil {
+		return hb, nil
+	}
+
+	if preset, ok := heartbeatPresets[settings.Mode]; ok {
+		hb = preset
+	}
+	if settings.LeaseDuration != "" {
+		d, err := time.ParseDuration(settings.LeaseDuration)
+		if err != nil {
+			return hb, fmt.Errorf("invalid leaseDuration: %w", err)
+		}
+		hb.LeaseDuration = d
+	}
+	if settings.StatusInterval != "" {
+		d, err := time.ParseDuration(settings.StatusInterval)
+		if err != nil {
+			return hb, fmt.Errorf("invalid statusInterval: %w", err)
+		}
+		hb.StatusInterval = d
+	}
+	return hb, nil
+}
+
+// NodeMonitorGracePeriod returns the kube-controller-manager node-monitor-grace-period
+// needed so nodes stay Ready between lease renewals, or 0 if the default suffices.
+// Leases longer than the default are given a grace period spanning four renewals.
+func (hb Heartbeat) NodeMonitorGracePeriod() time.Duration {
+	if hb.LeaseDuration <= defaultLeaseDuration {
+		return 0
+	}
+	if hb.LeaseDuration < defaultNodeMonitorGracePeriod {
+		return defaultNodeMonitorGracePeriod
+	}
+	return hb.LeaseDuration
+}
+
+// HeartbeatStages returns the node heartbeat stage with its delay set to the status
+// interval, for use as a stage override. Nothing is returned for default intervals.
+func HeartbeatStages(hb Heartbeat) ([][]byte, error) {
+	if hb.StatusInterval == defaultStatusInterval {
+		return nil, nil
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(nodeHeartbeatStage, &obj.Object); err != nil {
+		return nil, fmt.Errorf("failed to parse embedded heartbeat stage: %w", err)
+	}
+
+	// Keep the embedded stage's ~1.7% jitter so heartbeats do not arrive in lockstep
+	interval := hb.StatusInterval.Milliseconds()
+	delay := map[string]interface{}{
+		"durationMilliseconds":       interval,
+		"jitterDurationMilliseconds": interval + interval/60,
+	}
+	if err := unstructured.SetNestedMap(obj.Object, delay, "spec", "delay"); err != nil {
+		return nil, fmt.Errorf("failed to set heartbeat delay: %w", err)
+	}
+
+	stage, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode heartbeat stage: %w", err)
+	}
+	return [][]byte{stage}, nil
+}
+
+// leaseDurationMutator sets nodeLeaseDurationSeconds in the Kwok controller's configuration
+func leaseDurationMutator(leaseDuration time.Duration) func(*unstructured.Unstructured) {
+	return func(obj *unstructured.Unstructured) {
+		if obj.GetKind() != "ConfigMap" || obj.GetName() != kwokConfigMapName {
+			return
+		}
+		raw, found, err := unstructured.NestedString(obj.Object, "data", "kwok.yaml")
+		if err != nil || !found {
+			return
+		}
+
+		var kwokConfig map[string]interface{}
+		if err := yaml.Unmarshal([]byte(raw), &kwokConfig); err != nil {
+			return
+		}
+		if err := unstructured.SetNestedField(kwokConfig, int64(leaseDuration/time.Second), "options", "nodeLeaseDurationSeconds"); err != nil {
+			return
+		}
+		data, err := yaml.Marshal(kwokConfig)
+		if err != nil {
+			return
+		}
+		_ = unstructured.SetNestedField(obj.Object, string(data), "data", "kwok.yaml")
+	}
+}
@@ -0,0 +1,83 @@
+package kwok
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestResolveHeartbeat(t *testing.T) {
+	tests := []struct {
+		name      string
+		settings  *config.HeartbeatSettings
+		want      Heartbeat
+		wantGrace time.Duration
+	}{
+		{
+			name:     "defaults",
+			settings: nil,
+			want:     Heartbeat{LeaseDuration: 40 * time.Second, StatusInterval: 10 * time.Minute},
+		},
+		{
+			name:     "realistic preset",
+			settings: &config.HeartbeatSettings{Mode: config.HeartbeatModeRealistic},
+			want:     Heartbeat{LeaseDuration: 40 * time.Second, StatusInterval: 5 * time.Minute},
+		},
+		{
+			name:      "reduced preset extends grace period",
+			settings:  &config.HeartbeatSettings{Mode: config.HeartbeatModeReduced},
+			want:      Heartbeat{LeaseDuration: 10 * time.Minute, StatusInterval: 30 * time.Minute},
+			wantGrace: 10 * time.Minute,
+		},
+		{
+			name:     "explicit values override preset",
+			settings: &config.HeartbeatSettings{Mode: config.HeartbeatModeReduced, LeaseDuration: "20s", StatusInterval: "1m"},
+			want:     Heartbeat{LeaseDuration: 20 * time.Second, StatusInterval: time.Minute},
+		},
+		{
+			name:      "slightly longer lease keeps default grace period",
+			settings:  &config.HeartbeatSettings{LeaseDuration: "45s"},
+			want:      Heartbeat{LeaseDuration: 45 * time.Second, StatusInterval: 10 * time.Minute},
+			wantGrace: 50 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveHeartbeat(tt.settings)
+			if err != nil {
+				t.Fatalf("ResolveHeartbeat() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveHeartbeat() = %+v, want %+v", got, tt.want)
+			}
+			if grace := got.NodeMonitorGracePeriod(); grace != tt.wantGrace {
+				t.Errorf("NodeMonitorGracePeriod() = %s, want %s", grace, tt.wantGrace)
+			}
+		})
+	}
+}
+
+func TestLeaseDurationMutator(t *testing.T) {
+	cm := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "kwok", "namespace": "kube-system"},
+		"data": map[string]interface{}{
+			"kwok.yaml": "apiVersion: config.kwok.x-k8s.io/v1alpha1\nkind: KwokConfiguration\noptions:\n  nodeLeaseDurationSeconds: 40\n  nodePort: 10247\n",
+		},
+	}}
+
+	leaseDurationMutator(10 * time.Minute)(cm)
+
+	got, _, _ := unstructured.NestedString(cm.Object, "data", "kwok.yaml")
+	if !strings.Contains(got, "nodeLeaseDurationSeconds: 600") {
+		t.Errorf("lease duration not updated:\n%s", got)
+	}
+	if !strings.Contains(got, "nodePort: 10247") {
+		t.Errorf("other options not preserved:\n%s", got)
+	}
+}
@@ -6,28 +6,45 @@ import (
 	"time"
 
 	"github.com/jhwagner/kueue-bench/pkg/manifest"
-	appsv1 "k8s.io/api/apps/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+	"github.com/jhwagner/kueue-bench/pkg/wait"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/restmapper"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 const (
 	// Default Kwok version
 	DefaultKwokVersion = "v0.7.0"
 
-	// Kwok controller manifest URL
-	kwokManifestURLTemplate = "https://github.com/kubernetes-sigs/kwok/releases/download/%s/kwok.yaml"
+	// controllerReadyTimeout is how long to wait for the Kwok controller to become available
+	controllerReadyTimeout = 2 * time.Minute
 )
 
+// kwokManifestURLTemplate is the Kwok controller manifest URL of a release
+var kwokManifestURLTemplate = "https://github.com/kubernetes-sigs/kwok/releases/download/%s/kwok.yaml"
+
+// InstallOptions configures a Kwok installation
+type InstallOptions struct {
+	Version string
+	// Offline applies the embedded release manifest instead of downloading it
+	Offline bool
+	// ManifestSHA256 is the expected hex SHA-256 of the downloaded manifest (optional)
+	ManifestSHA256 string
+	// StageOverrides replace embedded stages of the same name or add new ones
+	StageOverrides [][]byte
+	// LeaseDuration overrides the node lease duration (optional)
+	LeaseDuration time.Duration
+	// Cache caches the downloaded manifest (default: the cache in the default state directory)
+	Cache *manifest.Cache
+}
+
 // Install installs Kwok into the cluster
-func Install(ctx context.Context, kubeconfigPath string, version string) error {
+func Install(ctx context.Context, kubeconfigPath string, opts InstallOptions, clientOpts ...restconfig.Option) error {
+	version := opts.Version
 	if version == "" {
 		version = DefaultKwokVersion
 	}
@@ -35,9 +52,9 @@ func Install(ctx context.Context, kubeconfigPath string, version string) error {
 	fmt.Printf("Installing Kwok %s...\n", version)
 
 	// Create Kubernetes clients
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	config, err := restconfig.ForKubeconfig(kubeconfigPath, 0, clientOpts...)
 	if err != nil {
-		return fmt.Errorf("failed to load kubeconfig: %w", err)
+		return err
 	}
 
 	dynamicClient, err := dynamic.NewForConfig(config)
@@ -58,13 +75,20 @@ func Install(ctx context.Context, kubeconfigPath string, version string) error {
 	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
 
 	// Apply Kwok controller manifest with hostNetwork patch
-	kwokURL := fmt.Sprintf(kwokManifestURLTemplate, version)
+	controller, err := controllerManifest(ctx, opts.Cache, version, opts.Offline, opts.ManifestSHA256)
+	if err != nil {
+		return fmt.Errorf("failed to get Kwok controller manifest: %w", err)
+	}
 	hostNetworkMutator := func(obj *unstructured.Unstructured) {
 		if obj.GetKind() == "Deployment" && obj.GetName() == "kwok-controller" {
 			_ = unstructured.SetNestedField(obj.Object, true, "spec", "template", "spec", "hostNetwork")
 		}
 	}
-	if err := manifest.ApplyURL(ctx, dynamicClient, mapper, kwokURL, hostNetworkMutator); err != nil {
+	mutators := []func(*unstructured.Unstructured){hostNetworkMutator}
+	if opts.LeaseDuration > 0 && opts.LeaseDuration != defaultLeaseDuration {
+		mutators = append(mutators, leaseDurationMutator(opts.LeaseDuration))
+	}
+	if err := manifest.ApplyBytes(ctx, dynamicClient, mapper, controller, mutators...); err != nil {
 		return fmt.Errorf("failed to install Kwok controller: %w", err)
 	}
 
@@ -72,35 +96,16 @@ func Install(ctx context.Context, kubeconfigPath string, version string) error {
 	mapper.Reset()
 
 	// Apply embedded Kwok stages for node lifecycle and pod completion
-	if err := installStages(ctx, dynamicClient, mapper); err != nil {
+	if err := installStages(ctx, dynamicClient, mapper, opts.StageOverrides); err != nil {
 		return fmt.Errorf("failed to install Kwok stages: %w", err)
 	}
 
 	// Wait for Kwok controller to be ready
 	fmt.Println("Waiting for Kwok controller to be ready...")
-	if err := waitForDeployment(ctx, clientset, "kube-system", "kwok-controller"); err != nil {
+	if err := wait.DeploymentAvailable(ctx, clientset, "kube-system", "kwok-controller", controllerReadyTimeout); err != nil {
 		return fmt.Errorf("kwok controller failed to become ready: %w", err)
 	}
 
 	fmt.Println("✓ Kwok installed successfully")
 	return nil
 }
-
-// waitForDeployment waits for a deployment to be ready
-func waitForDeployment(ctx context.Context, clientset kubernetes.Interface, namespace, name string) error {
-	return wait.PollUntilContextTimeout(ctx, 2*time.Second, 120*time.Second, true, func(ctx context.Context) (bool, error) {
-		deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
-		if err != nil {
-			return false, nil // Keep waiting
-		}
-
-		// Check if deployment is available
-		for _, condition := range deployment.Status.Conditions {
-			if condition.Type == appsv1.DeploymentAvailable && condition.Status == "True" {
-				return true, nil
-			}
-		}
-
-		return false, nil
-	})
-}
@@ -0,0 +1,102 @@
+package kwok
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// PodReadyStages generates pod-ready stages that delay pods becoming Running by the
+// configured startup latency. Each label override gets its own stage whose selector
+// excludes the labels of earlier overrides, and the default "pod-ready" stage excludes
+// all of them, so exactly one stage matches any pod.
+func PodReadyStages(latency *config.PodStartupLatency) ([][]byte, error) {
+	if latency == nil {
+		return nil, nil
+	}
+
+	var stages [][]byte
+	var excluded []interface{}
+	for i, o := range latency.Overrides {
+		key, value, _ := strings.Cut(o.Label, "=")
+		match := labelRequirement(key, "In", value)
+		stage, err := podReadyStageWithDelay(fmt.Sprintf("pod-ready-%d", i), o.Min, o.Max, append(excluded[:len(excluded):len(excluded)], match))
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate pod-ready stage for '%s': %w", o.Label, err)
+		}
+		stages = append(stages, stage)
+		excluded = append(excluded, labelRequirement(key, "NotIn", value))
+	}
+
+	stage, err := podReadyStageWithDelay("pod-ready", latency.Min, latency.Max, excluded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate pod-ready stage: %w", err)
+	}
+	return append(stages, stage), nil
+}
+
+// podReadyStageWithDelay renders the embedded pod-ready stage under a new name with a
+// uniform delay between minDelay and maxDelay and additional selector requirements.
+func podReadyStageWithDelay(name, minDelay, maxDelay string, requirements []interface{}) ([]byte, error) {
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(podReadyStage, &obj.Object); err != nil {
+		return nil, fmt.Errorf("failed to parse embedded pod-ready stage: %w", err)
+	}
+	obj.SetName(name)
+
+	lo, hi, err := delayRange(minDelay, maxDelay)
+	if err != nil {
+		return nil, err
+	}
+	if hi > 0 {
+		delay := map[string]interface{}{"durationMilliseconds": lo.Milliseconds()}
+		if hi > lo {
+			// KWOK picks a delay uniformly between durationMilliseconds and jitterDurationMilliseconds
+			delay["jitterDurationMilliseconds"] = hi.Milliseconds()
+		}
+		if err := unstructured.SetNestedMap(obj.Object, delay, "spec", "delay"); err != nil {
+			return nil, fmt.Errorf("failed to set stage delay: %w", err)
+		}
+	}
+
+	if len(requirements) > 0 {
+		exprs, _, err := unstructured.NestedSlice(obj.Object, "spec", "selector", "matchExpressions")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stage selector: %w", err)
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, append(exprs, requirements...), "spec", "selector", "matchExpressions"); err != nil {
+			return nil, fmt.Errorf("failed to set stage selector: %w", err)
+		}
+	}
+
+	return yaml.Marshal(obj.Object)
+}
+
+// delayRange parses a min/max delay pair. An empty max defaults to min.
+func delayRange(minDelay, maxDelay string) (lo, hi time.Duration, err error) {
+	if minDelay != "" {
+		if lo, err = time.ParseDuration(minDelay); err != nil {
+			return 0, 0, fmt.Errorf("invalid min delay %q: %w", minDelay, err)
+		}
+	}
+	hi = lo
+	if maxDelay != "" {
+		if hi, err = time.ParseDuration(maxDelay); err != nil {
+			return 0, 0, fmt.Errorf("invalid max delay %q: %w", maxDelay, err)
+		}
+	}
+	return lo, hi, nil
+}
+
+// labelRequirement returns a stage selector requirement on a pod label
+func labelRequirement(key, operator, value string) interface{} {
+	return map[string]interface{}{
+		"key":      fmt.Sprintf(".metadata.labels[%q]", key),
+		"operator": operator,
+		"values":   []interface{}{value},
+	}
+}
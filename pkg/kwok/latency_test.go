@@ -0,0 +1,129 @@
+package kwok
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// readyStage is a generated pod-ready stage's name, delay and added label requirements
+type readyStage struct {
+	name   string
+	delay  string
+	labels []string
+}
+
+func TestPodReadyStages(t *testing.T) {
+	tests := []struct {
+		name        string
+		latency     *config.PodStartupLatency
+		want        []readyStage
+		errContains string
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:    "fixed delay",
+			latency: &config.PodStartupLatency{Min: "5s"},
+			want:    []readyStage{{name: "pod-ready", delay: "5000"}},
+		},
+		{
+			name:    "uniform delay",
+			latency: &config.PodStartupLatency{Min: "5s", Max: "30s"},
+			want:    []readyStage{{name: "pod-ready", delay: "5000-30000"}},
+		},
+		{
+			name:    "no delay",
+			latency: &config.PodStartupLatency{},
+			want:    []readyStage{{name: "pod-ready"}},
+		},
+		{
+			name: "label overrides",
+			latency: &config.PodStartupLatency{
+				Min: "5s",
+				Overrides: []config.PodStartupOverride{
+					{Label: "accelerator=gpu", Min: "30s", Max: "1m"},
+					{Label: "tier=batch", Min: "10s"},
+				},
+			},
+			want: []readyStage{
+				{name: "pod-ready-0", delay: "30000-60000", labels: []string{"accelerator In gpu"}},
+				{name: "pod-ready-1", delay: "10000", labels: []string{"accelerator NotIn gpu", "tier In batch"}},
+				{name: "pod-ready", delay: "5000", labels: []string{"accelerator NotIn gpu", "tier NotIn batch"}},
+			},
+		},
+		{
+			name:        "invalid delay",
+			latency:     &config.PodStartupLatency{Min: "soon"},
+			errContains: `invalid min delay "soon"`,
+		},
+		{
+			name: "invalid override delay",
+			latency: &config.PodStartupLatency{
+				Overrides: []config.PodStartupOverride{{Label: "accelerator=gpu", Max: "later"}},
+			},
+			errContains: "for 'accelerator=gpu': invalid max delay",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stages, err := PodReadyStages(tt.latency)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("PodReadyStages() error = %v, expected to contain %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PodReadyStages() error = %v", err)
+			}
+			if len(stages) != len(tt.want) {
+				t.Fatalf("PodReadyStages() returned %d stages, want %d", len(stages), len(tt.want))
+			}
+			for i, data := range stages {
+				if got := decodeReadyStage(t, data); !reflect.DeepEqual(got, tt.want[i]) {
+					t.Errorf("stage %d = %+v, want %+v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// decodeReadyStage reads the name, delay and label requirements of a generated stage
+func decodeReadyStage(t *testing.T, data []byte) readyStage {
+	t.Helper()
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(data, &obj.Object); err != nil {
+		t.Fatalf("failed to parse stage: %v", err)
+	}
+	if obj.GetKind() != "Stage" {
+		t.Errorf("stage kind = %s, want Stage", obj.GetKind())
+	}
+	stage := readyStage{name: obj.GetName()}
+	// Stage delays decode as float64 milliseconds
+	if lo, found, _ := unstructured.NestedFloat64(obj.Object, "spec", "delay", "durationMilliseconds"); found {
+		stage.delay = fmt.Sprint(lo)
+		if hi, found, _ := unstructured.NestedFloat64(obj.Object, "spec", "delay", "jitterDurationMilliseconds"); found {
+			stage.delay += "-" + fmt.Sprint(hi)
+		}
+	}
+	exprs, _, _ := unstructured.NestedSlice(obj.Object, "spec", "selector", "matchExpressions")
+	for _, e := range exprs {
+		expr := e.(map[string]interface{})
+		key := expr["key"].(string)
+		if !strings.HasPrefix(key, ".metadata.labels[") {
+			continue
+		}
+		label := strings.Trim(strings.TrimSuffix(strings.TrimPrefix(key, ".metadata.labels["), "]"), `"`)
+		values := expr["values"].([]interface{})
+		stage.labels = append(stage.labels, label+" "+expr["operator"].(string)+" "+values[0].(string))
+	}
+	return stage
+}
@@ -0,0 +1,56 @@
+package kwok
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/jhwagner/kueue-bench/pkg/manifest"
+)
+
+// Release manifests for supported Kwok versions, built from the release's kustomize/kwok
+// configuration, so clusters can be created without internet access.
+//
+//go:embed manifests/kwok-*.yaml
+var embeddedManifests embed.FS
+
+// controllerManifest returns the Kwok controller manifest for version: the embedded
+// copy when offline, otherwise the release download through cache (the default cache if
+// nil), verified against sha256Hex if set.
+func controllerManifest(ctx context.Context, cache *manifest.Cache, version string, offline bool, sha256Hex string) ([]byte, error) {
+	if offline {
+		data, err := embeddedManifests.ReadFile(fmt.Sprintf("manifests/kwok-%s.yaml", version))
+		if err != nil {
+			return nil, fmt.Errorf("no embedded manifest for Kwok %s (embedded versions: %s)",
+				version, strings.Join(EmbeddedVersions(), ", "))
+		}
+		return data, nil
+	}
+
+	src := manifest.Source{URL: fmt.Sprintf(kwokManifestURLTemplate, version), SHA256: sha256Hex}
+	var data []byte
+	var err error
+	if cache != nil {
+		data, err = cache.Fetch(ctx, src)
+	} else {
+		data, err = manifest.FetchCached(ctx, src)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Kwok %s manifest: %w", version, err)
+	}
+	return data, nil
+}
+
+// EmbeddedVersions returns the Kwok versions whose manifests are embedded for offline installs
+func EmbeddedVersions() []string {
+	files, _ := fs.Glob(embeddedManifests, "manifests/kwok-*.yaml")
+	versions := make([]string, 0, len(files))
+	for _, f := range files {
+		versions = append(versions, strings.TrimSuffix(strings.TrimPrefix(f, "manifests/kwok-"), ".yaml"))
+	}
+	sort.Strings(versions)
+	return versions
+}
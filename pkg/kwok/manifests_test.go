@@ -0,0 +1,75 @@
+package kwok
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jhwagner/kueue-bench/pkg/manifest"
+)
+
+func TestControllerManifestOffline(t *testing.T) {
+	versions := EmbeddedVersions()
+	if len(versions) == 0 {
+		t.Fatal("no embedded Kwok manifests")
+	}
+	for _, version := range versions {
+		data, err := controllerManifest(context.Background(), nil, version, true, "")
+		if err != nil {
+			t.Errorf("controllerManifest(%s, offline) error = %v", version, err)
+			continue
+		}
+		if !strings.Contains(string(data), "kwok-controller") {
+			t.Errorf("embedded manifest of Kwok %s has no kwok-controller", version)
+		}
+	}
+
+	_, err := controllerManifest(context.Background(), nil, "v0.0.1", true, "")
+	if err == nil || !strings.Contains(err.Error(), "no embedded manifest for Kwok v0.0.1") {
+		t.Errorf("controllerManifest(v0.0.1, offline) error = %v, expected a missing embedded manifest", err)
+	}
+}
+
+func TestControllerManifestChecksum(t *testing.T) {
+	body := "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: kube-system\n"
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	defer func(template string) { kwokManifestURLTemplate = template }(kwokManifestURLTemplate)
+	kwokManifestURLTemplate = server.URL + "/%s/kwok.yaml"
+
+	sum := sha256.Sum256([]byte(body))
+	digest := hex.EncodeToString(sum[:])
+	cache := &manifest.Cache{Dir: t.TempDir()}
+	ctx := context.Background()
+
+	if _, err := controllerManifest(ctx, cache, "v0.7.0", false, strings.Repeat("0", 64)); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("controllerManifest() with wrong checksum error = %v, expected checksum mismatch", err)
+	}
+	data, err := controllerManifest(ctx, cache, "v0.7.0", false, digest)
+	if err != nil {
+		t.Fatalf("controllerManifest() error = %v", err)
+	}
+	if string(data) != body {
+		t.Errorf("controllerManifest() = %q, want %q", data, body)
+	}
+
+	// The verified download is cached: an offline cache lookup of the mismatched digest
+	// still misses, and a second pinned fetch does not download again
+	if _, err := cache.Fetch(ctx, manifest.Source{URL: server.URL + "/v0.7.0/kwok.yaml", SHA256: strings.Repeat("0", 64), Offline: true}); err == nil || !strings.Contains(err.Error(), "not in the download cache") {
+		t.Errorf("offline Fetch() of a mismatched digest error = %v, expected a cache miss", err)
+	}
+	if _, err := controllerManifest(ctx, cache, "v0.7.0", false, digest); err != nil {
+		t.Fatalf("controllerManifest() error = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("server got %d requests, want 2 (the mismatch and the first verified download)", requests)
+	}
+}
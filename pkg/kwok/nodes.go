@@ -3,53 +3,276 @@ package kwok
 import (
 	"context"
 	_ "embed"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"strconv"
+	"sync"
+	"sync/atomic"
 
+	"github.com/jhwagner/kueue-bench/pkg/bulk"
 	"github.com/jhwagner/kueue-bench/pkg/config"
-	"sigs.k8s.io/kwok/pkg/kwokctl/scale"
-	kwokClient "sigs.k8s.io/kwok/pkg/utils/client"
+	"github.com/jhwagner/kueue-bench/pkg/ownership"
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/kwok/pkg/utils/gotpl"
 )
 
 //go:embed templates/node.gotpl
 var nodeTemplate string
 
-// CreateNodes creates simulated Kwok nodes based on node pool configuration. Uses Kwok's internal scale.Scale function.
-func CreateNodes(ctx context.Context, kubeconfigPath string, nodePools []config.NodePool) error {
-	// Kwok's Scale expects a kwok clientset
-	clientset, err := kwokClient.NewClientset("", kubeconfigPath)
+const (
+	// fieldManager identifies kueue-bench as the owner of applied node fields
+	fieldManager = "kueue-bench"
+
+	// RackLabel is the node label identifying a simulated rack
+	RackLabel = "topology.kueue-bench.io/rack"
+
+	// PoolLabel is the node label holding the name of the node pool a node belongs to
+	PoolLabel = "kueue-bench.io/pool"
+
+	// NotReadyLabel makes the node-not-ready stage mark a node NotReady while present
+	NotReadyLabel = "node-not-ready.stage.kwok.x-k8s.io"
+
+	// poolLabelKey records the pool a node was created for (same key kwokctl scale uses)
+	poolLabelKey = "kwok.x-k8s.io/kwokctl-scale"
+
+	// nodeApplyWorkers is the number of concurrent apply requests
+	nodeApplyWorkers = 16
+
+	// nodeProgressChunks is how many progress lines are printed per pool
+	nodeProgressChunks = 10
+
+	// Bounds and starting point for the adaptive apply rate, in requests per second
+	initialApplyQPS = 200
+	minApplyQPS     = 5
+	maxApplyQPS     = 1000
+
+	// nodeDeleteQPS bounds node deletes when they cannot be done in bulk
+	nodeDeleteQPS = 200
+)
+
+var nodeGVR = schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+
+// DeleteNodes deletes the simulated nodes kueue-bench created for the topology in a pool,
+// or in every pool when pool is empty, and returns how many were deleted. Kwok nodes created
+// before kueue-bench labeled them as its own are deleted too. Nodes are deleted in bulk
+// rather than one request per node, so clearing very large pools stays fast.
+func DeleteNodes(ctx context.Context, kubeconfigPath, topologyName, pool string, opts ...restconfig.Option) (int, error) {
+	restConfig, err := restconfig.ForKubeconfig(kubeconfigPath, nodeDeleteQPS, opts...)
+	if err != nil {
+		return 0, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
 	if err != nil {
-		return fmt.Errorf("failed to create kwok clientset: %w", err)
+		return 0, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	deleted := 0
+	for _, owner := range []string{ownership.Selector(topologyName), ownership.LegacySelector()} {
+		selector := kwokNodeSelector + "," + owner
+		if pool != "" {
+			selector += "," + PoolLabel + "=" + pool
+		}
+		n, err := bulk.Delete(ctx, dynamicClient, nodeGVR, selector)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// applyPool server-side applies the nodes of a pool at the given indexes, reporting
+// progress in chunks. Nodes are applied by a pool of workers sharing an adaptive rate limit
+// that backs off when the apiserver returns 429s, which keeps very large pools from
+// overwhelming the kind apiserver. Nodes are labeled as kueue-bench's, for the topology named.
+func applyPool(ctx context.Context, nodes dynamic.ResourceInterface, limiter *adaptiveLimiter, topologyName string, pool *config.NodePool, indexes []int) error {
+	if len(indexes) == 0 {
+		return nil
 	}
 
-	for _, pool := range nodePools {
-		// Build template parameters
-		params := buildTemplateParameters(&pool)
+	params, err := buildTemplateParameters(pool, topologyName)
+	if err != nil {
+		return fmt.Errorf("pool '%s': %w", pool.Name, err)
+	}
+	base, err := renderNode(poolPrefix(pool.Name), pool.Name, params)
+	if err != nil {
+		return err
+	}
+	namer, err := config.NewNodeNamer(pool)
+	if err != nil {
+		return fmt.Errorf("pool '%s': %w", pool.Name, err)
+	}
 
-		fmt.Printf("Creating %d nodes in pool %s...\n", pool.Count, pool.Name)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-		err := scale.Scale(ctx, clientset, scale.Config{
-			Template:     nodeTemplate,
-			Parameters:   params,
-			Name:         fmt.Sprintf("kwok-node-%s", pool.Name),
-			Replicas:     pool.Count,
-			SerialLength: 3,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to scale pool %s: %w", pool.Name, err)
+	queue := make(chan int)
+	go func() {
+		defer close(queue)
+		for _, i := range indexes {
+			select {
+			case queue <- i:
+			case <-ctx.Done():
+				return
+			}
 		}
+	}()
+
+	total := len(indexes)
+	chunk := max(total/nodeProgressChunks, 1)
+	var done atomic.Int64
+	var once sync.Once
+	var firstErr error
+	var wg sync.WaitGroup
+
+	for w := 0; w < min(nodeApplyWorkers, total); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				node, err := poolNode(base, pool, namer, i)
+				if err == nil {
+					err = applyNode(ctx, nodes, limiter, node)
+				}
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					return
+				}
+
+				n := done.Add(1)
+				if n%int64(chunk) == 0 && n < int64(total) {
+					fmt.Printf("  %d/%d nodes in pool %s\n", n, total, pool.Name)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// renderNode renders the node template shared by every node of a pool
+func renderNode(prefix, pool string, params map[string]interface{}) (*unstructured.Unstructured, error) {
+	renderer := gotpl.NewRenderer(gotpl.FuncMap{})
+	data, err := renderer.ToJSON(nodeTemplate, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render node template: %w", err)
+	}
+
+	node := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &node.Object); err != nil {
+		return nil, fmt.Errorf("failed to decode node template: %w", err)
+	}
+
+	labels := node.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[poolLabelKey] = prefix
+	labels[PoolLabel] = pool
+	node.SetLabels(labels)
+
+	return node, nil
+}
+
+// poolNode returns the index-th node of a pool: a copy of base with its name,
+// topology labels and templated labels set
+func poolNode(base *unstructured.Unstructured, pool *config.NodePool, namer *config.NodeNamer, index int) (*unstructured.Unstructured, error) {
+	name, err := namer.Name(index)
+	if err != nil {
+		return nil, err
+	}
+	templated, err := namer.Labels(index)
+	if err != nil {
+		return nil, err
+	}
+
+	node := base.DeepCopy()
+	node.SetName(name)
+
+	labels := node.GetLabels()
+	for k, v := range templated {
+		labels[k] = v
+	}
+	for k, v := range topologyLabels(pool, name, index) {
+		labels[k] = v
+	}
+	node.SetLabels(labels)
+
+	return node, nil
+}
+
+// topologyLabels returns the hostname and well-known topology labels for the index-th node of a pool
+func topologyLabels(pool *config.NodePool, name string, index int) map[string]string {
+	labels := map[string]string{
+		corev1.LabelHostname: name,
+	}
+
+	data := pool.TemplateData(index)
+	if data.Region != "" {
+		labels[corev1.LabelTopologyRegion] = data.Region
+	}
+	if data.Zone != "" {
+		labels[corev1.LabelTopologyZone] = data.Zone
+	}
+	if data.Rack != "" {
+		labels[RackLabel] = data.Rack
+	}
+
+	return labels
+}
+
+// applyNode server-side applies a node and its status (capacity lives in status, which
+// is a separate subresource). Both go through the shared limiter, which retries conflicts
+// and 429s.
+func applyNode(ctx context.Context, nodes dynamic.ResourceInterface, limiter *adaptiveLimiter, node *unstructured.Unstructured) error {
+	opts := metav1.ApplyOptions{FieldManager: fieldManager, Force: true}
+
+	if err := limiter.Do(ctx, func() error {
+		_, err := nodes.Apply(ctx, node.GetName(), node, opts)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to apply node %s: %w", node.GetName(), err)
+	}
+
+	if err := limiter.Do(ctx, func() error {
+		_, err := nodes.ApplyStatus(ctx, node.GetName(), node, opts)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to apply status for node %s: %w", node.GetName(), err)
 	}
 
-	fmt.Printf("✓ Nodes created successfully\n")
 	return nil
 }
 
 // buildTemplateParameters converts NodePool config to template parameters
-func buildTemplateParameters(pool *config.NodePool) map[string]interface{} {
+func buildTemplateParameters(pool *config.NodePool, topologyName string) (map[string]interface{}, error) {
 	params := make(map[string]interface{})
 
-	// Add labels
-	if len(pool.Labels) > 0 {
-		params["Labels"] = pool.Labels
+	// Add labels (templated values are set per node), then the ownership labels
+	labels := make(map[string]string, len(pool.Labels))
+	for k, v := range pool.Labels {
+		if !config.IsNodeTemplate(v) {
+			labels[k] = v
+		}
+	}
+	maps.Copy(labels, ownership.Labels(topologyName, ""))
+	params["Labels"] = labels
+
+	// Record the node cost for run cost reports
+	if pool.CostPerHour > 0 {
+		params["Annotations"] = map[string]string{
+			config.NodeCostAnnotation: strconv.FormatFloat(pool.CostPerHour, 'f', -1, 64),
+		}
 	}
 
 	// Add taints
@@ -57,18 +280,21 @@ func buildTemplateParameters(pool *config.NodePool) map[string]interface{} {
 		params["Taints"] = pool.Taints
 	}
 
-	// Add resources with default pods capacity
-	resources := make(map[string]string)
-	for k, v := range pool.Resources {
-		resources[k] = v
-	}
-
-	// Add default pods capacity if not specified
-	if _, exists := resources["pods"]; !exists {
-		resources["pods"] = "110" // Standard Kubernetes default
+	// Capacity includes the default pods capacity; allocatable is capacity minus any
+	// system reservation
+	resources := pool.Capacity()
+	allocatable, err := pool.Allocatable()
+	if err != nil {
+		return nil, err
 	}
 
 	params["Resources"] = resources
+	params["Allocatable"] = allocatable
+
+	return params, nil
+}
 
-	return params
+// poolPrefix returns the name prefix of a pool's nodes
+func poolPrefix(pool string) string {
+	return fmt.Sprintf("kwok-node-%s", pool)
 }
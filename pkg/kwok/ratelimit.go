@@ -0,0 +1,147 @@
+package kwok
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+)
+
+// adaptiveLimiter is a token bucket whose rate adapts to apiserver pressure:
+// it halves on every 429 and grows additively after a run of successes (AIMD).
+type adaptiveLimiter struct {
+	mu        sync.Mutex
+	limiter   *rate.Limiter
+	qps       float64
+	minQPS    float64
+	maxQPS    float64
+	successes int
+	pauseTill time.Time
+}
+
+// successesPerIncrease is how many successful requests raise the rate by one step
+const successesPerIncrease = 100
+
+// Retries of a request sent through Do back off exponentially from retryBaseDelay up to
+// retryMaxDelay, for at most retryAttempts attempts: about two minutes, so a request
+// outlasts a burst of 429s while the limiter slows down
+const (
+	retryAttempts  = 20
+	retryBaseDelay = 100 * time.Millisecond
+	retryMaxDelay  = 10 * time.Second
+)
+
+func newAdaptiveLimiter(qps, minQPS, maxQPS float64) *adaptiveLimiter {
+	return &adaptiveLimiter{
+		limiter: rate.NewLimiter(rate.Limit(qps), burst(qps)),
+		qps:     qps,
+		minQPS:  minQPS,
+		maxQPS:  maxQPS,
+	}
+}
+
+// applyLimiter returns the adaptive limiter for node applies through restConfig. A client
+// override below maxApplyQPS lowers the limiter's ceiling, since client-go would throttle
+// at it anyway.
+func applyLimiter(restConfig *rest.Config) *adaptiveLimiter {
+	maxQPS := min(float64(restConfig.QPS), maxApplyQPS)
+	return newAdaptiveLimiter(min(initialApplyQPS, maxQPS), min(minApplyQPS, maxQPS), maxQPS)
+}
+
+// Wait blocks until a request may be sent, honoring any server-requested pause
+func (l *adaptiveLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	pause := time.Until(l.pauseTill)
+	l.mu.Unlock()
+
+	if pause > 0 {
+		timer := time.NewTimer(pause)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return l.limiter.Wait(ctx)
+}
+
+// Do sends a request through the limiter. 429s slow the limiter down and pause it for any
+// Retry-After delay; they and conflicts are retried with capped exponential backoff.
+// Other errors are returned immediately.
+func (l *adaptiveLimiter) Do(ctx context.Context, request func() error) error {
+	delay := retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		if err := l.Wait(ctx); err != nil {
+			return err
+		}
+		err := request()
+		switch {
+		case err == nil:
+			l.Succeeded()
+			return nil
+		case apierrors.IsTooManyRequests(err):
+			l.Throttled(err)
+		case !apierrors.IsConflict(err):
+			return err
+		}
+		if attempt == retryAttempts {
+			return err
+		}
+
+		timer := time.NewTimer(wait.Jitter(delay, 0.1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		delay = min(2*delay, retryMaxDelay)
+	}
+}
+
+// Succeeded records a successful request, slowly raising the rate
+func (l *adaptiveLimiter) Succeeded() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.successes++
+	if l.successes < successesPerIncrease || l.qps >= l.maxQPS {
+		return
+	}
+	l.successes = 0
+	l.setQPS(l.qps + l.minQPS)
+}
+
+// Throttled records a 429 response, halving the rate and pausing for any Retry-After delay
+func (l *adaptiveLimiter) Throttled(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.successes = 0
+	l.setQPS(l.qps / 2)
+
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok {
+		if until := time.Now().Add(time.Duration(seconds) * time.Second); until.After(l.pauseTill) {
+			l.pauseTill = until
+		}
+	}
+}
+
+// setQPS clamps and applies a new rate; callers must hold mu
+func (l *adaptiveLimiter) setQPS(qps float64) {
+	l.qps = min(max(qps, l.minQPS), l.maxQPS)
+	l.limiter.SetLimit(rate.Limit(l.qps))
+	l.limiter.SetBurst(burst(l.qps))
+}
+
+// burst returns the limiter burst for a rate: one second of requests, but at least one,
+// since a zero burst makes Wait fail for every request
+func burst(qps float64) int {
+	return max(1, int(qps))
+}
@@ -0,0 +1,160 @@
+package kwok
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+)
+
+func TestAdaptiveLimiterRate(t *testing.T) {
+	l := newAdaptiveLimiter(100, 5, 200)
+
+	l.Throttled(apierrors.NewTooManyRequests("slow down", 0))
+	if l.qps != 50 {
+		t.Errorf("qps after a 429 = %v, want 50", l.qps)
+	}
+	for range 10 {
+		l.Throttled(apierrors.NewTooManyRequests("slow down", 0))
+	}
+	if l.qps != 5 {
+		t.Errorf("qps after repeated 429s = %v, want the minimum 5", l.qps)
+	}
+
+	for range successesPerIncrease - 1 {
+		l.Succeeded()
+	}
+	if l.qps != 5 {
+		t.Errorf("qps before %d successes = %v, want 5", successesPerIncrease, l.qps)
+	}
+	l.Succeeded()
+	if l.qps != 10 {
+		t.Errorf("qps after %d successes = %v, want 10", successesPerIncrease, l.qps)
+	}
+
+	for range 100 * successesPerIncrease {
+		l.Succeeded()
+	}
+	if l.qps != 200 {
+		t.Errorf("qps after many successes = %v, want the maximum 200", l.qps)
+	}
+	if got := l.limiter.Burst(); got != 200 {
+		t.Errorf("burst = %d, want it to follow qps", got)
+	}
+}
+
+func TestAdaptiveLimiterBelowOneQPS(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// e.g. spec.client.qps: 0.5, which lowers the limiter's ceiling below one request a second
+	l := applyLimiter(&rest.Config{QPS: 0.5})
+	if err := l.Wait(ctx); err != nil {
+		t.Fatalf("Wait() at %v qps error = %v", l.qps, err)
+	}
+
+	// AIMD backing off below one request a second
+	l = newAdaptiveLimiter(2, 0.1, 2)
+	for range 3 {
+		l.Throttled(apierrors.NewTooManyRequests("slow down", 0))
+	}
+	if l.qps >= 1 {
+		t.Fatalf("qps after 429s = %v, want below 1", l.qps)
+	}
+	if err := l.Wait(ctx); err != nil {
+		t.Errorf("Wait() at %v qps error = %v", l.qps, err)
+	}
+}
+
+func TestApplyLimiter(t *testing.T) {
+	tests := []struct {
+		name                      string
+		clientQPS                 float32
+		wantQPS, wantMin, wantMax float64
+	}{
+		{name: "default client", clientQPS: 2000, wantQPS: initialApplyQPS, wantMin: minApplyQPS, wantMax: maxApplyQPS},
+		{name: "client override lowers the ceiling", clientQPS: 50, wantQPS: 50, wantMin: minApplyQPS, wantMax: 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := applyLimiter(&rest.Config{QPS: tt.clientQPS})
+			if l.qps != tt.wantQPS || l.minQPS != tt.wantMin || l.maxQPS != tt.wantMax {
+				t.Errorf("applyLimiter() qps %v in [%v, %v], want %v in [%v, %v]",
+					l.qps, l.minQPS, l.maxQPS, tt.wantQPS, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestAdaptiveLimiterRetryAfter(t *testing.T) {
+	l := newAdaptiveLimiter(100, 5, 200)
+	l.Throttled(apierrors.NewTooManyRequests("slow down", 30))
+	if pause := time.Until(l.pauseTill); pause < 29*time.Second || pause > 30*time.Second {
+		t.Fatalf("pause after Retry-After: 30 = %s, want 30s", pause)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() during a Retry-After pause error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestAdaptiveLimiterDo(t *testing.T) {
+	conflict := apierrors.NewConflict(schema.GroupResource{Resource: "nodes"}, "node-1", errors.New("changed"))
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   bool
+		wantQPS   float64
+	}{
+		{name: "success", wantCalls: 1, wantQPS: 100},
+		{name: "conflicts retried", errs: []error{conflict, conflict}, wantCalls: 3, wantQPS: 100},
+		{
+			name:      "429s retried and slow the limiter",
+			errs:      []error{apierrors.NewTooManyRequests("slow down", 0), apierrors.NewTooManyRequests("slow down", 0)},
+			wantCalls: 3,
+			wantQPS:   25,
+		},
+		{name: "other errors not retried", errs: []error{errors.New("forbidden")}, wantCalls: 1, wantErr: true, wantQPS: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newAdaptiveLimiter(100, 5, 200)
+			calls := 0
+			err := l.Do(context.Background(), func() error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Do() made %d calls, want %d", calls, tt.wantCalls)
+			}
+			if l.qps != tt.wantQPS {
+				t.Errorf("qps after Do() = %v, want %v", l.qps, tt.wantQPS)
+			}
+		})
+	}
+}
+
+func TestAdaptiveLimiterDoCancelled(t *testing.T) {
+	l := newAdaptiveLimiter(100, 5, 200)
+	ctx, cancel := context.WithCancel(context.Background())
+	err := l.Do(ctx, func() error {
+		cancel()
+		return apierrors.NewTooManyRequests("slow down", 0)
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Do() error = %v, want context canceled", err)
+	}
+}
@@ -0,0 +1,137 @@
+package kwok
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+)
+
+const (
+	// kwokNodeSelector matches the nodes created from the node template
+	kwokNodeSelector = "type=kwok"
+
+	// nodeReadyTimeout bounds how long to wait for KWOK to mark all nodes Ready
+	nodeReadyTimeout = 5 * time.Minute
+
+	// maxStuckNodesReported limits the stuck node names listed in a timeout error
+	maxStuckNodesReported = 10
+)
+
+// WaitForNodesReady watches the KWOK nodes until every node in the pools is Ready and
+// schedulable. The node template carries no conditions, so a Ready condition means the
+// KWOK controller has picked the node up. On timeout the error summarizes stuck nodes.
+func WaitForNodesReady(ctx context.Context, kubeconfigPath string, nodePools []config.NodePool, opts ...restconfig.Option) error {
+	expected := 0
+	for _, pool := range nodePools {
+		expected += pool.Count
+	}
+	if expected == 0 {
+		return nil
+	}
+
+	restConfig, err := restconfig.ForKubeconfig(kubeconfigPath, 0, opts...)
+	if err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	fmt.Printf("Waiting for %d nodes to become ready...\n", expected)
+
+	ctx, cancel := context.WithTimeout(ctx, nodeReadyTimeout)
+	defer cancel()
+
+	lw := cache.NewFilteredListWatchFromClient(clientset.CoreV1().RESTClient(), "nodes", metav1.NamespaceAll,
+		func(options *metav1.ListOptions) {
+			options.LabelSelector = kwokNodeSelector
+		})
+
+	ready := make(map[string]bool, expected)
+	_, err = watchtools.UntilWithSync(ctx, lw, &corev1.Node{}, nil, func(event watch.Event) (bool, error) {
+		node, ok := event.Object.(*corev1.Node)
+		if !ok {
+			return false, nil
+		}
+		if event.Type == watch.Deleted {
+			delete(ready, node.Name)
+		} else {
+			ready[node.Name] = isNodeReady(node)
+		}
+		return countReady(ready) >= expected, nil
+	})
+	if err != nil {
+		if wait.Interrupted(err) || errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("timed out waiting for nodes: %s", summarizeStuckNodes(ready, expected))
+		}
+		return fmt.Errorf("failed to watch nodes: %w", err)
+	}
+
+	fmt.Printf("✓ All %d nodes ready\n", expected)
+	return nil
+}
+
+// isNodeReady reports whether a node has a true Ready condition and accepts pods
+func isNodeReady(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// countReady returns the number of ready nodes
+func countReady(ready map[string]bool) int {
+	n := 0
+	for _, r := range ready {
+		if r {
+			n++
+		}
+	}
+	return n
+}
+
+// summarizeStuckNodes describes how many nodes are ready and which ones are not
+func summarizeStuckNodes(ready map[string]bool, expected int) string {
+	var stuck []string
+	for name, r := range ready {
+		if !r {
+			stuck = append(stuck, name)
+		}
+	}
+	sort.Strings(stuck)
+
+	summary := fmt.Sprintf("%d/%d nodes ready", countReady(ready), expected)
+	if missing := expected - len(ready); missing > 0 {
+		summary += fmt.Sprintf(", %d not yet created", missing)
+	}
+	if len(stuck) > 0 {
+		shown := stuck
+		if len(shown) > maxStuckNodesReported {
+			shown = shown[:maxStuckNodesReported]
+		}
+		summary += fmt.Sprintf(", not ready: %s", strings.Join(shown, ", "))
+		if len(stuck) > len(shown) {
+			summary += fmt.Sprintf(" (and %d more)", len(stuck)-len(shown))
+		}
+	}
+	return summary
+}
@@ -0,0 +1,245 @@
+package kwok
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/jhwagner/kueue-bench/pkg/bulk"
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/ownership"
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+// nodeListPageSize bounds each node list request, so very large clusters are listed in pages
+const nodeListPageSize = 500
+
+// NodeReconcileResult counts the nodes ReconcileNodes created, updated, deleted and left in place
+type NodeReconcileResult struct {
+	Created   int
+	Updated   int
+	Deleted   int
+	Unchanged int
+}
+
+// nodePlan is what ReconcileNodes changes: the indexes of each pool's missing nodes and
+// the names of Kwok nodes no pool expects
+type nodePlan struct {
+	missing map[string][]int // key: pool name
+	// present holds the indexes of each pool's existing nodes, checked for drift
+	present   map[string][]int
+	extra     []string
+	unchanged int
+}
+
+// ReconcileNodes converges the cluster's Kwok nodes to the node pools; topology creation
+// uses it to create nodes, so creating a topology again resumes where it stopped. Missing
+// nodes are created, and existing nodes whose labels or capacity drifted from their pool
+// are applied again; nodes beyond a pool's count, or of pools no longer configured, are
+// deleted. Nodes labeled for another topology are left alone; Kwok nodes created before
+// kueue-bench labeled them are applied again, which adds the labels, or deleted if no pool
+// expects them.
+func ReconcileNodes(ctx context.Context, kubeconfigPath, topologyName string, nodePools []config.NodePool, opts ...restconfig.Option) (NodeReconcileResult, error) {
+	var result NodeReconcileResult
+
+	// Client-side throttling is handled by the adaptive limiter
+	restConfig, err := restconfig.ForKubeconfig(kubeconfigPath, maxApplyQPS, opts...)
+	if err != nil {
+		return result, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return result, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	nodes := dynamicClient.Resource(nodeGVR)
+
+	existing, err := listNodes(ctx, nodes, kwokNodeSelector+","+ownership.Selector(topologyName))
+	if err != nil {
+		return result, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	legacy, err := listNodes(ctx, nodes, kwokNodeSelector+","+ownership.LegacySelector())
+	if err != nil {
+		return result, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	plan, err := planNodes(slices.Collect(maps.Keys(existing)), slices.Collect(maps.Keys(legacy)), nodePools)
+	if err != nil {
+		return result, err
+	}
+	result.Unchanged = plan.unchanged
+
+	limiter := applyLimiter(restConfig)
+	for _, pool := range nodePools {
+		drifted, err := driftedNodes(&pool, topologyName, plan.present[pool.Name], existing)
+		if err != nil {
+			return result, err
+		}
+		if missing := plan.missing[pool.Name]; len(missing) > 0 {
+			fmt.Printf("Creating %d nodes in pool %s...\n", len(missing), pool.Name)
+			if err := applyPool(ctx, nodes, limiter, topologyName, &pool, missing); err != nil {
+				return result, fmt.Errorf("failed to create pool %s: %w", pool.Name, err)
+			}
+			result.Created += len(missing)
+		}
+		if len(drifted) > 0 {
+			fmt.Printf("Updating %d drifted nodes in pool %s...\n", len(drifted), pool.Name)
+			if err := applyPool(ctx, nodes, limiter, topologyName, &pool, drifted); err != nil {
+				return result, fmt.Errorf("failed to update pool %s: %w", pool.Name, err)
+			}
+			result.Updated += len(drifted)
+			result.Unchanged -= len(drifted)
+		}
+	}
+
+	if len(plan.extra) > 0 {
+		fmt.Printf("Deleting %d nodes not in any pool...\n", len(plan.extra))
+		if result.Deleted, err = bulk.DeleteNamed(ctx, nodes, plan.extra); err != nil {
+			return result, fmt.Errorf("failed to delete nodes: %w", err)
+		}
+	}
+
+	fmt.Printf("✓ Nodes reconciled: %d created, %d updated, %d deleted, %d unchanged\n",
+		result.Created, result.Updated, result.Deleted, result.Unchanged)
+	return result, nil
+}
+
+// listNodes lists the nodes matching selector, keyed by name
+func listNodes(ctx context.Context, nodes dynamic.ResourceInterface, selector string) (map[string]*unstructured.Unstructured, error) {
+	found := make(map[string]*unstructured.Unstructured)
+	opts := metav1.ListOptions{LabelSelector: selector, Limit: nodeListPageSize}
+	for {
+		list, err := nodes.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			found[list.Items[i].GetName()] = &list.Items[i]
+		}
+		if opts.Continue = list.GetContinue(); opts.Continue == "" {
+			return found, nil
+		}
+	}
+}
+
+// driftedNodes returns the indexes of a pool's existing nodes that no longer match the
+// node the pool renders
+func driftedNodes(pool *config.NodePool, topologyName string, indexes []int, existing map[string]*unstructured.Unstructured) ([]int, error) {
+	if len(indexes) == 0 {
+		return nil, nil
+	}
+	params, err := buildTemplateParameters(pool, topologyName)
+	if err != nil {
+		return nil, fmt.Errorf("pool '%s': %w", pool.Name, err)
+	}
+	base, err := renderNode(poolPrefix(pool.Name), pool.Name, params)
+	if err != nil {
+		return nil, err
+	}
+	namer, err := config.NewNodeNamer(pool)
+	if err != nil {
+		return nil, fmt.Errorf("pool '%s': %w", pool.Name, err)
+	}
+
+	var drifted []int
+	for _, index := range indexes {
+		want, err := poolNode(base, pool, namer, index)
+		if err != nil {
+			return nil, err
+		}
+		if got, ok := existing[want.GetName()]; ok && nodeDrifted(want, got) {
+			drifted = append(drifted, index)
+		}
+	}
+	return drifted, nil
+}
+
+// nodeDrifted reports whether an existing node lacks a label of the node kueue-bench would
+// apply, or has a different capacity or allocatable. Labels added since (e.g. by chaos)
+// are not drift.
+func nodeDrifted(want, got *unstructured.Unstructured) bool {
+	gotLabels := got.GetLabels()
+	for k, v := range want.GetLabels() {
+		if value, ok := gotLabels[k]; !ok || value != v {
+			return true
+		}
+	}
+	for _, field := range []string{"capacity", "allocatable"} {
+		if !sameResources(nodeResources(want, field), nodeResources(got, field)) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeResources returns a resource list of a node's status, e.g. its capacity. Values that
+// do not parse are kept as zero quantities, so they count as drift.
+func nodeResources(node *unstructured.Unstructured, field string) corev1.ResourceList {
+	values, _, _ := unstructured.NestedStringMap(node.Object, "status", field)
+	resources := make(corev1.ResourceList, len(values))
+	for name, value := range values {
+		quantity, _ := resource.ParseQuantity(value)
+		resources[corev1.ResourceName(name)] = quantity
+	}
+	return resources
+}
+
+// sameResources reports whether two resource lists hold the same quantities
+func sameResources(a, b corev1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, quantity := range a {
+		other, ok := b[name]
+		if !ok || quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// planNodes compares the existing Kwok nodes with the nodes the pools expect. Legacy nodes,
+// created without ownership labels, are applied again if expected and deleted otherwise.
+func planNodes(existing, legacy []string, nodePools []config.NodePool) (nodePlan, error) {
+	plan := nodePlan{missing: make(map[string][]int), present: make(map[string][]int)}
+
+	found := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		found[name] = true
+	}
+
+	expected := make(map[string]bool)
+	for i := range nodePools {
+		pool := &nodePools[i]
+		namer, err := config.NewNodeNamer(pool)
+		if err != nil {
+			return plan, fmt.Errorf("pool '%s': %w", pool.Name, err)
+		}
+		for index := 0; index < pool.Count; index++ {
+			name, err := namer.Name(index)
+			if err != nil {
+				return plan, fmt.Errorf("pool '%s': %w", pool.Name, err)
+			}
+			expected[name] = true
+			if found[name] {
+				plan.present[pool.Name] = append(plan.present[pool.Name], index)
+				plan.unchanged++
+			} else {
+				plan.missing[pool.Name] = append(plan.missing[pool.Name], index)
+			}
+		}
+	}
+
+	for _, name := range append(slices.Clone(existing), legacy...) {
+		if !expected[name] {
+			plan.extra = append(plan.extra, name)
+		}
+	}
+	sort.Strings(plan.extra)
+	return plan, nil
+}
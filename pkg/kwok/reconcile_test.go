@@ -0,0 +1,121 @@
+package kwok
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPlanNodes(t *testing.T) {
+	pools := []config.NodePool{
+		{Name: "cpu", Count: 3},
+		{Name: "gpu", Count: 2},
+	}
+	existing := []string{
+		"kwok-node-cpu-000",
+		"kwok-node-cpu-002",
+		"kwok-node-cpu-003", // beyond the pool's count
+		"kwok-node-gpu-000",
+		"kwok-node-gpu-001",
+		"kwok-node-old-000", // pool no longer configured
+	}
+
+	// Created before kueue-bench labeled its nodes
+	legacy := []string{
+		"kwok-node-cpu-001", // applied again, which labels it
+		"kwok-node-old-001",
+	}
+
+	plan, err := planNodes(existing, legacy, pools)
+	if err != nil {
+		t.Fatalf("planNodes() error = %v", err)
+	}
+
+	wantMissing := map[string][]int{"cpu": {1}}
+	if !reflect.DeepEqual(plan.missing, wantMissing) {
+		t.Errorf("missing = %v, want %v", plan.missing, wantMissing)
+	}
+	wantExtra := []string{"kwok-node-cpu-003", "kwok-node-old-000", "kwok-node-old-001"}
+	if !reflect.DeepEqual(plan.extra, wantExtra) {
+		t.Errorf("extra = %v, want %v", plan.extra, wantExtra)
+	}
+	if plan.unchanged != 4 {
+		t.Errorf("unchanged = %d, want 4", plan.unchanged)
+	}
+}
+
+func TestNodeDrifted(t *testing.T) {
+	pool := config.NodePool{Name: "cpu", Count: 1, Resources: map[string]string{"cpu": "8"}}
+	params, err := buildTemplateParameters(&pool, "bench")
+	if err != nil {
+		t.Fatalf("buildTemplateParameters() error = %v", err)
+	}
+	base, err := renderNode(poolPrefix(pool.Name), pool.Name, params)
+	if err != nil {
+		t.Fatalf("renderNode() error = %v", err)
+	}
+	namer, err := config.NewNodeNamer(&pool)
+	if err != nil {
+		t.Fatalf("NewNodeNamer() error = %v", err)
+	}
+	want, err := poolNode(base, &pool, namer, 0)
+	if err != nil {
+		t.Fatalf("poolNode() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(node *unstructured.Unstructured)
+		want   bool
+	}{
+		{name: "unchanged", modify: func(*unstructured.Unstructured) {}},
+		{
+			name: "extra label",
+			modify: func(node *unstructured.Unstructured) {
+				labels := node.GetLabels()
+				labels[NotReadyLabel] = "true"
+				node.SetLabels(labels)
+			},
+		},
+		{
+			name: "equivalent quantity",
+			modify: func(node *unstructured.Unstructured) {
+				_ = unstructured.SetNestedField(node.Object, "8000m", "status", "capacity", "cpu")
+			},
+		},
+		{
+			name: "missing label",
+			modify: func(node *unstructured.Unstructured) {
+				labels := node.GetLabels()
+				delete(labels, PoolLabel)
+				node.SetLabels(labels)
+			},
+			want: true,
+		},
+		{
+			name: "capacity changed",
+			modify: func(node *unstructured.Unstructured) {
+				_ = unstructured.SetNestedField(node.Object, "4", "status", "capacity", "cpu")
+			},
+			want: true,
+		},
+		{
+			name: "allocatable resource removed",
+			modify: func(node *unstructured.Unstructured) {
+				unstructured.RemoveNestedField(node.Object, "status", "allocatable", "cpu")
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := want.DeepCopy()
+			tt.modify(got)
+			if drifted := nodeDrifted(want, got); drifted != tt.want {
+				t.Errorf("nodeDrifted() = %v, want %v", drifted, tt.want)
+			}
+		})
+	}
+}
@@ -5,9 +5,10 @@ import (
 	_ "embed"
 	"fmt"
 
+	"github.com/jhwagner/kueue-bench/pkg/config"
 	"github.com/jhwagner/kueue-bench/pkg/manifest"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/restmapper"
 )
 
 // Embedded stage manifests from the stages/ directory.
@@ -18,6 +19,9 @@ var (
 	//go:embed stages/node-initialize.yaml
 	nodeInitializeStage []byte
 
+	//go:embed stages/node-not-ready.yaml
+	nodeNotReadyStage []byte
+
 	//go:embed stages/pod-ready.yaml
 	podReadyStage []byte
 
@@ -34,13 +38,15 @@ var (
 	podSimulateFailureStage []byte
 )
 
-// installStages applies all embedded Kwok stages to the cluster.
+// installStages applies the embedded Kwok stages to the cluster, merged with
+// any user-supplied overrides.
 func installStages(ctx context.Context, dynamicClient dynamic.Interface,
-	mapper *restmapper.DeferredDiscoveryRESTMapper) error {
+	mapper meta.RESTMapper, overrides [][]byte) error {
 
-	stages := [][]byte{
+	embedded := [][]byte{
 		nodeHeartbeatStage,
 		nodeInitializeStage,
+		nodeNotReadyStage,
 		podReadyStage,
 		podDeleteStage,
 		podCompleteTimedStage,
@@ -48,6 +54,11 @@ func installStages(ctx context.Context, dynamicClient dynamic.Interface,
 		podSimulateFailureStage,
 	}
 
+	stages, err := mergeStages(embedded, overrides)
+	if err != nil {
+		return err
+	}
+
 	for _, stage := range stages {
 		if err := manifest.ApplyBytes(ctx, dynamicClient, mapper, stage); err != nil {
 			return fmt.Errorf("failed to apply Kwok stage: %w", err)
@@ -56,3 +67,58 @@ func installStages(ctx context.Context, dynamicClient dynamic.Interface,
 
 	return nil
 }
+
+// mergeStages returns the embedded stages with overrides applied: an override
+// replaces the embedded stage of the same name, and any other override is appended.
+func mergeStages(embedded, overrides [][]byte) ([][]byte, error) {
+	byName := make(map[string][]byte, len(overrides))
+	var order []string
+	for _, stage := range overrides {
+		name, err := config.KwokStageName(stage)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := byName[name]; !ok {
+			order = append(order, name)
+		}
+		byName[name] = stage
+	}
+
+	merged := make([][]byte, 0, len(embedded)+len(overrides))
+	for _, stage := range embedded {
+		name, err := config.KwokStageName(stage)
+		if err != nil {
+			return nil, fmt.Errorf("invalid embedded stage: %w", err)
+		}
+		if override, ok := byName[name]; ok {
+			fmt.Printf("Overriding Kwok stage '%s'\n", name)
+			merged = append(merged, override)
+			delete(byName, name)
+			continue
+		}
+		merged = append(merged, stage)
+	}
+	for _, name := range order {
+		if stage, ok := byName[name]; ok {
+			merged = append(merged, stage)
+		}
+	}
+
+	return merged, nil
+}
+
+// LoadStageOverrides reads the stage documents from a cluster's KWOK config.
+func LoadStageOverrides(cfg *config.ClusterKwokConfig) ([][]byte, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	var docs [][]byte
+	for i, stage := range cfg.Stages {
+		d, err := stage.Documents()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Kwok stage %d: %w", i, err)
+		}
+		docs = append(docs, d...)
+	}
+	return docs, nil
+}
@@ -3,7 +3,8 @@ package manifest
 import (
 	"context"
 	"fmt"
-	"strings"
+
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -13,16 +14,17 @@ import (
 	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/restmapper"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 // ApplyURL fetches a manifest from a URL and applies all resources.
+// The mapper resolves each object's resource; callers against a live cluster pass a
+// discovery-backed mapper, tests a static one with a fake dynamic client.
 // Optional mutators are called on each object before it is applied.
 func ApplyURL(ctx context.Context, client dynamic.Interface,
-	mapper *restmapper.DeferredDiscoveryRESTMapper, url string,
+	mapper meta.RESTMapper, src Source,
 	mutators ...func(*unstructured.Unstructured)) error {
 
-	documents, err := FetchYAMLDocuments(url)
+	documents, err := FetchYAMLDocuments(ctx, src)
 	if err != nil {
 		return fmt.Errorf("failed to fetch manifest: %w", err)
 	}
@@ -31,20 +33,15 @@ func ApplyURL(ctx context.Context, client dynamic.Interface,
 }
 
 // ApplyBytes applies YAML manifests from raw bytes.
-// The data may contain multiple YAML documents separated by "---".
+// The data may contain multiple YAML documents, split by SplitDocuments.
 // Optional mutators are called on each object before it is applied.
 func ApplyBytes(ctx context.Context, client dynamic.Interface,
-	mapper *restmapper.DeferredDiscoveryRESTMapper, data []byte,
+	mapper meta.RESTMapper, data []byte,
 	mutators ...func(*unstructured.Unstructured)) error {
 
-	parts := strings.Split(string(data), "\n---\n")
-	var documents [][]byte
-	for _, part := range parts {
-		trimmed := strings.TrimSpace(part)
-		if trimmed == "" {
-			continue
-		}
-		documents = append(documents, []byte(trimmed))
+	documents, err := SplitDocuments(data)
+	if err != nil {
+		return err
 	}
 
 	return applyDocuments(ctx, client, mapper, documents, mutators...)
@@ -52,7 +49,7 @@ func ApplyBytes(ctx context.Context, client dynamic.Interface,
 
 // applyDocuments decodes and applies a slice of YAML documents.
 func applyDocuments(ctx context.Context, client dynamic.Interface,
-	mapper *restmapper.DeferredDiscoveryRESTMapper, documents [][]byte,
+	mapper meta.RESTMapper, documents [][]byte,
 	mutators ...func(*unstructured.Unstructured)) error {
 
 	decoder := yaml.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
@@ -102,22 +99,41 @@ func applyDocuments(ctx context.Context, client dynamic.Interface,
 
 // ApplyURLWithKubeconfig is a convenience wrapper that creates
 // dynamic client + mapper from a kubeconfig path, then calls ApplyURL.
-func ApplyURLWithKubeconfig(ctx context.Context, kubeconfigPath, url string) error {
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+func ApplyURLWithKubeconfig(ctx context.Context, kubeconfigPath string, src Source, opts ...restconfig.Option) error {
+	dynamicClient, mapper, err := clientsForKubeconfig(kubeconfigPath, opts)
+	if err != nil {
+		return err
+	}
+	return ApplyURL(ctx, dynamicClient, mapper, src)
+}
+
+// ApplyBytesWithKubeconfig is a convenience wrapper that creates
+// dynamic client + mapper from a kubeconfig path, then calls ApplyBytes.
+func ApplyBytesWithKubeconfig(ctx context.Context, kubeconfigPath string, data []byte, opts ...restconfig.Option) error {
+	dynamicClient, mapper, err := clientsForKubeconfig(kubeconfigPath, opts)
+	if err != nil {
+		return err
+	}
+	return ApplyBytes(ctx, dynamicClient, mapper, data)
+}
+
+// clientsForKubeconfig creates a dynamic client and REST mapper from a kubeconfig path
+func clientsForKubeconfig(kubeconfigPath string, opts []restconfig.Option) (dynamic.Interface, *restmapper.DeferredDiscoveryRESTMapper, error) {
+	config, err := restconfig.ForKubeconfig(kubeconfigPath, 0, opts...)
 	if err != nil {
-		return fmt.Errorf("failed to load kubeconfig: %w", err)
+		return nil, nil, err
 	}
 
 	dynamicClient, err := dynamic.NewForConfig(config)
 	if err != nil {
-		return fmt.Errorf("failed to create dynamic client: %w", err)
+		return nil, nil, fmt.Errorf("failed to create dynamic client: %w", err)
 	}
 
 	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
 	if err != nil {
-		return fmt.Errorf("failed to create discovery client: %w", err)
+		return nil, nil, fmt.Errorf("failed to create discovery client: %w", err)
 	}
 	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
 
-	return ApplyURL(ctx, dynamicClient, mapper, url)
+	return dynamicClient, mapper, nil
 }
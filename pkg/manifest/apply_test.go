@@ -0,0 +1,110 @@
+package manifest
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var (
+	deploymentGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	crGVR         = schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}
+)
+
+const testManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: controller
+  namespace: kube-system
+spec:
+  replicas: 1
+---
+# empty documents are skipped
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: defaulted
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: viewer
+`
+
+// newApplyClients returns a fake dynamic client and a static mapper for Deployments and
+// ClusterRoles
+func newApplyClients() (*dynamicfake.FakeDynamicClient, meta.RESTMapper) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole"}, meta.RESTScopeRoot)
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{deploymentGVR: "DeploymentList", crGVR: "ClusterRoleList"})
+	return client, mapper
+}
+
+func TestApplyBytes(t *testing.T) {
+	ctx := context.TODO()
+	client, mapper := newApplyClients()
+	replicas := func(obj *unstructured.Unstructured) {
+		if obj.GetKind() == "Deployment" {
+			_ = unstructured.SetNestedField(obj.Object, int64(3), "spec", "replicas")
+		}
+	}
+
+	if err := ApplyBytes(ctx, client, mapper, []byte(testManifest), replicas); err != nil {
+		t.Fatalf("ApplyBytes() error = %v", err)
+	}
+	// Applying again updates the existing objects
+	if err := ApplyBytes(ctx, client, mapper, []byte(testManifest), replicas); err != nil {
+		t.Fatalf("second ApplyBytes() error = %v", err)
+	}
+
+	var created, updated []string
+	for _, action := range client.Actions() {
+		key := action.GetResource().Resource + "/" + action.GetNamespace()
+		switch action.GetVerb() {
+		case "create":
+			created = append(created, key)
+		case "update":
+			updated = append(updated, key)
+		}
+	}
+	want := []string{"deployments/kube-system", "deployments/default", "clusterroles/"}
+	if !slices.Equal(created, append(want, want...)) {
+		t.Errorf("create calls = %v, want %v twice", created, want)
+	}
+	if !slices.Equal(updated, want) {
+		t.Errorf("update calls = %v, want %v", updated, want)
+	}
+
+	deployment, err := client.Resource(deploymentGVR).Namespace("default").Get(ctx, "defaulted", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Deployment without a namespace not applied to default: %v", err)
+	}
+	if got, _, _ := unstructured.NestedInt64(deployment.Object, "spec", "replicas"); got != 3 {
+		t.Errorf("replicas = %d, want 3 from the mutator", got)
+	}
+	if _, err := client.Resource(crGVR).Get(ctx, "viewer", metav1.GetOptions{}); err != nil {
+		t.Errorf("cluster-scoped ClusterRole not applied: %v", err)
+	}
+}
+
+func TestApplyBytes_UnknownKind(t *testing.T) {
+	client, mapper := newApplyClients()
+	data := []byte("apiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: w\n")
+
+	if err := ApplyBytes(context.TODO(), client, mapper, data); err == nil {
+		t.Fatal("expected an error for a kind the mapper does not know")
+	}
+	if actions := client.Actions(); len(actions) != 0 {
+		t.Errorf("expected no API calls, got %d", len(actions))
+	}
+}
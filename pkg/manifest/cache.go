@@ -0,0 +1,132 @@
+package manifest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jhwagner/kueue-bench/pkg/state"
+)
+
+// Cache stores downloaded manifests on disk, keyed by URL and expected checksum
+type Cache struct {
+	// Dir is the cache directory
+	Dir string
+}
+
+// OpenCache returns the download cache in the default state directory (see state.Root)
+func OpenCache() (*Cache, error) {
+	store, err := state.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state: %w", err)
+	}
+	return NewCache(store), nil
+}
+
+// NewCache returns the download cache under store's cache/
+func NewCache(store *state.Store) *Cache {
+	return &Cache{Dir: filepath.Join(store.Root(), string(state.Cache))}
+}
+
+// FetchCached fetches a manifest through the download cache
+func FetchCached(ctx context.Context, src Source) ([]byte, error) {
+	cache, err := OpenCache()
+	if err != nil {
+		return nil, err
+	}
+	return cache.Fetch(ctx, src)
+}
+
+// Fetch returns the manifest for the source through the cache. A manifest pinned by
+// SHA256, or any manifest when offline, is served from the cache and only downloaded on a
+// miss; a cached entry whose content no longer matches SHA256 is downloaded again.
+// Unpinned manifests are downloaded every time, so they follow upstream, and the cached
+// copy is only served when the download fails.
+func (c *Cache) Fetch(ctx context.Context, src Source) ([]byte, error) {
+	path := c.path(src.URL, src.SHA256)
+	cached, readErr := os.ReadFile(path) //nolint:gosec // path is constructed from the cache directory
+	if readErr == nil && verifySHA256(cached, src.SHA256) != nil {
+		cached, readErr = nil, os.ErrNotExist
+	}
+	if readErr == nil && (src.SHA256 != "" || src.Offline) {
+		return cached, nil
+	}
+	if src.Offline {
+		return nil, fmt.Errorf("%s is not in the download cache (%s); run once without --offline to cache it", src.URL, c.Dir)
+	}
+
+	data, err := Fetch(ctx, src.URL, src.Auth)
+	if err != nil {
+		if readErr == nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; using the cached copy of %s\n", err, src.URL)
+			return cached, nil
+		}
+		return nil, err
+	}
+	if err := verifySHA256(data, src.SHA256); err != nil {
+		return nil, fmt.Errorf("%s: %w", src.URL, err)
+	}
+
+	// Caching is best effort: a failed write only costs a download next time
+	if err := c.write(path, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to cache %s: %v\n", src.URL, err)
+	}
+	return data, nil
+}
+
+// Purge removes every cached manifest and returns the number removed
+func (c *Cache) Purge() (int, error) {
+	entries, err := os.ReadDir(c.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+	if err := os.RemoveAll(c.Dir); err != nil {
+		return 0, fmt.Errorf("failed to remove cache directory: %w", err)
+	}
+	return len(entries), nil
+}
+
+// path returns the cache file for url and sha256Hex
+func (c *Cache) path(url, sha256Hex string) string {
+	key := sha256.Sum256([]byte(url + "\n" + sha256Hex))
+	return filepath.Join(c.Dir, hex.EncodeToString(key[:])+".yaml")
+}
+
+// write stores data at path atomically, so concurrent cluster creation never reads a
+// partially written entry
+func (c *Cache) write(path string, data []byte) error {
+	if err := os.MkdirAll(c.Dir, 0750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.Dir, ".download-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// verifySHA256 checks data has the SHA-256 (hex) digest want, if set
+func verifySHA256(data []byte, want string) error {
+	if want == "" {
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("checksum mismatch: got sha256 %s, want %s", got, want)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package manifest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCacheFetch(t *testing.T) {
+	body := "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: cached\n"
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	sum := sha256.Sum256([]byte(body))
+	digest := hex.EncodeToString(sum[:])
+
+	ctx := context.Background()
+	cache := &Cache{Dir: t.TempDir()}
+	for i := 0; i < 2; i++ {
+		data, err := cache.Fetch(ctx, Source{URL: server.URL, SHA256: digest})
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if string(data) != body {
+			t.Errorf("Fetch() = %q, want %q", data, body)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("server got %d requests, want 1 (second fetch should hit the cache)", requests)
+	}
+
+	if _, err := cache.Fetch(ctx, Source{URL: server.URL, SHA256: strings.Repeat("0", 64)}); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Fetch() with wrong checksum error = %v, expected checksum mismatch", err)
+	}
+
+	if _, err := cache.Fetch(ctx, Source{URL: server.URL, SHA256: digest, Offline: true}); err != nil {
+		t.Errorf("offline Fetch() of cached manifest error = %v", err)
+	}
+	if _, err := cache.Fetch(ctx, Source{URL: server.URL + "/other.yaml", Offline: true}); err == nil || !strings.Contains(err.Error(), "not in the download cache") {
+		t.Errorf("offline Fetch() of uncached manifest error = %v, expected cache miss", err)
+	}
+
+	removed, err := cache.Purge()
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Purge() removed %d entries, want 1", removed)
+	}
+	if _, err := cache.Fetch(ctx, Source{URL: server.URL, SHA256: digest, Offline: true}); err == nil {
+		t.Error("offline Fetch() after Purge() succeeded")
+	}
+}
+
+func TestCacheFetchUnpinned(t *testing.T) {
+	body := "v1"
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	cache := &Cache{Dir: t.TempDir()}
+	src := Source{URL: server.URL}
+	if data, err := cache.Fetch(ctx, src); err != nil || string(data) != "v1" {
+		t.Fatalf("Fetch() = %q, %v, want v1", data, err)
+	}
+
+	// Unpinned manifests follow upstream
+	body = "v2"
+	if data, err := cache.Fetch(ctx, src); err != nil || string(data) != "v2" {
+		t.Errorf("Fetch() after upstream change = %q, %v, want v2", data, err)
+	}
+
+	// and fall back to the cached copy when the download fails
+	fail = true
+	if data, err := cache.Fetch(ctx, src); err != nil || string(data) != "v2" {
+		t.Errorf("Fetch() with failing upstream = %q, %v, want cached v2", data, err)
+	}
+	if data, err := cache.Fetch(ctx, Source{URL: server.URL, Offline: true}); err != nil || string(data) != "v2" {
+		t.Errorf("offline Fetch() = %q, %v, want cached v2", data, err)
+	}
+}
@@ -0,0 +1,54 @@
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// SplitDocuments splits a multi-document YAML stream into its documents. Separators may
+// carry trailing whitespace or comments, lines may end in CRLF, and the stream may start
+// with a separator; empty and comment-only documents are dropped.
+func SplitDocuments(data []byte) ([][]byte, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	var documents [][]byte
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return documents, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read YAML document %d: %w", len(documents), err)
+		}
+
+		doc = bytes.TrimSpace(trimLeadingSeparator(doc))
+		if len(doc) == 0 {
+			continue
+		}
+		// Comment-only documents convert to JSON null
+		j, err := yaml.YAMLToJSON(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse YAML document %d: %w", len(documents), err)
+		}
+		if string(j) == "null" {
+			continue
+		}
+		documents = append(documents, doc)
+	}
+}
+
+// trimLeadingSeparator drops a "---" line starting the stream, which the YAML reader
+// leaves on the first document
+func trimLeadingSeparator(doc []byte) []byte {
+	line, rest, _ := bytes.Cut(bytes.TrimLeft(doc, " \t\r\n"), []byte("\n"))
+	line = bytes.TrimSpace(line)
+	if bytes.Equal(line, []byte("---")) || bytes.HasPrefix(line, []byte("--- #")) {
+		return rest
+	}
+	return doc
+}
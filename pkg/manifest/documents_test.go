@@ -0,0 +1,70 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitDocuments(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "plain separators",
+			data: "kind: A\n---\nkind: B\n",
+			want: []string{"kind: A", "kind: B"},
+		},
+		{
+			name: "leading separator",
+			data: "---\nkind: A\n---\nkind: B\n",
+			want: []string{"kind: A", "kind: B"},
+		},
+		{
+			name: "separator with trailing whitespace and comment",
+			data: "kind: A\n---  \nkind: B\n--- # next\nkind: C\n",
+			want: []string{"kind: A", "kind: B", "kind: C"},
+		},
+		{
+			name: "CRLF line endings",
+			data: "kind: A\r\n---\r\nkind: B\r\n",
+			want: []string{"kind: A", "kind: B"},
+		},
+		{
+			name: "empty and comment-only documents",
+			data: "# header\n---\n\n---\nkind: A\n---\n# trailing\n",
+			want: []string{"kind: A"},
+		},
+		{
+			name: "separator-like text inside a block scalar",
+			data: "kind: A\ndata:\n  script: |\n    echo ---\n",
+			want: []string{"kind: A\ndata:\n  script: |\n    echo ---"},
+		},
+		{
+			name:    "invalid document",
+			data:    "kind: [A\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := SplitDocuments([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitDocuments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var got []string
+			for _, d := range docs {
+				got = append(got, string(d))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitDocuments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,41 +1,140 @@
 package manifest
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
-	"strings"
+	"time"
 )
 
-// FetchYAMLDocuments fetches YAML content from a URL and splits it into separate documents
-func FetchYAMLDocuments(url string) ([][]byte, error) {
-	// Fetch content
-	resp, err := http.Get(url) //nolint:gosec // URL is from trusted internal config (Kwok manifest URLs)
+const (
+	// fetchAttempts is how many times a download is tried before giving up
+	fetchAttempts = 4
+	// fetchTimeout bounds a single download attempt, including reading the body
+	fetchTimeout = 2 * time.Minute
+)
+
+// fetchBackoff is the delay before the first retry; it doubles with each attempt
+var fetchBackoff = time.Second
+
+// httpClient downloads manifests. Proxies are taken from HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY.
+var httpClient = &http.Client{
+	Timeout: fetchTimeout,
+	Transport: &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+		ForceAttemptHTTP2:     true,
+	},
+}
+
+// Auth holds optional credentials sent with a download, e.g. for a private artifact host.
+// A bearer token takes precedence over basic auth.
+type Auth struct {
+	BearerToken string
+	Username    string
+	Password    string
+}
+
+// Source identifies a manifest to download
+type Source struct {
+	URL string
+	// SHA256 is the expected SHA-256 (hex) of the manifest, if set
+	SHA256 string
+	// Auth is sent with the request, if set
+	Auth *Auth
+	// Offline serves the manifest only from the download cache, never downloading it
+	Offline bool
+}
+
+// statusError is a non-200 HTTP response
+type statusError struct {
+	code int
+	url  string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("HTTP %d from %s", e.code, e.url)
+}
+
+// Fetch fetches raw content from a URL, retrying transient failures (network errors,
+// 429 and 5xx responses) with exponential backoff until ctx is cancelled
+func Fetch(ctx context.Context, url string, auth *Auth) ([]byte, error) {
+	backoff := fetchBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		var data []byte
+		data, err = fetchOnce(ctx, url, auth)
+		if err == nil {
+			return data, nil
+		}
+		if attempt == fetchAttempts || !retryable(err) || ctx.Err() != nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to fetch from %s: %w", url, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return nil, err
+}
+
+// fetchOnce makes a single download attempt
+func fetchOnce(ctx context.Context, url string, auth *Auth) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %s: %w", url, err)
+	}
+	if auth != nil {
+		if auth.BearerToken != "" {
+			req.Header.Set("Authorization", "Bearer "+auth.BearerToken)
+		} else if auth.Username != "" {
+			req.SetBasicAuth(auth.Username, auth.Password)
+		}
+	}
+
+	resp, err := httpClient.Do(req) //nolint:gosec // URL is from trusted config (Kwok release or topology file)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch from %s: %w", url, err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d from %s", resp.StatusCode, url)
+		return nil, &statusError{code: resp.StatusCode, url: url}
 	}
 
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	return data, nil
+}
 
-	// Split YAML documents by ---
-	documents := strings.Split(string(data), "\n---\n")
-	var result [][]byte
+// retryable reports whether a failed download may succeed when tried again
+func retryable(err error) bool {
+	var status *statusError
+	if errors.As(err, &status) {
+		return status.code == http.StatusTooManyRequests || status.code >= 500
+	}
+	return !errors.Is(err, context.Canceled)
+}
 
-	for _, doc := range documents {
-		trimmed := strings.TrimSpace(doc)
-		if trimmed == "" {
-			continue
-		}
-		result = append(result, []byte(trimmed))
+// FetchYAMLDocuments fetches YAML content through the download cache and splits it into
+// separate documents
+func FetchYAMLDocuments(ctx context.Context, src Source) ([][]byte, error) {
+	data, err := FetchCached(ctx, src)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	return SplitDocuments(data)
 }
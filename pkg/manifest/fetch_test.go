@@ -0,0 +1,110 @@
+package manifest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetch(t *testing.T) {
+	fetchBackoff = time.Millisecond
+	t.Cleanup(func() { fetchBackoff = time.Second })
+
+	tests := []struct {
+		name         string
+		statuses     []int
+		auth         *Auth
+		wantHeader   string
+		wantRequests int
+		wantErr      bool
+		errContains  string
+	}{
+		{
+			name:         "success",
+			statuses:     []int{http.StatusOK},
+			wantRequests: 1,
+		},
+		{
+			name:         "retries server errors",
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK},
+			wantRequests: 3,
+		},
+		{
+			name:         "gives up after max attempts",
+			statuses:     []int{http.StatusBadGateway},
+			wantRequests: fetchAttempts,
+			wantErr:      true,
+			errContains:  "HTTP 502",
+		},
+		{
+			name:         "does not retry client errors",
+			statuses:     []int{http.StatusNotFound},
+			wantRequests: 1,
+			wantErr:      true,
+			errContains:  "HTTP 404",
+		},
+		{
+			name:         "bearer token",
+			statuses:     []int{http.StatusOK},
+			auth:         &Auth{BearerToken: "secret"},
+			wantHeader:   "Bearer secret",
+			wantRequests: 1,
+		},
+		{
+			name:         "basic auth",
+			statuses:     []int{http.StatusOK},
+			auth:         &Auth{Username: "user", Password: "pass"},
+			wantHeader:   "Basic dXNlcjpwYXNz",
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			var header string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[min(requests, len(tt.statuses)-1)]
+				requests++
+				header = r.Header.Get("Authorization")
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte("kind: Namespace\n"))
+			}))
+			defer server.Close()
+
+			data, err := Fetch(context.Background(), server.URL, tt.auth)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("Fetch() error = %v, expected to contain %q", err, tt.errContains)
+				}
+			} else if string(data) != "kind: Namespace\n" {
+				t.Errorf("Fetch() = %q", data)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("server got %d requests, want %d", requests, tt.wantRequests)
+			}
+			if header != tt.wantHeader {
+				t.Errorf("Authorization header = %q, want %q", header, tt.wantHeader)
+			}
+		})
+	}
+}
+
+func TestFetchCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Fetch(ctx, server.URL, nil); err == nil || !strings.Contains(err.Error(), "context canceled") {
+		t.Errorf("Fetch() error = %v, expected context canceled", err)
+	}
+}
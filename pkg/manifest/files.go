@@ -0,0 +1,89 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+)
+
+// manifestExtensions are the file extensions read from a manifest directory
+var manifestExtensions = []string{".yaml", ".yml", ".json"}
+
+// Files expands a manifest path into the files it names, in lexical order. The path may be
+// a file, a directory, whose top-level .yaml, .yml and .json files are used, or a glob pattern.
+func Files(path string) ([]string, error) {
+	if strings.ContainsAny(path, "*?[") {
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %s: %w", path, err)
+		}
+		var files []string
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && !info.IsDir() {
+				files = append(files, m)
+			}
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no files match %s", path)
+		}
+		sort.Strings(files)
+		return files, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", path, err)
+	}
+	var files []string
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || !slices.Contains(manifestExtensions, ext) {
+			continue
+		}
+		files = append(files, filepath.Join(path, e.Name()))
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no manifest files in directory %s", path)
+	}
+	// ReadDir already sorts by file name
+	return files, nil
+}
+
+// ApplyPathWithKubeconfig applies the manifests at a local path, as expanded by Files, in order
+func ApplyPathWithKubeconfig(ctx context.Context, kubeconfigPath, path string, opts ...restconfig.Option) error {
+	files, err := Files(path)
+	if err != nil {
+		return err
+	}
+	dynamicClient, mapper, err := clientsForKubeconfig(kubeconfigPath, opts)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file) //nolint:gosec // path comes from the user's topology file
+		if err != nil {
+			return fmt.Errorf("failed to read manifest: %w", err)
+		}
+		if err := ApplyBytes(ctx, dynamicClient, mapper, data); err != nil {
+			return fmt.Errorf("failed to apply %s: %w", file, err)
+		}
+		// CRDs applied by one file may be used by the next
+		mapper.Reset()
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"02-operator.yaml", "01-crds.yml", "03-config.json", "README.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "nested.yaml"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	join := func(names ...string) []string {
+		paths := make([]string, len(names))
+		for i, n := range names {
+			paths[i] = filepath.Join(dir, n)
+		}
+		return paths
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "file", path: filepath.Join(dir, "02-operator.yaml"), want: join("02-operator.yaml")},
+		{name: "directory", path: dir, want: join("01-crds.yml", "02-operator.yaml", "03-config.json")},
+		{name: "glob", path: filepath.Join(dir, "0[12]-*"), want: join("01-crds.yml", "02-operator.yaml")},
+		{name: "glob without files", path: filepath.Join(dir, "*.txt"), wantErr: true},
+		{name: "missing file", path: filepath.Join(dir, "missing.yaml"), wantErr: true},
+		{name: "directory without manifests", path: t.TempDir(), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Files(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Files() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Files() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// BuildKustomization builds a kustomization, as kustomize build does, and returns the
+// resulting multi-document YAML. target is a local directory or a remote base such as
+// "https://github.com/org/repo//config/default?ref=v1.0.0"; remote bases require git.
+func BuildKustomization(target string) ([]byte, error) {
+	k := krusty.MakeKustomizer(krusty.MakeDefaultOptions())
+	resources, err := k.Run(filesys.MakeFsOnDisk(), target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kustomization %s: %w", target, err)
+	}
+	data, err := resources.AsYaml()
+	if err != nil {
+		return nil, fmt.Errorf("failed to render kustomization %s: %w", target, err)
+	}
+	return data, nil
+}
+
+// ApplyKustomizationWithKubeconfig builds a kustomization and applies the result
+func ApplyKustomizationWithKubeconfig(ctx context.Context, kubeconfigPath, target string, opts ...restconfig.Option) error {
+	data, err := BuildKustomization(target)
+	if err != nil {
+		return err
+	}
+	return ApplyBytesWithKubeconfig(ctx, kubeconfigPath, data, opts...)
+}
@@ -0,0 +1,35 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildKustomization(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"kustomization.yaml": "namePrefix: bench-\nnamespace: kueue-system\nresources:\n- configmap.yaml\n",
+		"configmap.yaml":     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\ndata:\n  key: value\n",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := BuildKustomization(dir)
+	if err != nil {
+		t.Fatalf("BuildKustomization() error = %v", err)
+	}
+	for _, want := range []string{"name: bench-settings", "namespace: kueue-system", "key: value"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("BuildKustomization() = %q, expected to contain %q", data, want)
+		}
+	}
+
+	if _, err := BuildKustomization(t.TempDir()); err == nil {
+		t.Error("BuildKustomization() of a directory without a kustomization succeeded")
+	}
+}
@@ -0,0 +1,94 @@
+// Package ownership labels the objects kueue-bench creates in clusters (nodes, workloads,
+// Kueue objects, namespaces and MultiKueue credentials), so they can be attributed to a
+// topology and run, and cleaned up with label selectors.
+package ownership
+
+import (
+	"maps"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Label keys and values set on every object kueue-bench creates
+const (
+	// ManagedByLabel is the well-known label naming the tool managing an object
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	ManagedBy      = "kueue-bench"
+	// TopologyLabel names the topology an object was created for
+	TopologyLabel = "kueue-bench.io/topology"
+	// RunLabel is the ID of the run that submitted a workload
+	RunLabel = "kueue-bench.io/run-id"
+)
+
+// Labels returns the labels attributing an object to kueue-bench, and to a topology and
+// run when set
+func Labels(topology, runID string) map[string]string {
+	labels := map[string]string{ManagedByLabel: ManagedBy}
+	if topology != "" {
+		labels[TopologyLabel] = topology
+	}
+	if runID != "" {
+		labels[RunLabel] = runID
+	}
+	return labels
+}
+
+// Apply adds the ownership labels to obj, keeping its other labels
+func Apply(obj metav1.Object, topology, runID string) {
+	labels := maps.Clone(obj.GetLabels())
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	maps.Copy(labels, Labels(topology, runID))
+	obj.SetLabels(labels)
+}
+
+// Selector returns a label selector matching the objects kueue-bench created, for the
+// topology if set
+func Selector(topology string) string {
+	selector := ManagedByLabel + "=" + ManagedBy
+	if topology != "" {
+		selector += "," + TopologyLabel + "=" + topology
+	}
+	return selector
+}
+
+// RunSelector returns a label selector matching the workloads kueue-bench submitted for a
+// run, or for any run when runID is empty, to the topology if set
+func RunSelector(topology, runID string) string {
+	return Selector(topology) + "," + runRequirement(runID)
+}
+
+// LegacySelector returns a label selector matching objects created before kueue-bench
+// labeled them, which have no managed-by label
+func LegacySelector() string {
+	return "!" + ManagedByLabel
+}
+
+// LegacyRunSelector returns a label selector matching the workloads of a run, or of any run
+// when runID is empty, that were submitted before kueue-bench labeled them as its own
+func LegacyRunSelector(runID string) string {
+	return LegacySelector() + "," + runRequirement(runID)
+}
+
+// Owned reports whether labels attribute an object to kueue-bench, and to the topology if set
+func Owned(labels map[string]string, topology string) bool {
+	if labels[ManagedByLabel] != ManagedBy {
+		return false
+	}
+	return topology == "" || labels[TopologyLabel] == topology
+}
+
+// Legacy reports whether an object was created before kueue-bench labeled its objects
+func Legacy(labels map[string]string) bool {
+	_, ok := labels[ManagedByLabel]
+	return !ok
+}
+
+// runRequirement selects objects of a run, or of any run when runID is empty
+func runRequirement(runID string) string {
+	if runID == "" {
+		return RunLabel
+	}
+	return RunLabel + "=" + runID
+}
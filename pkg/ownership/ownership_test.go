@@ -0,0 +1,84 @@
+package ownership
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestApply(t *testing.T) {
+	obj := &metav1.ObjectMeta{Labels: map[string]string{"team": "a", TopologyLabel: "stale"}}
+	Apply(obj, "bench", "")
+
+	want := map[string]string{"team": "a", ManagedByLabel: ManagedBy, TopologyLabel: "bench"}
+	if len(obj.Labels) != len(want) {
+		t.Fatalf("labels = %v, want %v", obj.Labels, want)
+	}
+	for k, v := range want {
+		if obj.Labels[k] != v {
+			t.Errorf("label %s = %q, want %q", k, obj.Labels[k], v)
+		}
+	}
+}
+
+func TestSelectors(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		labels   map[string]string
+		want     bool
+	}{
+		{name: "any topology", selector: Selector(""), labels: Labels("bench", ""), want: true},
+		{name: "same topology", selector: Selector("bench"), labels: Labels("bench", "r1"), want: true},
+		{name: "other topology", selector: Selector("bench"), labels: Labels("other", ""), want: false},
+		{name: "unmanaged", selector: Selector(""), labels: map[string]string{TopologyLabel: "bench"}, want: false},
+		{name: "any run", selector: RunSelector("", ""), labels: Labels("bench", "r1"), want: true},
+		{name: "same run", selector: RunSelector("", "r1"), labels: Labels("", "r1"), want: true},
+		{name: "other run", selector: RunSelector("", "r1"), labels: Labels("", "r2"), want: false},
+		{name: "run of topology", selector: RunSelector("bench", "r1"), labels: Labels("bench", "r1"), want: true},
+		{name: "run of other topology", selector: RunSelector("bench", ""), labels: Labels("other", "r1"), want: false},
+		{name: "not a workload", selector: RunSelector("", ""), labels: Labels("bench", ""), want: false},
+		{name: "legacy", selector: LegacySelector(), labels: map[string]string{"type": "kwok"}, want: true},
+		{name: "not legacy", selector: LegacySelector(), labels: Labels("bench", ""), want: false},
+		{name: "legacy run", selector: LegacyRunSelector("r1"), labels: map[string]string{RunLabel: "r1"}, want: true},
+		{name: "labeled run", selector: LegacyRunSelector(""), labels: Labels("", "r1"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := labels.Parse(tt.selector)
+			if err != nil {
+				t.Fatalf("invalid selector %q: %v", tt.selector, err)
+			}
+			if got := selector.Matches(labels.Set(tt.labels)); got != tt.want {
+				t.Errorf("%q matches %v = %v, want %v", tt.selector, tt.labels, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOwned(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		topology string
+		owned    bool
+		legacy   bool
+	}{
+		{name: "same topology", labels: Labels("bench", ""), topology: "bench", owned: true},
+		{name: "other topology", labels: Labels("other", ""), topology: "bench"},
+		{name: "any topology", labels: Labels("other", ""), owned: true},
+		{name: "unlabeled", labels: map[string]string{"team": "a"}, topology: "bench", legacy: true},
+		{name: "other manager", labels: map[string]string{ManagedByLabel: "helm"}, topology: "bench"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Owned(tt.labels, tt.topology); got != tt.owned {
+				t.Errorf("Owned(%v, %q) = %v, want %v", tt.labels, tt.topology, got, tt.owned)
+			}
+			if got := Legacy(tt.labels); got != tt.legacy {
+				t.Errorf("Legacy(%v) = %v, want %v", tt.labels, got, tt.legacy)
+			}
+		})
+	}
+}
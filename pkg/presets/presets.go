@@ -0,0 +1,139 @@
+// Package presets ships canned scenarios reproducing classic Kueue scheduling situations.
+// Each preset pairs a topology template with the workload profile that drives it, so it
+// can be run in one command, or exported to files to tweak and run with topology create
+// and workload submit.
+package presets
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+)
+
+// Preset topologies and profiles, one directory per preset
+//
+//go:embed templates
+var templates embed.FS
+
+const (
+	topologyFile = "topology.yaml"
+	profileFile  = "profile.yaml"
+)
+
+// Preset is a topology template paired with the workload profile that drives it
+type Preset struct {
+	Name string
+	// Description is the topology's kueue-bench.io/description annotation
+	Description string
+
+	topology []byte
+	profile  []byte
+}
+
+// Summary returns the first line of the preset's description
+func (p *Preset) Summary() string {
+	summary, _, _ := strings.Cut(strings.TrimSpace(p.Description), "\n")
+	return summary
+}
+
+// Names returns the names of the available presets, sorted
+func Names() []string {
+	entries, _ := fs.ReadDir(templates, "templates")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// List returns every available preset, sorted by name
+func List() ([]*Preset, error) {
+	names := Names()
+	presets := make([]*Preset, 0, len(names))
+	for _, name := range names {
+		p, err := Get(name)
+		if err != nil {
+			return nil, err
+		}
+		presets = append(presets, p)
+	}
+	return presets, nil
+}
+
+// Get returns the named preset
+func Get(name string) (*Preset, error) {
+	dir := path.Join("templates", name)
+	topology, err := templates.ReadFile(path.Join(dir, topologyFile))
+	if err != nil {
+		return nil, fmt.Errorf("unknown preset %q (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	profile, err := templates.ReadFile(path.Join(dir, profileFile))
+	if err != nil {
+		return nil, fmt.Errorf("preset %s has no workload profile: %w", name, err)
+	}
+
+	p := &Preset{Name: name, topology: topology, profile: profile}
+	cfg, err := p.Topology()
+	if err != nil {
+		return nil, err
+	}
+	p.Description = cfg.Metadata.Annotations[config.DescriptionAnnotation]
+	return p, nil
+}
+
+// Topology parses the preset's topology template
+func (p *Preset) Topology() (*config.Topology, error) {
+	cfg, err := config.ParseTopology(p.topology, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse topology of preset %s: %w", p.Name, err)
+	}
+	return cfg, nil
+}
+
+// Profile parses the preset's workload profile
+func (p *Preset) Profile() (*config.WorkloadProfile, error) {
+	profile, err := config.ParseWorkloadProfile(p.profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workload profile of preset %s: %w", p.Name, err)
+	}
+	return profile, nil
+}
+
+// Export writes the preset's topology and profile to <name>-topology.yaml and
+// <name>-profile.yaml in dir and returns their paths. Existing files are only replaced if
+// overwrite is set.
+func (p *Preset) Export(dir string, overwrite bool) (topologyPath, profilePath string, err error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+	topologyPath = filepath.Join(dir, p.Name+"-topology.yaml")
+	profilePath = filepath.Join(dir, p.Name+"-profile.yaml")
+	if !overwrite {
+		for _, f := range []string{topologyPath, profilePath} {
+			if _, err := os.Stat(f); err == nil {
+				return "", "", fmt.Errorf("%s already exists", f)
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return "", "", fmt.Errorf("failed to check %s: %w", f, err)
+			}
+		}
+	}
+
+	if err := os.WriteFile(topologyPath, p.topology, 0o644); err != nil {
+		return "", "", fmt.Errorf("failed to write topology: %w", err)
+	}
+	if err := os.WriteFile(profilePath, p.profile, 0o644); err != nil {
+		return "", "", fmt.Errorf("failed to write workload profile: %w", err)
+	}
+	return topologyPath, profilePath, nil
+}
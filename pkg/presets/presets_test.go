@@ -0,0 +1,98 @@
+package presets
+
+import (
+	"testing"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+)
+
+func TestPresetsValidate(t *testing.T) {
+	presets, err := List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	want := []string{"borrow-reclaim", "fair-share-convergence", "preemption-storm"}
+	if len(presets) != len(want) {
+		t.Fatalf("List() returned %d presets, want %v", len(presets), want)
+	}
+
+	for i, p := range presets {
+		t.Run(p.Name, func(t *testing.T) {
+			if p.Name != want[i] {
+				t.Errorf("presets[%d] = %s, want %s", i, p.Name, want[i])
+			}
+			if p.Summary() == "" {
+				t.Error("preset has no description")
+			}
+
+			topology, err := p.Topology()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if topology.Metadata.Name != p.Name {
+				t.Errorf("topology name = %s, want %s", topology.Metadata.Name, p.Name)
+			}
+			if err := config.ValidateTopology(topology); err != nil {
+				t.Errorf("ValidateTopology() error = %v", err)
+			}
+
+			profile, err := p.Profile()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := config.ValidateWorkloadProfile(profile); err != nil {
+				t.Errorf("ValidateWorkloadProfile() error = %v", err)
+			}
+
+			// Every LocalQueue and priority class the profile uses is defined by the topology
+			kueueCfg := topology.Spec.Clusters[0].Kueue
+			queues := make(map[string]bool)
+			for _, lq := range kueueCfg.LocalQueues {
+				queues[lq.Namespace+"/"+lq.Name] = true
+			}
+			priorityClasses := make(map[string]bool)
+			for _, pc := range kueueCfg.PriorityClasses {
+				priorityClasses[pc.Name] = true
+			}
+			for _, w := range profile.Spec.Workloads {
+				if !queues[w.Namespace+"/"+w.LocalQueue] {
+					t.Errorf("profile submits to %s/%s, which the topology does not define", w.Namespace, w.LocalQueue)
+				}
+				if w.PriorityClass != "" && !priorityClasses[w.PriorityClass] {
+					t.Errorf("profile uses priority class %s, which the topology does not define", w.PriorityClass)
+				}
+			}
+		})
+	}
+}
+
+func TestGetUnknown(t *testing.T) {
+	if _, err := Get("missing"); err == nil {
+		t.Error("expected an error for an unknown preset")
+	}
+}
+
+func TestExport(t *testing.T) {
+	p, err := Get("preemption-storm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	topologyPath, profilePath, err := p.Export(dir, false)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if _, err := config.LoadTopology(topologyPath); err != nil {
+		t.Errorf("exported topology does not load: %v", err)
+	}
+	if _, err := config.LoadWorkloadProfile(profilePath); err != nil {
+		t.Errorf("exported profile does not load: %v", err)
+	}
+
+	if _, _, err := p.Export(dir, false); err == nil {
+		t.Error("expected an error exporting over existing files")
+	}
+	if _, _, err := p.Export(dir, true); err != nil {
+		t.Errorf("Export() with overwrite error = %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+// Package restconfig builds the client-go REST configs kueue-bench connects to clusters
+// with, so every client shares one user agent and default rate limits.
+package restconfig
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// DefaultQPS is the client-side rate limit of clients that do not set their own. client-go's
+// own default (5 QPS) silently throttles bulk provisioning.
+const DefaultQPS = 50
+
+// Overrides replace the rate limits and timeout of a REST config, e.g. from a topology's
+// spec.client. Zero fields keep each client's defaults.
+type Overrides struct {
+	QPS     float32       `json:"qps,omitempty"`
+	Burst   int           `json:"burst,omitempty"`
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
+var (
+	mu        sync.RWMutex
+	userAgent = "kueue-bench/dev"
+)
+
+// Option customizes the REST configs ForKubeconfig and ForConfig build
+type Option func(*rest.Config)
+
+// WithOverrides applies a topology's client overrides; nil keeps the client's defaults.
+// Overrides are passed per client rather than set process-wide, so clients of topologies
+// with different limits can run side by side, e.g. under serve.
+func WithOverrides(o *Overrides) Option {
+	return func(config *rest.Config) {
+		if o == nil {
+			return
+		}
+		if o.QPS > 0 {
+			config.QPS = o.QPS
+			config.Burst = int(o.QPS)
+		}
+		if o.Burst > 0 {
+			config.Burst = o.Burst
+		}
+		if o.Timeout > 0 {
+			config.Timeout = o.Timeout
+		}
+	}
+}
+
+// SetVersion sets the kueue-bench version reported in the user agent, "kueue-bench/<version>"
+func SetVersion(version string) {
+	mu.Lock()
+	defer mu.Unlock()
+	userAgent = "kueue-bench/" + version
+}
+
+// ForKubeconfig loads the REST config of the cluster at kubeconfigPath. qps is the
+// client's default rate limit, also used as its burst; 0 selects DefaultQPS. Options
+// apply after the defaults.
+func ForKubeconfig(kubeconfigPath string, qps float32, opts ...Option) (*rest.Config, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	apply(config, qps, opts)
+	return config, nil
+}
+
+// ForConfig returns the REST config of the current context of a loaded kubeconfig, with
+// the user agent and rate limits ForKubeconfig sets
+func ForConfig(kubeconfig *clientcmdapi.Config, qps float32, opts ...Option) (*rest.Config, error) {
+	config, err := clientcmd.NewDefaultClientConfig(*kubeconfig, nil).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	apply(config, qps, opts)
+	return config, nil
+}
+
+// FlattenKubeconfig reads the kubeconfig at kubeconfigPath with the certificate and key
+// files it references inlined. Relative file paths are resolved against the kubeconfig's
+// directory, so the result can be used elsewhere, e.g. in a Secret of another cluster.
+func FlattenKubeconfig(kubeconfigPath string) ([]byte, error) {
+	kubeconfig, err := clientcmd.LoadFromFile(kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	if err := clientcmdapi.FlattenConfig(kubeconfig); err != nil {
+		return nil, fmt.Errorf("failed to inline kubeconfig files: %w", err)
+	}
+	data, err := clientcmd.Write(*kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode kubeconfig: %w", err)
+	}
+	return data, nil
+}
+
+// apply sets the user agent and default rate limits of config, then applies opts
+func apply(config *rest.Config, qps float32, opts []Option) {
+	mu.RLock()
+	config.UserAgent = userAgent
+	mu.RUnlock()
+
+	if qps <= 0 {
+		qps = DefaultQPS
+	}
+	config.QPS = qps
+	config.Burst = int(qps)
+	for _, opt := range opts {
+		opt(config)
+	}
+}
@@ -0,0 +1,118 @@
+package restconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestApply(t *testing.T) {
+	SetVersion("v1.2.3")
+	t.Cleanup(func() { SetVersion("dev") })
+
+	tests := []struct {
+		name        string
+		qps         float32
+		overrides   Overrides
+		wantQPS     float32
+		wantBurst   int
+		wantTimeout time.Duration
+	}{
+		{
+			name:      "default limits",
+			wantQPS:   DefaultQPS,
+			wantBurst: DefaultQPS,
+		},
+		{
+			name:      "client limits",
+			qps:       200,
+			wantQPS:   200,
+			wantBurst: 200,
+		},
+		{
+			name:      "qps override sets burst",
+			qps:       200,
+			overrides: Overrides{QPS: 20},
+			wantQPS:   20,
+			wantBurst: 20,
+		},
+		{
+			name:        "burst and timeout overrides",
+			qps:         200,
+			overrides:   Overrides{Burst: 500, Timeout: 30 * time.Second},
+			wantQPS:     200,
+			wantBurst:   500,
+			wantTimeout: 30 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &rest.Config{}
+			apply(config, tt.qps, []Option{WithOverrides(&tt.overrides)})
+			if config.QPS != tt.wantQPS || config.Burst != tt.wantBurst || config.Timeout != tt.wantTimeout {
+				t.Errorf("apply() = QPS %v, burst %d, timeout %s; want QPS %v, burst %d, timeout %s",
+					config.QPS, config.Burst, config.Timeout, tt.wantQPS, tt.wantBurst, tt.wantTimeout)
+			}
+			if config.UserAgent != "kueue-bench/v1.2.3" {
+				t.Errorf("apply() user agent = %q, want %q", config.UserAgent, "kueue-bench/v1.2.3")
+			}
+		})
+	}
+}
+
+func TestFlattenKubeconfig(t *testing.T) {
+	dir := t.TempDir()
+	ca := []byte("-----BEGIN CERTIFICATE-----\nca\n-----END CERTIFICATE-----\n")
+	if err := os.WriteFile(filepath.Join(dir, "ca.crt"), ca, 0600); err != nil {
+		t.Fatal(err)
+	}
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- name: staging
+  cluster:
+    server: https://staging.example.com
+    certificate-authority: ca.crt
+users:
+- name: admin
+  user:
+    token: secret
+contexts:
+- name: staging
+  context:
+    cluster: staging
+    user: admin
+current-context: staging
+`
+	path := filepath.Join(dir, "staging.kubeconfig")
+	if err := os.WriteFile(path, []byte(kubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Run from elsewhere, so the relative path only resolves against the kubeconfig
+	t.Chdir(t.TempDir())
+	data, err := FlattenKubeconfig(path)
+	if err != nil {
+		t.Fatalf("FlattenKubeconfig() error = %v", err)
+	}
+	flattened, err := clientcmd.Load(data)
+	if err != nil {
+		t.Fatalf("failed to load the flattened kubeconfig: %v", err)
+	}
+	cluster := flattened.Clusters["staging"]
+	if cluster == nil || cluster.CertificateAuthority != "" || string(cluster.CertificateAuthorityData) != string(ca) {
+		t.Errorf("flattened cluster = %+v, want the CA inlined", cluster)
+	}
+	if flattened.CurrentContext != "staging" || flattened.AuthInfos["admin"].Token != "secret" {
+		t.Errorf("flattened kubeconfig lost its context or credentials: %+v", flattened)
+	}
+
+	if _, err := FlattenKubeconfig(filepath.Join(dir, "missing.kubeconfig")); err == nil {
+		t.Error("FlattenKubeconfig() of a missing file succeeded")
+	}
+}
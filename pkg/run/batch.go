@@ -0,0 +1,105 @@
+package run
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/state"
+)
+
+// BatchReport consolidates the runs of a scenario queue executed against a topology
+type BatchReport struct {
+	ID         string        `json:"id"`
+	Topology   string        `json:"topology"`
+	StartedAt  time.Time     `json:"startedAt"`
+	FinishedAt time.Time     `json:"finishedAt"`
+	Results    []BatchResult `json:"results"`
+}
+
+// BatchResult is the outcome of one scenario of a batch
+type BatchResult struct {
+	ProfilePath string `json:"profilePath"`
+	ProfileName string `json:"profileName,omitempty"`
+	// RunID identifies the scenario's saved run; empty if the scenario failed before starting
+	RunID         string `json:"runID,omitempty"`
+	WorkloadCount int    `json:"workloadCount"`
+	Duration      string `json:"duration,omitempty"`
+	// Latency is the run's latency summary; nil if no timelines were recorded
+	Latency *TimelineSummary `json:"latency,omitempty"`
+	// Error is set if the scenario or the reset after it failed
+	Error string `json:"error,omitempty"`
+}
+
+// Failed returns the number of scenarios that failed
+func (r *BatchReport) Failed() int {
+	failed := 0
+	for _, result := range r.Results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	return failed
+}
+
+// SaveBatch persists a batch report in the default state directory; see Store.SaveBatch
+func SaveBatch(report *BatchReport) error {
+	store, err := OpenStore()
+	if err != nil {
+		return err
+	}
+	return store.SaveBatch(report)
+}
+
+// LoadBatch reads a batch report from the default state directory; see Store.LoadBatch
+func LoadBatch(id string) (*BatchReport, error) {
+	store, err := OpenStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.LoadBatch(id)
+}
+
+// ListBatches returns the batch reports in the default state directory; see Store.ListBatches
+func ListBatches() ([]*BatchReport, error) {
+	store, err := OpenStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.ListBatches()
+}
+
+// SaveBatch persists a batch report to batches/<id>/metadata.json in the state directory
+func (s *Store) SaveBatch(report *BatchReport) error {
+	return s.state.Save(state.Batches, report.ID, report)
+}
+
+// LoadBatch reads a batch report from disk
+func (s *Store) LoadBatch(id string) (*BatchReport, error) {
+	var report BatchReport
+	if err := s.state.Load(state.Batches, id, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ListBatches returns all saved batch reports, newest first
+func (s *Store) ListBatches() ([]*BatchReport, error) {
+	names, err := s.state.Names(state.Batches)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := []*BatchReport{}
+	for _, name := range names {
+		var report BatchReport
+		if err := s.state.Load(state.Batches, name, &report); err != nil {
+			continue
+		}
+		reports = append(reports, &report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].StartedAt.After(reports[j].StartedAt)
+	})
+	return reports, nil
+}
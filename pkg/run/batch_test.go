@@ -0,0 +1,61 @@
+package run
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/state"
+)
+
+func TestSaveAndLoadBatch(t *testing.T) {
+	t.Setenv(state.DirEnv, t.TempDir())
+
+	started := time.Date(2026, 10, 1, 22, 0, 0, 0, time.UTC)
+	older := &BatchReport{ID: "older", Topology: "bench", StartedAt: started.Add(-24 * time.Hour)}
+	report := &BatchReport{
+		ID:         "nightly",
+		Topology:   "bench",
+		StartedAt:  started,
+		FinishedAt: started.Add(2 * time.Hour),
+		Results: []BatchResult{
+			{
+				ProfilePath:   "/profiles/mix.yaml",
+				ProfileName:   "mix",
+				RunID:         "abc12345",
+				WorkloadCount: 100,
+				Duration:      "10m0s",
+				Latency:       &TimelineSummary{Admission: LatencyStats{Count: 100, P50: time.Second}},
+			},
+			{ProfilePath: "/profiles/broken.yaml", Error: "invalid workload profile"},
+		},
+	}
+	for _, r := range []*BatchReport{older, report} {
+		if err := SaveBatch(r); err != nil {
+			t.Fatalf("SaveBatch() error: %v", err)
+		}
+	}
+
+	loaded, err := LoadBatch("nightly")
+	if err != nil {
+		t.Fatalf("LoadBatch() error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, report) {
+		t.Errorf("LoadBatch() = %+v, want %+v", loaded, report)
+	}
+	if got := loaded.Failed(); got != 1 {
+		t.Errorf("Failed() = %d, want 1", got)
+	}
+
+	reports, err := ListBatches()
+	if err != nil {
+		t.Fatalf("ListBatches() error: %v", err)
+	}
+	if len(reports) != 2 || reports[0].ID != "nightly" || reports[1].ID != "older" {
+		t.Errorf("ListBatches() returned %d reports, want nightly then older", len(reports))
+	}
+
+	if _, err := LoadBatch("missing"); err == nil {
+		t.Error("LoadBatch() of a missing batch should fail")
+	}
+}
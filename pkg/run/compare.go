@@ -0,0 +1,203 @@
+package run
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// DefaultAlpha is the significance level CompareRuns tests at unless told otherwise
+const DefaultAlpha = 0.05
+
+// SampleStats summarizes one latency percentile over repeated runs
+type SampleStats struct {
+	N      int           `json:"n"`
+	Mean   time.Duration `json:"mean"`
+	Stddev time.Duration `json:"stddev"`
+}
+
+// MetricComparison compares a latency percentile between two sets of repeated runs
+type MetricComparison struct {
+	Metric    string      `json:"metric"`
+	Baseline  SampleStats `json:"baseline"`
+	Candidate SampleStats `json:"candidate"`
+	// Change is the candidate mean relative to the baseline mean, e.g. -0.2 for 20% lower
+	Change float64 `json:"change"`
+	// PValue is the two-sided p-value of Welch's t-test; NaN unless both sides have at
+	// least two runs
+	PValue float64 `json:"pValue"`
+	// Significant reports PValue < alpha
+	Significant bool `json:"significant"`
+}
+
+// RunComparison compares the latency percentiles of repeated runs of two configurations
+type RunComparison struct {
+	Baseline  []string           `json:"baseline"`
+	Candidate []string           `json:"candidate"`
+	Alpha     float64            `json:"alpha"`
+	Metrics   []MetricComparison `json:"metrics"`
+}
+
+// comparedMetrics are the latency percentiles CompareRuns compares, in report order
+var comparedMetrics = []struct {
+	name  string
+	value func(*TimelineSummary) time.Duration
+}{
+	{"admission p50", func(s *TimelineSummary) time.Duration { return s.Admission.P50 }},
+	{"admission p95", func(s *TimelineSummary) time.Duration { return s.Admission.P95 }},
+	{"end-to-end p50", func(s *TimelineSummary) time.Duration { return s.EndToEnd.P50 }},
+	{"end-to-end p95", func(s *TimelineSummary) time.Duration { return s.EndToEnd.P95 }},
+}
+
+// CompareRuns compares the latency percentiles of baseline and candidate runs, each
+// typically repetitions of one configuration. Each percentile is treated as one sample
+// per run, and the samples are compared with Welch's t-test at significance level alpha.
+func CompareRuns(baseline, candidate []*RunMetadata, alpha float64) (*RunComparison, error) {
+	if len(baseline) == 0 || len(candidate) == 0 {
+		return nil, fmt.Errorf("both baseline and candidate need at least one run")
+	}
+	for _, meta := range append(append([]*RunMetadata{}, baseline...), candidate...) {
+		if meta.Latency == nil {
+			return nil, fmt.Errorf("run %s recorded no latency (dry run or timelines not recorded)", meta.RunID)
+		}
+	}
+
+	comparison := &RunComparison{Alpha: alpha}
+	for _, meta := range baseline {
+		comparison.Baseline = append(comparison.Baseline, meta.RunID)
+	}
+	for _, meta := range candidate {
+		comparison.Candidate = append(comparison.Candidate, meta.RunID)
+	}
+
+	for _, metric := range comparedMetrics {
+		a := metricSamples(baseline, metric.value)
+		b := metricSamples(candidate, metric.value)
+		c := MetricComparison{
+			Metric:    metric.name,
+			Baseline:  sampleStats(a),
+			Candidate: sampleStats(b),
+			PValue:    welchTTest(a, b),
+		}
+		if c.Baseline.Mean > 0 {
+			c.Change = float64(c.Candidate.Mean-c.Baseline.Mean) / float64(c.Baseline.Mean)
+		}
+		c.Significant = c.PValue < alpha
+		comparison.Metrics = append(comparison.Metrics, c)
+	}
+	return comparison, nil
+}
+
+// metricSamples returns a metric of each run in seconds
+func metricSamples(runs []*RunMetadata, value func(*TimelineSummary) time.Duration) []float64 {
+	samples := make([]float64, len(runs))
+	for i, meta := range runs {
+		samples[i] = value(meta.Latency).Seconds()
+	}
+	return samples
+}
+
+// meanVariance returns the mean and unbiased sample variance of samples
+func meanVariance(samples []float64) (mean, variance float64) {
+	for _, x := range samples {
+		mean += x
+	}
+	mean /= float64(len(samples))
+	if len(samples) < 2 {
+		return mean, 0
+	}
+	for _, x := range samples {
+		variance += (x - mean) * (x - mean)
+	}
+	return mean, variance / float64(len(samples)-1)
+}
+
+func sampleStats(samples []float64) SampleStats {
+	mean, variance := meanVariance(samples)
+	return SampleStats{
+		N:      len(samples),
+		Mean:   time.Duration(mean * float64(time.Second)),
+		Stddev: time.Duration(math.Sqrt(variance) * float64(time.Second)),
+	}
+}
+
+// welchTTest returns the two-sided p-value of Welch's t-test for a difference in the
+// means of a and b, or NaN if either has fewer than two samples
+func welchTTest(a, b []float64) float64 {
+	if len(a) < 2 || len(b) < 2 {
+		return math.NaN()
+	}
+	meanA, varA := meanVariance(a)
+	meanB, varB := meanVariance(b)
+	seA, seB := varA/float64(len(a)), varB/float64(len(b))
+	if seA+seB == 0 {
+		// Both sides are constant: the means either differ or they do not
+		if meanA == meanB {
+			return 1
+		}
+		return 0
+	}
+
+	t := (meanA - meanB) / math.Sqrt(seA+seB)
+	df := (seA + seB) * (seA + seB) / (seA*seA/float64(len(a)-1) + seB*seB/float64(len(b)-1))
+	return regularizedIncompleteBeta(df/(df+t*t), df/2, 0.5)
+}
+
+// regularizedIncompleteBeta returns I_x(a, b), evaluated with the continued fraction of
+// Numerical Recipes (betai/betacf)
+func regularizedIncompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	lgab, _ := math.Lgamma(a + b)
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+	// The continued fraction converges quickly for x below (a+1)/(a+b+2); use the
+	// symmetry I_x(a, b) = 1 - I_{1-x}(b, a) above it
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+// betaContinuedFraction evaluates the continued fraction of the incomplete beta function
+// with the modified Lentz method
+func betaContinuedFraction(x, a, b float64) float64 {
+	const (
+		maxIterations = 200
+		epsilon       = 1e-14
+		tiny          = 1e-300
+	)
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= maxIterations; m++ {
+		fm := float64(m)
+		for _, num := range []float64{
+			fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm)),
+			-(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1)),
+		} {
+			d = 1 + num*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + num/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			h *= d * c
+		}
+		if math.Abs(d*c-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
@@ -0,0 +1,80 @@
+package run
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWelchTTest(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float64
+		want float64
+	}{
+		// t = -5, 8 degrees of freedom
+		{name: "equal variances", a: []float64{1, 2, 3, 4, 5}, b: []float64{6, 7, 8, 9, 10}, want: 0.0010528},
+		// t = -4.70, 6.98 degrees of freedom
+		{name: "unequal sizes", a: []float64{10, 12, 11, 13}, b: []float64{14, 15, 17, 16, 18}, want: 0.0022246},
+		{name: "same samples", a: []float64{1, 2, 3}, b: []float64{3, 2, 1}, want: 1},
+		{name: "constant and different", a: []float64{2, 2}, b: []float64{3, 3}, want: 0},
+		{name: "single run", a: []float64{1}, b: []float64{2, 3}, want: math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := welchTTest(tt.a, tt.b)
+			if math.IsNaN(tt.want) {
+				if !math.IsNaN(got) {
+					t.Errorf("welchTTest() = %v, want NaN", got)
+				}
+				return
+			}
+			if math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("welchTTest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// latencyRun returns a run whose admission and end-to-end percentiles are all p
+func latencyRun(id string, p time.Duration) *RunMetadata {
+	stats := LatencyStats{Count: 10, P50: p, P95: p}
+	return &RunMetadata{RunID: id, Latency: &TimelineSummary{Admission: stats, EndToEnd: stats}}
+}
+
+func TestCompareRuns(t *testing.T) {
+	baseline := []*RunMetadata{latencyRun("a", 10*time.Second), latencyRun("b", 12*time.Second), latencyRun("c", 11*time.Second)}
+	candidate := []*RunMetadata{latencyRun("d", 5*time.Second), latencyRun("e", 6*time.Second), latencyRun("f", 4*time.Second)}
+
+	comparison, err := CompareRuns(baseline, candidate, DefaultAlpha)
+	if err != nil {
+		t.Fatalf("CompareRuns() error = %v", err)
+	}
+	if len(comparison.Metrics) != len(comparedMetrics) {
+		t.Fatalf("got %d metrics, want %d", len(comparison.Metrics), len(comparedMetrics))
+	}
+	m := comparison.Metrics[0]
+	if m.Baseline.N != 3 || m.Baseline.Mean != 11*time.Second || m.Baseline.Stddev != time.Second {
+		t.Errorf("baseline = %+v, want 3 runs, 11s ± 1s", m.Baseline)
+	}
+	if math.Abs(m.Change+0.5454545) > 1e-6 || !m.Significant {
+		t.Errorf("comparison = %+v, want a significant 54.5%% decrease", m)
+	}
+
+	// Overlapping runs are not significantly different
+	noisy := []*RunMetadata{latencyRun("g", 9*time.Second), latencyRun("h", 13*time.Second), latencyRun("i", 11*time.Second)}
+	comparison, err = CompareRuns(baseline, noisy, DefaultAlpha)
+	if err != nil {
+		t.Fatalf("CompareRuns() error = %v", err)
+	}
+	if comparison.Metrics[0].Significant {
+		t.Errorf("comparison = %+v, want no significant difference", comparison.Metrics[0])
+	}
+
+	if _, err := CompareRuns(baseline, []*RunMetadata{{RunID: "dry"}}, DefaultAlpha); err == nil {
+		t.Error("expected an error for a run without latency")
+	}
+	if _, err := CompareRuns(nil, candidate, DefaultAlpha); err == nil {
+		t.Error("expected an error without baseline runs")
+	}
+}
@@ -0,0 +1,96 @@
+package run
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/kueue"
+)
+
+// CostSummary prices each ClusterQueue's capacity over a run from the hourly cost of the
+// node pools backing its flavors
+type CostSummary struct {
+	// Duration is the time the samples span
+	Duration time.Duration `json:"duration"`
+	Queues   []QueueCost   `json:"queues"`
+	// Total sums the queues; its utilization is weighted by each queue's nominal cost
+	Total QueueCost `json:"total"`
+}
+
+// QueueCost is the cost of one ClusterQueue's capacity over a run
+type QueueCost struct {
+	ClusterQueue string `json:"clusterQueue"`
+	// NominalCost is the cost of the queue's nominal quota
+	NominalCost float64 `json:"nominalCost"`
+	// UsedCost is the cost of the quota its workloads used, including borrowed quota
+	UsedCost float64 `json:"usedCost"`
+	// IdleCost is the cost of nominal quota the queue left unused
+	IdleCost float64 `json:"idleCost"`
+	// Utilization is UsedCost over NominalCost; above 1 when the queue borrowed
+	Utilization float64 `json:"utilization"`
+}
+
+// CostTracker integrates sampled ClusterQueue cost rates over a run. Each sample's rates
+// apply until the next sample.
+type CostTracker struct {
+	first, last time.Time
+	rates       map[string]kueue.QueueCostRate
+	costs       map[string]*QueueCost
+}
+
+// NewCostTracker returns an empty CostTracker
+func NewCostTracker() *CostTracker {
+	return &CostTracker{costs: make(map[string]*QueueCost)}
+}
+
+// Sample records the cost rates of every ClusterQueue at a point in time
+func (t *CostTracker) Sample(at time.Time, rates map[string]kueue.QueueCostRate) {
+	if t.rates != nil {
+		hours := at.Sub(t.last).Hours()
+		for name, rate := range t.rates {
+			cost := t.costs[name]
+			cost.NominalCost += rate.Nominal * hours
+			cost.UsedCost += rate.Used * hours
+			cost.IdleCost += max(rate.Nominal-rate.Used, 0) * hours
+		}
+	} else {
+		t.first = at
+	}
+	for name := range rates {
+		if t.costs[name] == nil {
+			t.costs[name] = &QueueCost{ClusterQueue: name}
+		}
+	}
+	t.last = at
+	t.rates = rates
+}
+
+// Summary returns the costs accumulated so far, sorted by ClusterQueue name; nil before
+// two samples were recorded
+func (t *CostTracker) Summary() *CostSummary {
+	if !t.last.After(t.first) {
+		return nil
+	}
+	summary := &CostSummary{Duration: t.last.Sub(t.first), Queues: make([]QueueCost, 0, len(t.costs))}
+	for _, cost := range t.costs {
+		q := *cost
+		q.Utilization = utilization(q.UsedCost, q.NominalCost)
+		summary.Queues = append(summary.Queues, q)
+		summary.Total.NominalCost += q.NominalCost
+		summary.Total.UsedCost += q.UsedCost
+		summary.Total.IdleCost += q.IdleCost
+	}
+	sort.Slice(summary.Queues, func(i, j int) bool {
+		return summary.Queues[i].ClusterQueue < summary.Queues[j].ClusterQueue
+	})
+	summary.Total.Utilization = utilization(summary.Total.UsedCost, summary.Total.NominalCost)
+	return summary
+}
+
+// utilization returns used over nominal, or 0 without nominal cost
+func utilization(used, nominal float64) float64 {
+	if nominal == 0 {
+		return 0
+	}
+	return used / nominal
+}
@@ -0,0 +1,56 @@
+package run
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/kueue"
+)
+
+func TestCostTracker(t *testing.T) {
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewCostTracker()
+	if tracker.Summary() != nil {
+		t.Fatal("Summary() before any sample should be nil")
+	}
+
+	// One hour idle, then one hour with training half used and inference borrowing
+	tracker.Sample(start, map[string]kueue.QueueCostRate{
+		"training":  {Nominal: 10},
+		"inference": {Nominal: 30},
+	})
+	tracker.Sample(start.Add(time.Hour), map[string]kueue.QueueCostRate{
+		"training":  {Nominal: 10, Used: 5},
+		"inference": {Nominal: 30, Used: 45},
+	})
+	tracker.Sample(start.Add(2*time.Hour), nil)
+
+	summary := tracker.Summary()
+	if summary.Duration != 2*time.Hour {
+		t.Errorf("Duration = %s, want 2h", summary.Duration)
+	}
+	want := []QueueCost{
+		{ClusterQueue: "inference", NominalCost: 60, UsedCost: 45, IdleCost: 30, Utilization: 0.75},
+		{ClusterQueue: "training", NominalCost: 20, UsedCost: 5, IdleCost: 15, Utilization: 0.25},
+	}
+	if len(summary.Queues) != len(want) {
+		t.Fatalf("Queues = %+v, want %+v", summary.Queues, want)
+	}
+	for i, w := range want {
+		if !costEqual(summary.Queues[i], w) {
+			t.Errorf("Queues[%d] = %+v, want %+v", i, summary.Queues[i], w)
+		}
+	}
+	total := QueueCost{NominalCost: 80, UsedCost: 50, IdleCost: 45, Utilization: 0.625}
+	if !costEqual(summary.Total, total) {
+		t.Errorf("Total = %+v, want %+v", summary.Total, total)
+	}
+}
+
+// costEqual compares queue costs with a tolerance for floating point error
+func costEqual(a, b QueueCost) bool {
+	near := func(x, y float64) bool { return math.Abs(x-y) < 1e-9 }
+	return a.ClusterQueue == b.ClusterQueue && near(a.NominalCost, b.NominalCost) &&
+		near(a.UsedCost, b.UsedCost) && near(a.IdleCost, b.IdleCost) && near(a.Utilization, b.Utilization)
+}
@@ -0,0 +1,165 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/state"
+)
+
+// RetentionPolicy bounds the runs kept in the state directory. Zero fields are unlimited.
+type RetentionPolicy struct {
+	// MaxRuns keeps at most this many of the newest runs
+	MaxRuns int
+	// MaxAge removes runs started longer ago than this
+	MaxAge time.Duration
+	// MaxBytes keeps the newest runs whose directories fit in this many bytes together
+	MaxBytes int64
+}
+
+// IsZero reports whether the policy sets no limit
+func (p RetentionPolicy) IsZero() bool {
+	return p.MaxRuns <= 0 && p.MaxAge <= 0 && p.MaxBytes <= 0
+}
+
+// RunUsage is a saved run's start time and the disk space of its directory
+type RunUsage struct {
+	RunID     string
+	StartedAt time.Time
+	Bytes     int64
+}
+
+// GCResult describes the runs GC removed, or would remove when dryRun is set
+type GCResult struct {
+	Removed []RunUsage
+	// Kept is the number of runs left in place
+	Kept int
+	// FreedBytes is the disk space of the removed runs
+	FreedBytes int64
+	// RemovedBatches are the IDs of the batch reports removed with their runs
+	RemovedBatches []string
+}
+
+// GC collects the runs in the default state directory; see Store.GC
+func GC(policy RetentionPolicy, dryRun bool) (*GCResult, error) {
+	s, err := OpenStore()
+	if err != nil {
+		return nil, err
+	}
+	return s.GC(policy, dryRun)
+}
+
+// GC removes the saved runs, with their timelines and other artifacts, that the policy
+// does not retain. Runs whose metadata cannot be read are aged by their directory's
+// modification time, so broken runs are collected too. A batch report is removed with the
+// last of its runs; one without runs (every scenario failed before starting) only by
+// MaxAge. Golden snapshots and the download cache are not runs and are left alone. With
+// dryRun nothing is removed.
+func (s *Store) GC(policy RetentionPolicy, dryRun bool) (*GCResult, error) {
+	store := s.state
+	names, err := store.Names(state.Runs)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]RunUsage, 0, len(names))
+	for _, name := range names {
+		usage := RunUsage{RunID: name}
+		var meta RunMetadata
+		dir, err := store.Dir(state.Runs, name)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.Load(state.Runs, name, &meta); err == nil {
+			usage.StartedAt = meta.StartedAt
+		} else if info, err := os.Stat(dir); err == nil {
+			usage.StartedAt = info.ModTime()
+		}
+		if usage.Bytes, err = store.Size(state.Runs, name); err != nil {
+			return nil, err
+		}
+		runs = append(runs, usage)
+	}
+
+	now := time.Now()
+	remove, keep := selectExpired(runs, policy, now)
+	result := &GCResult{Removed: remove, Kept: len(keep)}
+	for _, r := range remove {
+		if !dryRun {
+			if err := store.Remove(state.Runs, r.RunID); err != nil {
+				return result, fmt.Errorf("failed to remove run %s: %w", r.RunID, err)
+			}
+		}
+		result.FreedBytes += r.Bytes
+	}
+
+	batches, err := store.Names(state.Batches)
+	if err != nil {
+		return result, err
+	}
+	kept := make(map[string]bool, len(keep))
+	for _, r := range keep {
+		kept[r.RunID] = true
+	}
+	for _, id := range batches {
+		var report BatchReport
+		if err := store.Load(state.Batches, id, &report); err != nil || !batchExpired(&report, kept, policy, now) {
+			continue
+		}
+		if !dryRun {
+			if err := store.Remove(state.Batches, id); err != nil {
+				return result, fmt.Errorf("failed to remove batch %s: %w", id, err)
+			}
+		}
+		result.RemovedBatches = append(result.RemovedBatches, id)
+	}
+	return result, nil
+}
+
+// batchExpired reports whether none of a batch's runs is kept, or, for a batch without
+// runs, whether it is older than MaxAge
+func batchExpired(report *BatchReport, kept map[string]bool, policy RetentionPolicy, now time.Time) bool {
+	hasRuns := false
+	for _, r := range report.Results {
+		if r.RunID == "" {
+			continue
+		}
+		if kept[r.RunID] {
+			return false
+		}
+		hasRuns = true
+	}
+	if hasRuns {
+		return true
+	}
+	return policy.MaxAge > 0 && now.Sub(report.StartedAt) > policy.MaxAge
+}
+
+// selectExpired splits runs into those the policy removes and those it keeps, both
+// newest first. Once the newest runs fill MaxBytes, every older run is removed.
+func selectExpired(runs []RunUsage, policy RetentionPolicy, now time.Time) (remove, keep []RunUsage) {
+	sorted := append([]RunUsage(nil), runs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartedAt.After(sorted[j].StartedAt)
+	})
+
+	var total int64
+	overBudget := false
+	for _, r := range sorted {
+		expired := (policy.MaxRuns > 0 && len(keep) >= policy.MaxRuns) ||
+			(policy.MaxAge > 0 && now.Sub(r.StartedAt) > policy.MaxAge)
+		if !expired && policy.MaxBytes > 0 {
+			overBudget = overBudget || total+r.Bytes > policy.MaxBytes
+			expired = overBudget
+		}
+		if expired {
+			remove = append(remove, r)
+			continue
+		}
+		total += r.Bytes
+		keep = append(keep, r)
+	}
+	return remove, keep
+}
@@ -0,0 +1,139 @@
+package run
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/state"
+)
+
+func TestSelectExpired(t *testing.T) {
+	now := time.Date(2026, 4, 10, 12, 0, 0, 0, time.UTC)
+	runs := []RunUsage{
+		{RunID: "day-3", StartedAt: now.Add(-72 * time.Hour), Bytes: 100},
+		{RunID: "hour-1", StartedAt: now.Add(-time.Hour), Bytes: 300},
+		{RunID: "day-1", StartedAt: now.Add(-24 * time.Hour), Bytes: 100},
+		{RunID: "week-1", StartedAt: now.Add(-7 * 24 * time.Hour), Bytes: 50},
+	}
+
+	tests := []struct {
+		name   string
+		policy RetentionPolicy
+		want   []string
+	}{
+		{name: "no limits", want: nil},
+		{name: "max runs", policy: RetentionPolicy{MaxRuns: 2}, want: []string{"day-3", "week-1"}},
+		{name: "max age", policy: RetentionPolicy{MaxAge: 48 * time.Hour}, want: []string{"day-3", "week-1"}},
+		{
+			// week-1 would fit in the remaining budget, but runs older than day-3 go with it
+			name:   "max bytes removes everything older than the first run over budget",
+			policy: RetentionPolicy{MaxBytes: 450},
+			want:   []string{"day-3", "week-1"},
+		},
+		{name: "combined", policy: RetentionPolicy{MaxRuns: 3, MaxAge: 96 * time.Hour}, want: []string{"week-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remove, keep := selectExpired(runs, tt.policy, now)
+			var got []string
+			for _, r := range remove {
+				got = append(got, r.RunID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("removed = %v, want %v", got, tt.want)
+			}
+			if len(remove)+len(keep) != len(runs) {
+				t.Errorf("removed %d + kept %d runs, want %d", len(remove), len(keep), len(runs))
+			}
+		})
+	}
+}
+
+func TestGC(t *testing.T) {
+	t.Setenv(state.DirEnv, t.TempDir())
+	now := time.Now()
+	for i, id := range []string{"run-old", "run-mid", "run-new"} {
+		meta := &RunMetadata{RunID: id, StartedAt: now.Add(time.Duration(i-3) * time.Hour)}
+		if err := Save(meta); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := GC(RetentionPolicy{MaxRuns: 1}, true)
+	if err != nil {
+		t.Fatalf("GC(dry run) error: %v", err)
+	}
+	if len(result.Removed) != 2 || result.Kept != 1 || result.FreedBytes == 0 {
+		t.Errorf("GC(dry run) = %+v, want 2 removed and 1 kept", result)
+	}
+	if runs, _ := List(); len(runs) != 3 {
+		t.Fatalf("dry run removed runs: %d left, want 3", len(runs))
+	}
+
+	if _, err := GC(RetentionPolicy{MaxRuns: 1}, false); err != nil {
+		t.Fatalf("GC() error: %v", err)
+	}
+	runs, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].RunID != "run-new" {
+		t.Errorf("runs after GC = %v, want only run-new", runs)
+	}
+	store, err := state.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := store.Dir(state.Runs, "run-old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("run-old directory still exists: %v", err)
+	}
+}
+
+func TestGCBatches(t *testing.T) {
+	t.Setenv(state.DirEnv, t.TempDir())
+	now := time.Now()
+	for i, id := range []string{"run-old", "run-mid", "run-new"} {
+		meta := &RunMetadata{RunID: id, StartedAt: now.Add(time.Duration(i-3) * time.Hour)}
+		if err := Save(meta); err != nil {
+			t.Fatal(err)
+		}
+	}
+	batches := []*BatchReport{
+		{ID: "batch-old", StartedAt: now.Add(-3 * time.Hour), Results: []BatchResult{{RunID: "run-old"}, {RunID: "run-mid"}}},
+		{ID: "batch-mixed", StartedAt: now.Add(-2 * time.Hour), Results: []BatchResult{{RunID: "run-mid"}, {RunID: "run-new"}}},
+		{ID: "batch-failed-old", StartedAt: now.Add(-48 * time.Hour), Results: []BatchResult{{Error: "failed"}}},
+		{ID: "batch-failed-new", StartedAt: now.Add(-time.Hour), Results: []BatchResult{{Error: "failed"}}},
+	}
+	for _, b := range batches {
+		if err := SaveBatch(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := GC(RetentionPolicy{MaxRuns: 1, MaxAge: 24 * time.Hour}, false)
+	if err != nil {
+		t.Fatalf("GC() error: %v", err)
+	}
+	want := []string{"batch-failed-old", "batch-old"}
+	if !reflect.DeepEqual(result.RemovedBatches, want) {
+		t.Errorf("removed batches = %v, want %v", result.RemovedBatches, want)
+	}
+
+	reports, err := ListBatches()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var left []string
+	for _, r := range reports {
+		left = append(left, r.ID)
+	}
+	if wantLeft := []string{"batch-failed-new", "batch-mixed"}; !reflect.DeepEqual(left, wantLeft) {
+		t.Errorf("batches after GC = %v, want %v", left, wantLeft)
+	}
+}
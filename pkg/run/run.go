@@ -1,91 +1,85 @@
 package run
 
 import (
-	"encoding/json"
-	"fmt"
-	"os"
-	"path/filepath"
 	"sort"
-)
 
-const (
-	metadataDir      = ".kueue-bench/runs"
-	metadataFilename = "metadata.json"
+	"github.com/jhwagner/kueue-bench/pkg/state"
 )
 
-// Save persists run metadata to ~/.kueue-bench/runs/<runID>/metadata.json.
-func Save(meta *RunMetadata) error {
-	runDir, err := getRunDir(meta.RunID)
-	if err != nil {
-		return err
-	}
+// Store keeps run metadata, timelines and batch reports in a state directory
+type Store struct {
+	state *state.Store
+}
 
-	if err := os.MkdirAll(runDir, 0750); err != nil {
-		return fmt.Errorf("failed to create run directory: %w", err)
-	}
+// NewStore returns a Store keeping runs in s
+func NewStore(s *state.Store) *Store {
+	return &Store{state: s}
+}
 
-	data, err := json.MarshalIndent(meta, "", "  ")
+// OpenStore opens the Store in the default state directory (see state.Root)
+func OpenStore() (*Store, error) {
+	s, err := state.Open()
 	if err != nil {
-		return fmt.Errorf("failed to marshal run metadata: %w", err)
+		return nil, err
 	}
+	return NewStore(s), nil
+}
 
-	metadataPath := filepath.Join(runDir, metadataFilename)
-	if err := os.WriteFile(metadataPath, data, 0600); err != nil {
-		return fmt.Errorf("failed to write run metadata: %w", err)
+// Save persists run metadata in the default state directory; see Store.Save
+func Save(meta *RunMetadata) error {
+	store, err := OpenStore()
+	if err != nil {
+		return err
 	}
-
-	return nil
+	return store.Save(meta)
 }
 
-// Load reads run metadata from disk for the given run ID.
+// Load reads run metadata from the default state directory; see Store.Load
 func Load(runID string) (*RunMetadata, error) {
-	runDir, err := getRunDir(runID)
+	store, err := OpenStore()
 	if err != nil {
 		return nil, err
 	}
+	return store.Load(runID)
+}
 
-	metadataPath := filepath.Join(runDir, metadataFilename)
-	data, err := os.ReadFile(metadataPath) //nolint:gosec // path is constructed from known base directory
+// List returns the runs in the default state directory; see Store.List
+func List() ([]*RunMetadata, error) {
+	store, err := OpenStore()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read run metadata: %w", err)
+		return nil, err
 	}
+	return store.List()
+}
 
+// Save persists run metadata to runs/<runID>/metadata.json in the state directory.
+func (s *Store) Save(meta *RunMetadata) error {
+	return s.state.Save(state.Runs, meta.RunID, meta)
+}
+
+// Load reads run metadata from disk for the given run ID.
+func (s *Store) Load(runID string) (*RunMetadata, error) {
 	var meta RunMetadata
-	if err := json.Unmarshal(data, &meta); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal run metadata: %w", err)
+	if err := s.state.Load(state.Runs, runID, &meta); err != nil {
+		return nil, err
 	}
-
 	return &meta, nil
 }
 
 // List returns all saved run metadata, sorted by StartedAt descending (newest first).
-func List() ([]*RunMetadata, error) {
-	home, err := os.UserHomeDir()
+func (s *Store) List() ([]*RunMetadata, error) {
+	names, err := s.state.Names(state.Runs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
-	}
-
-	runsDir := filepath.Join(home, metadataDir)
-	entries, err := os.ReadDir(runsDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return []*RunMetadata{}, nil
-		}
-		return nil, fmt.Errorf("failed to read runs directory: %w", err)
+		return nil, err
 	}
 
-	var runs []*RunMetadata
-	for _, entry := range entries {
-		if !entry.IsDir() {
+	runs := []*RunMetadata{}
+	for _, name := range names {
+		var meta RunMetadata
+		if err := s.state.Load(state.Runs, name, &meta); err != nil {
 			continue
 		}
-
-		meta, err := Load(entry.Name())
-		if err != nil {
-			continue
-		}
-
-		runs = append(runs, meta)
+		runs = append(runs, &meta)
 	}
 
 	sort.Slice(runs, func(i, j int) bool {
@@ -94,11 +88,3 @@ func List() ([]*RunMetadata, error) {
 
 	return runs, nil
 }
-
-func getRunDir(runID string) (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
-	}
-	return filepath.Join(home, metadataDir, runID), nil
-}
@@ -5,12 +5,14 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/state"
 )
 
 func TestSaveAndLoad(t *testing.T) {
-	// Override HOME to use a temp directory
+	// Use a temp state directory
 	tmp := t.TempDir()
-	t.Setenv("HOME", tmp)
+	t.Setenv(state.DirEnv, tmp)
 
 	meta := &RunMetadata{
 		RunID:         "test1234",
@@ -30,7 +32,7 @@ func TestSaveAndLoad(t *testing.T) {
 	}
 
 	// Verify file exists
-	metaPath := filepath.Join(tmp, metadataDir, "test1234", metadataFilename)
+	metaPath := filepath.Join(tmp, "runs", "test1234", "metadata.json")
 	if _, err := os.Stat(metaPath); err != nil {
 		t.Fatalf("metadata file not found: %v", err)
 	}
@@ -62,7 +64,7 @@ func TestSaveAndLoad(t *testing.T) {
 
 func TestListEmpty(t *testing.T) {
 	tmp := t.TempDir()
-	t.Setenv("HOME", tmp)
+	t.Setenv(state.DirEnv, tmp)
 
 	runs, err := List()
 	if err != nil {
@@ -75,7 +77,7 @@ func TestListEmpty(t *testing.T) {
 
 func TestListMultipleSortedByStartedAt(t *testing.T) {
 	tmp := t.TempDir()
-	t.Setenv("HOME", tmp)
+	t.Setenv(state.DirEnv, tmp)
 
 	older := &RunMetadata{
 		RunID:     "run-older",
@@ -112,7 +114,7 @@ func TestListMultipleSortedByStartedAt(t *testing.T) {
 
 func TestLoadNonExistent(t *testing.T) {
 	tmp := t.TempDir()
-	t.Setenv("HOME", tmp)
+	t.Setenv(state.DirEnv, tmp)
 
 	_, err := Load("does-not-exist")
 	if err == nil {
@@ -0,0 +1,266 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/jhwagner/kueue-bench/pkg/state"
+)
+
+const timelinesFilename = "timelines.json"
+
+// WorkloadTimeline records when a submitted workload reached each lifecycle milestone,
+// from submission through Kueue admission to its pods being scheduled, running and finished.
+// Pod milestones are the latest among the workload's observed pods, so a workload counts as
+// running once its last pod is running. Unreached milestones are nil.
+type WorkloadTimeline struct {
+	Name            string     `json:"name"`
+	Namespace       string     `json:"namespace"`
+	Type            string     `json:"type"`
+	SubmittedAt     time.Time  `json:"submittedAt"`
+	QuotaReservedAt *time.Time `json:"quotaReservedAt,omitempty"`
+	AdmittedAt      *time.Time `json:"admittedAt,omitempty"`
+	Pods            int        `json:"pods"`
+	PodsScheduledAt *time.Time `json:"podsScheduledAt,omitempty"`
+	PodsRunningAt   *time.Time `json:"podsRunningAt,omitempty"`
+	FinishedAt      *time.Time `json:"finishedAt,omitempty"`
+	// AdmissionChecks are the observed state transitions of the workload's AdmissionChecks,
+	// e.g. MultiKueue's
+	AdmissionChecks []AdmissionCheckTimeline `json:"admissionChecks,omitempty"`
+	// LocalQueue, Priority and Requests describe the workload's Kueue Workload, so the
+	// run's trace can be replayed against other quotas
+	LocalQueue string `json:"localQueue,omitempty"`
+	Priority   int32  `json:"priority,omitempty"`
+	// Requests are the total resource requests of all the workload's pods
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+	// ClusterQueue is the ClusterQueue that admitted the workload; empty if not admitted
+	ClusterQueue string `json:"clusterQueue,omitempty"`
+}
+
+// AdmissionCheckTimeline records the states an AdmissionCheck went through on a workload
+type AdmissionCheckTimeline struct {
+	Name        string                 `json:"name"`
+	Transitions []CheckStateTransition `json:"transitions"`
+	// Retries is how often the check moved to Retry; Kueue's retryCount if it saw more
+	// transitions than the recorder did
+	Retries int `json:"retries"`
+}
+
+// CheckStateTransition is an AdmissionCheck entering a state (Pending, Retry, Ready, Rejected)
+type CheckStateTransition struct {
+	State string    `json:"state"`
+	At    time.Time `json:"at"`
+}
+
+// AdmissionCheck states, as reported in a Workload's status.admissionChecks
+const (
+	CheckStatePending  = "Pending"
+	CheckStateRetry    = "Retry"
+	CheckStateReady    = "Ready"
+	CheckStateRejected = "Rejected"
+)
+
+// LatencyStats summarizes the durations of one timeline stage across workloads
+type LatencyStats struct {
+	Count int           `json:"count"`
+	P50   time.Duration `json:"p50"`
+	P95   time.Duration `json:"p95"`
+	Max   time.Duration `json:"max"`
+}
+
+// TimelineSummary breaks submit-to-running latency into its Kueue and kube-scheduler legs
+type TimelineSummary struct {
+	// Admission is submission to Kueue admission
+	Admission LatencyStats `json:"admission"`
+	// Scheduling is admission to all pods bound to nodes
+	Scheduling LatencyStats `json:"scheduling"`
+	// Startup is pods bound to all pods running
+	Startup LatencyStats `json:"startup"`
+	// EndToEnd is submission to all pods running
+	EndToEnd LatencyStats `json:"endToEnd"`
+	// AdmissionChecks summarizes AdmissionCheck retries; nil if no workload had checks
+	AdmissionChecks *AdmissionCheckSummary `json:"admissionChecks,omitempty"`
+}
+
+// AdmissionCheckSummary quantifies the overhead of admission checks, e.g. MultiKueue's
+// remote admission protocol
+type AdmissionCheckSummary struct {
+	// Workloads is the number of workloads with AdmissionChecks
+	Workloads int `json:"workloads"`
+	// Retried is the number of workloads with at least one retried check
+	Retried int `json:"retried"`
+	// Retries is the total number of retries across all checks
+	Retries int `json:"retries"`
+	// Pending is the total time each check spent Pending, counting only left states
+	Pending LatencyStats `json:"pending"`
+	// Retry is the total time each check spent in Retry, counting only left states
+	Retry LatencyStats `json:"retry"`
+}
+
+// Summarize computes per-stage latency statistics. Each stage only counts workloads
+// that reached both of its milestones.
+func Summarize(timelines []WorkloadTimeline) TimelineSummary {
+	var admission, scheduling, startup, endToEnd []time.Duration
+	for _, t := range timelines {
+		submitted := &t.SubmittedAt
+		admission = appendStage(admission, submitted, t.AdmittedAt)
+		scheduling = appendStage(scheduling, t.AdmittedAt, t.PodsScheduledAt)
+		startup = appendStage(startup, t.PodsScheduledAt, t.PodsRunningAt)
+		endToEnd = appendStage(endToEnd, submitted, t.PodsRunningAt)
+	}
+
+	return TimelineSummary{
+		Admission:       NewLatencyStats(admission),
+		Scheduling:      NewLatencyStats(scheduling),
+		Startup:         NewLatencyStats(startup),
+		EndToEnd:        NewLatencyStats(endToEnd),
+		AdmissionChecks: summarizeAdmissionChecks(timelines),
+	}
+}
+
+// summarizeAdmissionChecks counts retries and sums each check's time per state. The state a
+// check is in when recording stops has no end and is not counted.
+func summarizeAdmissionChecks(timelines []WorkloadTimeline) *AdmissionCheckSummary {
+	var summary AdmissionCheckSummary
+	var pending, retry []time.Duration
+	for _, t := range timelines {
+		if len(t.AdmissionChecks) == 0 {
+			continue
+		}
+		summary.Workloads++
+		retried := false
+		for _, check := range t.AdmissionChecks {
+			summary.Retries += check.Retries
+			retried = retried || check.Retries > 0
+
+			inState := map[string]time.Duration{}
+			for i := 1; i < len(check.Transitions); i++ {
+				prev := check.Transitions[i-1]
+				inState[prev.State] += max(check.Transitions[i].At.Sub(prev.At), 0)
+			}
+			if d, ok := inState[CheckStatePending]; ok {
+				pending = append(pending, d)
+			}
+			if d, ok := inState[CheckStateRetry]; ok {
+				retry = append(retry, d)
+			}
+		}
+		if retried {
+			summary.Retried++
+		}
+	}
+	if summary.Workloads == 0 {
+		return nil
+	}
+	summary.Pending = NewLatencyStats(pending)
+	summary.Retry = NewLatencyStats(retry)
+	return &summary
+}
+
+// SummarizeWorkerLoss summarizes how long the Workloads dispatched to a lost worker took to
+// be requeued or rejected, measured from the worker's deletion
+func SummarizeWorkerLoss(worker string, deletedAt time.Time, workloads int, requeued, rejected []time.Duration) WorkerLossResult {
+	recovery := append(append([]time.Duration{}, requeued...), rejected...)
+	return WorkerLossResult{
+		Worker:     worker,
+		DeletedAt:  deletedAt,
+		Workloads:  workloads,
+		Requeued:   len(requeued),
+		Rejected:   len(rejected),
+		Unresolved: workloads - len(recovery),
+		Recovery:   NewLatencyStats(recovery),
+	}
+}
+
+// appendStage appends the duration between two milestones if both were reached
+func appendStage(durations []time.Duration, from, to *time.Time) []time.Duration {
+	if from == nil || to == nil || from.IsZero() {
+		return durations
+	}
+	return append(durations, max(to.Sub(*from), 0))
+}
+
+// NewLatencyStats computes nearest-rank percentiles of durations. It sorts durations in place.
+func NewLatencyStats(durations []time.Duration) LatencyStats {
+	if len(durations) == 0 {
+		return LatencyStats{}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	rank := func(p float64) time.Duration {
+		i := int(math.Ceil(p*float64(len(durations)))) - 1
+		return durations[max(i, 0)]
+	}
+
+	return LatencyStats{
+		Count: len(durations),
+		P50:   rank(0.50),
+		P95:   rank(0.95),
+		Max:   durations[len(durations)-1],
+	}
+}
+
+// SaveTimelines persists workload timelines in the default state directory; see
+// Store.SaveTimelines
+func SaveTimelines(runID string, timelines []WorkloadTimeline) error {
+	store, err := OpenStore()
+	if err != nil {
+		return err
+	}
+	return store.SaveTimelines(runID, timelines)
+}
+
+// LoadTimelines reads a run's workload timelines from the default state directory; see
+// Store.LoadTimelines
+func LoadTimelines(runID string) ([]WorkloadTimeline, error) {
+	store, err := OpenStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.LoadTimelines(runID)
+}
+
+// SaveTimelines persists workload timelines to runs/<runID>/timelines.json in the state directory.
+func (s *Store) SaveTimelines(runID string, timelines []WorkloadTimeline) error {
+	dir, err := s.state.Dir(state.Runs, runID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create run directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(timelines, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal timelines: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, timelinesFilename), data, 0600); err != nil {
+		return fmt.Errorf("failed to write timelines: %w", err)
+	}
+	return nil
+}
+
+// LoadTimelines reads the workload timelines recorded for a run.
+func (s *Store) LoadTimelines(runID string) ([]WorkloadTimeline, error) {
+	dir, err := s.state.Dir(state.Runs, runID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, timelinesFilename)) //nolint:gosec // path is constructed from known base directory
+	if err != nil {
+		return nil, fmt.Errorf("failed to read timelines: %w", err)
+	}
+
+	var timelines []WorkloadTimeline
+	if err := json.Unmarshal(data, &timelines); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal timelines: %w", err)
+	}
+	return timelines, nil
+}
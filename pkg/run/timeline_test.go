@@ -0,0 +1,158 @@
+package run
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/state"
+)
+
+func TestSummarize(t *testing.T) {
+	base := time.Date(2026, 3, 28, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := base.Add(d)
+		return &t
+	}
+
+	timelines := []WorkloadTimeline{
+		{
+			Name:            "wl-0",
+			SubmittedAt:     base,
+			AdmittedAt:      at(1 * time.Second),
+			PodsScheduledAt: at(3 * time.Second),
+			PodsRunningAt:   at(4 * time.Second),
+		},
+		{
+			Name:            "wl-1",
+			SubmittedAt:     base,
+			AdmittedAt:      at(2 * time.Second),
+			PodsScheduledAt: at(3 * time.Second),
+			PodsRunningAt:   at(6 * time.Second),
+		},
+		{
+			// Still queued: excluded from every stage
+			Name:        "wl-2",
+			SubmittedAt: base,
+		},
+	}
+
+	got := Summarize(timelines)
+
+	tests := []struct {
+		name  string
+		stats LatencyStats
+		want  LatencyStats
+	}{
+		{"admission", got.Admission, LatencyStats{Count: 2, P50: 1 * time.Second, P95: 2 * time.Second, Max: 2 * time.Second}},
+		{"scheduling", got.Scheduling, LatencyStats{Count: 2, P50: 1 * time.Second, P95: 2 * time.Second, Max: 2 * time.Second}},
+		{"startup", got.Startup, LatencyStats{Count: 2, P50: 1 * time.Second, P95: 3 * time.Second, Max: 3 * time.Second}},
+		{"endToEnd", got.EndToEnd, LatencyStats{Count: 2, P50: 4 * time.Second, P95: 6 * time.Second, Max: 6 * time.Second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.stats != tt.want {
+				t.Errorf("%s = %+v, want %+v", tt.name, tt.stats, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeAdmissionChecks(t *testing.T) {
+	base := time.Date(2026, 3, 28, 12, 0, 0, 0, time.UTC)
+	transition := func(state string, d time.Duration) CheckStateTransition {
+		return CheckStateTransition{State: state, At: base.Add(d)}
+	}
+
+	if got := Summarize([]WorkloadTimeline{{Name: "wl-0", SubmittedAt: base}}).AdmissionChecks; got != nil {
+		t.Errorf("AdmissionChecks without checks = %+v, want nil", got)
+	}
+
+	got := Summarize([]WorkloadTimeline{
+		{
+			Name: "wl-0",
+			AdmissionChecks: []AdmissionCheckTimeline{{
+				Name: "multikueue",
+				Transitions: []CheckStateTransition{
+					transition(CheckStatePending, 0),
+					transition(CheckStateRetry, 2*time.Second),
+					transition(CheckStatePending, 5*time.Second),
+					transition(CheckStateReady, 6*time.Second),
+				},
+				Retries: 1,
+			}},
+		},
+		{
+			// Still Pending: no completed state to count
+			Name: "wl-1",
+			AdmissionChecks: []AdmissionCheckTimeline{{
+				Name:        "multikueue",
+				Transitions: []CheckStateTransition{transition(CheckStatePending, 0)},
+			}},
+		},
+	}).AdmissionChecks
+
+	want := &AdmissionCheckSummary{
+		Workloads: 2,
+		Retried:   1,
+		Retries:   1,
+		Pending:   LatencyStats{Count: 1, P50: 3 * time.Second, P95: 3 * time.Second, Max: 3 * time.Second},
+		Retry:     LatencyStats{Count: 1, P50: 3 * time.Second, P95: 3 * time.Second, Max: 3 * time.Second},
+	}
+	if got == nil || *got != *want {
+		t.Errorf("AdmissionChecks = %+v, want %+v", got, want)
+	}
+}
+
+func TestSummarizeWorkerLoss(t *testing.T) {
+	deletedAt := time.Date(2026, 3, 28, 12, 0, 0, 0, time.UTC)
+	got := SummarizeWorkerLoss("worker-1", deletedAt, 4,
+		[]time.Duration{15 * time.Minute, 16 * time.Minute}, []time.Duration{time.Minute})
+
+	want := WorkerLossResult{
+		Worker:     "worker-1",
+		DeletedAt:  deletedAt,
+		Workloads:  4,
+		Requeued:   2,
+		Rejected:   1,
+		Unresolved: 1,
+		Recovery:   LatencyStats{Count: 3, P50: 15 * time.Minute, P95: 16 * time.Minute, Max: 16 * time.Minute},
+	}
+	if got != want {
+		t.Errorf("SummarizeWorkerLoss() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSaveAndLoadTimelines(t *testing.T) {
+	t.Setenv(state.DirEnv, t.TempDir())
+
+	admitted := time.Date(2026, 3, 28, 12, 0, 1, 0, time.UTC)
+	timelines := []WorkloadTimeline{{
+		Name:        "kueue-bench-test1234-0",
+		Namespace:   "default",
+		Type:        "job",
+		SubmittedAt: time.Date(2026, 3, 28, 12, 0, 0, 0, time.UTC),
+		AdmittedAt:  &admitted,
+		Pods:        2,
+	}}
+
+	if err := SaveTimelines("test1234", timelines); err != nil {
+		t.Fatalf("SaveTimelines() error: %v", err)
+	}
+	loaded, err := LoadTimelines("test1234")
+	if err != nil {
+		t.Fatalf("LoadTimelines() error: %v", err)
+	}
+
+	if len(loaded) != 1 {
+		t.Fatalf("LoadTimelines() returned %d timelines, want 1", len(loaded))
+	}
+	if loaded[0].Name != timelines[0].Name || loaded[0].Pods != 2 {
+		t.Errorf("LoadTimelines()[0] = %+v, want %+v", loaded[0], timelines[0])
+	}
+	if loaded[0].AdmittedAt == nil || !loaded[0].AdmittedAt.Equal(admitted) {
+		t.Errorf("AdmittedAt = %v, want %v", loaded[0].AdmittedAt, admitted)
+	}
+	if loaded[0].PodsRunningAt != nil {
+		t.Errorf("PodsRunningAt = %v, want nil", loaded[0].PodsRunningAt)
+	}
+}
@@ -2,6 +2,8 @@ package run
 
 import (
 	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/kueue"
 )
 
 // RunMetadata stores information about a workload simulation run.
@@ -16,4 +18,31 @@ type RunMetadata struct {
 	WorkloadCount int       `json:"workloadCount"`
 	StartedAt     time.Time `json:"startedAt"`
 	Duration      string    `json:"duration"`
+	// KueueFeatureGates are the Kueue feature gates set explicitly in the target cluster
+	KueueFeatureGates map[string]bool `json:"kueueFeatureGates,omitempty"`
+	// KueueCRDs are the API versions of the Kueue CRDs in the target cluster during the run
+	KueueCRDs []kueue.CRDVersion `json:"kueueCRDs,omitempty"`
+	// Latency summarizes the recorded workload timelines; nil for dry runs
+	Latency *TimelineSummary `json:"latency,omitempty"`
+	// WorkerLoss records how the management cluster handled each worker deleted by
+	// spec.chaos.workerLoss
+	WorkerLoss []WorkerLossResult `json:"workerLoss,omitempty"`
+	// Cost prices the ClusterQueues' capacity over the run; nil unless node pools set costPerHour
+	Cost *CostSummary `json:"cost,omitempty"`
+	// ToolUsage is what kueue-bench itself used while submitting and recording the run
+	ToolUsage *ToolUsage `json:"toolUsage,omitempty"`
+}
+
+// WorkerLossResult records how the management cluster handled the loss of a MultiKueue worker
+type WorkerLossResult struct {
+	Worker    string    `json:"worker"`
+	DeletedAt time.Time `json:"deletedAt"`
+	// Workloads is the number of Workloads dispatched to the worker when it was deleted
+	Workloads int `json:"workloads"`
+	Requeued  int `json:"requeued"`
+	Rejected  int `json:"rejected"`
+	// Unresolved Workloads still waited on the worker when recording stopped
+	Unresolved int `json:"unresolved"`
+	// Recovery is the time from the deletion until each Workload was requeued or rejected
+	Recovery LatencyStats `json:"recovery"`
 }
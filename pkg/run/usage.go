@@ -0,0 +1,83 @@
+package run
+
+import (
+	"math"
+	"runtime"
+	"runtime/debug"
+	"syscall"
+	"time"
+)
+
+// ToolUsage records the resources kueue-bench itself used during a run, so its overhead
+// on a shared host can be told apart from the behavior of the system under test. It is
+// measured for the whole process: runs that overlap in one process, such as concurrent
+// serve runs, each include the others' usage.
+type ToolUsage struct {
+	// GOMAXPROCS is the number of CPUs the Go runtime could use at once
+	GOMAXPROCS int `json:"gomaxprocs"`
+	// MemoryLimit is the Go runtime's soft memory limit in bytes; 0 means unlimited
+	MemoryLimit int64 `json:"memoryLimit,omitempty"`
+	// SubmitWorkers is how many workloads could be submitted concurrently
+	SubmitWorkers int `json:"submitWorkers"`
+	// CPUTime is the user and system CPU time the process used during the run
+	CPUTime time.Duration `json:"cpuTime"`
+	// CPUCores is CPUTime divided by the run's wall-clock time
+	CPUCores float64 `json:"cpuCores"`
+	// PeakRSS is the highest resident set size of the process so far, in bytes
+	PeakRSS int64 `json:"peakRSS"`
+	// GCCycles is the number of garbage collections during the run
+	GCCycles uint32 `json:"gcCycles"`
+}
+
+// UsageMeter measures the process's resource usage from when it was started
+type UsageMeter struct {
+	startedAt time.Time
+	cpu       time.Duration
+	gcCycles  uint32
+}
+
+// StartUsage starts measuring the process's resource usage
+func StartUsage() *UsageMeter {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	cpu, _ := processUsage()
+	return &UsageMeter{startedAt: time.Now(), cpu: cpu, gcCycles: mem.NumGC}
+}
+
+// Stop returns the usage since StartUsage, along with the runtime limits in effect.
+// CPU time and peak RSS are left zero if the operating system does not report them.
+func (m *UsageMeter) Stop() ToolUsage {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	usage := ToolUsage{
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		GCCycles:   mem.NumGC - m.gcCycles,
+	}
+	// A negative limit reads the current limit without changing it
+	if limit := debug.SetMemoryLimit(-1); limit != math.MaxInt64 {
+		usage.MemoryLimit = limit
+	}
+	if cpu, peakRSS := processUsage(); cpu > 0 {
+		usage.CPUTime = cpu - m.cpu
+		usage.PeakRSS = peakRSS
+		if elapsed := time.Since(m.startedAt); elapsed > 0 {
+			usage.CPUCores = usage.CPUTime.Seconds() / elapsed.Seconds()
+		}
+	}
+	return usage
+}
+
+// processUsage returns the CPU time the process has used and its peak RSS in bytes
+func processUsage() (time.Duration, int64) {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0, 0
+	}
+	cpu := time.Duration(ru.Utime.Nano() + ru.Stime.Nano())
+	// Linux reports the peak RSS in kilobytes, macOS in bytes
+	peakRSS := int64(ru.Maxrss)
+	if runtime.GOOS != "darwin" {
+		peakRSS *= 1024
+	}
+	return cpu, peakRSS
+}
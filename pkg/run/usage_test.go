@@ -0,0 +1,35 @@
+package run
+
+import (
+	"runtime"
+	"runtime/debug"
+	"testing"
+)
+
+func TestUsageMeter(t *testing.T) {
+	previous := debug.SetMemoryLimit(512 << 20)
+	defer debug.SetMemoryLimit(previous)
+
+	meter := StartUsage()
+	// Burn a little CPU and memory so there is something to measure
+	var sink [][]byte
+	for i := 0; i < 64; i++ {
+		sink = append(sink, make([]byte, 64<<10))
+	}
+	runtime.GC()
+	_ = sink
+	usage := meter.Stop()
+
+	if usage.GOMAXPROCS != runtime.GOMAXPROCS(0) {
+		t.Errorf("GOMAXPROCS = %d, want %d", usage.GOMAXPROCS, runtime.GOMAXPROCS(0))
+	}
+	if usage.MemoryLimit != 512<<20 {
+		t.Errorf("MemoryLimit = %d, want %d", usage.MemoryLimit, 512<<20)
+	}
+	if usage.GCCycles == 0 {
+		t.Error("GCCycles = 0, want the forced collection counted")
+	}
+	if usage.CPUTime < 0 || usage.PeakRSS <= 0 {
+		t.Errorf("CPUTime = %s, PeakRSS = %d; want a measured process", usage.CPUTime, usage.PeakRSS)
+	}
+}
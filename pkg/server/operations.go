@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Operation types
+const (
+	OpCreateTopology = "create-topology"
+	OpDeleteTopology = "delete-topology"
+	OpRun            = "run"
+)
+
+// Operation statuses
+const (
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// Operation is a long-running topology or scenario operation started over the API
+type Operation struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// Topology is the topology the operation acts on
+	Topology string `json:"topology"`
+	// RunID identifies the run of OpRun operations, for GET /v1/runs/{id} once it finishes
+	RunID string `json:"runID,omitempty"`
+	// Submitted counts the workloads an OpRun operation has submitted so far
+	Submitted  int        `json:"submitted,omitempty"`
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+
+	cancel context.CancelFunc
+}
+
+// operations tracks the operations started by a server. Safe for concurrent use.
+type operations struct {
+	ctx context.Context
+	wg  sync.WaitGroup
+
+	mu     sync.Mutex
+	nextID int
+	byID   map[string]*Operation
+	order  []string
+}
+
+func newOperations(ctx context.Context) *operations {
+	return &operations{ctx: ctx, byID: make(map[string]*Operation)}
+}
+
+// start runs fn in the background as a new operation on a topology. Only one operation
+// may act on a topology at a time.
+func (o *operations) start(opType, topology string, prepare func(op *Operation), fn func(ctx context.Context, op *Operation) error) (Operation, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, id := range o.order {
+		if op := o.byID[id]; op.Topology == topology && op.Status == StatusRunning {
+			return Operation{}, fmt.Errorf("operation %s (%s) is already running on topology %q", op.ID, op.Type, topology)
+		}
+	}
+
+	o.nextID++
+	ctx, cancel := context.WithCancel(o.ctx)
+	op := &Operation{
+		ID:        fmt.Sprintf("op-%d", o.nextID),
+		Type:      opType,
+		Topology:  topology,
+		Status:    StatusRunning,
+		StartedAt: time.Now(),
+		cancel:    cancel,
+	}
+	if prepare != nil {
+		prepare(op)
+	}
+	o.byID[op.ID] = op
+	o.order = append(o.order, op.ID)
+
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		defer cancel()
+		err := fn(ctx, op)
+		o.finish(ctx, op, err)
+	}()
+	return *op, nil
+}
+
+// finish records the outcome of an operation
+func (o *operations) finish(ctx context.Context, op *Operation, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	now := time.Now()
+	op.FinishedAt = &now
+	switch {
+	case err == nil:
+		op.Status = StatusSucceeded
+	case ctx.Err() != nil:
+		op.Status = StatusCancelled
+		op.Error = err.Error()
+	default:
+		op.Status = StatusFailed
+		op.Error = err.Error()
+	}
+}
+
+// update applies fn to a running operation, e.g. to record progress
+func (o *operations) update(op *Operation, fn func(op *Operation)) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	fn(op)
+}
+
+// get returns a copy of an operation
+func (o *operations) get(id string) (Operation, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	op, ok := o.byID[id]
+	if !ok {
+		return Operation{}, false
+	}
+	return *op, true
+}
+
+// list returns copies of all operations, oldest first
+func (o *operations) list() []Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	ops := make([]Operation, 0, len(o.order))
+	for _, id := range o.order {
+		ops = append(ops, *o.byID[id])
+	}
+	return ops
+}
+
+// cancel cancels a running operation; it stops at its next cancellation point
+func (o *operations) cancel(id string) (Operation, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	op, ok := o.byID[id]
+	if !ok {
+		return Operation{}, false
+	}
+	op.cancel()
+	return *op, true
+}
+
+// wait blocks until every operation has finished
+func (o *operations) wait() {
+	o.wg.Wait()
+}
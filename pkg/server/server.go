@@ -0,0 +1,420 @@
+// Package server exposes topology and scenario operations over a local HTTP API, for
+// orchestration that outlives a single CLI invocation (CI pipelines, notebooks).
+// Creating and deleting topologies and running scenarios are long-running, so they start
+// an Operation in the background that clients poll until it finishes.
+//
+// Routes:
+//
+//	GET    /healthz
+//	GET    /v1/topologies
+//	POST   /v1/topologies                 {"path": "topology.yaml"} or {"config": "<YAML>"}, optional "offline"
+//	GET    /v1/topologies/{name}
+//	DELETE /v1/topologies/{name}
+//	GET    /v1/runs
+//	POST   /v1/runs                       {"topology": "t", "profilePath": "p.yaml", ...}
+//	GET    /v1/runs/{id}
+//	GET    /v1/runs/{id}/results
+//	GET    /v1/operations
+//	GET    /v1/operations/{id}
+//	POST   /v1/operations/{id}/cancel
+//
+// Topology names and run IDs must be DNS-1123 labels, and request bodies must be sent
+// with Content-Type: application/json.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/bench"
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/run"
+	"github.com/jhwagner/kueue-bench/pkg/topology"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// maxRequestBytes bounds request bodies, which hold at most a topology or profile
+const maxRequestBytes = 4 << 20
+
+// Server serves the kueue-bench HTTP API
+type Server struct {
+	ops *operations
+	// harness holds the topologies and runs the server works on
+	harness *bench.Harness
+
+	// The operations the API triggers, replaced in tests
+	createTopology func(ctx context.Context, cfg *config.Topology) error
+	deleteTopology func(ctx context.Context, name string) error
+	runScenario    func(ctx context.Context, opts bench.ScenarioOptions) (*run.RunMetadata, error)
+}
+
+// New returns a server over the topologies and runs of h whose operations run under ctx;
+// cancelling it cancels them all
+func New(ctx context.Context, h *bench.Harness) *Server {
+	return &Server{
+		ops:     newOperations(ctx),
+		harness: h,
+		createTopology: func(ctx context.Context, cfg *config.Topology) error {
+			_, err := h.CreateTopology(ctx, cfg)
+			return err
+		},
+		deleteTopology: h.DeleteTopology,
+		runScenario:    h.RunScenario,
+	}
+}
+
+// Wait blocks until every operation has finished, e.g. after cancelling the server's
+// context on shutdown
+func (s *Server) Wait() {
+	s.ops.wait()
+}
+
+// Handler returns the HTTP handler serving the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("GET /v1/topologies", s.listTopologies)
+	mux.HandleFunc("POST /v1/topologies", s.createTopologyHandler)
+	mux.HandleFunc("GET /v1/topologies/{name}", s.getTopology)
+	mux.HandleFunc("DELETE /v1/topologies/{name}", s.deleteTopologyHandler)
+	mux.HandleFunc("GET /v1/runs", s.listRuns)
+	mux.HandleFunc("POST /v1/runs", s.startRun)
+	mux.HandleFunc("GET /v1/runs/{id}", s.getRun)
+	mux.HandleFunc("GET /v1/runs/{id}/results", s.getRunResults)
+	mux.HandleFunc("GET /v1/operations", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, s.ops.list())
+	})
+	mux.HandleFunc("GET /v1/operations/{id}", s.getOperation)
+	mux.HandleFunc("POST /v1/operations/{id}/cancel", s.cancelOperation)
+	return mux
+}
+
+// CreateTopologyRequest is the body of POST /v1/topologies. Path names a topology file
+// on the server's host; Config holds the topology YAML (or JSON) itself, with relative
+// file references resolved against the server's working directory. Offline sets
+// spec.kwok.offline, like topology create --offline.
+type CreateTopologyRequest struct {
+	Path    string `json:"path,omitempty"`
+	Config  string `json:"config,omitempty"`
+	Offline bool   `json:"offline,omitempty"`
+}
+
+// RunRequest is the body of POST /v1/runs. The profile is read from ProfilePath on the
+// server's host unless Profile holds the profile YAML (or JSON) itself.
+type RunRequest struct {
+	Topology    string `json:"topology"`
+	Cluster     string `json:"cluster,omitempty"`
+	ProfilePath string `json:"profilePath,omitempty"`
+	Profile     string `json:"profile,omitempty"`
+	RunID       string `json:"runID,omitempty"`
+	// TimelineWait keeps recording workload timelines this long after submission ends,
+	// as a Go duration string (e.g. "1m"); default bench.DefaultTimelineWait
+	TimelineWait string `json:"timelineWait,omitempty"`
+	// SubmitWorkers is how many workloads may be submitted concurrently; default 1
+	SubmitWorkers int `json:"submitWorkers,omitempty"`
+}
+
+// ResultsResponse is the body returned by GET /v1/runs/{id}/results
+type ResultsResponse struct {
+	Run       *run.RunMetadata       `json:"run"`
+	Timelines []run.WorkloadTimeline `json:"timelines,omitempty"`
+}
+
+func (s *Server) listTopologies(w http.ResponseWriter, _ *http.Request) {
+	topologies, err := s.harness.Topologies().List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	metadata := make([]*topology.Metadata, 0, len(topologies))
+	for _, topo := range topologies {
+		metadata = append(metadata, topo.GetMetadata())
+	}
+	writeJSON(w, http.StatusOK, metadata)
+}
+
+func (s *Server) getTopology(w http.ResponseWriter, r *http.Request) {
+	name, ok := pathName(w, r, "name", "topology name")
+	if !ok {
+		return
+	}
+	topo, err := s.harness.Topologies().Load(name)
+	if err != nil {
+		writeError(w, notFoundStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, topo.GetMetadata())
+}
+
+func (s *Server) createTopologyHandler(w http.ResponseWriter, r *http.Request) {
+	var req CreateTopologyRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	var cfg *config.Topology
+	var err error
+	switch {
+	case req.Path != "" && req.Config != "":
+		err = fmt.Errorf("set only one of path and config")
+	case req.Path != "":
+		cfg, err = config.LoadTopology(req.Path)
+	case req.Config != "":
+		var dir string
+		if dir, err = os.Getwd(); err == nil {
+			cfg, err = config.ParseTopology([]byte(req.Config), dir)
+		}
+	default:
+		err = fmt.Errorf("path or config is required")
+	}
+	if err == nil && cfg.Metadata.Name == "" {
+		err = fmt.Errorf("topology name must be set in metadata.name")
+	}
+	if err == nil && req.Offline {
+		if cfg.Spec.Kwok == nil {
+			cfg.Spec.Kwok = &config.KwokSettings{}
+		}
+		cfg.Spec.Kwok.Offline = true
+	}
+	if err == nil {
+		err = config.ValidateTopology(cfg)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.startOperation(w, OpCreateTopology, cfg.Metadata.Name, nil, func(ctx context.Context, _ *Operation) error {
+		return s.createTopology(ctx, cfg)
+	})
+}
+
+func (s *Server) deleteTopologyHandler(w http.ResponseWriter, r *http.Request) {
+	name, ok := pathName(w, r, "name", "topology name")
+	if !ok {
+		return
+	}
+	if _, err := s.harness.Topologies().Load(name); err != nil {
+		writeError(w, notFoundStatus(err), err)
+		return
+	}
+	s.startOperation(w, OpDeleteTopology, name, nil, func(ctx context.Context, _ *Operation) error {
+		return s.deleteTopology(ctx, name)
+	})
+}
+
+func (s *Server) listRuns(w http.ResponseWriter, _ *http.Request) {
+	runs, err := s.harness.Runs().List()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, runs)
+}
+
+func (s *Server) getRun(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathName(w, r, "id", "run ID")
+	if !ok {
+		return
+	}
+	meta, err := s.harness.Runs().Load(id)
+	if err != nil {
+		writeError(w, notFoundStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, meta)
+}
+
+func (s *Server) getRunResults(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathName(w, r, "id", "run ID")
+	if !ok {
+		return
+	}
+	results, err := s.harness.CollectResults(id)
+	if err != nil {
+		writeError(w, notFoundStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ResultsResponse{Run: results.Run, Timelines: results.Timelines})
+}
+
+func (s *Server) startRun(w http.ResponseWriter, r *http.Request) {
+	var req RunRequest
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+
+	opts, err := req.scenarioOptions()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if _, err := s.harness.ResolveCluster(opts.Topology, opts.Cluster); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, os.ErrNotExist) {
+			status = http.StatusNotFound
+		}
+		writeError(w, status, err)
+		return
+	}
+
+	prepare := func(op *Operation) { op.RunID = opts.RunID }
+	s.startOperation(w, OpRun, opts.Topology, prepare, func(ctx context.Context, op *Operation) error {
+		opts.OnSubmit = func(string, string, string) {
+			s.ops.update(op, func(op *Operation) { op.Submitted++ })
+		}
+		_, err := s.runScenario(ctx, opts)
+		return err
+	})
+}
+
+// scenarioOptions validates a run request and converts it to scenario options. The run
+// ID is generated here, so it can be returned before the run starts.
+func (req RunRequest) scenarioOptions() (bench.ScenarioOptions, error) {
+	opts := bench.ScenarioOptions{
+		Topology:      req.Topology,
+		Cluster:       req.Cluster,
+		RunID:         req.RunID,
+		SubmitWorkers: req.SubmitWorkers,
+	}
+	if req.Topology == "" {
+		return opts, fmt.Errorf("topology is required")
+	}
+	if err := validName("topology", req.Topology); err != nil {
+		return opts, err
+	}
+	if req.SubmitWorkers < 0 {
+		return opts, fmt.Errorf("submitWorkers must not be negative")
+	}
+
+	var err error
+	switch {
+	case req.ProfilePath != "" && req.Profile != "":
+		return opts, fmt.Errorf("set only one of profilePath and profile")
+	case req.ProfilePath != "":
+		opts.Profile, err = config.LoadWorkloadProfile(req.ProfilePath)
+		if abs, absErr := filepath.Abs(req.ProfilePath); absErr == nil {
+			opts.ProfilePath = abs
+		}
+	case req.Profile != "":
+		opts.Profile, err = config.ParseWorkloadProfile([]byte(req.Profile))
+	default:
+		return opts, fmt.Errorf("profilePath or profile is required")
+	}
+	if err != nil {
+		return opts, err
+	}
+	if err := config.ValidateWorkloadProfile(opts.Profile); err != nil {
+		return opts, fmt.Errorf("invalid workload profile: %w", err)
+	}
+
+	opts.TimelineWait = bench.DefaultTimelineWait
+	if req.TimelineWait != "" {
+		if opts.TimelineWait, err = time.ParseDuration(req.TimelineWait); err != nil {
+			return opts, fmt.Errorf("invalid timelineWait: %w", err)
+		}
+	}
+	if opts.RunID == "" {
+		opts.RunID = bench.NewRunID()
+	} else if err := validName("runID", opts.RunID); err != nil {
+		// The run ID names result files and labels the run's workloads
+		return opts, err
+	}
+	return opts, nil
+}
+
+func (s *Server) getOperation(w http.ResponseWriter, r *http.Request) {
+	op, ok := s.ops.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("operation %q not found", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, op)
+}
+
+func (s *Server) cancelOperation(w http.ResponseWriter, r *http.Request) {
+	op, ok := s.ops.cancel(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("operation %q not found", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusAccepted, op)
+}
+
+// startOperation starts an operation and responds with it, or with a conflict if another
+// operation is running on the topology
+func (s *Server) startOperation(w http.ResponseWriter, opType, topologyName string, prepare func(*Operation), fn func(context.Context, *Operation) error) {
+	op, err := s.ops.start(opType, topologyName, prepare, fn)
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	w.Header().Set("Location", "/v1/operations/"+op.ID)
+	writeJSON(w, http.StatusAccepted, op)
+}
+
+// validName checks that a topology name or run ID from a request is a DNS-1123 label, so
+// that it names a record inside its store
+func validName(what, name string) error {
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return fmt.Errorf("invalid %s %q: %s", what, name, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// pathName returns the named path wildcard, responding with 400 if it is not a valid name.
+// ServeMux decodes %2F inside a wildcard, so a path value may hold "../".
+func pathName(w http.ResponseWriter, r *http.Request, key, what string) (string, bool) {
+	name := r.PathValue(key)
+	if err := validName(what, name); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return "", false
+	}
+	return name, true
+}
+
+// decodeRequest decodes a JSON request body, responding with an error if it is invalid.
+// The body must be sent as application/json: browsers can't send that cross-origin
+// without a CORS preflight, which the server doesn't answer, so a web page can't drive
+// the unauthenticated API.
+func decodeRequest(w http.ResponseWriter, r *http.Request, v any) bool {
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, fmt.Errorf("request Content-Type must be application/json"))
+		return false
+	}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+// notFoundStatus maps errors from loading saved topologies and runs to a status code
+func notFoundStatus(err error) int {
+	if errors.Is(err, os.ErrNotExist) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,281 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/bench"
+	"github.com/jhwagner/kueue-bench/pkg/run"
+	"github.com/jhwagner/kueue-bench/pkg/state"
+	"github.com/jhwagner/kueue-bench/pkg/topology"
+)
+
+const testProfile = `apiVersion: kueue-bench.io/v1alpha1
+kind: WorkloadProfile
+metadata:
+  name: test
+spec:
+  duration: 1s
+  arrivalPattern:
+    type: constant
+    ratePerMinute: 60
+  workloads:
+    - type: Job
+      weight: 1
+      localQueue: lq
+      template:
+        resources:
+          requests:
+            cpu: "1"
+        duration: 1s
+`
+
+// newTestServer returns a test server over a temporary state directory holding a
+// one-cluster topology named "bench"
+func newTestServer(t *testing.T) (*Server, *httptest.Server) {
+	t.Helper()
+	store, err := state.OpenAt(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := topology.Metadata{Name: "bench", Clusters: map[string]topology.Cluster{
+		"bench": {Name: "bench", KubeconfigPath: "/kubeconfigs/bench"},
+	}}
+	if err := store.Save(state.Topologies, "bench", meta); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h, err := bench.Open(store.Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(ctx, h)
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(func() {
+		ts.Close()
+		cancel()
+		s.Wait()
+	})
+	return s, ts
+}
+
+// do sends a request, with a JSON body if body is set, and decodes the JSON response into
+// out, returning the status code
+func do(t *testing.T, method, url, body string, out any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("%s %s: failed to decode response: %v", method, url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// waitForStatus polls an operation until it leaves StatusRunning
+func waitForStatus(t *testing.T, ts *httptest.Server, id string) Operation {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		var op Operation
+		if status := do(t, http.MethodGet, ts.URL+"/v1/operations/"+id, "", &op); status != http.StatusOK {
+			t.Fatalf("GET operation status = %d", status)
+		}
+		if op.Status != StatusRunning {
+			return op
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("operation %s did not finish", id)
+	return Operation{}
+}
+
+func TestTopologyEndpoints(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	var topologies []topology.Metadata
+	if status := do(t, http.MethodGet, ts.URL+"/v1/topologies", "", &topologies); status != http.StatusOK {
+		t.Fatalf("GET /v1/topologies status = %d", status)
+	}
+	if len(topologies) != 1 || topologies[0].Name != "bench" {
+		t.Errorf("topologies = %+v, want [bench]", topologies)
+	}
+
+	var meta topology.Metadata
+	if status := do(t, http.MethodGet, ts.URL+"/v1/topologies/bench", "", &meta); status != http.StatusOK || meta.Name != "bench" {
+		t.Errorf("GET /v1/topologies/bench = %d %+v", status, meta)
+	}
+	if status := do(t, http.MethodGet, ts.URL+"/v1/topologies/missing", "", nil); status != http.StatusNotFound {
+		t.Errorf("GET missing topology status = %d, want 404", status)
+	}
+
+	for _, body := range []string{`{}`, `{"config": "metadata: {}"}`, `{"unknown": true}`, `not json`} {
+		var resp map[string]string
+		if status := do(t, http.MethodPost, ts.URL+"/v1/topologies", body, &resp); status != http.StatusBadRequest {
+			t.Errorf("POST /v1/topologies %s status = %d, want 400", body, status)
+		}
+		if resp["error"] == "" {
+			t.Errorf("POST /v1/topologies %s returned no error message", body)
+		}
+	}
+}
+
+func TestPathNameValidation(t *testing.T) {
+	s, ts := newTestServer(t)
+	s.deleteTopology = func(_ context.Context, name string) error {
+		t.Errorf("deleteTopology(%q) called for an invalid name", name)
+		return nil
+	}
+	if err := s.harness.Runs().Save(&run.RunMetadata{RunID: "run-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, req := range []struct{ method, path string }{
+		{http.MethodDelete, "/v1/topologies/..%2Fruns%2Frun-1"},
+		{http.MethodGet, "/v1/topologies/..%2Fruns%2Frun-1"},
+		{http.MethodGet, "/v1/topologies/Bench"},
+		{http.MethodGet, "/v1/runs/..%2Ftopologies%2Fbench"},
+		{http.MethodGet, "/v1/runs/..%2Ftopologies%2Fbench/results"},
+	} {
+		if status := do(t, req.method, ts.URL+req.path, "", nil); status != http.StatusBadRequest {
+			t.Errorf("%s %s status = %d, want 400", req.method, req.path, status)
+		}
+	}
+	if _, err := s.harness.Runs().Load("run-1"); err != nil {
+		t.Errorf("run-1 was affected by the rejected requests: %v", err)
+	}
+}
+
+func TestRequestContentType(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	for _, contentType := range []string{"", "text/plain", "application/x-www-form-urlencoded"} {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/v1/topologies", strings.NewReader(`{"path": "t.yaml"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusUnsupportedMediaType {
+			t.Errorf("POST with Content-Type %q status = %d, want 415", contentType, resp.StatusCode)
+		}
+	}
+}
+
+func TestDeleteTopology(t *testing.T) {
+	s, ts := newTestServer(t)
+	var deleted string
+	s.deleteTopology = func(_ context.Context, name string) error {
+		deleted = name
+		return nil
+	}
+
+	var op Operation
+	if status := do(t, http.MethodDelete, ts.URL+"/v1/topologies/bench", "", &op); status != http.StatusAccepted {
+		t.Fatalf("DELETE status = %d, want 202", status)
+	}
+	if op = waitForStatus(t, ts, op.ID); op.Status != StatusSucceeded || op.Type != OpDeleteTopology {
+		t.Errorf("operation = %+v, want succeeded %s", op, OpDeleteTopology)
+	}
+	if deleted != "bench" {
+		t.Errorf("deleted topology %q, want bench", deleted)
+	}
+}
+
+func TestRunLifecycle(t *testing.T) {
+	s, ts := newTestServer(t)
+	started := make(chan bench.ScenarioOptions)
+	s.runScenario = func(ctx context.Context, opts bench.ScenarioOptions) (*run.RunMetadata, error) {
+		opts.OnSubmit("job-1", "Job", "default")
+		started <- opts
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	body, _ := json.Marshal(RunRequest{Topology: "bench", Profile: testProfile, TimelineWait: "30s", SubmitWorkers: 4})
+	var op Operation
+	if status := do(t, http.MethodPost, ts.URL+"/v1/runs", string(body), &op); status != http.StatusAccepted {
+		t.Fatalf("POST /v1/runs status = %d, want 202", status)
+	}
+	opts := <-started
+	if op.RunID == "" || opts.RunID != op.RunID {
+		t.Errorf("operation run ID = %q, scenario run ID = %q; want the same generated ID", op.RunID, opts.RunID)
+	}
+	if opts.TimelineWait != 30*time.Second || opts.SubmitWorkers != 4 || opts.Profile == nil || opts.Profile.Metadata.Name != "test" {
+		t.Errorf("scenario options = %+v, want the requested profile, timeline wait and submit workers", opts)
+	}
+
+	// A second operation on the same topology conflicts
+	if status := do(t, http.MethodPost, ts.URL+"/v1/runs", string(body), nil); status != http.StatusConflict {
+		t.Errorf("concurrent POST /v1/runs status = %d, want 409", status)
+	}
+
+	var running Operation
+	do(t, http.MethodGet, ts.URL+"/v1/operations/"+op.ID, "", &running)
+	if running.Status != StatusRunning || running.Submitted != 1 {
+		t.Errorf("operation = %+v, want running with 1 submitted", running)
+	}
+
+	if status := do(t, http.MethodPost, ts.URL+"/v1/operations/"+op.ID+"/cancel", "", nil); status != http.StatusAccepted {
+		t.Fatalf("cancel status = %d, want 202", status)
+	}
+	if op = waitForStatus(t, ts, op.ID); op.Status != StatusCancelled {
+		t.Errorf("operation status = %s, want %s", op.Status, StatusCancelled)
+	}
+
+	var ops []Operation
+	do(t, http.MethodGet, ts.URL+"/v1/operations", "", &ops)
+	if len(ops) != 1 {
+		t.Errorf("operations = %+v, want one", ops)
+	}
+}
+
+func TestRunRequestValidation(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	tests := []struct {
+		name string
+		req  RunRequest
+		want int
+	}{
+		{name: "missing topology", req: RunRequest{Profile: testProfile}, want: http.StatusBadRequest},
+		{name: "missing profile", req: RunRequest{Topology: "bench"}, want: http.StatusBadRequest},
+		{name: "both profiles", req: RunRequest{Topology: "bench", Profile: testProfile, ProfilePath: "p.yaml"}, want: http.StatusBadRequest},
+		{name: "invalid run ID", req: RunRequest{Topology: "bench", Profile: testProfile, RunID: "../other"}, want: http.StatusBadRequest},
+		{name: "invalid timeline wait", req: RunRequest{Topology: "bench", Profile: testProfile, TimelineWait: "soon"}, want: http.StatusBadRequest},
+		{name: "negative submit workers", req: RunRequest{Topology: "bench", Profile: testProfile, SubmitWorkers: -1}, want: http.StatusBadRequest},
+		{name: "unknown topology", req: RunRequest{Topology: "missing", Profile: testProfile}, want: http.StatusNotFound},
+		{name: "unknown cluster", req: RunRequest{Topology: "bench", Cluster: "other", Profile: testProfile}, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.req)
+			if status := do(t, http.MethodPost, ts.URL+"/v1/runs", string(body), nil); status != tt.want {
+				t.Errorf("POST /v1/runs status = %d, want %d", status, tt.want)
+			}
+		})
+	}
+}
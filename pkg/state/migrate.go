@@ -0,0 +1,164 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// migration upgrades the store layout from version-1 to version
+type migration struct {
+	version     int
+	description string
+	apply       func(s *Store) error
+}
+
+// migrations are applied in order to bring a store up to CurrentSchemaVersion.
+// Append new entries; never edit or reorder released ones.
+var migrations = []migration{
+	{
+		// Stores written before versioning already use the v1 layout
+		// (topologies/<name>/ and runs/<id>/), so adopting them is a no-op.
+		version:     1,
+		description: "adopt unversioned layout",
+		apply:       func(*Store) error { return nil },
+	},
+}
+
+// CurrentSchemaVersion is the store layout version written by this build
+var CurrentSchemaVersion = migrations[len(migrations)-1].version
+
+// migrate applies any pending migrations and records the resulting schema version
+func (s *Store) migrate() error {
+	info, err := s.Info()
+	if err != nil {
+		return err
+	}
+
+	if info.SchemaVersion > CurrentSchemaVersion {
+		return fmt.Errorf("state directory %s has schema version %d, newer than supported version %d",
+			s.root, info.SchemaVersion, CurrentSchemaVersion)
+	}
+	if info.SchemaVersion == CurrentSchemaVersion {
+		return nil
+	}
+
+	for _, m := range migrations {
+		if m.version <= info.SchemaVersion {
+			continue
+		}
+		if err := m.apply(s); err != nil {
+			return fmt.Errorf("failed to migrate state to version %d (%s): %w", m.version, m.description, err)
+		}
+		info.SchemaVersion = m.version
+		if err := s.writeInfo(info); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordVersionField is the metadata field holding a record's schema version
+const recordVersionField = "schemaVersion"
+
+// RecordMigration upgrades a record's metadata document from Version-1 to Version. Each
+// kind of record versions its metadata independently of the store layout.
+type RecordMigration struct {
+	Version     int
+	Description string
+	// Apply edits the decoded metadata document in place. Numbers are json.Number.
+	Apply func(doc map[string]any) error
+}
+
+// NewerSchemaError is returned by LoadVersioned for a record written by a newer
+// kueue-bench, whose metadata this build cannot read
+type NewerSchemaError struct {
+	Kind      Kind
+	Name      string
+	Version   int
+	Supported int
+}
+
+func (e *NewerSchemaError) Error() string {
+	return fmt.Sprintf("%s %q has metadata schema version %d, newer than supported version %d; upgrade kueue-bench",
+		e.Kind, e.Name, e.Version, e.Supported)
+}
+
+// LoadVersioned reads a record's metadata into v like Load, first upgrading documents
+// written with an older schema version through migrations, which must be in version
+// order. Documents without a schemaVersion are version 0. An upgraded record is saved
+// back; a record newer than the last migration fails to load with a *NewerSchemaError
+// rather than being misread.
+func (s *Store) LoadVersioned(kind Kind, name string, v any, migrations []RecordMigration) error {
+	dir, err := s.Dir(kind, name)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, metadataFilename)) //nolint:gosec // path is constructed from known base directory
+	if err != nil {
+		return fmt.Errorf("failed to read %s metadata: %w", kind, err)
+	}
+	var doc map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return fmt.Errorf("failed to unmarshal %s metadata: %w", kind, err)
+	}
+
+	version, err := recordVersion(doc)
+	if err != nil {
+		return fmt.Errorf("%s %q: %w", kind, name, err)
+	}
+	current := 0
+	if len(migrations) > 0 {
+		current = migrations[len(migrations)-1].Version
+	}
+	if version > current {
+		return &NewerSchemaError{Kind: kind, Name: name, Version: version, Supported: current}
+	}
+
+	migrated := false
+	for _, m := range migrations {
+		if m.Version <= version {
+			continue
+		}
+		if err := m.Apply(doc); err != nil {
+			return fmt.Errorf("failed to migrate %s %q metadata to version %d (%s): %w", kind, name, m.Version, m.Description, err)
+		}
+		doc[recordVersionField] = m.Version
+		migrated = true
+	}
+
+	if migrated {
+		if data, err = json.Marshal(doc); err != nil {
+			return fmt.Errorf("failed to marshal %s metadata: %w", kind, err)
+		}
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to unmarshal %s metadata: %w", kind, err)
+	}
+	if migrated {
+		return s.Save(kind, name, v)
+	}
+	return nil
+}
+
+// recordVersion returns a metadata document's schema version, 0 if it has none
+func recordVersion(doc map[string]any) (int, error) {
+	raw, ok := doc[recordVersionField]
+	if !ok {
+		return 0, nil
+	}
+	number, ok := raw.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("invalid %s %v", recordVersionField, raw)
+	}
+	version, err := number.Int64()
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %v", recordVersionField, raw)
+	}
+	return int(version), nil
+}
@@ -0,0 +1,246 @@
+// Package state is the single on-disk store for kueue-bench state. Open uses the default
+// root resolved by Root: $KUEUE_BENCH_STATE_DIR, then an existing ~/.kueue-bench, then
+// $XDG_DATA_HOME/kueue-bench (~/.local/share/kueue-bench). OpenAt opens a store at any
+// other root, e.g. a per-test directory.
+//
+// Each kind of record (topologies, runs, ...) lives in its own subdirectory, one
+// directory per record holding a metadata.json document plus any artifacts (e.g.
+// kubeconfigs). The layout is versioned and upgraded in place by Open.
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DirEnv is the environment variable that overrides the state directory
+const DirEnv = "KUEUE_BENCH_STATE_DIR"
+
+const (
+	// legacyDirName is the state directory under $HOME used before XDG support
+	legacyDirName = ".kueue-bench"
+	// xdgDirName is the state directory under $XDG_DATA_HOME
+	xdgDirName       = "kueue-bench"
+	metadataFilename = "metadata.json"
+	stateFilename    = "state.json"
+)
+
+// Kind identifies a category of records in the store
+type Kind string
+
+const (
+	// Topologies holds topology metadata and per-cluster kubeconfigs
+	Topologies Kind = "topologies"
+	// Runs holds workload run metadata
+	Runs Kind = "runs"
+	// Snapshots holds golden cluster image snapshots
+	Snapshots Kind = "snapshots"
+	// Cache holds downloaded manifests, keyed by URL and checksum
+	Cache Kind = "cache"
+	// Queues holds the scenarios queued against each topology
+	Queues Kind = "queues"
+	// Batches holds the consolidated reports of executed scenario queues
+	Batches Kind = "batches"
+)
+
+// Store provides access to records under a state root directory
+type Store struct {
+	root string
+}
+
+// Info describes the store layout
+type Info struct {
+	SchemaVersion int `json:"schemaVersion"`
+}
+
+// Root returns the state directory Open uses: $KUEUE_BENCH_STATE_DIR, else ~/.kueue-bench
+// if it exists (stores created before XDG support stay where they are), else
+// $XDG_DATA_HOME/kueue-bench, defaulting to ~/.local/share/kueue-bench.
+func Root() (string, error) {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	legacy := filepath.Join(home, legacyDirName)
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy, nil
+	}
+	// The XDG spec says relative paths are invalid and must be ignored
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if !filepath.IsAbs(dataHome) {
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, xdgDirName), nil
+}
+
+// Open opens the store at Root, migrating it to the current schema if needed
+func Open() (*Store, error) {
+	dir, err := Root()
+	if err != nil {
+		return nil, err
+	}
+	return OpenAt(dir)
+}
+
+// OpenAt opens the store rooted at dir, migrating it to the current schema if needed
+func OpenAt(dir string) (*Store, error) {
+	s := &Store{root: dir}
+	if err := s.migrate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Root returns the store root directory
+func (s *Store) Root() string {
+	return s.root
+}
+
+// Path returns the path of an entry directly under a kind directory, e.g. a snapshot archive
+func (s *Store) Path(kind Kind, name string) string {
+	return filepath.Join(s.root, string(kind), name)
+}
+
+// Dir returns the directory of a record. Names that are empty, "." or "..", or contain a
+// path separator are rejected, so that a name can't resolve outside its kind directory.
+func (s *Store) Dir(kind Kind, name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsRune(name, '/') || strings.ContainsRune(name, filepath.Separator) {
+		return "", fmt.Errorf("invalid %s name %q", kind, name)
+	}
+	return s.Path(kind, name), nil
+}
+
+// Load reads a record's metadata into v
+func (s *Store) Load(kind Kind, name string, v interface{}) error {
+	dir, err := s.Dir(kind, name)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, metadataFilename)) //nolint:gosec // path is constructed from known base directory
+	if err != nil {
+		return fmt.Errorf("failed to read %s metadata: %w", kind, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to unmarshal %s metadata: %w", kind, err)
+	}
+	return nil
+}
+
+// Save writes v as a record's metadata, creating the record directory if needed
+func (s *Store) Save(kind Kind, name string, v interface{}) error {
+	dir, err := s.Dir(kind, name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", kind, err)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s metadata: %w", kind, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, metadataFilename), data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s metadata: %w", kind, err)
+	}
+	return nil
+}
+
+// Names returns the sorted names of all records of a kind
+func (s *Store) Names(kind Kind) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.root, string(kind)))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s directory: %w", kind, err)
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Remove deletes a record and all its artifacts
+func (s *Store) Remove(kind Kind, name string) error {
+	dir, err := s.Dir(kind, name)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove %s directory: %w", kind, err)
+	}
+	return nil
+}
+
+// Info returns the store layout information
+func (s *Store) Info() (*Info, error) {
+	data, err := os.ReadFile(filepath.Join(s.root, stateFilename)) //nolint:gosec // path is constructed from known base directory
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Info{}, nil
+		}
+		return nil, fmt.Errorf("failed to read state info: %w", err)
+	}
+
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal state info: %w", err)
+	}
+	return &info, nil
+}
+
+// writeInfo persists the store layout information
+func (s *Store) writeInfo(info *Info) error {
+	if err := os.MkdirAll(s.root, 0750); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state info: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.root, stateFilename), data, 0600); err != nil {
+		return fmt.Errorf("failed to write state info: %w", err)
+	}
+	return nil
+}
+
+// Size returns the total size in bytes of the files in a record's directory
+func (s *Store) Size(kind Kind, name string) (int64, error) {
+	dir, err := s.Dir(kind, name)
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	err = filepath.WalkDir(dir, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure %s directory: %w", kind, err)
+	}
+	return size, nil
+}
@@ -0,0 +1,253 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type record struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestStoreSaveLoadRemove(t *testing.T) {
+	store, err := OpenAt(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenAt() error: %v", err)
+	}
+
+	if err := store.Save(Runs, "b", &record{Name: "b", Count: 2}); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if err := store.Save(Runs, "a", &record{Name: "a", Count: 1}); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	var got record
+	if err := store.Load(Runs, "b", &got); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if got.Name != "b" || got.Count != 2 {
+		t.Errorf("Load() = %+v, want {b 2}", got)
+	}
+
+	names, err := store.Names(Runs)
+	if err != nil {
+		t.Fatalf("Names() error: %v", err)
+	}
+	if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Errorf("Names() = %v, want [a b]", names)
+	}
+
+	if err := store.Remove(Runs, "a"); err != nil {
+		t.Fatalf("Remove() error: %v", err)
+	}
+	if err := store.Load(Runs, "a", &got); err == nil {
+		t.Error("Load() after Remove() expected error")
+	}
+}
+
+func TestStoreRejectsEscapingNames(t *testing.T) {
+	store, err := OpenAt(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenAt() error: %v", err)
+	}
+	if err := store.Save(Runs, "run-1", &record{Name: "run-1"}); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	for _, name := range []string{"", ".", "..", "../runs/run-1", "a/b"} {
+		if _, err := store.Dir(Topologies, name); err == nil {
+			t.Errorf("Dir(%q) expected error", name)
+		}
+		var got record
+		if err := store.Load(Topologies, name, &got); err == nil {
+			t.Errorf("Load(%q) expected error", name)
+		}
+		if err := store.Remove(Topologies, name); err == nil {
+			t.Errorf("Remove(%q) expected error", name)
+		}
+	}
+
+	// The run another kind's name pointed at is untouched
+	var got record
+	if err := store.Load(Runs, "run-1", &got); err != nil {
+		t.Errorf("Load() error after rejected removals: %v", err)
+	}
+}
+
+func TestStoreNamesMissingKind(t *testing.T) {
+	store, err := OpenAt(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenAt() error: %v", err)
+	}
+
+	names, err := store.Names(Topologies)
+	if err != nil {
+		t.Fatalf("Names() error: %v", err)
+	}
+	if len(names) != 0 {
+		t.Errorf("Names() = %v, want empty", names)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	t.Run("stamps unversioned store", func(t *testing.T) {
+		dir := t.TempDir()
+		store, err := OpenAt(dir)
+		if err != nil {
+			t.Fatalf("OpenAt() error: %v", err)
+		}
+		info, err := store.Info()
+		if err != nil {
+			t.Fatalf("Info() error: %v", err)
+		}
+		if info.SchemaVersion != CurrentSchemaVersion {
+			t.Errorf("SchemaVersion = %d, want %d", info.SchemaVersion, CurrentSchemaVersion)
+		}
+	})
+
+	t.Run("rejects newer schema", func(t *testing.T) {
+		dir := t.TempDir()
+		data := []byte(`{"schemaVersion": 999}`)
+		if err := os.WriteFile(filepath.Join(dir, stateFilename), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := OpenAt(dir); err == nil {
+			t.Error("OpenAt() expected error for newer schema version")
+		}
+	})
+}
+
+func TestRoot(t *testing.T) {
+	home := t.TempDir()
+	tests := []struct {
+		name   string
+		env    string
+		xdg    string
+		legacy bool
+		want   string
+	}{
+		{name: "XDG default", want: filepath.Join(home, ".local", "share", "kueue-bench")},
+		{name: "XDG_DATA_HOME", xdg: "/data", want: "/data/kueue-bench"},
+		{name: "relative XDG_DATA_HOME ignored", xdg: "data", want: filepath.Join(home, ".local", "share", "kueue-bench")},
+		{name: "existing legacy directory", xdg: "/data", legacy: true, want: filepath.Join(home, ".kueue-bench")},
+		{name: "environment", env: "/ci/job-1", legacy: true, want: "/ci/job-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", home)
+			t.Setenv("XDG_DATA_HOME", tt.xdg)
+			t.Setenv(DirEnv, tt.env)
+			legacy := filepath.Join(home, ".kueue-bench")
+			if tt.legacy {
+				if err := os.MkdirAll(legacy, 0750); err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { _ = os.RemoveAll(legacy) })
+			}
+
+			got, err := Root()
+			if err != nil {
+				t.Fatalf("Root() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Root() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+type versionedRecord struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Name          string `json:"name"`
+	Size          int64  `json:"size"`
+}
+
+func TestLoadVersioned(t *testing.T) {
+	migrations := []RecordMigration{
+		{Version: 1, Description: "adopt unversioned", Apply: func(map[string]any) error { return nil }},
+		{Version: 2, Description: "rename count to size", Apply: func(doc map[string]any) error {
+			doc["size"] = doc["count"]
+			delete(doc, "count")
+			return nil
+		}},
+	}
+
+	tests := []struct {
+		name      string
+		data      string
+		want      versionedRecord
+		wantErr   bool
+		wantNewer bool
+	}{
+		{
+			name: "unversioned",
+			data: `{"name": "a", "count": 9007199254740993}`,
+			want: versionedRecord{SchemaVersion: 2, Name: "a", Size: 9007199254740993},
+		},
+		{
+			name: "version 1",
+			data: `{"schemaVersion": 1, "name": "a", "count": 3}`,
+			want: versionedRecord{SchemaVersion: 2, Name: "a", Size: 3},
+		},
+		{
+			name: "current",
+			data: `{"schemaVersion": 2, "name": "a", "size": 3, "count": 7}`,
+			want: versionedRecord{SchemaVersion: 2, Name: "a", Size: 3},
+		},
+		{
+			name:      "newer",
+			data:      `{"schemaVersion": 3, "name": "a"}`,
+			wantErr:   true,
+			wantNewer: true,
+		},
+		{
+			name:    "invalid version",
+			data:    `{"schemaVersion": "two", "name": "a"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := OpenAt(t.TempDir())
+			if err != nil {
+				t.Fatalf("OpenAt() error: %v", err)
+			}
+			dir := store.Path(Topologies, "a")
+			if err := os.MkdirAll(dir, 0750); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, metadataFilename), []byte(tt.data), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			var got versionedRecord
+			err = store.LoadVersioned(Topologies, "a", &got, migrations)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadVersioned() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var newer *NewerSchemaError
+			if errors.As(err, &newer) != tt.wantNewer {
+				t.Errorf("LoadVersioned() error = %v, want *NewerSchemaError %v", err, tt.wantNewer)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("LoadVersioned() = %+v, want %+v", got, tt.want)
+			}
+
+			// The upgraded record was saved back
+			var saved versionedRecord
+			if err := store.Load(Topologies, "a", &saved); err != nil {
+				t.Fatalf("Load() error: %v", err)
+			}
+			if saved != tt.want {
+				t.Errorf("saved record = %+v, want %+v", saved, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/cluster"
+	"github.com/jhwagner/kueue-bench/pkg/config"
+)
+
+// WorkerDown simulates an outage of a MultiKueue worker by pausing its kind node containers
+// for duration, then resuming them. The worker is also resumed if ctx is cancelled first.
+func (t *Topology) WorkerDown(ctx context.Context, worker string, duration time.Duration) error {
+	c, ok := t.metadata.Clusters[worker]
+	if !ok {
+		return fmt.Errorf("worker %q not found in topology %q", worker, t.metadata.Name)
+	}
+	if c.Role != config.RoleWorker {
+		return fmt.Errorf("cluster %q is not a MultiKueue worker", worker)
+	}
+	if c.External {
+		return fmt.Errorf("worker %q is external; kueue-bench can only pause clusters it created", worker)
+	}
+
+	if err := cluster.PauseCluster(ctx, c.KindClusterName); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Worker '%s' is down, restoring in %s\n", worker, duration)
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		fmt.Println("Interrupted, restoring worker early...")
+	}
+
+	// Resume even if ctx was cancelled, so the worker is never left paused
+	if err := cluster.UnpauseCluster(context.WithoutCancel(ctx), c.KindClusterName); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Worker '%s' restored\n", worker)
+	return nil
+}
+
+// DeleteWorker deletes the kind cluster of a MultiKueue worker for good, simulating a lost
+// worker. The worker stays in the topology's metadata; recreate the topology to restore it.
+func (t *Topology) DeleteWorker(ctx context.Context, worker string) error {
+	c, ok := t.metadata.Clusters[worker]
+	if !ok {
+		return fmt.Errorf("worker %q not found in topology %q", worker, t.metadata.Name)
+	}
+	if c.Role != config.RoleWorker {
+		return fmt.Errorf("cluster %q is not a MultiKueue worker", worker)
+	}
+	if c.External {
+		return fmt.Errorf("worker %q is external; kueue-bench can only delete clusters it created", worker)
+	}
+	return cluster.DeleteCluster(ctx, c.KindClusterName)
+}
+
+// MultiKueueClusterNames returns the names of the MultiKueueClusters through which a
+// management cluster reaches a worker, one per WorkerSet it belongs to
+func (t *Topology) MultiKueueClusterNames(management, worker string) ([]string, error) {
+	if t.metadata.ConfigPath == "" {
+		return nil, fmt.Errorf("topology %q has no recorded config; recreate it to manage MultiKueue", t.metadata.Name)
+	}
+	cfg, err := config.LoadTopology(t.metadata.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, ws := range config.WorkerSetsFor(cfg.Spec.WorkerSets, management) {
+		for _, w := range ws.Workers {
+			if w.Name == worker {
+				names = append(names, ws.MultiKueueClusterName(worker))
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("worker %q is not a MultiKueue worker of cluster %q", worker, management)
+	}
+	return names, nil
+}
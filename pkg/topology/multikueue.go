@@ -0,0 +1,287 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/kueue"
+)
+
+// RotateMultiKueueCredentials regenerates the kubeconfig of every MultiKueue worker, checks
+// the worker accepts it, updates its Secret on the management cluster and waits for the
+// MultiKueueClusters to reconnect.
+// Workers with serviceAccount credentials get a new token and the old one is revoked;
+// admin kubeconfigs are extracted again, since kind's client certificates cannot be reissued.
+func (t *Topology) RotateMultiKueueCredentials(ctx context.Context) error {
+	managers, err := t.multiKueueManagers()
+	if err != nil {
+		return err
+	}
+
+	// Shared workers are rotated once; a second rotation would revoke the first token
+	rotated := make(map[string][]byte)
+	for _, m := range managers {
+		for _, ws := range m.workerSets {
+			for _, worker := range ws.Workers {
+				kubeconfigData, ok := rotated[worker.Name]
+				if !ok {
+					if kubeconfigData, err = t.freshWorkerKubeconfig(ctx, ws, worker.Name, true); err != nil {
+						return err
+					}
+					if err := t.checkWorkerCredentials(ctx, worker.Name, kubeconfigData); err != nil {
+						return err
+					}
+					rotated[worker.Name] = kubeconfigData
+				}
+				secretName, err := ws.KubeconfigSecretName(worker.Name)
+				if err != nil {
+					return err
+				}
+				if err := m.client.CreateKubeconfigSecret(ctx, ws.Namespace(), secretName, kubeconfigData); err != nil {
+					return fmt.Errorf("failed to update kubeconfig secret for worker %q: %w", worker.Name, err)
+				}
+				fmt.Printf("✓ Rotated credentials for worker '%s'\n", worker.Name)
+			}
+		}
+	}
+
+	fmt.Println("Waiting for MultiKueueClusters to reconnect...")
+	for _, m := range managers {
+		if err := m.client.WaitForActive(ctx, kueue.DefaultActiveTimeout); err != nil {
+			return fmt.Errorf("kueue objects in management cluster '%s' did not become active after rotating MultiKueue credentials: %w", m.name, err)
+		}
+	}
+	return nil
+}
+
+// WorkerHealth is the MultiKueue connection state of a worker
+type WorkerHealth struct {
+	// Management is the management cluster dispatching to the worker
+	Management string
+	WorkerSet  string
+	Worker     string
+	// Cluster is the worker's MultiKueueCluster name
+	Cluster   string
+	Connected bool
+	// Reason explains why the worker is not connected
+	Reason string
+}
+
+// CheckMultiKueue reports the MultiKueue connection state of every worker, as seen by the
+// MultiKueueClusters on the management cluster
+func (t *Topology) CheckMultiKueue(ctx context.Context) ([]WorkerHealth, error) {
+	managers, err := t.multiKueueManagers()
+	if err != nil {
+		return nil, err
+	}
+
+	var health []WorkerHealth
+	for _, m := range managers {
+		connections, err := m.client.MultiKueueConnections(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("management cluster '%s': %w", m.name, err)
+		}
+		byCluster := make(map[string]kueue.WorkerConnection, len(connections))
+		for _, c := range connections {
+			byCluster[c.Cluster] = c
+		}
+
+		for _, ws := range m.workerSets {
+			for _, worker := range ws.RankedWorkers() {
+				h := WorkerHealth{Management: m.name, WorkerSet: ws.Name, Worker: worker, Cluster: ws.MultiKueueClusterName(worker)}
+				if c, ok := byCluster[h.Cluster]; ok {
+					h.Connected, h.Reason = c.Connected, c.Reason
+				} else {
+					h.Reason = "MultiKueueCluster not found"
+				}
+				health = append(health, h)
+			}
+		}
+	}
+	return health, nil
+}
+
+// AuditMultiKueue checks that the Workloads on each management cluster and on its workers
+// are consistent, as kueue.AuditMultiKueueWorkloads describes. External workers are included.
+func (t *Topology) AuditMultiKueue(ctx context.Context) ([]kueue.WorkloadInconsistency, error) {
+	managers, err := t.multiKueueManagers()
+	if err != nil {
+		return nil, err
+	}
+
+	var found []kueue.WorkloadInconsistency
+	for _, m := range managers {
+		management, err := m.client.ClusterWorkloads(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("management cluster '%s': %w", m.name, err)
+		}
+
+		workers := make(map[string]kueue.ClusterWorkloads)
+		clusterWorkers := make(map[string]string)
+		for _, ws := range m.workerSets {
+			for _, worker := range ws.Workers {
+				clusterWorkers[ws.MultiKueueClusterName(worker.Name)] = worker.Name
+				if _, ok := workers[worker.Name]; ok {
+					continue
+				}
+				c, ok := t.metadata.Clusters[worker.Name]
+				if !ok {
+					return nil, fmt.Errorf("worker %q not found in topology %q", worker.Name, t.metadata.Name)
+				}
+				client, err := kueue.NewClient(c.KubeconfigPath, t.clientOption())
+				if err != nil {
+					return nil, fmt.Errorf("failed to create Kueue client for worker %q: %w", worker.Name, err)
+				}
+				if workers[worker.Name], err = client.ClusterWorkloads(ctx); err != nil {
+					return nil, fmt.Errorf("worker '%s': %w", worker.Name, err)
+				}
+			}
+		}
+		found = append(found, kueue.AuditMultiKueueWorkloads(management, workers, clusterWorkers)...)
+	}
+	return found, nil
+}
+
+// RepairMultiKueue extracts the kubeconfigs of the given workers again, checks the workers
+// accept them, rewrites their
+// Secrets and MultiKueueClusters on the management cluster and waits for them to become
+// active. Unlike RotateMultiKueueCredentials, existing ServiceAccount tokens are kept.
+func (t *Topology) RepairMultiKueue(ctx context.Context, workers []string) error {
+	managers, err := t.multiKueueManagers()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range managers {
+		repaired := false
+		for _, ws := range m.workerSets {
+			for _, worker := range ws.Workers {
+				if !slices.Contains(workers, worker.Name) {
+					continue
+				}
+				kubeconfigData, err := t.freshWorkerKubeconfig(ctx, ws, worker.Name, false)
+				if err != nil {
+					return err
+				}
+				if err := t.checkWorkerCredentials(ctx, worker.Name, kubeconfigData); err != nil {
+					return err
+				}
+				secretName, err := ws.KubeconfigSecretName(worker.Name)
+				if err != nil {
+					return err
+				}
+				if err := m.client.CreateKubeconfigSecret(ctx, ws.Namespace(), secretName, kubeconfigData); err != nil {
+					return fmt.Errorf("failed to update kubeconfig secret for worker %q: %w", worker.Name, err)
+				}
+				if err := m.client.CreateMultiKueueCluster(ctx, kueue.BuildMultiKueueCluster(ws.MultiKueueClusterName(worker.Name), secretName)); err != nil {
+					return fmt.Errorf("failed to update MultiKueueCluster for worker %q: %w", worker.Name, err)
+				}
+				fmt.Printf("✓ Updated kubeconfig for worker '%s'\n", worker.Name)
+				repaired = true
+			}
+		}
+
+		if !repaired {
+			continue
+		}
+		if err := m.client.WaitForActive(ctx, kueue.DefaultActiveTimeout); err != nil {
+			return fmt.Errorf("kueue objects in management cluster '%s' did not become active after repairing MultiKueue: %w", m.name, err)
+		}
+	}
+	return nil
+}
+
+// checkWorkerCredentials checks a worker accepts the kubeconfig the management cluster is
+// given for it. Waiting for MultiKueueClusters to be active does not show this: they stay
+// Active with the credentials they had before.
+func (t *Topology) checkWorkerCredentials(ctx context.Context, workerName string, kubeconfigData []byte) error {
+	c, ok := t.metadata.Clusters[workerName]
+	if !ok {
+		return fmt.Errorf("worker %q not found in topology %q", workerName, t.metadata.Name)
+	}
+	if err := kueue.CheckWorkerKubeconfig(ctx, kubeconfigData, c.KubeconfigPath); err != nil {
+		return fmt.Errorf("new kubeconfig for worker %q does not work: %w", workerName, err)
+	}
+	return nil
+}
+
+// freshWorkerKubeconfig extracts the kubeconfig the management cluster uses for a worker in
+// the WorkerSet again. With rotate, a worker's ServiceAccount token is revoked and reissued.
+func (t *Topology) freshWorkerKubeconfig(ctx context.Context, ws config.WorkerSet, workerName string, rotate bool) ([]byte, error) {
+	c, ok := t.metadata.Clusters[workerName]
+	if !ok {
+		return nil, fmt.Errorf("worker %q not found in topology %q", workerName, t.metadata.Name)
+	}
+	var external string
+	if c.External {
+		external = c.KubeconfigPath
+	}
+	kubeconfigData, err := t.workerKubeconfig(ctx, workerName, external)
+	if err != nil {
+		return nil, err
+	}
+	if ws.Credentials != config.CredentialsServiceAccount {
+		return kubeconfigData, nil
+	}
+
+	workerClient, err := kueue.NewClient(c.KubeconfigPath, t.clientOption())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kueue client for worker %q: %w", workerName, err)
+	}
+	workerClient.SetTopology(t.metadata.Name)
+	if rotate {
+		kubeconfigData, err = kueue.RotateServiceAccountKubeconfig(ctx, workerClient, ws.Namespace(), kubeconfigData)
+	} else {
+		kubeconfigData, err = kueue.ServiceAccountKubeconfig(ctx, workerClient, ws.Namespace(), kubeconfigData)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get MultiKueue ServiceAccount kubeconfig for worker %q: %w", workerName, err)
+	}
+	return kubeconfigData, nil
+}
+
+// multiKueueManager is a management cluster and the WorkerSets it dispatches to
+type multiKueueManager struct {
+	name       string
+	client     *kueue.Client
+	workerSets []config.WorkerSet
+}
+
+// multiKueueManagers loads the topology's config and returns a Kueue client for each
+// management cluster, sorted by name, with the WorkerSets it manages
+func (t *Topology) multiKueueManagers() ([]multiKueueManager, error) {
+	if t.metadata.ConfigPath == "" {
+		return nil, fmt.Errorf("topology %q has no recorded config; recreate it to manage MultiKueue", t.metadata.Name)
+	}
+	cfg, err := config.LoadTopology(t.metadata.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Spec.WorkerSets) == 0 {
+		return nil, fmt.Errorf("topology %q has no MultiKueue workerSets", t.metadata.Name)
+	}
+
+	var managers []multiKueueManager
+	for _, c := range t.metadata.Clusters {
+		if c.Role != config.RoleManagement {
+			continue
+		}
+		client, err := kueue.NewClient(c.KubeconfigPath, t.clientOption())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Kueue client for management cluster '%s': %w", c.Name, err)
+		}
+		managers = append(managers, multiKueueManager{
+			name:       c.Name,
+			client:     client,
+			workerSets: config.WorkerSetsFor(cfg.Spec.WorkerSets, c.Name),
+		})
+	}
+	if len(managers) == 0 {
+		return nil, fmt.Errorf("topology %q has no management cluster", t.metadata.Name)
+	}
+	sort.Slice(managers, func(i, j int) bool { return managers[i].name < managers[j].name })
+	return managers, nil
+}
@@ -0,0 +1,49 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/kwok"
+)
+
+// ReconcileNodes converges a cluster's Kwok nodes to the node pools in the topology's
+// recorded config: missing nodes are created and extra ones deleted, then the pools are
+// waited on to be Ready. It resumes node creation interrupted part way and corrects drift,
+// e.g. nodes deleted with delete-nodes or by chaos.
+func (t *Topology) ReconcileNodes(ctx context.Context, clusterName string) (kwok.NodeReconcileResult, error) {
+	var result kwok.NodeReconcileResult
+	c, ok := t.metadata.Clusters[clusterName]
+	if !ok {
+		return result, fmt.Errorf("cluster %q not found in topology %q", clusterName, t.metadata.Name)
+	}
+	if c.External {
+		return result, fmt.Errorf("cluster %q is external; kueue-bench does not manage its nodes", clusterName)
+	}
+	if t.metadata.ConfigPath == "" {
+		return result, fmt.Errorf("topology %q has no recorded config; recreate it to reconcile nodes", t.metadata.Name)
+	}
+	cfg, err := config.LoadTopology(t.metadata.ConfigPath)
+	if err != nil {
+		return result, err
+	}
+	clusters, err := config.ExpandTopology(cfg)
+	if err != nil {
+		return result, err
+	}
+
+	for _, clusterCfg := range clusters {
+		if clusterCfg.Name != clusterName {
+			continue
+		}
+		if result, err = kwok.ReconcileNodes(ctx, c.KubeconfigPath, t.metadata.Name, clusterCfg.NodePools, t.clientOption()); err != nil {
+			return result, fmt.Errorf("failed to reconcile nodes in cluster '%s': %w", clusterName, err)
+		}
+		if err := kwok.WaitForNodesReady(ctx, c.KubeconfigPath, clusterCfg.NodePools, t.clientOption()); err != nil {
+			return result, fmt.Errorf("nodes not ready in cluster '%s': %w", clusterName, err)
+		}
+		return result, nil
+	}
+	return result, fmt.Errorf("cluster %q not found in the config of topology %q", clusterName, t.metadata.Name)
+}
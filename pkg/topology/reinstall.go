@@ -0,0 +1,109 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/kueue"
+)
+
+// ReinstallOptions configure Topology.ReinstallKueue
+type ReinstallOptions struct {
+	// Version is the Kueue release to install; empty reinstalls the topology's Kueue version
+	Version string
+	// RecreateObjects deletes the cluster's Kueue objects before uninstalling and provisions
+	// them again after installing. By default objects are kept across the reinstall.
+	RecreateObjects bool
+}
+
+// ReinstallKueue uninstalls Kueue from a cluster and installs it again, e.g. at another
+// version to test an upgrade or downgrade. The install uses the topology's Kueue settings
+// (Helm values, manager config) and the cluster's feature gates. Kueue's CRDs are kept, so
+// existing objects are served by the new version unless RecreateObjects is set.
+func (t *Topology) ReinstallKueue(ctx context.Context, clusterName string, opts ReinstallOptions) error {
+	c, ok := t.metadata.Clusters[clusterName]
+	if !ok {
+		return fmt.Errorf("cluster %q not found in topology %q", clusterName, t.metadata.Name)
+	}
+	if c.External {
+		return fmt.Errorf("cluster %q is external; kueue-bench does not manage its Kueue install", clusterName)
+	}
+	if t.metadata.ConfigPath == "" {
+		return fmt.Errorf("topology %q has no recorded config; recreate it to reinstall Kueue", t.metadata.Name)
+	}
+	cfg, err := config.LoadTopology(t.metadata.ConfigPath)
+	if err != nil {
+		return err
+	}
+
+	settings, err := newClusterSettings(cfg)
+	if err != nil {
+		return err
+	}
+	installOpts := settings.kueueInstallOptions(clusterName, c.KueueFeatureGates)
+	if installOpts.Manifest != "" {
+		return fmt.Errorf("kueue was installed from a manifest and cannot be uninstalled; only Helm installs can be reinstalled")
+	}
+	switch {
+	case opts.Version != "":
+		installOpts.Version = opts.Version
+		installOpts.Chart = ""
+	case cfg.Spec.Kueue != nil && cfg.Spec.Kueue.BuildFrom != nil:
+		return fmt.Errorf("kueue was built from source; specify a version to reinstall")
+	}
+
+	var kueueConfig *config.KueueConfig
+	if opts.RecreateObjects {
+		if c.KueueConfigPath == "" {
+			return fmt.Errorf("cluster %q has no recorded Kueue config; recreate the topology to recreate its objects", clusterName)
+		}
+		if kueueConfig, err = config.LoadKueueConfig(c.KueueConfigPath); err != nil {
+			return err
+		}
+	}
+
+	client, err := kueue.NewClient(c.KubeconfigPath, t.clientOption())
+	if err != nil {
+		return fmt.Errorf("failed to create Kueue client for cluster '%s': %w", clusterName, err)
+	}
+	client.SetTopology(t.metadata.Name)
+
+	if kueueConfig != nil {
+		if err := kueue.DeprovisionKueueObjects(ctx, client, kueueConfig); err != nil {
+			return fmt.Errorf("failed to delete Kueue objects in cluster '%s': %w", clusterName, err)
+		}
+	}
+	if err := kueue.Uninstall(ctx, c.KubeconfigPath, t.clientOption()); err != nil {
+		return fmt.Errorf("failed to uninstall Kueue in cluster '%s': %w", clusterName, err)
+	}
+	if err := kueue.Install(ctx, c.KubeconfigPath, installOpts, t.clientOption()); err != nil {
+		return fmt.Errorf("failed to install Kueue in cluster '%s': %w", clusterName, err)
+	}
+	crds, err := kueueCRDVersions(ctx, c.KubeconfigPath, t.clientOption())
+	if err != nil {
+		return fmt.Errorf("failed to check Kueue CRDs in cluster '%s': %w", clusterName, err)
+	}
+	for _, change := range kueue.CRDVersionChanges(c.KueueCRDs, crds) {
+		fmt.Printf("  CRD %s\n", change)
+	}
+	c.KueueCRDs = crds
+	c.WorkloadRunLabels = installOpts.CopiesRunLabel()
+	t.metadata.Clusters[clusterName] = c
+	if err := t.save(); err != nil {
+		return err
+	}
+	if err := client.WaitForAPIReady(ctx, kueue.DefaultAPIReadyTimeout); err != nil {
+		return fmt.Errorf("cluster '%s' not ready: %w", clusterName, err)
+	}
+	if kueueConfig != nil {
+		if err := kueue.ProvisionKueueObjects(ctx, client, kueueConfig, settings.provisionConcurrency); err != nil {
+			return fmt.Errorf("failed to provision Kueue objects in cluster '%s': %w", clusterName, err)
+		}
+	}
+
+	if err := client.WaitForActive(ctx, kueue.DefaultActiveTimeout); err != nil {
+		return fmt.Errorf("kueue objects in cluster '%s' did not become active: %w", clusterName, err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package topology
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Creation stages timed per cluster, in the order they run. Kueue is installed concurrently
+// with Kwok and its nodes, so their durations overlap.
+const (
+	StageKind       = "kind"
+	StageImages     = "images"
+	StageKwok       = "kwok"
+	StageNodes      = "nodes"
+	StageKueue      = "kueue"
+	StageExtensions = "extensions"
+	StageAPIReady   = "api-ready"
+	StageMultiKueue = "multikueue"
+	StageObjects    = "objects"
+)
+
+// Stages lists the creation stages in the order they run
+var Stages = []string{
+	StageKind, StageImages, StageKwok, StageNodes, StageKueue,
+	StageExtensions, StageAPIReady, StageMultiKueue, StageObjects,
+}
+
+// stageTimer records the durations of a cluster's creation stages. Safe for concurrent use.
+type stageTimer struct {
+	start  time.Time
+	mu     sync.Mutex
+	stages []StageTiming
+}
+
+func newStageTimer() *stageTimer {
+	return &stageTimer{start: time.Now()}
+}
+
+// time runs fn as the named stage and records its duration, whether or not it fails
+func (s *stageTimer) time(stage string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stages = append(s.stages, StageTiming{Stage: stage, Duration: time.Since(start)})
+	return err
+}
+
+// record stores the timed stages and the time since the timer started in a cluster's metadata
+func (s *stageTimer) record(c *Cluster) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stages := slices.Clone(s.stages)
+	slices.SortStableFunc(stages, func(a, b StageTiming) int {
+		return slices.Index(Stages, a.Stage) - slices.Index(Stages, b.Stage)
+	})
+	c.Stages = stages
+	c.SetupDuration = time.Since(s.start)
+}
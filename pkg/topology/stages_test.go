@@ -0,0 +1,77 @@
+package topology
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStageTimerTime(t *testing.T) {
+	timer := newStageTimer()
+
+	if err := timer.time(StageKind, func() error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}); err != nil {
+		t.Fatalf("time() error = %v", err)
+	}
+
+	// A failed stage is still recorded, and its error returned unchanged
+	wantErr := errors.New("install failed")
+	if err := timer.time(StageKueue, func() error { return wantErr }); !errors.Is(err, wantErr) {
+		t.Fatalf("time() error = %v, want %v", err, wantErr)
+	}
+
+	var c Cluster
+	timer.record(&c)
+	if len(c.Stages) != 2 {
+		t.Fatalf("recorded %d stages, want 2: %v", len(c.Stages), c.Stages)
+	}
+	if c.Stages[0].Stage != StageKind || c.Stages[0].Duration < 10*time.Millisecond {
+		t.Errorf("stage 0 = %+v, want %s lasting at least 10ms", c.Stages[0], StageKind)
+	}
+	if c.Stages[1].Stage != StageKueue {
+		t.Errorf("stage 1 = %+v, want %s", c.Stages[1], StageKueue)
+	}
+	if c.SetupDuration < c.Stages[0].Duration {
+		t.Errorf("SetupDuration = %s, want at least %s", c.SetupDuration, c.Stages[0].Duration)
+	}
+}
+
+func TestStageTimerRecordOrder(t *testing.T) {
+	timer := newStageTimer()
+
+	// Stages finishing out of order, e.g. Kueue installed concurrently with Kwok and its nodes
+	var wg sync.WaitGroup
+	for _, stage := range []string{StageObjects, StageNodes, StageKueue, StageKwok, StageKind} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = timer.time(stage, func() error { return nil })
+		}()
+	}
+	wg.Wait()
+
+	var c Cluster
+	timer.record(&c)
+	want := []string{StageKind, StageKwok, StageNodes, StageKueue, StageObjects}
+	if len(c.Stages) != len(want) {
+		t.Fatalf("recorded %d stages, want %d: %v", len(c.Stages), len(want), c.Stages)
+	}
+	for i, stage := range want {
+		if c.Stages[i].Stage != stage {
+			t.Errorf("stage %d = %s, want %s", i, c.Stages[i].Stage, stage)
+		}
+	}
+
+	// Stages timed after a record are included, in order, by the next one
+	_ = timer.time(StageMultiKueue, func() error { return nil })
+	timer.record(&c)
+	if got := c.Stages[len(c.Stages)-1].Stage; got != StageObjects {
+		t.Errorf("last stage = %s, want %s", got, StageObjects)
+	}
+	if got := c.Stages[len(c.Stages)-2].Stage; got != StageMultiKueue {
+		t.Errorf("second to last stage = %s, want %s", got, StageMultiKueue)
+	}
+}
@@ -1,48 +1,91 @@
 package topology
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"time"
 
+	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v3"
+
 	"github.com/jhwagner/kueue-bench/pkg/cluster"
 	"github.com/jhwagner/kueue-bench/pkg/config"
 	"github.com/jhwagner/kueue-bench/pkg/extensions"
 	"github.com/jhwagner/kueue-bench/pkg/kueue"
 	"github.com/jhwagner/kueue-bench/pkg/kwok"
-)
-
-const (
-	metadataDir      = ".kueue-bench/topologies"
-	metadataFilename = "metadata.json"
+	"github.com/jhwagner/kueue-bench/pkg/manifest"
+	"github.com/jhwagner/kueue-bench/pkg/ownership"
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+	"github.com/jhwagner/kueue-bench/pkg/state"
 )
 
 // Topology represents a Kueue test topology
 type Topology struct {
 	metadata *Metadata
+	// store is the Store the topology was created in or loaded from
+	store *Store
+}
+
+// Store keeps topology metadata, kubeconfigs and recorded configs in a state directory
+type Store struct {
+	state *state.Store
+}
+
+// NewStore returns a Store keeping topologies in s
+func NewStore(s *state.Store) *Store {
+	return &Store{state: s}
+}
+
+// OpenStore opens the Store in the default state directory (see state.Root)
+func OpenStore() (*Store, error) {
+	s, err := state.Open()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(s), nil
+}
+
+// Create creates a topology in the default state directory; see Store.Create
+func Create(ctx context.Context, name string, cfg *config.Topology) (*Topology, error) {
+	store, err := OpenStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.Create(ctx, name, cfg)
 }
 
-// Create creates a new topology with all its clusters and components
-func Create(ctx context.Context, name string, cfg *config.Topology) (t *Topology, err error) {
+// Create creates a new topology with all its clusters and components. Creating a topology
+// again reuses the kind clusters it already has and converges them to cfg, so a create that
+// failed part way can be re-run; kind-level settings of reused clusters are not changed.
+func (s *Store) Create(ctx context.Context, name string, cfg *config.Topology) (t *Topology, err error) {
+	if err := config.CheckKueueSource(cfg.Spec.Kueue); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
 	t = &Topology{
 		metadata: &Metadata{
 			Name:      name,
 			CreatedAt: time.Now(),
 			Clusters:  make(map[string]Cluster),
 		},
+		store: s,
 	}
 
 	// Get topology directory for storing kubeconfigs
-	topologyDir, err := getTopologyDir(name)
+	topologyDir, err := s.state.Dir(state.Topologies, name)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create topology directory
+	// Create topology directory, unless an earlier create of this topology left it
+	_, statErr := os.Stat(topologyDir)
+	existingDir := statErr == nil
 	if err := os.MkdirAll(topologyDir, 0750); err != nil {
 		return nil, fmt.Errorf("failed to create topology directory: %w", err)
 	}
@@ -55,54 +98,68 @@ func Create(ctx context.Context, name string, cfg *config.Topology) (t *Topology
 		if err != nil {
 			if len(createdClusters) > 0 {
 				fmt.Fprintf(os.Stderr, "\nTopology creation failed, cleaning up %d cluster(s)...\n", len(createdClusters))
+				// Clean up even when creation failed because ctx was cancelled (e.g. Ctrl-C)
+				cleanupCtx := context.WithoutCancel(ctx)
 				for _, kindClusterName := range createdClusters {
-					if err := cluster.DeleteCluster(ctx, kindClusterName); err != nil {
+					if err := cluster.DeleteCluster(cleanupCtx, kindClusterName); err != nil {
 						fmt.Fprintf(os.Stderr, "Warning: failed to cleanup cluster %s: %v\n", kindClusterName, err)
 					}
 				}
 			}
-			// Remove topology directory
-			if err := os.RemoveAll(topologyDir); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to remove topology directory: %v\n", err)
+			// Remove topology directory, keeping one that still describes reused clusters
+			if !existingDir {
+				if err := os.RemoveAll(topologyDir); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to remove topology directory: %v\n", err)
+				}
 			}
 		}
 	}()
 
-	// Get Kwok version from spec
-	kwokVersion := kwok.DefaultKwokVersion
-	if cfg.Spec.Kwok != nil && cfg.Spec.Kwok.Version != "" {
-		kwokVersion = cfg.Spec.Kwok.Version
+	settings, err := newClusterSettings(cfg)
+	if err != nil {
+		return nil, err
 	}
+	settings.topologyName = name
+	settings.recordedKindClusters = s.recordedKindClusters(name)
+	settings.state = s.state
 
-	// Get Kueue version and helm values from spec
-	kueueVersion := kueue.DefaultKueueVersion
-	var kueueHelmValues map[string]interface{}
-	if cfg.Spec.Kueue != nil {
-		if cfg.Spec.Kueue.Version != "" {
-			kueueVersion = cfg.Spec.Kueue.Version
+	if cfg.Spec.Client != nil {
+		overrides, err := clientOverrides(cfg.Spec.Client)
+		if err != nil {
+			return nil, err
 		}
-		kueueHelmValues = cfg.Spec.Kueue.HelmValues
+		t.metadata.Client = &overrides
+		settings.client = restconfig.WithOverrides(&overrides)
 	}
 
-	// Expand WorkerSets into worker ClusterConfigs
-	expandedWorkers, err := config.ExpandWorkerSets(cfg.Spec.WorkerSets)
-	if err != nil {
-		return nil, fmt.Errorf("failed to expand worker sets: %w", err)
+	if err := t.recordConfig(topologyDir, cfg); err != nil {
+		return nil, err
 	}
 
-	// Combine explicit clusters with expanded workers (new slice to avoid mutating cfg.Spec.Clusters)
-	allClusters := make([]config.ClusterConfig, 0, len(cfg.Spec.Clusters)+len(expandedWorkers))
-	allClusters = append(allClusters, cfg.Spec.Clusters...)
-	allClusters = append(allClusters, expandedWorkers...)
+	// Build Kueue from source once; the image is then loaded into every cluster
+	if cfg.Spec.Kueue != nil && cfg.Spec.Kueue.BuildFrom != nil {
+		var image string
+		image, settings.kueue, err = kueue.BuildFromSource(ctx, cfg.Spec.Kueue.BuildFrom, settings.kueue)
+		if err != nil {
+			return nil, err
+		}
+		settings.images = append(append([]string{}, settings.images...), image)
+	}
+
+	// Expand WorkerSets into worker ClusterConfigs and derive the management Kueue configs
+	allClusters, err := config.ExpandTopology(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	// Classify clusters by role in a single pass
-	var managementCluster *config.ClusterConfig
+	var managementClusters []*config.ClusterConfig
 	var workerClusters []*config.ClusterConfig
 	var standaloneClusters []*config.ClusterConfig
 	for i := range allClusters {
 		switch allClusters[i].Role {
 		case config.RoleManagement:
-			managementCluster = &allClusters[i]
+			managementClusters = append(managementClusters, &allClusters[i])
 		case config.RoleWorker:
 			workerClusters = append(workerClusters, &allClusters[i])
 		default:
@@ -112,186 +169,647 @@ func Create(ctx context.Context, name string, cfg *config.Topology) (t *Topology
 
 	// Create worker clusters first (with Kueue objects)
 	for _, clusterCfg := range workerClusters {
-		if err := t.createCluster(ctx, clusterCfg, topologyDir, kwokVersion, kueueVersion, kueueHelmValues, &createdClusters); err != nil {
+		if clusterCfg.ExternalKubeconfig != "" {
+			if err := t.addExternalCluster(ctx, clusterCfg); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := t.createCluster(ctx, clusterCfg, topologyDir, settings, &createdClusters); err != nil {
 			return nil, err
 		}
 	}
 
 	// Create standalone clusters
 	for _, clusterCfg := range standaloneClusters {
-		if err := t.createCluster(ctx, clusterCfg, topologyDir, kwokVersion, kueueVersion, kueueHelmValues, &createdClusters); err != nil {
+		if err := t.createCluster(ctx, clusterCfg, topologyDir, settings, &createdClusters); err != nil {
 			return nil, err
 		}
 	}
 
-	// Create management cluster (if exists)
-	if managementCluster != nil {
-		// Create cluster infrastructure (kind + Kwok + Kueue + extensions install, but no Kueue objects yet)
-		kubeconfigPath, err := t.createClusterInfrastructure(ctx, managementCluster, topologyDir, kwokVersion, kueueVersion, kueueHelmValues, &createdClusters)
-		if err != nil {
+	// Create management clusters, each dispatching to the workers of its WorkerSets
+	for _, managementCluster := range managementClusters {
+		workerSets := config.WorkerSetsFor(cfg.Spec.WorkerSets, managementCluster.Name)
+		if err := t.createManagementCluster(ctx, managementCluster, workerSets, workerClusters, topologyDir, settings, &createdClusters); err != nil {
 			return nil, err
 		}
+	}
 
-		// Create Kueue client for management cluster (used for MultiKueue setup and object provisioning)
-		kueueClient, err := kueue.NewClient(kubeconfigPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create Kueue client for management cluster: %w", err)
-		}
-
-		// Setup MultiKueue infrastructure (if WorkerSets exist)
-		if len(cfg.Spec.WorkerSets) > 0 {
-			// Get internal kubeconfigs for inter-cluster connectivity
-			// (default kubeconfigs use 127.0.0.1 which is unreachable from other kind containers)
-			workerKubeconfigs := make(map[string][]byte, len(workerClusters))
-			for _, worker := range workerClusters {
-				kindClusterName := t.getKindClusterName(worker.Name)
-				kubeconfigData, err := cluster.GetKubeconfig(kindClusterName, true)
-				if err != nil {
-					return nil, fmt.Errorf("failed to get internal kubeconfig for worker %q: %w", worker.Name, err)
-				}
-				workerKubeconfigs[worker.Name] = kubeconfigData
-			}
+	// Save metadata
+	t.metadata.CreateDuration = time.Since(start)
+	if err := t.save(); err != nil {
+		return nil, fmt.Errorf("failed to save metadata: %w", err)
+	}
 
-			// Create MultiKueue infrastructure (Secrets, MultiKueueClusters, MultiKueueConfigs, AdmissionChecks)
-			if err := kueue.SetupMultiKueueInfrastructure(ctx, kueueClient, cfg.Spec.WorkerSets, workerKubeconfigs); err != nil {
-				return nil, fmt.Errorf("failed to setup MultiKueue infrastructure: %w", err)
-			}
+	return t, nil
+}
+
+// createManagementCluster creates a management cluster and its MultiKueue objects for the
+// given WorkerSets, whose workers must already exist, then provisions its Kueue objects,
+// derived from them by config.ExpandTopology
+func (t *Topology) createManagementCluster(ctx context.Context, managementCluster *config.ClusterConfig, workerSets []config.WorkerSet, workerClusters []*config.ClusterConfig, topologyDir string, settings clusterSettings, createdClusters *[]string) error {
+	// Create cluster infrastructure (kind + Kwok + Kueue + extensions install, but no Kueue objects yet)
+	timer := newStageTimer()
+	kubeconfigPath, err := t.createClusterInfrastructure(ctx, managementCluster, topologyDir, settings, createdClusters, timer)
+	if err != nil {
+		return err
+	}
+
+	// Create Kueue client for management cluster (used for MultiKueue setup and object provisioning)
+	kueueClient, err := kueue.NewClient(kubeconfigPath, t.clientOption())
+	if err != nil {
+		return fmt.Errorf("failed to create Kueue client for management cluster '%s': %w", managementCluster.Name, err)
+	}
+	kueueClient.SetTopology(t.metadata.Name)
+
+	// Setup MultiKueue infrastructure (if WorkerSets exist)
+	if len(workerSets) > 0 {
+		if err := timer.time(StageMultiKueue, func() error {
+			return t.setupMultiKueue(ctx, kueueClient, managementCluster.Name, workerSets, workerClusters)
+		}); err != nil {
+			return err
 		}
+	}
 
-		// Derive management Kueue objects from WorkerSets + user-defined config
-		derivedConfig := config.DeriveManagementKueueConfig(cfg.Spec.WorkerSets, expandedWorkers, managementCluster.Kueue)
+	derivedConfig := managementCluster.Kueue
+	if err := t.recordKueueConfig(managementCluster.Name, topologyDir, derivedConfig); err != nil {
+		return err
+	}
 
-		// Provision management Kueue objects
+	// Provision management Kueue objects
+	if err := timer.time(StageObjects, func() error {
 		if derivedConfig != nil {
-			if err := kueue.ProvisionKueueObjects(ctx, kueueClient, derivedConfig); err != nil {
-				return nil, fmt.Errorf("failed to provision Kueue objects in management cluster: %w", err)
+			if err := kueue.ProvisionKueueObjects(ctx, kueueClient, derivedConfig, settings.provisionConcurrency); err != nil {
+				return fmt.Errorf("failed to provision Kueue objects in management cluster '%s': %w", managementCluster.Name, err)
+			}
+		}
+		if err := kueueClient.WaitForActive(ctx, kueue.DefaultActiveTimeout); err != nil {
+			return fmt.Errorf("kueue objects in management cluster '%s' did not become active: %w", managementCluster.Name, err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	t.recordStages(managementCluster.Name, timer)
+
+	// Shape worker traffic last, so setup itself runs at full speed
+	return t.shapeWorkerNetworks(ctx, workerSets, managementCluster.Name)
+}
+
+// setupMultiKueue creates the MultiKueue Secrets, MultiKueueClusters, MultiKueueConfigs and
+// AdmissionChecks of a management cluster's WorkerSets, whose workers must already exist
+func (t *Topology) setupMultiKueue(ctx context.Context, kueueClient *kueue.Client, managementName string, workerSets []config.WorkerSet, workerClusters []*config.ClusterConfig) error {
+	// Get kubeconfigs the management cluster can use to reach each worker
+	workerKubeconfigs := make(map[string][]byte)
+	scoped := scopedWorkers(workerSets)
+	managed := managedWorkers(workerSets)
+	for _, worker := range workerClusters {
+		if !managed[worker.Name] {
+			continue
+		}
+		kubeconfigData, err := t.workerKubeconfig(ctx, worker.Name, worker.ExternalKubeconfig)
+		if err != nil {
+			return err
+		}
+		if namespace, ok := scoped[worker.Name]; ok {
+			workerClient, err := kueue.NewClient(t.metadata.Clusters[worker.Name].KubeconfigPath, t.clientOption())
+			if err != nil {
+				return fmt.Errorf("failed to create Kueue client for worker %q: %w", worker.Name, err)
+			}
+			workerClient.SetTopology(t.metadata.Name)
+			if kubeconfigData, err = kueue.ServiceAccountKubeconfig(ctx, workerClient, namespace, kubeconfigData); err != nil {
+				return fmt.Errorf("failed to create MultiKueue ServiceAccount for worker %q: %w", worker.Name, err)
 			}
 		}
+		workerKubeconfigs[worker.Name] = kubeconfigData
 	}
 
-	// Save metadata
-	if err := t.save(); err != nil {
-		return nil, fmt.Errorf("failed to save metadata: %w", err)
+	// Create MultiKueue infrastructure (Secrets, MultiKueueClusters, MultiKueueConfigs, AdmissionChecks)
+	if err := kueue.SetupMultiKueueInfrastructure(ctx, kueueClient, workerSets, workerKubeconfigs); err != nil {
+		return fmt.Errorf("failed to setup MultiKueue infrastructure in management cluster '%s': %w", managementName, err)
 	}
+	return nil
+}
 
-	return t, nil
+// clusterSettings holds topology-wide settings applied to every cluster
+type clusterSettings struct {
+	// topologyName labels the objects created in the cluster
+	topologyName string
+	kwokVersion  string
+	kueue        kueue.InstallOptions
+	// provisionConcurrency bounds parallel Kueue object creation (0: default)
+	provisionConcurrency int
+	// probeCapacity checks each ClusterQueue admits a job sized at its nominal quota
+	probeCapacity      bool
+	images             []string
+	registry           *config.RegistrySettings
+	goldenSnapshots    bool
+	podStartup         *config.PodStartupLatency
+	kwokOffline        bool
+	heartbeat          kwok.Heartbeat
+	kwokManifestSHA256 string
+	// client applies spec.client's overrides to the clients opened against the cluster
+	client restconfig.Option
+	// multiKueueDispatchers maps management clusters to their WorkerSets' MultiKueue dispatcher
+	multiKueueDispatchers map[string]string
+	// recordedKindClusters are the kind clusters of an earlier create of the topology, which
+	// a re-run may reuse
+	recordedKindClusters map[string]bool
+	// state holds the golden snapshots and downloaded manifests clusters are created from
+	state *state.Store
+}
+
+// newClusterSettings resolves topology-wide settings from the spec, applying defaults
+func newClusterSettings(cfg *config.Topology) (clusterSettings, error) {
+	settings := clusterSettings{
+		topologyName:    cfg.Metadata.Name,
+		kueue:           kueue.InstallOptions{Version: kueue.DefaultKueueVersion},
+		images:          cfg.Spec.Images,
+		registry:        cfg.Spec.Registry,
+		goldenSnapshots: cfg.Spec.GoldenSnapshots,
+		client:          restconfig.WithOverrides(nil),
+
+		multiKueueDispatchers: make(map[string]string),
+	}
+	for _, c := range cfg.Spec.Clusters {
+		if c.Role == config.RoleManagement {
+			settings.multiKueueDispatchers[c.Name] = config.MultiKueueDispatcher(config.WorkerSetsFor(cfg.Spec.WorkerSets, c.Name))
+		}
+	}
+
+	var heartbeat *config.HeartbeatSettings
+	if cfg.Spec.Kwok != nil {
+		if cfg.Spec.Kwok.Version != "" {
+			settings.kwokVersion = cfg.Spec.Kwok.Version
+		}
+		settings.podStartup = cfg.Spec.Kwok.PodStartup
+		settings.kwokOffline = cfg.Spec.Kwok.Offline
+		settings.kwokManifestSHA256 = cfg.Spec.Kwok.ManifestSHA256
+		heartbeat = cfg.Spec.Kwok.Heartbeat
+	}
+
+	var err error
+	if settings.heartbeat, err = kwok.ResolveHeartbeat(heartbeat); err != nil {
+		return settings, fmt.Errorf("invalid Kwok heartbeat settings: %w", err)
+	}
+
+	if cfg.Spec.Kueue != nil {
+		if cfg.Spec.Kueue.Version != "" {
+			settings.kueue.Version = cfg.Spec.Kueue.Version
+		}
+		settings.kueue.HelmValues = cfg.Spec.Kueue.HelmValues
+		settings.kueue.Chart = cfg.Spec.Kueue.Chart
+		settings.kueue.Manifest = cfg.Spec.Kueue.Manifest
+		settings.kueue.ManagerConfig = cfg.Spec.Kueue.Config
+		settings.kueue.FeatureGates = cfg.Spec.Kueue.FeatureGates
+		settings.provisionConcurrency = cfg.Spec.Kueue.ProvisionConcurrency
+		settings.probeCapacity = cfg.Spec.Kueue.ProbeCapacity
+	}
+
+	return settings, nil
+}
+
+// clientOverrides converts a topology's API client settings into REST config overrides
+func clientOverrides(c *config.ClientSettings) (restconfig.Overrides, error) {
+	overrides := restconfig.Overrides{QPS: c.QPS, Burst: c.Burst}
+	if c.Timeout != "" {
+		timeout, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			return overrides, fmt.Errorf("invalid client timeout: %w", err)
+		}
+		overrides.Timeout = timeout
+	}
+	return overrides, nil
+}
+
+// recordedKindClusters returns the kind clusters listed by the topology's saved metadata
+func (s *Store) recordedKindClusters(name string) map[string]bool {
+	previous, err := s.Load(name)
+	if err != nil {
+		return nil
+	}
+	recorded := make(map[string]bool, len(previous.metadata.Clusters))
+	for _, c := range previous.metadata.Clusters {
+		recorded[c.KindClusterName] = true
+	}
+	return recorded
+}
+
+// checkReusable fails unless an existing kind cluster belongs to the topology: it is listed
+// by the topology's metadata, or its nodes carry the topology's ownership labels. Kind
+// cluster names join topology and cluster names, so another topology's cluster may share
+// the name, and a reused cluster is destroyed with the topology.
+func (s clusterSettings) checkReusable(ctx context.Context, kindClusterName, kubeconfigPath string) error {
+	if s.recordedKindClusters[kindClusterName] {
+		return nil
+	}
+	owned, err := cluster.HasNodes(ctx, kubeconfigPath, ownership.Selector(s.topologyName), s.client)
+	if err != nil {
+		return fmt.Errorf("failed to check the owner of kind cluster '%s': %w", kindClusterName, err)
+	}
+	if !owned {
+		return fmt.Errorf("kind cluster '%s' already exists and was not created for topology '%s'; delete it or use another topology name", kindClusterName, s.topologyName)
+	}
+	return nil
+}
+
+// kueueInstallOptions returns the Kueue install options for a cluster with the given feature
+// gates. A management cluster also gets its WorkerSets' MultiKueue dispatcher.
+func (s clusterSettings) kueueInstallOptions(clusterName string, featureGates map[string]bool) kueue.InstallOptions {
+	opts := s.kueue
+	opts.FeatureGates = featureGates
+	if dispatcher := s.multiKueueDispatchers[clusterName]; dispatcher != "" {
+		var managerConfig config.KueueManagerConfig
+		if opts.ManagerConfig != nil {
+			managerConfig = *opts.ManagerConfig
+		}
+		managerConfig.MultiKueueDispatcher = dispatcher
+		opts.ManagerConfig = &managerConfig
+	}
+	return opts
+}
+
+// mergeFeatureGates returns the topology-wide feature gates with cluster overrides applied
+func mergeFeatureGates(global, cluster map[string]bool) map[string]bool {
+	if len(global) == 0 && len(cluster) == 0 {
+		return nil
+	}
+	gates := make(map[string]bool, len(global)+len(cluster))
+	for name, enabled := range global {
+		gates[name] = enabled
+	}
+	for name, enabled := range cluster {
+		gates[name] = enabled
+	}
+	return gates
 }
 
 // createCluster creates a complete cluster with all components (infrastructure + Kueue objects)
-func (t *Topology) createCluster(ctx context.Context, clusterCfg *config.ClusterConfig, topologyDir, kwokVersion, kueueVersion string, kueueHelmValues map[string]interface{}, createdClusters *[]string) error {
-	kubeconfigPath, err := t.createClusterInfrastructure(ctx, clusterCfg, topologyDir, kwokVersion, kueueVersion, kueueHelmValues, createdClusters)
+func (t *Topology) createCluster(ctx context.Context, clusterCfg *config.ClusterConfig, topologyDir string, settings clusterSettings, createdClusters *[]string) error {
+	timer := newStageTimer()
+	kubeconfigPath, err := t.createClusterInfrastructure(ctx, clusterCfg, topologyDir, settings, createdClusters, timer)
 	if err != nil {
 		return err
 	}
 
+	if err := t.recordKueueConfig(clusterCfg.Name, topologyDir, clusterCfg.Kueue); err != nil {
+		return err
+	}
+
 	// Provision Kueue objects (if specified)
 	if clusterCfg.Kueue != nil {
-		kueueClient, err := kueue.NewClient(kubeconfigPath)
-		if err != nil {
-			return fmt.Errorf("failed to create Kueue client for cluster '%s': %w", clusterCfg.Name, err)
+		if err := timer.time(StageObjects, func() error {
+			return provisionObjects(ctx, clusterCfg, kubeconfigPath, settings)
+		}); err != nil {
+			return err
 		}
+	}
+	t.recordStages(clusterCfg.Name, timer)
+
+	return nil
+}
 
-		if err := kueue.ProvisionKueueObjects(ctx, kueueClient, clusterCfg.Kueue); err != nil {
-			return fmt.Errorf("failed to provision Kueue objects in cluster '%s': %w", clusterCfg.Name, err)
+// provisionObjects creates a cluster's Kueue objects and waits for them to become active,
+// then probes their capacity if requested
+func provisionObjects(ctx context.Context, clusterCfg *config.ClusterConfig, kubeconfigPath string, settings clusterSettings) error {
+	kueueClient, err := kueue.NewClient(kubeconfigPath, settings.client)
+	if err != nil {
+		return fmt.Errorf("failed to create Kueue client for cluster '%s': %w", clusterCfg.Name, err)
+	}
+	kueueClient.SetTopology(settings.topologyName)
+
+	if err := kueue.ProvisionKueueObjects(ctx, kueueClient, clusterCfg.Kueue, settings.provisionConcurrency); err != nil {
+		return fmt.Errorf("failed to provision Kueue objects in cluster '%s': %w", clusterCfg.Name, err)
+	}
+
+	if err := kueueClient.WaitForActive(ctx, kueue.DefaultActiveTimeout); err != nil {
+		return fmt.Errorf("kueue objects in cluster '%s' did not become active: %w", clusterCfg.Name, err)
+	}
+
+	if settings.probeCapacity {
+		if err := kueue.ProbeCapacity(ctx, kueueClient, clusterCfg.Kueue, kueue.DefaultProbeTimeout); err != nil {
+			return fmt.Errorf("capacity probe failed in cluster '%s': %w", clusterCfg.Name, err)
 		}
 	}
+	return nil
+}
+
+// recordStages stores a cluster's creation stage timings in its metadata
+func (t *Topology) recordStages(clusterName string, timer *stageTimer) {
+	c := t.metadata.Clusters[clusterName]
+	timer.record(&c)
+	t.metadata.Clusters[clusterName] = c
+}
 
+// addExternalCluster registers an existing cluster in the topology after checking that it
+// runs Kueue. The cluster is not modified and is kept when the topology is deleted.
+func (t *Topology) addExternalCluster(ctx context.Context, clusterCfg *config.ClusterConfig) error {
+	crds, err := kueueCRDVersions(ctx, clusterCfg.ExternalKubeconfig, t.clientOption())
+	if err != nil {
+		return fmt.Errorf("failed to check Kueue CRDs in external cluster '%s': %w", clusterCfg.Name, err)
+	}
+	t.metadata.Clusters[clusterCfg.Name] = Cluster{
+		Name:           clusterCfg.Name,
+		KubeconfigPath: clusterCfg.ExternalKubeconfig,
+		Role:           clusterCfg.Role,
+		External:       true,
+		KueueCRDs:      crds,
+		CreatedAt:      time.Now(),
+	}
 	return nil
 }
 
-// createClusterInfrastructure creates cluster infrastructure (kind + Kwok + Kueue install) without Kueue objects
-func (t *Topology) createClusterInfrastructure(ctx context.Context, clusterCfg *config.ClusterConfig, topologyDir, kwokVersion, kueueVersion string, kueueHelmValues map[string]interface{}, createdClusters *[]string) (string, error) {
+// workerKubeconfig returns the kubeconfig the management cluster uses to reach a worker.
+// Kind workers use their internal kubeconfig (the default one uses 127.0.0.1, which is
+// unreachable from other kind containers); external workers use their kubeconfig file with
+// the certificate files it references inlined, since the file's paths mean nothing there.
+func (t *Topology) workerKubeconfig(ctx context.Context, name, externalKubeconfig string) ([]byte, error) {
+	if externalKubeconfig != "" {
+		data, err := restconfig.FlattenKubeconfig(externalKubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read kubeconfig for external worker %q: %w", name, err)
+		}
+		return data, nil
+	}
+	data, err := cluster.GetKubeconfig(ctx, t.getKindClusterName(name), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get internal kubeconfig for worker %q: %w", name, err)
+	}
+	return data, nil
+}
+
+// createClusterInfrastructure creates cluster infrastructure (kind + Kwok + Kueue install) without
+// Kueue objects, timing each stage with timer
+func (t *Topology) createClusterInfrastructure(ctx context.Context, clusterCfg *config.ClusterConfig, topologyDir string, settings clusterSettings, createdClusters *[]string, timer *stageTimer) (string, error) {
 	clusterName := clusterCfg.Name
 	kindClusterName := t.getKindClusterName(clusterName)
 	kubeconfigPath := filepath.Join(topologyDir, fmt.Sprintf("%s.kubeconfig", clusterName))
+	featureGates := mergeFeatureGates(settings.kueue.FeatureGates, clusterCfg.KueueFeatureGates)
 
-	// Create kind cluster
-	if err := cluster.CreateCluster(ctx, kindClusterName, clusterCfg, kubeconfigPath); err != nil {
-		return "", fmt.Errorf("failed to create cluster '%s': %w", clusterName, err)
+	// Pick a Kwok version tested with the cluster's Kubernetes version (unless pinned)
+	kwokVersion, warning := kwok.SelectVersion(settings.kwokVersion, cluster.KubernetesVersion(clusterCfg.KubernetesVersion))
+	if warning != "" {
+		fmt.Fprintf(os.Stderr, "Warning: cluster '%s': %s\n", clusterName, warning)
 	}
-	// Track created cluster for cleanup on error
-	*createdClusters = append(*createdClusters, kindClusterName)
 
-	// Install Kwok
-	if err := kwok.Install(ctx, kubeconfigPath, kwokVersion); err != nil {
-		return "", fmt.Errorf("failed to install Kwok in cluster '%s': %w", clusterName, err)
+	// Create kind cluster, or reuse the one left by an earlier create of this topology, so
+	// re-running create after a partial failure converges instead of failing. Every later
+	// step creates or updates, so it completes what the earlier run left undone.
+	reused, err := cluster.Exists(kindClusterName)
+	if err != nil {
+		return "", err
 	}
+	if err := timer.time(StageKind, func() error {
+		if reused {
+			fmt.Printf("Reusing existing kind cluster '%s'...\n", kindClusterName)
+			if err := cluster.ExportKubeconfig(ctx, kindClusterName, kubeconfigPath); err != nil {
+				return err
+			}
+			return settings.checkReusable(ctx, kindClusterName, kubeconfigPath)
+		}
+		return cluster.CreateCluster(ctx, kindClusterName, clusterCfg, kubeconfigPath, cluster.CreateOptions{
+			Registry:               settings.registry,
+			NodeMonitorGracePeriod: settings.heartbeat.NodeMonitorGracePeriod(),
+			NodeLabels:             ownership.Labels(settings.topologyName, ""),
+		})
+	}); err != nil {
+		return "", fmt.Errorf("failed to create cluster '%s': %w", clusterName, err)
+	}
+	// Track created cluster for cleanup on error; reused clusters are kept
+	if !reused {
+		*createdClusters = append(*createdClusters, kindClusterName)
+	}
+
+	// Restore a golden snapshot if one exists, otherwise record the baseline images so
+	// a snapshot can be saved once all components are installed
+	var snapshotKey string
+	var snapshotBaseline []string
+	if err := timer.time(StageImages, func() error {
+		// Preload local images (before any component that may reference them is installed)
+		if err := cluster.LoadImages(ctx, kindClusterName, settings.images); err != nil {
+			return fmt.Errorf("failed to load images into cluster '%s': %w", clusterName, err)
+		}
 
-	// Create Kwok nodes
-	if err := kwok.CreateNodes(ctx, kubeconfigPath, clusterCfg.NodePools); err != nil {
-		return "", fmt.Errorf("failed to create nodes in cluster '%s': %w", clusterName, err)
+		// Local Kueue builds change between runs, so their images are never snapshotted
+		localKueue := settings.kueue.Chart != "" || settings.kueue.Manifest != ""
+		if !settings.goldenSnapshots || localKueue {
+			return nil
+		}
+		snapshotKey = cluster.SnapshotKey(clusterCfg.KubernetesVersion, kwokVersion, settings.kueue.Version)
+		exists, err := cluster.HasSnapshot(settings.state, snapshotKey)
+		if err != nil {
+			return err
+		}
+		if exists {
+			if err := cluster.RestoreSnapshot(ctx, settings.state, kindClusterName, snapshotKey); err != nil {
+				return fmt.Errorf("failed to restore snapshot in cluster '%s': %w", clusterName, err)
+			}
+			snapshotKey = ""
+		} else if snapshotBaseline, err = cluster.ListImages(ctx, kindClusterName); err != nil {
+			return fmt.Errorf("failed to list images in cluster '%s': %w", clusterName, err)
+		}
+		return nil
+	}); err != nil {
+		return "", err
 	}
 
-	// Install Kueue
-	if err := kueue.Install(ctx, kubeconfigPath, kueueVersion, kueueHelmValues); err != nil {
-		return "", fmt.Errorf("failed to install Kueue in cluster '%s': %w", clusterName, err)
+	// Kwok (controller, nodes and devices) and Kueue touch disjoint resources, and Kueue
+	// runs on the kind nodes rather than simulated ones, so they are installed concurrently
+	var crds []kueue.CRDVersion
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return installKwok(gctx, clusterCfg, kubeconfigPath, kwokVersion, settings, timer)
+	})
+	g.Go(func() error {
+		return timer.time(StageKueue, func() error {
+			kueueOpts := settings.kueueInstallOptions(clusterCfg.Name, featureGates)
+			if err := kueue.Install(gctx, kubeconfigPath, kueueOpts, settings.client); err != nil {
+				return fmt.Errorf("failed to install Kueue in cluster '%s': %w", clusterName, err)
+			}
+			var err error
+			if crds, err = kueueCRDVersions(gctx, kubeconfigPath, settings.client); err != nil {
+				return fmt.Errorf("failed to check Kueue CRDs in cluster '%s': %w", clusterName, err)
+			}
+			return nil
+		})
+	})
+	if err := g.Wait(); err != nil {
+		return "", err
 	}
 
 	// Install extensions (after Kueue install, before Kueue objects)
 	if len(clusterCfg.Extensions) > 0 {
-		if err := extensions.InstallExtensions(ctx, kubeconfigPath, clusterCfg.Extensions); err != nil {
+		if err := timer.time(StageExtensions, func() error {
+			return extensions.InstallExtensions(ctx, kubeconfigPath, clusterCfg.Extensions, settings.kwokOffline, settings.client)
+		}); err != nil {
 			return "", fmt.Errorf("failed to install extensions in cluster '%s': %w", clusterName, err)
 		}
 	}
 
+	// Kueue objects and workloads need every CRD served and every webhook listening
+	if err := timer.time(StageAPIReady, func() error {
+		return waitForAPIReady(ctx, kubeconfigPath, settings.client)
+	}); err != nil {
+		return "", fmt.Errorf("cluster '%s' not ready: %w", clusterName, err)
+	}
+
+	// Save a golden snapshot for later clusters (best effort: a failure only costs speed)
+	if snapshotKey != "" {
+		if err := cluster.SaveSnapshot(ctx, settings.state, kindClusterName, snapshotKey, snapshotBaseline); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save golden snapshot: %v\n", err)
+		}
+	}
+
 	// Add cluster to metadata
+	scheduler := config.SchedulerKube
+	if clusterCfg.Kwok != nil && clusterCfg.Kwok.Scheduler != "" {
+		scheduler = clusterCfg.Kwok.Scheduler
+	}
 	t.metadata.Clusters[clusterName] = Cluster{
-		Name:            clusterName,
-		KindClusterName: kindClusterName,
-		KubeconfigPath:  kubeconfigPath,
-		Role:            clusterCfg.Role,
-		CreatedAt:       time.Now(),
+		Name:              clusterName,
+		KindClusterName:   kindClusterName,
+		KubeconfigPath:    kubeconfigPath,
+		Role:              clusterCfg.Role,
+		Scheduler:         scheduler,
+		KueueFeatureGates: featureGates,
+		KueueCRDs:         crds,
+		WorkloadRunLabels: settings.kueueInstallOptions(clusterCfg.Name, featureGates).CopiesRunLabel(),
+		CreatedAt:         time.Now(),
 	}
 
 	return kubeconfigPath, nil
 }
 
-// Load loads an existing topology from disk
-func Load(name string) (*Topology, error) {
-	topologyDir, err := getTopologyDir(name)
+// installKwok installs the Kwok controller with the cluster's stages, then creates the
+// cluster's simulated nodes and devices and waits for the nodes to be Ready
+func installKwok(ctx context.Context, clusterCfg *config.ClusterConfig, kubeconfigPath, kwokVersion string, settings clusterSettings, timer *stageTimer) error {
+	// Install Kwok
+	stageOverrides, err := kwok.PodReadyStages(settings.podStartup)
+	if err != nil {
+		return err
+	}
+	heartbeatStages, err := kwok.HeartbeatStages(settings.heartbeat)
+	if err != nil {
+		return err
+	}
+	stageOverrides = append(stageOverrides, heartbeatStages...)
+	clusterStages, err := kwok.LoadStageOverrides(clusterCfg.Kwok)
+	if err != nil {
+		return fmt.Errorf("failed to load Kwok stages for cluster '%s': %w", clusterCfg.Name, err)
+	}
+	// Cluster stage overrides take precedence over generated ones with the same name
+	stageOverrides = append(stageOverrides, clusterStages...)
+	kwokOpts := kwok.InstallOptions{
+		Version:        kwokVersion,
+		Offline:        settings.kwokOffline,
+		ManifestSHA256: settings.kwokManifestSHA256,
+		StageOverrides: stageOverrides,
+		LeaseDuration:  settings.heartbeat.LeaseDuration,
+		Cache:          manifest.NewCache(settings.state),
+	}
+	if err := timer.time(StageKwok, func() error {
+		return kwok.Install(ctx, kubeconfigPath, kwokOpts, settings.client)
+	}); err != nil {
+		return fmt.Errorf("failed to install Kwok in cluster '%s': %w", clusterCfg.Name, err)
+	}
+
+	return timer.time(StageNodes, func() error {
+		// Create Kwok nodes, converging any left by an earlier attempt to the node pools
+		if _, err := kwok.ReconcileNodes(ctx, kubeconfigPath, settings.topologyName, clusterCfg.NodePools, settings.client); err != nil {
+			return fmt.Errorf("failed to create nodes in cluster '%s': %w", clusterCfg.Name, err)
+		}
+
+		// Wait for KWOK to mark all nodes Ready before anything is scheduled against them
+		if err := kwok.WaitForNodesReady(ctx, kubeconfigPath, clusterCfg.NodePools, settings.client); err != nil {
+			return fmt.Errorf("nodes not ready in cluster '%s': %w", clusterCfg.Name, err)
+		}
+
+		// Publish simulated DRA devices for pools that declare them
+		if err := kwok.CreateDevices(ctx, kubeconfigPath, clusterCfg.NodePools, settings.client); err != nil {
+			return fmt.Errorf("failed to create devices in cluster '%s': %w", clusterCfg.Name, err)
+		}
+		return nil
+	})
+}
+
+// waitForAPIReady waits for the cluster's CRDs to be Established and its webhooks to serve
+func waitForAPIReady(ctx context.Context, kubeconfigPath string, clientOpt restconfig.Option) error {
+	client, err := kueue.NewClient(kubeconfigPath, clientOpt)
+	if err != nil {
+		return fmt.Errorf("failed to create Kueue client: %w", err)
+	}
+	return client.WaitForAPIReady(ctx, kueue.DefaultAPIReadyTimeout)
+}
+
+// kueueCRDVersions records the installed Kueue CRD versions and checks that they serve
+// the API version kueue-bench creates objects with
+func kueueCRDVersions(ctx context.Context, kubeconfigPath string, clientOpt restconfig.Option) ([]kueue.CRDVersion, error) {
+	client, err := kueue.NewClient(kubeconfigPath, clientOpt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kueue client: %w", err)
+	}
+	crds, err := client.CRDVersions(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if err := kueue.CheckCRDVersions(crds); err != nil {
+		return nil, err
+	}
+	return crds, nil
+}
 
-	metadataPath := filepath.Join(topologyDir, metadataFilename)
-	data, err := os.ReadFile(metadataPath) //nolint:gosec // path is constructed from known base directory
+// Load loads a topology from the default state directory; see Store.Load
+func Load(name string) (*Topology, error) {
+	store, err := OpenStore()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read metadata: %w", err)
+		return nil, err
 	}
+	return store.Load(name)
+}
 
+// Load loads an existing topology from disk, upgrading metadata written by older
+// kueue-bench binaries
+func (s *Store) Load(name string) (*Topology, error) {
 	var metadata Metadata
-	if err := json.Unmarshal(data, &metadata); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	if err := s.state.LoadVersioned(state.Topologies, name, &metadata, metadataMigrations); err != nil {
+		return nil, err
+	}
+	// Clients of this topology's clusters use the limits it was created with
+	for name, c := range metadata.Clusters {
+		c.Client = metadata.Client
+		metadata.Clusters[name] = c
 	}
 
 	return &Topology{
 		metadata: &metadata,
+		store:    s,
 	}, nil
 }
 
-// List lists all topologies from disk
+// List lists the topologies in the default state directory; see Store.List
 func List() ([]*Topology, error) {
-	home, err := os.UserHomeDir()
+	store, err := OpenStore()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
 	}
+	return store.List()
+}
 
-	topologiesDir := filepath.Join(home, metadataDir)
-	entries, err := os.ReadDir(topologiesDir)
+// List lists all topologies from disk. Topologies created by a newer kueue-bench are
+// listed too, with only their name and schema version; see Topology.Unsupported.
+func (s *Store) List() ([]*Topology, error) {
+	names, err := s.state.Names(state.Topologies)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []*Topology{}, nil
-		}
-		return nil, fmt.Errorf("failed to read topologies directory: %w", err)
+		return nil, err
 	}
 
 	var topologies []*Topology
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
+	for _, name := range names {
+		topo, err := s.Load(name)
+		var newer *state.NewerSchemaError
+		if errors.As(err, &newer) {
+			topo, err = &Topology{metadata: &Metadata{Name: name, SchemaVersion: newer.Version}, store: s}, nil
 		}
-
-		topo, err := Load(entry.Name())
 		if err != nil {
 			// Skip entries that fail to load
 			continue
@@ -310,24 +828,23 @@ func List() ([]*Topology, error) {
 
 // Delete deletes the topology and all its clusters
 func (t *Topology) Delete(ctx context.Context) error {
-	// Delete all kind clusters (best effort - continue on errors)
+	// Delete all kind clusters (best effort - continue on errors); external clusters are kept
 	for _, clusterInfo := range t.metadata.Clusters {
+		if clusterInfo.External {
+			continue
+		}
 		if err := cluster.DeleteCluster(ctx, clusterInfo.KindClusterName); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to delete cluster %s: %v\n", clusterInfo.Name, err)
 		}
 	}
 
 	// Delete metadata directory
-	topologyDir, err := getTopologyDir(t.metadata.Name)
-	if err != nil {
-		return err
-	}
-
-	if err := os.RemoveAll(topologyDir); err != nil {
-		return fmt.Errorf("failed to remove topology directory: %w", err)
-	}
+	return t.store.state.Remove(state.Topologies, t.metadata.Name)
+}
 
-	return nil
+// clientOption applies the topology's client overrides to the clients it opens
+func (t *Topology) clientOption() restconfig.Option {
+	return restconfig.WithOverrides(t.metadata.Client)
 }
 
 // GetMetadata returns the topology metadata
@@ -335,37 +852,122 @@ func (t *Topology) GetMetadata() *Metadata {
 	return t.metadata
 }
 
+// Unsupported reports whether the topology was created by a newer kueue-bench whose
+// metadata this build cannot read. Only its name and schema version are known.
+func (t *Topology) Unsupported() bool {
+	return t.metadata.SchemaVersion > MetadataSchemaVersion
+}
+
 // save saves topology metadata to disk
 func (t *Topology) save() error {
-	topologyDir, err := getTopologyDir(t.metadata.Name)
+	t.metadata.SchemaVersion = MetadataSchemaVersion
+	return t.store.state.Save(state.Topologies, t.metadata.Name, t.metadata)
+}
+
+// recordConfig saves the topology configuration in the topology directory, so
+// clusters can later be reconfigured with the same settings
+func (t *Topology) recordConfig(topologyDir string, cfg *config.Topology) error {
+	data, err := yaml.Marshal(cfg)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to marshal topology config: %w", err)
 	}
+	path := filepath.Join(topologyDir, "topology.yaml")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write topology config: %w", err)
+	}
+	t.metadata.ConfigPath = path
+	return nil
+}
 
-	metadataPath := filepath.Join(topologyDir, metadataFilename)
-	data, err := json.MarshalIndent(t.metadata, "", "  ")
+// CreatedFrom reports whether the topology was created from cfg, comparing it with the
+// configuration recorded at creation. Topologies without a recorded configuration were
+// not created from cfg.
+func (t *Topology) CreatedFrom(cfg *config.Topology) (bool, error) {
+	if t.metadata.ConfigPath == "" {
+		return false, nil
+	}
+	recorded, err := os.ReadFile(t.metadata.ConfigPath)
 	if err != nil {
-		return fmt.Errorf("failed to marshal metadata: %w", err)
+		return false, fmt.Errorf("failed to read topology config: %w", err)
 	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal topology config: %w", err)
+	}
+	return bytes.Equal(recorded, data), nil
+}
 
-	if err := os.WriteFile(metadataPath, data, 0600); err != nil {
-		return fmt.Errorf("failed to write metadata: %w", err)
+// recordKueueConfig saves the Kueue objects provisioned in a cluster next to its kubeconfig,
+// so live objects can later be compared against them
+func (t *Topology) recordKueueConfig(clusterName, topologyDir string, kueueConfig *config.KueueConfig) error {
+	if kueueConfig == nil {
+		kueueConfig = &config.KueueConfig{}
+	}
+	data, err := yaml.Marshal(kueueConfig)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Kueue config for cluster '%s': %w", clusterName, err)
+	}
+	path := filepath.Join(topologyDir, fmt.Sprintf("%s.kueue.yaml", clusterName))
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write Kueue config for cluster '%s': %w", clusterName, err)
 	}
 
+	c := t.metadata.Clusters[clusterName]
+	c.KueueConfigPath = path
+	t.metadata.Clusters[clusterName] = c
 	return nil
 }
 
-// getKindClusterName returns the kind cluster name for a cluster
-func (t *Topology) getKindClusterName(clusterName string) string {
-	return fmt.Sprintf("%s-%s", t.metadata.Name, clusterName)
+// shapeWorkerNetworks applies each WorkerSet's network shaping to traffic from its workers
+// to the management cluster
+func (t *Topology) shapeWorkerNetworks(ctx context.Context, workerSets []config.WorkerSet, managementName string) error {
+	shaped := make(map[string]bool)
+	for _, ws := range workerSets {
+		if ws.Network == nil {
+			continue
+		}
+		for _, worker := range ws.Workers {
+			// Shared workers have the same network settings in every WorkerSet
+			if shaped[worker.Name] {
+				continue
+			}
+			shaped[worker.Name] = true
+			if err := cluster.ShapeTraffic(ctx, t.getKindClusterName(worker.Name), t.getKindClusterName(managementName), ws.Network); err != nil {
+				return fmt.Errorf("failed to shape network of worker %q: %w", worker.Name, err)
+			}
+			fmt.Printf("✓ Shaped network from worker '%s' to management cluster\n", worker.Name)
+		}
+	}
+	return nil
 }
 
-// getTopologyDir returns the directory path for a topology
-func getTopologyDir(name string) (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+// managedWorkers returns the names of the workers in the WorkerSets
+func managedWorkers(workerSets []config.WorkerSet) map[string]bool {
+	workers := make(map[string]bool)
+	for _, ws := range workerSets {
+		for _, worker := range ws.Workers {
+			workers[worker.Name] = true
+		}
+	}
+	return workers
+}
+
+// scopedWorkers maps workers whose MultiKueue kubeconfigs use a scoped ServiceAccount to the
+// namespace the ServiceAccount is created in
+func scopedWorkers(workerSets []config.WorkerSet) map[string]string {
+	scoped := map[string]string{}
+	for _, ws := range workerSets {
+		if ws.Credentials != config.CredentialsServiceAccount {
+			continue
+		}
+		for _, w := range ws.Workers {
+			scoped[w.Name] = ws.Namespace()
+		}
 	}
+	return scoped
+}
 
-	return filepath.Join(home, metadataDir, name), nil
+// getKindClusterName returns the kind cluster name for a cluster
+func (t *Topology) getKindClusterName(clusterName string) string {
+	return fmt.Sprintf("%s-%s", t.metadata.Name, clusterName)
 }
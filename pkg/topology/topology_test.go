@@ -0,0 +1,51 @@
+package topology
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/state"
+)
+
+func TestStoreListUnsupported(t *testing.T) {
+	s, err := state.OpenAt(t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenAt() error: %v", err)
+	}
+	current := Metadata{
+		SchemaVersion: MetadataSchemaVersion,
+		Name:          "current",
+		CreatedAt:     time.Now(),
+		Clusters:      map[string]Cluster{"c1": {Name: "c1"}},
+	}
+	if err := s.Save(state.Topologies, current.Name, current); err != nil {
+		t.Fatal(err)
+	}
+	newer := map[string]any{"schemaVersion": MetadataSchemaVersion + 1, "name": "newer", "clusters": "restructured"}
+	if err := s.Save(state.Topologies, "newer", newer); err != nil {
+		t.Fatal(err)
+	}
+
+	topologies, err := NewStore(s).List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(topologies) != 2 {
+		t.Fatalf("List() returned %d topologies, want 2", len(topologies))
+	}
+
+	got := map[string]*Topology{}
+	for _, topo := range topologies {
+		got[topo.GetMetadata().Name] = topo
+	}
+	if topo := got["current"]; topo == nil || topo.Unsupported() || len(topo.GetMetadata().Clusters) != 1 {
+		t.Errorf("current topology = %+v, want a supported topology with 1 cluster", topo)
+	}
+	topo := got["newer"]
+	if topo == nil || !topo.Unsupported() {
+		t.Fatalf("newer topology = %+v, want it listed as unsupported", topo)
+	}
+	if v := topo.GetMetadata().SchemaVersion; v != MetadataSchemaVersion+1 {
+		t.Errorf("newer topology schema version = %d, want %d", v, MetadataSchemaVersion+1)
+	}
+}
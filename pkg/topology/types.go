@@ -2,20 +2,87 @@ package topology
 
 import (
 	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/kueue"
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+	"github.com/jhwagner/kueue-bench/pkg/state"
 )
 
+// metadataMigrations upgrade topology metadata saved by older kueue-bench binaries when
+// it is loaded. Append an entry whenever a Metadata or Cluster change would misread older
+// documents (a renamed or restructured field); new optional fields need none. Never edit
+// or reorder released entries.
+var metadataMigrations = []state.RecordMigration{
+	{
+		// Metadata written before versioning matches the v1 layout
+		Version:     1,
+		Description: "adopt unversioned metadata",
+		Apply:       func(map[string]any) error { return nil },
+	},
+}
+
+// MetadataSchemaVersion is the topology metadata schema version written by this build
+var MetadataSchemaVersion = metadataMigrations[len(metadataMigrations)-1].Version
+
 // Metadata stores information about a created topology
 type Metadata struct {
-	Name      string             `json:"name"`
-	CreatedAt time.Time          `json:"createdAt"`
-	Clusters  map[string]Cluster `json:"clusters"`
+	// SchemaVersion is the metadata schema version, see MetadataSchemaVersion
+	SchemaVersion int                `json:"schemaVersion"`
+	Name          string             `json:"name"`
+	CreatedAt     time.Time          `json:"createdAt"`
+	Clusters      map[string]Cluster `json:"clusters"`
+	// ConfigPath is the topology configuration the topology was created from
+	ConfigPath string `json:"configPath,omitempty"`
+	// Client holds the API client overrides from the topology's spec.client
+	Client *restconfig.Overrides `json:"client,omitempty"`
+	// CreateDuration is how long topology create took end to end
+	CreateDuration time.Duration `json:"createDuration,omitempty"`
 }
 
 // Cluster stores information about a cluster within a topology
 type Cluster struct {
-	Name            string    `json:"name"`
-	KindClusterName string    `json:"kindClusterName"`
-	KubeconfigPath  string    `json:"kubeconfigPath"`
-	Role            string    `json:"role,omitempty"`
-	CreatedAt       time.Time `json:"createdAt"`
+	Name            string `json:"name"`
+	KindClusterName string `json:"kindClusterName"`
+	KubeconfigPath  string `json:"kubeconfigPath"`
+	// KueueConfigPath is the Kueue object configuration provisioned in this cluster
+	KueueConfigPath string `json:"kueueConfigPath,omitempty"`
+	Role            string `json:"role,omitempty"`
+	// External is set for existing clusters the topology did not create
+	External bool `json:"external,omitempty"`
+	// Scheduler is the pod scheduling mode (config.SchedulerKube or config.SchedulerBypass)
+	Scheduler string `json:"scheduler,omitempty"`
+	// KueueFeatureGates are the Kueue feature gates set explicitly in this cluster
+	KueueFeatureGates map[string]bool `json:"kueueFeatureGates,omitempty"`
+	// KueueCRDs are the API versions of the Kueue CRDs installed in this cluster
+	KueueCRDs []kueue.CRDVersion `json:"kueueCRDs,omitempty"`
+	// WorkloadRunLabels is set when Kueue copies the run label of jobs onto their Workloads
+	WorkloadRunLabels bool `json:"workloadRunLabels,omitempty"`
+	// Stages are the durations of the cluster's creation stages, in the order of Stages
+	Stages []StageTiming `json:"stages,omitempty"`
+	// SetupDuration is how long the cluster took to create, from kind to its Kueue objects
+	SetupDuration time.Duration `json:"setupDuration,omitempty"`
+	CreatedAt     time.Time     `json:"createdAt"`
+	// Client holds the topology's API client overrides; set by Load, not saved per cluster
+	Client *restconfig.Overrides `json:"-"`
+}
+
+// ClientOption applies the cluster's topology client overrides to an API client for it
+func (c Cluster) ClientOption() restconfig.Option {
+	return restconfig.WithOverrides(c.Client)
+}
+
+// StageTiming records how long one creation stage of a cluster took
+type StageTiming struct {
+	Stage    string        `json:"stage"`
+	Duration time.Duration `json:"duration"`
+}
+
+// StageDuration returns the duration of a creation stage, or false if it was not run
+func (c Cluster) StageDuration(stage string) (time.Duration, bool) {
+	for _, s := range c.Stages {
+		if s.Stage == stage {
+			return s.Duration, true
+		}
+	}
+	return 0, false
 }
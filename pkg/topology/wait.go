@@ -0,0 +1,86 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/kueue"
+)
+
+// ClusterReadiness is the outcome of waiting for one cluster of a topology
+type ClusterReadiness struct {
+	Cluster string
+	Role    string
+	// Duration is how long the cluster took to become ready, or how long was waited
+	Duration time.Duration
+	// Err is why the cluster is not ready, nil if it is
+	Err error
+}
+
+// WaitForReady waits for every cluster of the topology concurrently, as
+// kueue.Client.WaitForReady describes, with a single timeout shared by all clusters.
+// Management clusters also wait for a MultiKueueCluster of each of their workers. The
+// result has one entry per cluster, sorted by name.
+func (t *Topology) WaitForReady(ctx context.Context, timeout time.Duration) ([]ClusterReadiness, error) {
+	expected, err := t.expectedMultiKueueClusters()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	results := make([]ClusterReadiness, 0, len(t.metadata.Clusters))
+	for _, c := range t.metadata.Clusters {
+		results = append(results, ClusterReadiness{Cluster: c.Name, Role: c.Role})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Cluster < results[j].Cluster })
+
+	var wg sync.WaitGroup
+	for i := range results {
+		r := &results[i]
+		c := t.metadata.Clusters[r.Cluster]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client, err := kueue.NewClient(c.KubeconfigPath, t.clientOption())
+			if err != nil {
+				r.Err = fmt.Errorf("failed to create Kueue client: %w", err)
+			} else {
+				r.Err = client.WaitForReady(ctx, timeout, expected[c.Name])
+			}
+			r.Duration = time.Since(start)
+		}()
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// expectedMultiKueueClusters returns the MultiKueueCluster names each management cluster
+// dispatches to, read from the topology's recorded config
+func (t *Topology) expectedMultiKueueClusters() (map[string][]string, error) {
+	expected := make(map[string][]string)
+	if t.metadata.ConfigPath == "" {
+		return expected, nil
+	}
+	cfg, err := config.LoadTopology(t.metadata.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range t.metadata.Clusters {
+		if c.Role != config.RoleManagement {
+			continue
+		}
+		for _, ws := range config.WorkerSetsFor(cfg.Spec.WorkerSets, c.Name) {
+			for _, worker := range ws.RankedWorkers() {
+				expected[c.Name] = append(expected[c.Name], ws.MultiKueueClusterName(worker))
+			}
+		}
+	}
+	return expected, nil
+}
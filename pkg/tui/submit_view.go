@@ -13,6 +13,7 @@ import (
 	"charm.land/lipgloss/v2"
 	"k8s.io/apimachinery/pkg/api/resource"
 
+	"github.com/jhwagner/kueue-bench/pkg/topology"
 	"github.com/jhwagner/kueue-bench/pkg/watcher"
 	"github.com/jhwagner/kueue-bench/pkg/workload"
 )
@@ -83,13 +84,13 @@ type submitViewModel struct {
 	focusedField int
 
 	// Submission context
-	kubeconfigPath string
+	cluster topology.Cluster
 
 	// Error shown below the form
 	submitErr string
 }
 
-func newSubmitView(snap watcher.Snapshot, kubeconfigPath string) (submitViewModel, tea.Cmd) {
+func newSubmitView(snap watcher.Snapshot, cluster topology.Cluster) (submitViewModel, tea.Cmd) {
 	dur := textinput.New()
 	dur.Prompt = ""
 	dur.SetValue("60s")
@@ -121,12 +122,12 @@ func newSubmitView(snap watcher.Snapshot, kubeconfigPath string) (submitViewMode
 	gpu.SetWidth(8)
 
 	m := submitViewModel{
-		durationInput:  dur,
-		replicasInput:  rep,
-		cpuInput:       cpu,
-		memInput:       mem,
-		gpuInput:       gpu,
-		kubeconfigPath: kubeconfigPath,
+		durationInput: dur,
+		replicasInput: rep,
+		cpuInput:      cpu,
+		memInput:      mem,
+		gpuInput:      gpu,
+		cluster:       cluster,
 	}
 	m.refreshOptions(snap)
 	cmd := m.applyFocus()
@@ -416,10 +417,10 @@ func (m *submitViewModel) submitCmd() tea.Cmd {
 	}
 
 	isJobSet := m.typeIdx == 1
-	kubeconfigPath := m.kubeconfigPath
+	cluster := m.cluster
 
 	return func() tea.Msg {
-		client, err := workload.NewWorkloadClient(kubeconfigPath)
+		client, err := workload.NewWorkloadClient(cluster.KubeconfigPath, cluster.ClientOption())
 		if err != nil {
 			return submitResultMsg{err: fmt.Errorf("create client: %w", err)}
 		}
@@ -123,7 +123,7 @@ func New(topologyName, clusterName string, meta topology.Metadata) (*Model, erro
 	}
 
 	isManagement := cluster.Role == "management"
-	w, err := watcher.New(cluster.KubeconfigPath, isManagement)
+	w, err := watcher.New(cluster.KubeconfigPath, isManagement, cluster.ClientOption())
 	if err != nil {
 		return nil, fmt.Errorf("create watcher: %w", err)
 	}
@@ -295,7 +295,7 @@ func (m Model) handleKey(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.Submit):
 		cluster := m.clusters[m.currentCluster]
 		var cmd tea.Cmd
-		m.submitView, cmd = newSubmitView(m.snapshot, cluster.KubeconfigPath)
+		m.submitView, cmd = newSubmitView(m.snapshot, cluster)
 		m.showSubmit = true
 		return m, cmd
 
@@ -485,7 +485,7 @@ func (m Model) switchToCluster(name string) (tea.Model, tea.Cmd) {
 	m.cancelWatcher()
 	m.watcher.Stop()
 
-	w, err := watcher.New(cluster.KubeconfigPath, isManagement)
+	w, err := watcher.New(cluster.KubeconfigPath, isManagement, cluster.ClientOption())
 	if err != nil {
 		m.statusErr = "switch cluster: " + err.Error()
 		return m, nil
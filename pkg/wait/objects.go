@@ -0,0 +1,172 @@
+package wait
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DeploymentAvailable waits until a Deployment has the Available condition. On timeout
+// the error carries the Deployment's replica counts and its last failing condition.
+func DeploymentAvailable(ctx context.Context, clientset kubernetes.Interface, namespace, name string, timeout time.Duration) error {
+	deployments := clientset.AppsV1().Deployments(namespace)
+	lw := byName(name, clientset,
+		func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return deployments.List(ctx, opts)
+		},
+		func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+			return deployments.Watch(ctx, opts)
+		},
+	)
+	return For(ctx, fmt.Sprintf("deployment %s/%s", namespace, name), lw, &appsv1.Deployment{}, timeout, deploymentAvailable)
+}
+
+// CRDEstablished waits until a CRD exists and has the Established condition, so objects
+// of its kind can be created
+func CRDEstablished(ctx context.Context, client apiextensionsclientset.Interface, name string, timeout time.Duration) error {
+	crds := client.ApiextensionsV1().CustomResourceDefinitions()
+	lw := byName(name, client,
+		func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return crds.List(ctx, opts)
+		},
+		func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+			return crds.Watch(ctx, opts)
+		},
+	)
+	return For(ctx, "CRD "+name, lw, &apiextensionsv1.CustomResourceDefinition{}, timeout, crdEstablished)
+}
+
+// SecretData waits until a Secret has a non-empty value for key, e.g. the token the token
+// controller fills into a ServiceAccount token Secret, and returns the Secret
+func SecretData(ctx context.Context, clientset kubernetes.Interface, namespace, name, key string, timeout time.Duration) (*corev1.Secret, error) {
+	secrets := clientset.CoreV1().Secrets(namespace)
+	lw := byName(name, clientset,
+		func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return secrets.List(ctx, opts)
+		},
+		func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+			return secrets.Watch(ctx, opts)
+		},
+	)
+	var secret *corev1.Secret
+	err := For(ctx, fmt.Sprintf("secret %s/%s", namespace, name), lw, &corev1.Secret{}, timeout, func(obj runtime.Object) (bool, string) {
+		s, ok := obj.(*corev1.Secret)
+		if !ok {
+			return false, "not found"
+		}
+		if len(s.Data[key]) == 0 {
+			return false, fmt.Sprintf("no %s yet", key)
+		}
+		secret = s
+		return true, ""
+	})
+	return secret, err
+}
+
+// ByLabel returns a ListerWatcher for the objects matching selector. client is the
+// clientset list and watch come from. Like byName, objects are also filtered client-side.
+func ByLabel(selector labels.Selector, client any, list cache.ListWithContextFunc, watchFunc cache.WatchFuncWithContext) cache.ListerWatcher {
+	return filtered(selector.String(), "", client, list, watchFunc, func(obj runtime.Object) bool {
+		accessor, err := meta.Accessor(obj)
+		return err == nil && selector.Matches(labels.Set(accessor.GetLabels()))
+	})
+}
+
+// byName returns a ListerWatcher for the single object called name. client is the
+// clientset list and watch come from. Objects are also filtered client-side, as not every
+// client (e.g. the client-go fakes) honors field selectors.
+func byName(name string, client any, list cache.ListWithContextFunc, watchFunc cache.WatchFuncWithContext) cache.ListerWatcher {
+	selector := fields.OneTermEqualSelector("metadata.name", name).String()
+	return filtered("", selector, client, list, watchFunc, func(obj runtime.Object) bool {
+		accessor, err := meta.Accessor(obj)
+		return err == nil && accessor.GetName() == name
+	})
+}
+
+// filtered returns a ListerWatcher that lists and watches with the given label and field
+// selectors and drops objects matches rejects
+func filtered(labelSelector, fieldSelector string, client any, list cache.ListWithContextFunc, watchFunc cache.WatchFuncWithContext, matches func(runtime.Object) bool) cache.ListerWatcher {
+	lw := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			opts.LabelSelector = labelSelector
+			opts.FieldSelector = fieldSelector
+			obj, err := list(ctx, opts)
+			if err != nil {
+				return nil, err
+			}
+			items, err := meta.ExtractList(obj)
+			if err != nil {
+				return nil, err
+			}
+			var matching []runtime.Object
+			for _, item := range items {
+				if matches(item) {
+					matching = append(matching, item)
+				}
+			}
+			return obj, meta.SetList(obj, matching)
+		},
+		WatchFuncWithContext: func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+			opts.LabelSelector = labelSelector
+			opts.FieldSelector = fieldSelector
+			w, err := watchFunc(ctx, opts)
+			if err != nil {
+				return nil, err
+			}
+			return watch.Filter(w, func(event watch.Event) (watch.Event, bool) {
+				return event, event.Type == watch.Error || event.Type == watch.Bookmark || matches(event.Object)
+			}), nil
+		},
+	}
+	// Clients that cannot stream the initial list (e.g. the fakes) fall back to list and watch
+	return cache.ToListWatcherWithWatchListSemantics(lw, client)
+}
+
+// deploymentAvailable is the Check for DeploymentAvailable
+func deploymentAvailable(obj runtime.Object) (bool, string) {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return false, "not found"
+	}
+	cause := fmt.Sprintf("%d/%d replicas available", deployment.Status.AvailableReplicas, deployment.Status.Replicas)
+	for _, cond := range deployment.Status.Conditions {
+		if cond.Type == appsv1.DeploymentAvailable && cond.Status == corev1.ConditionTrue {
+			return true, ""
+		}
+		if cond.Status != corev1.ConditionTrue && cond.Message != "" {
+			cause += fmt.Sprintf(", %s: %s", cond.Type, cond.Message)
+		}
+	}
+	return false, cause
+}
+
+// crdEstablished is the Check for CRDEstablished
+func crdEstablished(obj runtime.Object) (bool, string) {
+	crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+	if !ok {
+		return false, "not found"
+	}
+	cause := "no Established condition"
+	for _, cond := range crd.Status.Conditions {
+		switch {
+		case cond.Type == apiextensionsv1.Established && cond.Status == apiextensionsv1.ConditionTrue:
+			return true, ""
+		case cond.Status == apiextensionsv1.ConditionFalse && cond.Message != "":
+			cause = fmt.Sprintf("%s: %s", cond.Type, cond.Message)
+		}
+	}
+	return false, cause
+}
@@ -0,0 +1,99 @@
+// Package wait waits for Kubernetes objects to reach a desired state. Waits list and then
+// watch the object, so they return as soon as it changes instead of on the next poll, and
+// report the last state observed when they time out.
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+)
+
+// Check reports whether obj is in the desired state and, if not, why not. obj is nil
+// while the object does not exist.
+type Check func(obj runtime.Object) (done bool, cause string)
+
+// ListCheck reports whether the objects a wait lists and watches are together in the
+// desired state and, if not, why not
+type ListCheck func(objs []runtime.Object) (done bool, cause string)
+
+// TimeoutError is returned when a wait times out. Cause is what the check last reported.
+type TimeoutError struct {
+	What    string
+	Timeout time.Duration
+	Cause   string
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s not ready after %s: %s", e.What, e.Timeout, e.Cause)
+}
+
+// For waits until check is satisfied by the object lw lists and watches, ctx is cancelled
+// or timeout elapses. what names the object in errors; on timeout the error is a
+// *TimeoutError carrying the cause check last reported.
+func For(ctx context.Context, what string, lw cache.ListerWatcher, objType runtime.Object, timeout time.Duration, check Check) error {
+	return ForList(ctx, what, lw, objType, timeout, func(objs []runtime.Object) (bool, string) {
+		if len(objs) == 0 {
+			return check(nil)
+		}
+		var cause string
+		for _, obj := range objs {
+			var done bool
+			if done, cause = check(obj); done {
+				return true, ""
+			}
+		}
+		return false, cause
+	})
+}
+
+// ForList is For over every object lw lists and watches: check is called with all of them
+// after the initial list and after each change.
+func ForList(ctx context.Context, what string, lw cache.ListerWatcher, objType runtime.Object, timeout time.Duration, check ListCheck) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var store cache.Store
+	var cause string
+	evaluate := func() bool {
+		items := store.List()
+		objs := make([]runtime.Object, 0, len(items))
+		for _, item := range items {
+			objs = append(objs, item.(runtime.Object))
+		}
+		var done bool
+		done, cause = check(objs)
+		return done
+	}
+	_, cause = check(nil)
+	precondition := func(synced cache.Store) (bool, error) {
+		store = synced
+		return evaluate(), nil
+	}
+	// The informer updates the store before delivering an event, so the store holds at
+	// least the change the event reports
+	condition := func(event watch.Event) (bool, error) {
+		if event.Type == watch.Error {
+			return false, fmt.Errorf("watch error: %v", event.Object)
+		}
+		return evaluate(), nil
+	}
+
+	_, err := watchtools.UntilWithSync(ctx, lw, objType, precondition, condition)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &TimeoutError{What: what, Timeout: timeout, Cause: cause}
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("stopped waiting for %s (%s): %w", what, cause, ctx.Err())
+	}
+	return fmt.Errorf("failed to wait for %s: %w", what, err)
+}
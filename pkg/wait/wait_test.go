@@ -0,0 +1,117 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func deployment(name string, available corev1.ConditionStatus, message string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kube-system"},
+		Status: appsv1.DeploymentStatus{
+			Replicas:   1,
+			Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: available, Message: message}},
+		},
+	}
+}
+
+func TestDeploymentAvailable(t *testing.T) {
+	tests := []struct {
+		name        string
+		objects     []runtime.Object
+		wantErr     bool
+		errContains string
+	}{
+		{
+			name:    "available",
+			objects: []runtime.Object{deployment("kwok-controller", corev1.ConditionTrue, "")},
+		},
+		{
+			name: "unavailable reports condition",
+			objects: []runtime.Object{
+				deployment("kwok-controller", corev1.ConditionFalse, "Deployment does not have minimum availability."),
+				deployment("other", corev1.ConditionTrue, ""),
+			},
+			wantErr:     true,
+			errContains: "0/1 replicas available, Available: Deployment does not have minimum availability.",
+		},
+		{
+			name:        "missing",
+			objects:     []runtime.Object{deployment("other", corev1.ConditionTrue, "")},
+			wantErr:     true,
+			errContains: "deployment kube-system/kwok-controller not ready after 1s: not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := k8sfake.NewClientset(tt.objects...)
+			err := DeploymentAvailable(context.TODO(), clientset, "kube-system", "kwok-controller", time.Second)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeploymentAvailable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && tt.errContains != "" && !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("DeploymentAvailable() error = %v, expected to contain %q", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestDeploymentAvailableWatchesUpdates(t *testing.T) {
+	ctx := context.TODO()
+	clientset := k8sfake.NewClientset(deployment("kwok-controller", corev1.ConditionFalse, ""))
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_, _ = clientset.AppsV1().Deployments("kube-system").Update(ctx, deployment("kwok-controller", corev1.ConditionTrue, ""), metav1.UpdateOptions{})
+	}()
+
+	if err := DeploymentAvailable(ctx, clientset, "kube-system", "kwok-controller", 5*time.Second); err != nil {
+		t.Errorf("DeploymentAvailable() error = %v", err)
+	}
+}
+
+func TestForCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	err := DeploymentAvailable(ctx, k8sfake.NewClientset(), "kube-system", "kwok-controller", time.Second)
+	if err == nil || !strings.Contains(err.Error(), "stopped waiting for deployment kube-system/kwok-controller") {
+		t.Errorf("DeploymentAvailable() with cancelled context error = %v", err)
+	}
+}
+
+func TestSecretData(t *testing.T) {
+	ctx := context.TODO()
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "sa-token", Namespace: "kueue-system"}}
+	clientset := k8sfake.NewClientset(secret)
+
+	_, err := SecretData(ctx, clientset, "kueue-system", "sa-token", corev1.ServiceAccountTokenKey, 200*time.Millisecond)
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Cause != "no token yet" {
+		t.Fatalf("SecretData() error = %v, want a timeout with cause %q", err, "no token yet")
+	}
+
+	// Stand in for the token controller
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		filled := secret.DeepCopy()
+		filled.Data = map[string][]byte{corev1.ServiceAccountTokenKey: []byte("token")}
+		_, _ = clientset.CoreV1().Secrets("kueue-system").Update(ctx, filled, metav1.UpdateOptions{})
+	}()
+	got, err := SecretData(ctx, clientset, "kueue-system", "sa-token", corev1.ServiceAccountTokenKey, 5*time.Second)
+	if err != nil {
+		t.Fatalf("SecretData() error = %v", err)
+	}
+	if string(got.Data[corev1.ServiceAccountTokenKey]) != "token" {
+		t.Errorf("SecretData() token = %q, want %q", got.Data[corev1.ServiceAccountTokenKey], "token")
+	}
+}
@@ -8,13 +8,14 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	coreinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
-	"k8s.io/client-go/tools/clientcmd"
 	kueuev1beta2 "sigs.k8s.io/kueue/apis/kueue/v1beta2"
 	kueueclientset "sigs.k8s.io/kueue/client-go/clientset/versioned"
 	"sigs.k8s.io/kueue/client-go/informers/externalversions"
@@ -39,10 +40,10 @@ type Watcher struct {
 
 // New builds a Watcher connected to the cluster at kubeconfigPath. It does not
 // start the informers — call Start to do that.
-func New(kubeconfigPath string, isManagement bool) (*Watcher, error) {
-	cfg, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+func New(kubeconfigPath string, isManagement bool, opts ...restconfig.Option) (*Watcher, error) {
+	cfg, err := restconfig.ForKubeconfig(kubeconfigPath, 0, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("load kubeconfig: %w", err)
+		return nil, err
 	}
 
 	kueueClient, err := kueueclientset.NewForConfig(cfg)
@@ -0,0 +1,249 @@
+// Package whatif replays a recorded run's workload trace against Kueue quota
+// configurations with a simplified admission model. Comparing a replay of the quotas the
+// run used with one of modified quotas flags the workloads a change would admit earlier or
+// later, as a fast first pass before a full simulation.
+//
+// The model admits pending workloads in priority order, then submission order, whenever
+// quota frees up (best-effort FIFO across all ClusterQueues). A workload fits a resource
+// group when one of the group's flavors, tried in order, has room for every resource it
+// requests from the group. A ClusterQueue in a cohort may borrow up to its borrowingLimit
+// while the cohort's total nominal quota, including quota defined on the Cohort itself, has
+// room. Lending limits, hierarchical cohorts, preemption, fair sharing and admission
+// checks are not modeled.
+package whatif
+
+import (
+	"fmt"
+	"math"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+)
+
+// flavorResource identifies a resource of a flavor
+type flavorResource struct {
+	flavor   string
+	resource corev1.ResourceName
+}
+
+// quotas holds resource amounts in milli-units, by flavor and resource
+type quotas map[flavorResource]int64
+
+// unlimited is the borrowing limit of ClusterQueues without one
+const unlimited = math.MaxInt64
+
+// Model is the quota structure of a Kueue configuration, as the replay sees it
+type Model struct {
+	clusterQueues map[string]*clusterQueue
+	// localQueues maps "namespace/name" to the ClusterQueue name
+	localQueues map[string]string
+	// cohorts holds each cohort's total nominal quota
+	cohorts map[string]quotas
+}
+
+// clusterQueue is the quota of one ClusterQueue
+type clusterQueue struct {
+	name           string
+	cohort         string
+	groups         []resourceGroup
+	nominal        quotas
+	borrowingLimit quotas
+}
+
+// resourceGroup is a set of resources assigned from the same flavor
+type resourceGroup struct {
+	resources map[corev1.ResourceName]bool
+	flavors   []string
+}
+
+// NewModel builds the replay model of a Kueue configuration
+func NewModel(cfg *config.KueueConfig) (*Model, error) {
+	m := &Model{
+		clusterQueues: make(map[string]*clusterQueue, len(cfg.ClusterQueues)),
+		localQueues:   make(map[string]string, len(cfg.LocalQueues)),
+		cohorts:       make(map[string]quotas),
+	}
+
+	for _, c := range cfg.Cohorts {
+		nominal, _, err := parseQuotas(c.ResourceGroups)
+		if err != nil {
+			return nil, fmt.Errorf("cohort %s: %w", c.Name, err)
+		}
+		m.cohorts[c.Name] = nominal
+	}
+
+	for _, cq := range cfg.ClusterQueues {
+		nominal, borrowingLimit, err := parseQuotas(cq.ResourceGroups)
+		if err != nil {
+			return nil, fmt.Errorf("ClusterQueue %s: %w", cq.Name, err)
+		}
+		q := &clusterQueue{name: cq.Name, cohort: cq.Cohort, nominal: nominal, borrowingLimit: borrowingLimit}
+		for _, rg := range cq.ResourceGroups {
+			group := resourceGroup{resources: make(map[corev1.ResourceName]bool, len(rg.CoveredResources))}
+			for _, r := range rg.CoveredResources {
+				group.resources[corev1.ResourceName(r)] = true
+			}
+			for _, f := range rg.Flavors {
+				group.flavors = append(group.flavors, f.Name)
+			}
+			q.groups = append(q.groups, group)
+		}
+		m.clusterQueues[cq.Name] = q
+
+		if cq.Cohort != "" {
+			cohort := m.cohorts[cq.Cohort]
+			if cohort == nil {
+				cohort = quotas{}
+				m.cohorts[cq.Cohort] = cohort
+			}
+			for fr, amount := range nominal {
+				cohort[fr] += amount
+			}
+		}
+	}
+
+	for _, lq := range cfg.LocalQueues {
+		m.localQueues[lq.Namespace+"/"+lq.Name] = lq.ClusterQueue
+	}
+	return m, nil
+}
+
+// parseQuotas returns the nominal quotas and borrowing limits of resource groups in
+// milli-units. Resources without a borrowing limit may borrow without limit.
+func parseQuotas(groups []config.ResourceGroup) (nominal, borrowingLimit quotas, err error) {
+	nominal, borrowingLimit = quotas{}, quotas{}
+	for _, rg := range groups {
+		for _, f := range rg.Flavors {
+			for _, r := range f.Resources {
+				fr := flavorResource{flavor: f.Name, resource: corev1.ResourceName(r.Name)}
+				q, err := resource.ParseQuantity(r.NominalQuota)
+				if err != nil {
+					return nil, nil, fmt.Errorf("flavor %s: invalid nominalQuota for %s: %w", f.Name, r.Name, err)
+				}
+				nominal[fr] = q.MilliValue()
+				borrowingLimit[fr] = unlimited
+				if r.BorrowingLimit != "" {
+					limit, err := resource.ParseQuantity(r.BorrowingLimit)
+					if err != nil {
+						return nil, nil, fmt.Errorf("flavor %s: invalid borrowingLimit for %s: %w", f.Name, r.Name, err)
+					}
+					borrowingLimit[fr] = limit.MilliValue()
+				}
+			}
+		}
+	}
+	return nominal, borrowingLimit, nil
+}
+
+// clusterQueueFor returns the ClusterQueue of a LocalQueue ("namespace/name"), or nil
+func (m *Model) clusterQueueFor(localQueue string) *clusterQueue {
+	return m.clusterQueues[m.localQueues[localQueue]]
+}
+
+// usage tracks the quota in use during a replay
+type usage struct {
+	clusterQueues map[string]quotas
+	cohorts       map[string]quotas
+}
+
+func newUsage() *usage {
+	return &usage{clusterQueues: make(map[string]quotas), cohorts: make(map[string]quotas)}
+}
+
+// assign returns the quota a workload would take from its ClusterQueue, or false if it
+// does not fit now
+func (m *Model) assign(cq *clusterQueue, requests map[corev1.ResourceName]int64, u *usage) (quotas, bool) {
+	used := u.clusterQueues[cq.name]
+	cohortUsed := u.cohorts[cq.cohort]
+	assigned := quotas{}
+	for _, group := range cq.groups {
+		var requested []corev1.ResourceName
+		for name := range requests {
+			if group.resources[name] {
+				requested = append(requested, name)
+			}
+		}
+		if len(requested) == 0 {
+			continue
+		}
+
+		fits := false
+		for _, flavor := range group.flavors {
+			fits = true
+			for _, name := range requested {
+				fr := flavorResource{flavor: flavor, resource: name}
+				if !m.fits(cq, fr, used[fr]+requests[name], cohortUsed[fr]+requests[name]) {
+					fits = false
+					break
+				}
+			}
+			if fits {
+				for _, name := range requested {
+					assigned[flavorResource{flavor: flavor, resource: name}] = requests[name]
+				}
+				break
+			}
+		}
+		if !fits {
+			return nil, false
+		}
+	}
+	return assigned, true
+}
+
+// fits reports whether a ClusterQueue may use amount of a flavor's resource, bringing its
+// cohort's usage to cohortAmount
+func (m *Model) fits(cq *clusterQueue, fr flavorResource, amount, cohortAmount int64) bool {
+	nominal, ok := cq.nominal[fr]
+	if !ok {
+		return false
+	}
+	if cq.cohort == "" {
+		return amount <= nominal
+	}
+	// Nominal quota lent to other queues of the cohort is only available once they return it
+	if limit := cq.borrowingLimit[fr]; amount > nominal && limit != unlimited && amount > nominal+limit {
+		return false
+	}
+	return cohortAmount <= m.cohorts[cq.cohort][fr]
+}
+
+// covers reports whether a ClusterQueue has quota for every resource requested, so the
+// workload can be admitted once quota is free
+func (cq *clusterQueue) covers(requests map[corev1.ResourceName]int64) bool {
+	for name := range requests {
+		covered := false
+		for _, group := range cq.groups {
+			if group.resources[name] {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// add adds (or with sign -1, removes) assigned quota to a ClusterQueue's usage
+func (u *usage) add(cq *clusterQueue, assigned quotas, sign int64) {
+	used := u.clusterQueues[cq.name]
+	if used == nil {
+		used = quotas{}
+		u.clusterQueues[cq.name] = used
+	}
+	cohortUsed := u.cohorts[cq.cohort]
+	if cohortUsed == nil {
+		cohortUsed = quotas{}
+		u.cohorts[cq.cohort] = cohortUsed
+	}
+	for fr, amount := range assigned {
+		used[fr] += sign * amount
+		if cq.cohort != "" {
+			cohortUsed[fr] += sign * amount
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package whatif
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/jhwagner/kueue-bench/pkg/run"
+)
+
+// Workload is one workload of a recorded trace
+type Workload struct {
+	Name string
+	// LocalQueue is the workload's LocalQueue as "namespace/name"
+	LocalQueue string
+	Priority   int32
+	// Requests are the total requests of the workload's pods, in milli-units
+	Requests map[corev1.ResourceName]int64
+	// Submitted is the submission time relative to the first workload of the trace
+	Submitted time.Duration
+	// Runtime is how long the workload holds quota once admitted; 0 holds it until the
+	// end of the replay
+	Runtime time.Duration
+}
+
+// FromTimelines builds a trace from a run's recorded timelines. A workload holds quota
+// from its admission until it finished; workloads that did not finish during the recording
+// are assumed to run for the median runtime of those that did.
+func FromTimelines(timelines []run.WorkloadTimeline) ([]Workload, error) {
+	if len(timelines) == 0 {
+		return nil, fmt.Errorf("the run recorded no workload timelines")
+	}
+	start := timelines[0].SubmittedAt
+	for _, t := range timelines {
+		if t.SubmittedAt.Before(start) {
+			start = t.SubmittedAt
+		}
+	}
+
+	trace := make([]Workload, 0, len(timelines))
+	var runtimes []time.Duration
+	for _, t := range timelines {
+		if t.LocalQueue == "" || len(t.Requests) == 0 {
+			return nil, fmt.Errorf("workload %s was recorded without its queue and requests; rerun the profile to record them", t.Name)
+		}
+		w := Workload{
+			Name:       t.Name,
+			LocalQueue: t.Namespace + "/" + t.LocalQueue,
+			Priority:   t.Priority,
+			Requests:   make(map[corev1.ResourceName]int64, len(t.Requests)),
+			Submitted:  t.SubmittedAt.Sub(start),
+		}
+		for name, q := range t.Requests {
+			w.Requests[name] = q.MilliValue()
+		}
+		if t.AdmittedAt != nil && t.FinishedAt != nil {
+			w.Runtime = max(t.FinishedAt.Sub(*t.AdmittedAt), time.Millisecond)
+			runtimes = append(runtimes, w.Runtime)
+		}
+		trace = append(trace, w)
+	}
+
+	if median := run.NewLatencyStats(runtimes).P50; median > 0 {
+		for i := range trace {
+			if trace[i].Runtime == 0 {
+				trace[i].Runtime = median
+			}
+		}
+	}
+	sort.SliceStable(trace, func(i, j int) bool { return trace[i].Submitted < trace[j].Submitted })
+	return trace, nil
+}
+
+// Outcome is how a workload fared in a replay
+type Outcome struct {
+	// ClusterQueue is the workload's ClusterQueue; empty if its LocalQueue is unknown
+	ClusterQueue string
+	// Wait is the time from submission to admission; nil if it was never admitted
+	Wait *time.Duration
+}
+
+// completion is an admitted workload releasing its quota
+type completion struct {
+	at       time.Duration
+	cq       *clusterQueue
+	assigned quotas
+}
+
+// completions is a min-heap of completions by time
+type completions []completion
+
+func (c completions) Len() int           { return len(c) }
+func (c completions) Less(i, j int) bool { return c[i].at < c[j].at }
+func (c completions) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c *completions) Push(x any)        { *c = append(*c, x.(completion)) }
+func (c *completions) Pop() any {
+	old := *c
+	last := old[len(old)-1]
+	*c = old[:len(old)-1]
+	return last
+}
+
+// Replay admits a trace against the model and returns each workload's outcome by name.
+// The trace must be sorted by submission time, as FromTimelines returns it.
+func (m *Model) Replay(trace []Workload) map[string]Outcome {
+	outcomes := make(map[string]Outcome, len(trace))
+	u := newUsage()
+	running := &completions{}
+	var pending []*Workload
+
+	next := 0
+	for next < len(trace) || running.Len() > 0 {
+		// Advance to the next arrival or completion
+		now := time.Duration(-1)
+		if next < len(trace) {
+			now = trace[next].Submitted
+		}
+		if running.Len() > 0 && (now < 0 || (*running)[0].at < now) {
+			now = (*running)[0].at
+		}
+
+		for running.Len() > 0 && (*running)[0].at <= now {
+			c := heap.Pop(running).(completion)
+			u.add(c.cq, c.assigned, -1)
+		}
+		arrived := false
+		for ; next < len(trace) && trace[next].Submitted <= now; next++ {
+			w := &trace[next]
+			cq := m.clusterQueueFor(w.LocalQueue)
+			if cq == nil {
+				outcomes[w.Name] = Outcome{}
+				continue
+			}
+			outcomes[w.Name] = Outcome{ClusterQueue: cq.name}
+			// Workloads requesting resources their queue has no quota for stay pending forever
+			if cq.covers(w.Requests) {
+				pending = append(pending, w)
+				arrived = true
+			}
+		}
+		if arrived {
+			sort.SliceStable(pending, func(i, j int) bool {
+				if pending[i].Priority != pending[j].Priority {
+					return pending[i].Priority > pending[j].Priority
+				}
+				return pending[i].Submitted < pending[j].Submitted
+			})
+		}
+
+		// Admit every pending workload that fits, in order
+		remaining := pending[:0]
+		for _, w := range pending {
+			cq := m.clusterQueueFor(w.LocalQueue)
+			assigned, ok := m.assign(cq, w.Requests, u)
+			if !ok {
+				remaining = append(remaining, w)
+				continue
+			}
+			u.add(cq, assigned, 1)
+			wait := now - w.Submitted
+			outcomes[w.Name] = Outcome{ClusterQueue: cq.name, Wait: &wait}
+			if w.Runtime > 0 {
+				heap.Push(running, completion{at: now + w.Runtime, cq: cq, assigned: assigned})
+			}
+		}
+		pending = remaining
+	}
+	return outcomes
+}
+
+// Change is a workload whose admission differs between two replays
+type Change struct {
+	Name         string
+	ClusterQueue string
+	// Baseline and WhatIf are the waits for admission; nil if not admitted
+	Baseline *time.Duration
+	WhatIf   *time.Duration
+}
+
+// Delta returns how much later the workload was admitted in the what-if replay; negative
+// if earlier. ok is false unless both replays admitted it.
+func (c Change) Delta() (delta time.Duration, ok bool) {
+	if c.Baseline == nil || c.WhatIf == nil {
+		return 0, false
+	}
+	return *c.WhatIf - *c.Baseline, true
+}
+
+// QueueWaits compares the admission waits of a ClusterQueue's workloads in two replays
+type QueueWaits struct {
+	ClusterQueue string
+	Baseline     run.LatencyStats
+	WhatIf       run.LatencyStats
+}
+
+// Report compares a baseline replay of a trace with a what-if replay
+type Report struct {
+	Workloads int
+	// Earlier and Later count workloads admitted in both replays whose wait moved by more
+	// than the threshold
+	Earlier int
+	Later   int
+	// Admitted counts workloads only the what-if replay admitted; NotAdmitted those only
+	// the baseline admitted
+	Admitted    int
+	NotAdmitted int
+	// Changes lists the workloads counted above, largest change first
+	Changes []Change
+	// Queues compares waits per what-if ClusterQueue, sorted by name
+	Queues []QueueWaits
+}
+
+// Compare replays a trace against a baseline and a what-if model and reports the
+// workloads whose admission moved by more than threshold
+func Compare(trace []Workload, baseline, whatIf *Model, threshold time.Duration) *Report {
+	before := baseline.Replay(trace)
+	after := whatIf.Replay(trace)
+
+	report := &Report{Workloads: len(trace)}
+	baselineWaits := make(map[string][]time.Duration)
+	whatIfWaits := make(map[string][]time.Duration)
+	for _, w := range trace {
+		b, a := before[w.Name], after[w.Name]
+		if b.Wait != nil {
+			baselineWaits[b.ClusterQueue] = append(baselineWaits[b.ClusterQueue], *b.Wait)
+		}
+		if a.Wait != nil {
+			whatIfWaits[a.ClusterQueue] = append(whatIfWaits[a.ClusterQueue], *a.Wait)
+		}
+
+		change := Change{Name: w.Name, ClusterQueue: a.ClusterQueue, Baseline: b.Wait, WhatIf: a.Wait}
+		if change.ClusterQueue == "" {
+			change.ClusterQueue = b.ClusterQueue
+		}
+		switch delta, ok := change.Delta(); {
+		case ok && delta < -threshold:
+			report.Earlier++
+		case ok && delta > threshold:
+			report.Later++
+		case b.Wait == nil && a.Wait != nil:
+			report.Admitted++
+		case b.Wait != nil && a.Wait == nil:
+			report.NotAdmitted++
+		default:
+			continue
+		}
+		report.Changes = append(report.Changes, change)
+	}
+
+	// Admission changes first, then the largest moves
+	magnitude := func(c Change) time.Duration {
+		delta, ok := c.Delta()
+		if !ok {
+			return time.Duration(1<<63 - 1)
+		}
+		return max(delta, -delta)
+	}
+	sort.SliceStable(report.Changes, func(i, j int) bool {
+		return magnitude(report.Changes[i]) > magnitude(report.Changes[j])
+	})
+
+	queues := make(map[string]bool)
+	for name := range baselineWaits {
+		queues[name] = true
+	}
+	for name := range whatIfWaits {
+		queues[name] = true
+	}
+	for name := range queues {
+		if name == "" {
+			continue
+		}
+		report.Queues = append(report.Queues, QueueWaits{
+			ClusterQueue: name,
+			Baseline:     run.NewLatencyStats(baselineWaits[name]),
+			WhatIf:       run.NewLatencyStats(whatIfWaits[name]),
+		})
+	}
+	sort.Slice(report.Queues, func(i, j int) bool { return report.Queues[i].ClusterQueue < report.Queues[j].ClusterQueue })
+	return report
+}
@@ -0,0 +1,172 @@
+package whatif
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/run"
+)
+
+// kueueConfig returns two ClusterQueues, a and b, with the given CPU quotas, in cohort
+// "shared" if set, each fed by a LocalQueue of the same name in namespace "team"
+func kueueConfig(quotaA, quotaB, borrowingLimitA, cohort string) *config.KueueConfig {
+	cq := func(name, quota, borrowingLimit string) config.ClusterQueue {
+		return config.ClusterQueue{
+			Name:   name,
+			Cohort: cohort,
+			ResourceGroups: []config.ResourceGroup{{
+				CoveredResources: []string{"cpu"},
+				Flavors: []config.FlavorQuotas{{Name: "default", Resources: []config.Resource{
+					{Name: "cpu", NominalQuota: quota, BorrowingLimit: borrowingLimit},
+				}}},
+			}},
+		}
+	}
+	return &config.KueueConfig{
+		ClusterQueues: []config.ClusterQueue{cq("a", quotaA, borrowingLimitA), cq("b", quotaB, "")},
+		LocalQueues: []config.LocalQueue{
+			{Name: "a", Namespace: "team", ClusterQueue: "a"},
+			{Name: "b", Namespace: "team", ClusterQueue: "b"},
+		},
+	}
+}
+
+// workload returns a trace workload requesting cpu cores from a LocalQueue
+func workload(name, queue string, cpu int64, submitted, runtime time.Duration) Workload {
+	return Workload{
+		Name:       name,
+		LocalQueue: "team/" + queue,
+		Requests:   map[corev1.ResourceName]int64{corev1.ResourceCPU: cpu * 1000},
+		Submitted:  submitted,
+		Runtime:    runtime,
+	}
+}
+
+func mustModel(t *testing.T, cfg *config.KueueConfig) *Model {
+	t.Helper()
+	m, err := NewModel(cfg)
+	if err != nil {
+		t.Fatalf("NewModel() error = %v", err)
+	}
+	return m
+}
+
+// waits returns the admission wait of each workload, -1 if it was not admitted
+func waits(outcomes map[string]Outcome) map[string]time.Duration {
+	got := make(map[string]time.Duration, len(outcomes))
+	for name, o := range outcomes {
+		got[name] = -1
+		if o.Wait != nil {
+			got[name] = *o.Wait
+		}
+	}
+	return got
+}
+
+func TestReplay(t *testing.T) {
+	trace := []Workload{
+		workload("a-1", "a", 4, 0, time.Minute),
+		workload("a-2", "a", 4, time.Second, time.Minute),
+		workload("b-1", "b", 2, 2*time.Second, time.Minute),
+		workload("too-big", "b", 16, 3*time.Second, time.Minute),
+		workload("unknown", "missing", 1, 4*time.Second, time.Minute),
+	}
+
+	tests := []struct {
+		name string
+		cfg  *config.KueueConfig
+		want map[string]time.Duration
+	}{
+		{
+			name: "no cohort",
+			cfg:  kueueConfig("4", "4", "", ""),
+			// a-2 waits for a-1 to finish; nothing borrows
+			want: map[string]time.Duration{"a-1": 0, "a-2": 59 * time.Second, "b-1": 0, "too-big": -1, "unknown": -1},
+		},
+		{
+			name: "cohort borrowing",
+			cfg:  kueueConfig("4", "4", "", "shared"),
+			// a-2 borrows b's unused quota, leaving b-1 to wait for a-1
+			want: map[string]time.Duration{"a-1": 0, "a-2": 0, "b-1": 58 * time.Second, "too-big": -1, "unknown": -1},
+		},
+		{
+			name: "borrowing limit",
+			cfg:  kueueConfig("4", "4", "2", "shared"),
+			want: map[string]time.Duration{"a-1": 0, "a-2": 59 * time.Second, "b-1": 0, "too-big": -1, "unknown": -1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := waits(mustModel(t, tt.cfg).Replay(trace))
+			for name, want := range tt.want {
+				if got[name] != want {
+					t.Errorf("wait of %s = %s, want %s", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestReplayPriority(t *testing.T) {
+	low := workload("low", "a", 4, time.Second, time.Minute)
+	high := workload("high", "a", 4, 2*time.Second, time.Minute)
+	high.Priority = 100
+	trace := []Workload{workload("first", "a", 4, 0, time.Minute), low, high}
+
+	got := waits(mustModel(t, kueueConfig("4", "4", "", "")).Replay(trace))
+	if got["high"] != 58*time.Second || got["low"] != 119*time.Second {
+		t.Errorf("waits = %v, want high admitted when first finishes, then low", got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	trace := []Workload{
+		workload("a-1", "a", 4, 0, time.Minute),
+		workload("a-2", "a", 4, time.Second, time.Minute),
+		workload("a-3", "a", 6, 2*time.Second, time.Minute),
+		workload("b-1", "b", 2, 3*time.Second, time.Minute),
+	}
+	// Doubling a's quota admits a-2 right away and a-3 (too big for the old quota) at all
+	report := Compare(trace, mustModel(t, kueueConfig("4", "4", "", "")), mustModel(t, kueueConfig("10", "4", "", "")), time.Second)
+
+	if report.Workloads != 4 || report.Earlier != 1 || report.Later != 0 || report.Admitted != 1 || report.NotAdmitted != 0 {
+		t.Errorf("report = %+v, want 1 earlier and 1 newly admitted of 4", report)
+	}
+	if len(report.Changes) != 2 || report.Changes[0].Name != "a-3" || report.Changes[1].Name != "a-2" {
+		t.Fatalf("changes = %+v, want a-3 then a-2", report.Changes)
+	}
+	if delta, ok := report.Changes[1].Delta(); !ok || delta != -59*time.Second {
+		t.Errorf("a-2 delta = %s, %v; want -59s", delta, ok)
+	}
+	if len(report.Queues) != 2 || report.Queues[0].ClusterQueue != "a" || report.Queues[0].WhatIf.Count != 3 || report.Queues[0].Baseline.Count != 2 {
+		t.Errorf("queues = %+v", report.Queues)
+	}
+}
+
+func TestFromTimelines(t *testing.T) {
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { t := start.Add(d); return &t }
+	requests := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m")}
+
+	trace, err := FromTimelines([]run.WorkloadTimeline{
+		{Name: "late", Namespace: "team", LocalQueue: "a", Requests: requests, SubmittedAt: start.Add(time.Minute)},
+		{Name: "done", Namespace: "team", LocalQueue: "a", Requests: requests, SubmittedAt: start, AdmittedAt: at(time.Second), FinishedAt: at(31 * time.Second)},
+	})
+	if err != nil {
+		t.Fatalf("FromTimelines() error = %v", err)
+	}
+	if trace[0].Name != "done" || trace[0].Runtime != 30*time.Second || trace[0].Requests[corev1.ResourceCPU] != 1500 {
+		t.Errorf("trace[0] = %+v, want done running 30s with 1500m CPU", trace[0])
+	}
+	if trace[1].Submitted != time.Minute || trace[1].Runtime != 30*time.Second || trace[1].LocalQueue != "team/a" {
+		t.Errorf("trace[1] = %+v, want the median runtime for the unfinished workload", trace[1])
+	}
+
+	if _, err := FromTimelines([]run.WorkloadTimeline{{Name: "old", SubmittedAt: start}}); err == nil {
+		t.Error("expected an error for timelines recorded without requests")
+	}
+}
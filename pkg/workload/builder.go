@@ -7,12 +7,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/ownership"
 )
 
 // Annotation and label keys injected on every generated workload.
 const (
 	labelProfile       = "kueue-bench.io/profile"
-	labelRunID         = "kueue-bench.io/run-id"
+	labelRunID         = ownership.RunLabel
 	labelWorkloadType  = "kueue-bench.io/workload-type"
 	labelWorkloadIndex = "kueue-bench.io/workload-index"
 
@@ -57,16 +58,39 @@ func builderFor(workloadType string) (WorkloadBuilder, error) {
 
 // workloadName generates the name for a workload.
 func workloadName(runID string, index int) string {
-	return fmt.Sprintf("kueue-bench-%s-%d", runID, index)
+	return fmt.Sprintf("%s%d", workloadNamePrefix(runID), index)
+}
+
+// workloadNamePrefix returns the name prefix shared by all workloads of a run.
+func workloadNamePrefix(runID string) string {
+	return fmt.Sprintf("kueue-bench-%s-", runID)
+}
+
+// setSchedulerName sets schedulerName on every pod spec (any map with "containers") nested in obj.
+func setSchedulerName(obj interface{}, name string) {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		if _, ok := v["containers"]; ok {
+			v["schedulerName"] = name
+		}
+		for _, child := range v {
+			setSchedulerName(child, name)
+		}
+	case []interface{}:
+		for _, child := range v {
+			setSchedulerName(child, name)
+		}
+	}
 }
 
 // commonLabels returns the standard kueue-bench labels for a workload.
 func commonLabels(profileName, runID, workloadType string, index int) map[string]interface{} {
 	return map[string]interface{}{
-		labelProfile:       profileName,
-		labelRunID:         runID,
-		labelWorkloadType:  workloadType,
-		labelWorkloadIndex: fmt.Sprintf("%d", index),
+		ownership.ManagedByLabel: ownership.ManagedBy,
+		labelProfile:             profileName,
+		labelRunID:               runID,
+		labelWorkloadType:        workloadType,
+		labelWorkloadIndex:       fmt.Sprintf("%d", index),
 	}
 }
 
@@ -86,18 +110,47 @@ type workloadMeta struct {
 	labels         map[string]interface{}
 	podAnnotations map[string]interface{} // applied to pod template metadata (e.g. kwok duration); nil if no duration
 	tolerations    []interface{}
+	podPriority    string // Kubernetes PriorityClass of the pods; empty if unset
+}
+
+// podSpec returns a pod spec running one container with the workload's tolerations and
+// pod priority class. restartPolicy is omitted if empty.
+func (m workloadMeta) podSpec(restartPolicy, container string, resources map[string]interface{}) map[string]interface{} {
+	spec := map[string]interface{}{
+		"tolerations": m.tolerations,
+		"containers": []interface{}{
+			map[string]interface{}{
+				"name":      container,
+				"image":     containerImage,
+				"resources": resources,
+			},
+		},
+	}
+	if restartPolicy != "" {
+		spec["restartPolicy"] = restartPolicy
+	}
+	if m.podPriority != "" {
+		spec["priorityClassName"] = m.podPriority
+	}
+	return spec
 }
 
 // buildMeta constructs the name, namespace, labels, and annotations shared by all workload types.
 func buildMeta(spec *config.WorkloadSpec, profileName, runID string, index int, duration *config.Distribution, sampler *Sampler) (workloadMeta, error) {
-	ns := spec.Namespace
+	ns, queue := spec.Namespace, spec.LocalQueue
+	if spread := spec.Spread; spread != nil {
+		nsIndex := sampler.SampleIndex(spread.Namespaces, nil)
+		lqIndex := sampler.SampleIndex(spread.QueuesPerNamespace, nil)
+		ns = config.QueueName(ns, "", nsIndex, lqIndex)
+		queue = config.QueueName(queue, "", nsIndex, lqIndex)
+	}
 	if ns == "" {
 		ns = "default"
 	}
 
 	labels := commonLabels(profileName, runID, spec.Type, index)
-	if spec.LocalQueue != "" {
-		labels[labelQueue] = spec.LocalQueue
+	if queue != "" {
+		labels[labelQueue] = queue
 	}
 	if spec.PriorityClass != "" {
 		labels[labelPriority] = spec.PriorityClass
@@ -132,6 +185,7 @@ func buildMeta(spec *config.WorkloadSpec, profileName, runID string, index int,
 		labels:         labels,
 		podAnnotations: podAnnotations,
 		tolerations:    tolerations,
+		podPriority:    spec.PodPriorityClass,
 	}, nil
 }
 
@@ -214,17 +268,7 @@ func (b *JobBuilder) Build(spec *config.WorkloadSpec, profileName, runID string,
 				"completions": completions,
 				"template": map[string]interface{}{
 					"metadata": podTmplMeta,
-					"spec": map[string]interface{}{
-						"restartPolicy": "Never",
-						"tolerations":   meta.tolerations,
-						"containers": []interface{}{
-							map[string]interface{}{
-								"name":      "workload",
-								"image":     containerImage,
-								"resources": resources,
-							},
-						},
-					},
+					"spec":     meta.podSpec("Never", "workload", resources),
 				},
 			},
 		},
@@ -276,17 +320,7 @@ func (b *JobSetBuilder) Build(spec *config.WorkloadSpec, profileName, runID stri
 					"completions": int64(1),
 					"template": map[string]interface{}{
 						"metadata": innerPodMeta,
-						"spec": map[string]interface{}{
-							"restartPolicy": "Never",
-							"tolerations":   meta.tolerations,
-							"containers": []interface{}{
-								map[string]interface{}{
-									"name":      "workload",
-									"image":     containerImage,
-									"resources": resources,
-								},
-							},
-						},
+						"spec":     meta.podSpec("Never", "workload", resources),
 					},
 				},
 			},
@@ -368,16 +402,7 @@ func (b *RayJobBuilder) Build(spec *config.WorkloadSpec, profileName, runID stri
 					"headGroupSpec": map[string]interface{}{
 						"template": map[string]interface{}{
 							"metadata": podTmplMeta,
-							"spec": map[string]interface{}{
-								"tolerations": meta.tolerations,
-								"containers": []interface{}{
-									map[string]interface{}{
-										"name":      "ray-head",
-										"image":     containerImage,
-										"resources": headResources,
-									},
-								},
-							},
+							"spec":     meta.podSpec("", "ray-head", headResources),
 						},
 					},
 					"workerGroupSpecs": []interface{}{
@@ -386,16 +411,7 @@ func (b *RayJobBuilder) Build(spec *config.WorkloadSpec, profileName, runID stri
 							"replicas":  workerReplicas,
 							"template": map[string]interface{}{
 								"metadata": podTmplMeta,
-								"spec": map[string]interface{}{
-									"tolerations": meta.tolerations,
-									"containers": []interface{}{
-										map[string]interface{}{
-											"name":      "ray-worker",
-											"image":     containerImage,
-											"resources": workerResources,
-										},
-									},
-								},
+								"spec":     meta.podSpec("", "ray-worker", workerResources),
 							},
 						},
 					},
@@ -0,0 +1,129 @@
+package workload
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+)
+
+func TestBuildMetaSpread(t *testing.T) {
+	seed := int64(1)
+	sampler := NewSampler(&seed)
+	spec := &config.WorkloadSpec{
+		Type:       "Job",
+		Namespace:  "cq-ns-{ns}",
+		LocalQueue: "cq-lq-{lq}",
+		Spread:     &config.QueueSpread{Namespaces: 3, QueuesPerNamespace: 2},
+	}
+	want := map[string]bool{}
+	for _, ns := range []string{"cq-ns-0", "cq-ns-1", "cq-ns-2"} {
+		for _, lq := range []string{"cq-lq-0", "cq-lq-1"} {
+			want[ns+"/"+lq] = true
+		}
+	}
+
+	seen := map[string]bool{}
+	for i := range 200 {
+		meta, err := buildMeta(spec, "p", "run", i, nil, sampler)
+		if err != nil {
+			t.Fatalf("buildMeta() error = %v", err)
+		}
+		key := meta.namespace + "/" + meta.labels[labelQueue].(string)
+		if !want[key] {
+			t.Fatalf("workload %d submitted to %s, outside the spread", i, key)
+		}
+		seen[key] = true
+	}
+	if len(seen) != len(want) {
+		t.Errorf("spread reached %d of %d LocalQueues: %v", len(seen), len(want), seen)
+	}
+}
+
+func TestBuildMetaDefaultNamespace(t *testing.T) {
+	meta, err := buildMeta(&config.WorkloadSpec{Type: "Job", LocalQueue: "lq"}, "p", "run", 0, nil, NewSampler(nil))
+	if err != nil {
+		t.Fatalf("buildMeta() error = %v", err)
+	}
+	if meta.namespace != "default" || meta.labels[labelQueue] != "lq" {
+		t.Errorf("namespace, queue = %s, %v, want default, lq", meta.namespace, meta.labels[labelQueue])
+	}
+}
+
+func TestBuildPodPriorityClass(t *testing.T) {
+	cpu := map[string]config.Distribution{"cpu": {Value: "1"}}
+	tests := []struct {
+		name     string
+		template interface{}
+		podSpecs int
+	}{
+		{
+			name:     "Job",
+			template: &config.JobTemplate{Resources: &config.ResourceRequirements{Requests: cpu}},
+			podSpecs: 1,
+		},
+		{
+			name: "JobSet",
+			template: &config.JobSetTemplate{ReplicatedJobs: []config.ReplicatedJobTemplate{
+				{Name: "leader", Resources: &config.ResourceRequirements{Requests: cpu}},
+				{Name: "workers", Resources: &config.ResourceRequirements{Requests: cpu}},
+			}},
+			podSpecs: 2,
+		},
+		{
+			name: "RayJob",
+			template: &config.RayJobTemplate{
+				HeadResources:   &config.ResourceRequirements{Requests: cpu},
+				WorkerResources: &config.ResourceRequirements{Requests: cpu},
+			},
+			podSpecs: 2,
+		},
+	}
+	for _, tt := range tests {
+		for _, priority := range []string{"batch-high", ""} {
+			t.Run(tt.name+"/"+cmp.Or(priority, "unset"), func(t *testing.T) {
+				spec := &config.WorkloadSpec{Type: tt.name, LocalQueue: "lq", PodPriorityClass: priority, Template: tt.template}
+				builder, err := builderFor(tt.name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				obj, _, err := builder.Build(spec, "p", "run", 0, NewSampler(nil))
+				if err != nil {
+					t.Fatalf("Build() error = %v", err)
+				}
+				podSpecs := findPodSpecs(obj.Object)
+				if len(podSpecs) != tt.podSpecs {
+					t.Fatalf("found %d pod specs, want %d", len(podSpecs), tt.podSpecs)
+				}
+				for i, podSpec := range podSpecs {
+					got, set := podSpec["priorityClassName"]
+					if priority == "" && set {
+						t.Errorf("pod spec %d sets priorityClassName %v without podPriorityClass", i, got)
+					}
+					if priority != "" && got != priority {
+						t.Errorf("pod spec %d priorityClassName = %v, want %s", i, got, priority)
+					}
+				}
+			})
+		}
+	}
+}
+
+// findPodSpecs returns the pod specs, maps with containers, nested in obj
+func findPodSpecs(obj interface{}) []map[string]interface{} {
+	var specs []map[string]interface{}
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		if _, ok := v["containers"]; ok {
+			return []map[string]interface{}{v}
+		}
+		for _, child := range v {
+			specs = append(specs, findPodSpecs(child)...)
+		}
+	case []interface{}:
+		for _, child := range v {
+			specs = append(specs, findPodSpecs(child)...)
+		}
+	}
+	return specs
+}
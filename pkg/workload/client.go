@@ -4,13 +4,20 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/jhwagner/kueue-bench/pkg/bulk"
+	"github.com/jhwagner/kueue-bench/pkg/ownership"
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
+// submitQPS bounds workload submission calls, which are issued at the run's arrival rate
+const submitQPS = 200
+
 // WorkloadClient submits workload objects to a Kubernetes cluster using the dynamic client.
 // It uses the dynamic client so that CRD-based workloads (JobSet, RayJob) can be submitted
 // without importing their Go SDK dependencies.
@@ -19,10 +26,10 @@ type WorkloadClient struct {
 }
 
 // NewWorkloadClient creates a WorkloadClient from a kubeconfig path.
-func NewWorkloadClient(kubeconfigPath string) (*WorkloadClient, error) {
-	cfg, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+func NewWorkloadClient(kubeconfigPath string, opts ...restconfig.Option) (*WorkloadClient, error) {
+	cfg, err := restconfig.ForKubeconfig(kubeconfigPath, submitQPS, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+		return nil, err
 	}
 	dyn, err := dynamic.NewForConfig(cfg)
 	if err != nil {
@@ -40,3 +47,24 @@ func (c *WorkloadClient) Create(ctx context.Context, gvr schema.GroupVersionReso
 	}
 	return nil
 }
+
+// DeleteRun deletes the workloads kueue-bench generated for a run, or for every run when
+// runID is empty, in the topology if set, and returns how many were deleted. Workloads are
+// selected by their ownership labels; those submitted before kueue-bench set them are
+// selected by their run label alone. Workload kinds whose CRD is not installed are skipped.
+func (c *WorkloadClient) DeleteRun(ctx context.Context, topology, runID string) (int, error) {
+	deleted := 0
+	for _, selector := range []string{ownership.RunSelector(topology, runID), ownership.LegacyRunSelector(runID)} {
+		for _, gvr := range []schema.GroupVersionResource{jobGVR, jobSetGVR, rayJobGVR} {
+			n, err := bulk.Delete(ctx, c.dynamic, gvr, selector)
+			deleted += n
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return deleted, err
+			}
+		}
+	}
+	return deleted, nil
+}
@@ -3,9 +3,15 @@ package workload
 import (
 	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jhwagner/kueue-bench/pkg/config"
+	"github.com/jhwagner/kueue-bench/pkg/ownership"
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // RunResult contains summary information from a completed Engine.Run invocation.
@@ -25,6 +31,13 @@ type Engine struct {
 	runID     string
 	dryRun    bool
 	onSubmit  func(name, workloadType, namespace string)
+	// schedulerName, if set, overrides the pod templates' schedulerName
+	schedulerName string
+	// topology, if set, labels generated workloads with the topology they run in
+	topology string
+	// submitWorkers is how many workloads may be submitted concurrently
+	submitWorkers int
+	clientOpts    []restconfig.Option
 }
 
 // EngineOption configures an Engine.
@@ -41,6 +54,28 @@ func WithOnSubmit(fn func(name, workloadType, namespace string)) EngineOption {
 	return func(e *Engine) { e.onSubmit = fn }
 }
 
+// WithSchedulerName sets spec.schedulerName on every pod template of generated workloads,
+// e.g. so pods are bound by a Kwok binder instead of kube-scheduler.
+func WithSchedulerName(name string) EngineOption {
+	return func(e *Engine) { e.schedulerName = name }
+}
+
+// WithTopology labels generated workloads with the topology they are submitted to.
+func WithTopology(name string) EngineOption {
+	return func(e *Engine) { e.topology = name }
+}
+
+// WithSubmitWorkers submits up to n workloads concurrently, so slow API calls delay
+// later arrivals less. The default of 1 submits each workload before the next arrival.
+func WithSubmitWorkers(n int) EngineOption {
+	return func(e *Engine) { e.submitWorkers = n }
+}
+
+// WithClientOptions customizes the engine's API client, e.g. with a topology's overrides.
+func WithClientOptions(opts ...restconfig.Option) EngineOption {
+	return func(e *Engine) { e.clientOpts = opts }
+}
+
 // NewEngine creates an Engine from a WorkloadProfile.
 // kubeconfigPath is required unless WithDryRun is set.
 func NewEngine(profile *config.WorkloadProfile, kubeconfigPath, runID string, opts ...EngineOption) (*Engine, error) {
@@ -65,7 +100,7 @@ func NewEngine(profile *config.WorkloadProfile, kubeconfigPath, runID string, op
 		if kubeconfigPath == "" {
 			return nil, fmt.Errorf("kubeconfigPath required when not in dry-run mode")
 		}
-		wc, err := NewWorkloadClient(kubeconfigPath)
+		wc, err := NewWorkloadClient(kubeconfigPath, e.clientOpts...)
 		if err != nil {
 			return nil, fmt.Errorf("workload client: %w", err)
 		}
@@ -100,15 +135,15 @@ func (e *Engine) Run(ctx context.Context) (RunResult, error) {
 		weights[i] = workloads[i].Weight
 	}
 
-	for index := 0; ; index++ {
+	sub := e.newSubmitter(deadlineCtx)
+	for index := 0; sub.ctx.Err() == nil; index++ {
 		interval := e.scheduler.NextInterval()
 
 		timer := time.NewTimer(interval)
 		select {
-		case <-deadlineCtx.Done():
+		case <-sub.ctx.Done():
 			timer.Stop()
-			result.WorkloadCount = index
-			return result, nil
+			continue
 		case <-timer.C:
 		}
 
@@ -116,29 +151,124 @@ func (e *Engine) Run(ctx context.Context) (RunResult, error) {
 
 		builder, err := builderFor(spec.Type)
 		if err != nil {
-			result.WorkloadCount = index
+			result.WorkloadCount, _ = sub.wait()
 			return result, fmt.Errorf("build workload #%d: %w", index, err)
 		}
 
 		obj, gvr, err := builder.Build(spec, e.profile.Metadata.Name, e.runID, index, e.sampler)
 		if err != nil {
-			result.WorkloadCount = index
+			result.WorkloadCount, _ = sub.wait()
 			return result, fmt.Errorf("build workload #%d: %w", index, err)
 		}
 
-		if !e.dryRun {
-			if err := e.client.Create(deadlineCtx, gvr, obj); err != nil {
-				result.WorkloadCount = index
-				if deadlineCtx.Err() != nil {
-					// Profile duration elapsed during the API call; treat as clean termination.
-					return result, nil
-				}
-				return result, fmt.Errorf("submit workload #%d: %w", index, err)
-			}
+		if e.schedulerName != "" {
+			setSchedulerName(obj.Object, e.schedulerName)
+		}
+		if e.topology != "" {
+			ownership.Apply(obj, e.topology, e.runID)
 		}
 
-		if e.onSubmit != nil {
-			e.onSubmit(obj.GetName(), spec.Type, obj.GetNamespace())
+		sub.submit(submission{index: index, obj: obj, gvr: gvr, workloadType: spec.Type})
+	}
+
+	result.WorkloadCount, err = sub.wait()
+	return result, err
+}
+
+// submission is a built workload waiting to be submitted
+type submission struct {
+	index        int
+	obj          *unstructured.Unstructured
+	gvr          schema.GroupVersionResource
+	workloadType string
+}
+
+// submitter submits workloads inline, or on a pool of workers when the engine allows
+// more than one concurrent submission. Its context is cancelled once the profile
+// duration elapses or a submission fails.
+type submitter struct {
+	engine   *Engine
+	deadline context.Context
+	ctx      context.Context
+	cancel   context.CancelFunc
+	jobs     chan submission
+	wg       sync.WaitGroup
+	count    atomic.Int64
+
+	mu  sync.Mutex
+	err error
+}
+
+// newSubmitter creates a submitter, starting its workers if it has more than one
+func (e *Engine) newSubmitter(deadline context.Context) *submitter {
+	ctx, cancel := context.WithCancel(deadline)
+	s := &submitter{engine: e, deadline: deadline, ctx: ctx, cancel: cancel}
+	if e.dryRun || e.submitWorkers <= 1 {
+		return s
+	}
+	s.jobs = make(chan submission)
+	for range e.submitWorkers {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for sub := range s.jobs {
+				s.do(sub)
+			}
+		}()
+	}
+	return s
+}
+
+// submit submits a workload, blocking until it is submitted or, with workers, until a
+// worker is free to take it
+func (s *submitter) submit(sub submission) {
+	if s.jobs == nil {
+		s.do(sub)
+		return
+	}
+	select {
+	case s.jobs <- sub:
+	case <-s.ctx.Done():
+	}
+}
+
+// do creates a workload in the cluster and reports it, recording the first failure
+func (s *submitter) do(sub submission) {
+	e := s.engine
+	if !e.dryRun {
+		if err := e.client.Create(s.ctx, sub.gvr, sub.obj); err != nil {
+			// A failure after the profile duration elapsed is a clean termination
+			if s.deadline.Err() == nil {
+				s.fail(fmt.Errorf("submit workload #%d: %w", sub.index, err))
+			}
+			return
 		}
 	}
+	s.count.Add(1)
+	if e.onSubmit != nil {
+		e.onSubmit(sub.obj.GetName(), sub.workloadType, sub.obj.GetNamespace())
+	}
+}
+
+// fail records a submission failure and stops further submissions
+func (s *submitter) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+	s.cancel()
+}
+
+// wait stops the workers once their submissions finish and returns how many workloads
+// were submitted along with the first failure
+func (s *submitter) wait() (int, error) {
+	if s.jobs != nil {
+		close(s.jobs)
+		s.wg.Wait()
+	}
+	s.cancel()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return int(s.count.Load()), s.err
 }
@@ -0,0 +1,85 @@
+package workload
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jhwagner/kueue-bench/pkg/config"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const engineTestProfile = `apiVersion: kueue-bench.io/v1alpha1
+kind: WorkloadProfile
+metadata:
+  name: engine-test
+spec:
+  seed: 7
+  duration: 200ms
+  arrivalPattern:
+    type: constant
+    ratePerMinute: 60000
+  workloads:
+    - type: Job
+      weight: 1
+      localQueue: lq
+      namespace: default
+      template:
+        resources:
+          requests:
+            cpu: "1"
+`
+
+func TestEngineSubmitWorkers(t *testing.T) {
+	profile, err := config.ParseWorkloadProfile([]byte(engineTestProfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		workers int
+		failAt  int64
+		wantErr string
+	}{
+		{name: "serial", workers: 1},
+		{name: "pool", workers: 4},
+		{name: "pool stops at the first failure", workers: 4, failAt: 5, wantErr: "submit workload #"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dynamic := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+			var creates atomic.Int64
+			dynamic.PrependReactor("create", "*", func(k8stesting.Action) (bool, runtime.Object, error) {
+				if n := creates.Add(1); tc.failAt > 0 && n >= tc.failAt {
+					return true, nil, fmt.Errorf("apiserver unavailable")
+				}
+				return false, nil, nil
+			})
+
+			var submitted atomic.Int64
+			e, err := NewEngine(profile, "", "run", WithDryRun(), WithSubmitWorkers(tc.workers),
+				WithOnSubmit(func(_, _, _ string) { submitted.Add(1) }))
+			if err != nil {
+				t.Fatal(err)
+			}
+			e.dryRun = false
+			e.client = &WorkloadClient{dynamic: dynamic}
+
+			result, err := e.Run(context.Background())
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("Run() error = %v, want %q", err, tc.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if result.WorkloadCount == 0 || int64(result.WorkloadCount) != submitted.Load() {
+				t.Errorf("WorkloadCount = %d, onSubmit calls = %d; want equal and non-zero", result.WorkloadCount, submitted.Load())
+			}
+		})
+	}
+}
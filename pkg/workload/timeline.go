@@ -0,0 +1,694 @@
+package workload
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coreinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	resourcehelpers "k8s.io/component-helpers/resource"
+	kueuev1beta2 "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+	kueueclientset "sigs.k8s.io/kueue/client-go/clientset/versioned"
+	"sigs.k8s.io/kueue/client-go/informers/externalversions"
+
+	"github.com/jhwagner/kueue-bench/pkg/restconfig"
+	"github.com/jhwagner/kueue-bench/pkg/run"
+)
+
+// podTimes holds the lifecycle timestamps observed for a single pod
+type podTimes struct {
+	workload    string // owning workload name
+	scheduledAt *time.Time
+	runningAt   *time.Time
+	finishedAt  *time.Time
+}
+
+// admissionTimes holds the Kueue admission timestamps observed for a workload, and the
+// queue, priority and requests of its Workload
+type admissionTimes struct {
+	quotaReservedAt *time.Time
+	admittedAt      *time.Time
+	checks          []run.AdmissionCheckTimeline
+	localQueue      string
+	clusterQueue    string
+	priority        int32
+	requests        corev1.ResourceList
+}
+
+// spilledPod is the on-disk record of a finished pod's timestamps
+type spilledPod struct {
+	Key         string     `json:"key"`
+	Workload    string     `json:"workload"`
+	ScheduledAt *time.Time `json:"scheduledAt,omitempty"`
+	RunningAt   *time.Time `json:"runningAt,omitempty"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+}
+
+// TimelineScope narrows the watches of a TimelineRecorder
+type TimelineScope struct {
+	// Namespaces are the namespaces the run submits to; empty watches every namespace
+	Namespaces []string
+	// LabeledWorkloads is set when Kueue copies the run label of jobs onto their Workloads,
+	// so only the run's Workloads are watched. Otherwise every Workload in the namespaces
+	// is watched and those of other runs are cached as bare stubs.
+	LabeledWorkloads bool
+}
+
+// TimelineRecorder watches a run's pods and Kueue Workloads and merges their lifecycle
+// timestamps into per-workload timelines. Pods are matched to workloads by the run labels
+// injected by the builders; Kueue Workloads by their owner reference.
+//
+// To keep memory flat at 100k-workload scale, the watches are scoped to the run's
+// namespaces and selected by its run label, the informer caches hold trimmed objects with
+// only the fields timelines are built from, and finished pods are spilled to a temporary
+// file instead of being kept in memory. Call Close to remove the file.
+type TimelineRecorder struct {
+	runID string
+	// One factory of each kind per watched namespace
+	coreFactories  []coreinformers.SharedInformerFactory
+	kueueFactories []externalversions.SharedInformerFactory
+	stopCh         chan struct{}
+	stopOnce       sync.Once
+
+	mu        sync.Mutex
+	submitted map[string]*run.WorkloadTimeline // key: workload name
+	pods      map[string]podTimes              // key: "namespace/name"; pods still running
+	admission map[string]admissionTimes        // key: owning workload name
+
+	// spillDir holds the spill file; empty selects the default temporary directory
+	spillDir string
+	spill    *os.File
+	spillBuf *bufio.Writer
+	spillErr error
+}
+
+// NewTimelineRecorder creates a recorder for the run's workloads on the cluster at
+// kubeconfigPath, watching as scope allows. Call Start before submitting workloads so no
+// transitions are missed.
+func NewTimelineRecorder(kubeconfigPath, runID string, scope TimelineScope, opts ...restconfig.Option) (*TimelineRecorder, error) {
+	cfg, err := restconfig.ForKubeconfig(kubeconfigPath, 0, opts...)
+	if err != nil {
+		return nil, err
+	}
+	k8sClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+	kueueClient, err := kueueclientset.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kueue clientset: %w", err)
+	}
+
+	return newTimelineRecorder(k8sClient, kueueClient, runID, scope), nil
+}
+
+// newTimelineRecorder creates a recorder watching through the given clients
+func newTimelineRecorder(k8sClient kubernetes.Interface, kueueClient kueueclientset.Interface, runID string, scope TimelineScope) *TimelineRecorder {
+	r := &TimelineRecorder{
+		runID:     runID,
+		stopCh:    make(chan struct{}),
+		submitted: make(map[string]*run.WorkloadTimeline),
+		pods:      make(map[string]podTimes),
+		admission: make(map[string]admissionTimes),
+	}
+
+	namespaces := scope.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	runSelector := func(opts *metav1.ListOptions) {
+		opts.LabelSelector = labelRunID + "=" + runID
+	}
+	for _, ns := range namespaces {
+		r.coreFactories = append(r.coreFactories, coreinformers.NewSharedInformerFactoryWithOptions(k8sClient, 0,
+			coreinformers.WithNamespace(ns),
+			coreinformers.WithTweakListOptions(runSelector),
+			coreinformers.WithTransform(trimPod),
+		))
+		kueueOpts := []externalversions.SharedInformerOption{
+			externalversions.WithNamespace(ns),
+			externalversions.WithTransform(trimWorkload(runID)),
+		}
+		if scope.LabeledWorkloads {
+			kueueOpts = append(kueueOpts, externalversions.WithTweakListOptions(runSelector))
+		}
+		r.kueueFactories = append(r.kueueFactories, externalversions.NewSharedInformerFactoryWithOptions(kueueClient, 0, kueueOpts...))
+	}
+	return r
+}
+
+// Start starts the pod and Workload informers and blocks until their caches are synced
+// or ctx is cancelled. The informers run until Stop is called or ctx is cancelled.
+func (r *TimelineRecorder) Start(ctx context.Context) error {
+	for _, factory := range r.coreFactories {
+		if _, err := factory.Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { r.observePod(obj) },
+			UpdateFunc: func(_, newObj interface{}) { r.observePod(newObj) },
+		}); err != nil {
+			return fmt.Errorf("failed to add pod event handler: %w", err)
+		}
+		factory.Start(r.stopCh)
+	}
+	for _, factory := range r.kueueFactories {
+		if _, err := factory.Kueue().V1beta2().Workloads().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { r.observeWorkload(obj) },
+			UpdateFunc: func(_, newObj interface{}) { r.observeWorkload(newObj) },
+		}); err != nil {
+			return fmt.Errorf("failed to add workload event handler: %w", err)
+		}
+		factory.Start(r.stopCh)
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			r.Stop()
+		case <-r.stopCh:
+		}
+	}()
+
+	for _, factory := range r.coreFactories {
+		for _, ok := range factory.WaitForCacheSync(r.stopCh) {
+			if !ok {
+				return fmt.Errorf("pod cache sync failed")
+			}
+		}
+	}
+	for _, factory := range r.kueueFactories {
+		for _, ok := range factory.WaitForCacheSync(r.stopCh) {
+			if !ok {
+				return fmt.Errorf("workload cache sync failed")
+			}
+		}
+	}
+	return nil
+}
+
+// Stop stops the informers. Timestamps recorded so far remain available. Safe to call
+// multiple times.
+func (r *TimelineRecorder) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+		for _, factory := range r.coreFactories {
+			factory.Shutdown()
+		}
+		for _, factory := range r.kueueFactories {
+			factory.Shutdown()
+		}
+	})
+}
+
+// Close removes the spill file, after which Timelines no longer includes finished pods.
+// Call it once the recorder is stopped and its timelines saved. Safe to call multiple times.
+func (r *TimelineRecorder) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.spill == nil {
+		return
+	}
+	_ = r.spill.Close()
+	_ = os.Remove(r.spill.Name())
+	r.spill = nil
+	r.spillBuf = nil
+}
+
+// Submitted records that a workload was submitted. Its signature matches WithOnSubmit.
+func (r *TimelineRecorder) Submitted(name, workloadType, namespace string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.submitted[name] = &run.WorkloadTimeline{
+		Name:        name,
+		Namespace:   namespace,
+		Type:        workloadType,
+		SubmittedAt: time.Now(),
+	}
+}
+
+// Timelines returns the timelines of all submitted workloads, in submission order.
+func (r *TimelineRecorder) Timelines() []run.WorkloadTimeline {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	spilled, err := r.readSpill()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read spilled pod timestamps, timelines are incomplete: %v\n", err)
+	}
+
+	timelines := make([]run.WorkloadTimeline, 0, len(r.submitted))
+	for _, t := range r.submitted {
+		timeline := *t
+		if a, ok := r.admission[t.Name]; ok {
+			timeline.QuotaReservedAt = a.quotaReservedAt
+			timeline.AdmittedAt = a.admittedAt
+			timeline.AdmissionChecks = cloneCheckTimelines(a.checks)
+			timeline.LocalQueue = a.localQueue
+			timeline.ClusterQueue = a.clusterQueue
+			timeline.Priority = a.priority
+			timeline.Requests = a.requests
+		}
+		timelines = append(timelines, timeline)
+	}
+
+	index := make(map[string]int, len(timelines))
+	for i := range timelines {
+		index[timelines[i].Name] = i
+	}
+	for key, p := range spilled {
+		// A pod observed again after it was spilled is only counted once
+		if _, ok := r.pods[key]; ok {
+			continue
+		}
+		if i, ok := index[p.workload]; ok {
+			mergePodTimes(&timelines[i], p)
+		}
+	}
+	for _, p := range r.pods {
+		if i, ok := index[p.workload]; ok {
+			mergePodTimes(&timelines[i], p)
+		}
+	}
+
+	sort.Slice(timelines, func(i, j int) bool {
+		return timelines[i].SubmittedAt.Before(timelines[j].SubmittedAt)
+	})
+	return timelines
+}
+
+func (r *TimelineRecorder) observePod(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	index, err := strconv.Atoi(pod.Labels[labelWorkloadIndex])
+	if err != nil {
+		return
+	}
+
+	times := buildPodTimes(pod)
+	times.workload = workloadName(r.runID, index)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Ready turns False again when a pod completes, so keep timestamps seen earlier
+	key := pod.Namespace + "/" + pod.Name
+	if prev, ok := r.pods[key]; ok {
+		if times.scheduledAt == nil {
+			times.scheduledAt = prev.scheduledAt
+		}
+		if times.runningAt == nil {
+			times.runningAt = prev.runningAt
+		}
+	}
+	// Finished pods change no further, so their timestamps move to disk
+	if times.finishedAt != nil && r.spillPod(key, times) {
+		delete(r.pods, key)
+		return
+	}
+	r.pods[key] = times
+}
+
+// spillPod appends a finished pod's timestamps to the spill file, creating it on first
+// use. It reports whether the pod was spilled; after a write error pods stay in memory.
+// r.mu must be held.
+func (r *TimelineRecorder) spillPod(key string, times podTimes) bool {
+	if r.spillErr != nil {
+		return false
+	}
+	if r.spill == nil {
+		f, err := os.CreateTemp(r.spillDir, "kueue-bench-pods-*.jsonl")
+		if err != nil {
+			r.spillErr = err
+			fmt.Fprintf(os.Stderr, "Warning: failed to create pod spill file, keeping pods in memory: %v\n", err)
+			return false
+		}
+		r.spill = f
+		r.spillBuf = bufio.NewWriter(f)
+	}
+
+	record := spilledPod{
+		Key:         key,
+		Workload:    times.workload,
+		ScheduledAt: times.scheduledAt,
+		RunningAt:   times.runningAt,
+		FinishedAt:  times.finishedAt,
+	}
+	if err := json.NewEncoder(r.spillBuf).Encode(record); err != nil {
+		r.spillErr = err
+		fmt.Fprintf(os.Stderr, "Warning: failed to spill pod timestamps, keeping pods in memory: %v\n", err)
+		return false
+	}
+	return true
+}
+
+// readSpill reads the spilled pods back, keyed by "namespace/name". A pod spilled more
+// than once keeps its first record, which was merged with its earlier observations.
+// r.mu must be held.
+func (r *TimelineRecorder) readSpill() (map[string]podTimes, error) {
+	pods := make(map[string]podTimes)
+	if r.spill == nil {
+		return pods, nil
+	}
+	if err := r.spillBuf.Flush(); err != nil {
+		return pods, err
+	}
+
+	f, err := os.Open(r.spill.Name())
+	if err != nil {
+		return pods, err
+	}
+	defer func() { _ = f.Close() }()
+
+	decoder := json.NewDecoder(f)
+	for {
+		var record spilledPod
+		if err := decoder.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return pods, nil
+			}
+			return pods, err
+		}
+		if _, ok := pods[record.Key]; ok {
+			continue
+		}
+		pods[record.Key] = podTimes{
+			workload:    record.Workload,
+			scheduledAt: record.ScheduledAt,
+			runningAt:   record.RunningAt,
+			finishedAt:  record.FinishedAt,
+		}
+	}
+}
+
+func (r *TimelineRecorder) observeWorkload(obj interface{}) {
+	wl, ok := obj.(*kueuev1beta2.Workload)
+	if !ok {
+		return
+	}
+	owner := controllerName(wl.OwnerReferences)
+	if !strings.HasPrefix(owner, workloadNamePrefix(r.runID)) {
+		return
+	}
+
+	times := admissionTimes{
+		quotaReservedAt: conditionTime(wl.Status.Conditions, kueuev1beta2.WorkloadQuotaReserved),
+		admittedAt:      conditionTime(wl.Status.Conditions, kueuev1beta2.WorkloadAdmitted),
+		localQueue:      string(wl.Spec.QueueName),
+		requests:        workloadRequests(wl.Spec.PodSets),
+	}
+	if wl.Spec.Priority != nil {
+		times.priority = *wl.Spec.Priority
+	}
+	if wl.Status.Admission != nil {
+		times.clusterQueue = string(wl.Status.Admission.ClusterQueue)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Keep the first admission: a workload evicted (e.g. preempted) and admitted again
+	// would otherwise report its re-admission as its queueing latency
+	prev := r.admission[owner]
+	if prev.quotaReservedAt != nil {
+		times.quotaReservedAt = prev.quotaReservedAt
+	}
+	if prev.admittedAt != nil {
+		times.admittedAt = prev.admittedAt
+	}
+	if prev.clusterQueue != "" {
+		times.clusterQueue = prev.clusterQueue
+	}
+	// Check states are only visible while current, so transitions accumulate across updates
+	times.checks = observeChecks(prev.checks, wl.Status.AdmissionChecks, time.Now())
+	r.admission[owner] = times
+}
+
+// trimPod is the pod informer's transform. It keeps only the fields buildPodTimes reads,
+// so the cache of a large run holds a fraction of each pod.
+func trimPod(obj interface{}) (interface{}, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return obj, nil
+	}
+	trimmed := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            pod.Name,
+			Namespace:       pod.Namespace,
+			UID:             pod.UID,
+			ResourceVersion: pod.ResourceVersion,
+		},
+		Status: corev1.PodStatus{Phase: pod.Status.Phase},
+	}
+	if index, ok := pod.Labels[labelWorkloadIndex]; ok {
+		trimmed.Labels = map[string]string{labelWorkloadIndex: index}
+	}
+	for _, c := range pod.Status.Conditions {
+		trimmed.Status.Conditions = append(trimmed.Status.Conditions, corev1.PodCondition{
+			Type:               c.Type,
+			Status:             c.Status,
+			LastTransitionTime: c.LastTransitionTime,
+		})
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Terminated == nil {
+			continue
+		}
+		trimmed.Status.ContainerStatuses = append(trimmed.Status.ContainerStatuses, corev1.ContainerStatus{
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				StartedAt:  cs.State.Terminated.StartedAt,
+				FinishedAt: cs.State.Terminated.FinishedAt,
+			}},
+		})
+	}
+	return trimmed, nil
+}
+
+// trimWorkload returns the Workload informer's transform. Workloads of the run keep only
+// their owner references, queue, priority, pod set requests, admitting ClusterQueue,
+// conditions and AdmissionCheck states; those of other runs, watched when Kueue does not
+// copy the run label onto Workloads, are reduced to their identity.
+func trimWorkload(runID string) cache.TransformFunc {
+	return func(obj interface{}) (interface{}, error) {
+		wl, ok := obj.(*kueuev1beta2.Workload)
+		if !ok {
+			return obj, nil
+		}
+		trimmed := &kueuev1beta2.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            wl.Name,
+				Namespace:       wl.Namespace,
+				UID:             wl.UID,
+				ResourceVersion: wl.ResourceVersion,
+			},
+		}
+		if !strings.HasPrefix(controllerName(wl.OwnerReferences), workloadNamePrefix(runID)) {
+			return trimmed, nil
+		}
+		trimmed.OwnerReferences = wl.OwnerReferences
+		trimmed.Spec.QueueName = wl.Spec.QueueName
+		trimmed.Spec.Priority = wl.Spec.Priority
+		for _, ps := range wl.Spec.PodSets {
+			trimmed.Spec.PodSets = append(trimmed.Spec.PodSets, kueuev1beta2.PodSet{
+				Name:     ps.Name,
+				Count:    ps.Count,
+				Template: corev1.PodTemplateSpec{Spec: trimPodSpec(ps.Template.Spec)},
+			})
+		}
+		if wl.Status.Admission != nil {
+			trimmed.Status.Admission = &kueuev1beta2.Admission{ClusterQueue: wl.Status.Admission.ClusterQueue}
+		}
+		for _, c := range wl.Status.Conditions {
+			trimmed.Status.Conditions = append(trimmed.Status.Conditions, metav1.Condition{
+				Type:               c.Type,
+				Status:             c.Status,
+				LastTransitionTime: c.LastTransitionTime,
+			})
+		}
+		for _, s := range wl.Status.AdmissionChecks {
+			trimmed.Status.AdmissionChecks = append(trimmed.Status.AdmissionChecks, kueuev1beta2.AdmissionCheckState{
+				Name:               s.Name,
+				State:              s.State,
+				LastTransitionTime: s.LastTransitionTime,
+				RetryCount:         s.RetryCount,
+			})
+		}
+		return trimmed, nil
+	}
+}
+
+// trimPodSpec keeps only what a pod spec's requests are computed from: container requests,
+// the restart policy marking init containers as sidecars, pod-level requests and overhead
+func trimPodSpec(spec corev1.PodSpec) corev1.PodSpec {
+	trim := func(containers []corev1.Container) []corev1.Container {
+		var trimmed []corev1.Container
+		for _, c := range containers {
+			trimmed = append(trimmed, corev1.Container{
+				Resources:     corev1.ResourceRequirements{Requests: c.Resources.Requests},
+				RestartPolicy: c.RestartPolicy,
+			})
+		}
+		return trimmed
+	}
+	trimmed := corev1.PodSpec{
+		InitContainers: trim(spec.InitContainers),
+		Containers:     trim(spec.Containers),
+		Overhead:       spec.Overhead,
+	}
+	if spec.Resources != nil {
+		trimmed.Resources = &corev1.ResourceRequirements{Requests: spec.Resources.Requests}
+	}
+	return trimmed
+}
+
+// workloadRequests returns the total requests of a Workload's pods, computed per pod as
+// Kueue does with the Kubernetes PodRequests helper: sidecars, init containers, pod-level
+// requests and pod overhead included.
+func workloadRequests(podSets []kueuev1beta2.PodSet) corev1.ResourceList {
+	total := corev1.ResourceList{}
+	for _, ps := range podSets {
+		pod := resourcehelpers.PodRequests(&corev1.Pod{Spec: ps.Template.Spec}, resourcehelpers.PodResourcesOptions{})
+		for name, request := range pod {
+			// Mul falls back to exact decimal arithmetic when the product overflows int64
+			q := request.DeepCopy()
+			q.Mul(int64(ps.Count))
+			sum := total[name]
+			sum.Add(q)
+			total[name] = sum
+		}
+	}
+	if len(total) == 0 {
+		return nil
+	}
+	return total
+}
+
+// controllerName returns the name of the controlling owner, or "" if there is none
+func controllerName(refs []metav1.OwnerReference) string {
+	for _, ref := range refs {
+		if ref.Controller != nil && *ref.Controller {
+			return ref.Name
+		}
+	}
+	return ""
+}
+
+// observeChecks appends the current AdmissionCheck states to the check timelines seen so far.
+// A state is recorded as a transition when it differs from the check's last recorded state
+// or re-entered it at a later time. Checks without a transition time are stamped with now.
+func observeChecks(checks []run.AdmissionCheckTimeline, states []kueuev1beta2.AdmissionCheckState, now time.Time) []run.AdmissionCheckTimeline {
+	for _, s := range states {
+		i := slices.IndexFunc(checks, func(c run.AdmissionCheckTimeline) bool { return c.Name == string(s.Name) })
+		if i < 0 {
+			checks = append(checks, run.AdmissionCheckTimeline{Name: string(s.Name)})
+			i = len(checks) - 1
+		}
+		check := &checks[i]
+
+		at := now
+		if !s.LastTransitionTime.IsZero() {
+			at = s.LastTransitionTime.Time
+		}
+		state := string(s.State)
+		if n := len(check.Transitions); n == 0 || check.Transitions[n-1].State != state || check.Transitions[n-1].At.Before(at) {
+			check.Transitions = append(check.Transitions, run.CheckStateTransition{State: state, At: at})
+			if state == run.CheckStateRetry {
+				check.Retries++
+			}
+		}
+		if s.RetryCount != nil && int(*s.RetryCount) > check.Retries {
+			check.Retries = int(*s.RetryCount)
+		}
+	}
+	return checks
+}
+
+// cloneCheckTimelines deep-copies check timelines so callers cannot race the recorder
+func cloneCheckTimelines(checks []run.AdmissionCheckTimeline) []run.AdmissionCheckTimeline {
+	if len(checks) == 0 {
+		return nil
+	}
+	out := make([]run.AdmissionCheckTimeline, len(checks))
+	for i, c := range checks {
+		out[i] = c
+		out[i].Transitions = slices.Clone(c.Transitions)
+	}
+	return out
+}
+
+// buildPodTimes extracts when a pod was bound to a node, became ready, and terminated
+func buildPodTimes(pod *corev1.Pod) podTimes {
+	var times podTimes
+	for _, c := range pod.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case corev1.PodScheduled:
+			times.scheduledAt = timePtr(c.LastTransitionTime)
+		case corev1.PodReady:
+			times.runningAt = timePtr(c.LastTransitionTime)
+		}
+	}
+
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Terminated == nil {
+				continue
+			}
+			times.finishedAt = latest(times.finishedAt, timePtr(cs.State.Terminated.FinishedAt))
+			if times.runningAt == nil {
+				// Completed before it was observed running
+				times.runningAt = timePtr(cs.State.Terminated.StartedAt)
+			}
+		}
+	}
+	return times
+}
+
+// mergePodTimes folds a pod's timestamps into its workload's timeline, keeping the latest
+// of each milestone across the workload's pods
+func mergePodTimes(t *run.WorkloadTimeline, p podTimes) {
+	t.Pods++
+	t.PodsScheduledAt = latest(t.PodsScheduledAt, p.scheduledAt)
+	t.PodsRunningAt = latest(t.PodsRunningAt, p.runningAt)
+	t.FinishedAt = latest(t.FinishedAt, p.finishedAt)
+}
+
+// latest returns the later of two optional timestamps
+func latest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
+
+// conditionTime returns when a condition last became True, or nil if it is not True
+func conditionTime(conditions []metav1.Condition, condType string) *time.Time {
+	for _, c := range conditions {
+		if c.Type == condType && c.Status == metav1.ConditionTrue {
+			return timePtr(c.LastTransitionTime)
+		}
+	}
+	return nil
+}
+
+// timePtr returns a pointer to t's time, or nil if t is zero
+func timePtr(t metav1.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
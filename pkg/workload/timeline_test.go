@@ -0,0 +1,426 @@
+package workload
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	kueuev1beta2 "sigs.k8s.io/kueue/apis/kueue/v1beta2"
+	kueuefake "sigs.k8s.io/kueue/client-go/clientset/versioned/fake"
+
+	"github.com/jhwagner/kueue-bench/pkg/run"
+)
+
+// TestTimelineRecorderMergesPods verifies pod timestamps are attributed to their workload,
+// survive Ready turning False on completion, and keep the latest pod per milestone.
+func TestTimelineRecorderMergesPods(t *testing.T) {
+	base := time.Date(2026, 3, 28, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) metav1.Time { return metav1.NewTime(base.Add(d)) }
+
+	pod := func(name string, phase corev1.PodPhase, conditions ...corev1.PodCondition) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{labelRunID: "abc", labelWorkloadIndex: "0"},
+			},
+			Status: corev1.PodStatus{Phase: phase, Conditions: conditions},
+		}
+	}
+	scheduled := func(d time.Duration) corev1.PodCondition {
+		return corev1.PodCondition{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: at(d)}
+	}
+	ready := func(status corev1.ConditionStatus, d time.Duration) corev1.PodCondition {
+		return corev1.PodCondition{Type: corev1.PodReady, Status: status, LastTransitionTime: at(d)}
+	}
+
+	r := &TimelineRecorder{
+		runID:     "abc",
+		submitted: make(map[string]*run.WorkloadTimeline),
+		pods:      make(map[string]podTimes),
+		admission: make(map[string]admissionTimes),
+		spillDir:  t.TempDir(),
+	}
+	defer r.Close()
+	r.Submitted(workloadName("abc", 0), "job", "default")
+
+	r.observePod(pod("p0", corev1.PodRunning, scheduled(1*time.Second), ready(corev1.ConditionTrue, 2*time.Second)))
+	r.observePod(pod("p0", corev1.PodSucceeded, scheduled(1*time.Second), ready(corev1.ConditionFalse, 9*time.Second)))
+	r.observePod(pod("p1", corev1.PodRunning, scheduled(3*time.Second), ready(corev1.ConditionTrue, 5*time.Second)))
+
+	timelines := r.Timelines()
+	if len(timelines) != 1 {
+		t.Fatalf("Timelines() returned %d timelines, want 1", len(timelines))
+	}
+	got := timelines[0]
+	if got.Pods != 2 {
+		t.Errorf("Pods = %d, want 2", got.Pods)
+	}
+	if got.PodsScheduledAt == nil || !got.PodsScheduledAt.Equal(base.Add(3*time.Second)) {
+		t.Errorf("PodsScheduledAt = %v, want %v", got.PodsScheduledAt, base.Add(3*time.Second))
+	}
+	if got.PodsRunningAt == nil || !got.PodsRunningAt.Equal(base.Add(5*time.Second)) {
+		t.Errorf("PodsRunningAt = %v, want %v", got.PodsRunningAt, base.Add(5*time.Second))
+	}
+}
+
+// TestTimelineRecorderSpillsFinishedPods verifies finished pods leave memory for the spill
+// file, still count towards their workload, and are counted once if observed again.
+func TestTimelineRecorderSpillsFinishedPods(t *testing.T) {
+	base := time.Date(2026, 3, 28, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) metav1.Time { return metav1.NewTime(base.Add(d)) }
+	finished := func(name string, readyAt time.Duration) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{labelWorkloadIndex: "0"},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodSucceeded,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: at(time.Second)},
+					{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: at(readyAt)},
+				},
+				ContainerStatuses: []corev1.ContainerStatus{{
+					State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+						StartedAt:  at(readyAt),
+						FinishedAt: at(10 * time.Second),
+					}},
+				}},
+			},
+		}
+	}
+
+	r := &TimelineRecorder{
+		runID:     "abc",
+		submitted: make(map[string]*run.WorkloadTimeline),
+		pods:      make(map[string]podTimes),
+		admission: make(map[string]admissionTimes),
+		spillDir:  t.TempDir(),
+	}
+	defer r.Close()
+	r.Submitted(workloadName("abc", 0), "job", "default")
+
+	r.observePod(finished("p0", 2*time.Second))
+	r.observePod(finished("p1", 3*time.Second))
+	// Re-observed after spilling, e.g. when the pod is deleted, with a different Ready time
+	r.observePod(finished("p0", 7*time.Second))
+
+	if len(r.pods) != 0 {
+		t.Errorf("%d finished pods kept in memory, want 0", len(r.pods))
+	}
+
+	timelines := r.Timelines()
+	if len(timelines) != 1 {
+		t.Fatalf("Timelines() returned %d timelines, want 1", len(timelines))
+	}
+	got := timelines[0]
+	if got.Pods != 2 {
+		t.Errorf("Pods = %d, want 2", got.Pods)
+	}
+	if got.PodsRunningAt == nil || !got.PodsRunningAt.Equal(base.Add(3*time.Second)) {
+		t.Errorf("PodsRunningAt = %v, want %v", got.PodsRunningAt, base.Add(3*time.Second))
+	}
+	if got.FinishedAt == nil || !got.FinishedAt.Equal(base.Add(10*time.Second)) {
+		t.Errorf("FinishedAt = %v, want %v", got.FinishedAt, base.Add(10*time.Second))
+	}
+
+	spill := r.spill.Name()
+	r.Close()
+	if _, err := os.Stat(spill); !os.IsNotExist(err) {
+		t.Errorf("spill file %s not removed by Close: %v", spill, err)
+	}
+}
+
+// TestTrimTransforms verifies the informer transforms keep what timelines are built from
+// TestTimelineRecorderKeepsFirstAdmission verifies a workload evicted and admitted again
+// keeps the timestamps and ClusterQueue of its first admission.
+func TestTimelineRecorderKeepsFirstAdmission(t *testing.T) {
+	base := time.Date(2026, 3, 28, 12, 0, 0, 0, time.UTC)
+	controller := true
+	wl := func(admitted bool, at time.Duration) *kueuev1beta2.Workload {
+		w := &kueuev1beta2.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "wl",
+				Namespace:       "default",
+				OwnerReferences: []metav1.OwnerReference{{Name: workloadName("abc", 0), Controller: &controller}},
+			},
+		}
+		status := metav1.ConditionFalse
+		if admitted {
+			status = metav1.ConditionTrue
+			w.Status.Admission = &kueuev1beta2.Admission{ClusterQueue: "cq"}
+		}
+		for _, condition := range []string{kueuev1beta2.WorkloadQuotaReserved, kueuev1beta2.WorkloadAdmitted} {
+			w.Status.Conditions = append(w.Status.Conditions, metav1.Condition{
+				Type: condition, Status: status, LastTransitionTime: metav1.NewTime(base.Add(at)),
+			})
+		}
+		return w
+	}
+
+	r := &TimelineRecorder{
+		runID:     "abc",
+		submitted: make(map[string]*run.WorkloadTimeline),
+		pods:      make(map[string]podTimes),
+		admission: make(map[string]admissionTimes),
+	}
+	r.Submitted(workloadName("abc", 0), "job", "default")
+
+	r.observeWorkload(wl(true, time.Second))
+	r.observeWorkload(wl(false, 5*time.Second)) // preempted
+	r.observeWorkload(wl(true, 9*time.Second))  // admitted again
+
+	got := r.Timelines()[0]
+	if got.AdmittedAt == nil || !got.AdmittedAt.Equal(base.Add(time.Second)) {
+		t.Errorf("AdmittedAt = %v, want the first admission %v", got.AdmittedAt, base.Add(time.Second))
+	}
+	if got.QuotaReservedAt == nil || !got.QuotaReservedAt.Equal(base.Add(time.Second)) {
+		t.Errorf("QuotaReservedAt = %v, want the first reservation %v", got.QuotaReservedAt, base.Add(time.Second))
+	}
+	if got.ClusterQueue != "cq" {
+		t.Errorf("ClusterQueue = %q, want cq", got.ClusterQueue)
+	}
+}
+
+// TestTimelineRecorderScope verifies the recorder only watches the run's namespaces, and
+// selects Workloads by the run label when Kueue copies it.
+func TestTimelineRecorderScope(t *testing.T) {
+	meta := func(name, namespace, runID string) metav1.ObjectMeta {
+		m := metav1.ObjectMeta{Name: name, Namespace: namespace}
+		if runID != "" {
+			m.Labels = map[string]string{labelRunID: runID}
+		}
+		return m
+	}
+	pod := func(name, namespace, runID string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: meta(name, namespace, runID)}
+	}
+	wl := func(name, namespace, runID string) *kueuev1beta2.Workload {
+		return &kueuev1beta2.Workload{ObjectMeta: meta(name, namespace, runID)}
+	}
+	k8sClient := fake.NewClientset(
+		pod("run", "team-a", "abc"),
+		pod("other-run", "team-a", "xyz"),
+		pod("other-namespace", "team-b", "abc"),
+	)
+	kueueClient := kueuefake.NewSimpleClientset(
+		wl("run", "team-a", "abc"),
+		wl("other-run", "team-a", "xyz"),
+		wl("unlabeled", "team-a", ""),
+		wl("other-namespace", "team-b", "abc"),
+	)
+
+	tests := []struct {
+		name          string
+		scope         TimelineScope
+		wantPods      []string
+		wantWorkloads []string
+	}{
+		{
+			name:          "labeled workloads",
+			scope:         TimelineScope{Namespaces: []string{"team-a"}, LabeledWorkloads: true},
+			wantPods:      []string{"team-a/run"},
+			wantWorkloads: []string{"team-a/run"},
+		},
+		{
+			name:          "unlabeled workloads",
+			scope:         TimelineScope{Namespaces: []string{"team-a"}},
+			wantPods:      []string{"team-a/run"},
+			wantWorkloads: []string{"team-a/other-run", "team-a/run", "team-a/unlabeled"},
+		},
+		{
+			name:          "all namespaces",
+			scope:         TimelineScope{LabeledWorkloads: true},
+			wantPods:      []string{"team-a/run", "team-b/other-namespace"},
+			wantWorkloads: []string{"team-a/run", "team-b/other-namespace"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTimelineRecorder(k8sClient, kueueClient, "abc", tt.scope)
+			if err := r.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			defer r.Stop()
+
+			var pods, workloads []string
+			for _, factory := range r.coreFactories {
+				pods = append(pods, factory.Core().V1().Pods().Informer().GetStore().ListKeys()...)
+			}
+			for _, factory := range r.kueueFactories {
+				workloads = append(workloads, factory.Kueue().V1beta2().Workloads().Informer().GetStore().ListKeys()...)
+			}
+			slices.Sort(pods)
+			slices.Sort(workloads)
+			if !slices.Equal(pods, tt.wantPods) {
+				t.Errorf("watched pods %v, want %v", pods, tt.wantPods)
+			}
+			if !slices.Equal(workloads, tt.wantWorkloads) {
+				t.Errorf("watched Workloads %v, want %v", workloads, tt.wantWorkloads)
+			}
+		})
+	}
+}
+
+// and reduce other runs' Workloads to stubs.
+func TestTrimTransforms(t *testing.T) {
+	controller := true
+	now := metav1.NewTime(time.Date(2026, 3, 28, 12, 0, 0, 0, time.UTC))
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "p0",
+			Namespace:   "default",
+			Labels:      map[string]string{labelRunID: "abc", labelWorkloadIndex: "4"},
+			Annotations: map[string]string{"large": "annotation"},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: "busybox"}}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: now, Message: "ok"}},
+		},
+	}
+	obj, err := trimPod(pod)
+	if err != nil {
+		t.Fatalf("trimPod() error = %v", err)
+	}
+	trimmedPod := obj.(*corev1.Pod)
+	if len(trimmedPod.Spec.Containers) != 0 || trimmedPod.Annotations != nil {
+		t.Errorf("trimPod() kept spec or annotations: %+v", trimmedPod)
+	}
+	if trimmedPod.Labels[labelWorkloadIndex] != "4" || buildPodTimes(trimmedPod).runningAt == nil {
+		t.Errorf("trimPod() dropped timeline fields: %+v", trimmedPod)
+	}
+
+	workload := func(owner string) *kueuev1beta2.Workload {
+		return &kueuev1beta2.Workload{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "wl",
+				Namespace:       "default",
+				OwnerReferences: []metav1.OwnerReference{{Name: owner, Controller: &controller}},
+			},
+			Spec: kueuev1beta2.WorkloadSpec{
+				QueueName: "lq",
+				PodSets: []kueuev1beta2.PodSet{{Name: "main", Count: 3, Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{
+						Name:      "main",
+						Image:     "busybox",
+						Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")}},
+					}}},
+				}}},
+			},
+			Status: kueuev1beta2.WorkloadStatus{
+				Admission:  &kueuev1beta2.Admission{ClusterQueue: "cq", PodSetAssignments: []kueuev1beta2.PodSetAssignment{{Name: "main"}}},
+				Conditions: []metav1.Condition{{Type: kueuev1beta2.WorkloadAdmitted, Status: metav1.ConditionTrue, LastTransitionTime: now}},
+			},
+		}
+	}
+	transform := trimWorkload("abc")
+
+	obj, err = transform(workload(workloadName("abc", 0)))
+	if err != nil {
+		t.Fatalf("trimWorkload() error = %v", err)
+	}
+	ours := obj.(*kueuev1beta2.Workload)
+	if c := ours.Spec.PodSets[0].Template.Spec.Containers[0]; c.Image != "" || c.Name != "" || len(ours.Status.Admission.PodSetAssignments) != 0 {
+		t.Errorf("trimWorkload() kept more than the pod set requests: %+v", ours)
+	}
+	if ours.Spec.QueueName != "lq" || ours.Status.Admission.ClusterQueue != "cq" {
+		t.Errorf("trimWorkload() dropped the queues: %+v", ours)
+	}
+	if cpu := workloadRequests(ours.Spec.PodSets)[corev1.ResourceCPU]; cpu.Cmp(resource.MustParse("1500m")) != 0 {
+		t.Errorf("workload CPU requests = %s, want 1500m", cpu.String())
+	}
+	if len(ours.OwnerReferences) != 1 || conditionTime(ours.Status.Conditions, kueuev1beta2.WorkloadAdmitted) == nil {
+		t.Errorf("trimWorkload() dropped timeline fields: %+v", ours)
+	}
+
+	obj, err = transform(workload(workloadName("other", 0)))
+	if err != nil {
+		t.Fatalf("trimWorkload() error = %v", err)
+	}
+	other := obj.(*kueuev1beta2.Workload)
+	if other.Name != "wl" || len(other.OwnerReferences) != 0 || len(other.Status.Conditions) != 0 {
+		t.Errorf("trimWorkload() of another run's Workload = %+v, want a stub", other)
+	}
+}
+
+// TestObserveChecks verifies AdmissionCheck states accumulate into transitions across
+// Workload updates, and that Kueue's retryCount covers retries the watch missed.
+func TestWorkloadRequests(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	requests := func(cpu, memory string) corev1.ResourceRequirements {
+		list := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}
+		if memory != "" {
+			list[corev1.ResourceMemory] = resource.MustParse(memory)
+		}
+		return corev1.ResourceRequirements{Requests: list}
+	}
+	spec := corev1.PodSpec{
+		InitContainers: []corev1.Container{
+			{Name: "init", Resources: requests("1", "")},
+			{Name: "sidecar", Resources: requests("500m", "128Mi"), RestartPolicy: &always},
+		},
+		Containers: []corev1.Container{{Name: "main", Resources: requests("1", "1Gi")}},
+		Overhead:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+	}
+
+	// Per pod: main and the sidecar, which keeps running, outweigh the init container,
+	// plus the overhead: 1 + 500m + 250m CPU and 1Gi + 128Mi memory
+	want := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("7"),
+		corev1.ResourceMemory: resource.MustParse("4608Mi"),
+	}
+	// The requests survive the informer's trimming
+	got := workloadRequests([]kueuev1beta2.PodSet{{Name: "main", Count: 4, Template: corev1.PodTemplateSpec{Spec: trimPodSpec(spec)}}})
+	if len(got) != len(want) {
+		t.Fatalf("workloadRequests() = %v, want %v", got, want)
+	}
+	for name, q := range want {
+		if g := got[name]; g.Cmp(q) != 0 {
+			t.Errorf("workloadRequests()[%s] = %s, want %s", name, g.String(), q.String())
+		}
+	}
+}
+
+func TestObserveChecks(t *testing.T) {
+	base := time.Date(2026, 3, 28, 12, 0, 0, 0, time.UTC)
+	state := func(s kueuev1beta2.CheckState, d time.Duration, retryCount *int32) []kueuev1beta2.AdmissionCheckState {
+		return []kueuev1beta2.AdmissionCheckState{{
+			Name:               "multikueue",
+			State:              s,
+			LastTransitionTime: metav1.NewTime(base.Add(d)),
+			RetryCount:         retryCount,
+		}}
+	}
+	three := int32(3)
+
+	var checks []run.AdmissionCheckTimeline
+	checks = observeChecks(checks, state(kueuev1beta2.CheckStatePending, 0, nil), base)
+	// Unchanged state from a resync is not a new transition
+	checks = observeChecks(checks, state(kueuev1beta2.CheckStatePending, 0, nil), base)
+	checks = observeChecks(checks, state(kueuev1beta2.CheckStateRetry, 1*time.Second, nil), base)
+	checks = observeChecks(checks, state(kueuev1beta2.CheckStateReady, 4*time.Second, &three), base)
+
+	if len(checks) != 1 {
+		t.Fatalf("observeChecks() returned %d checks, want 1", len(checks))
+	}
+	var got []string
+	for _, tr := range checks[0].Transitions {
+		got = append(got, tr.State)
+	}
+	if want := []string{run.CheckStatePending, run.CheckStateRetry, run.CheckStateReady}; !slices.Equal(got, want) {
+		t.Errorf("transitions = %v, want %v", got, want)
+	}
+	if checks[0].Retries != 3 {
+		t.Errorf("Retries = %d, want 3", checks[0].Retries)
+	}
+}
@@ -0,0 +1,86 @@
+//go:build e2e
+
+// Package e2e holds end-to-end tests that create real kind clusters. They need Docker and
+// run only with the e2e build tag:
+//
+//	make e2e-test
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jhwagner/kueue-bench/pkg/bench"
+	"github.com/jhwagner/kueue-bench/pkg/config"
+)
+
+const (
+	// smokeTimeout bounds the whole smoke test: topology create, the run and cleanup
+	smokeTimeout = 20 * time.Minute
+
+	// minSmokeWorkloads is the fewest jobs the profile's 50 arrivals may yield; the last
+	// arrival can race the end of the profile duration
+	minSmokeWorkloads = 49
+)
+
+// TestSmoke creates a one-cluster topology, runs a 50-job scenario against it and checks
+// every submitted job was admitted and its pods ran on the simulated nodes.
+func TestSmoke(t *testing.T) {
+	// Keep topologies, runs and caches out of the developer's state directory
+	h, err := bench.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to open harness: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), smokeTimeout)
+	defer cancel()
+
+	topologyCfg, err := config.LoadTopology("testdata/topology.yaml")
+	if err != nil {
+		t.Fatalf("failed to load topology: %v", err)
+	}
+	profile, err := config.LoadWorkloadProfile("testdata/profile.yaml")
+	if err != nil {
+		t.Fatalf("failed to load workload profile: %v", err)
+	}
+
+	if _, err := h.CreateTopology(ctx, topologyCfg); err != nil {
+		t.Fatalf("CreateTopology() error = %v", err)
+	}
+	t.Cleanup(func() {
+		if err := h.DeleteTopology(context.Background(), topologyCfg.Metadata.Name); err != nil {
+			t.Errorf("DeleteTopology() error = %v", err)
+		}
+	})
+
+	meta, err := h.RunScenario(ctx, bench.ScenarioOptions{
+		Profile:      profile,
+		ProfilePath:  "testdata/profile.yaml",
+		Topology:     topologyCfg.Metadata.Name,
+		TimelineWait: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("RunScenario() error = %v", err)
+	}
+	if meta.WorkloadCount < minSmokeWorkloads {
+		t.Fatalf("submitted %d workloads, want at least %d", meta.WorkloadCount, minSmokeWorkloads)
+	}
+
+	results, err := h.CollectResults(meta.RunID)
+	if err != nil {
+		t.Fatalf("CollectResults() error = %v", err)
+	}
+	if len(results.Timelines) != meta.WorkloadCount {
+		t.Fatalf("recorded %d timelines, want one per submitted workload (%d)", len(results.Timelines), meta.WorkloadCount)
+	}
+	for _, timeline := range results.Timelines {
+		if timeline.AdmittedAt == nil {
+			t.Errorf("workload %s was not admitted", timeline.Name)
+			continue
+		}
+		if timeline.PodsRunningAt == nil {
+			t.Errorf("workload %s was admitted but its pods never ran", timeline.Name)
+		}
+	}
+}